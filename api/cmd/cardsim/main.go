@@ -0,0 +1,93 @@
+// Command cardsim plays the card network against a running wallet: it sends
+// a signed authorization for a virtual card and, when asked, clears or
+// reverses it:
+//
+//	cardsim -pan 4895370000000015 -expiry 1029 -amount 125000 -clear 120000
+//
+// It reads CARD_WEBHOOK_SECRET and SERVER_PORT_HTTP from the .env file of the
+// working directory. It exits with 1 when the issuer declined a message and
+// with 2 when a message could not be sent.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"my_wallet/api/entities"
+	"my_wallet/api/utils/cardnet"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func main() {
+	os.Exit(simulate())
+}
+
+// simulate sends the messages and returns the exit code.
+func simulate() int {
+	ctx := context.Background()
+	logger := logrus.StandardLogger()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	dir, err := os.Getwd()
+	if err != nil {
+		logger.Errorln("Layer: cardsim ", "Error: finding the work directory:", err)
+		return 2
+	}
+	viper.SetConfigFile(filepath.Join(dir, ".env"))
+	if err := viper.ReadInConfig(); err != nil {
+		logger.Errorln("Layer: cardsim ", "Error: reading the configuration:", err)
+		return 2
+	}
+
+	url := flag.String("url", "http://localhost"+viper.GetString("SERVER_PORT_HTTP")+"/cards/network/messages", "webhook of the issuer")
+	pan := flag.String("pan", "", "card number")
+	expiry := flag.String("expiry", "", "expiry of the card, MMYY")
+	amount := flag.Int64("amount", 0, "amount of the purchase, in cents")
+	currency := flag.String("currency", entities.DefaultCurrency, "currency of the purchase")
+	merchant := flag.String("merchant", "Simulated Store", "name of the merchant")
+	mcc := flag.String("mcc", "5999", "category code of the merchant")
+	country := flag.String("country", "CO", "country of the merchant")
+	clear := flag.Int64("clear", 0, "amount to clear after the authorization, in cents")
+	reverse := flag.Bool("reverse", false, "reverse the authorization instead of clearing it")
+	flag.Parse()
+	if *pan == "" || *expiry == "" || *amount <= 0 || (*clear > 0 && *reverse) {
+		flag.Usage()
+		return 2
+	}
+
+	simulator := cardnet.NewSimulator(*url, []byte(viper.GetString("CARD_WEBHOOK_SECRET")), nil)
+	authorization, response, err := simulator.Authorize(ctx, *pan, *expiry, *amount, *currency, cardnet.Merchant{Name: *merchant, CategoryCode: *mcc, Country: *country})
+	if err != nil {
+		logger.Errorln("Layer: cardsim ", "Error: sending the authorization:", err)
+		return 2
+	}
+	fmt.Printf("authorization %s: %s\n", authorization.ID, response.Code)
+	if !response.Approved() {
+		return 1
+	}
+
+	var kind string
+	switch {
+	case *clear > 0:
+		kind = "clearing"
+		response, err = simulator.Clear(ctx, authorization, *clear)
+	case *reverse:
+		kind = "reversal"
+		response, err = simulator.Reverse(ctx, authorization)
+	default:
+		return 0
+	}
+	if err != nil {
+		logger.Errorln("Layer: cardsim ", "Error: sending the message:", err)
+		return 2
+	}
+	fmt.Printf("%s %s: %s\n", kind, authorization.ID, response.Code)
+	if !response.Approved() {
+		return 1
+	}
+	return 0
+}
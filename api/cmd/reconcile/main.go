@@ -0,0 +1,109 @@
+// Command reconcile runs the end-of-day reconciliation of the settlement file
+// of a provider against the ledger and prints its summary:
+//
+//	reconcile -provider bank -file settlement-2026-10-18.csv -day 2026-10-18
+//
+// It reads DB_URL and the tolerances from the .env file of the working
+// directory. It exits with 1 when the run found discrepancies, which are
+// listed by GET /admin/reconciliations/{id}, and with 2 when it failed.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"my_wallet/api/entities"
+	repository_reconciliation "my_wallet/api/respository/reconciliation"
+	repository_user "my_wallet/api/respository/user"
+	"my_wallet/api/server"
+	"my_wallet/api/services"
+	"my_wallet/api/utils/settlement"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func main() {
+	os.Exit(reconcile())
+}
+
+// reconcile runs the reconciliation and returns the exit code.
+func reconcile() int {
+	ctx := context.Background()
+	logger := logrus.StandardLogger()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	dir, err := os.Getwd()
+	if err != nil {
+		logger.Errorln("Layer: reconcile ", "Error: finding the work directory:", err)
+		return 2
+	}
+	viper.SetConfigFile(filepath.Join(dir, ".env"))
+	if err := viper.ReadInConfig(); err != nil {
+		logger.Errorln("Layer: reconcile ", "Error: reading the configuration:", err)
+		return 2
+	}
+	tolerance := server.ReconciliationTolerance()
+
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+	file := flag.String("file", "", "settlement file of the provider, CSV")
+	provider := flag.String("provider", "", "name of the provider that sent the file")
+	day := flag.String("day", yesterday, "day to reconcile, YYYY-MM-DD in UTC")
+	amountTolerance := flag.Int64("amount-tolerance", tolerance.Amount, "difference in cents still taken as a match")
+	dateTolerance := flag.Int("date-tolerance", tolerance.Days, "days a movement may settle before or after its transaction")
+	runBy := flag.String("run-by", os.Getenv("USER"), "who runs the reconciliation")
+	flag.Parse()
+	if *file == "" || *provider == "" {
+		flag.Usage()
+		return 2
+	}
+	date, err := time.Parse(time.DateOnly, *day)
+	if err != nil {
+		logger.Errorln("Layer: reconcile ", "Error: invalid day:", err)
+		return 2
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		logger.Errorln("Layer: reconcile ", "Error: opening the settlement file:", err)
+		return 2
+	}
+	records, err := settlement.Parse(f)
+	f.Close()
+	if err != nil {
+		logger.Errorln("Layer: reconcile ", "Error: reading the settlement file:", err)
+		return 2
+	}
+
+	db := server.GetMongoDB(ctx, viper.GetString("DB_URL"))
+	defer db.Disconnect(ctx)
+	reconciliationRepository := repository_reconciliation.NewMongoReconciliationRepository(db, logger)
+	if err := reconciliationRepository.CreateIndexes(ctx); err != nil {
+		return 2
+	}
+	service := services.NewReconciliationService(repository_user.NewMongoUserREpository(db, logger), reconciliationRepository, logger, ctx)
+	run, err := service.Reconcile(ctx, entities.ReconciliationRun{
+		Provider:  *provider,
+		FileName:  filepath.Base(*file),
+		Day:       date,
+		Tolerance: entities.ReconciliationTolerance{Amount: *amountTolerance, Days: *dateTolerance},
+		RunBy:     *runBy,
+	}, records)
+	if err != nil {
+		return 2
+	}
+
+	fmt.Printf("run %s: %s %s\n", run.ID, run.Provider, run.Day.Format(time.DateOnly))
+	fmt.Printf("records            %d\n", run.Summary.Records)
+	fmt.Printf("matched            %d\n", run.Summary.Matched)
+	fmt.Printf("amount mismatch    %d\n", run.Summary.AmountMismatch)
+	fmt.Printf("unmatched internal %d\n", run.Summary.UnmatchedInternal)
+	fmt.Printf("unmatched external %d\n", run.Summary.UnmatchedExternal)
+	if run.Summary.Discrepancies() > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,157 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateBeneficiaryRequest represents the request to save a beneficiary
+// @Description Wallet beneficiaries are found by exactly one of recipient email, phone or DNI; bank beneficiaries need the bank account
+type CreateBeneficiaryRequest struct {
+	Email       string                  `json:"-"` // Email of the authenticated user
+	Beneficiary entities.NewBeneficiary `json:"beneficiary"`
+}
+
+// UpdateBeneficiaryRequest represents the request to rename a beneficiary or mark it as a favorite
+type UpdateBeneficiaryRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Beneficiary ID
+	// @example "Mom"
+	Nickname string `json:"nickname"` // Nickname
+	// @example true
+	Favorite bool `json:"favorite"` // Whether the beneficiary is a favorite
+}
+
+// BeneficiaryRequest represents the request to act on a beneficiary
+type BeneficiaryRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Beneficiary ID
+}
+
+// BeneficiaryResponse represents a beneficiary
+type BeneficiaryResponse struct {
+	Beneficiary entities.Beneficiary `json:"beneficiary"`     // Beneficiary
+	Err         string               `json:"error,omitempty"` // Error message, if any
+}
+
+// ListBeneficiariesRequest represents the request for the beneficiaries of the user
+type ListBeneficiariesRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListBeneficiariesResponse represents the beneficiaries of the user
+type ListBeneficiariesResponse struct {
+	Beneficiaries []entities.Beneficiary `json:"beneficiaries"`   // Beneficiaries, favorites first
+	Err           string                 `json:"error,omitempty"` // Error message, if any
+}
+
+// DeleteBeneficiaryResponse represents the response when a beneficiary is deleted
+type DeleteBeneficiaryResponse struct {
+	Err string `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Create Beneficiary
+// @Description Saves a wallet user or a bank account to send money to again
+// @Accept json
+// @Produce json
+// @Param beneficiary body entities.NewBeneficiary true "Beneficiary"
+// @Success 201 {object} BeneficiaryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /beneficiaries [post]
+func MakeCreateBeneficiaryEndpoint(s services.BeneficiaryService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateBeneficiaryRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateBeneficiaryRequest); !ok {
+			logger.Errorln("Layer:beneficiary_endpoint", "Method:MakeCreateBeneficiaryEndpoint", ErrInterfaceWrong)
+			return BeneficiaryResponse{}, ErrInterfaceWrong
+		}
+		beneficiary, err := s.CreateBeneficiary(ctx, req.Email, req.Beneficiary)
+		if err != nil {
+			logger.Errorln("Layer:beneficiary_endpoint", "Method:MakeCreateBeneficiaryEndpoint", err)
+			return BeneficiaryResponse{}, err
+		}
+		return BeneficiaryResponse{Beneficiary: beneficiary}, nil
+	}
+}
+
+// @Summary List Beneficiaries
+// @Description Returns the beneficiaries of the user, favorites first and then the most recently used
+// @Produce json
+// @Success 200 {object} ListBeneficiariesResponse
+// @Router /beneficiaries [get]
+func MakeListBeneficiariesEndpoint(s services.BeneficiaryService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListBeneficiariesRequest
+		var ok bool = false
+
+		if req, ok = request.(ListBeneficiariesRequest); !ok {
+			logger.Errorln("Layer:beneficiary_endpoint", "Method:MakeListBeneficiariesEndpoint", ErrInterfaceWrong)
+			return ListBeneficiariesResponse{}, ErrInterfaceWrong
+		}
+		beneficiaries, err := s.ListBeneficiaries(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:beneficiary_endpoint", "Method:MakeListBeneficiariesEndpoint", err)
+			return ListBeneficiariesResponse{}, err
+		}
+		return ListBeneficiariesResponse{Beneficiaries: beneficiaries}, nil
+	}
+}
+
+// @Summary Update Beneficiary
+// @Description Renames a beneficiary or marks it as a favorite
+// @Accept json
+// @Produce json
+// @Param id path string true "Beneficiary ID"
+// @Param beneficiary body UpdateBeneficiaryRequest true "Beneficiary"
+// @Success 200 {object} BeneficiaryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /beneficiaries/{id} [put]
+func MakeUpdateBeneficiaryEndpoint(s services.BeneficiaryService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req UpdateBeneficiaryRequest
+		var ok bool = false
+
+		if req, ok = request.(UpdateBeneficiaryRequest); !ok {
+			logger.Errorln("Layer:beneficiary_endpoint", "Method:MakeUpdateBeneficiaryEndpoint", ErrInterfaceWrong)
+			return BeneficiaryResponse{}, ErrInterfaceWrong
+		}
+		beneficiary, err := s.UpdateBeneficiary(ctx, req.Email, req.ID, req.Nickname, req.Favorite)
+		if err != nil {
+			logger.Errorln("Layer:beneficiary_endpoint", "Method:MakeUpdateBeneficiaryEndpoint", err)
+			return BeneficiaryResponse{}, err
+		}
+		return BeneficiaryResponse{Beneficiary: beneficiary}, nil
+	}
+}
+
+// @Summary Delete Beneficiary
+// @Description Deletes a beneficiary
+// @Param id path string true "Beneficiary ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /beneficiaries/{id} [delete]
+func MakeDeleteBeneficiaryEndpoint(s services.BeneficiaryService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req BeneficiaryRequest
+		var ok bool = false
+
+		if req, ok = request.(BeneficiaryRequest); !ok {
+			logger.Errorln("Layer:beneficiary_endpoint", "Method:MakeDeleteBeneficiaryEndpoint", ErrInterfaceWrong)
+			return DeleteBeneficiaryResponse{}, ErrInterfaceWrong
+		}
+		if err := s.DeleteBeneficiary(ctx, req.Email, req.ID); err != nil {
+			logger.Errorln("Layer:beneficiary_endpoint", "Method:MakeDeleteBeneficiaryEndpoint", err)
+			return DeleteBeneficiaryResponse{}, err
+		}
+		return DeleteBeneficiaryResponse{}, nil
+	}
+}
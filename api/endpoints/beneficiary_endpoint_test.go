@@ -0,0 +1,134 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreateBeneficiaryEndpoint(t *testing.T) {
+
+	input := entities.NewBeneficiary{Type: entities.BeneficiaryWallet, RecipientEmail: "jane@gmail.com"}
+
+	testScenarios := []struct {
+		testName        string
+		mock            *beneficiaryServiceMock
+		mockResponse    entities.Beneficiary
+		mockError       error
+		configureMock   func(*beneficiaryServiceMock, entities.Beneficiary, error)
+		endpointRequest interface{}
+		expectedOutput  BeneficiaryResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreateBeneficiaryEndpoint",
+			mock:         &beneficiaryServiceMock{},
+			mockResponse: entities.Beneficiary{ID: "b1", Type: entities.BeneficiaryWallet, Nickname: "Jane", RecipientUserID: "u2"},
+			configureMock: func(m *beneficiaryServiceMock, mockResponse entities.Beneficiary, mockError error) {
+				m.On("CreateBeneficiary", mock.Anything, "alexer@gmail.com", input).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateBeneficiaryRequest{Email: "alexer@gmail.com", Beneficiary: input},
+			expectedOutput:  BeneficiaryResponse{Beneficiary: entities.Beneficiary{ID: "b1", Type: entities.BeneficiaryWallet, Nickname: "Jane", RecipientUserID: "u2"}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreateBeneficiaryEndpoint with error Interface type wrong",
+			mock:            &beneficiaryServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  BeneficiaryResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreateBeneficiaryEndpoint with error in the service",
+			mock:      &beneficiaryServiceMock{},
+			mockError: services.ErrBeneficiaryExists,
+			configureMock: func(m *beneficiaryServiceMock, mockResponse entities.Beneficiary, mockError error) {
+				m.On("CreateBeneficiary", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateBeneficiaryRequest{Email: "alexer@gmail.com", Beneficiary: input},
+			expectedOutput:  BeneficiaryResponse{},
+			expectedError:   services.ErrBeneficiaryExists,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreateBeneficiaryEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeDeleteBeneficiaryEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *beneficiaryServiceMock
+		mockError       error
+		configureMock   func(*beneficiaryServiceMock, error)
+		endpointRequest interface{}
+		expectedOutput  DeleteBeneficiaryResponse
+		expectedError   error
+	}{
+		{
+			testName: "test MakeDeleteBeneficiaryEndpoint",
+			mock:     &beneficiaryServiceMock{},
+			configureMock: func(m *beneficiaryServiceMock, mockError error) {
+				m.On("DeleteBeneficiary", mock.Anything, "alexer@gmail.com", "b1").Return(mockError)
+			},
+			endpointRequest: BeneficiaryRequest{Email: "alexer@gmail.com", ID: "b1"},
+			expectedOutput:  DeleteBeneficiaryResponse{},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeDeleteBeneficiaryEndpoint with error Interface type wrong",
+			mock:            &beneficiaryServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  DeleteBeneficiaryResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeDeleteBeneficiaryEndpoint with error in the service",
+			mock:      &beneficiaryServiceMock{},
+			mockError: services.ErrBeneficiaryNotFound,
+			configureMock: func(m *beneficiaryServiceMock, mockError error) {
+				m.On("DeleteBeneficiary", mock.Anything, mock.Anything, mock.Anything).Return(mockError)
+			},
+			endpointRequest: BeneficiaryRequest{Email: "alexer@gmail.com", ID: "b1"},
+			expectedOutput:  DeleteBeneficiaryResponse{},
+			expectedError:   services.ErrBeneficiaryNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeDeleteBeneficiaryEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type beneficiaryServiceMock struct {
+	mock.Mock
+}
+
+func (s *beneficiaryServiceMock) CreateBeneficiary(ctx context.Context, email string, beneficiary entities.NewBeneficiary) (entities.Beneficiary, error) {
+	r := s.Called(ctx, email, beneficiary)
+	return r.Get(0).(entities.Beneficiary), r.Error(1)
+}
+
+func (s *beneficiaryServiceMock) ListBeneficiaries(ctx context.Context, email string) ([]entities.Beneficiary, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.Beneficiary), r.Error(1)
+}
+
+func (s *beneficiaryServiceMock) UpdateBeneficiary(ctx context.Context, email string, id string, nickname string, favorite bool) (entities.Beneficiary, error) {
+	r := s.Called(ctx, email, id, nickname, favorite)
+	return r.Get(0).(entities.Beneficiary), r.Error(1)
+}
+
+func (s *beneficiaryServiceMock) DeleteBeneficiary(ctx context.Context, email string, id string) error {
+	r := s.Called(ctx, email, id)
+	return r.Error(0)
+}
+
+func (s *beneficiaryServiceMock) Beneficiary(ctx context.Context, user entities.User, id string, beneficiaryType string) (entities.Beneficiary, error) {
+	r := s.Called(ctx, user, id, beneficiaryType)
+	return r.Get(0).(entities.Beneficiary), r.Error(1)
+}
+
+func (s *beneficiaryServiceMock) CheckCoolingOff(beneficiary entities.Beneficiary, currency string, amount int64) error {
+	r := s.Called(beneficiary, currency, amount)
+	return r.Error(0)
+}
+
+func (s *beneficiaryServiceMock) Used(ctx context.Context, beneficiary entities.Beneficiary) {
+	s.Called(ctx, beneficiary)
+}
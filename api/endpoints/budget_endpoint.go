@@ -0,0 +1,185 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// BudgetRequest represents the request to create a budget or change its limit
+// @Description Wallet and category are ignored when updating
+type BudgetRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Budget to update
+	// @example "64b7f0c2e1a4b5c6d7e8f901"
+	WalletID string `json:"wallet_id,omitempty"` // Wallet whose spending is tracked
+	// @example "groceries"
+	Category string `json:"category,omitempty"` // Category slug
+	// @example 80000000
+	Limit int64 `json:"limit"` // Monthly limit in cents, in the wallet currency
+}
+
+// BudgetResponse represents a budget
+type BudgetResponse struct {
+	Budget entities.Budget `json:"budget"`          // Budget
+	Err    string          `json:"error,omitempty"` // Error message, if any
+}
+
+// ListBudgetsRequest represents the request to list the budgets of the user
+type ListBudgetsRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListBudgetsResponse represents the budgets of the user
+type ListBudgetsResponse struct {
+	Budgets []entities.Budget `json:"budgets"`         // Budgets
+	Err     string            `json:"error,omitempty"` // Error message, if any
+}
+
+// GetBudgetRequest represents the request to get the status of or delete a budget
+type GetBudgetRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Budget ID
+}
+
+// BudgetStatusResponse represents the status of a budget this month
+// @Description Remaining amount and projected spend at the end of the month
+type BudgetStatusResponse struct {
+	Status entities.BudgetStatus `json:"status"`          // Status
+	Err    string                `json:"error,omitempty"` // Error message, if any
+}
+
+// DeleteBudgetResponse represents the response when a budget is deleted
+type DeleteBudgetResponse struct {
+	Err string `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Create Budget
+// @Description Sets a monthly spending limit for a category of a wallet
+// @Accept json
+// @Produce json
+// @Param budget body BudgetRequest true "Budget"
+// @Success 201 {object} BudgetResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /budgets [post]
+func MakeCreateBudgetEndpoint(s services.BudgetService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req BudgetRequest
+		var ok bool = false
+
+		if req, ok = request.(BudgetRequest); !ok {
+			logger.Errorln("Layer:budget_endpoint", "Method:MakeCreateBudgetEndpoint", ErrInterfaceWrong)
+			return BudgetResponse{}, ErrInterfaceWrong
+		}
+		budget, err := s.CreateBudget(ctx, req.Email, entities.Budget{WalletID: req.WalletID, Category: req.Category, Limit: req.Limit})
+		if err != nil {
+			logger.Errorln("Layer:budget_endpoint", "Method:MakeCreateBudgetEndpoint", err)
+			return BudgetResponse{}, err
+		}
+		return BudgetResponse{Budget: budget}, nil
+	}
+}
+
+// @Summary List Budgets
+// @Description Lists the budgets of the user
+// @Produce json
+// @Success 200 {object} ListBudgetsResponse
+// @Router /budgets [get]
+func MakeListBudgetsEndpoint(s services.BudgetService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListBudgetsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListBudgetsRequest); !ok {
+			logger.Errorln("Layer:budget_endpoint", "Method:MakeListBudgetsEndpoint", ErrInterfaceWrong)
+			return ListBudgetsResponse{}, ErrInterfaceWrong
+		}
+		budgets, err := s.ListBudgets(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:budget_endpoint", "Method:MakeListBudgetsEndpoint", err)
+			return ListBudgetsResponse{}, err
+		}
+		return ListBudgetsResponse{Budgets: budgets}, nil
+	}
+}
+
+// @Summary Update Budget
+// @Description Changes the monthly limit of a budget
+// @Accept json
+// @Produce json
+// @Param id path string true "Budget ID"
+// @Param budget body BudgetRequest true "Budget"
+// @Success 200 {object} BudgetResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /budgets/{id} [put]
+func MakeUpdateBudgetEndpoint(s services.BudgetService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req BudgetRequest
+		var ok bool = false
+
+		if req, ok = request.(BudgetRequest); !ok {
+			logger.Errorln("Layer:budget_endpoint", "Method:MakeUpdateBudgetEndpoint", ErrInterfaceWrong)
+			return BudgetResponse{}, ErrInterfaceWrong
+		}
+		budget, err := s.UpdateBudget(ctx, req.Email, req.ID, req.Limit)
+		if err != nil {
+			logger.Errorln("Layer:budget_endpoint", "Method:MakeUpdateBudgetEndpoint", err)
+			return BudgetResponse{}, err
+		}
+		return BudgetResponse{Budget: budget}, nil
+	}
+}
+
+// @Summary Delete Budget
+// @Description Deletes a budget
+// @Param id path string true "Budget ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /budgets/{id} [delete]
+func MakeDeleteBudgetEndpoint(s services.BudgetService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetBudgetRequest
+		var ok bool = false
+
+		if req, ok = request.(GetBudgetRequest); !ok {
+			logger.Errorln("Layer:budget_endpoint", "Method:MakeDeleteBudgetEndpoint", ErrInterfaceWrong)
+			return DeleteBudgetResponse{}, ErrInterfaceWrong
+		}
+		if err := s.DeleteBudget(ctx, req.Email, req.ID); err != nil {
+			logger.Errorln("Layer:budget_endpoint", "Method:MakeDeleteBudgetEndpoint", err)
+			return DeleteBudgetResponse{}, err
+		}
+		return DeleteBudgetResponse{}, nil
+	}
+}
+
+// @Summary Get Budget Status
+// @Description Returns the spend of a budget this month, what is left and the projection to month end
+// @Produce json
+// @Param id path string true "Budget ID"
+// @Success 200 {object} BudgetStatusResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /budgets/{id}/status [get]
+func MakeGetBudgetStatusEndpoint(s services.BudgetService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetBudgetRequest
+		var ok bool = false
+
+		if req, ok = request.(GetBudgetRequest); !ok {
+			logger.Errorln("Layer:budget_endpoint", "Method:MakeGetBudgetStatusEndpoint", ErrInterfaceWrong)
+			return BudgetStatusResponse{}, ErrInterfaceWrong
+		}
+		status, err := s.GetBudgetStatus(ctx, req.Email, req.ID)
+		if err != nil {
+			logger.Errorln("Layer:budget_endpoint", "Method:MakeGetBudgetStatusEndpoint", err)
+			return BudgetStatusResponse{}, err
+		}
+		return BudgetStatusResponse{Status: status}, nil
+	}
+}
@@ -0,0 +1,73 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeGetBudgetStatusEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *budgetServiceMock
+		mockResponse    entities.BudgetStatus
+		mockError       error
+		configureMock   func(*budgetServiceMock, entities.BudgetStatus, error)
+		endpointRequest interface{}
+		expectedOutput  BudgetStatusResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeGetBudgetStatusEndpoint",
+			mock:         &budgetServiceMock{},
+			mockResponse: entities.BudgetStatus{BudgetID: "b1", Limit: 300000, Spent: 60000, Remaining: 240000},
+			configureMock: func(m *budgetServiceMock, mockResponse entities.BudgetStatus, mockError error) {
+				m.On("GetBudgetStatus", mock.Anything, "alexer@gmail.com", "b1").Return(mockResponse, mockError)
+			},
+			endpointRequest: GetBudgetRequest{Email: "alexer@gmail.com", ID: "b1"},
+			expectedOutput:  BudgetStatusResponse{Status: entities.BudgetStatus{BudgetID: "b1", Limit: 300000, Spent: 60000, Remaining: 240000}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeGetBudgetStatusEndpoint with error Interface type wrong",
+			mock:            &budgetServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  BudgetStatusResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeGetBudgetStatusEndpoint with error in the service",
+			mock:      &budgetServiceMock{},
+			mockError: services.ErrBudgetNotFound,
+			configureMock: func(m *budgetServiceMock, mockResponse entities.BudgetStatus, mockError error) {
+				m.On("GetBudgetStatus", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: GetBudgetRequest{Email: "alexer@gmail.com", ID: "b9"},
+			expectedOutput:  BudgetStatusResponse{},
+			expectedError:   services.ErrBudgetNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeGetBudgetStatusEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
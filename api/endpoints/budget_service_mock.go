@@ -0,0 +1,41 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type budgetServiceMock struct {
+	mock.Mock
+}
+
+func (s *budgetServiceMock) Posted(ctx context.Context, transactions []entities.Transaction) {
+	s.Called(ctx, transactions)
+}
+
+func (s *budgetServiceMock) CreateBudget(ctx context.Context, email string, budget entities.Budget) (entities.Budget, error) {
+	r := s.Called(ctx, email, budget)
+	return r.Get(0).(entities.Budget), r.Error(1)
+}
+
+func (s *budgetServiceMock) ListBudgets(ctx context.Context, email string) ([]entities.Budget, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.Budget), r.Error(1)
+}
+
+func (s *budgetServiceMock) UpdateBudget(ctx context.Context, email string, id string, limit int64) (entities.Budget, error) {
+	r := s.Called(ctx, email, id, limit)
+	return r.Get(0).(entities.Budget), r.Error(1)
+}
+
+func (s *budgetServiceMock) DeleteBudget(ctx context.Context, email string, id string) error {
+	r := s.Called(ctx, email, id)
+	return r.Error(0)
+}
+
+func (s *budgetServiceMock) GetBudgetStatus(ctx context.Context, email string, id string) (entities.BudgetStatus, error) {
+	r := s.Called(ctx, email, id)
+	return r.Get(0).(entities.BudgetStatus), r.Error(1)
+}
@@ -0,0 +1,239 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"my_wallet/api/utils/cardnet"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// IssueCardRequest represents the request to issue a virtual card
+type IssueCardRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example "Subscriptions"
+	Name string `json:"name,omitempty"` // Name of the card, up to 40 characters
+	// @example "COP"
+	Currency string              `json:"currency,omitempty"` // Currency of the card, the default one of the wallet if empty
+	Limits   entities.CardLimits `json:"limits"`             // Spending limits, in cents; zero means no limit
+}
+
+// IssueCardResponse represents a card just issued, with its number
+type IssueCardResponse struct {
+	Card entities.IssuedCard `json:"card"`            // Card and its number, shown only once
+	Err  string              `json:"error,omitempty"` // Error message, if any
+}
+
+// ListCardsRequest represents the request for the cards of the authenticated user
+type ListCardsRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListCardsResponse represents the cards of a user
+type ListCardsResponse struct {
+	Cards []entities.Card `json:"cards"`           // Cards
+	Err   string          `json:"error,omitempty"` // Error message, if any
+}
+
+// UpdateCardLimitsRequest represents the request to change the limits of a card
+type UpdateCardLimitsRequest struct {
+	Email  string              `json:"-"`      // Email of the authenticated user
+	CardID string              `json:"-"`      // Card ID
+	Limits entities.CardLimits `json:"limits"` // Spending limits, in cents; zero means no limit
+}
+
+// FreezeCardRequest represents the request to freeze or unfreeze a card
+type FreezeCardRequest struct {
+	Email  string `json:"-"` // Email of the authenticated user
+	CardID string `json:"-"` // Card ID
+	Frozen bool   `json:"-"` // Whether the card is frozen
+}
+
+// CardResponse represents a card
+type CardResponse struct {
+	Card entities.Card `json:"card"`            // Card
+	Err  string        `json:"error,omitempty"` // Error message, if any
+}
+
+// ListCardAuthorizationsRequest represents the request for the authorizations of a card
+type ListCardAuthorizationsRequest struct {
+	Email  string `json:"-"` // Email of the authenticated user
+	CardID string `json:"-"` // Card ID
+}
+
+// ListCardAuthorizationsResponse represents the last authorizations of a card
+type ListCardAuthorizationsResponse struct {
+	Authorizations []entities.CardAuthorization `json:"authorizations"`  // Authorizations, newest first
+	Err            string                       `json:"error,omitempty"` // Error message, if any
+}
+
+// CardNetworkMessageRequest represents a message of the card network
+type CardNetworkMessageRequest struct {
+	Body      []byte `json:"-"` // Message as sent
+	Signature string `json:"-"` // Signature of the message
+}
+
+// CardNetworkMessageResponse represents the answer to a message of the card network
+type CardNetworkMessageResponse struct {
+	Response cardnet.Response `json:"response"`        // Answer
+	Err      string           `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Issue Card
+// @Description Issues a virtual card on the wallet of the authenticated user. The card number is only returned here
+// @Accept json
+// @Produce json
+// @Param card body IssueCardRequest true "Card"
+// @Success 201 {object} IssueCardResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /cards [post]
+func MakeIssueCardEndpoint(s services.CardService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req IssueCardRequest
+		var ok bool = false
+
+		if req, ok = request.(IssueCardRequest); !ok {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeIssueCardEndpoint", ErrInterfaceWrong)
+			return IssueCardResponse{}, ErrInterfaceWrong
+		}
+		card, err := s.IssueCard(ctx, req.Email, entities.Card{Name: req.Name, Currency: req.Currency, Limits: req.Limits})
+		if err != nil {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeIssueCardEndpoint", err)
+			return IssueCardResponse{}, err
+		}
+		return IssueCardResponse{Card: card}, nil
+	}
+}
+
+// @Summary List Cards
+// @Description Returns the virtual cards of the authenticated user
+// @Produce json
+// @Success 200 {object} ListCardsResponse
+// @Router /cards [get]
+func MakeListCardsEndpoint(s services.CardService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListCardsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListCardsRequest); !ok {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeListCardsEndpoint", ErrInterfaceWrong)
+			return ListCardsResponse{}, ErrInterfaceWrong
+		}
+		cards, err := s.ListCards(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeListCardsEndpoint", err)
+			return ListCardsResponse{}, err
+		}
+		return ListCardsResponse{Cards: cards}, nil
+	}
+}
+
+// @Summary Update Card Limits
+// @Description Replaces the spending limits of a card of the authenticated user
+// @Accept json
+// @Produce json
+// @Param id path string true "Card ID"
+// @Param limits body UpdateCardLimitsRequest true "Limits"
+// @Success 200 {object} CardResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /cards/{id}/limits [put]
+func MakeUpdateCardLimitsEndpoint(s services.CardService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req UpdateCardLimitsRequest
+		var ok bool = false
+
+		if req, ok = request.(UpdateCardLimitsRequest); !ok {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeUpdateCardLimitsEndpoint", ErrInterfaceWrong)
+			return CardResponse{}, ErrInterfaceWrong
+		}
+		card, err := s.UpdateCardLimits(ctx, req.Email, req.CardID, req.Limits)
+		if err != nil {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeUpdateCardLimitsEndpoint", err)
+			return CardResponse{}, err
+		}
+		return CardResponse{Card: card}, nil
+	}
+}
+
+// @Summary Freeze or Unfreeze Card
+// @Description Freezes a card of the authenticated user so it declines new purchases, or unfreezes it
+// @Produce json
+// @Param id path string true "Card ID"
+// @Success 200 {object} CardResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /cards/{id}/freeze [post]
+// @Router /cards/{id}/unfreeze [post]
+func MakeFreezeCardEndpoint(s services.CardService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req FreezeCardRequest
+		var ok bool = false
+
+		if req, ok = request.(FreezeCardRequest); !ok {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeFreezeCardEndpoint", ErrInterfaceWrong)
+			return CardResponse{}, ErrInterfaceWrong
+		}
+		card, err := s.FreezeCard(ctx, req.Email, req.CardID, req.Frozen)
+		if err != nil {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeFreezeCardEndpoint", err)
+			return CardResponse{}, err
+		}
+		return CardResponse{Card: card}, nil
+	}
+}
+
+// @Summary List Card Authorizations
+// @Description Returns the last purchases attempted with a card of the authenticated user, declined ones included
+// @Produce json
+// @Param id path string true "Card ID"
+// @Success 200 {object} ListCardAuthorizationsResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /cards/{id}/authorizations [get]
+func MakeListCardAuthorizationsEndpoint(s services.CardService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListCardAuthorizationsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListCardAuthorizationsRequest); !ok {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeListCardAuthorizationsEndpoint", ErrInterfaceWrong)
+			return ListCardAuthorizationsResponse{}, ErrInterfaceWrong
+		}
+		authorizations, err := s.ListCardAuthorizations(ctx, req.Email, req.CardID)
+		if err != nil {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeListCardAuthorizationsEndpoint", err)
+			return ListCardAuthorizationsResponse{}, err
+		}
+		return ListCardAuthorizationsResponse{Authorizations: authorizations}, nil
+	}
+}
+
+// @Summary Card Network Webhook
+// @Description Receives the authorization, clearing and reversal messages of the card network, signed in the X-Card-Signature header, and answers them with an ISO 8583 response code
+// @Accept json
+// @Produce json
+// @Success 200 {object} cardnet.Response
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /cards/network/messages [post]
+func MakeCardNetworkMessageEndpoint(s services.CardService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CardNetworkMessageRequest
+		var ok bool = false
+
+		if req, ok = request.(CardNetworkMessageRequest); !ok {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeCardNetworkMessageEndpoint", ErrInterfaceWrong)
+			return CardNetworkMessageResponse{}, ErrInterfaceWrong
+		}
+		answer, err := s.HandleNetworkMessage(ctx, req.Body, req.Signature)
+		if err != nil {
+			logger.Errorln("Layer:card_endpoint", "Method:MakeCardNetworkMessageEndpoint", err)
+			return CardNetworkMessageResponse{}, err
+		}
+		return CardNetworkMessageResponse{Response: answer}, nil
+	}
+}
@@ -0,0 +1,137 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"my_wallet/api/utils/cardnet"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeIssueCardEndpoint(t *testing.T) {
+	issued := entities.IssuedCard{Card: entities.Card{ID: "c1", Name: "Subscriptions", Last4: "0015", Currency: "COP", Status: entities.CardActive}, PAN: "4895370000000015"}
+
+	testScenarios := []struct {
+		testName        string
+		mock            *cardServiceMock
+		mockResponse    entities.IssuedCard
+		mockError       error
+		configureMock   func(*cardServiceMock, entities.IssuedCard, error)
+		endpointRequest interface{}
+		expectedOutput  IssueCardResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeIssueCardEndpoint",
+			mock:         &cardServiceMock{},
+			mockResponse: issued,
+			configureMock: func(m *cardServiceMock, mockResponse entities.IssuedCard, mockError error) {
+				m.On("IssueCard", mock.Anything, "buyer@gmail.com", entities.Card{Name: "Subscriptions", Limits: entities.CardLimits{Daily: 100000}}).Return(mockResponse, mockError)
+			},
+			endpointRequest: IssueCardRequest{Email: "buyer@gmail.com", Name: "Subscriptions", Limits: entities.CardLimits{Daily: 100000}},
+			expectedOutput:  IssueCardResponse{Card: issued},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeIssueCardEndpoint with error Interface type wrong",
+			mock:            &cardServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  IssueCardResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeIssueCardEndpoint with error in the service",
+			mock:      &cardServiceMock{},
+			mockError: services.ErrInvalidCard,
+			configureMock: func(m *cardServiceMock, mockResponse entities.IssuedCard, mockError error) {
+				m.On("IssueCard", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: IssueCardRequest{Email: "buyer@gmail.com", Limits: entities.CardLimits{Daily: -1}},
+			expectedOutput:  IssueCardResponse{},
+			expectedError:   services.ErrInvalidCard,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeIssueCardEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeCardNetworkMessageEndpoint(t *testing.T) {
+	body := []byte(`{"id":"auth-1","type":"authorization"}`)
+
+	testScenarios := []struct {
+		testName        string
+		mock            *cardServiceMock
+		mockResponse    cardnet.Response
+		mockError       error
+		configureMock   func(*cardServiceMock, cardnet.Response, error)
+		endpointRequest interface{}
+		expectedOutput  CardNetworkMessageResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCardNetworkMessageEndpoint",
+			mock:         &cardServiceMock{},
+			mockResponse: cardnet.Response{MessageID: "auth-1", Code: cardnet.CodeInsufficientFunds},
+			configureMock: func(m *cardServiceMock, mockResponse cardnet.Response, mockError error) {
+				m.On("HandleNetworkMessage", mock.Anything, body, "sha256=abc").Return(mockResponse, mockError)
+			},
+			endpointRequest: CardNetworkMessageRequest{Body: body, Signature: "sha256=abc"},
+			expectedOutput:  CardNetworkMessageResponse{Response: cardnet.Response{MessageID: "auth-1", Code: cardnet.CodeInsufficientFunds}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCardNetworkMessageEndpoint with error Interface type wrong",
+			mock:            &cardServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  CardNetworkMessageResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCardNetworkMessageEndpoint with error in the service",
+			mock:      &cardServiceMock{},
+			mockError: services.ErrInvalidCardSignature,
+			configureMock: func(m *cardServiceMock, mockResponse cardnet.Response, mockError error) {
+				m.On("HandleNetworkMessage", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CardNetworkMessageRequest{Body: body},
+			expectedOutput:  CardNetworkMessageResponse{},
+			expectedError:   services.ErrInvalidCardSignature,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCardNetworkMessageEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/utils/cardnet"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type cardServiceMock struct {
+	mock.Mock
+}
+
+func (s *cardServiceMock) IssueCard(ctx context.Context, email string, card entities.Card) (entities.IssuedCard, error) {
+	r := s.Called(ctx, email, card)
+	return r.Get(0).(entities.IssuedCard), r.Error(1)
+}
+
+func (s *cardServiceMock) ListCards(ctx context.Context, email string) ([]entities.Card, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.Card), r.Error(1)
+}
+
+func (s *cardServiceMock) UpdateCardLimits(ctx context.Context, email string, cardID string, limits entities.CardLimits) (entities.Card, error) {
+	r := s.Called(ctx, email, cardID, limits)
+	return r.Get(0).(entities.Card), r.Error(1)
+}
+
+func (s *cardServiceMock) FreezeCard(ctx context.Context, email string, cardID string, frozen bool) (entities.Card, error) {
+	r := s.Called(ctx, email, cardID, frozen)
+	return r.Get(0).(entities.Card), r.Error(1)
+}
+
+func (s *cardServiceMock) ListCardAuthorizations(ctx context.Context, email string, cardID string) ([]entities.CardAuthorization, error) {
+	r := s.Called(ctx, email, cardID)
+	return r.Get(0).([]entities.CardAuthorization), r.Error(1)
+}
+
+func (s *cardServiceMock) HandleNetworkMessage(ctx context.Context, body []byte, signature string) (cardnet.Response, error) {
+	r := s.Called(ctx, body, signature)
+	return r.Get(0).(cardnet.Response), r.Error(1)
+}
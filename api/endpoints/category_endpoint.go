@@ -0,0 +1,260 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// ListCategoriesRequest represents the request to list the categories of the user
+type ListCategoriesRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListCategoriesResponse represents the categories available to the user
+// @Description Response with the built-in categories followed by the custom ones
+type ListCategoriesResponse struct {
+	Categories []entities.Category `json:"categories"`      // Categories
+	Err        string              `json:"error,omitempty"` // Error message, if any
+}
+
+// CreateCategoryRequest represents the request to create a custom category
+// @Description The slug used in rules and transactions is made from the name
+type CreateCategoryRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example "Pets"
+	Name string `json:"name"` // Name of the category
+}
+
+// CategoryResponse represents a category
+type CategoryResponse struct {
+	Category entities.Category `json:"category"`        // Category
+	Err      string            `json:"error,omitempty"` // Error message, if any
+}
+
+// ListCategoryRulesRequest represents the request to list the rules of the user
+type ListCategoryRulesRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListCategoryRulesResponse represents the categorization rules of the user
+// @Description Rules in evaluation order, the first rule that matches wins
+type ListCategoryRulesResponse struct {
+	Rules []entities.CategoryRule `json:"rules"`           // Rules
+	Err   string                  `json:"error,omitempty"` // Error message, if any
+}
+
+// CategoryRuleRequest represents the request to create a categorization rule
+// @Description Every condition that is set must match; keywords match the memo
+type CategoryRuleRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example "groceries"
+	Category string `json:"category"` // Category slug to assign
+	// @example "64b7f0c2e1a4b5c6d7e8f901"
+	CounterpartyUserID string `json:"counterparty_user_id,omitempty"` // Counterparty user to match
+	// @example ["market", "super"]
+	Keywords []string `json:"keywords,omitempty"` // Any of these words in the memo
+	// @example 1000000
+	MinAmount int64 `json:"min_amount,omitempty"` // Minimum amount in cents
+	// @example 50000000
+	MaxAmount int64 `json:"max_amount,omitempty"` // Maximum amount in cents
+	// @example 10
+	Priority int `json:"priority,omitempty"` // Rules with higher priority are evaluated first
+}
+
+// CategoryRuleResponse represents a categorization rule
+type CategoryRuleResponse struct {
+	Rule entities.CategoryRule `json:"rule"`            // Rule
+	Err  string                `json:"error,omitempty"` // Error message, if any
+}
+
+// DeleteCategoryRuleRequest represents the request to delete a categorization rule
+type DeleteCategoryRuleRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Rule to delete
+}
+
+// DeleteCategoryRuleResponse represents the response when a rule is deleted
+type DeleteCategoryRuleResponse struct {
+	Err string `json:"error,omitempty"` // Error message, if any
+}
+
+// RecategorizeRequest represents the request to change the category of a transaction
+// @Description With learn, future transactions like this one get the same category
+type RecategorizeRequest struct {
+	Email         string `json:"-"` // Email of the authenticated user
+	WalletID      string `json:"-"` // Wallet of the transaction
+	TransactionID string `json:"-"` // Transaction to recategorize
+	// @example "groceries"
+	Category string `json:"category"` // Category slug
+	// @example true
+	Learn bool `json:"learn,omitempty"` // Create a rule from the counterparty or memo
+}
+
+// TransactionResponse represents a transaction
+type TransactionResponse struct {
+	Transaction entities.Transaction `json:"transaction"`     // Transaction
+	Err         string               `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary List Categories
+// @Description Lists the built-in and custom categories of the user
+// @Produce json
+// @Success 200 {object} ListCategoriesResponse
+// @Router /categories [get]
+func MakeListCategoriesEndpoint(s services.CategoryService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListCategoriesRequest
+		var ok bool = false
+
+		if req, ok = request.(ListCategoriesRequest); !ok {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeListCategoriesEndpoint", ErrInterfaceWrong)
+			return ListCategoriesResponse{}, ErrInterfaceWrong
+		}
+		categories, err := s.ListCategories(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeListCategoriesEndpoint", err)
+			return ListCategoriesResponse{}, err
+		}
+		return ListCategoriesResponse{Categories: categories}, nil
+	}
+}
+
+// @Summary Create Category
+// @Description Creates a custom category for the user
+// @Accept json
+// @Produce json
+// @Param category body CreateCategoryRequest true "Category"
+// @Success 201 {object} CategoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /categories [post]
+func MakeCreateCategoryEndpoint(s services.CategoryService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateCategoryRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateCategoryRequest); !ok {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeCreateCategoryEndpoint", ErrInterfaceWrong)
+			return CategoryResponse{}, ErrInterfaceWrong
+		}
+		category, err := s.CreateCategory(ctx, req.Email, req.Name)
+		if err != nil {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeCreateCategoryEndpoint", err)
+			return CategoryResponse{}, err
+		}
+		return CategoryResponse{Category: category}, nil
+	}
+}
+
+// @Summary List Category Rules
+// @Description Lists the categorization rules of the user in evaluation order
+// @Produce json
+// @Success 200 {object} ListCategoryRulesResponse
+// @Router /categories/rules [get]
+func MakeListCategoryRulesEndpoint(s services.CategoryService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListCategoryRulesRequest
+		var ok bool = false
+
+		if req, ok = request.(ListCategoryRulesRequest); !ok {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeListCategoryRulesEndpoint", ErrInterfaceWrong)
+			return ListCategoryRulesResponse{}, ErrInterfaceWrong
+		}
+		rules, err := s.ListRules(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeListCategoryRulesEndpoint", err)
+			return ListCategoryRulesResponse{}, err
+		}
+		return ListCategoryRulesResponse{Rules: rules}, nil
+	}
+}
+
+// @Summary Create Category Rule
+// @Description Creates a rule that categorizes new transactions
+// @Accept json
+// @Produce json
+// @Param rule body CategoryRuleRequest true "Rule"
+// @Success 201 {object} CategoryRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /categories/rules [post]
+func MakeCreateCategoryRuleEndpoint(s services.CategoryService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CategoryRuleRequest
+		var ok bool = false
+
+		if req, ok = request.(CategoryRuleRequest); !ok {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeCreateCategoryRuleEndpoint", ErrInterfaceWrong)
+			return CategoryRuleResponse{}, ErrInterfaceWrong
+		}
+		rule, err := s.CreateRule(ctx, req.Email, entities.CategoryRule{
+			Category:           req.Category,
+			CounterpartyUserID: req.CounterpartyUserID,
+			Keywords:           req.Keywords,
+			MinAmount:          req.MinAmount,
+			MaxAmount:          req.MaxAmount,
+			Priority:           req.Priority,
+		})
+		if err != nil {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeCreateCategoryRuleEndpoint", err)
+			return CategoryRuleResponse{}, err
+		}
+		return CategoryRuleResponse{Rule: rule}, nil
+	}
+}
+
+// @Summary Delete Category Rule
+// @Description Deletes a categorization rule of the user
+// @Param id path string true "Rule ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/rules/{id} [delete]
+func MakeDeleteCategoryRuleEndpoint(s services.CategoryService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req DeleteCategoryRuleRequest
+		var ok bool = false
+
+		if req, ok = request.(DeleteCategoryRuleRequest); !ok {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeDeleteCategoryRuleEndpoint", ErrInterfaceWrong)
+			return DeleteCategoryRuleResponse{}, ErrInterfaceWrong
+		}
+		if err := s.DeleteRule(ctx, req.Email, req.ID); err != nil {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeDeleteCategoryRuleEndpoint", err)
+			return DeleteCategoryRuleResponse{}, err
+		}
+		return DeleteCategoryRuleResponse{}, nil
+	}
+}
+
+// @Summary Recategorize Transaction
+// @Description Changes the category of a transaction and optionally learns a rule from it
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param transactionId path string true "Transaction ID"
+// @Param category body RecategorizeRequest true "Category"
+// @Success 200 {object} TransactionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /wallets/{id}/transactions/{transactionId}/category [put]
+func MakeRecategorizeEndpoint(s services.CategoryService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req RecategorizeRequest
+		var ok bool = false
+
+		if req, ok = request.(RecategorizeRequest); !ok {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeRecategorizeEndpoint", ErrInterfaceWrong)
+			return TransactionResponse{}, ErrInterfaceWrong
+		}
+		transaction, err := s.Recategorize(ctx, req.Email, req.WalletID, req.TransactionID, req.Category, req.Learn)
+		if err != nil {
+			logger.Errorln("Layer:category_endpoint", "Method:MakeRecategorizeEndpoint", err)
+			return TransactionResponse{}, err
+		}
+		return TransactionResponse{Transaction: transaction}, nil
+	}
+}
@@ -0,0 +1,134 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreateCategoryRuleEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *categoryServiceMock
+		mockResponse    entities.CategoryRule
+		mockError       error
+		configureMock   func(*categoryServiceMock, entities.CategoryRule, error)
+		endpointRequest interface{}
+		expectedOutput  CategoryRuleResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreateCategoryRuleEndpoint",
+			mock:         &categoryServiceMock{},
+			mockResponse: entities.CategoryRule{ID: "r1", Category: "groceries", Keywords: []string{"market"}},
+			configureMock: func(m *categoryServiceMock, mockResponse entities.CategoryRule, mockError error) {
+				m.On("CreateRule", mock.Anything, "alexer@gmail.com", entities.CategoryRule{Category: "groceries", Keywords: []string{"market"}, Priority: 5}).Return(mockResponse, mockError)
+			},
+			endpointRequest: CategoryRuleRequest{Email: "alexer@gmail.com", Category: "groceries", Keywords: []string{"market"}, Priority: 5},
+			expectedOutput:  CategoryRuleResponse{Rule: entities.CategoryRule{ID: "r1", Category: "groceries", Keywords: []string{"market"}}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreateCategoryRuleEndpoint with error Interface type wrong",
+			mock:            &categoryServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  CategoryRuleResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreateCategoryRuleEndpoint with error in the service",
+			mock:      &categoryServiceMock{},
+			mockError: services.ErrInvalidCategoryRule,
+			configureMock: func(m *categoryServiceMock, mockResponse entities.CategoryRule, mockError error) {
+				m.On("CreateRule", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CategoryRuleRequest{Email: "alexer@gmail.com", Category: "groceries"},
+			expectedOutput:  CategoryRuleResponse{},
+			expectedError:   services.ErrInvalidCategoryRule,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreateCategoryRuleEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeRecategorizeEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *categoryServiceMock
+		mockResponse    entities.Transaction
+		mockError       error
+		configureMock   func(*categoryServiceMock, entities.Transaction, error)
+		endpointRequest interface{}
+		expectedOutput  TransactionResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeRecategorizeEndpoint",
+			mock:         &categoryServiceMock{},
+			mockResponse: entities.Transaction{ID: "t1", WalletID: "w1", Category: "groceries"},
+			configureMock: func(m *categoryServiceMock, mockResponse entities.Transaction, mockError error) {
+				m.On("Recategorize", mock.Anything, "alexer@gmail.com", "w1", "t1", "groceries", true).Return(mockResponse, mockError)
+			},
+			endpointRequest: RecategorizeRequest{Email: "alexer@gmail.com", WalletID: "w1", TransactionID: "t1", Category: "groceries", Learn: true},
+			expectedOutput:  TransactionResponse{Transaction: entities.Transaction{ID: "t1", WalletID: "w1", Category: "groceries"}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeRecategorizeEndpoint with error Interface type wrong",
+			mock:            &categoryServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  TransactionResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeRecategorizeEndpoint with error in the service",
+			mock:      &categoryServiceMock{},
+			mockError: services.ErrTransactionNotFound,
+			configureMock: func(m *categoryServiceMock, mockResponse entities.Transaction, mockError error) {
+				m.On("Recategorize", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: RecategorizeRequest{Email: "alexer@gmail.com", WalletID: "w1", TransactionID: "t9", Category: "groceries"},
+			expectedOutput:  TransactionResponse{},
+			expectedError:   services.ErrTransactionNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeRecategorizeEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
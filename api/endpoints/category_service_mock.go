@@ -0,0 +1,46 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type categoryServiceMock struct {
+	mock.Mock
+}
+
+func (s *categoryServiceMock) Categorize(ctx context.Context, transactions []entities.Transaction) {
+	s.Called(ctx, transactions)
+}
+
+func (s *categoryServiceMock) ListCategories(ctx context.Context, email string) ([]entities.Category, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.Category), r.Error(1)
+}
+
+func (s *categoryServiceMock) CreateCategory(ctx context.Context, email string, name string) (entities.Category, error) {
+	r := s.Called(ctx, email, name)
+	return r.Get(0).(entities.Category), r.Error(1)
+}
+
+func (s *categoryServiceMock) ListRules(ctx context.Context, email string) ([]entities.CategoryRule, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.CategoryRule), r.Error(1)
+}
+
+func (s *categoryServiceMock) CreateRule(ctx context.Context, email string, rule entities.CategoryRule) (entities.CategoryRule, error) {
+	r := s.Called(ctx, email, rule)
+	return r.Get(0).(entities.CategoryRule), r.Error(1)
+}
+
+func (s *categoryServiceMock) DeleteRule(ctx context.Context, email string, id string) error {
+	r := s.Called(ctx, email, id)
+	return r.Error(0)
+}
+
+func (s *categoryServiceMock) Recategorize(ctx context.Context, email string, walletID string, transactionID string, category string, learn bool) (entities.Transaction, error) {
+	r := s.Called(ctx, email, walletID, transactionID, category, learn)
+	return r.Get(0).(entities.Transaction), r.Error(1)
+}
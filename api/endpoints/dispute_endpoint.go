@@ -0,0 +1,305 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// OpenDisputeRequest represents the request to dispute a transaction
+type OpenDisputeRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example "65f1c0a2b3d4e5f6a7b8c9d0"
+	TransactionID string `json:"transaction_id"` // Outgoing transfer disputed
+	// @example "wrong_recipient"
+	Reason string `json:"reason"` // wrong_recipient, wrong_amount, unauthorized, not_received or other
+	// @example "I sent the money to the wrong phone number"
+	Description string `json:"description,omitempty"` // What happened
+}
+
+// DisputeRequest represents the request to act on a dispute
+type DisputeRequest struct {
+	Email     string `json:"-"` // Email of the authenticated user
+	DisputeID string `json:"-"` // Dispute ID
+}
+
+// DisputeResponse represents a dispute
+type DisputeResponse struct {
+	Dispute entities.Dispute `json:"dispute"`         // Dispute
+	Err     string           `json:"error,omitempty"` // Error message, if any
+}
+
+// ListDisputesRequest represents the request for the disputes of the user
+type ListDisputesRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListDisputeQueueRequest represents the request for the dispute queue
+type ListDisputeQueueRequest struct {
+	Email  string `json:"-"` // Email of the authenticated user
+	Status string `json:"-"` // Status of the disputes; opened by default
+}
+
+// ListDisputesResponse represents a list of disputes
+type ListDisputesResponse struct {
+	Disputes []entities.Dispute `json:"disputes"`        // Disputes
+	Err      string             `json:"error,omitempty"` // Error message, if any
+}
+
+// AddDisputeEvidenceRequest represents the upload of evidence for a dispute
+// @Description The body is the file itself, a JPEG, PNG or PDF of up to 5 MB
+type AddDisputeEvidenceRequest struct {
+	Email       string `json:"-"` // Email of the authenticated user
+	DisputeID   string `json:"-"` // Dispute ID
+	Name        string `json:"-"` // File name
+	ContentType string `json:"-"` // Content type of the file
+	Data        []byte `json:"-"` // File
+}
+
+// GetDisputeEvidenceRequest represents the request for evidence of a dispute
+type GetDisputeEvidenceRequest struct {
+	Email      string `json:"-"` // Email of the authenticated user
+	DisputeID  string `json:"-"` // Dispute ID
+	EvidenceID string `json:"-"` // Evidence ID
+}
+
+// GetDisputeEvidenceResponse represents evidence of a dispute
+type GetDisputeEvidenceResponse struct {
+	Evidence entities.DisputeEvidence // Evidence
+	Data     []byte                   // Content of the file
+}
+
+// ResolveDisputeRequest represents the decision of support staff on a dispute
+type ResolveDisputeRequest struct {
+	Email     string `json:"-"` // Email of the authenticated user
+	DisputeID string `json:"-"` // Dispute ID
+	// @example true
+	InFavor bool `json:"in_favor"` // Whether the dispute is resolved in favor of the user
+	// @example 2000
+	Amount int64 `json:"amount,omitempty"` // Amount to reverse; the whole transfer when missing
+	// @example "The recipient agreed to return the money"
+	Note string `json:"note,omitempty"` // Note to the user, required to reject
+}
+
+// Resource returns the dispute being resolved.
+func (r ResolveDisputeRequest) Resource() string {
+	return "disputes/" + r.DisputeID
+}
+
+// @Summary Open Dispute
+// @Description Disputes a completed outgoing transfer of the user
+// @Accept json
+// @Produce json
+// @Param dispute body OpenDisputeRequest true "Dispute"
+// @Success 201 {object} DisputeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /disputes [post]
+func MakeOpenDisputeEndpoint(s services.DisputeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req OpenDisputeRequest
+		var ok bool = false
+
+		if req, ok = request.(OpenDisputeRequest); !ok {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeOpenDisputeEndpoint", ErrInterfaceWrong)
+			return DisputeResponse{}, ErrInterfaceWrong
+		}
+		dispute, err := s.OpenDispute(ctx, req.Email, req.TransactionID, req.Reason, req.Description)
+		if err != nil {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeOpenDisputeEndpoint", err)
+			return DisputeResponse{}, err
+		}
+		return DisputeResponse{Dispute: dispute}, nil
+	}
+}
+
+// @Summary List Disputes
+// @Description Returns the latest disputes of the user
+// @Produce json
+// @Success 200 {object} ListDisputesResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /disputes [get]
+func MakeListDisputesEndpoint(s services.DisputeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListDisputesRequest
+		var ok bool = false
+
+		if req, ok = request.(ListDisputesRequest); !ok {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeListDisputesEndpoint", ErrInterfaceWrong)
+			return ListDisputesResponse{}, ErrInterfaceWrong
+		}
+		disputes, err := s.ListDisputes(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeListDisputesEndpoint", err)
+			return ListDisputesResponse{}, err
+		}
+		return ListDisputesResponse{Disputes: disputes}, nil
+	}
+}
+
+// @Summary Get Dispute
+// @Description Returns a dispute of the user; support staff see any dispute
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Success 200 {object} DisputeResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /disputes/{id} [get]
+func MakeGetDisputeEndpoint(s services.DisputeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req DisputeRequest
+		var ok bool = false
+
+		if req, ok = request.(DisputeRequest); !ok {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeGetDisputeEndpoint", ErrInterfaceWrong)
+			return DisputeResponse{}, ErrInterfaceWrong
+		}
+		dispute, err := s.GetDispute(ctx, req.Email, req.DisputeID)
+		if err != nil {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeGetDisputeEndpoint", err)
+			return DisputeResponse{}, err
+		}
+		return DisputeResponse{Dispute: dispute}, nil
+	}
+}
+
+// @Summary Add Dispute Evidence
+// @Description Attaches a file to a dispute not yet decided
+// @Accept image/jpeg,image/png,application/pdf
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Param name query string false "File name"
+// @Success 200 {object} DisputeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /disputes/{id}/evidence [post]
+func MakeAddDisputeEvidenceEndpoint(s services.DisputeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req AddDisputeEvidenceRequest
+		var ok bool = false
+
+		if req, ok = request.(AddDisputeEvidenceRequest); !ok {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeAddDisputeEvidenceEndpoint", ErrInterfaceWrong)
+			return DisputeResponse{}, ErrInterfaceWrong
+		}
+		dispute, err := s.AddEvidence(ctx, req.Email, req.DisputeID, req.Name, req.ContentType, req.Data)
+		if err != nil {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeAddDisputeEvidenceEndpoint", err)
+			return DisputeResponse{}, err
+		}
+		return DisputeResponse{Dispute: dispute}, nil
+	}
+}
+
+// @Summary Get Dispute Evidence
+// @Description Returns the content of a file attached to a dispute
+// @Produce image/jpeg,image/png,application/pdf
+// @Param id path string true "Dispute ID"
+// @Param evidence_id path string true "Evidence ID"
+// @Success 200 {file} file
+// @Failure 404 {object} ErrorResponse
+// @Router /disputes/{id}/evidence/{evidence_id} [get]
+func MakeGetDisputeEvidenceEndpoint(s services.DisputeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetDisputeEvidenceRequest
+		var ok bool = false
+
+		if req, ok = request.(GetDisputeEvidenceRequest); !ok {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeGetDisputeEvidenceEndpoint", ErrInterfaceWrong)
+			return GetDisputeEvidenceResponse{}, ErrInterfaceWrong
+		}
+		evidence, data, err := s.GetEvidence(ctx, req.Email, req.DisputeID, req.EvidenceID)
+		if err != nil {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeGetDisputeEvidenceEndpoint", err)
+			return GetDisputeEvidenceResponse{}, err
+		}
+		return GetDisputeEvidenceResponse{Evidence: evidence, Data: data}, nil
+	}
+}
+
+// @Summary List Dispute Queue
+// @Description Returns the disputes in a status, oldest first; support staff only
+// @Produce json
+// @Param status query string false "opened (default), under_review, resolved_in_favor or rejected"
+// @Success 200 {object} ListDisputesResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /disputes/queue [get]
+func MakeListDisputeQueueEndpoint(s services.DisputeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListDisputeQueueRequest
+		var ok bool = false
+
+		if req, ok = request.(ListDisputeQueueRequest); !ok {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeListDisputeQueueEndpoint", ErrInterfaceWrong)
+			return ListDisputesResponse{}, ErrInterfaceWrong
+		}
+		disputes, err := s.ListDisputeQueue(ctx, req.Email, req.Status)
+		if err != nil {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeListDisputeQueueEndpoint", err)
+			return ListDisputesResponse{}, err
+		}
+		return ListDisputesResponse{Disputes: disputes}, nil
+	}
+}
+
+// @Summary Review Dispute
+// @Description Takes an opened dispute under review; support staff only
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Success 200 {object} DisputeResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /disputes/{id}/review [post]
+func MakeReviewDisputeEndpoint(s services.DisputeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req DisputeRequest
+		var ok bool = false
+
+		if req, ok = request.(DisputeRequest); !ok {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeReviewDisputeEndpoint", ErrInterfaceWrong)
+			return DisputeResponse{}, ErrInterfaceWrong
+		}
+		dispute, err := s.ReviewDispute(ctx, req.Email, req.DisputeID)
+		if err != nil {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeReviewDisputeEndpoint", err)
+			return DisputeResponse{}, err
+		}
+		return DisputeResponse{Dispute: dispute}, nil
+	}
+}
+
+// @Summary Resolve Dispute
+// @Description Resolves a dispute under review in favor of the user, reversing all or part of the transfer, or rejects it; support staff only
+// @Accept json
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Param decision body ResolveDisputeRequest true "Decision"
+// @Success 200 {object} DisputeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /disputes/{id}/resolve [post]
+func MakeResolveDisputeEndpoint(s services.DisputeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ResolveDisputeRequest
+		var ok bool = false
+
+		if req, ok = request.(ResolveDisputeRequest); !ok {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeResolveDisputeEndpoint", ErrInterfaceWrong)
+			return DisputeResponse{}, ErrInterfaceWrong
+		}
+		dispute, err := s.ResolveDispute(ctx, req.Email, req.DisputeID, req.InFavor, req.Amount, req.Note)
+		if err != nil {
+			logger.Errorln("Layer:dispute_endpoint", "Method:MakeResolveDisputeEndpoint", err)
+			return DisputeResponse{}, err
+		}
+		return DisputeResponse{Dispute: dispute}, nil
+	}
+}
@@ -0,0 +1,134 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeOpenDisputeEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *disputeServiceMock
+		mockResponse    entities.Dispute
+		mockError       error
+		configureMock   func(*disputeServiceMock, entities.Dispute, error)
+		endpointRequest interface{}
+		expectedOutput  DisputeResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeOpenDisputeEndpoint",
+			mock:         &disputeServiceMock{},
+			mockResponse: entities.Dispute{ID: "d1", TransactionID: "t1", Reason: entities.DisputeWrongRecipient, Status: entities.DisputeOpened},
+			configureMock: func(m *disputeServiceMock, mockResponse entities.Dispute, mockError error) {
+				m.On("OpenDispute", mock.Anything, "alexer@gmail.com", "t1", entities.DisputeWrongRecipient, "").Return(mockResponse, mockError)
+			},
+			endpointRequest: OpenDisputeRequest{Email: "alexer@gmail.com", TransactionID: "t1", Reason: entities.DisputeWrongRecipient},
+			expectedOutput:  DisputeResponse{Dispute: entities.Dispute{ID: "d1", TransactionID: "t1", Reason: entities.DisputeWrongRecipient, Status: entities.DisputeOpened}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeOpenDisputeEndpoint with error Interface type wrong",
+			mock:            &disputeServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  DisputeResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeOpenDisputeEndpoint with error in the service",
+			mock:      &disputeServiceMock{},
+			mockError: services.ErrDisputeExists,
+			configureMock: func(m *disputeServiceMock, mockResponse entities.Dispute, mockError error) {
+				m.On("OpenDispute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: OpenDisputeRequest{Email: "alexer@gmail.com", TransactionID: "t1", Reason: entities.DisputeWrongRecipient},
+			expectedOutput:  DisputeResponse{},
+			expectedError:   services.ErrDisputeExists,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeOpenDisputeEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeResolveDisputeEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *disputeServiceMock
+		mockResponse    entities.Dispute
+		mockError       error
+		configureMock   func(*disputeServiceMock, entities.Dispute, error)
+		endpointRequest interface{}
+		expectedOutput  DisputeResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeResolveDisputeEndpoint",
+			mock:         &disputeServiceMock{},
+			mockResponse: entities.Dispute{ID: "d1", Status: entities.DisputeResolvedInFavor, ReversedAmount: 2000, ReversalEntryID: "r1"},
+			configureMock: func(m *disputeServiceMock, mockResponse entities.Dispute, mockError error) {
+				m.On("ResolveDispute", mock.Anything, "support@gmail.com", "d1", true, int64(2000), "").Return(mockResponse, mockError)
+			},
+			endpointRequest: ResolveDisputeRequest{Email: "support@gmail.com", DisputeID: "d1", InFavor: true, Amount: 2000},
+			expectedOutput:  DisputeResponse{Dispute: entities.Dispute{ID: "d1", Status: entities.DisputeResolvedInFavor, ReversedAmount: 2000, ReversalEntryID: "r1"}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeResolveDisputeEndpoint with error Interface type wrong",
+			mock:            &disputeServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  DisputeResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeResolveDisputeEndpoint with error in the service",
+			mock:      &disputeServiceMock{},
+			mockError: services.ErrReversalFunds,
+			configureMock: func(m *disputeServiceMock, mockResponse entities.Dispute, mockError error) {
+				m.On("ResolveDispute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: ResolveDisputeRequest{Email: "support@gmail.com", DisputeID: "d1", InFavor: true},
+			expectedOutput:  DisputeResponse{},
+			expectedError:   services.ErrReversalFunds,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeResolveDisputeEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
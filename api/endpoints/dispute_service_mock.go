@@ -0,0 +1,52 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type disputeServiceMock struct {
+	mock.Mock
+}
+
+func (s *disputeServiceMock) OpenDispute(ctx context.Context, email string, transactionID string, reason string, description string) (entities.Dispute, error) {
+	r := s.Called(ctx, email, transactionID, reason, description)
+	return r.Get(0).(entities.Dispute), r.Error(1)
+}
+
+func (s *disputeServiceMock) ListDisputes(ctx context.Context, email string) ([]entities.Dispute, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.Dispute), r.Error(1)
+}
+
+func (s *disputeServiceMock) GetDispute(ctx context.Context, email string, disputeID string) (entities.Dispute, error) {
+	r := s.Called(ctx, email, disputeID)
+	return r.Get(0).(entities.Dispute), r.Error(1)
+}
+
+func (s *disputeServiceMock) AddEvidence(ctx context.Context, email string, disputeID string, name string, contentType string, data []byte) (entities.Dispute, error) {
+	r := s.Called(ctx, email, disputeID, name, contentType, data)
+	return r.Get(0).(entities.Dispute), r.Error(1)
+}
+
+func (s *disputeServiceMock) GetEvidence(ctx context.Context, email string, disputeID string, evidenceID string) (entities.DisputeEvidence, []byte, error) {
+	r := s.Called(ctx, email, disputeID, evidenceID)
+	return r.Get(0).(entities.DisputeEvidence), r.Get(1).([]byte), r.Error(2)
+}
+
+func (s *disputeServiceMock) ListDisputeQueue(ctx context.Context, email string, status string) ([]entities.Dispute, error) {
+	r := s.Called(ctx, email, status)
+	return r.Get(0).([]entities.Dispute), r.Error(1)
+}
+
+func (s *disputeServiceMock) ReviewDispute(ctx context.Context, email string, disputeID string) (entities.Dispute, error) {
+	r := s.Called(ctx, email, disputeID)
+	return r.Get(0).(entities.Dispute), r.Error(1)
+}
+
+func (s *disputeServiceMock) ResolveDispute(ctx context.Context, email string, disputeID string, inFavor bool, amount int64, note string) (entities.Dispute, error) {
+	r := s.Called(ctx, email, disputeID, inFavor, amount, note)
+	return r.Get(0).(entities.Dispute), r.Error(1)
+}
@@ -0,0 +1,196 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// QuoteFeeRequest represents the request to know the fee of an operation
+// @Description The fee is computed with the rules in force for the user
+type QuoteFeeRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example "transfer"
+	Operation string `json:"operation"` // transfer, withdrawal or conversion
+	// @example "COP"
+	Currency string `json:"currency,omitempty"` // Currency, COP when empty
+	// @example 150000
+	Amount int64 `json:"amount"` // Amount in cents
+}
+
+// QuoteFeeResponse represents the fee an operation would be charged
+// @Description Response with the fee and the total to debit
+type QuoteFeeResponse struct {
+	Quote entities.FeeQuote `json:"quote"`           // Fee quote
+	Err   string            `json:"error,omitempty"` // Error message, if any
+}
+
+// ListFeeRulesRequest represents the request to list the fee rules
+type ListFeeRulesRequest struct {
+	Email string `json:"-"` // Email of the authenticated administrator
+}
+
+// ListFeeRulesResponse represents the fee rules
+// @Description Response with every fee rule
+type ListFeeRulesResponse struct {
+	Rules []entities.FeeRule `json:"rules"`           // Fee rules
+	Err   string             `json:"error,omitempty"` // Error message, if any
+}
+
+// FeeRuleRequest represents the request to create or replace a fee rule. The
+// body of the request is the rule itself.
+type FeeRuleRequest struct {
+	Email string           `json:"-"` // Email of the authenticated administrator
+	ID    string           `json:"-"` // Rule to replace
+	Rule  entities.FeeRule `json:"-"` // Rule to save
+}
+
+// FeeRuleResponse represents a saved fee rule
+// @Description Response with the saved fee rule
+type FeeRuleResponse struct {
+	Rule entities.FeeRule `json:"rule"`            // Saved fee rule
+	Err  string           `json:"error,omitempty"` // Error message, if any
+}
+
+// DeleteFeeRuleRequest represents the request to delete a fee rule
+type DeleteFeeRuleRequest struct {
+	Email string `json:"-"` // Email of the authenticated administrator
+	ID    string `json:"-"` // Rule to delete
+}
+
+// DeleteFeeRuleResponse represents the response when a fee rule is deleted
+type DeleteFeeRuleResponse struct {
+	Err string `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Quote Fee
+// @Description Returns the fee an operation would be charged before confirming it
+// @Accept json
+// @Produce json
+// @Param quote body QuoteFeeRequest true "Operation"
+// @Success 200 {object} QuoteFeeResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /fees/quote [post]
+func MakeQuoteFeeEndpoint(s services.FeeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req QuoteFeeRequest
+		var ok bool = false
+
+		if req, ok = request.(QuoteFeeRequest); !ok {
+			logger.Errorln("Layer:fee_endpoint", "Method:MakeQuoteFeeEndpoint", ErrInterfaceWrong)
+			return QuoteFeeResponse{}, ErrInterfaceWrong
+		}
+		quote, err := s.QuoteFee(ctx, req.Email, req.Operation, req.Currency, req.Amount)
+		if err != nil {
+			logger.Errorln("Layer:fee_endpoint", "Method:MakeQuoteFeeEndpoint", err)
+			return QuoteFeeResponse{}, err
+		}
+		return QuoteFeeResponse{Quote: quote}, nil
+	}
+}
+
+// @Summary List Fee Rules
+// @Description Lists every fee rule, for administrators
+// @Produce json
+// @Success 200 {object} ListFeeRulesResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/fee-rules [get]
+func MakeListFeeRulesEndpoint(s services.FeeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListFeeRulesRequest
+		var ok bool = false
+
+		if req, ok = request.(ListFeeRulesRequest); !ok {
+			logger.Errorln("Layer:fee_endpoint", "Method:MakeListFeeRulesEndpoint", ErrInterfaceWrong)
+			return ListFeeRulesResponse{}, ErrInterfaceWrong
+		}
+		rules, err := s.ListFeeRules(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:fee_endpoint", "Method:MakeListFeeRulesEndpoint", err)
+			return ListFeeRulesResponse{}, err
+		}
+		return ListFeeRulesResponse{Rules: rules}, nil
+	}
+}
+
+// @Summary Create Fee Rule
+// @Description Creates a fee rule, for administrators
+// @Accept json
+// @Produce json
+// @Param rule body entities.FeeRule true "Fee rule"
+// @Success 201 {object} FeeRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/fee-rules [post]
+func MakeCreateFeeRuleEndpoint(s services.FeeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req FeeRuleRequest
+		var ok bool = false
+
+		if req, ok = request.(FeeRuleRequest); !ok {
+			logger.Errorln("Layer:fee_endpoint", "Method:MakeCreateFeeRuleEndpoint", ErrInterfaceWrong)
+			return FeeRuleResponse{}, ErrInterfaceWrong
+		}
+		rule, err := s.CreateFeeRule(ctx, req.Email, req.Rule)
+		if err != nil {
+			logger.Errorln("Layer:fee_endpoint", "Method:MakeCreateFeeRuleEndpoint", err)
+			return FeeRuleResponse{}, err
+		}
+		return FeeRuleResponse{Rule: rule}, nil
+	}
+}
+
+// @Summary Update Fee Rule
+// @Description Replaces a fee rule, for administrators
+// @Accept json
+// @Produce json
+// @Param id path string true "Fee rule ID"
+// @Param rule body entities.FeeRule true "Fee rule"
+// @Success 200 {object} FeeRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/fee-rules/{id} [put]
+func MakeUpdateFeeRuleEndpoint(s services.FeeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req FeeRuleRequest
+		var ok bool = false
+
+		if req, ok = request.(FeeRuleRequest); !ok {
+			logger.Errorln("Layer:fee_endpoint", "Method:MakeUpdateFeeRuleEndpoint", ErrInterfaceWrong)
+			return FeeRuleResponse{}, ErrInterfaceWrong
+		}
+		req.Rule.ID = req.ID
+		rule, err := s.UpdateFeeRule(ctx, req.Email, req.Rule)
+		if err != nil {
+			logger.Errorln("Layer:fee_endpoint", "Method:MakeUpdateFeeRuleEndpoint", err)
+			return FeeRuleResponse{}, err
+		}
+		return FeeRuleResponse{Rule: rule}, nil
+	}
+}
+
+// @Summary Delete Fee Rule
+// @Description Deletes a fee rule, for administrators
+// @Param id path string true "Fee rule ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/fee-rules/{id} [delete]
+func MakeDeleteFeeRuleEndpoint(s services.FeeService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req DeleteFeeRuleRequest
+		var ok bool = false
+
+		if req, ok = request.(DeleteFeeRuleRequest); !ok {
+			logger.Errorln("Layer:fee_endpoint", "Method:MakeDeleteFeeRuleEndpoint", ErrInterfaceWrong)
+			return DeleteFeeRuleResponse{}, ErrInterfaceWrong
+		}
+		if err := s.DeleteFeeRule(ctx, req.Email, req.ID); err != nil {
+			logger.Errorln("Layer:fee_endpoint", "Method:MakeDeleteFeeRuleEndpoint", err)
+			return DeleteFeeRuleResponse{}, err
+		}
+		return DeleteFeeRuleResponse{}, nil
+	}
+}
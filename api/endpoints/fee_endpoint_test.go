@@ -0,0 +1,127 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeQuoteFeeEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *feeServiceMock
+		mockResponse    entities.FeeQuote
+		mockError       error
+		configureMock   func(*feeServiceMock, entities.FeeQuote, error)
+		endpointRequest interface{}
+		expectedOutput  QuoteFeeResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeQuoteFeeEndpoint",
+			mock:         &feeServiceMock{},
+			mockResponse: entities.FeeQuote{Operation: "transfer", Currency: "COP", Amount: 1000, Fee: 10, Total: 1010},
+			configureMock: func(m *feeServiceMock, mockResponse entities.FeeQuote, mockError error) {
+				m.On("QuoteFee", mock.Anything, "alexer@gmail.com", "transfer", "COP", int64(1000)).Return(mockResponse, mockError)
+			},
+			endpointRequest: QuoteFeeRequest{Email: "alexer@gmail.com", Operation: "transfer", Currency: "COP", Amount: 1000},
+			expectedOutput:  QuoteFeeResponse{Quote: entities.FeeQuote{Operation: "transfer", Currency: "COP", Amount: 1000, Fee: 10, Total: 1010}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeQuoteFeeEndpoint with error Interface type wrong",
+			mock:            &feeServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  QuoteFeeResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeQuoteFeeEndpoint with error in the service",
+			mock:      &feeServiceMock{},
+			mockError: services.ErrInvalidOperation,
+			configureMock: func(m *feeServiceMock, mockResponse entities.FeeQuote, mockError error) {
+				m.On("QuoteFee", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: QuoteFeeRequest{Email: "alexer@gmail.com", Operation: "deposit", Amount: 1000},
+			expectedOutput:  QuoteFeeResponse{},
+			expectedError:   services.ErrInvalidOperation,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeQuoteFeeEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeUpdateFeeRuleEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *feeServiceMock
+		mockResponse    entities.FeeRule
+		mockError       error
+		configureMock   func(*feeServiceMock, entities.FeeRule, error)
+		endpointRequest interface{}
+		expectedOutput  FeeRuleResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeUpdateFeeRuleEndpoint",
+			mock:         &feeServiceMock{},
+			mockResponse: entities.FeeRule{ID: "r1", Kind: entities.FeeFlat},
+			configureMock: func(m *feeServiceMock, mockResponse entities.FeeRule, mockError error) {
+				m.On("UpdateFeeRule", mock.Anything, "admin@gmail.com", entities.FeeRule{ID: "r1", Kind: entities.FeeFlat}).Return(mockResponse, mockError)
+			},
+			endpointRequest: FeeRuleRequest{Email: "admin@gmail.com", ID: "r1", Rule: entities.FeeRule{Kind: entities.FeeFlat}},
+			expectedOutput:  FeeRuleResponse{Rule: entities.FeeRule{ID: "r1", Kind: entities.FeeFlat}},
+			expectedError:   nil,
+		},
+		{
+			testName:  "test MakeUpdateFeeRuleEndpoint with error in the service",
+			mock:      &feeServiceMock{},
+			mockError: services.ErrAdminRequired,
+			configureMock: func(m *feeServiceMock, mockResponse entities.FeeRule, mockError error) {
+				m.On("UpdateFeeRule", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: FeeRuleRequest{Email: "alexer@gmail.com", ID: "r1"},
+			expectedOutput:  FeeRuleResponse{},
+			expectedError:   services.ErrAdminRequired,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeUpdateFeeRuleEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type feeServiceMock struct {
+	mock.Mock
+}
+
+func (s *feeServiceMock) QuoteFee(ctx context.Context, email string, operation string, currency string, amount int64) (entities.FeeQuote, error) {
+	r := s.Called(ctx, email, operation, currency, amount)
+	return r.Get(0).(entities.FeeQuote), r.Error(1)
+}
+
+func (s *feeServiceMock) ComputeFee(ctx context.Context, user entities.User, operation string, currency string, amount int64) (entities.FeeQuote, error) {
+	r := s.Called(ctx, user, operation, currency, amount)
+	return r.Get(0).(entities.FeeQuote), r.Error(1)
+}
+
+func (s *feeServiceMock) ListFeeRules(ctx context.Context, email string) ([]entities.FeeRule, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.FeeRule), r.Error(1)
+}
+
+func (s *feeServiceMock) CreateFeeRule(ctx context.Context, email string, rule entities.FeeRule) (entities.FeeRule, error) {
+	r := s.Called(ctx, email, rule)
+	return r.Get(0).(entities.FeeRule), r.Error(1)
+}
+
+func (s *feeServiceMock) UpdateFeeRule(ctx context.Context, email string, rule entities.FeeRule) (entities.FeeRule, error) {
+	r := s.Called(ctx, email, rule)
+	return r.Get(0).(entities.FeeRule), r.Error(1)
+}
+
+func (s *feeServiceMock) DeleteFeeRule(ctx context.Context, email string, id string) error {
+	r := s.Called(ctx, email, id)
+	return r.Error(0)
+}
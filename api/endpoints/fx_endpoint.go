@@ -0,0 +1,108 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateQuoteRequest represents the request to price a currency conversion
+// @Description The quoted rate is locked until the quote expires
+type CreateQuoteRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet to convert within
+	// @example "COP"
+	From string `json:"from"` // Currency to sell
+	// @example "USD"
+	To string `json:"to"` // Currency to buy
+	// @example 4150000
+	Amount int64 `json:"amount"` // Amount to sell in cents
+}
+
+// CreateQuoteResponse represents the response when a quote is created
+// @Description Response with the locked quote
+type CreateQuoteResponse struct {
+	Quote entities.FXQuote `json:"quote"`           // Locked quote
+	Err   string           `json:"error,omitempty"` // Error message, if any
+}
+
+// CreateConversionRequest represents the request to execute a quote
+// @Description Executes a quote that has not expired
+type CreateConversionRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet to convert within
+	// @example "66f1b2c3d4e5f60718293a4b"
+	QuoteID string `json:"quote_id"` // Quote to execute
+}
+
+// Resource returns the wallet the conversion is posted to.
+func (r CreateConversionRequest) Resource() string {
+	return "wallets/" + r.WalletID
+}
+
+// CreateConversionResponse represents the response when a conversion is posted
+// @Description Response when a conversion is completed
+type CreateConversionResponse struct {
+	Conversion entities.Conversion `json:"conversion"`      // Posted conversion
+	Err        string              `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Create FX Quote
+// @Description Quotes a conversion between two currencies of the wallet
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param quote body CreateQuoteRequest true "Quote"
+// @Success 201 {object} CreateQuoteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /wallets/{id}/conversions/quotes [post]
+func MakeCreateQuoteEndpoint(s services.FXService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateQuoteRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateQuoteRequest); !ok {
+			logger.Errorln("Layer:fx_endpoint", "Method:MakeCreateQuoteEndpoint", ErrInterfaceWrong)
+			return CreateQuoteResponse{}, ErrInterfaceWrong
+		}
+		quote, err := s.CreateQuote(ctx, req.Email, req.WalletID, req.From, req.To, req.Amount)
+		if err != nil {
+			logger.Errorln("Layer:fx_endpoint", "Method:MakeCreateQuoteEndpoint", err)
+			return CreateQuoteResponse{}, err
+		}
+		return CreateQuoteResponse{Quote: quote}, nil
+	}
+}
+
+// @Summary Create FX Conversion
+// @Description Executes a quote, moving funds between the wallet's currencies
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param conversion body CreateConversionRequest true "Conversion"
+// @Success 201 {object} CreateConversionResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /wallets/{id}/conversions [post]
+func MakeCreateConversionEndpoint(s services.FXService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateConversionRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateConversionRequest); !ok {
+			logger.Errorln("Layer:fx_endpoint", "Method:MakeCreateConversionEndpoint", ErrInterfaceWrong)
+			return CreateConversionResponse{}, ErrInterfaceWrong
+		}
+		conversion, err := s.CreateConversion(ctx, req.Email, req.WalletID, req.QuoteID)
+		if err != nil {
+			logger.Errorln("Layer:fx_endpoint", "Method:MakeCreateConversionEndpoint", err)
+			return CreateConversionResponse{}, err
+		}
+		return CreateConversionResponse{Conversion: conversion}, nil
+	}
+}
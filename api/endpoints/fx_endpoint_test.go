@@ -0,0 +1,134 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreateQuoteEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *fxServiceMock
+		mockResponse    entities.FXQuote
+		mockError       error
+		configureMock   func(*fxServiceMock, entities.FXQuote, error)
+		endpointRequest interface{}
+		expectedOutput  CreateQuoteResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreateQuoteEndpoint",
+			mock:         &fxServiceMock{},
+			mockResponse: entities.FXQuote{ID: "q1", From: "COP", To: "USD", FromAmount: 400000, ToAmount: 99},
+			configureMock: func(m *fxServiceMock, mockResponse entities.FXQuote, mockError error) {
+				m.On("CreateQuote", mock.Anything, "alexer@gmail.com", "w1", "COP", "USD", int64(400000)).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateQuoteRequest{Email: "alexer@gmail.com", WalletID: "w1", From: "COP", To: "USD", Amount: 400000},
+			expectedOutput:  CreateQuoteResponse{Quote: entities.FXQuote{ID: "q1", From: "COP", To: "USD", FromAmount: 400000, ToAmount: 99}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreateQuoteEndpoint with error Interface type wrong",
+			mock:            &fxServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  CreateQuoteResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreateQuoteEndpoint with error in the service",
+			mock:      &fxServiceMock{},
+			mockError: services.ErrRateNotFound,
+			configureMock: func(m *fxServiceMock, mockResponse entities.FXQuote, mockError error) {
+				m.On("CreateQuote", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateQuoteRequest{Email: "alexer@gmail.com", WalletID: "w1", From: "COP", To: "JPY", Amount: 100},
+			expectedOutput:  CreateQuoteResponse{},
+			expectedError:   services.ErrRateNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreateQuoteEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeCreateConversionEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *fxServiceMock
+		mockResponse    entities.Conversion
+		mockError       error
+		configureMock   func(*fxServiceMock, entities.Conversion, error)
+		endpointRequest interface{}
+		expectedOutput  CreateConversionResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreateConversionEndpoint",
+			mock:         &fxServiceMock{},
+			mockResponse: entities.Conversion{ID: "c1", QuoteID: "q1"},
+			configureMock: func(m *fxServiceMock, mockResponse entities.Conversion, mockError error) {
+				m.On("CreateConversion", mock.Anything, "alexer@gmail.com", "w1", "q1").Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateConversionRequest{Email: "alexer@gmail.com", WalletID: "w1", QuoteID: "q1"},
+			expectedOutput:  CreateConversionResponse{Conversion: entities.Conversion{ID: "c1", QuoteID: "q1"}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreateConversionEndpoint with error Interface type wrong",
+			mock:            &fxServiceMock{},
+			endpointRequest: CreateQuoteRequest{},
+			expectedOutput:  CreateConversionResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreateConversionEndpoint with error in the service",
+			mock:      &fxServiceMock{},
+			mockError: services.ErrQuoteExpired,
+			configureMock: func(m *fxServiceMock, mockResponse entities.Conversion, mockError error) {
+				m.On("CreateConversion", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateConversionRequest{Email: "alexer@gmail.com", WalletID: "w1", QuoteID: "q1"},
+			expectedOutput:  CreateConversionResponse{},
+			expectedError:   services.ErrQuoteExpired,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreateConversionEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type fxServiceMock struct {
+	mock.Mock
+}
+
+func (s *fxServiceMock) CreateQuote(ctx context.Context, email string, walletID string, from string, to string, amount int64) (entities.FXQuote, error) {
+	r := s.Called(ctx, email, walletID, from, to, amount)
+	return r.Get(0).(entities.FXQuote), r.Error(1)
+}
+
+func (s *fxServiceMock) CreateConversion(ctx context.Context, email string, walletID string, quoteID string) (entities.Conversion, error) {
+	r := s.Called(ctx, email, walletID, quoteID)
+	return r.Get(0).(entities.Conversion), r.Error(1)
+}
@@ -0,0 +1,96 @@
+package endpoints
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"my_wallet/api/services"
+	"my_wallet/api/utils/jwt"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+type idempotencyContextKey struct{}
+
+// resourceRequest is implemented by requests that act on a resource named in
+// the URL, which their JSON body leaves out.
+type resourceRequest interface {
+	Resource() string
+}
+
+// ContextWithIdempotencyKey stores the Idempotency-Key sent by the client.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the Idempotency-Key of the request, if any.
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyContextKey{}).(string)
+	return key
+}
+
+// IdempotencyMiddleware makes an endpoint safe to retry. Requests without an
+// Idempotency-Key go straight through. A retry with the same key and the same
+// request gets the stored response back instead of running the endpoint again.
+// Keys are scoped by endpoint name and authenticated user; the resource in the
+// URL is part of the request, so a key reused on another one is refused. A
+// request held for review is finished too: its retries get the same review.
+func IdempotencyMiddleware(name string, s services.IdempotencyService, logger logrus.FieldLogger) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			key := IdempotencyKeyFromContext(ctx)
+			if key == "" {
+				return next(ctx, request)
+			}
+			body, err := json.Marshal(request)
+			if err != nil {
+				logger.Errorln("Layer:idempotency_middleware", "Method:IdempotencyMiddleware", err)
+				return nil, err
+			}
+			if r, ok := request.(resourceRequest); ok {
+				body = append([]byte(r.Resource()+"\n"), body...)
+			}
+			sum := sha256.Sum256(body)
+			fingerprint := hex.EncodeToString(sum[:])
+			scope := name + ":" + jwt.EmailFromContext(ctx)
+
+			record, replay, err := s.Begin(ctx, scope, key, fingerprint)
+			if err != nil {
+				return nil, err
+			}
+			if replay {
+				if record.ReviewID != "" {
+					return nil, &services.TransferHeldError{ReviewID: record.ReviewID}
+				}
+				return json.RawMessage(record.Response), nil
+			}
+
+			response, err = next(ctx, request)
+			var heldErr *services.TransferHeldError
+			if errors.As(err, &heldErr) {
+				if holdErr := s.Hold(ctx, scope, key, heldErr.ReviewID); holdErr != nil {
+					logger.Errorln("Layer:idempotency_middleware", "Method:IdempotencyMiddleware", holdErr)
+				}
+				return response, err
+			}
+			if err != nil {
+				if releaseErr := s.Release(ctx, scope, key); releaseErr != nil {
+					logger.Errorln("Layer:idempotency_middleware", "Method:IdempotencyMiddleware", releaseErr)
+				}
+				return response, err
+			}
+			stored, err := json.Marshal(response)
+			if err != nil {
+				logger.Errorln("Layer:idempotency_middleware", "Method:IdempotencyMiddleware", err)
+				return response, nil
+			}
+			if err := s.Complete(ctx, scope, key, stored); err != nil {
+				logger.Errorln("Layer:idempotency_middleware", "Method:IdempotencyMiddleware", err)
+			}
+			return response, nil
+		}
+	}
+}
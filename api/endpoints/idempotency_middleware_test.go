@@ -0,0 +1,189 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	errService := errors.New("service failed")
+
+	testScenarios := []struct {
+		testName       string
+		key            string
+		configureMock  func(*idempotencyServiceMock)
+		nextResponse   interface{}
+		nextError      error
+		expectedCalls  int
+		expectedOutput interface{}
+		expectedError  error
+	}{
+		{
+			testName:       "test without Idempotency-Key",
+			key:            "",
+			nextResponse:   CreateTransferResponse{Transfer: entities.Transfer{ID: "1"}},
+			expectedCalls:  1,
+			expectedOutput: CreateTransferResponse{Transfer: entities.Transfer{ID: "1"}},
+		},
+		{
+			testName: "test first request stores the response",
+			key:      "abc",
+			configureMock: func(m *idempotencyServiceMock) {
+				m.On("Begin", mock.Anything, "CreateTransfer:", "abc", mock.Anything).Return(entities.IdempotencyRecord{}, false, nil)
+				m.On("Complete", mock.Anything, "CreateTransfer:", "abc", []byte(`{"transfer":{"id":"1"}}`)).Return(nil)
+			},
+			nextResponse:   map[string]interface{}{"transfer": map[string]string{"id": "1"}},
+			expectedCalls:  1,
+			expectedOutput: map[string]interface{}{"transfer": map[string]string{"id": "1"}},
+		},
+		{
+			testName: "test retry replays the stored response",
+			key:      "abc",
+			configureMock: func(m *idempotencyServiceMock) {
+				m.On("Begin", mock.Anything, "CreateTransfer:", "abc", mock.Anything).Return(entities.IdempotencyRecord{Response: []byte(`{"transfer":{"id":"1"}}`)}, true, nil)
+			},
+			expectedCalls:  0,
+			expectedOutput: json.RawMessage(`{"transfer":{"id":"1"}}`),
+		},
+		{
+			testName: "test key reused with a different request",
+			key:      "abc",
+			configureMock: func(m *idempotencyServiceMock) {
+				m.On("Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(entities.IdempotencyRecord{}, false, services.ErrIdempotencyKeyReused)
+			},
+			expectedCalls: 0,
+			expectedError: services.ErrIdempotencyKeyReused,
+		},
+		{
+			testName: "test failed request releases the key",
+			key:      "abc",
+			configureMock: func(m *idempotencyServiceMock) {
+				m.On("Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(entities.IdempotencyRecord{}, false, nil)
+				m.On("Release", mock.Anything, "CreateTransfer:", "abc").Return(nil)
+			},
+			nextResponse:   CreateTransferResponse{},
+			nextError:      errService,
+			expectedCalls:  1,
+			expectedOutput: CreateTransferResponse{},
+			expectedError:  errService,
+		},
+		{
+			testName: "test held request keeps the key",
+			key:      "abc",
+			configureMock: func(m *idempotencyServiceMock) {
+				m.On("Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(entities.IdempotencyRecord{}, false, nil)
+				m.On("Hold", mock.Anything, "CreateTransfer:", "abc", "r1").Return(nil)
+			},
+			nextResponse:   CreateTransferResponse{},
+			nextError:      &services.TransferHeldError{ReviewID: "r1"},
+			expectedCalls:  1,
+			expectedOutput: CreateTransferResponse{},
+			expectedError:  &services.TransferHeldError{ReviewID: "r1"},
+		},
+		{
+			testName: "test retry of a held request replays the review",
+			key:      "abc",
+			configureMock: func(m *idempotencyServiceMock) {
+				m.On("Begin", mock.Anything, "CreateTransfer:", "abc", mock.Anything).Return(entities.IdempotencyRecord{Status: entities.IdempotencyCompleted, ReviewID: "r1"}, true, nil)
+			},
+			expectedCalls: 0,
+			expectedError: &services.TransferHeldError{ReviewID: "r1"},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			m := &idempotencyServiceMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(m)
+			}
+			calls := 0
+			next := func(ctx context.Context, request interface{}) (interface{}, error) {
+				calls++
+				return tt.nextResponse, tt.nextError
+			}
+			ctx := ContextWithIdempotencyKey(context.TODO(), tt.key)
+
+			// Act
+			result, err := IdempotencyMiddleware("CreateTransfer", m, logrus.StandardLogger())(next)(ctx, CreateTransferRequest{Amount: 100})
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+			assert.Equal(t, tt.expectedCalls, calls)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotencyMiddlewareResource(t *testing.T) {
+	testScenarios := []struct {
+		testName      string
+		first         interface{}
+		second        interface{}
+		expectedEqual bool
+	}{
+		{
+			testName:      "test same pocket",
+			first:         MovePocketRequest{WalletID: "w1", PocketID: "p1", Amount: 100},
+			second:        MovePocketRequest{WalletID: "w1", PocketID: "p1", Amount: 100},
+			expectedEqual: true,
+		},
+		{
+			testName: "test another pocket",
+			first:    MovePocketRequest{WalletID: "w1", PocketID: "p1", Amount: 100},
+			second:   MovePocketRequest{WalletID: "w1", PocketID: "p2", Amount: 100},
+		},
+		{
+			testName: "test another payment request",
+			first:    AnswerPaymentRequestRequest{ID: "r1"},
+			second:   AnswerPaymentRequestRequest{ID: "r2"},
+		},
+		{
+			testName: "test another wallet",
+			first:    CreateConversionRequest{WalletID: "w1", QuoteID: "q1"},
+			second:   CreateConversionRequest{WalletID: "w2", QuoteID: "q1"},
+		},
+		{
+			testName: "test another dispute",
+			first:    ResolveDisputeRequest{DisputeID: "d1", InFavor: true},
+			second:   ResolveDisputeRequest{DisputeID: "d2", InFavor: true},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			var fingerprints []string
+			m := &idempotencyServiceMock{}
+			m.On("Begin", mock.Anything, "MoveToPocket:", "abc", mock.Anything).Run(func(args mock.Arguments) {
+				fingerprints = append(fingerprints, args.String(3))
+			}).Return(entities.IdempotencyRecord{}, false, nil)
+			m.On("Complete", mock.Anything, "MoveToPocket:", "abc", mock.Anything).Return(nil)
+			next := func(ctx context.Context, request interface{}) (interface{}, error) {
+				return PocketResponse{}, nil
+			}
+			handler := IdempotencyMiddleware("MoveToPocket", m, logrus.StandardLogger())(next)
+			ctx := ContextWithIdempotencyKey(context.TODO(), "abc")
+
+			// Act
+			_, _ = handler(ctx, tt.first)
+			_, _ = handler(ctx, tt.second)
+
+			// Assert
+			assert.Len(t, fingerprints, 2)
+			assert.Equal(t, tt.expectedEqual, fingerprints[0] == fingerprints[1])
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type idempotencyServiceMock struct {
+	mock.Mock
+}
+
+func (s *idempotencyServiceMock) Begin(ctx context.Context, scope string, key string, fingerprint string) (entities.IdempotencyRecord, bool, error) {
+	r := s.Called(ctx, scope, key, fingerprint)
+	return r.Get(0).(entities.IdempotencyRecord), r.Bool(1), r.Error(2)
+}
+
+func (s *idempotencyServiceMock) Complete(ctx context.Context, scope string, key string, response []byte) error {
+	r := s.Called(ctx, scope, key, response)
+	return r.Error(0)
+}
+
+func (s *idempotencyServiceMock) Hold(ctx context.Context, scope string, key string, reviewID string) error {
+	r := s.Called(ctx, scope, key, reviewID)
+	return r.Error(0)
+}
+
+func (s *idempotencyServiceMock) Release(ctx context.Context, scope string, key string) error {
+	r := s.Called(ctx, scope, key)
+	return r.Error(0)
+}
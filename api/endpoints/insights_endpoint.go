@@ -0,0 +1,53 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// GetInsightsRequest represents the request for the spending insights of a wallet
+type GetInsightsRequest struct {
+	Email       string `json:"-"` // Email of the authenticated user
+	WalletID    string `json:"-"` // Wallet ID
+	Period      string `json:"-"` // week, month, quarter or year up to now; month by default
+	Granularity string `json:"-"` // day, week or month buckets of the series; by period by default
+}
+
+// GetInsightsResponse represents the spending insights of a wallet
+// @Description Totals per category and counterparty, a series, income vs. expense and top merchants
+type GetInsightsResponse struct {
+	Insights entities.Insights `json:"insights"`        // Insights
+	Err      string            `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Get Insights
+// @Description Summarizes the income and expense of a wallet over a period
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param period query string false "week, month, quarter or year"
+// @Param granularity query string false "day, week or month"
+// @Success 200 {object} GetInsightsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /wallets/{id}/insights [get]
+func MakeGetInsightsEndpoint(s services.InsightsService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetInsightsRequest
+		var ok bool = false
+
+		if req, ok = request.(GetInsightsRequest); !ok {
+			logger.Errorln("Layer:insights_endpoint", "Method:MakeGetInsightsEndpoint", ErrInterfaceWrong)
+			return GetInsightsResponse{}, ErrInterfaceWrong
+		}
+		insights, err := s.GetInsights(ctx, req.Email, req.WalletID, req.Period, req.Granularity)
+		if err != nil {
+			logger.Errorln("Layer:insights_endpoint", "Method:MakeGetInsightsEndpoint", err)
+			return GetInsightsResponse{}, err
+		}
+		return GetInsightsResponse{Insights: insights}, nil
+	}
+}
@@ -0,0 +1,73 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeGetInsightsEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *insightsServiceMock
+		mockResponse    entities.Insights
+		mockError       error
+		configureMock   func(*insightsServiceMock, entities.Insights, error)
+		endpointRequest interface{}
+		expectedOutput  GetInsightsResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeGetInsightsEndpoint",
+			mock:         &insightsServiceMock{},
+			mockResponse: entities.Insights{WalletID: "w1", Period: "week", Income: 500000, Expense: 200000, Net: 300000},
+			configureMock: func(m *insightsServiceMock, mockResponse entities.Insights, mockError error) {
+				m.On("GetInsights", mock.Anything, "alexer@gmail.com", "w1", "week", "").Return(mockResponse, mockError)
+			},
+			endpointRequest: GetInsightsRequest{Email: "alexer@gmail.com", WalletID: "w1", Period: "week"},
+			expectedOutput:  GetInsightsResponse{Insights: entities.Insights{WalletID: "w1", Period: "week", Income: 500000, Expense: 200000, Net: 300000}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeGetInsightsEndpoint with error Interface type wrong",
+			mock:            &insightsServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  GetInsightsResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeGetInsightsEndpoint with error in the service",
+			mock:      &insightsServiceMock{},
+			mockError: services.ErrInvalidInsightPeriod,
+			configureMock: func(m *insightsServiceMock, mockResponse entities.Insights, mockError error) {
+				m.On("GetInsights", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: GetInsightsRequest{Email: "alexer@gmail.com", WalletID: "w1", Period: "decade"},
+			expectedOutput:  GetInsightsResponse{},
+			expectedError:   services.ErrInvalidInsightPeriod,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeGetInsightsEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type insightsServiceMock struct {
+	mock.Mock
+}
+
+func (s *insightsServiceMock) GetInsights(ctx context.Context, email string, walletID string, period string, granularity string) (entities.Insights, error) {
+	r := s.Called(ctx, email, walletID, period, granularity)
+	return r.Get(0).(entities.Insights), r.Error(1)
+}
+
+func (s *insightsServiceMock) RollupDays(ctx context.Context, now time.Time) (int, error) {
+	r := s.Called(ctx, now)
+	return r.Int(0), r.Error(1)
+}
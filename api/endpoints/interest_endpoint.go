@@ -0,0 +1,89 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// GetInterestRequest represents the request for the interest of a wallet
+type GetInterestRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet ID
+}
+
+// GetInterestResponse represents the interest of a wallet and its pockets
+type GetInterestResponse struct {
+	Interest entities.InterestSummary `json:"interest"`        // Rates, accrued interest not paid yet and latest payouts
+	Err      string                   `json:"error,omitempty"` // Error message, if any
+}
+
+// AccrueInterestRequest represents the request of support staff to accrue a day of interest again
+type AccrueInterestRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example "2026-10-18"
+	Day time.Time `json:"-"` // Day to accrue, YYYY-MM-DD
+}
+
+// AccrueInterestResponse represents what accruing a day did
+type AccrueInterestResponse struct {
+	Run entities.InterestRun `json:"run"`             // Accounts accrued and skipped
+	Err string               `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Get Interest
+// @Description Returns the rate the wallet and each of its pockets earn, the interest they accrued and were not paid yet, in millionths of a cent and in cents, and the latest monthly payouts
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {object} GetInterestResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /wallets/{id}/interest [get]
+func MakeGetInterestEndpoint(s services.InterestService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetInterestRequest
+		var ok bool = false
+
+		if req, ok = request.(GetInterestRequest); !ok {
+			logger.Errorln("Layer:interest_endpoint", "Method:MakeGetInterestEndpoint", ErrInterfaceWrong)
+			return GetInterestResponse{}, ErrInterfaceWrong
+		}
+		interest, err := s.GetInterest(ctx, req.Email, req.WalletID)
+		if err != nil {
+			logger.Errorln("Layer:interest_endpoint", "Method:MakeGetInterestEndpoint", err)
+			return GetInterestResponse{}, err
+		}
+		return GetInterestResponse{Interest: interest}, nil
+	}
+}
+
+// @Summary Accrue Interest
+// @Description Accrues the interest of a day that has ended again, skipping the accounts that already accrued it; support staff only
+// @Accept json
+// @Produce json
+// @Param accrual body AccrueInterestRequest true "Day, as {\"day\": \"2026-10-18\"}"
+// @Success 200 {object} AccrueInterestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/interest/accruals [post]
+func MakeAccrueInterestEndpoint(s services.InterestService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req AccrueInterestRequest
+		var ok bool = false
+
+		if req, ok = request.(AccrueInterestRequest); !ok {
+			logger.Errorln("Layer:interest_endpoint", "Method:MakeAccrueInterestEndpoint", ErrInterfaceWrong)
+			return AccrueInterestResponse{}, ErrInterfaceWrong
+		}
+		run, err := s.AccrueDay(ctx, req.Email, req.Day)
+		if err != nil {
+			logger.Errorln("Layer:interest_endpoint", "Method:MakeAccrueInterestEndpoint", err)
+			return AccrueInterestResponse{}, err
+		}
+		return AccrueInterestResponse{Run: run}, nil
+	}
+}
@@ -0,0 +1,78 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeGetInterestEndpoint(t *testing.T) {
+	summary := entities.InterestSummary{
+		WalletID: "w1",
+		Accounts: []entities.InterestAccount{{AccountKind: entities.InterestAccountWallet, AccountID: "w1", Currency: "COP", Rate: &entities.InterestRate{AnnualRateBps: 200, DayCount: "ACT/365"}, Accrued: 2_500_000, AccruedAmount: 2}},
+		Payouts:  []entities.InterestPayout{},
+	}
+
+	testScenarios := []struct {
+		testName        string
+		mock            *interestServiceMock
+		mockResponse    entities.InterestSummary
+		mockError       error
+		configureMock   func(*interestServiceMock, entities.InterestSummary, error)
+		endpointRequest interface{}
+		expectedOutput  GetInterestResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeGetInterestEndpoint",
+			mock:         &interestServiceMock{},
+			mockResponse: summary,
+			configureMock: func(m *interestServiceMock, mockResponse entities.InterestSummary, mockError error) {
+				m.On("GetInterest", mock.Anything, "juan@gmail.com", "w1").Return(mockResponse, mockError)
+			},
+			endpointRequest: GetInterestRequest{Email: "juan@gmail.com", WalletID: "w1"},
+			expectedOutput:  GetInterestResponse{Interest: summary},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeGetInterestEndpoint with error Interface type wrong",
+			mock:            &interestServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  GetInterestResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeGetInterestEndpoint with error in the service",
+			mock:      &interestServiceMock{},
+			mockError: services.ErrWalletForbidden,
+			configureMock: func(m *interestServiceMock, mockResponse entities.InterestSummary, mockError error) {
+				m.On("GetInterest", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: GetInterestRequest{Email: "juan@gmail.com", WalletID: "w2"},
+			expectedOutput:  GetInterestResponse{},
+			expectedError:   services.ErrWalletForbidden,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeGetInterestEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type interestServiceMock struct {
+	mock.Mock
+}
+
+func (s *interestServiceMock) GetInterest(ctx context.Context, email string, walletID string) (entities.InterestSummary, error) {
+	r := s.Called(ctx, email, walletID)
+	return r.Get(0).(entities.InterestSummary), r.Error(1)
+}
+
+func (s *interestServiceMock) AccrueDay(ctx context.Context, email string, day time.Time) (entities.InterestRun, error) {
+	r := s.Called(ctx, email, day)
+	return r.Get(0).(entities.InterestRun), r.Error(1)
+}
+
+func (s *interestServiceMock) RunInterest(ctx context.Context, now time.Time) (int, error) {
+	r := s.Called(ctx, now)
+	return r.Int(0), r.Error(1)
+}
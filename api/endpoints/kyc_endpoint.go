@@ -0,0 +1,312 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// GetKYCRequest represents the request for the KYC status of the user
+type GetKYCRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// GetKYCResponse represents the KYC status of the user
+// @Description Level, application in progress and audit trail
+type GetKYCResponse struct {
+	KYC entities.KYCStatus `json:"kyc"`             // KYC status
+	Err string             `json:"error,omitempty"` // Error message, if any
+}
+
+// CreateKYCApplicationRequest represents the request to apply for a KYC level
+type CreateKYCApplicationRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example "basic"
+	Level string `json:"level"` // Level applied for: basic or full
+}
+
+// KYCApplicationRequest represents the request to act on a KYC application
+type KYCApplicationRequest struct {
+	Email         string `json:"-"` // Email of the authenticated user
+	ApplicationID string `json:"-"` // Application ID
+}
+
+// UploadKYCDocumentRequest represents the upload of a KYC document
+// @Description The body is the document itself, a JPEG, PNG or PDF of up to 5 MB
+type UploadKYCDocumentRequest struct {
+	Email         string `json:"-"` // Email of the authenticated user
+	ApplicationID string `json:"-"` // Application ID
+	DocumentType  string `json:"-"` // id_front, id_back, selfie or proof_of_address
+	ContentType   string `json:"-"` // Content type of the document
+	Data          []byte `json:"-"` // Document
+}
+
+// ReviewKYCApplicationRequest represents the decision of support staff on a KYC application
+type ReviewKYCApplicationRequest struct {
+	Email         string `json:"-"` // Email of the authenticated user
+	ApplicationID string `json:"-"` // Application ID
+	// @example true
+	Approve bool `json:"approve"` // Whether the application is approved
+	// @example "Document is blurry"
+	Reason string `json:"reason,omitempty"` // Reason, required to reject
+}
+
+// KYCApplicationResponse represents a KYC application
+type KYCApplicationResponse struct {
+	Application entities.KYCApplication `json:"application"`     // Application
+	Err         string                  `json:"error,omitempty"` // Error message, if any
+}
+
+// ListKYCApplicationsRequest represents the request for the KYC review queue
+type ListKYCApplicationsRequest struct {
+	Email  string `json:"-"` // Email of the authenticated user
+	Status string `json:"-"` // Status of the applications; submitted by default
+}
+
+// ListKYCApplicationsResponse represents the KYC review queue
+type ListKYCApplicationsResponse struct {
+	Applications []entities.KYCApplication `json:"applications"`    // Applications
+	Err          string                    `json:"error,omitempty"` // Error message, if any
+}
+
+// GetKYCDocumentRequest represents the request for a document of a KYC application
+type GetKYCDocumentRequest struct {
+	Email         string `json:"-"` // Email of the authenticated user
+	ApplicationID string `json:"-"` // Application ID
+	DocumentType  string `json:"-"` // Document type
+}
+
+// GetKYCDocumentResponse represents a document of a KYC application
+type GetKYCDocumentResponse struct {
+	Document entities.KYCDocument // Document
+	Data     []byte               // Content of the document
+}
+
+// ListKYCEventsRequest represents the request for the KYC audit trail of a user
+type ListKYCEventsRequest struct {
+	Email  string `json:"-"` // Email of the authenticated user
+	UserID string `json:"-"` // User whose trail is listed
+}
+
+// ListKYCEventsResponse represents the KYC audit trail of a user
+type ListKYCEventsResponse struct {
+	Events []entities.KYCEvent `json:"events"`          // Events, newest first
+	Err    string              `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Get KYC
+// @Description Returns the KYC level of the user, the application in progress and the audit trail
+// @Produce json
+// @Success 200 {object} GetKYCResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /kyc [get]
+func MakeGetKYCEndpoint(s services.KYCService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetKYCRequest
+		var ok bool = false
+
+		if req, ok = request.(GetKYCRequest); !ok {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeGetKYCEndpoint", ErrInterfaceWrong)
+			return GetKYCResponse{}, ErrInterfaceWrong
+		}
+		status, err := s.GetKYC(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeGetKYCEndpoint", err)
+			return GetKYCResponse{}, err
+		}
+		return GetKYCResponse{KYC: status}, nil
+	}
+}
+
+// @Summary Create KYC Application
+// @Description Starts a draft application for a KYC level above the current one
+// @Accept json
+// @Produce json
+// @Param application body CreateKYCApplicationRequest true "Application"
+// @Success 201 {object} KYCApplicationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /kyc/applications [post]
+func MakeCreateKYCApplicationEndpoint(s services.KYCService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateKYCApplicationRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateKYCApplicationRequest); !ok {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeCreateKYCApplicationEndpoint", ErrInterfaceWrong)
+			return KYCApplicationResponse{}, ErrInterfaceWrong
+		}
+		application, err := s.CreateApplication(ctx, req.Email, req.Level)
+		if err != nil {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeCreateKYCApplicationEndpoint", err)
+			return KYCApplicationResponse{}, err
+		}
+		return KYCApplicationResponse{Application: application}, nil
+	}
+}
+
+// @Summary Upload KYC Document
+// @Description Stores a document of a draft application, replacing the previous one of the same type
+// @Accept image/jpeg,image/png,application/pdf
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param type path string true "id_front, id_back, selfie or proof_of_address"
+// @Success 200 {object} KYCApplicationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /kyc/applications/{id}/documents/{type} [put]
+func MakeUploadKYCDocumentEndpoint(s services.KYCService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req UploadKYCDocumentRequest
+		var ok bool = false
+
+		if req, ok = request.(UploadKYCDocumentRequest); !ok {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeUploadKYCDocumentEndpoint", ErrInterfaceWrong)
+			return KYCApplicationResponse{}, ErrInterfaceWrong
+		}
+		application, err := s.UploadDocument(ctx, req.Email, req.ApplicationID, req.DocumentType, req.ContentType, req.Data)
+		if err != nil {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeUploadKYCDocumentEndpoint", err)
+			return KYCApplicationResponse{}, err
+		}
+		return KYCApplicationResponse{Application: application}, nil
+	}
+}
+
+// @Summary Submit KYC Application
+// @Description Sends a draft application with every required document to review
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} KYCApplicationResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /kyc/applications/{id}/submit [post]
+func MakeSubmitKYCApplicationEndpoint(s services.KYCService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req KYCApplicationRequest
+		var ok bool = false
+
+		if req, ok = request.(KYCApplicationRequest); !ok {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeSubmitKYCApplicationEndpoint", ErrInterfaceWrong)
+			return KYCApplicationResponse{}, ErrInterfaceWrong
+		}
+		application, err := s.SubmitApplication(ctx, req.Email, req.ApplicationID)
+		if err != nil {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeSubmitKYCApplicationEndpoint", err)
+			return KYCApplicationResponse{}, err
+		}
+		return KYCApplicationResponse{Application: application}, nil
+	}
+}
+
+// @Summary List KYC Applications
+// @Description Returns the KYC applications in a status, oldest first; support staff only
+// @Produce json
+// @Param status query string false "draft, submitted, approved or rejected"
+// @Success 200 {object} ListKYCApplicationsResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /kyc/applications [get]
+func MakeListKYCApplicationsEndpoint(s services.KYCService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListKYCApplicationsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListKYCApplicationsRequest); !ok {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeListKYCApplicationsEndpoint", ErrInterfaceWrong)
+			return ListKYCApplicationsResponse{}, ErrInterfaceWrong
+		}
+		applications, err := s.ListApplications(ctx, req.Email, req.Status)
+		if err != nil {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeListKYCApplicationsEndpoint", err)
+			return ListKYCApplicationsResponse{}, err
+		}
+		return ListKYCApplicationsResponse{Applications: applications}, nil
+	}
+}
+
+// @Summary Review KYC Application
+// @Description Approves or rejects a submitted application; support staff only
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param review body ReviewKYCApplicationRequest true "Review"
+// @Success 200 {object} KYCApplicationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /kyc/applications/{id}/review [post]
+func MakeReviewKYCApplicationEndpoint(s services.KYCService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ReviewKYCApplicationRequest
+		var ok bool = false
+
+		if req, ok = request.(ReviewKYCApplicationRequest); !ok {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeReviewKYCApplicationEndpoint", ErrInterfaceWrong)
+			return KYCApplicationResponse{}, ErrInterfaceWrong
+		}
+		application, err := s.ReviewApplication(ctx, req.Email, req.ApplicationID, req.Approve, req.Reason)
+		if err != nil {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeReviewKYCApplicationEndpoint", err)
+			return KYCApplicationResponse{}, err
+		}
+		return KYCApplicationResponse{Application: application}, nil
+	}
+}
+
+// @Summary Get KYC Document
+// @Description Returns a document of a KYC application as it was uploaded; support staff only
+// @Produce image/jpeg,image/png,application/pdf
+// @Param id path string true "Application ID"
+// @Param type path string true "Document type"
+// @Success 200 {file} file
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /kyc/applications/{id}/documents/{type} [get]
+func MakeGetKYCDocumentEndpoint(s services.KYCService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetKYCDocumentRequest
+		var ok bool = false
+
+		if req, ok = request.(GetKYCDocumentRequest); !ok {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeGetKYCDocumentEndpoint", ErrInterfaceWrong)
+			return GetKYCDocumentResponse{}, ErrInterfaceWrong
+		}
+		document, data, err := s.GetDocument(ctx, req.Email, req.ApplicationID, req.DocumentType)
+		if err != nil {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeGetKYCDocumentEndpoint", err)
+			return GetKYCDocumentResponse{}, err
+		}
+		return GetKYCDocumentResponse{Document: document, Data: data}, nil
+	}
+}
+
+// @Summary List KYC Events
+// @Description Returns the KYC audit trail of a user, newest first; support staff only
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} ListKYCEventsResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /kyc/users/{id}/events [get]
+func MakeListKYCEventsEndpoint(s services.KYCService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListKYCEventsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListKYCEventsRequest); !ok {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeListKYCEventsEndpoint", ErrInterfaceWrong)
+			return ListKYCEventsResponse{}, ErrInterfaceWrong
+		}
+		events, err := s.ListEvents(ctx, req.Email, req.UserID)
+		if err != nil {
+			logger.Errorln("Layer:kyc_endpoint", "Method:MakeListKYCEventsEndpoint", err)
+			return ListKYCEventsResponse{}, err
+		}
+		return ListKYCEventsResponse{Events: events}, nil
+	}
+}
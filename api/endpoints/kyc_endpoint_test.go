@@ -0,0 +1,134 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreateKYCApplicationEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *kycServiceMock
+		mockResponse    entities.KYCApplication
+		mockError       error
+		configureMock   func(*kycServiceMock, entities.KYCApplication, error)
+		endpointRequest interface{}
+		expectedOutput  KYCApplicationResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreateKYCApplicationEndpoint",
+			mock:         &kycServiceMock{},
+			mockResponse: entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCBasic, Status: entities.KYCDraft},
+			configureMock: func(m *kycServiceMock, mockResponse entities.KYCApplication, mockError error) {
+				m.On("CreateApplication", mock.Anything, "alexer@gmail.com", entities.KYCBasic).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateKYCApplicationRequest{Email: "alexer@gmail.com", Level: entities.KYCBasic},
+			expectedOutput:  KYCApplicationResponse{Application: entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCBasic, Status: entities.KYCDraft}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreateKYCApplicationEndpoint with error Interface type wrong",
+			mock:            &kycServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  KYCApplicationResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreateKYCApplicationEndpoint with error in the service",
+			mock:      &kycServiceMock{},
+			mockError: services.ErrKYCApplicationOpen,
+			configureMock: func(m *kycServiceMock, mockResponse entities.KYCApplication, mockError error) {
+				m.On("CreateApplication", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateKYCApplicationRequest{Email: "alexer@gmail.com", Level: entities.KYCFull},
+			expectedOutput:  KYCApplicationResponse{},
+			expectedError:   services.ErrKYCApplicationOpen,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreateKYCApplicationEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeReviewKYCApplicationEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *kycServiceMock
+		mockResponse    entities.KYCApplication
+		mockError       error
+		configureMock   func(*kycServiceMock, entities.KYCApplication, error)
+		endpointRequest interface{}
+		expectedOutput  KYCApplicationResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeReviewKYCApplicationEndpoint",
+			mock:         &kycServiceMock{},
+			mockResponse: entities.KYCApplication{ID: "a1", Status: entities.KYCRejected, Reason: "Document is blurry"},
+			configureMock: func(m *kycServiceMock, mockResponse entities.KYCApplication, mockError error) {
+				m.On("ReviewApplication", mock.Anything, "support@gmail.com", "a1", false, "Document is blurry").Return(mockResponse, mockError)
+			},
+			endpointRequest: ReviewKYCApplicationRequest{Email: "support@gmail.com", ApplicationID: "a1", Reason: "Document is blurry"},
+			expectedOutput:  KYCApplicationResponse{Application: entities.KYCApplication{ID: "a1", Status: entities.KYCRejected, Reason: "Document is blurry"}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeReviewKYCApplicationEndpoint with error Interface type wrong",
+			mock:            &kycServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  KYCApplicationResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeReviewKYCApplicationEndpoint with error in the service",
+			mock:      &kycServiceMock{},
+			mockError: services.ErrStaffRequired,
+			configureMock: func(m *kycServiceMock, mockResponse entities.KYCApplication, mockError error) {
+				m.On("ReviewApplication", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: ReviewKYCApplicationRequest{Email: "alexer@gmail.com", ApplicationID: "a1", Approve: true},
+			expectedOutput:  KYCApplicationResponse{},
+			expectedError:   services.ErrStaffRequired,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeReviewKYCApplicationEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
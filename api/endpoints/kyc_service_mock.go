@@ -0,0 +1,52 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type kycServiceMock struct {
+	mock.Mock
+}
+
+func (s *kycServiceMock) GetKYC(ctx context.Context, email string) (entities.KYCStatus, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).(entities.KYCStatus), r.Error(1)
+}
+
+func (s *kycServiceMock) CreateApplication(ctx context.Context, email string, level string) (entities.KYCApplication, error) {
+	r := s.Called(ctx, email, level)
+	return r.Get(0).(entities.KYCApplication), r.Error(1)
+}
+
+func (s *kycServiceMock) UploadDocument(ctx context.Context, email string, applicationID string, documentType string, contentType string, data []byte) (entities.KYCApplication, error) {
+	r := s.Called(ctx, email, applicationID, documentType, contentType, data)
+	return r.Get(0).(entities.KYCApplication), r.Error(1)
+}
+
+func (s *kycServiceMock) SubmitApplication(ctx context.Context, email string, applicationID string) (entities.KYCApplication, error) {
+	r := s.Called(ctx, email, applicationID)
+	return r.Get(0).(entities.KYCApplication), r.Error(1)
+}
+
+func (s *kycServiceMock) ListApplications(ctx context.Context, email string, status string) ([]entities.KYCApplication, error) {
+	r := s.Called(ctx, email, status)
+	return r.Get(0).([]entities.KYCApplication), r.Error(1)
+}
+
+func (s *kycServiceMock) ReviewApplication(ctx context.Context, email string, applicationID string, approve bool, reason string) (entities.KYCApplication, error) {
+	r := s.Called(ctx, email, applicationID, approve, reason)
+	return r.Get(0).(entities.KYCApplication), r.Error(1)
+}
+
+func (s *kycServiceMock) GetDocument(ctx context.Context, email string, applicationID string, documentType string) (entities.KYCDocument, []byte, error) {
+	r := s.Called(ctx, email, applicationID, documentType)
+	return r.Get(0).(entities.KYCDocument), r.Get(1).([]byte), r.Error(2)
+}
+
+func (s *kycServiceMock) ListEvents(ctx context.Context, email string, userID string) ([]entities.KYCEvent, error) {
+	r := s.Called(ctx, email, userID)
+	return r.Get(0).([]entities.KYCEvent), r.Error(1)
+}
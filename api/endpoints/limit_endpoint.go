@@ -0,0 +1,49 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// GetLimitsRequest represents the request for the transaction limits of the user
+type GetLimitsRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	Currency string `json:"-"` // Currency of the limits; COP by default
+}
+
+// GetLimitsResponse represents the transaction limits of the user
+// @Description Limits of the KYC level of the user and how much of them is used
+type GetLimitsResponse struct {
+	Limits entities.LimitStatus `json:"limits"`          // Limits and usage
+	Err    string               `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Get Limits
+// @Description Returns the outgoing limits of the user in a currency and their current usage
+// @Produce json
+// @Param currency query string false "Currency"
+// @Success 200 {object} GetLimitsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /limits [get]
+func MakeGetLimitsEndpoint(s services.LimitService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetLimitsRequest
+		var ok bool = false
+
+		if req, ok = request.(GetLimitsRequest); !ok {
+			logger.Errorln("Layer:limit_endpoint", "Method:MakeGetLimitsEndpoint", ErrInterfaceWrong)
+			return GetLimitsResponse{}, ErrInterfaceWrong
+		}
+		limits, err := s.GetLimits(ctx, req.Email, req.Currency)
+		if err != nil {
+			logger.Errorln("Layer:limit_endpoint", "Method:MakeGetLimitsEndpoint", err)
+			return GetLimitsResponse{}, err
+		}
+		return GetLimitsResponse{Limits: limits}, nil
+	}
+}
@@ -0,0 +1,73 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeGetLimitsEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *limitServiceMock
+		mockResponse    entities.LimitStatus
+		mockError       error
+		configureMock   func(*limitServiceMock, entities.LimitStatus, error)
+		endpointRequest interface{}
+		expectedOutput  GetLimitsResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeGetLimitsEndpoint",
+			mock:         &limitServiceMock{},
+			mockResponse: entities.LimitStatus{KYCLevel: entities.KYCBasic, Currency: "USD", Limits: entities.Limits{Daily: 250000}},
+			configureMock: func(m *limitServiceMock, mockResponse entities.LimitStatus, mockError error) {
+				m.On("GetLimits", mock.Anything, "alexer@gmail.com", "USD").Return(mockResponse, mockError)
+			},
+			endpointRequest: GetLimitsRequest{Email: "alexer@gmail.com", Currency: "USD"},
+			expectedOutput:  GetLimitsResponse{Limits: entities.LimitStatus{KYCLevel: entities.KYCBasic, Currency: "USD", Limits: entities.Limits{Daily: 250000}}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeGetLimitsEndpoint with error Interface type wrong",
+			mock:            &limitServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  GetLimitsResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeGetLimitsEndpoint with error in the service",
+			mock:      &limitServiceMock{},
+			mockError: services.ErrInvalidCurrency,
+			configureMock: func(m *limitServiceMock, mockResponse entities.LimitStatus, mockError error) {
+				m.On("GetLimits", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: GetLimitsRequest{Email: "alexer@gmail.com", Currency: "usd"},
+			expectedOutput:  GetLimitsResponse{},
+			expectedError:   services.ErrInvalidCurrency,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeGetLimitsEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
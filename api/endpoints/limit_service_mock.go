@@ -0,0 +1,26 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type limitServiceMock struct {
+	mock.Mock
+}
+
+func (s *limitServiceMock) Reserve(ctx context.Context, user entities.User, currency string, amount int64) (entities.LimitReservation, error) {
+	r := s.Called(ctx, user, currency, amount)
+	return r.Get(0).(entities.LimitReservation), r.Error(1)
+}
+
+func (s *limitServiceMock) Release(ctx context.Context, reservation entities.LimitReservation) {
+	s.Called(ctx, reservation)
+}
+
+func (s *limitServiceMock) GetLimits(ctx context.Context, email string, currency string) (entities.LimitStatus, error) {
+	r := s.Called(ctx, email, currency)
+	return r.Get(0).(entities.LimitStatus), r.Error(1)
+}
@@ -0,0 +1,235 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// InviteMemberRequest represents the invitation of a user to a shared wallet
+type InviteMemberRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet ID
+	// @example "family@gmail.com"
+	MemberEmail string `json:"email"` // Email of the invited user
+	// @example "spender"
+	Role           string           `json:"role"`                      // owner, spender or viewer
+	SpendingLimits map[string]int64 `json:"spending_limits,omitempty"` // Monthly spending limits of a spender by currency, in cents
+}
+
+// UpdateMemberRequest represents the request to change the role of a member
+type UpdateMemberRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet ID
+	MemberID string `json:"-"` // Member ID
+	// @example "viewer"
+	Role           string           `json:"role"`                      // owner, spender or viewer
+	SpendingLimits map[string]int64 `json:"spending_limits,omitempty"` // Monthly spending limits of a spender by currency, in cents
+}
+
+// RemoveMemberRequest represents the request to take a member out of a wallet
+type RemoveMemberRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet ID
+	MemberID string `json:"-"` // Member ID
+}
+
+// MemberResponse represents a member of a shared wallet
+type MemberResponse struct {
+	Member entities.WalletMember `json:"member"`          // Member
+	Err    string                `json:"error,omitempty"` // Error message, if any
+}
+
+// ListMembersRequest represents the request for the members of a wallet
+type ListMembersRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet ID
+}
+
+// ListMembersResponse represents the members of a wallet
+type ListMembersResponse struct {
+	Members []entities.WalletMember `json:"members"`         // Members, invited ones included
+	Err     string                  `json:"error,omitempty"` // Error message, if any
+}
+
+// ListMembershipsRequest represents the request for the shared wallets of the authenticated user
+type ListMembershipsRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListMembershipsResponse represents the invitations and shared wallets of a user
+type ListMembershipsResponse struct {
+	Memberships []entities.WalletMember `json:"memberships"`     // Memberships, invitations included
+	Err         string                  `json:"error,omitempty"` // Error message, if any
+}
+
+// RespondInvitationRequest represents the answer to an invitation to a shared wallet
+type RespondInvitationRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	MemberID string `json:"-"` // Member ID of the invitation
+	Accept   bool   `json:"-"` // Whether the invitation is accepted
+}
+
+// @Summary Invite Wallet Member
+// @Description Invites a user to a wallet the authenticated user manages. The invitation is sent as a notification
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param member body InviteMemberRequest true "Invitation"
+// @Success 201 {object} MemberResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /wallets/{id}/members [post]
+func MakeInviteMemberEndpoint(s services.MemberService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req InviteMemberRequest
+		var ok bool = false
+
+		if req, ok = request.(InviteMemberRequest); !ok {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeInviteMemberEndpoint", ErrInterfaceWrong)
+			return MemberResponse{}, ErrInterfaceWrong
+		}
+		member, err := s.InviteMember(ctx, req.Email, req.WalletID, entities.WalletMember{Email: req.MemberEmail, Role: req.Role, SpendingLimits: req.SpendingLimits})
+		if err != nil {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeInviteMemberEndpoint", err)
+			return MemberResponse{}, err
+		}
+		return MemberResponse{Member: member}, nil
+	}
+}
+
+// @Summary List Wallet Members
+// @Description Returns the members of a wallet, invited ones included, and what spenders spent this month
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {object} ListMembersResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /wallets/{id}/members [get]
+func MakeListMembersEndpoint(s services.MemberService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListMembersRequest
+		var ok bool = false
+
+		if req, ok = request.(ListMembersRequest); !ok {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeListMembersEndpoint", ErrInterfaceWrong)
+			return ListMembersResponse{}, ErrInterfaceWrong
+		}
+		members, err := s.ListMembers(ctx, req.Email, req.WalletID)
+		if err != nil {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeListMembersEndpoint", err)
+			return ListMembersResponse{}, err
+		}
+		return ListMembersResponse{Members: members}, nil
+	}
+}
+
+// @Summary Update Wallet Member
+// @Description Changes the role and spending limits of a member of a wallet the authenticated user manages
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param memberId path string true "Member ID"
+// @Param member body UpdateMemberRequest true "Role"
+// @Success 200 {object} MemberResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /wallets/{id}/members/{memberId} [put]
+func MakeUpdateMemberEndpoint(s services.MemberService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req UpdateMemberRequest
+		var ok bool = false
+
+		if req, ok = request.(UpdateMemberRequest); !ok {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeUpdateMemberEndpoint", ErrInterfaceWrong)
+			return MemberResponse{}, ErrInterfaceWrong
+		}
+		member, err := s.UpdateMember(ctx, req.Email, req.WalletID, req.MemberID, entities.WalletMember{Role: req.Role, SpendingLimits: req.SpendingLimits})
+		if err != nil {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeUpdateMemberEndpoint", err)
+			return MemberResponse{}, err
+		}
+		return MemberResponse{Member: member}, nil
+	}
+}
+
+// @Summary Remove Wallet Member
+// @Description Takes a member out of a wallet the authenticated user manages, or lets the authenticated user leave it
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param memberId path string true "Member ID"
+// @Success 200 {object} MemberResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /wallets/{id}/members/{memberId} [delete]
+func MakeRemoveMemberEndpoint(s services.MemberService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req RemoveMemberRequest
+		var ok bool = false
+
+		if req, ok = request.(RemoveMemberRequest); !ok {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeRemoveMemberEndpoint", ErrInterfaceWrong)
+			return MemberResponse{}, ErrInterfaceWrong
+		}
+		member, err := s.RemoveMember(ctx, req.Email, req.WalletID, req.MemberID)
+		if err != nil {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeRemoveMemberEndpoint", err)
+			return MemberResponse{}, err
+		}
+		return MemberResponse{Member: member}, nil
+	}
+}
+
+// @Summary List Memberships
+// @Description Returns the shared wallets the authenticated user is a member of or invited to
+// @Produce json
+// @Success 200 {object} ListMembershipsResponse
+// @Router /memberships [get]
+func MakeListMembershipsEndpoint(s services.MemberService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListMembershipsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListMembershipsRequest); !ok {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeListMembershipsEndpoint", ErrInterfaceWrong)
+			return ListMembershipsResponse{}, ErrInterfaceWrong
+		}
+		memberships, err := s.ListMemberships(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeListMembershipsEndpoint", err)
+			return ListMembershipsResponse{}, err
+		}
+		return ListMembershipsResponse{Memberships: memberships}, nil
+	}
+}
+
+// @Summary Accept or Decline Invitation
+// @Description Accepts or declines an invitation of the authenticated user to a shared wallet
+// @Produce json
+// @Param id path string true "Member ID"
+// @Success 200 {object} MemberResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /memberships/{id}/accept [post]
+// @Router /memberships/{id}/decline [post]
+func MakeRespondInvitationEndpoint(s services.MemberService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req RespondInvitationRequest
+		var ok bool = false
+
+		if req, ok = request.(RespondInvitationRequest); !ok {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeRespondInvitationEndpoint", ErrInterfaceWrong)
+			return MemberResponse{}, ErrInterfaceWrong
+		}
+		member, err := s.RespondInvitation(ctx, req.Email, req.MemberID, req.Accept)
+		if err != nil {
+			logger.Errorln("Layer:member_endpoint", "Method:MakeRespondInvitationEndpoint", err)
+			return MemberResponse{}, err
+		}
+		return MemberResponse{Member: member}, nil
+	}
+}
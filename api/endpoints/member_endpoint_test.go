@@ -0,0 +1,136 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeInviteMemberEndpoint(t *testing.T) {
+	invited := entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "u2", Email: "kid@gmail.com", Role: entities.MemberSpender, SpendingLimits: map[string]int64{"USD": 5000}, Status: entities.MemberInvited}
+
+	testScenarios := []struct {
+		testName        string
+		mock            *memberServiceMock
+		mockResponse    entities.WalletMember
+		mockError       error
+		configureMock   func(*memberServiceMock, entities.WalletMember, error)
+		endpointRequest interface{}
+		expectedOutput  MemberResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeInviteMemberEndpoint",
+			mock:         &memberServiceMock{},
+			mockResponse: invited,
+			configureMock: func(m *memberServiceMock, mockResponse entities.WalletMember, mockError error) {
+				m.On("InviteMember", mock.Anything, "owner@gmail.com", "w1", entities.WalletMember{Email: "kid@gmail.com", Role: entities.MemberSpender, SpendingLimits: map[string]int64{"USD": 5000}}).Return(mockResponse, mockError)
+			},
+			endpointRequest: InviteMemberRequest{Email: "owner@gmail.com", WalletID: "w1", MemberEmail: "kid@gmail.com", Role: entities.MemberSpender, SpendingLimits: map[string]int64{"USD": 5000}},
+			expectedOutput:  MemberResponse{Member: invited},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeInviteMemberEndpoint with error Interface type wrong",
+			mock:            &memberServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  MemberResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeInviteMemberEndpoint with error in the service",
+			mock:      &memberServiceMock{},
+			mockError: services.ErrWalletPermission,
+			configureMock: func(m *memberServiceMock, mockResponse entities.WalletMember, mockError error) {
+				m.On("InviteMember", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: InviteMemberRequest{Email: "viewer@gmail.com", WalletID: "w1", MemberEmail: "kid@gmail.com", Role: entities.MemberViewer},
+			expectedOutput:  MemberResponse{},
+			expectedError:   services.ErrWalletPermission,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeInviteMemberEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeRespondInvitationEndpoint(t *testing.T) {
+	joined := entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "u2", Role: entities.MemberViewer, Status: entities.MemberActive}
+
+	testScenarios := []struct {
+		testName        string
+		mock            *memberServiceMock
+		mockResponse    entities.WalletMember
+		mockError       error
+		configureMock   func(*memberServiceMock, entities.WalletMember, error)
+		endpointRequest interface{}
+		expectedOutput  MemberResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeRespondInvitationEndpoint",
+			mock:         &memberServiceMock{},
+			mockResponse: joined,
+			configureMock: func(m *memberServiceMock, mockResponse entities.WalletMember, mockError error) {
+				m.On("RespondInvitation", mock.Anything, "kid@gmail.com", "m1", true).Return(mockResponse, mockError)
+			},
+			endpointRequest: RespondInvitationRequest{Email: "kid@gmail.com", MemberID: "m1", Accept: true},
+			expectedOutput:  MemberResponse{Member: joined},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeRespondInvitationEndpoint with error Interface type wrong",
+			mock:            &memberServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  MemberResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeRespondInvitationEndpoint with error in the service",
+			mock:      &memberServiceMock{},
+			mockError: services.ErrInvitationClosed,
+			configureMock: func(m *memberServiceMock, mockResponse entities.WalletMember, mockError error) {
+				m.On("RespondInvitation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: RespondInvitationRequest{Email: "kid@gmail.com", MemberID: "m1"},
+			expectedOutput:  MemberResponse{},
+			expectedError:   services.ErrInvitationClosed,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeRespondInvitationEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
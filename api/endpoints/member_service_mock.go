@@ -0,0 +1,56 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type memberServiceMock struct {
+	mock.Mock
+}
+
+func (s *memberServiceMock) InviteMember(ctx context.Context, email string, walletID string, invitation entities.WalletMember) (entities.WalletMember, error) {
+	r := s.Called(ctx, email, walletID, invitation)
+	return r.Get(0).(entities.WalletMember), r.Error(1)
+}
+
+func (s *memberServiceMock) ListMembers(ctx context.Context, email string, walletID string) ([]entities.WalletMember, error) {
+	r := s.Called(ctx, email, walletID)
+	return r.Get(0).([]entities.WalletMember), r.Error(1)
+}
+
+func (s *memberServiceMock) UpdateMember(ctx context.Context, email string, walletID string, memberID string, update entities.WalletMember) (entities.WalletMember, error) {
+	r := s.Called(ctx, email, walletID, memberID, update)
+	return r.Get(0).(entities.WalletMember), r.Error(1)
+}
+
+func (s *memberServiceMock) RemoveMember(ctx context.Context, email string, walletID string, memberID string) (entities.WalletMember, error) {
+	r := s.Called(ctx, email, walletID, memberID)
+	return r.Get(0).(entities.WalletMember), r.Error(1)
+}
+
+func (s *memberServiceMock) ListMemberships(ctx context.Context, email string) ([]entities.WalletMember, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.WalletMember), r.Error(1)
+}
+
+func (s *memberServiceMock) RespondInvitation(ctx context.Context, email string, memberID string, accept bool) (entities.WalletMember, error) {
+	r := s.Called(ctx, email, memberID, accept)
+	return r.Get(0).(entities.WalletMember), r.Error(1)
+}
+
+func (s *memberServiceMock) Member(ctx context.Context, walletID string, userID string) (entities.WalletMember, error) {
+	r := s.Called(ctx, walletID, userID)
+	return r.Get(0).(entities.WalletMember), r.Error(1)
+}
+
+func (s *memberServiceMock) ReserveSpending(ctx context.Context, member entities.WalletMember, currency string, amount int64) (entities.LimitReservation, error) {
+	r := s.Called(ctx, member, currency, amount)
+	return r.Get(0).(entities.LimitReservation), r.Error(1)
+}
+
+func (s *memberServiceMock) ReleaseSpending(ctx context.Context, reservation entities.LimitReservation) {
+	s.Called(ctx, reservation)
+}
@@ -0,0 +1,224 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateMerchantRequest represents the request to open a merchant account
+type CreateMerchantRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example "Panaderia La Esquina"
+	Name string `json:"name"` // Name shown to payers, up to 25 characters
+	// @example "Bogota"
+	City string `json:"city"` // City, up to 15 characters
+	// @example "5462"
+	CategoryCode string `json:"category_code"` // ISO 18245 merchant category code
+}
+
+// MerchantRequest represents the request for the merchant account of the user
+type MerchantRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// MerchantResponse represents a merchant account
+type MerchantResponse struct {
+	Merchant entities.Merchant `json:"merchant"`        // Merchant account
+	Err      string            `json:"error,omitempty"` // Error message, if any
+}
+
+// CreateQRCodeRequest represents the request to generate a payment QR code
+// @Description Without an amount the code is static and the payer enters the amount; with one it is dynamic and paid once
+type CreateQRCodeRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example 1550000
+	Amount int64 `json:"amount,omitempty"` // Amount in cents, for dynamic codes
+	// @example "COP"
+	Currency string `json:"currency,omitempty"` // Currency, the wallet's default when empty
+	// @example "INV-2041"
+	Reference string `json:"reference,omitempty"` // Bill number of the merchant, up to 25 characters
+}
+
+// QRCodeResponse represents a generated payment QR code
+type QRCodeResponse struct {
+	QRCode entities.MerchantQR `json:"qr_code"`         // QR code and the payload to render
+	Err    string              `json:"error,omitempty"` // Error message, if any
+}
+
+// PayQRCodeRequest represents the request to pay a merchant QR code
+type PayQRCodeRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example "00020101021126..."
+	Payload string `json:"payload"` // Text read from the QR code
+	// @example 1550000
+	Amount int64 `json:"amount,omitempty"` // Amount in cents, for codes without one
+	// @example "Bread"
+	Memo string `json:"memo,omitempty"` // Optional note
+}
+
+// PayQRCodeResponse represents the response when a QR code is paid
+type PayQRCodeResponse struct {
+	Payment entities.MerchantPayment `json:"payment"`         // Posted payment
+	Err     string                   `json:"error,omitempty"` // Error message, if any
+}
+
+// SettlementReportRequest represents the request for the settlement of the merchant of the user
+type SettlementReportRequest struct {
+	Email string    `json:"-"` // Email of the authenticated user
+	From  time.Time `json:"-"` // Start of the period, inclusive
+	To    time.Time `json:"-"` // End of the period, exclusive
+}
+
+// SettlementReportResponse represents the settlement of a merchant
+type SettlementReportResponse struct {
+	Report entities.SettlementReport `json:"report"`          // Settlement by day and in total
+	Err    string                    `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Create Merchant
+// @Description Opens a merchant account for the authenticated user, who needs full KYC; payments reach their wallet
+// @Accept json
+// @Produce json
+// @Param merchant body CreateMerchantRequest true "Merchant"
+// @Success 201 {object} MerchantResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /merchants [post]
+func MakeCreateMerchantEndpoint(s services.MerchantService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateMerchantRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateMerchantRequest); !ok {
+			logger.Errorln("Layer:merchant_endpoint", "Method:MakeCreateMerchantEndpoint", ErrInterfaceWrong)
+			return MerchantResponse{}, ErrInterfaceWrong
+		}
+		merchant, err := s.CreateMerchant(ctx, req.Email, entities.Merchant{
+			Name:         req.Name,
+			City:         req.City,
+			CategoryCode: req.CategoryCode,
+		})
+		if err != nil {
+			logger.Errorln("Layer:merchant_endpoint", "Method:MakeCreateMerchantEndpoint", err)
+			return MerchantResponse{}, err
+		}
+		return MerchantResponse{Merchant: merchant}, nil
+	}
+}
+
+// @Summary Get Merchant
+// @Description Returns the merchant account of the authenticated user
+// @Produce json
+// @Success 200 {object} MerchantResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /merchants/me [get]
+func MakeGetMerchantEndpoint(s services.MerchantService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req MerchantRequest
+		var ok bool = false
+
+		if req, ok = request.(MerchantRequest); !ok {
+			logger.Errorln("Layer:merchant_endpoint", "Method:MakeGetMerchantEndpoint", ErrInterfaceWrong)
+			return MerchantResponse{}, ErrInterfaceWrong
+		}
+		merchant, err := s.GetMerchant(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:merchant_endpoint", "Method:MakeGetMerchantEndpoint", err)
+			return MerchantResponse{}, err
+		}
+		return MerchantResponse{Merchant: merchant}, nil
+	}
+}
+
+// @Summary Create QR Code
+// @Description Generates an EMVCo merchant-presented payment QR code, static or with an amount
+// @Accept json
+// @Produce json
+// @Param qr_code body CreateQRCodeRequest true "QR code"
+// @Success 201 {object} QRCodeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /merchants/me/qr-codes [post]
+func MakeCreateQRCodeEndpoint(s services.MerchantService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateQRCodeRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateQRCodeRequest); !ok {
+			logger.Errorln("Layer:merchant_endpoint", "Method:MakeCreateQRCodeEndpoint", ErrInterfaceWrong)
+			return QRCodeResponse{}, ErrInterfaceWrong
+		}
+		qr, err := s.CreateQRCode(ctx, req.Email, entities.MerchantQR{
+			Amount:    req.Amount,
+			Currency:  req.Currency,
+			Reference: req.Reference,
+		})
+		if err != nil {
+			logger.Errorln("Layer:merchant_endpoint", "Method:MakeCreateQRCodeEndpoint", err)
+			return QRCodeResponse{}, err
+		}
+		return QRCodeResponse{QRCode: qr}, nil
+	}
+}
+
+// @Summary Pay QR Code
+// @Description Pays a merchant QR code from the wallet of the authenticated user
+// @Accept json
+// @Produce json
+// @Param payment body PayQRCodeRequest true "Payment"
+// @Success 201 {object} PayQRCodeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /qr-payments [post]
+func MakePayQRCodeEndpoint(s services.MerchantService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req PayQRCodeRequest
+		var ok bool = false
+
+		if req, ok = request.(PayQRCodeRequest); !ok {
+			logger.Errorln("Layer:merchant_endpoint", "Method:MakePayQRCodeEndpoint", ErrInterfaceWrong)
+			return PayQRCodeResponse{}, ErrInterfaceWrong
+		}
+		payment, err := s.PayQRCode(ctx, req.Email, req.Payload, req.Amount, req.Memo)
+		if err != nil {
+			logger.Errorln("Layer:merchant_endpoint", "Method:MakePayQRCodeEndpoint", err)
+			return PayQRCodeResponse{}, err
+		}
+		return PayQRCodeResponse{Payment: payment}, nil
+	}
+}
+
+// @Summary Get Settlement Report
+// @Description Returns the QR payments the merchant of the authenticated user received by day, with fees and net settled, for the last 30 days by default
+// @Produce json
+// @Param from query string false "Start of the period, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "End of the period, exclusive"
+// @Success 200 {object} SettlementReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /merchants/me/settlements [get]
+func MakeGetSettlementReportEndpoint(s services.MerchantService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req SettlementReportRequest
+		var ok bool = false
+
+		if req, ok = request.(SettlementReportRequest); !ok {
+			logger.Errorln("Layer:merchant_endpoint", "Method:MakeGetSettlementReportEndpoint", ErrInterfaceWrong)
+			return SettlementReportResponse{}, ErrInterfaceWrong
+		}
+		report, err := s.GetSettlementReport(ctx, req.Email, req.From, req.To)
+		if err != nil {
+			logger.Errorln("Layer:merchant_endpoint", "Method:MakeGetSettlementReportEndpoint", err)
+			return SettlementReportResponse{}, err
+		}
+		return SettlementReportResponse{Report: report}, nil
+	}
+}
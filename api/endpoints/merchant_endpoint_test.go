@@ -0,0 +1,134 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreateQRCodeEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *merchantServiceMock
+		mockResponse    entities.MerchantQR
+		mockError       error
+		configureMock   func(*merchantServiceMock, entities.MerchantQR, error)
+		endpointRequest interface{}
+		expectedOutput  QRCodeResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreateQRCodeEndpoint",
+			mock:         &merchantServiceMock{},
+			mockResponse: entities.MerchantQR{ID: "q1", Kind: entities.QRDynamic, Amount: 1550000, Currency: "COP", Payload: "000201"},
+			configureMock: func(m *merchantServiceMock, mockResponse entities.MerchantQR, mockError error) {
+				m.On("CreateQRCode", mock.Anything, "alexer@gmail.com", entities.MerchantQR{Amount: 1550000, Currency: "COP"}).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateQRCodeRequest{Email: "alexer@gmail.com", Amount: 1550000, Currency: "COP"},
+			expectedOutput:  QRCodeResponse{QRCode: entities.MerchantQR{ID: "q1", Kind: entities.QRDynamic, Amount: 1550000, Currency: "COP", Payload: "000201"}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreateQRCodeEndpoint with error Interface type wrong",
+			mock:            &merchantServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  QRCodeResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreateQRCodeEndpoint with error in the service",
+			mock:      &merchantServiceMock{},
+			mockError: services.ErrMerchantNotFound,
+			configureMock: func(m *merchantServiceMock, mockResponse entities.MerchantQR, mockError error) {
+				m.On("CreateQRCode", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateQRCodeRequest{Email: "alexer@gmail.com"},
+			expectedOutput:  QRCodeResponse{},
+			expectedError:   services.ErrMerchantNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreateQRCodeEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakePayQRCodeEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *merchantServiceMock
+		mockResponse    entities.MerchantPayment
+		mockError       error
+		configureMock   func(*merchantServiceMock, entities.MerchantPayment, error)
+		endpointRequest interface{}
+		expectedOutput  PayQRCodeResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakePayQRCodeEndpoint",
+			mock:         &merchantServiceMock{},
+			mockResponse: entities.MerchantPayment{ID: "e1", MerchantID: "m1", Amount: 2500, Currency: "COP", Status: entities.StatusCompleted},
+			configureMock: func(m *merchantServiceMock, mockResponse entities.MerchantPayment, mockError error) {
+				m.On("PayQRCode", mock.Anything, "alexer@gmail.com", "000201", int64(2500), "Bread").Return(mockResponse, mockError)
+			},
+			endpointRequest: PayQRCodeRequest{Email: "alexer@gmail.com", Payload: "000201", Amount: 2500, Memo: "Bread"},
+			expectedOutput:  PayQRCodeResponse{Payment: entities.MerchantPayment{ID: "e1", MerchantID: "m1", Amount: 2500, Currency: "COP", Status: entities.StatusCompleted}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakePayQRCodeEndpoint with error Interface type wrong",
+			mock:            &merchantServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  PayQRCodeResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakePayQRCodeEndpoint with error in the service",
+			mock:      &merchantServiceMock{},
+			mockError: services.ErrQRCodePaid,
+			configureMock: func(m *merchantServiceMock, mockResponse entities.MerchantPayment, mockError error) {
+				m.On("PayQRCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: PayQRCodeRequest{Email: "alexer@gmail.com", Payload: "000201"},
+			expectedOutput:  PayQRCodeResponse{},
+			expectedError:   services.ErrQRCodePaid,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakePayQRCodeEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
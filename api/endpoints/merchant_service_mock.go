@@ -0,0 +1,38 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type merchantServiceMock struct {
+	mock.Mock
+}
+
+func (s *merchantServiceMock) CreateMerchant(ctx context.Context, email string, merchant entities.Merchant) (entities.Merchant, error) {
+	r := s.Called(ctx, email, merchant)
+	return r.Get(0).(entities.Merchant), r.Error(1)
+}
+
+func (s *merchantServiceMock) GetMerchant(ctx context.Context, email string) (entities.Merchant, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).(entities.Merchant), r.Error(1)
+}
+
+func (s *merchantServiceMock) CreateQRCode(ctx context.Context, email string, qr entities.MerchantQR) (entities.MerchantQR, error) {
+	r := s.Called(ctx, email, qr)
+	return r.Get(0).(entities.MerchantQR), r.Error(1)
+}
+
+func (s *merchantServiceMock) PayQRCode(ctx context.Context, email string, payload string, amount int64, memo string) (entities.MerchantPayment, error) {
+	r := s.Called(ctx, email, payload, amount, memo)
+	return r.Get(0).(entities.MerchantPayment), r.Error(1)
+}
+
+func (s *merchantServiceMock) GetSettlementReport(ctx context.Context, email string, from time.Time, to time.Time) (entities.SettlementReport, error) {
+	r := s.Called(ctx, email, from, to)
+	return r.Get(0).(entities.SettlementReport), r.Error(1)
+}
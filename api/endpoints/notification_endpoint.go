@@ -0,0 +1,84 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// ListNotificationsRequest represents the request to list the notifications of the user
+type ListNotificationsRequest struct {
+	Email      string `json:"-"` // Email of the authenticated user
+	UnreadOnly bool   `json:"-"` // Only the notifications not read yet
+}
+
+// ListNotificationsResponse represents the notifications of the user
+// @Description Latest notifications, newest first
+type ListNotificationsResponse struct {
+	Notifications []entities.Notification `json:"notifications"`   // Notifications
+	Err           string                  `json:"error,omitempty"` // Error message, if any
+}
+
+// MarkNotificationReadRequest represents the request to mark a notification as read
+type MarkNotificationReadRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Notification ID
+}
+
+// NotificationResponse represents a notification
+type NotificationResponse struct {
+	Notification entities.Notification `json:"notification"`    // Notification
+	Err          string                `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary List Notifications
+// @Description Lists the latest notifications of the user
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Success 200 {object} ListNotificationsResponse
+// @Router /notifications [get]
+func MakeListNotificationsEndpoint(s services.NotificationService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListNotificationsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListNotificationsRequest); !ok {
+			logger.Errorln("Layer:notification_endpoint", "Method:MakeListNotificationsEndpoint", ErrInterfaceWrong)
+			return ListNotificationsResponse{}, ErrInterfaceWrong
+		}
+		notifications, err := s.ListNotifications(ctx, req.Email, req.UnreadOnly)
+		if err != nil {
+			logger.Errorln("Layer:notification_endpoint", "Method:MakeListNotificationsEndpoint", err)
+			return ListNotificationsResponse{}, err
+		}
+		return ListNotificationsResponse{Notifications: notifications}, nil
+	}
+}
+
+// @Summary Mark Notification Read
+// @Description Marks a notification as read
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} NotificationResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /notifications/{id}/read [post]
+func MakeMarkNotificationReadEndpoint(s services.NotificationService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req MarkNotificationReadRequest
+		var ok bool = false
+
+		if req, ok = request.(MarkNotificationReadRequest); !ok {
+			logger.Errorln("Layer:notification_endpoint", "Method:MakeMarkNotificationReadEndpoint", ErrInterfaceWrong)
+			return NotificationResponse{}, ErrInterfaceWrong
+		}
+		notification, err := s.MarkRead(ctx, req.Email, req.ID)
+		if err != nil {
+			logger.Errorln("Layer:notification_endpoint", "Method:MakeMarkNotificationReadEndpoint", err)
+			return NotificationResponse{}, err
+		}
+		return NotificationResponse{Notification: notification}, nil
+	}
+}
@@ -0,0 +1,27 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type notificationServiceMock struct {
+	mock.Mock
+}
+
+func (s *notificationServiceMock) Notify(ctx context.Context, notification entities.Notification) error {
+	r := s.Called(ctx, notification)
+	return r.Error(0)
+}
+
+func (s *notificationServiceMock) ListNotifications(ctx context.Context, email string, unreadOnly bool) ([]entities.Notification, error) {
+	r := s.Called(ctx, email, unreadOnly)
+	return r.Get(0).([]entities.Notification), r.Error(1)
+}
+
+func (s *notificationServiceMock) MarkRead(ctx context.Context, email string, id string) (entities.Notification, error) {
+	r := s.Called(ctx, email, id)
+	return r.Get(0).(entities.Notification), r.Error(1)
+}
@@ -0,0 +1,176 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// CreatePaymentRequestRequest represents the request to ask another user for money
+// @Description Exactly one of payer email, phone or DNI identifies the payer
+type CreatePaymentRequestRequest struct {
+	Email string `json:"-"` // Email of the authenticated requester
+	// @example "friend@gmail.com"
+	PayerEmail string `json:"payer_email,omitempty"` // Payer's email
+	// @example 3017942380
+	PayerPhone int `json:"payer_phone,omitempty"` // Payer's phone number
+	// @example 1002842747
+	PayerDNI int `json:"payer_dni,omitempty"` // Payer's DNI
+	// @example 45000
+	Amount int64 `json:"amount"` // Amount in cents
+	// @example "COP"
+	Currency string `json:"currency,omitempty"` // Currency, COP when empty
+	// @example "Movie tickets"
+	Memo string `json:"memo,omitempty"` // Optional note for the payer
+}
+
+// PaymentRequestResponse represents a payment request
+// @Description Response with a payment request
+type PaymentRequestResponse struct {
+	PaymentRequest entities.PaymentRequest `json:"payment_request"` // Payment request
+	Err            string                  `json:"error,omitempty"` // Error message, if any
+}
+
+// ListPaymentRequestsRequest represents the request to list payment requests
+type ListPaymentRequestsRequest struct {
+	Email     string `json:"-"` // Email of the authenticated user
+	Direction string `json:"-"` // incoming or outgoing
+	Status    string `json:"-"` // Optional status filter
+}
+
+// ListPaymentRequestsResponse represents a list of payment requests
+// @Description Response with the payment requests, newest first
+type ListPaymentRequestsResponse struct {
+	PaymentRequests []entities.PaymentRequest `json:"payment_requests"` // Payment requests
+	Err             string                    `json:"error,omitempty"`  // Error message, if any
+}
+
+// AnswerPaymentRequestRequest represents accepting, declining or cancelling a request
+type AnswerPaymentRequestRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Payment request ID
+}
+
+// Resource returns the payment request being answered.
+func (r AnswerPaymentRequestRequest) Resource() string {
+	return "payment-requests/" + r.ID
+}
+
+// @Summary Create Payment Request
+// @Description Asks another user to pay the authenticated user
+// @Accept json
+// @Produce json
+// @Param request body CreatePaymentRequestRequest true "Payment request"
+// @Success 201 {object} PaymentRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /payment-requests [post]
+func MakeCreatePaymentRequestEndpoint(s services.PaymentRequestService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreatePaymentRequestRequest
+		var ok bool = false
+
+		if req, ok = request.(CreatePaymentRequestRequest); !ok {
+			logger.Errorln("Layer:payment_request_endpoint", "Method:MakeCreatePaymentRequestEndpoint", ErrInterfaceWrong)
+			return PaymentRequestResponse{}, ErrInterfaceWrong
+		}
+		paymentRequest := entities.PaymentRequest{
+			PayerEmail: req.PayerEmail,
+			PayerPhone: req.PayerPhone,
+			PayerDNI:   req.PayerDNI,
+			Amount:     req.Amount,
+			Currency:   req.Currency,
+			Memo:       req.Memo,
+		}
+		paymentRequest, err = s.CreatePaymentRequest(ctx, req.Email, paymentRequest)
+		if err != nil {
+			logger.Errorln("Layer:payment_request_endpoint", "Method:MakeCreatePaymentRequestEndpoint", err)
+			return PaymentRequestResponse{}, err
+		}
+		return PaymentRequestResponse{PaymentRequest: paymentRequest}, nil
+	}
+}
+
+// @Summary List Payment Requests
+// @Description Lists the payment requests received or sent by the authenticated user
+// @Produce json
+// @Param direction query string true "incoming or outgoing"
+// @Param status query string false "pending, held, accepted, declined, cancelled or expired"
+// @Success 200 {object} ListPaymentRequestsResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /payment-requests [get]
+func MakeListPaymentRequestsEndpoint(s services.PaymentRequestService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListPaymentRequestsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListPaymentRequestsRequest); !ok {
+			logger.Errorln("Layer:payment_request_endpoint", "Method:MakeListPaymentRequestsEndpoint", ErrInterfaceWrong)
+			return ListPaymentRequestsResponse{}, ErrInterfaceWrong
+		}
+		paymentRequests, err := s.ListPaymentRequests(ctx, req.Email, req.Direction, req.Status)
+		if err != nil {
+			logger.Errorln("Layer:payment_request_endpoint", "Method:MakeListPaymentRequestsEndpoint", err)
+			return ListPaymentRequestsResponse{}, err
+		}
+		return ListPaymentRequestsResponse{PaymentRequests: paymentRequests}, nil
+	}
+}
+
+// @Summary Accept Payment Request
+// @Description Pays a pending request addressed to the authenticated user
+// @Produce json
+// @Param id path string true "Payment request ID"
+// @Success 200 {object} PaymentRequestResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /payment-requests/{id}/accept [post]
+func MakeAcceptPaymentRequestEndpoint(s services.PaymentRequestService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return makeAnswerPaymentRequestEndpoint("MakeAcceptPaymentRequestEndpoint", s.AcceptPaymentRequest, logger)
+}
+
+// @Summary Decline Payment Request
+// @Description Refuses a pending request addressed to the authenticated user
+// @Produce json
+// @Param id path string true "Payment request ID"
+// @Success 200 {object} PaymentRequestResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /payment-requests/{id}/decline [post]
+func MakeDeclinePaymentRequestEndpoint(s services.PaymentRequestService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return makeAnswerPaymentRequestEndpoint("MakeDeclinePaymentRequestEndpoint", s.DeclinePaymentRequest, logger)
+}
+
+// @Summary Cancel Payment Request
+// @Description Withdraws a pending request sent by the authenticated user
+// @Produce json
+// @Param id path string true "Payment request ID"
+// @Success 200 {object} PaymentRequestResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /payment-requests/{id}/cancel [post]
+func MakeCancelPaymentRequestEndpoint(s services.PaymentRequestService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return makeAnswerPaymentRequestEndpoint("MakeCancelPaymentRequestEndpoint", s.CancelPaymentRequest, logger)
+}
+
+func makeAnswerPaymentRequestEndpoint(method string, answer func(ctx context.Context, email string, id string) (entities.PaymentRequest, error), logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req AnswerPaymentRequestRequest
+		var ok bool = false
+
+		if req, ok = request.(AnswerPaymentRequestRequest); !ok {
+			logger.Errorln("Layer:payment_request_endpoint", "Method:"+method, ErrInterfaceWrong)
+			return PaymentRequestResponse{}, ErrInterfaceWrong
+		}
+		paymentRequest, err := answer(ctx, req.Email, req.ID)
+		if err != nil {
+			logger.Errorln("Layer:payment_request_endpoint", "Method:"+method, err)
+			return PaymentRequestResponse{}, err
+		}
+		return PaymentRequestResponse{PaymentRequest: paymentRequest}, nil
+	}
+}
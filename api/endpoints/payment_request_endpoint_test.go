@@ -0,0 +1,134 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreatePaymentRequestEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *paymentRequestServiceMock
+		mockResponse    entities.PaymentRequest
+		mockError       error
+		configureMock   func(*paymentRequestServiceMock, entities.PaymentRequest, error)
+		endpointRequest interface{}
+		expectedOutput  PaymentRequestResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreatePaymentRequestEndpoint",
+			mock:         &paymentRequestServiceMock{},
+			mockResponse: entities.PaymentRequest{ID: "r1", Status: entities.RequestPending},
+			configureMock: func(m *paymentRequestServiceMock, mockResponse entities.PaymentRequest, mockError error) {
+				m.On("CreatePaymentRequest", mock.Anything, "alexer@gmail.com", entities.PaymentRequest{PayerEmail: "friend@gmail.com", Amount: 45000, Memo: "Movie tickets"}).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreatePaymentRequestRequest{Email: "alexer@gmail.com", PayerEmail: "friend@gmail.com", Amount: 45000, Memo: "Movie tickets"},
+			expectedOutput:  PaymentRequestResponse{PaymentRequest: entities.PaymentRequest{ID: "r1", Status: entities.RequestPending}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreatePaymentRequestEndpoint with error Interface type wrong",
+			mock:            &paymentRequestServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  PaymentRequestResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreatePaymentRequestEndpoint with error in the service",
+			mock:      &paymentRequestServiceMock{},
+			mockError: services.ErrSelfRequest,
+			configureMock: func(m *paymentRequestServiceMock, mockResponse entities.PaymentRequest, mockError error) {
+				m.On("CreatePaymentRequest", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreatePaymentRequestRequest{Email: "alexer@gmail.com", PayerEmail: "alexer@gmail.com", Amount: 45000},
+			expectedOutput:  PaymentRequestResponse{},
+			expectedError:   services.ErrSelfRequest,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreatePaymentRequestEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeAcceptPaymentRequestEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *paymentRequestServiceMock
+		mockResponse    entities.PaymentRequest
+		mockError       error
+		configureMock   func(*paymentRequestServiceMock, entities.PaymentRequest, error)
+		endpointRequest interface{}
+		expectedOutput  PaymentRequestResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeAcceptPaymentRequestEndpoint",
+			mock:         &paymentRequestServiceMock{},
+			mockResponse: entities.PaymentRequest{ID: "r1", Status: entities.RequestAccepted, TransferID: "t1"},
+			configureMock: func(m *paymentRequestServiceMock, mockResponse entities.PaymentRequest, mockError error) {
+				m.On("AcceptPaymentRequest", mock.Anything, "friend@gmail.com", "r1").Return(mockResponse, mockError)
+			},
+			endpointRequest: AnswerPaymentRequestRequest{Email: "friend@gmail.com", ID: "r1"},
+			expectedOutput:  PaymentRequestResponse{PaymentRequest: entities.PaymentRequest{ID: "r1", Status: entities.RequestAccepted, TransferID: "t1"}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeAcceptPaymentRequestEndpoint with error Interface type wrong",
+			mock:            &paymentRequestServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  PaymentRequestResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeAcceptPaymentRequestEndpoint with error in the service",
+			mock:      &paymentRequestServiceMock{},
+			mockError: services.ErrPaymentRequestExpired,
+			configureMock: func(m *paymentRequestServiceMock, mockResponse entities.PaymentRequest, mockError error) {
+				m.On("AcceptPaymentRequest", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: AnswerPaymentRequestRequest{Email: "friend@gmail.com", ID: "r1"},
+			expectedOutput:  PaymentRequestResponse{},
+			expectedError:   services.ErrPaymentRequestExpired,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeAcceptPaymentRequestEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
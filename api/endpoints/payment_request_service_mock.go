@@ -0,0 +1,37 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type paymentRequestServiceMock struct {
+	mock.Mock
+}
+
+func (s *paymentRequestServiceMock) CreatePaymentRequest(ctx context.Context, email string, request entities.PaymentRequest) (entities.PaymentRequest, error) {
+	r := s.Called(ctx, email, request)
+	return r.Get(0).(entities.PaymentRequest), r.Error(1)
+}
+
+func (s *paymentRequestServiceMock) ListPaymentRequests(ctx context.Context, email string, direction string, status string) ([]entities.PaymentRequest, error) {
+	r := s.Called(ctx, email, direction, status)
+	return r.Get(0).([]entities.PaymentRequest), r.Error(1)
+}
+
+func (s *paymentRequestServiceMock) AcceptPaymentRequest(ctx context.Context, email string, id string) (entities.PaymentRequest, error) {
+	r := s.Called(ctx, email, id)
+	return r.Get(0).(entities.PaymentRequest), r.Error(1)
+}
+
+func (s *paymentRequestServiceMock) DeclinePaymentRequest(ctx context.Context, email string, id string) (entities.PaymentRequest, error) {
+	r := s.Called(ctx, email, id)
+	return r.Get(0).(entities.PaymentRequest), r.Error(1)
+}
+
+func (s *paymentRequestServiceMock) CancelPaymentRequest(ctx context.Context, email string, id string) (entities.PaymentRequest, error) {
+	r := s.Called(ctx, email, id)
+	return r.Get(0).(entities.PaymentRequest), r.Error(1)
+}
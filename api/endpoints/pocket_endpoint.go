@@ -0,0 +1,271 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// PocketRequest represents the request to create or update a pocket
+// @Description Round-up and weekly saving are turned off when zero
+type PocketRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet that holds the pocket
+	PocketID string `json:"-"` // Pocket to update
+	// @example "Trip to Cartagena"
+	Name string `json:"name"` // Name of the goal
+	// @example 150000000
+	TargetAmount int64 `json:"target_amount"` // Target amount in cents
+	// @example "2025-12-01T00:00:00Z"
+	TargetDate *time.Time `json:"target_date,omitempty"` // Optional date to reach the target
+	// @example "COP"
+	Currency string `json:"currency,omitempty"` // Currency, the wallet currency when empty; ignored on update
+	// @example 100000
+	RoundUpTo int64 `json:"round_up_to,omitempty"` // Round outgoing transfers up to a multiple of this amount in cents
+	// @example 5000000
+	WeeklyAmount int64 `json:"weekly_amount,omitempty"` // Amount in cents saved every week
+}
+
+// PocketResponse represents a pocket
+// @Description Response with a pocket and its progress towards the target
+type PocketResponse struct {
+	Pocket entities.Pocket `json:"pocket"`          // Pocket
+	Err    string          `json:"error,omitempty"` // Error message, if any
+}
+
+// ListPocketsRequest represents the request to list the pockets of a wallet
+type ListPocketsRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet that holds the pockets
+}
+
+// ListPocketsResponse represents the pockets of a wallet
+// @Description Response with the active pockets of a wallet, oldest first
+type ListPocketsResponse struct {
+	Pockets []entities.Pocket `json:"pockets"`         // Pockets
+	Err     string            `json:"error,omitempty"` // Error message, if any
+}
+
+// GetPocketRequest represents the request to get or close a pocket
+type GetPocketRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet that holds the pocket
+	PocketID string `json:"-"` // Pocket ID
+}
+
+// MovePocketRequest represents a move between the wallet balance and a pocket
+// @Description Moves are instant and free
+type MovePocketRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet that holds the pocket
+	PocketID string `json:"-"` // Pocket ID
+	// @example 2000000
+	Amount int64 `json:"amount"` // Amount in cents
+}
+
+// Resource returns the pocket money is moved to or from.
+func (r MovePocketRequest) Resource() string {
+	return "wallets/" + r.WalletID + "/pockets/" + r.PocketID
+}
+
+// @Summary Create Pocket
+// @Description Opens a savings pocket inside a wallet
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param pocket body PocketRequest true "Pocket"
+// @Success 201 {object} PocketResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /wallets/{id}/pockets [post]
+func MakeCreatePocketEndpoint(s services.PocketService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req PocketRequest
+		var ok bool = false
+
+		if req, ok = request.(PocketRequest); !ok {
+			logger.Errorln("Layer:pocket_endpoint", "Method:MakeCreatePocketEndpoint", ErrInterfaceWrong)
+			return PocketResponse{}, ErrInterfaceWrong
+		}
+		pocket, err := s.CreatePocket(ctx, req.Email, req.WalletID, req.pocket())
+		if err != nil {
+			logger.Errorln("Layer:pocket_endpoint", "Method:MakeCreatePocketEndpoint", err)
+			return PocketResponse{}, err
+		}
+		return PocketResponse{Pocket: pocket}, nil
+	}
+}
+
+// @Summary List Pockets
+// @Description Lists the active pockets of a wallet with their progress
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {object} ListPocketsResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /wallets/{id}/pockets [get]
+func MakeListPocketsEndpoint(s services.PocketService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListPocketsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListPocketsRequest); !ok {
+			logger.Errorln("Layer:pocket_endpoint", "Method:MakeListPocketsEndpoint", ErrInterfaceWrong)
+			return ListPocketsResponse{}, ErrInterfaceWrong
+		}
+		pockets, err := s.ListPockets(ctx, req.Email, req.WalletID)
+		if err != nil {
+			logger.Errorln("Layer:pocket_endpoint", "Method:MakeListPocketsEndpoint", err)
+			return ListPocketsResponse{}, err
+		}
+		return ListPocketsResponse{Pockets: pockets}, nil
+	}
+}
+
+// @Summary Get Pocket
+// @Description Gets a pocket with its progress towards the target
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param pocketId path string true "Pocket ID"
+// @Success 200 {object} PocketResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /wallets/{id}/pockets/{pocketId} [get]
+func MakeGetPocketEndpoint(s services.PocketService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetPocketRequest
+		var ok bool = false
+
+		if req, ok = request.(GetPocketRequest); !ok {
+			logger.Errorln("Layer:pocket_endpoint", "Method:MakeGetPocketEndpoint", ErrInterfaceWrong)
+			return PocketResponse{}, ErrInterfaceWrong
+		}
+		pocket, err := s.GetPocket(ctx, req.Email, req.WalletID, req.PocketID)
+		if err != nil {
+			logger.Errorln("Layer:pocket_endpoint", "Method:MakeGetPocketEndpoint", err)
+			return PocketResponse{}, err
+		}
+		return PocketResponse{Pocket: pocket}, nil
+	}
+}
+
+// @Summary Update Pocket
+// @Description Changes the name, target and auto-save rules of a pocket
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param pocketId path string true "Pocket ID"
+// @Param pocket body PocketRequest true "Pocket"
+// @Success 200 {object} PocketResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /wallets/{id}/pockets/{pocketId} [put]
+func MakeUpdatePocketEndpoint(s services.PocketService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req PocketRequest
+		var ok bool = false
+
+		if req, ok = request.(PocketRequest); !ok {
+			logger.Errorln("Layer:pocket_endpoint", "Method:MakeUpdatePocketEndpoint", ErrInterfaceWrong)
+			return PocketResponse{}, ErrInterfaceWrong
+		}
+		pocket, err := s.UpdatePocket(ctx, req.Email, req.WalletID, req.pocket())
+		if err != nil {
+			logger.Errorln("Layer:pocket_endpoint", "Method:MakeUpdatePocketEndpoint", err)
+			return PocketResponse{}, err
+		}
+		return PocketResponse{Pocket: pocket}, nil
+	}
+}
+
+// @Summary Close Pocket
+// @Description Gives the pocket balance back to the wallet and closes the pocket
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param pocketId path string true "Pocket ID"
+// @Success 200 {object} PocketResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /wallets/{id}/pockets/{pocketId} [delete]
+func MakeClosePocketEndpoint(s services.PocketService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetPocketRequest
+		var ok bool = false
+
+		if req, ok = request.(GetPocketRequest); !ok {
+			logger.Errorln("Layer:pocket_endpoint", "Method:MakeClosePocketEndpoint", ErrInterfaceWrong)
+			return PocketResponse{}, ErrInterfaceWrong
+		}
+		pocket, err := s.ClosePocket(ctx, req.Email, req.WalletID, req.PocketID)
+		if err != nil {
+			logger.Errorln("Layer:pocket_endpoint", "Method:MakeClosePocketEndpoint", err)
+			return PocketResponse{}, err
+		}
+		return PocketResponse{Pocket: pocket}, nil
+	}
+}
+
+// @Summary Move To Pocket
+// @Description Moves money from the wallet balance into a pocket
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param pocketId path string true "Pocket ID"
+// @Param move body MovePocketRequest true "Move"
+// @Success 200 {object} PocketResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /wallets/{id}/pockets/{pocketId}/deposit [post]
+func MakeMoveToPocketEndpoint(s services.PocketService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return makeMovePocketEndpoint("MakeMoveToPocketEndpoint", s.MoveToPocket, logger)
+}
+
+// @Summary Move From Pocket
+// @Description Moves money saved in a pocket back to the wallet balance
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param pocketId path string true "Pocket ID"
+// @Param move body MovePocketRequest true "Move"
+// @Success 200 {object} PocketResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /wallets/{id}/pockets/{pocketId}/withdraw [post]
+func MakeMoveFromPocketEndpoint(s services.PocketService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return makeMovePocketEndpoint("MakeMoveFromPocketEndpoint", s.MoveFromPocket, logger)
+}
+
+func makeMovePocketEndpoint(method string, move func(ctx context.Context, email string, walletID string, pocketID string, amount int64) (entities.Pocket, error), logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req MovePocketRequest
+		var ok bool = false
+
+		if req, ok = request.(MovePocketRequest); !ok {
+			logger.Errorln("Layer:pocket_endpoint", "Method:"+method, ErrInterfaceWrong)
+			return PocketResponse{}, ErrInterfaceWrong
+		}
+		pocket, err := move(ctx, req.Email, req.WalletID, req.PocketID, req.Amount)
+		if err != nil {
+			logger.Errorln("Layer:pocket_endpoint", "Method:"+method, err)
+			return PocketResponse{}, err
+		}
+		return PocketResponse{Pocket: pocket}, nil
+	}
+}
+
+func (req PocketRequest) pocket() entities.Pocket {
+	return entities.Pocket{
+		ID:           req.PocketID,
+		Name:         req.Name,
+		TargetAmount: req.TargetAmount,
+		TargetDate:   req.TargetDate,
+		Currency:     req.Currency,
+		RoundUpTo:    req.RoundUpTo,
+		WeeklyAmount: req.WeeklyAmount,
+	}
+}
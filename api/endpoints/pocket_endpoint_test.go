@@ -0,0 +1,134 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreatePocketEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *pocketServiceMock
+		mockResponse    entities.Pocket
+		mockError       error
+		configureMock   func(*pocketServiceMock, entities.Pocket, error)
+		endpointRequest interface{}
+		expectedOutput  PocketResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreatePocketEndpoint",
+			mock:         &pocketServiceMock{},
+			mockResponse: entities.Pocket{ID: "p1", Name: "Trip", Status: entities.PocketActive},
+			configureMock: func(m *pocketServiceMock, mockResponse entities.Pocket, mockError error) {
+				m.On("CreatePocket", mock.Anything, "alexer@gmail.com", "w1", entities.Pocket{Name: "Trip", TargetAmount: 1000000, RoundUpTo: 100000}).Return(mockResponse, mockError)
+			},
+			endpointRequest: PocketRequest{Email: "alexer@gmail.com", WalletID: "w1", Name: "Trip", TargetAmount: 1000000, RoundUpTo: 100000},
+			expectedOutput:  PocketResponse{Pocket: entities.Pocket{ID: "p1", Name: "Trip", Status: entities.PocketActive}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreatePocketEndpoint with error Interface type wrong",
+			mock:            &pocketServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  PocketResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreatePocketEndpoint with error in the service",
+			mock:      &pocketServiceMock{},
+			mockError: services.ErrTooManyPockets,
+			configureMock: func(m *pocketServiceMock, mockResponse entities.Pocket, mockError error) {
+				m.On("CreatePocket", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: PocketRequest{Email: "alexer@gmail.com", WalletID: "w1", Name: "Trip", TargetAmount: 1000000},
+			expectedOutput:  PocketResponse{},
+			expectedError:   services.ErrTooManyPockets,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreatePocketEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeMoveToPocketEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *pocketServiceMock
+		mockResponse    entities.Pocket
+		mockError       error
+		configureMock   func(*pocketServiceMock, entities.Pocket, error)
+		endpointRequest interface{}
+		expectedOutput  PocketResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeMoveToPocketEndpoint",
+			mock:         &pocketServiceMock{},
+			mockResponse: entities.Pocket{ID: "p1", Balance: 2000},
+			configureMock: func(m *pocketServiceMock, mockResponse entities.Pocket, mockError error) {
+				m.On("MoveToPocket", mock.Anything, "alexer@gmail.com", "w1", "p1", int64(2000)).Return(mockResponse, mockError)
+			},
+			endpointRequest: MovePocketRequest{Email: "alexer@gmail.com", WalletID: "w1", PocketID: "p1", Amount: 2000},
+			expectedOutput:  PocketResponse{Pocket: entities.Pocket{ID: "p1", Balance: 2000}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeMoveToPocketEndpoint with error Interface type wrong",
+			mock:            &pocketServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  PocketResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeMoveToPocketEndpoint with error in the service",
+			mock:      &pocketServiceMock{},
+			mockError: services.ErrInsufficientFunds,
+			configureMock: func(m *pocketServiceMock, mockResponse entities.Pocket, mockError error) {
+				m.On("MoveToPocket", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: MovePocketRequest{Email: "alexer@gmail.com", WalletID: "w1", PocketID: "p1", Amount: 2000},
+			expectedOutput:  PocketResponse{},
+			expectedError:   services.ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeMoveToPocketEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type pocketServiceMock struct {
+	mock.Mock
+}
+
+func (s *pocketServiceMock) CreatePocket(ctx context.Context, email string, walletID string, pocket entities.Pocket) (entities.Pocket, error) {
+	r := s.Called(ctx, email, walletID, pocket)
+	return r.Get(0).(entities.Pocket), r.Error(1)
+}
+
+func (s *pocketServiceMock) ListPockets(ctx context.Context, email string, walletID string) ([]entities.Pocket, error) {
+	r := s.Called(ctx, email, walletID)
+	return r.Get(0).([]entities.Pocket), r.Error(1)
+}
+
+func (s *pocketServiceMock) GetPocket(ctx context.Context, email string, walletID string, pocketID string) (entities.Pocket, error) {
+	r := s.Called(ctx, email, walletID, pocketID)
+	return r.Get(0).(entities.Pocket), r.Error(1)
+}
+
+func (s *pocketServiceMock) UpdatePocket(ctx context.Context, email string, walletID string, pocket entities.Pocket) (entities.Pocket, error) {
+	r := s.Called(ctx, email, walletID, pocket)
+	return r.Get(0).(entities.Pocket), r.Error(1)
+}
+
+func (s *pocketServiceMock) MoveToPocket(ctx context.Context, email string, walletID string, pocketID string, amount int64) (entities.Pocket, error) {
+	r := s.Called(ctx, email, walletID, pocketID, amount)
+	return r.Get(0).(entities.Pocket), r.Error(1)
+}
+
+func (s *pocketServiceMock) MoveFromPocket(ctx context.Context, email string, walletID string, pocketID string, amount int64) (entities.Pocket, error) {
+	r := s.Called(ctx, email, walletID, pocketID, amount)
+	return r.Get(0).(entities.Pocket), r.Error(1)
+}
+
+func (s *pocketServiceMock) ClosePocket(ctx context.Context, email string, walletID string, pocketID string) (entities.Pocket, error) {
+	r := s.Called(ctx, email, walletID, pocketID)
+	return r.Get(0).(entities.Pocket), r.Error(1)
+}
+
+func (s *pocketServiceMock) RoundUp(ctx context.Context, transfer entities.Transfer) {
+	s.Called(ctx, transfer)
+}
+
+func (s *pocketServiceMock) RunAutoSaves(ctx context.Context, now time.Time) (int, error) {
+	r := s.Called(ctx, now)
+	return r.Int(0), r.Error(1)
+}
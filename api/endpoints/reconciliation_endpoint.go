@@ -0,0 +1,86 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// ListReconciliationsRequest represents the request for the latest reconciliation runs
+type ListReconciliationsRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListReconciliationsResponse represents the latest reconciliation runs
+type ListReconciliationsResponse struct {
+	Runs []entities.ReconciliationRun `json:"runs"`            // Runs with their summaries, newest first
+	Err  string                       `json:"error,omitempty"` // Error message, if any
+}
+
+// GetReconciliationRequest represents the request for the results of a reconciliation run
+type GetReconciliationRequest struct {
+	Email  string `json:"-"` // Email of the authenticated user
+	RunID  string `json:"-"` // Reconciliation run ID
+	Result string `json:"-"` // Only items with this result, when not empty
+}
+
+// GetReconciliationResponse represents a reconciliation run with its items
+type GetReconciliationResponse struct {
+	Report entities.ReconciliationReport `json:"report"`          // Run and items
+	Err    string                        `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary List Reconciliations
+// @Description Returns the latest end-of-day reconciliation runs against the providers with their summaries; support staff only
+// @Produce json
+// @Success 200 {object} ListReconciliationsResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/reconciliations [get]
+func MakeListReconciliationsEndpoint(s services.ReconciliationService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListReconciliationsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListReconciliationsRequest); !ok {
+			logger.Errorln("Layer:reconciliation_endpoint", "Method:MakeListReconciliationsEndpoint", ErrInterfaceWrong)
+			return ListReconciliationsResponse{}, ErrInterfaceWrong
+		}
+		runs, err := s.ListReconciliations(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:reconciliation_endpoint", "Method:MakeListReconciliationsEndpoint", err)
+			return ListReconciliationsResponse{}, err
+		}
+		return ListReconciliationsResponse{Runs: runs}, nil
+	}
+}
+
+// @Summary Get Reconciliation
+// @Description Returns a reconciliation run with its matched, mismatched and unmatched items; support staff only
+// @Produce json
+// @Param id path string true "Reconciliation run ID"
+// @Param result query string false "matched, amount_mismatch, unmatched_internal or unmatched_external"
+// @Success 200 {object} GetReconciliationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/reconciliations/{id} [get]
+func MakeGetReconciliationEndpoint(s services.ReconciliationService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetReconciliationRequest
+		var ok bool = false
+
+		if req, ok = request.(GetReconciliationRequest); !ok {
+			logger.Errorln("Layer:reconciliation_endpoint", "Method:MakeGetReconciliationEndpoint", ErrInterfaceWrong)
+			return GetReconciliationResponse{}, ErrInterfaceWrong
+		}
+		report, err := s.GetReconciliation(ctx, req.Email, req.RunID, req.Result)
+		if err != nil {
+			logger.Errorln("Layer:reconciliation_endpoint", "Method:MakeGetReconciliationEndpoint", err)
+			return GetReconciliationResponse{}, err
+		}
+		return GetReconciliationResponse{Report: report}, nil
+	}
+}
@@ -0,0 +1,77 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeGetReconciliationEndpoint(t *testing.T) {
+	report := entities.ReconciliationReport{
+		Run:   entities.ReconciliationRun{ID: "run1", Provider: "bank", Summary: entities.ReconciliationSummary{Records: 1, AmountMismatch: 1}},
+		Items: []entities.ReconciliationItem{{RunID: "run1", Result: entities.ReconciliationAmountMismatch, Reference: "e1", InternalAmount: 50000, ExternalAmount: 45000, Difference: -5000}},
+	}
+
+	testScenarios := []struct {
+		testName        string
+		mock            *reconciliationServiceMock
+		mockResponse    entities.ReconciliationReport
+		mockError       error
+		configureMock   func(*reconciliationServiceMock, entities.ReconciliationReport, error)
+		endpointRequest interface{}
+		expectedOutput  GetReconciliationResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeGetReconciliationEndpoint",
+			mock:         &reconciliationServiceMock{},
+			mockResponse: report,
+			configureMock: func(m *reconciliationServiceMock, mockResponse entities.ReconciliationReport, mockError error) {
+				m.On("GetReconciliation", mock.Anything, "support@gmail.com", "run1", entities.ReconciliationAmountMismatch).Return(mockResponse, mockError)
+			},
+			endpointRequest: GetReconciliationRequest{Email: "support@gmail.com", RunID: "run1", Result: entities.ReconciliationAmountMismatch},
+			expectedOutput:  GetReconciliationResponse{Report: report},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeGetReconciliationEndpoint with error Interface type wrong",
+			mock:            &reconciliationServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  GetReconciliationResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeGetReconciliationEndpoint with error in the service",
+			mock:      &reconciliationServiceMock{},
+			mockError: services.ErrReconciliationNotFound,
+			configureMock: func(m *reconciliationServiceMock, mockResponse entities.ReconciliationReport, mockError error) {
+				m.On("GetReconciliation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: GetReconciliationRequest{Email: "support@gmail.com", RunID: "run2"},
+			expectedOutput:  GetReconciliationResponse{},
+			expectedError:   services.ErrReconciliationNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeGetReconciliationEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
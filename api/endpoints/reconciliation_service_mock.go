@@ -0,0 +1,28 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/utils/settlement"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type reconciliationServiceMock struct {
+	mock.Mock
+}
+
+func (s *reconciliationServiceMock) Reconcile(ctx context.Context, run entities.ReconciliationRun, records []settlement.Record) (entities.ReconciliationRun, error) {
+	r := s.Called(ctx, run, records)
+	return r.Get(0).(entities.ReconciliationRun), r.Error(1)
+}
+
+func (s *reconciliationServiceMock) ListReconciliations(ctx context.Context, email string) ([]entities.ReconciliationRun, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.ReconciliationRun), r.Error(1)
+}
+
+func (s *reconciliationServiceMock) GetReconciliation(ctx context.Context, email string, runID string, result string) (entities.ReconciliationReport, error) {
+	r := s.Called(ctx, email, runID, result)
+	return r.Get(0).(entities.ReconciliationReport), r.Error(1)
+}
@@ -0,0 +1,146 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// PlaceRestrictionRequest represents the request of support staff to restrict a wallet
+type PlaceRestrictionRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet ID
+	// @example "capabilities"
+	Kind string `json:"kind"` // freeze, block or capabilities
+	// @example ["withdrawals"]
+	Capabilities []string `json:"capabilities,omitempty"` // Capabilities restricted: transfers, withdrawals, payments, conversions or pockets
+	// @example "suspected_fraud"
+	Reason string `json:"reason"` // Reason code
+	// @example "Chargebacks reported by the bank"
+	Note string `json:"note,omitempty"` // Note for other staff
+	// @example "2026-11-19T00:00:00Z"
+	ExpiresAt time.Time `json:"expires_at"` // Expiry, within a year
+}
+
+// RestrictionResponse represents a wallet restriction
+type RestrictionResponse struct {
+	Restriction entities.WalletRestriction `json:"restriction"`     // Restriction
+	Err         string                     `json:"error,omitempty"` // Error message, if any
+}
+
+// ListRestrictionsRequest represents the request for the restrictions of a wallet
+type ListRestrictionsRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	WalletID string `json:"-"` // Wallet ID
+}
+
+// ListRestrictionsResponse represents the restrictions of a wallet and their audit trail
+type ListRestrictionsResponse struct {
+	Restrictions entities.WalletRestrictions `json:"restrictions"`    // Restrictions and audit trail
+	Err          string                      `json:"error,omitempty"` // Error message, if any
+}
+
+// LiftRestrictionRequest represents the request of support staff to lift a wallet restriction
+type LiftRestrictionRequest struct {
+	Email         string `json:"-"` // Email of the authenticated user
+	WalletID      string `json:"-"` // Wallet ID
+	RestrictionID string `json:"-"` // Restriction ID
+	// @example "Customer confirmed the transfers"
+	Note string `json:"note,omitempty"` // Note for other staff
+}
+
+// @Summary Restrict Wallet
+// @Description Freezes, blocks or restricts capabilities of a wallet until the expiry; support staff only
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param restriction body PlaceRestrictionRequest true "Restriction"
+// @Success 201 {object} RestrictionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/wallets/{id}/restrictions [post]
+func MakePlaceRestrictionEndpoint(s services.RestrictionService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req PlaceRestrictionRequest
+		var ok bool = false
+
+		if req, ok = request.(PlaceRestrictionRequest); !ok {
+			logger.Errorln("Layer:restriction_endpoint", "Method:MakePlaceRestrictionEndpoint", ErrInterfaceWrong)
+			return RestrictionResponse{}, ErrInterfaceWrong
+		}
+		restriction, err := s.PlaceRestriction(ctx, req.Email, req.WalletID, entities.WalletRestriction{
+			Kind:         req.Kind,
+			Capabilities: req.Capabilities,
+			Reason:       req.Reason,
+			Note:         req.Note,
+			Expires_at:   req.ExpiresAt,
+		})
+		if err != nil {
+			logger.Errorln("Layer:restriction_endpoint", "Method:MakePlaceRestrictionEndpoint", err)
+			return RestrictionResponse{}, err
+		}
+		return RestrictionResponse{Restriction: restriction}, nil
+	}
+}
+
+// @Summary List Wallet Restrictions
+// @Description Returns every restriction of a wallet with its status, and the audit trail; support staff only
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {object} ListRestrictionsResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/wallets/{id}/restrictions [get]
+func MakeListRestrictionsEndpoint(s services.RestrictionService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListRestrictionsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListRestrictionsRequest); !ok {
+			logger.Errorln("Layer:restriction_endpoint", "Method:MakeListRestrictionsEndpoint", ErrInterfaceWrong)
+			return ListRestrictionsResponse{}, ErrInterfaceWrong
+		}
+		restrictions, err := s.ListRestrictions(ctx, req.Email, req.WalletID)
+		if err != nil {
+			logger.Errorln("Layer:restriction_endpoint", "Method:MakeListRestrictionsEndpoint", err)
+			return ListRestrictionsResponse{}, err
+		}
+		return ListRestrictionsResponse{Restrictions: restrictions}, nil
+	}
+}
+
+// @Summary Lift Wallet Restriction
+// @Description Ends a restriction of a wallet before its expiry; support staff only
+// @Accept json
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Param restrictionId path string true "Restriction ID"
+// @Param lift body LiftRestrictionRequest false "Lift"
+// @Success 200 {object} RestrictionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/wallets/{id}/restrictions/{restrictionId}/lift [post]
+func MakeLiftRestrictionEndpoint(s services.RestrictionService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req LiftRestrictionRequest
+		var ok bool = false
+
+		if req, ok = request.(LiftRestrictionRequest); !ok {
+			logger.Errorln("Layer:restriction_endpoint", "Method:MakeLiftRestrictionEndpoint", ErrInterfaceWrong)
+			return RestrictionResponse{}, ErrInterfaceWrong
+		}
+		restriction, err := s.LiftRestriction(ctx, req.Email, req.WalletID, req.RestrictionID, req.Note)
+		if err != nil {
+			logger.Errorln("Layer:restriction_endpoint", "Method:MakeLiftRestrictionEndpoint", err)
+			return RestrictionResponse{}, err
+		}
+		return RestrictionResponse{Restriction: restriction}, nil
+	}
+}
@@ -0,0 +1,136 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakePlaceRestrictionEndpoint(t *testing.T) {
+	expiresAt := time.Date(2026, 11, 19, 0, 0, 0, 0, time.UTC)
+
+	testScenarios := []struct {
+		testName        string
+		mock            *restrictionServiceMock
+		mockResponse    entities.WalletRestriction
+		mockError       error
+		configureMock   func(*restrictionServiceMock, entities.WalletRestriction, error)
+		endpointRequest interface{}
+		expectedOutput  RestrictionResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakePlaceRestrictionEndpoint",
+			mock:         &restrictionServiceMock{},
+			mockResponse: entities.WalletRestriction{ID: "r1", WalletID: "w1", Kind: entities.RestrictionFreeze, Reason: "suspected_fraud", Status: entities.RestrictionActive, Expires_at: expiresAt},
+			configureMock: func(m *restrictionServiceMock, mockResponse entities.WalletRestriction, mockError error) {
+				m.On("PlaceRestriction", mock.Anything, "support@gmail.com", "w1", entities.WalletRestriction{Kind: entities.RestrictionFreeze, Reason: "suspected_fraud", Expires_at: expiresAt}).Return(mockResponse, mockError)
+			},
+			endpointRequest: PlaceRestrictionRequest{Email: "support@gmail.com", WalletID: "w1", Kind: entities.RestrictionFreeze, Reason: "suspected_fraud", ExpiresAt: expiresAt},
+			expectedOutput:  RestrictionResponse{Restriction: entities.WalletRestriction{ID: "r1", WalletID: "w1", Kind: entities.RestrictionFreeze, Reason: "suspected_fraud", Status: entities.RestrictionActive, Expires_at: expiresAt}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakePlaceRestrictionEndpoint with error Interface type wrong",
+			mock:            &restrictionServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  RestrictionResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakePlaceRestrictionEndpoint with error in the service",
+			mock:      &restrictionServiceMock{},
+			mockError: services.ErrInvalidRestriction,
+			configureMock: func(m *restrictionServiceMock, mockResponse entities.WalletRestriction, mockError error) {
+				m.On("PlaceRestriction", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: PlaceRestrictionRequest{Email: "support@gmail.com", WalletID: "w1", Kind: "lock"},
+			expectedOutput:  RestrictionResponse{},
+			expectedError:   services.ErrInvalidRestriction,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakePlaceRestrictionEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeLiftRestrictionEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *restrictionServiceMock
+		mockResponse    entities.WalletRestriction
+		mockError       error
+		configureMock   func(*restrictionServiceMock, entities.WalletRestriction, error)
+		endpointRequest interface{}
+		expectedOutput  RestrictionResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeLiftRestrictionEndpoint",
+			mock:         &restrictionServiceMock{},
+			mockResponse: entities.WalletRestriction{ID: "r1", WalletID: "w1", Status: entities.RestrictionLifted, LiftedBy: "support@gmail.com"},
+			configureMock: func(m *restrictionServiceMock, mockResponse entities.WalletRestriction, mockError error) {
+				m.On("LiftRestriction", mock.Anything, "support@gmail.com", "w1", "r1", "Customer confirmed").Return(mockResponse, mockError)
+			},
+			endpointRequest: LiftRestrictionRequest{Email: "support@gmail.com", WalletID: "w1", RestrictionID: "r1", Note: "Customer confirmed"},
+			expectedOutput:  RestrictionResponse{Restriction: entities.WalletRestriction{ID: "r1", WalletID: "w1", Status: entities.RestrictionLifted, LiftedBy: "support@gmail.com"}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeLiftRestrictionEndpoint with error Interface type wrong",
+			mock:            &restrictionServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  RestrictionResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeLiftRestrictionEndpoint with error in the service",
+			mock:      &restrictionServiceMock{},
+			mockError: services.ErrRestrictionLifted,
+			configureMock: func(m *restrictionServiceMock, mockResponse entities.WalletRestriction, mockError error) {
+				m.On("LiftRestriction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: LiftRestrictionRequest{Email: "support@gmail.com", WalletID: "w1", RestrictionID: "r1"},
+			expectedOutput:  RestrictionResponse{},
+			expectedError:   services.ErrRestrictionLifted,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeLiftRestrictionEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
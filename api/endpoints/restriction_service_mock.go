@@ -0,0 +1,37 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type restrictionServiceMock struct {
+	mock.Mock
+}
+
+func (s *restrictionServiceMock) PlaceRestriction(ctx context.Context, email string, walletID string, restriction entities.WalletRestriction) (entities.WalletRestriction, error) {
+	r := s.Called(ctx, email, walletID, restriction)
+	return r.Get(0).(entities.WalletRestriction), r.Error(1)
+}
+
+func (s *restrictionServiceMock) ListRestrictions(ctx context.Context, email string, walletID string) (entities.WalletRestrictions, error) {
+	r := s.Called(ctx, email, walletID)
+	return r.Get(0).(entities.WalletRestrictions), r.Error(1)
+}
+
+func (s *restrictionServiceMock) LiftRestriction(ctx context.Context, email string, walletID string, restrictionID string, note string) (entities.WalletRestriction, error) {
+	r := s.Called(ctx, email, walletID, restrictionID, note)
+	return r.Get(0).(entities.WalletRestriction), r.Error(1)
+}
+
+func (s *restrictionServiceMock) CheckDebit(ctx context.Context, walletID string, capability string) error {
+	r := s.Called(ctx, walletID, capability)
+	return r.Error(0)
+}
+
+func (s *restrictionServiceMock) CheckCredit(ctx context.Context, walletID string) error {
+	r := s.Called(ctx, walletID)
+	return r.Error(0)
+}
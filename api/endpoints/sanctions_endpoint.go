@@ -0,0 +1,119 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// ListSanctionsScreeningsRequest represents the request for the compliance review queue
+type ListSanctionsScreeningsRequest struct {
+	Email  string `json:"-"` // Email of the authenticated user
+	Status string `json:"-"` // Status of the screenings; pending by default
+}
+
+// ListSanctionsScreeningsResponse represents the compliance review queue
+type ListSanctionsScreeningsResponse struct {
+	Screenings []entities.SanctionsScreening `json:"screenings"`      // Screenings, oldest first
+	Err        string                        `json:"error,omitempty"` // Error message, if any
+}
+
+// SanctionsScreeningRequest represents the decision of an analyst on a user matching the sanctions list
+type SanctionsScreeningRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Screening ID
+	// @example "Date of birth does not match the listed party"
+	Note string `json:"note,omitempty"` // Note of the analyst
+}
+
+// SanctionsScreeningResponse represents a sanctions screening
+type SanctionsScreeningResponse struct {
+	Screening entities.SanctionsScreening `json:"screening"`       // Screening
+	Err       string                      `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary List Sanctions Screenings
+// @Description Returns the users whose name matched the sanctions list, oldest first; analysts only
+// @Produce json
+// @Param status query string false "pending, cleared or confirmed"
+// @Success 200 {object} ListSanctionsScreeningsResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /sanctions/screenings [get]
+func MakeListSanctionsScreeningsEndpoint(s services.SanctionsService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListSanctionsScreeningsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListSanctionsScreeningsRequest); !ok {
+			logger.Errorln("Layer:sanctions_endpoint", "Method:MakeListSanctionsScreeningsEndpoint", ErrInterfaceWrong)
+			return ListSanctionsScreeningsResponse{}, ErrInterfaceWrong
+		}
+		screenings, err := s.ListScreenings(ctx, req.Email, req.Status)
+		if err != nil {
+			logger.Errorln("Layer:sanctions_endpoint", "Method:MakeListSanctionsScreeningsEndpoint", err)
+			return ListSanctionsScreeningsResponse{}, err
+		}
+		return ListSanctionsScreeningsResponse{Screenings: screenings}, nil
+	}
+}
+
+// @Summary Clear Sanctions Screening
+// @Description Clears a user whose name matched the sanctions list, who can move money again; analysts only
+// @Accept json
+// @Produce json
+// @Param id path string true "Screening ID"
+// @Param screening body SanctionsScreeningRequest false "Note"
+// @Success 200 {object} SanctionsScreeningResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /sanctions/screenings/{id}/clear [post]
+func MakeClearSanctionsScreeningEndpoint(s services.SanctionsService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req SanctionsScreeningRequest
+		var ok bool = false
+
+		if req, ok = request.(SanctionsScreeningRequest); !ok {
+			logger.Errorln("Layer:sanctions_endpoint", "Method:MakeClearSanctionsScreeningEndpoint", ErrInterfaceWrong)
+			return SanctionsScreeningResponse{}, ErrInterfaceWrong
+		}
+		screening, err := s.ClearScreening(ctx, req.Email, req.ID, req.Note)
+		if err != nil {
+			logger.Errorln("Layer:sanctions_endpoint", "Method:MakeClearSanctionsScreeningEndpoint", err)
+			return SanctionsScreeningResponse{}, err
+		}
+		return SanctionsScreeningResponse{Screening: screening}, nil
+	}
+}
+
+// @Summary Confirm Sanctions Screening
+// @Description Confirms a user is a listed party; the user is blocked and disabled. Analysts only
+// @Accept json
+// @Produce json
+// @Param id path string true "Screening ID"
+// @Param screening body SanctionsScreeningRequest false "Note"
+// @Success 200 {object} SanctionsScreeningResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /sanctions/screenings/{id}/confirm [post]
+func MakeConfirmSanctionsScreeningEndpoint(s services.SanctionsService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req SanctionsScreeningRequest
+		var ok bool = false
+
+		if req, ok = request.(SanctionsScreeningRequest); !ok {
+			logger.Errorln("Layer:sanctions_endpoint", "Method:MakeConfirmSanctionsScreeningEndpoint", ErrInterfaceWrong)
+			return SanctionsScreeningResponse{}, ErrInterfaceWrong
+		}
+		screening, err := s.ConfirmScreening(ctx, req.Email, req.ID, req.Note)
+		if err != nil {
+			logger.Errorln("Layer:sanctions_endpoint", "Method:MakeConfirmSanctionsScreeningEndpoint", err)
+			return SanctionsScreeningResponse{}, err
+		}
+		return SanctionsScreeningResponse{Screening: screening}, nil
+	}
+}
@@ -0,0 +1,73 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeClearSanctionsScreeningEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *sanctionsServiceMock
+		mockResponse    entities.SanctionsScreening
+		mockError       error
+		configureMock   func(*sanctionsServiceMock, entities.SanctionsScreening, error)
+		endpointRequest interface{}
+		expectedOutput  SanctionsScreeningResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeClearSanctionsScreeningEndpoint",
+			mock:         &sanctionsServiceMock{},
+			mockResponse: entities.SanctionsScreening{ID: "s1", Status: entities.SanctionsCleared},
+			configureMock: func(m *sanctionsServiceMock, mockResponse entities.SanctionsScreening, mockError error) {
+				m.On("ClearScreening", mock.Anything, "analyst@gmail.com", "s1", "ok").Return(mockResponse, mockError)
+			},
+			endpointRequest: SanctionsScreeningRequest{Email: "analyst@gmail.com", ID: "s1", Note: "ok"},
+			expectedOutput:  SanctionsScreeningResponse{Screening: entities.SanctionsScreening{ID: "s1", Status: entities.SanctionsCleared}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeClearSanctionsScreeningEndpoint with error Interface type wrong",
+			mock:            &sanctionsServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  SanctionsScreeningResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeClearSanctionsScreeningEndpoint with error in the service",
+			mock:      &sanctionsServiceMock{},
+			mockError: services.ErrSanctionsScreeningState,
+			configureMock: func(m *sanctionsServiceMock, mockResponse entities.SanctionsScreening, mockError error) {
+				m.On("ClearScreening", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: SanctionsScreeningRequest{Email: "analyst@gmail.com", ID: "s1"},
+			expectedOutput:  SanctionsScreeningResponse{},
+			expectedError:   services.ErrSanctionsScreeningState,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeClearSanctionsScreeningEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
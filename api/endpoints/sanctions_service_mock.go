@@ -0,0 +1,32 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type sanctionsServiceMock struct {
+	mock.Mock
+}
+
+func (s *sanctionsServiceMock) ListScreenings(ctx context.Context, email string, status string) ([]entities.SanctionsScreening, error) {
+	r := s.Called(ctx, email, status)
+	return r.Get(0).([]entities.SanctionsScreening), r.Error(1)
+}
+
+func (s *sanctionsServiceMock) ClearScreening(ctx context.Context, email string, id string, note string) (entities.SanctionsScreening, error) {
+	r := s.Called(ctx, email, id, note)
+	return r.Get(0).(entities.SanctionsScreening), r.Error(1)
+}
+
+func (s *sanctionsServiceMock) ConfirmScreening(ctx context.Context, email string, id string, note string) (entities.SanctionsScreening, error) {
+	r := s.Called(ctx, email, id, note)
+	return r.Get(0).(entities.SanctionsScreening), r.Error(1)
+}
+
+func (s *sanctionsServiceMock) RescreenUsers(ctx context.Context) (int, error) {
+	r := s.Called(ctx)
+	return r.Int(0), r.Error(1)
+}
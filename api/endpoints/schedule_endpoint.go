@@ -0,0 +1,213 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateScheduleRequest represents the request to schedule a payment
+// @Description Exactly one of recipient email, phone or DNI identifies the recipient
+type CreateScheduleRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	// @example "landlord@gmail.com"
+	RecipientEmail string `json:"recipient_email,omitempty"` // Recipient's email
+	// @example 3017942380
+	RecipientPhone int `json:"recipient_phone,omitempty"` // Recipient's phone number
+	// @example 1002842747
+	RecipientDNI int `json:"recipient_dni,omitempty"` // Recipient's DNI
+	// @example 150000000
+	Amount int64 `json:"amount"` // Amount in cents
+	// @example "COP"
+	Currency string `json:"currency,omitempty"` // Currency, the sender's default when empty
+	// @example "Rent"
+	Memo string `json:"memo,omitempty"` // Optional note for the recipient
+	// @example "monthly"
+	Frequency string `json:"frequency"` // once, daily, weekly or monthly
+	// @example 1
+	Interval int `json:"interval,omitempty"` // Repeat every N days, weeks or months, 1 when empty
+	// @example -1
+	DayOfMonth int `json:"day_of_month,omitempty"` // Day of monthly payments, -1 for the last day
+	// @example "2024-10-01T09:00:00Z"
+	StartAt time.Time `json:"start_at,omitempty"` // First payment, now when empty
+	// @example "2025-09-30T23:59:59Z"
+	EndAt *time.Time `json:"end_at,omitempty"` // No payments after this time
+	// @example 12
+	MaxRuns int `json:"max_runs,omitempty"` // Stop after this many payments
+}
+
+// ScheduleRequest represents a request about one scheduled payment
+type ScheduleRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Scheduled payment ID
+}
+
+// ScheduleResponse represents a scheduled payment
+// @Description Response with a scheduled payment
+type ScheduleResponse struct {
+	Schedule entities.ScheduledPayment `json:"schedule"`        // Scheduled payment
+	Err      string                    `json:"error,omitempty"` // Error message, if any
+}
+
+// ListSchedulesRequest represents the request to list scheduled payments
+type ListSchedulesRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListSchedulesResponse represents the scheduled payments of a user
+// @Description Response with the scheduled payments, newest first
+type ListSchedulesResponse struct {
+	Schedules []entities.ScheduledPayment `json:"schedules"`       // Scheduled payments
+	Err       string                      `json:"error,omitempty"` // Error message, if any
+}
+
+// ListExecutionsResponse represents the execution history of a schedule
+// @Description Response with every run of a scheduled payment, newest first
+type ListExecutionsResponse struct {
+	Executions []entities.ScheduleExecution `json:"executions"`      // Executions
+	Err        string                       `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Create Scheduled Payment
+// @Description Schedules a transfer once or on a recurring schedule
+// @Accept json
+// @Produce json
+// @Param schedule body CreateScheduleRequest true "Scheduled payment"
+// @Success 201 {object} ScheduleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /schedules [post]
+func MakeCreateScheduleEndpoint(s services.ScheduleService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateScheduleRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateScheduleRequest); !ok {
+			logger.Errorln("Layer:schedule_endpoint", "Method:MakeCreateScheduleEndpoint", ErrInterfaceWrong)
+			return ScheduleResponse{}, ErrInterfaceWrong
+		}
+		schedule := entities.ScheduledPayment{
+			RecipientEmail: req.RecipientEmail,
+			RecipientPhone: req.RecipientPhone,
+			RecipientDNI:   req.RecipientDNI,
+			Amount:         req.Amount,
+			Currency:       req.Currency,
+			Memo:           req.Memo,
+			Frequency:      req.Frequency,
+			Interval:       req.Interval,
+			DayOfMonth:     req.DayOfMonth,
+			StartAt:        req.StartAt,
+			EndAt:          req.EndAt,
+			MaxRuns:        req.MaxRuns,
+		}
+		schedule, err = s.CreateSchedule(ctx, req.Email, schedule)
+		if err != nil {
+			logger.Errorln("Layer:schedule_endpoint", "Method:MakeCreateScheduleEndpoint", err)
+			return ScheduleResponse{}, err
+		}
+		return ScheduleResponse{Schedule: schedule}, nil
+	}
+}
+
+// @Summary List Scheduled Payments
+// @Description Lists the scheduled payments of the authenticated user
+// @Produce json
+// @Success 200 {object} ListSchedulesResponse
+// @Router /schedules [get]
+func MakeListSchedulesEndpoint(s services.ScheduleService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListSchedulesRequest
+		var ok bool = false
+
+		if req, ok = request.(ListSchedulesRequest); !ok {
+			logger.Errorln("Layer:schedule_endpoint", "Method:MakeListSchedulesEndpoint", ErrInterfaceWrong)
+			return ListSchedulesResponse{}, ErrInterfaceWrong
+		}
+		schedules, err := s.ListSchedules(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:schedule_endpoint", "Method:MakeListSchedulesEndpoint", err)
+			return ListSchedulesResponse{}, err
+		}
+		return ListSchedulesResponse{Schedules: schedules}, nil
+	}
+}
+
+// @Summary Get Scheduled Payment
+// @Description Returns a scheduled payment of the authenticated user
+// @Produce json
+// @Param id path string true "Scheduled payment ID"
+// @Success 200 {object} ScheduleResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /schedules/{id} [get]
+func MakeGetScheduleEndpoint(s services.ScheduleService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ScheduleRequest
+		var ok bool = false
+
+		if req, ok = request.(ScheduleRequest); !ok {
+			logger.Errorln("Layer:schedule_endpoint", "Method:MakeGetScheduleEndpoint", ErrInterfaceWrong)
+			return ScheduleResponse{}, ErrInterfaceWrong
+		}
+		schedule, err := s.GetSchedule(ctx, req.Email, req.ID)
+		if err != nil {
+			logger.Errorln("Layer:schedule_endpoint", "Method:MakeGetScheduleEndpoint", err)
+			return ScheduleResponse{}, err
+		}
+		return ScheduleResponse{Schedule: schedule}, nil
+	}
+}
+
+// @Summary Cancel Scheduled Payment
+// @Description Stops an active scheduled payment
+// @Produce json
+// @Param id path string true "Scheduled payment ID"
+// @Success 200 {object} ScheduleResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /schedules/{id} [delete]
+func MakeCancelScheduleEndpoint(s services.ScheduleService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ScheduleRequest
+		var ok bool = false
+
+		if req, ok = request.(ScheduleRequest); !ok {
+			logger.Errorln("Layer:schedule_endpoint", "Method:MakeCancelScheduleEndpoint", ErrInterfaceWrong)
+			return ScheduleResponse{}, ErrInterfaceWrong
+		}
+		schedule, err := s.CancelSchedule(ctx, req.Email, req.ID)
+		if err != nil {
+			logger.Errorln("Layer:schedule_endpoint", "Method:MakeCancelScheduleEndpoint", err)
+			return ScheduleResponse{}, err
+		}
+		return ScheduleResponse{Schedule: schedule}, nil
+	}
+}
+
+// @Summary List Schedule Executions
+// @Description Returns every run of a scheduled payment, including retries
+// @Produce json
+// @Param id path string true "Scheduled payment ID"
+// @Success 200 {object} ListExecutionsResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /schedules/{id}/executions [get]
+func MakeListExecutionsEndpoint(s services.ScheduleService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ScheduleRequest
+		var ok bool = false
+
+		if req, ok = request.(ScheduleRequest); !ok {
+			logger.Errorln("Layer:schedule_endpoint", "Method:MakeListExecutionsEndpoint", ErrInterfaceWrong)
+			return ListExecutionsResponse{}, ErrInterfaceWrong
+		}
+		executions, err := s.ListExecutions(ctx, req.Email, req.ID)
+		if err != nil {
+			logger.Errorln("Layer:schedule_endpoint", "Method:MakeListExecutionsEndpoint", err)
+			return ListExecutionsResponse{}, err
+		}
+		return ListExecutionsResponse{Executions: executions}, nil
+	}
+}
@@ -0,0 +1,73 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreateScheduleEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *scheduleServiceMock
+		mockResponse    entities.ScheduledPayment
+		mockError       error
+		configureMock   func(*scheduleServiceMock, entities.ScheduledPayment, error)
+		endpointRequest interface{}
+		expectedOutput  ScheduleResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreateScheduleEndpoint",
+			mock:         &scheduleServiceMock{},
+			mockResponse: entities.ScheduledPayment{ID: "s1", Status: entities.ScheduleActive},
+			configureMock: func(m *scheduleServiceMock, mockResponse entities.ScheduledPayment, mockError error) {
+				m.On("CreateSchedule", mock.Anything, "alexer@gmail.com", entities.ScheduledPayment{RecipientEmail: "landlord@gmail.com", Amount: 100, Frequency: entities.FrequencyMonthly, DayOfMonth: -1}).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateScheduleRequest{Email: "alexer@gmail.com", RecipientEmail: "landlord@gmail.com", Amount: 100, Frequency: entities.FrequencyMonthly, DayOfMonth: -1},
+			expectedOutput:  ScheduleResponse{Schedule: entities.ScheduledPayment{ID: "s1", Status: entities.ScheduleActive}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreateScheduleEndpoint with error Interface type wrong",
+			mock:            &scheduleServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  ScheduleResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreateScheduleEndpoint with error in the service",
+			mock:      &scheduleServiceMock{},
+			mockError: services.ErrInvalidSchedule,
+			configureMock: func(m *scheduleServiceMock, mockResponse entities.ScheduledPayment, mockError error) {
+				m.On("CreateSchedule", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateScheduleRequest{Email: "alexer@gmail.com", Frequency: "yearly"},
+			expectedOutput:  ScheduleResponse{},
+			expectedError:   services.ErrInvalidSchedule,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreateScheduleEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type scheduleServiceMock struct {
+	mock.Mock
+}
+
+func (s *scheduleServiceMock) CreateSchedule(ctx context.Context, email string, schedule entities.ScheduledPayment) (entities.ScheduledPayment, error) {
+	r := s.Called(ctx, email, schedule)
+	return r.Get(0).(entities.ScheduledPayment), r.Error(1)
+}
+
+func (s *scheduleServiceMock) ListSchedules(ctx context.Context, email string) ([]entities.ScheduledPayment, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.ScheduledPayment), r.Error(1)
+}
+
+func (s *scheduleServiceMock) GetSchedule(ctx context.Context, email string, id string) (entities.ScheduledPayment, error) {
+	r := s.Called(ctx, email, id)
+	return r.Get(0).(entities.ScheduledPayment), r.Error(1)
+}
+
+func (s *scheduleServiceMock) CancelSchedule(ctx context.Context, email string, id string) (entities.ScheduledPayment, error) {
+	r := s.Called(ctx, email, id)
+	return r.Get(0).(entities.ScheduledPayment), r.Error(1)
+}
+
+func (s *scheduleServiceMock) ListExecutions(ctx context.Context, email string, id string) ([]entities.ScheduleExecution, error) {
+	r := s.Called(ctx, email, id)
+	return r.Get(0).([]entities.ScheduleExecution), r.Error(1)
+}
+
+func (s *scheduleServiceMock) RunDueSchedules(ctx context.Context, now time.Time) (int, error) {
+	r := s.Called(ctx, now)
+	return r.Int(0), r.Error(1)
+}
@@ -0,0 +1,223 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// ListScreeningRulesRequest represents the request for the screening rules in force
+type ListScreeningRulesRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListScreeningRulesResponse represents the screening rules in force
+type ListScreeningRulesResponse struct {
+	Rules []entities.ScreeningRule `json:"rules"`           // Rules
+	Err   string                   `json:"error,omitempty"` // Error message, if any
+}
+
+// SaveScreeningRuleRequest represents the request to create or replace a screening rule
+// @Description Threshold is in cents; count and window_minutes are used by rapid_succession and structuring rules
+type SaveScreeningRuleRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Rule ID
+	// @example "Transfer above 20,000,000 COP"
+	Name string `json:"name"` // Name shown to analysts
+	// @example "amount_above"
+	Kind string `json:"kind"` // amount_above, new_device, rapid_succession, new_counterparty or structuring
+	// @example "hold"
+	Action string `json:"action"` // allow, hold or block
+	// @example true
+	Enabled bool `json:"enabled"` // Whether the rule runs
+	// @example "COP"
+	Currency string `json:"currency,omitempty"` // Currency the rule applies to; all when empty
+	// @example 2000000000
+	Threshold int64 `json:"threshold,omitempty"` // Amount threshold in cents
+	// @example 3
+	Count int `json:"count,omitempty"` // Number of transactions
+	// @example 1440
+	WindowMinutes int `json:"window_minutes,omitempty"` // Time window in minutes
+}
+
+// ScreeningRuleResponse represents a screening rule
+type ScreeningRuleResponse struct {
+	Rule entities.ScreeningRule `json:"rule"`            // Rule
+	Err  string                 `json:"error,omitempty"` // Error message, if any
+}
+
+// ListScreeningReviewsRequest represents the request for the review queue
+type ListScreeningReviewsRequest struct {
+	Email  string `json:"-"` // Email of the authenticated user
+	Status string `json:"-"` // Status of the reviews; pending by default
+}
+
+// ListScreeningReviewsResponse represents the review queue
+type ListScreeningReviewsResponse struct {
+	Reviews []entities.ScreeningReview `json:"reviews"`         // Reviews, oldest first
+	Err     string                     `json:"error,omitempty"` // Error message, if any
+}
+
+// ScreeningReviewRequest represents the decision of an analyst on a held transaction
+type ScreeningReviewRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Review ID
+	// @example "Customer confirmed by phone"
+	Note string `json:"note,omitempty"` // Note of the analyst
+}
+
+// ScreeningReviewResponse represents a review of a held transaction
+type ScreeningReviewResponse struct {
+	Review entities.ScreeningReview `json:"review"`          // Review
+	Err    string                   `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary List Screening Rules
+// @Description Returns the fraud and AML rules in force; analysts only
+// @Produce json
+// @Success 200 {object} ListScreeningRulesResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /screening/rules [get]
+func MakeListScreeningRulesEndpoint(s services.ScreeningService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListScreeningRulesRequest
+		var ok bool = false
+
+		if req, ok = request.(ListScreeningRulesRequest); !ok {
+			logger.Errorln("Layer:screening_endpoint", "Method:MakeListScreeningRulesEndpoint", ErrInterfaceWrong)
+			return ListScreeningRulesResponse{}, ErrInterfaceWrong
+		}
+		rules, err := s.ListRules(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:screening_endpoint", "Method:MakeListScreeningRulesEndpoint", err)
+			return ListScreeningRulesResponse{}, err
+		}
+		return ListScreeningRulesResponse{Rules: rules}, nil
+	}
+}
+
+// @Summary Save Screening Rule
+// @Description Creates or replaces a fraud and AML rule; analysts only, and only when rules are kept in Mongo
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param rule body SaveScreeningRuleRequest true "Rule"
+// @Success 200 {object} ScreeningRuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /screening/rules/{id} [put]
+func MakeSaveScreeningRuleEndpoint(s services.ScreeningService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req SaveScreeningRuleRequest
+		var ok bool = false
+
+		if req, ok = request.(SaveScreeningRuleRequest); !ok {
+			logger.Errorln("Layer:screening_endpoint", "Method:MakeSaveScreeningRuleEndpoint", ErrInterfaceWrong)
+			return ScreeningRuleResponse{}, ErrInterfaceWrong
+		}
+		rule, err := s.SaveRule(ctx, req.Email, entities.ScreeningRule{
+			ID:            req.ID,
+			Name:          req.Name,
+			Kind:          req.Kind,
+			Action:        req.Action,
+			Enabled:       req.Enabled,
+			Currency:      req.Currency,
+			Threshold:     req.Threshold,
+			Count:         req.Count,
+			WindowMinutes: req.WindowMinutes,
+		})
+		if err != nil {
+			logger.Errorln("Layer:screening_endpoint", "Method:MakeSaveScreeningRuleEndpoint", err)
+			return ScreeningRuleResponse{}, err
+		}
+		return ScreeningRuleResponse{Rule: rule}, nil
+	}
+}
+
+// @Summary List Screening Reviews
+// @Description Returns the transactions held by the rules, oldest first; analysts only
+// @Produce json
+// @Param status query string false "pending, released, rejected or failed"
+// @Success 200 {object} ListScreeningReviewsResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /screening/reviews [get]
+func MakeListScreeningReviewsEndpoint(s services.ScreeningService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListScreeningReviewsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListScreeningReviewsRequest); !ok {
+			logger.Errorln("Layer:screening_endpoint", "Method:MakeListScreeningReviewsEndpoint", ErrInterfaceWrong)
+			return ListScreeningReviewsResponse{}, ErrInterfaceWrong
+		}
+		reviews, err := s.ListReviews(ctx, req.Email, req.Status)
+		if err != nil {
+			logger.Errorln("Layer:screening_endpoint", "Method:MakeListScreeningReviewsEndpoint", err)
+			return ListScreeningReviewsResponse{}, err
+		}
+		return ListScreeningReviewsResponse{Reviews: reviews}, nil
+	}
+}
+
+// @Summary Release Screening Review
+// @Description Releases a held transfer, which executes right away; analysts only
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param review body ScreeningReviewRequest false "Note"
+// @Success 200 {object} ScreeningReviewResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /screening/reviews/{id}/release [post]
+func MakeReleaseScreeningReviewEndpoint(s services.ScreeningService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ScreeningReviewRequest
+		var ok bool = false
+
+		if req, ok = request.(ScreeningReviewRequest); !ok {
+			logger.Errorln("Layer:screening_endpoint", "Method:MakeReleaseScreeningReviewEndpoint", ErrInterfaceWrong)
+			return ScreeningReviewResponse{}, ErrInterfaceWrong
+		}
+		review, err := s.ReleaseReview(ctx, req.Email, req.ID, req.Note)
+		if err != nil {
+			logger.Errorln("Layer:screening_endpoint", "Method:MakeReleaseScreeningReviewEndpoint", err)
+			return ScreeningReviewResponse{}, err
+		}
+		return ScreeningReviewResponse{Review: review}, nil
+	}
+}
+
+// @Summary Reject Screening Review
+// @Description Rejects a held transfer, which never executes; analysts only
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param review body ScreeningReviewRequest false "Note"
+// @Success 200 {object} ScreeningReviewResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /screening/reviews/{id}/reject [post]
+func MakeRejectScreeningReviewEndpoint(s services.ScreeningService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ScreeningReviewRequest
+		var ok bool = false
+
+		if req, ok = request.(ScreeningReviewRequest); !ok {
+			logger.Errorln("Layer:screening_endpoint", "Method:MakeRejectScreeningReviewEndpoint", ErrInterfaceWrong)
+			return ScreeningReviewResponse{}, ErrInterfaceWrong
+		}
+		review, err := s.RejectReview(ctx, req.Email, req.ID, req.Note)
+		if err != nil {
+			logger.Errorln("Layer:screening_endpoint", "Method:MakeRejectScreeningReviewEndpoint", err)
+			return ScreeningReviewResponse{}, err
+		}
+		return ScreeningReviewResponse{Review: review}, nil
+	}
+}
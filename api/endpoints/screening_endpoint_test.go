@@ -0,0 +1,135 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeListScreeningReviewsEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *screeningServiceMock
+		mockResponse    []entities.ScreeningReview
+		mockError       error
+		configureMock   func(*screeningServiceMock, []entities.ScreeningReview, error)
+		endpointRequest interface{}
+		expectedOutput  ListScreeningReviewsResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeListScreeningReviewsEndpoint",
+			mock:         &screeningServiceMock{},
+			mockResponse: []entities.ScreeningReview{{ID: "r1", Status: entities.ReviewPending}},
+			configureMock: func(m *screeningServiceMock, mockResponse []entities.ScreeningReview, mockError error) {
+				m.On("ListReviews", mock.Anything, "analyst@gmail.com", "").Return(mockResponse, mockError)
+			},
+			endpointRequest: ListScreeningReviewsRequest{Email: "analyst@gmail.com"},
+			expectedOutput:  ListScreeningReviewsResponse{Reviews: []entities.ScreeningReview{{ID: "r1", Status: entities.ReviewPending}}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeListScreeningReviewsEndpoint with error Interface type wrong",
+			mock:            &screeningServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  ListScreeningReviewsResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:     "test MakeListScreeningReviewsEndpoint with error in the service",
+			mock:         &screeningServiceMock{},
+			mockResponse: []entities.ScreeningReview(nil),
+			mockError:    services.ErrAnalystRequired,
+			configureMock: func(m *screeningServiceMock, mockResponse []entities.ScreeningReview, mockError error) {
+				m.On("ListReviews", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: ListScreeningReviewsRequest{Email: "alexer@gmail.com"},
+			expectedOutput:  ListScreeningReviewsResponse{},
+			expectedError:   services.ErrAnalystRequired,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeListScreeningReviewsEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeReleaseScreeningReviewEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *screeningServiceMock
+		mockResponse    entities.ScreeningReview
+		mockError       error
+		configureMock   func(*screeningServiceMock, entities.ScreeningReview, error)
+		endpointRequest interface{}
+		expectedOutput  ScreeningReviewResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeReleaseScreeningReviewEndpoint",
+			mock:         &screeningServiceMock{},
+			mockResponse: entities.ScreeningReview{ID: "r1", Status: entities.ReviewReleased},
+			configureMock: func(m *screeningServiceMock, mockResponse entities.ScreeningReview, mockError error) {
+				m.On("ReleaseReview", mock.Anything, "analyst@gmail.com", "r1", "ok").Return(mockResponse, mockError)
+			},
+			endpointRequest: ScreeningReviewRequest{Email: "analyst@gmail.com", ID: "r1", Note: "ok"},
+			expectedOutput:  ScreeningReviewResponse{Review: entities.ScreeningReview{ID: "r1", Status: entities.ReviewReleased}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeReleaseScreeningReviewEndpoint with error Interface type wrong",
+			mock:            &screeningServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  ScreeningReviewResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeReleaseScreeningReviewEndpoint with error in the service",
+			mock:      &screeningServiceMock{},
+			mockError: services.ErrReviewState,
+			configureMock: func(m *screeningServiceMock, mockResponse entities.ScreeningReview, mockError error) {
+				m.On("ReleaseReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: ScreeningReviewRequest{Email: "analyst@gmail.com", ID: "r1"},
+			expectedOutput:  ScreeningReviewResponse{},
+			expectedError:   services.ErrReviewState,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeReleaseScreeningReviewEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
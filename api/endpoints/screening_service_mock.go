@@ -0,0 +1,37 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type screeningServiceMock struct {
+	mock.Mock
+}
+
+func (s *screeningServiceMock) ListRules(ctx context.Context, email string) ([]entities.ScreeningRule, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.ScreeningRule), r.Error(1)
+}
+
+func (s *screeningServiceMock) SaveRule(ctx context.Context, email string, rule entities.ScreeningRule) (entities.ScreeningRule, error) {
+	r := s.Called(ctx, email, rule)
+	return r.Get(0).(entities.ScreeningRule), r.Error(1)
+}
+
+func (s *screeningServiceMock) ListReviews(ctx context.Context, email string, status string) ([]entities.ScreeningReview, error) {
+	r := s.Called(ctx, email, status)
+	return r.Get(0).([]entities.ScreeningReview), r.Error(1)
+}
+
+func (s *screeningServiceMock) ReleaseReview(ctx context.Context, email string, id string, note string) (entities.ScreeningReview, error) {
+	r := s.Called(ctx, email, id, note)
+	return r.Get(0).(entities.ScreeningReview), r.Error(1)
+}
+
+func (s *screeningServiceMock) RejectReview(ctx context.Context, email string, id string, note string) (entities.ScreeningReview, error) {
+	r := s.Called(ctx, email, id, note)
+	return r.Get(0).(entities.ScreeningReview), r.Error(1)
+}
@@ -0,0 +1,156 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// SplitParticipantRequest names one participant of a split
+// @Description Exactly one of email, phone or DNI identifies the participant
+type SplitParticipantRequest struct {
+	// @example "friend@gmail.com"
+	Email string `json:"email,omitempty"` // Participant's email
+	// @example 3017942380
+	Phone int `json:"phone,omitempty"` // Participant's phone number
+	// @example 1002842747
+	DNI int `json:"dni,omitempty"` // Participant's DNI
+	// @example 2
+	Shares int64 `json:"shares,omitempty"` // Shares, for the shares method
+	// @example 30000
+	Amount int64 `json:"amount,omitempty"` // Amount in cents, for the exact method
+}
+
+// CreateSplitRequest represents the request to split a bill
+// @Description The owner may list themselves as a participant to keep a part of the bill
+type CreateSplitRequest struct {
+	Email string `json:"-"` // Email of the authenticated owner
+	// @example "Dinner at Andres"
+	Description string `json:"description"` // Description sent with every payment request
+	// @example 100000
+	Amount int64 `json:"amount"` // Total amount in cents
+	// @example "COP"
+	Currency string `json:"currency,omitempty"` // Currency, COP when empty
+	// @example "even"
+	Method       string                    `json:"method"`       // even, shares or exact
+	Participants []SplitParticipantRequest `json:"participants"` // Participants of the split
+}
+
+// SplitResponse represents a split
+// @Description Response with a split and the settlement status of every participant
+type SplitResponse struct {
+	Split entities.Split `json:"split"`           // Split
+	Err   string         `json:"error,omitempty"` // Error message, if any
+}
+
+// ListSplitsRequest represents the request to list the user's splits
+type ListSplitsRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+}
+
+// ListSplitsResponse represents a list of splits
+// @Description Response with the splits the user owns or takes part in, newest first
+type ListSplitsResponse struct {
+	Splits []entities.Split `json:"splits"`          // Splits
+	Err    string           `json:"error,omitempty"` // Error message, if any
+}
+
+// GetSplitRequest represents the request to get a split
+type GetSplitRequest struct {
+	Email string `json:"-"` // Email of the authenticated user
+	ID    string `json:"-"` // Split ID
+}
+
+// @Summary Create Split
+// @Description Divides a bill among participants and sends each one a payment request
+// @Accept json
+// @Produce json
+// @Param request body CreateSplitRequest true "Split"
+// @Success 201 {object} SplitResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /splits [post]
+func MakeCreateSplitEndpoint(s services.SplitService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateSplitRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateSplitRequest); !ok {
+			logger.Errorln("Layer:split_endpoint", "Method:MakeCreateSplitEndpoint", ErrInterfaceWrong)
+			return SplitResponse{}, ErrInterfaceWrong
+		}
+		split := entities.Split{
+			Description: req.Description,
+			Amount:      req.Amount,
+			Currency:    req.Currency,
+			Method:      req.Method,
+		}
+		for _, participant := range req.Participants {
+			split.Participants = append(split.Participants, entities.SplitParticipant{
+				Email:  participant.Email,
+				Phone:  participant.Phone,
+				DNI:    participant.DNI,
+				Shares: participant.Shares,
+				Amount: participant.Amount,
+			})
+		}
+		split, err = s.CreateSplit(ctx, req.Email, split)
+		if err != nil {
+			logger.Errorln("Layer:split_endpoint", "Method:MakeCreateSplitEndpoint", err)
+			return SplitResponse{}, err
+		}
+		return SplitResponse{Split: split}, nil
+	}
+}
+
+// @Summary List Splits
+// @Description Lists the splits the authenticated user owns or takes part in
+// @Produce json
+// @Success 200 {object} ListSplitsResponse
+// @Router /splits [get]
+func MakeListSplitsEndpoint(s services.SplitService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListSplitsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListSplitsRequest); !ok {
+			logger.Errorln("Layer:split_endpoint", "Method:MakeListSplitsEndpoint", ErrInterfaceWrong)
+			return ListSplitsResponse{}, ErrInterfaceWrong
+		}
+		splits, err := s.ListSplits(ctx, req.Email)
+		if err != nil {
+			logger.Errorln("Layer:split_endpoint", "Method:MakeListSplitsEndpoint", err)
+			return ListSplitsResponse{}, err
+		}
+		return ListSplitsResponse{Splits: splits}, nil
+	}
+}
+
+// @Summary Get Split
+// @Description Gets a split with the settlement status of every participant
+// @Produce json
+// @Param id path string true "Split ID"
+// @Success 200 {object} SplitResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /splits/{id} [get]
+func MakeGetSplitEndpoint(s services.SplitService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetSplitRequest
+		var ok bool = false
+
+		if req, ok = request.(GetSplitRequest); !ok {
+			logger.Errorln("Layer:split_endpoint", "Method:MakeGetSplitEndpoint", ErrInterfaceWrong)
+			return SplitResponse{}, ErrInterfaceWrong
+		}
+		split, err := s.GetSplit(ctx, req.Email, req.ID)
+		if err != nil {
+			logger.Errorln("Layer:split_endpoint", "Method:MakeGetSplitEndpoint", err)
+			return SplitResponse{}, err
+		}
+		return SplitResponse{Split: split}, nil
+	}
+}
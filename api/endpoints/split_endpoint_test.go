@@ -0,0 +1,79 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreateSplitEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *splitServiceMock
+		mockResponse    entities.Split
+		mockError       error
+		configureMock   func(*splitServiceMock, entities.Split, error)
+		endpointRequest interface{}
+		expectedOutput  SplitResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreateSplitEndpoint",
+			mock:         &splitServiceMock{},
+			mockResponse: entities.Split{ID: "s1", Status: entities.SplitOpen},
+			configureMock: func(m *splitServiceMock, mockResponse entities.Split, mockError error) {
+				m.On("CreateSplit", mock.Anything, "alexer@gmail.com", entities.Split{
+					Description: "Dinner", Amount: 10000, Method: entities.SplitShares,
+					Participants: []entities.SplitParticipant{{Email: "ana@gmail.com", Shares: 2}, {Phone: 3017942380, Shares: 1}},
+				}).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateSplitRequest{
+				Email: "alexer@gmail.com", Description: "Dinner", Amount: 10000, Method: entities.SplitShares,
+				Participants: []SplitParticipantRequest{{Email: "ana@gmail.com", Shares: 2}, {Phone: 3017942380, Shares: 1}},
+			},
+			expectedOutput: SplitResponse{Split: entities.Split{ID: "s1", Status: entities.SplitOpen}},
+			expectedError:  nil,
+		},
+		{
+			testName:        "test MakeCreateSplitEndpoint with error Interface type wrong",
+			mock:            &splitServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  SplitResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreateSplitEndpoint with error in the service",
+			mock:      &splitServiceMock{},
+			mockError: services.ErrSplitAmountMismatch,
+			configureMock: func(m *splitServiceMock, mockResponse entities.Split, mockError error) {
+				m.On("CreateSplit", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateSplitRequest{Email: "alexer@gmail.com", Amount: 10000, Method: entities.SplitExact},
+			expectedOutput:  SplitResponse{},
+			expectedError:   services.ErrSplitAmountMismatch,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreateSplitEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
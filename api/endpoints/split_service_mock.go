@@ -0,0 +1,27 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type splitServiceMock struct {
+	mock.Mock
+}
+
+func (s *splitServiceMock) CreateSplit(ctx context.Context, email string, split entities.Split) (entities.Split, error) {
+	r := s.Called(ctx, email, split)
+	return r.Get(0).(entities.Split), r.Error(1)
+}
+
+func (s *splitServiceMock) ListSplits(ctx context.Context, email string) ([]entities.Split, error) {
+	r := s.Called(ctx, email)
+	return r.Get(0).([]entities.Split), r.Error(1)
+}
+
+func (s *splitServiceMock) GetSplit(ctx context.Context, email string, id string) (entities.Split, error) {
+	r := s.Called(ctx, email, id)
+	return r.Get(0).(entities.Split), r.Error(1)
+}
@@ -0,0 +1,79 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateTransferRequest represents the request to send money to another user
+// @Description Exactly one of recipient email, phone, DNI or beneficiary ID identifies the recipient
+type CreateTransferRequest struct {
+	SenderEmail string `json:"-"` // Email of the authenticated sender
+	DeviceID    string `json:"-"` // Device the transfer is sent from, for screening
+	// @example "65f1c0a2b3d4e5f6a7b8c9d1"
+	WalletID string `json:"wallet_id,omitempty"` // Shared wallet to send from, the sender's own wallet when empty
+	// @example "friend@gmail.com"
+	RecipientEmail string `json:"recipient_email,omitempty"` // Recipient's email
+	// @example 3017942380
+	RecipientPhone int `json:"recipient_phone,omitempty"` // Recipient's phone number
+	// @example 1002842747
+	RecipientDNI int `json:"recipient_dni,omitempty"` // Recipient's DNI
+	// @example "65f1c0a2b3d4e5f6a7b8c9d0"
+	BeneficiaryID string `json:"beneficiary_id,omitempty"` // Saved wallet beneficiary
+	// @example 150000
+	Amount int64 `json:"amount"` // Amount in cents
+	// @example "COP"
+	Currency string `json:"currency,omitempty"` // Currency, the sender's default when empty
+	// @example "Dinner"
+	Memo string `json:"memo,omitempty"` // Optional note for the recipient
+}
+
+// CreateTransferResponse represents the response when a transfer is posted
+// @Description Response when a transfer is completed
+type CreateTransferResponse struct {
+	Transfer entities.Transfer `json:"transfer"`        // Posted transfer
+	Err      string            `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Create Transfer
+// @Description Sends money from the authenticated user, or from a shared wallet they can spend from, to another wallet user
+// @Accept json
+// @Produce json
+// @Param transfer body CreateTransferRequest true "Transfer"
+// @Success 201 {object} CreateTransferResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /transfers [post]
+func MakeCreateTransferEndpoint(s services.TransferService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateTransferRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateTransferRequest); !ok {
+			logger.Errorln("Layer:transfer_endpoint", "Method:MakeCreateTransferEndpoint", ErrInterfaceWrong)
+			return CreateTransferResponse{}, ErrInterfaceWrong
+		}
+		transfer := entities.Transfer{
+			RecipientEmail: req.RecipientEmail,
+			RecipientPhone: req.RecipientPhone,
+			RecipientDNI:   req.RecipientDNI,
+			BeneficiaryID:  req.BeneficiaryID,
+			Amount:         req.Amount,
+			Currency:       req.Currency,
+			Memo:           req.Memo,
+			DeviceID:       req.DeviceID,
+			SenderWalletID: req.WalletID,
+		}
+		transfer, err = s.CreateTransfer(ctx, req.SenderEmail, transfer)
+		if err != nil {
+			logger.Errorln("Layer:transfer_endpoint", "Method:MakeCreateTransferEndpoint", err)
+			return CreateTransferResponse{}, err
+		}
+		return CreateTransferResponse{Transfer: transfer}, nil
+	}
+}
@@ -0,0 +1,73 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreateTransferEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *transferServiceMock
+		mockResponse    entities.Transfer
+		mockError       error
+		configureMock   func(*transferServiceMock, entities.Transfer, error)
+		endpointRequest interface{}
+		expectedOutput  CreateTransferResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreateTransferEndpoint",
+			mock:         &transferServiceMock{},
+			mockResponse: entities.Transfer{ID: "5", Amount: 100, Status: entities.StatusCompleted},
+			configureMock: func(m *transferServiceMock, mockResponse entities.Transfer, mockError error) {
+				m.On("CreateTransfer", mock.Anything, "alexer@gmail.com", entities.Transfer{RecipientEmail: "friend@gmail.com", Amount: 100}).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateTransferRequest{SenderEmail: "alexer@gmail.com", RecipientEmail: "friend@gmail.com", Amount: 100},
+			expectedOutput:  CreateTransferResponse{Transfer: entities.Transfer{ID: "5", Amount: 100, Status: entities.StatusCompleted}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreateTransferEndpoint with error Interface type wrong",
+			mock:            &transferServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  CreateTransferResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreateTransferEndpoint with error in the service",
+			mock:      &transferServiceMock{},
+			mockError: services.ErrInsufficientFunds,
+			configureMock: func(m *transferServiceMock, mockResponse entities.Transfer, mockError error) {
+				m.On("CreateTransfer", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateTransferRequest{SenderEmail: "alexer@gmail.com", RecipientDNI: 34, Amount: 100},
+			expectedOutput:  CreateTransferResponse{},
+			expectedError:   services.ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreateTransferEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
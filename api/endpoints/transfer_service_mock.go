@@ -0,0 +1,17 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type transferServiceMock struct {
+	mock.Mock
+}
+
+func (s *transferServiceMock) CreateTransfer(ctx context.Context, senderEmail string, transfer entities.Transfer) (entities.Transfer, error) {
+	r := s.Called(ctx, senderEmail, transfer)
+	return r.Get(0).(entities.Transfer), r.Error(1)
+}
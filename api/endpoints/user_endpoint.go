@@ -119,24 +119,220 @@ type SoftDeleteUserResponse struct {
 }
 
 type Endpoints struct {
-	CreateUser     endpoint.Endpoint
-	GetUser        endpoint.Endpoint
-	DeleteUser     endpoint.Endpoint
-	UpdateUser     endpoint.Endpoint
-	SoftDeleteUser endpoint.Endpoint
-	Login          endpoint.Endpoint
-	HealthCheck    endpoint.Endpoint
+	CreateUser                        endpoint.Endpoint
+	GetUser                           endpoint.Endpoint
+	DeleteUser                        endpoint.Endpoint
+	UpdateUser                        endpoint.Endpoint
+	SoftDeleteUser                    endpoint.Endpoint
+	Login                             endpoint.Endpoint
+	HealthCheck                       endpoint.Endpoint
+	CreateTransfer                    endpoint.Endpoint
+	ListTransactions                  endpoint.Endpoint
+	GetStatement                      endpoint.Endpoint
+	CreateQuote                       endpoint.Endpoint
+	CreateConversion                  endpoint.Endpoint
+	QuoteFee                          endpoint.Endpoint
+	ListFeeRules                      endpoint.Endpoint
+	CreateFeeRule                     endpoint.Endpoint
+	UpdateFeeRule                     endpoint.Endpoint
+	DeleteFeeRule                     endpoint.Endpoint
+	CreateSchedule                    endpoint.Endpoint
+	ListSchedules                     endpoint.Endpoint
+	GetSchedule                       endpoint.Endpoint
+	CancelSchedule                    endpoint.Endpoint
+	ListExecutions                    endpoint.Endpoint
+	CreatePaymentRequest              endpoint.Endpoint
+	ListPaymentRequests               endpoint.Endpoint
+	AcceptPaymentRequest              endpoint.Endpoint
+	DeclinePaymentRequest             endpoint.Endpoint
+	CancelPaymentRequest              endpoint.Endpoint
+	CreateSplit                       endpoint.Endpoint
+	ListSplits                        endpoint.Endpoint
+	GetSplit                          endpoint.Endpoint
+	CreatePocket                      endpoint.Endpoint
+	ListPockets                       endpoint.Endpoint
+	GetPocket                         endpoint.Endpoint
+	UpdatePocket                      endpoint.Endpoint
+	ClosePocket                       endpoint.Endpoint
+	MoveToPocket                      endpoint.Endpoint
+	MoveFromPocket                    endpoint.Endpoint
+	ListCategories                    endpoint.Endpoint
+	CreateCategory                    endpoint.Endpoint
+	ListCategoryRules                 endpoint.Endpoint
+	CreateCategoryRule                endpoint.Endpoint
+	DeleteCategoryRule                endpoint.Endpoint
+	Recategorize                      endpoint.Endpoint
+	CreateBudget                      endpoint.Endpoint
+	ListBudgets                       endpoint.Endpoint
+	UpdateBudget                      endpoint.Endpoint
+	DeleteBudget                      endpoint.Endpoint
+	GetBudgetStatus                   endpoint.Endpoint
+	ListNotifications                 endpoint.Endpoint
+	MarkNotificationRead              endpoint.Endpoint
+	GetInsights                       endpoint.Endpoint
+	GetLimitsEndpoint                 endpoint.Endpoint
+	GetKYCEndpoint                    endpoint.Endpoint
+	CreateKYCApplicationEndpoint      endpoint.Endpoint
+	ListKYCApplicationsEndpoint       endpoint.Endpoint
+	UploadKYCDocumentEndpoint         endpoint.Endpoint
+	GetKYCDocumentEndpoint            endpoint.Endpoint
+	SubmitKYCApplicationEndpoint      endpoint.Endpoint
+	ReviewKYCApplicationEndpoint      endpoint.Endpoint
+	ListKYCEventsEndpoint             endpoint.Endpoint
+	ListScreeningRulesEndpoint        endpoint.Endpoint
+	SaveScreeningRuleEndpoint         endpoint.Endpoint
+	ListScreeningReviewsEndpoint      endpoint.Endpoint
+	ReleaseScreeningReviewEndpoint    endpoint.Endpoint
+	RejectScreeningReviewEndpoint     endpoint.Endpoint
+	ListSanctionsScreeningsEndpoint   endpoint.Endpoint
+	ClearSanctionsScreeningEndpoint   endpoint.Endpoint
+	ConfirmSanctionsScreeningEndpoint endpoint.Endpoint
+	OpenDisputeEndpoint               endpoint.Endpoint
+	ListDisputesEndpoint              endpoint.Endpoint
+	ListDisputeQueueEndpoint          endpoint.Endpoint
+	GetDisputeEndpoint                endpoint.Endpoint
+	AddDisputeEvidenceEndpoint        endpoint.Endpoint
+	GetDisputeEvidenceEndpoint        endpoint.Endpoint
+	ReviewDisputeEndpoint             endpoint.Endpoint
+	ResolveDisputeEndpoint            endpoint.Endpoint
+	CreateBeneficiaryEndpoint         endpoint.Endpoint
+	ListBeneficiariesEndpoint         endpoint.Endpoint
+	UpdateBeneficiaryEndpoint         endpoint.Endpoint
+	DeleteBeneficiaryEndpoint         endpoint.Endpoint
+	CreateWithdrawalEndpoint          endpoint.Endpoint
+	CreateMerchantEndpoint            endpoint.Endpoint
+	GetMerchantEndpoint               endpoint.Endpoint
+	CreateQRCodeEndpoint              endpoint.Endpoint
+	PayQRCodeEndpoint                 endpoint.Endpoint
+	GetSettlementReportEndpoint       endpoint.Endpoint
+	PlaceRestrictionEndpoint          endpoint.Endpoint
+	ListRestrictionsEndpoint          endpoint.Endpoint
+	LiftRestrictionEndpoint           endpoint.Endpoint
+	ListReconciliationsEndpoint       endpoint.Endpoint
+	GetReconciliationEndpoint         endpoint.Endpoint
+	GetInterestEndpoint               endpoint.Endpoint
+	AccrueInterestEndpoint            endpoint.Endpoint
+	IssueCardEndpoint                 endpoint.Endpoint
+	ListCardsEndpoint                 endpoint.Endpoint
+	UpdateCardLimitsEndpoint          endpoint.Endpoint
+	FreezeCardEndpoint                endpoint.Endpoint
+	ListCardAuthorizationsEndpoint    endpoint.Endpoint
+	CardNetworkMessageEndpoint        endpoint.Endpoint
+	InviteMemberEndpoint              endpoint.Endpoint
+	ListMembersEndpoint               endpoint.Endpoint
+	UpdateMemberEndpoint              endpoint.Endpoint
+	RemoveMemberEndpoint              endpoint.Endpoint
+	ListMembershipsEndpoint           endpoint.Endpoint
+	RespondInvitationEndpoint         endpoint.Endpoint
 }
 
-func MakeServerEndpoints(s services.UserService, h infraestructure_services.HealtcheckService, logger logrus.FieldLogger) Endpoints {
+func MakeServerEndpoints(s services.UserService, h infraestructure_services.HealtcheckService, t services.TransferService, i services.IdempotencyService, w services.WalletService, f services.FXService, fee services.FeeService, sch services.ScheduleService, pr services.PaymentRequestService, sp services.SplitService, pk services.PocketService, cat services.CategoryService, b services.BudgetService, n services.NotificationService, in services.InsightsService, lim services.LimitService, kyc services.KYCService, scr services.ScreeningService, sanc services.SanctionsService, dis services.DisputeService, ben services.BeneficiaryService, wd services.WithdrawalService, mer services.MerchantService, res services.RestrictionService, rec services.ReconciliationService, intr services.InterestService, card services.CardService, member services.MemberService, logger logrus.FieldLogger) Endpoints {
 	return Endpoints{
-		CreateUser:     MakeCreateUserEndpoint(s, logger),
-		GetUser:        MakeGetUserEndpoint(s, logger),
-		DeleteUser:     MakeDeleteUserEndpoint(s, logger),
-		UpdateUser:     MakeUpdateUserEndpoint(s, logger),
-		SoftDeleteUser: MakeSoftDeleteUserEndpoint(s, logger),
-		Login:          MakeLoginEndpoint(s, logger),
-		HealthCheck:    MakeGetHealthCheckEndpoint(h, logger),
+		CreateUser:                        MakeCreateUserEndpoint(s, logger),
+		GetUser:                           MakeGetUserEndpoint(s, logger),
+		DeleteUser:                        MakeDeleteUserEndpoint(s, logger),
+		UpdateUser:                        MakeUpdateUserEndpoint(s, logger),
+		SoftDeleteUser:                    MakeSoftDeleteUserEndpoint(s, logger),
+		Login:                             MakeLoginEndpoint(s, logger),
+		HealthCheck:                       MakeGetHealthCheckEndpoint(h, logger),
+		CreateTransfer:                    IdempotencyMiddleware("CreateTransfer", i, logger)(MakeCreateTransferEndpoint(t, logger)),
+		ListTransactions:                  MakeListTransactionsEndpoint(w, logger),
+		GetStatement:                      MakeGetStatementEndpoint(w, logger),
+		CreateQuote:                       MakeCreateQuoteEndpoint(f, logger),
+		CreateConversion:                  IdempotencyMiddleware("CreateConversion", i, logger)(MakeCreateConversionEndpoint(f, logger)),
+		QuoteFee:                          MakeQuoteFeeEndpoint(fee, logger),
+		ListFeeRules:                      MakeListFeeRulesEndpoint(fee, logger),
+		CreateFeeRule:                     MakeCreateFeeRuleEndpoint(fee, logger),
+		UpdateFeeRule:                     MakeUpdateFeeRuleEndpoint(fee, logger),
+		DeleteFeeRule:                     MakeDeleteFeeRuleEndpoint(fee, logger),
+		CreateSchedule:                    MakeCreateScheduleEndpoint(sch, logger),
+		ListSchedules:                     MakeListSchedulesEndpoint(sch, logger),
+		GetSchedule:                       MakeGetScheduleEndpoint(sch, logger),
+		CancelSchedule:                    MakeCancelScheduleEndpoint(sch, logger),
+		ListExecutions:                    MakeListExecutionsEndpoint(sch, logger),
+		CreatePaymentRequest:              MakeCreatePaymentRequestEndpoint(pr, logger),
+		ListPaymentRequests:               MakeListPaymentRequestsEndpoint(pr, logger),
+		AcceptPaymentRequest:              IdempotencyMiddleware("AcceptPaymentRequest", i, logger)(MakeAcceptPaymentRequestEndpoint(pr, logger)),
+		DeclinePaymentRequest:             MakeDeclinePaymentRequestEndpoint(pr, logger),
+		CancelPaymentRequest:              MakeCancelPaymentRequestEndpoint(pr, logger),
+		CreateSplit:                       IdempotencyMiddleware("CreateSplit", i, logger)(MakeCreateSplitEndpoint(sp, logger)),
+		ListSplits:                        MakeListSplitsEndpoint(sp, logger),
+		GetSplit:                          MakeGetSplitEndpoint(sp, logger),
+		CreatePocket:                      MakeCreatePocketEndpoint(pk, logger),
+		ListPockets:                       MakeListPocketsEndpoint(pk, logger),
+		GetPocket:                         MakeGetPocketEndpoint(pk, logger),
+		UpdatePocket:                      MakeUpdatePocketEndpoint(pk, logger),
+		ClosePocket:                       MakeClosePocketEndpoint(pk, logger),
+		MoveToPocket:                      IdempotencyMiddleware("MoveToPocket", i, logger)(MakeMoveToPocketEndpoint(pk, logger)),
+		MoveFromPocket:                    IdempotencyMiddleware("MoveFromPocket", i, logger)(MakeMoveFromPocketEndpoint(pk, logger)),
+		ListCategories:                    MakeListCategoriesEndpoint(cat, logger),
+		CreateCategory:                    MakeCreateCategoryEndpoint(cat, logger),
+		ListCategoryRules:                 MakeListCategoryRulesEndpoint(cat, logger),
+		CreateCategoryRule:                MakeCreateCategoryRuleEndpoint(cat, logger),
+		DeleteCategoryRule:                MakeDeleteCategoryRuleEndpoint(cat, logger),
+		Recategorize:                      MakeRecategorizeEndpoint(cat, logger),
+		CreateBudget:                      MakeCreateBudgetEndpoint(b, logger),
+		ListBudgets:                       MakeListBudgetsEndpoint(b, logger),
+		UpdateBudget:                      MakeUpdateBudgetEndpoint(b, logger),
+		DeleteBudget:                      MakeDeleteBudgetEndpoint(b, logger),
+		GetBudgetStatus:                   MakeGetBudgetStatusEndpoint(b, logger),
+		ListNotifications:                 MakeListNotificationsEndpoint(n, logger),
+		MarkNotificationRead:              MakeMarkNotificationReadEndpoint(n, logger),
+		GetInsights:                       MakeGetInsightsEndpoint(in, logger),
+		GetLimitsEndpoint:                 MakeGetLimitsEndpoint(lim, logger),
+		GetKYCEndpoint:                    MakeGetKYCEndpoint(kyc, logger),
+		CreateKYCApplicationEndpoint:      MakeCreateKYCApplicationEndpoint(kyc, logger),
+		ListKYCApplicationsEndpoint:       MakeListKYCApplicationsEndpoint(kyc, logger),
+		UploadKYCDocumentEndpoint:         MakeUploadKYCDocumentEndpoint(kyc, logger),
+		GetKYCDocumentEndpoint:            MakeGetKYCDocumentEndpoint(kyc, logger),
+		SubmitKYCApplicationEndpoint:      MakeSubmitKYCApplicationEndpoint(kyc, logger),
+		ReviewKYCApplicationEndpoint:      MakeReviewKYCApplicationEndpoint(kyc, logger),
+		ListKYCEventsEndpoint:             MakeListKYCEventsEndpoint(kyc, logger),
+		ListScreeningRulesEndpoint:        MakeListScreeningRulesEndpoint(scr, logger),
+		SaveScreeningRuleEndpoint:         MakeSaveScreeningRuleEndpoint(scr, logger),
+		ListScreeningReviewsEndpoint:      MakeListScreeningReviewsEndpoint(scr, logger),
+		ReleaseScreeningReviewEndpoint:    MakeReleaseScreeningReviewEndpoint(scr, logger),
+		RejectScreeningReviewEndpoint:     MakeRejectScreeningReviewEndpoint(scr, logger),
+		ListSanctionsScreeningsEndpoint:   MakeListSanctionsScreeningsEndpoint(sanc, logger),
+		ClearSanctionsScreeningEndpoint:   MakeClearSanctionsScreeningEndpoint(sanc, logger),
+		ConfirmSanctionsScreeningEndpoint: MakeConfirmSanctionsScreeningEndpoint(sanc, logger),
+		OpenDisputeEndpoint:               MakeOpenDisputeEndpoint(dis, logger),
+		ListDisputesEndpoint:              MakeListDisputesEndpoint(dis, logger),
+		ListDisputeQueueEndpoint:          MakeListDisputeQueueEndpoint(dis, logger),
+		GetDisputeEndpoint:                MakeGetDisputeEndpoint(dis, logger),
+		AddDisputeEvidenceEndpoint:        MakeAddDisputeEvidenceEndpoint(dis, logger),
+		GetDisputeEvidenceEndpoint:        MakeGetDisputeEvidenceEndpoint(dis, logger),
+		ReviewDisputeEndpoint:             MakeReviewDisputeEndpoint(dis, logger),
+		ResolveDisputeEndpoint:            IdempotencyMiddleware("ResolveDispute", i, logger)(MakeResolveDisputeEndpoint(dis, logger)),
+		CreateBeneficiaryEndpoint:         MakeCreateBeneficiaryEndpoint(ben, logger),
+		ListBeneficiariesEndpoint:         MakeListBeneficiariesEndpoint(ben, logger),
+		UpdateBeneficiaryEndpoint:         MakeUpdateBeneficiaryEndpoint(ben, logger),
+		DeleteBeneficiaryEndpoint:         MakeDeleteBeneficiaryEndpoint(ben, logger),
+		CreateWithdrawalEndpoint:          IdempotencyMiddleware("CreateWithdrawal", i, logger)(MakeCreateWithdrawalEndpoint(wd, logger)),
+		CreateMerchantEndpoint:            MakeCreateMerchantEndpoint(mer, logger),
+		GetMerchantEndpoint:               MakeGetMerchantEndpoint(mer, logger),
+		CreateQRCodeEndpoint:              MakeCreateQRCodeEndpoint(mer, logger),
+		PayQRCodeEndpoint:                 IdempotencyMiddleware("PayQRCode", i, logger)(MakePayQRCodeEndpoint(mer, logger)),
+		GetSettlementReportEndpoint:       MakeGetSettlementReportEndpoint(mer, logger),
+		PlaceRestrictionEndpoint:          MakePlaceRestrictionEndpoint(res, logger),
+		ListRestrictionsEndpoint:          MakeListRestrictionsEndpoint(res, logger),
+		LiftRestrictionEndpoint:           MakeLiftRestrictionEndpoint(res, logger),
+		ListReconciliationsEndpoint:       MakeListReconciliationsEndpoint(rec, logger),
+		GetReconciliationEndpoint:         MakeGetReconciliationEndpoint(rec, logger),
+		GetInterestEndpoint:               MakeGetInterestEndpoint(intr, logger),
+		AccrueInterestEndpoint:            MakeAccrueInterestEndpoint(intr, logger),
+		IssueCardEndpoint:                 MakeIssueCardEndpoint(card, logger),
+		ListCardsEndpoint:                 MakeListCardsEndpoint(card, logger),
+		UpdateCardLimitsEndpoint:          MakeUpdateCardLimitsEndpoint(card, logger),
+		FreezeCardEndpoint:                MakeFreezeCardEndpoint(card, logger),
+		ListCardAuthorizationsEndpoint:    MakeListCardAuthorizationsEndpoint(card, logger),
+		CardNetworkMessageEndpoint:        MakeCardNetworkMessageEndpoint(card, logger),
+		InviteMemberEndpoint:              MakeInviteMemberEndpoint(member, logger),
+		ListMembersEndpoint:               MakeListMembersEndpoint(member, logger),
+		UpdateMemberEndpoint:              MakeUpdateMemberEndpoint(member, logger),
+		RemoveMemberEndpoint:              MakeRemoveMemberEndpoint(member, logger),
+		ListMembershipsEndpoint:           MakeListMembershipsEndpoint(member, logger),
+		RespondInvitationEndpoint:         MakeRespondInvitationEndpoint(member, logger),
 	}
 }
 
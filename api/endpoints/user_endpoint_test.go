@@ -420,7 +420,7 @@ func TestMakeServerEndpoints(t *testing.T) {
 		t.Run(tt.testName, func(t *testing.T) {
 
 			// Act
-			result := MakeServerEndpoints(tt.mock, tt.mock, logrus.StandardLogger())
+			result := MakeServerEndpoints(tt.mock, tt.mock, &transferServiceMock{}, &idempotencyServiceMock{}, &walletServiceMock{}, &fxServiceMock{}, &feeServiceMock{}, &scheduleServiceMock{}, &paymentRequestServiceMock{}, &splitServiceMock{}, &pocketServiceMock{}, &categoryServiceMock{}, &budgetServiceMock{}, &notificationServiceMock{}, &insightsServiceMock{}, &limitServiceMock{}, &kycServiceMock{}, &screeningServiceMock{}, &sanctionsServiceMock{}, &disputeServiceMock{}, &beneficiaryServiceMock{}, &withdrawalServiceMock{}, &merchantServiceMock{}, &restrictionServiceMock{}, &reconciliationServiceMock{}, &interestServiceMock{}, &cardServiceMock{}, &memberServiceMock{}, logrus.StandardLogger())
 
 			// Assert
 			assert.NotNil(t, result.CreateUser)
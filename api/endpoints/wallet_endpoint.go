@@ -0,0 +1,128 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// ListTransactionsRequest represents the request to read the history of a wallet
+// @Description Filters are optional and combined with AND
+type ListTransactionsRequest struct {
+	Email        string    `json:"-"`                      // Email of the authenticated user
+	WalletID     string    `json:"wallet_id"`              // Wallet ID
+	Types        []string  `json:"types,omitempty"`        // deposit, withdrawal, transfer-in, transfer-out, fee
+	Currency     string    `json:"currency,omitempty"`     // Transaction currency
+	Status       string    `json:"status,omitempty"`       // Transaction status
+	MinAmount    int64     `json:"min_amount,omitempty"`   // Minimum amount in cents
+	MaxAmount    int64     `json:"max_amount,omitempty"`   // Maximum amount in cents
+	From         time.Time `json:"from,omitempty"`         // Inclusive start date
+	To           time.Time `json:"to,omitempty"`           // Exclusive end date
+	Counterparty string    `json:"counterparty,omitempty"` // Counterparty user or wallet ID
+	Category     string    `json:"category,omitempty"`     // Category slug
+	Member       string    `json:"member,omitempty"`       // User ID of the member who initiated the transaction
+	Search       string    `json:"q,omitempty"`            // Free text search on the memo
+	Cursor       string    `json:"cursor,omitempty"`       // Cursor of the next page
+	Limit        int       `json:"limit,omitempty"`        // Page size
+}
+
+// ListTransactionsResponse represents one page of the wallet history
+// @Description Transactions newest first and the cursor of the next page
+type ListTransactionsResponse struct {
+	Transactions []entities.Transaction `json:"transactions"`          // Transactions of the page
+	NextCursor   string                 `json:"next_cursor,omitempty"` // Empty on the last page
+	Err          string                 `json:"error,omitempty"`       // Error message, if any
+}
+
+// @Summary List Transactions
+// @Description Lists the transactions of a wallet with filters and cursor pagination
+// @Produce json
+// @Param id path string true "Wallet ID"
+// @Success 200 {object} ListTransactionsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /wallets/{id}/transactions [get]
+func MakeListTransactionsEndpoint(s services.WalletService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req ListTransactionsRequest
+		var ok bool = false
+
+		if req, ok = request.(ListTransactionsRequest); !ok {
+			logger.Errorln("Layer:wallet_endpoint", "Method:MakeListTransactionsEndpoint", ErrInterfaceWrong)
+			return ListTransactionsResponse{}, ErrInterfaceWrong
+		}
+		filter := entities.TransactionFilter{
+			WalletID:     req.WalletID,
+			Currency:     req.Currency,
+			Types:        req.Types,
+			Status:       req.Status,
+			MinAmount:    req.MinAmount,
+			MaxAmount:    req.MaxAmount,
+			From:         req.From,
+			To:           req.To,
+			Counterparty: req.Counterparty,
+			Category:     req.Category,
+			Member:       req.Member,
+			Search:       req.Search,
+			Limit:        req.Limit,
+		}
+		page, err := s.ListTransactions(ctx, req.Email, filter, req.Cursor)
+		if err != nil {
+			logger.Errorln("Layer:wallet_endpoint", "Method:MakeListTransactionsEndpoint", err)
+			return ListTransactionsResponse{}, err
+		}
+		return ListTransactionsResponse{Transactions: page.Transactions, NextCursor: page.NextCursor}, nil
+	}
+}
+
+// GetStatementRequest represents the request to export a wallet statement
+// @Description Period [from, to) and output format: csv, jsonl or pdf
+type GetStatementRequest struct {
+	Email    string    `json:"-"`         // Email of the authenticated user
+	WalletID string    `json:"wallet_id"` // Wallet ID
+	Currency string    `json:"currency"`  // Currency, the wallet default when empty
+	From     time.Time `json:"from"`      // Inclusive start date
+	To       time.Time `json:"to"`        // Exclusive end date
+	Format   string    `json:"format"`    // csv, jsonl or pdf
+}
+
+// GetStatementResponse carries the statement to the encoder, which streams it
+// in the requested format.
+type GetStatementResponse struct {
+	Statement entities.Statement `json:"statement"`
+	Format    string             `json:"format"`
+}
+
+// @Summary Get Statement
+// @Description Exports the statement of a wallet with opening balance, transactions and closing balance
+// @Produce text/csv,application/x-ndjson,application/pdf
+// @Param id path string true "Wallet ID"
+// @Param from query string true "Start date"
+// @Param to query string true "End date"
+// @Param currency query string false "Currency"
+// @Param format query string false "csv, jsonl or pdf"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /wallets/{id}/statements [get]
+func MakeGetStatementEndpoint(s services.WalletService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req GetStatementRequest
+		var ok bool = false
+
+		if req, ok = request.(GetStatementRequest); !ok {
+			logger.Errorln("Layer:wallet_endpoint", "Method:MakeGetStatementEndpoint", ErrInterfaceWrong)
+			return GetStatementResponse{}, ErrInterfaceWrong
+		}
+		statement, err := s.GetStatement(ctx, req.Email, req.WalletID, req.Currency, req.From, req.To)
+		if err != nil {
+			logger.Errorln("Layer:wallet_endpoint", "Method:MakeGetStatementEndpoint", err)
+			return GetStatementResponse{}, err
+		}
+		return GetStatementResponse{Statement: statement, Format: req.Format}, nil
+	}
+}
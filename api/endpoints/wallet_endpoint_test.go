@@ -0,0 +1,132 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeListTransactionsEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *walletServiceMock
+		mockResponse    entities.TransactionPage
+		mockError       error
+		configureMock   func(*walletServiceMock, entities.TransactionPage, error)
+		endpointRequest interface{}
+		expectedOutput  ListTransactionsResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeListTransactionsEndpoint",
+			mock:         &walletServiceMock{},
+			mockResponse: entities.TransactionPage{Transactions: []entities.Transaction{{ID: "1"}}, NextCursor: "next"},
+			configureMock: func(m *walletServiceMock, mockResponse entities.TransactionPage, mockError error) {
+				filter := entities.TransactionFilter{WalletID: "w1", Types: []string{"fee"}, Search: "rent", Limit: 10}
+				m.On("ListTransactions", mock.Anything, "alexer@gmail.com", filter, "abc").Return(mockResponse, mockError)
+			},
+			endpointRequest: ListTransactionsRequest{Email: "alexer@gmail.com", WalletID: "w1", Types: []string{"fee"}, Search: "rent", Cursor: "abc", Limit: 10},
+			expectedOutput:  ListTransactionsResponse{Transactions: []entities.Transaction{{ID: "1"}}, NextCursor: "next"},
+		},
+		{
+			testName:        "test MakeListTransactionsEndpoint with error Interface type wrong",
+			mock:            &walletServiceMock{},
+			endpointRequest: GetUserRequest{},
+			expectedOutput:  ListTransactionsResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeListTransactionsEndpoint with error in the service",
+			mock:      &walletServiceMock{},
+			mockError: services.ErrWalletForbidden,
+			configureMock: func(m *walletServiceMock, mockResponse entities.TransactionPage, mockError error) {
+				m.On("ListTransactions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: ListTransactionsRequest{Email: "alexer@gmail.com", WalletID: "w2"},
+			expectedOutput:  ListTransactionsResponse{},
+			expectedError:   services.ErrWalletForbidden,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeListTransactionsEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestMakeGetStatementEndpoint(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	testScenarios := []struct {
+		testName        string
+		mock            *walletServiceMock
+		configureMock   func(*walletServiceMock)
+		endpointRequest interface{}
+		expectedOutput  GetStatementResponse
+		expectedError   error
+	}{
+		{
+			testName: "test MakeGetStatementEndpoint",
+			mock:     &walletServiceMock{},
+			configureMock: func(m *walletServiceMock) {
+				m.On("GetStatement", mock.Anything, "alexer@gmail.com", "w1", "USD", from, to).Return(entities.Statement{WalletID: "w1", OpeningBalance: 10}, nil)
+			},
+			endpointRequest: GetStatementRequest{Email: "alexer@gmail.com", WalletID: "w1", Currency: "USD", From: from, To: to, Format: "pdf"},
+			expectedOutput:  GetStatementResponse{Statement: entities.Statement{WalletID: "w1", OpeningBalance: 10}, Format: "pdf"},
+		},
+		{
+			testName:        "test MakeGetStatementEndpoint with error Interface type wrong",
+			mock:            &walletServiceMock{},
+			endpointRequest: GetUserRequest{},
+			expectedOutput:  GetStatementResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName: "test MakeGetStatementEndpoint with error in the service",
+			mock:     &walletServiceMock{},
+			configureMock: func(m *walletServiceMock) {
+				m.On("GetStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(entities.Statement{}, services.ErrInvalidStatementPeriod)
+			},
+			endpointRequest: GetStatementRequest{Email: "alexer@gmail.com", WalletID: "w1", Format: "csv"},
+			expectedOutput:  GetStatementResponse{},
+			expectedError:   services.ErrInvalidStatementPeriod,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock)
+			}
+
+			// Act
+			result, err := MakeGetStatementEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type walletServiceMock struct {
+	mock.Mock
+}
+
+func (s *walletServiceMock) ListTransactions(ctx context.Context, email string, filter entities.TransactionFilter, cursor string) (entities.TransactionPage, error) {
+	r := s.Called(ctx, email, filter, cursor)
+	return r.Get(0).(entities.TransactionPage), r.Error(1)
+}
+
+func (s *walletServiceMock) GetStatement(ctx context.Context, email string, walletID string, currency string, from time.Time, to time.Time) (entities.Statement, error) {
+	r := s.Called(ctx, email, walletID, currency, from, to)
+	return r.Get(0).(entities.Statement), r.Error(1)
+}
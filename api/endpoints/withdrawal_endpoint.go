@@ -0,0 +1,65 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateWithdrawalRequest represents the request to pay money out to a bank account
+type CreateWithdrawalRequest struct {
+	Email    string `json:"-"` // Email of the authenticated user
+	DeviceID string `json:"-"` // Device the withdrawal is sent from, for screening
+	// @example "65f1c0a2b3d4e5f6a7b8c9d0"
+	BeneficiaryID string `json:"beneficiary_id"` // Saved bank beneficiary
+	// @example 150000
+	Amount int64 `json:"amount"` // Amount in cents
+	// @example "COP"
+	Currency string `json:"currency,omitempty"` // Currency, the wallet's default when empty
+	// @example "Rent"
+	Memo string `json:"memo,omitempty"` // Optional note
+}
+
+// CreateWithdrawalResponse represents the response when a withdrawal is posted
+type CreateWithdrawalResponse struct {
+	Withdrawal entities.Withdrawal `json:"withdrawal"`      // Posted withdrawal
+	Err        string              `json:"error,omitempty"` // Error message, if any
+}
+
+// @Summary Create Withdrawal
+// @Description Pays money out of the wallet of the authenticated user to the bank account of a beneficiary
+// @Accept json
+// @Produce json
+// @Param withdrawal body CreateWithdrawalRequest true "Withdrawal"
+// @Success 201 {object} CreateWithdrawalResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /withdrawals [post]
+func MakeCreateWithdrawalEndpoint(s services.WithdrawalService, logger logrus.FieldLogger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		var req CreateWithdrawalRequest
+		var ok bool = false
+
+		if req, ok = request.(CreateWithdrawalRequest); !ok {
+			logger.Errorln("Layer:withdrawal_endpoint", "Method:MakeCreateWithdrawalEndpoint", ErrInterfaceWrong)
+			return CreateWithdrawalResponse{}, ErrInterfaceWrong
+		}
+		withdrawal, err := s.CreateWithdrawal(ctx, req.Email, entities.Withdrawal{
+			BeneficiaryID: req.BeneficiaryID,
+			Amount:        req.Amount,
+			Currency:      req.Currency,
+			Memo:          req.Memo,
+			DeviceID:      req.DeviceID,
+		})
+		if err != nil {
+			logger.Errorln("Layer:withdrawal_endpoint", "Method:MakeCreateWithdrawalEndpoint", err)
+			return CreateWithdrawalResponse{}, err
+		}
+		return CreateWithdrawalResponse{Withdrawal: withdrawal}, nil
+	}
+}
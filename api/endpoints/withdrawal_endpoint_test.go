@@ -0,0 +1,73 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMakeCreateWithdrawalEndpoint(t *testing.T) {
+
+	testScenarios := []struct {
+		testName        string
+		mock            *withdrawalServiceMock
+		mockResponse    entities.Withdrawal
+		mockError       error
+		configureMock   func(*withdrawalServiceMock, entities.Withdrawal, error)
+		endpointRequest interface{}
+		expectedOutput  CreateWithdrawalResponse
+		expectedError   error
+	}{
+		{
+			testName:     "test MakeCreateWithdrawalEndpoint",
+			mock:         &withdrawalServiceMock{},
+			mockResponse: entities.Withdrawal{ID: "e1", BeneficiaryID: "b1", Amount: 150000, Currency: "COP", Status: entities.StatusCompleted},
+			configureMock: func(m *withdrawalServiceMock, mockResponse entities.Withdrawal, mockError error) {
+				m.On("CreateWithdrawal", mock.Anything, "alexer@gmail.com", entities.Withdrawal{BeneficiaryID: "b1", Amount: 150000}).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateWithdrawalRequest{Email: "alexer@gmail.com", BeneficiaryID: "b1", Amount: 150000},
+			expectedOutput:  CreateWithdrawalResponse{Withdrawal: entities.Withdrawal{ID: "e1", BeneficiaryID: "b1", Amount: 150000, Currency: "COP", Status: entities.StatusCompleted}},
+			expectedError:   nil,
+		},
+		{
+			testName:        "test MakeCreateWithdrawalEndpoint with error Interface type wrong",
+			mock:            &withdrawalServiceMock{},
+			endpointRequest: CreateUserRequest{},
+			expectedOutput:  CreateWithdrawalResponse{},
+			expectedError:   ErrInterfaceWrong,
+		},
+		{
+			testName:  "test MakeCreateWithdrawalEndpoint with error in the service",
+			mock:      &withdrawalServiceMock{},
+			mockError: services.ErrBeneficiaryCoolingOff,
+			configureMock: func(m *withdrawalServiceMock, mockResponse entities.Withdrawal, mockError error) {
+				m.On("CreateWithdrawal", mock.Anything, mock.Anything, mock.Anything).Return(mockResponse, mockError)
+			},
+			endpointRequest: CreateWithdrawalRequest{Email: "alexer@gmail.com", BeneficiaryID: "b1", Amount: 150000},
+			expectedOutput:  CreateWithdrawalResponse{},
+			expectedError:   services.ErrBeneficiaryCoolingOff,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+
+			// Prepare
+			if tt.configureMock != nil {
+				tt.configureMock(tt.mock, tt.mockResponse, tt.mockError)
+			}
+
+			// Act
+			result, err := MakeCreateWithdrawalEndpoint(tt.mock, logrus.StandardLogger())(context.TODO(), tt.endpointRequest)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
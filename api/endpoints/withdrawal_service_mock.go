@@ -0,0 +1,17 @@
+package endpoints
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type withdrawalServiceMock struct {
+	mock.Mock
+}
+
+func (s *withdrawalServiceMock) CreateWithdrawal(ctx context.Context, email string, withdrawal entities.Withdrawal) (entities.Withdrawal, error) {
+	r := s.Called(ctx, email, withdrawal)
+	return r.Get(0).(entities.Withdrawal), r.Error(1)
+}
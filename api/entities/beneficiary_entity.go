@@ -0,0 +1,89 @@
+package entities
+
+import "time"
+
+// Beneficiary types: another user of the wallet or an external bank account.
+const (
+	BeneficiaryWallet = "wallet"
+	BeneficiaryBank   = "bank"
+)
+
+// Bank account types.
+const (
+	BankSavings  = "savings"
+	BankChecking = "checking"
+)
+
+// Banks are the banks withdrawals can be paid out to, by ACH code.
+var Banks = map[string]string{
+	"1001": "Banco de Bogotá",
+	"1002": "Banco Popular",
+	"1007": "Bancolombia",
+	"1013": "BBVA Colombia",
+	"1019": "Scotiabank Colpatria",
+	"1023": "Banco de Occidente",
+	"1040": "Banco Agrario",
+	"1051": "Davivienda",
+	"1052": "Banco AV Villas",
+	"1507": "Nequi",
+	"1551": "Daviplata",
+}
+
+// BankAccount is an account at an external bank.
+type BankAccount struct {
+	BankCode      string `json:"bank_code" bson:"bank_code"`
+	BankName      string `json:"bank_name,omitempty" bson:"bank_name,omitempty"`
+	AccountType   string `json:"account_type" bson:"account_type"`
+	AccountNumber string `json:"account_number" bson:"account_number"`
+	HolderName    string `json:"holder_name" bson:"holder_name"`
+	HolderDNI     int    `json:"holder_dni,omitempty" bson:"holder_dni,omitempty"`
+}
+
+// Beneficiary is a recipient a user saved to send money to again. Until
+// CoolingOff_until, transfers and withdrawals to it are limited to small
+// amounts, so a stolen session cannot add a beneficiary and drain the
+// wallet right away.
+type Beneficiary struct {
+	ID               string       `json:"id" bson:"_id,omitempty"`
+	UserID           string       `json:"user_id" bson:"user_id"`
+	Type             string       `json:"type" bson:"type"`
+	Nickname         string       `json:"nickname" bson:"nickname"`
+	Favorite         bool         `json:"favorite" bson:"favorite"`
+	RecipientUserID  string       `json:"recipient_user_id,omitempty" bson:"recipient_user_id,omitempty"`
+	RecipientName    string       `json:"recipient_name,omitempty" bson:"recipient_name,omitempty"`
+	Bank             *BankAccount `json:"bank,omitempty" bson:"bank,omitempty"`
+	Target           string       `json:"-" bson:"target"`
+	CoolingOff_until time.Time    `json:"cooling_off_until" bson:"cooling_off_until"`
+	Last_used_at     *time.Time   `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	Created_at       time.Time    `json:"created_at" bson:"created_at"`
+	Update_at        time.Time    `json:"updated_at" bson:"updated_at"`
+}
+
+// Withdrawal is money paid out from a wallet to the bank account of a
+// beneficiary.
+type Withdrawal struct {
+	ID            string      `json:"id"`
+	UserID        string      `json:"user_id"`
+	WalletID      string      `json:"wallet_id"`
+	BeneficiaryID string      `json:"beneficiary_id"`
+	Bank          BankAccount `json:"bank"`
+	Amount        int64       `json:"amount"`
+	Fee           int64       `json:"fee"`
+	Currency      string      `json:"currency"`
+	Memo          string      `json:"memo,omitempty"`
+	Status        string      `json:"status"`
+	DeviceID      string      `json:"-"`
+	Created_at    time.Time   `json:"created_at"`
+}
+
+// NewBeneficiary is what a user gives to save a beneficiary: exactly one of
+// recipient email, phone or DNI for a wallet user, or the bank account.
+type NewBeneficiary struct {
+	Type           string       `json:"type"`
+	Nickname       string       `json:"nickname,omitempty"`
+	Favorite       bool         `json:"favorite,omitempty"`
+	RecipientEmail string       `json:"recipient_email,omitempty"`
+	RecipientPhone int          `json:"recipient_phone,omitempty"`
+	RecipientDNI   int          `json:"recipient_dni,omitempty"`
+	Bank           *BankAccount `json:"bank,omitempty"`
+}
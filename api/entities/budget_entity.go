@@ -0,0 +1,45 @@
+package entities
+
+import "time"
+
+// BudgetThresholds are the percentages of the limit at which a budget alert
+// is sent, at most once each per month.
+var BudgetThresholds = []int{50, 80, 100}
+
+// Budget is a monthly spending limit for a category of a wallet. Spent and
+// Alerted belong to Period, the month being tracked, and start over when the
+// first spend of a new month lands.
+type Budget struct {
+	ID         string    `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	WalletID   string    `json:"wallet_id" bson:"wallet_id"`
+	Category   string    `json:"category" bson:"category"`
+	Currency   string    `json:"currency" bson:"currency"`
+	Limit      int64     `json:"limit" bson:"limit"`
+	Period     string    `json:"period" bson:"period"`
+	Spent      int64     `json:"spent" bson:"spent"`
+	Alerted    int       `json:"alerted" bson:"alerted"`
+	Created_at time.Time `json:"created_at" bson:"created_at"`
+	Update_at  time.Time `json:"update_at" bson:"update_at"`
+}
+
+// BudgetStatus is the state of a budget in the current month.
+type BudgetStatus struct {
+	BudgetID      string `json:"budget_id"`
+	Category      string `json:"category"`
+	Currency      string `json:"currency"`
+	Period        string `json:"period"`
+	Limit         int64  `json:"limit"`
+	Spent         int64  `json:"spent"`
+	Remaining     int64  `json:"remaining"`
+	Percent       int64  `json:"percent"`
+	Projected     int64  `json:"projected"`
+	DaysLeft      int    `json:"days_left"`
+	OverBudget    bool   `json:"over_budget"`
+	ProjectedOver bool   `json:"projected_over"`
+}
+
+// BudgetPeriod returns the month a budget tracks at t, as YYYY-MM in UTC.
+func BudgetPeriod(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
@@ -0,0 +1,93 @@
+package entities
+
+import "time"
+
+// Card statuses. Frozen cards decline every authorization until the user
+// unfreezes them.
+const (
+	CardActive = "active"
+	CardFrozen = "frozen"
+)
+
+// Card authorization statuses. An authorization is pending while the issuer
+// decides on it; approved ones hold the amount in the wallet until the
+// network clears or reverses them.
+const (
+	CardAuthorizationPending  = "pending"
+	CardAuthorizationApproved = "approved"
+	CardAuthorizationDeclined = "declined"
+	CardAuthorizationCleared  = "cleared"
+	CardAuthorizationReversed = "reversed"
+)
+
+// Card is a virtual card that pays from a wallet in Currency. Its number is
+// not stored: Token stands for it and Last4 tells it apart. The card can be
+// used until the end of its expiry month, within its limits.
+type Card struct {
+	ID          string     `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID      string     `json:"user_id" bson:"user_id"`
+	WalletID    string     `json:"wallet_id" bson:"wallet_id"`
+	Name        string     `json:"name,omitempty" bson:"name,omitempty"`
+	Token       string     `json:"-" bson:"token"`
+	Last4       string     `json:"last4" bson:"last4"`
+	ExpiryMonth int        `json:"expiry_month" bson:"expiry_month"`
+	ExpiryYear  int        `json:"expiry_year" bson:"expiry_year"`
+	Currency    string     `json:"currency" bson:"currency"`
+	Status      string     `json:"status" bson:"status"`
+	Limits      CardLimits `json:"limits" bson:"limits"`
+	Created_at  time.Time  `json:"created_at" bson:"created_at"`
+	Update_at   time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// Expired tells whether the card can no longer be used at t.
+func (c Card) Expired(t time.Time) bool {
+	end := time.Date(c.ExpiryYear, time.Month(c.ExpiryMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !t.Before(end)
+}
+
+// CardLimits cap what a card can spend in its currency: in one purchase, in
+// a UTC day and in a calendar month. Zero means no limit.
+type CardLimits struct {
+	PerTransaction int64 `json:"per_transaction,omitempty" bson:"per_transaction,omitempty"`
+	Daily          int64 `json:"daily,omitempty" bson:"daily,omitempty"`
+	Monthly        int64 `json:"monthly,omitempty" bson:"monthly,omitempty"`
+}
+
+// IssuedCard is a card as it is returned when issued, the only time its
+// number is shown.
+type IssuedCard struct {
+	Card
+	PAN string `json:"pan"`
+}
+
+// CardAuthorization is a purchase the card network asked to approve with a
+// card. Code is the ISO 8583 response code the issuer answered. Approved
+// authorizations hold Amount in the wallet with the HoldEntryID ledger
+// entry; clearing settles ClearedAmount of it and gives the rest back, and
+// a reversal gives it all back. MessageID and the IDs of the clearing and
+// reversal messages are the ones of the network, so the messages it sends
+// again are answered the same way.
+type CardAuthorization struct {
+	ID                   string           `json:"id,omitempty" bson:"_id,omitempty"`
+	CardID               string           `json:"card_id" bson:"card_id"`
+	WalletID             string           `json:"wallet_id" bson:"wallet_id"`
+	UserID               string           `json:"user_id" bson:"user_id"`
+	MessageID            string           `json:"-" bson:"message_id"`
+	Amount               int64            `json:"amount" bson:"amount"`
+	Currency             string           `json:"currency" bson:"currency"`
+	MerchantName         string           `json:"merchant_name" bson:"merchant_name"`
+	MerchantCategoryCode string           `json:"merchant_category_code,omitempty" bson:"merchant_category_code,omitempty"`
+	MerchantCountry      string           `json:"merchant_country,omitempty" bson:"merchant_country,omitempty"`
+	Status               string           `json:"status" bson:"status"`
+	Code                 string           `json:"code,omitempty" bson:"code,omitempty"`
+	HoldEntryID          string           `json:"hold_entry_id,omitempty" bson:"hold_entry_id,omitempty"`
+	CardReservation      LimitReservation `json:"-" bson:"card_reservation,omitempty"`
+	LimitReservation     LimitReservation `json:"-" bson:"limit_reservation,omitempty"`
+	ClearedAmount        int64            `json:"cleared_amount,omitempty" bson:"cleared_amount,omitempty"`
+	ClearingMessageID    string           `json:"-" bson:"clearing_message_id,omitempty"`
+	ClearingEntryID      string           `json:"clearing_entry_id,omitempty" bson:"clearing_entry_id,omitempty"`
+	ReversalMessageID    string           `json:"-" bson:"reversal_message_id,omitempty"`
+	ReversalEntryID      string           `json:"reversal_entry_id,omitempty" bson:"reversal_entry_id,omitempty"`
+	Created_at           time.Time        `json:"created_at" bson:"created_at"`
+	Update_at            time.Time        `json:"updated_at" bson:"updated_at"`
+}
@@ -0,0 +1,94 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// Built-in categories, available to every user.
+const (
+	CategoryFood          = "food"
+	CategoryGroceries     = "groceries"
+	CategoryTransport     = "transport"
+	CategoryRent          = "rent"
+	CategoryUtilities     = "utilities"
+	CategoryShopping      = "shopping"
+	CategoryEntertainment = "entertainment"
+	CategoryHealth        = "health"
+	CategoryTravel        = "travel"
+	CategoryIncome        = "income"
+	CategorySavings       = "savings"
+	CategoryFees          = "fees"
+	CategoryTransfers     = "transfers"
+	CategoryOther         = "other"
+)
+
+// DefaultCategories is the built-in taxonomy. Users can add their own
+// categories next to it.
+var DefaultCategories = []Category{
+	{Slug: CategoryFood, Name: "Food and restaurants"},
+	{Slug: CategoryGroceries, Name: "Groceries"},
+	{Slug: CategoryTransport, Name: "Transport"},
+	{Slug: CategoryRent, Name: "Rent"},
+	{Slug: CategoryUtilities, Name: "Utilities"},
+	{Slug: CategoryShopping, Name: "Shopping"},
+	{Slug: CategoryEntertainment, Name: "Entertainment"},
+	{Slug: CategoryHealth, Name: "Health"},
+	{Slug: CategoryTravel, Name: "Travel"},
+	{Slug: CategoryIncome, Name: "Income"},
+	{Slug: CategorySavings, Name: "Savings"},
+	{Slug: CategoryFees, Name: "Fees"},
+	{Slug: CategoryTransfers, Name: "Transfers"},
+	{Slug: CategoryOther, Name: "Other"},
+}
+
+// Category groups transactions by what the money was spent on. Built-in
+// categories have no ID nor UserID. Transactions refer to a category by slug.
+type Category struct {
+	ID         string    `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID     string    `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Slug       string    `json:"slug" bson:"slug"`
+	Name       string    `json:"name" bson:"name"`
+	Created_at time.Time `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// CategoryRule assigns Category to the transactions of a user that match every
+// condition it sets: the counterparty, any of the memo keywords and the
+// amount range, where a zero bound is open. Learned rules were created from a
+// transaction the user re-categorized.
+type CategoryRule struct {
+	ID                 string    `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID             string    `json:"user_id" bson:"user_id"`
+	Category           string    `json:"category" bson:"category"`
+	CounterpartyUserID string    `json:"counterparty_user_id,omitempty" bson:"counterparty_user_id,omitempty"`
+	Keywords           []string  `json:"keywords,omitempty" bson:"keywords,omitempty"`
+	MinAmount          int64     `json:"min_amount,omitempty" bson:"min_amount,omitempty"`
+	MaxAmount          int64     `json:"max_amount,omitempty" bson:"max_amount,omitempty"`
+	Priority           int       `json:"priority" bson:"priority"`
+	Learned            bool      `json:"learned" bson:"learned"`
+	Created_at         time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Matches reports whether the transaction meets every condition of the rule.
+// Keywords match the memo case-insensitively.
+func (r CategoryRule) Matches(t Transaction) bool {
+	if r.CounterpartyUserID != "" && r.CounterpartyUserID != t.CounterpartyUserID {
+		return false
+	}
+	if r.MinAmount > 0 && t.Amount < r.MinAmount {
+		return false
+	}
+	if r.MaxAmount > 0 && t.Amount > r.MaxAmount {
+		return false
+	}
+	if len(r.Keywords) == 0 {
+		return true
+	}
+	memo := strings.ToLower(t.Memo)
+	for _, keyword := range r.Keywords {
+		if strings.Contains(memo, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
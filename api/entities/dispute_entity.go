@@ -0,0 +1,66 @@
+package entities
+
+import "time"
+
+// Dispute statuses. A dispute is opened by the user, taken under review by
+// support staff and then either resolved in favor of the user, with a full
+// or partial reversal, or rejected.
+const (
+	DisputeOpened          = "opened"
+	DisputeUnderReview     = "under_review"
+	DisputeResolvedInFavor = "resolved_in_favor"
+	DisputeRejected        = "rejected"
+)
+
+// Reasons a user disputes a transaction.
+const (
+	DisputeWrongRecipient = "wrong_recipient"
+	DisputeWrongAmount    = "wrong_amount"
+	DisputeUnauthorized   = "unauthorized"
+	DisputeNotReceived    = "not_received"
+	DisputeOther          = "other"
+)
+
+// Notification types of disputes.
+const (
+	NotificationDisputeResolved = "dispute.resolved"
+	NotificationDisputeReversal = "dispute.reversal"
+)
+
+// DisputeEvidence is a file attached to a dispute by the user or by support
+// staff.
+type DisputeEvidence struct {
+	ID          string    `json:"id" bson:"id"`
+	Name        string    `json:"name,omitempty" bson:"name,omitempty"`
+	BlobKey     string    `json:"-" bson:"blob_key"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	Size        int64     `json:"size" bson:"size"`
+	UploadedBy  string    `json:"uploaded_by" bson:"uploaded_by"`
+	Uploaded_at time.Time `json:"uploaded_at" bson:"uploaded_at"`
+}
+
+// Dispute is a claim of a user about a completed transaction of their
+// wallet. The transaction is never changed: a reversal is posted as a new
+// ledger entry, ReversalEntryID, linked to the original one.
+type Dispute struct {
+	ID                   string            `json:"id" bson:"_id,omitempty"`
+	UserID               string            `json:"user_id" bson:"user_id"`
+	WalletID             string            `json:"wallet_id" bson:"wallet_id"`
+	TransactionID        string            `json:"transaction_id" bson:"transaction_id"`
+	EntryID              string            `json:"entry_id" bson:"entry_id"`
+	CounterpartyWalletID string            `json:"counterparty_wallet_id" bson:"counterparty_wallet_id"`
+	CounterpartyUserID   string            `json:"counterparty_user_id" bson:"counterparty_user_id"`
+	Amount               int64             `json:"amount" bson:"amount"`
+	Currency             string            `json:"currency" bson:"currency"`
+	Reason               string            `json:"reason" bson:"reason"`
+	Description          string            `json:"description,omitempty" bson:"description,omitempty"`
+	Status               string            `json:"status" bson:"status"`
+	Evidence             []DisputeEvidence `json:"evidence" bson:"evidence"`
+	Reviewer             string            `json:"reviewer,omitempty" bson:"reviewer,omitempty"`
+	Resolution           string            `json:"resolution,omitempty" bson:"resolution,omitempty"`
+	ReversedAmount       int64             `json:"reversed_amount,omitempty" bson:"reversed_amount,omitempty"`
+	ReversalEntryID      string            `json:"reversal_entry_id,omitempty" bson:"reversal_entry_id,omitempty"`
+	Created_at           time.Time         `json:"created_at" bson:"created_at"`
+	Update_at            time.Time         `json:"updated_at" bson:"updated_at"`
+	Resolved_at          *time.Time        `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+}
@@ -0,0 +1,59 @@
+package entities
+
+import "time"
+
+// Operations that can be charged a fee.
+const (
+	OperationTransfer   = "transfer"
+	OperationWithdrawal = "withdrawal"
+	OperationConversion = "conversion"
+	// OperationMerchantPayment is charged to the merchant, not the payer.
+	OperationMerchantPayment = "merchant-payment"
+)
+
+// Kinds of fee rule.
+const (
+	FeeFlat       = "flat"
+	FeePercentage = "percentage"
+	FeeTiered     = "tiered"
+)
+
+// FeeRule prices an operation. Empty Currency or Segment match any value;
+// when several rules match, the most specific one wins and then the one with
+// the highest Priority. Percentages are in basis points and MinFee/MaxFee,
+// when not zero, bound the result.
+type FeeRule struct {
+	ID         string    `json:"id,omitempty" bson:"_id,omitempty"`
+	Name       string    `json:"name" bson:"name"`
+	Operation  string    `json:"operation" bson:"operation"`
+	Currency   string    `json:"currency,omitempty" bson:"currency,omitempty"`
+	Segment    string    `json:"segment,omitempty" bson:"segment,omitempty"`
+	Kind       string    `json:"kind" bson:"kind"`
+	FlatAmount int64     `json:"flat_amount,omitempty" bson:"flat_amount,omitempty"`
+	Bps        int64     `json:"bps,omitempty" bson:"bps,omitempty"`
+	Tiers      []FeeTier `json:"tiers,omitempty" bson:"tiers,omitempty"`
+	MinFee     int64     `json:"min_fee,omitempty" bson:"min_fee,omitempty"`
+	MaxFee     int64     `json:"max_fee,omitempty" bson:"max_fee,omitempty"`
+	Priority   int       `json:"priority" bson:"priority"`
+	Enabled    bool      `json:"enabled" bson:"enabled"`
+	Created_at time.Time `json:"created_at" bson:"created_at"`
+	Update_at  time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// FeeTier prices amounts up to UpTo, inclusive. The last tier also prices any
+// larger amount, so it may leave UpTo at zero.
+type FeeTier struct {
+	UpTo       int64 `json:"up_to" bson:"up_to"`
+	FlatAmount int64 `json:"flat_amount,omitempty" bson:"flat_amount,omitempty"`
+	Bps        int64 `json:"bps,omitempty" bson:"bps,omitempty"`
+}
+
+// FeeQuote is the fee an operation would be charged right now.
+type FeeQuote struct {
+	Operation string `json:"operation"`
+	Currency  string `json:"currency"`
+	Amount    int64  `json:"amount"`
+	Fee       int64  `json:"fee"`
+	Total     int64  `json:"total"`
+	RuleID    string `json:"rule_id,omitempty"`
+}
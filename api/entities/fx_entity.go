@@ -0,0 +1,60 @@
+package entities
+
+import "time"
+
+// FX quote statuses.
+const (
+	QuoteOpen = "open"
+	QuoteUsed = "used"
+)
+
+// FXQuote locks a conversion rate for a wallet until ExpiresAt. MidRate is
+// the rate of the provider and Rate the one given to the customer after the
+// spread, both as decimal strings. Fee is charged on top of FromAmount, in
+// the From currency.
+type FXQuote struct {
+	ID         string    `json:"id,omitempty" bson:"_id,omitempty"`
+	WalletID   string    `json:"wallet_id" bson:"wallet_id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	From       string    `json:"from" bson:"from"`
+	To         string    `json:"to" bson:"to"`
+	FromAmount int64     `json:"from_amount" bson:"from_amount"`
+	ToAmount   int64     `json:"to_amount" bson:"to_amount"`
+	Fee        int64     `json:"fee" bson:"fee"`
+	MidAmount  int64     `json:"-" bson:"mid_amount"`
+	MidRate    string    `json:"mid_rate" bson:"mid_rate"`
+	Rate       string    `json:"rate" bson:"rate"`
+	SpreadBps  int64     `json:"spread_bps" bson:"spread_bps"`
+	Status     string    `json:"status" bson:"status"`
+	ExpiresAt  time.Time `json:"expires_at" bson:"expires_at"`
+	Created_at time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Conversion is an executed FX quote.
+type Conversion struct {
+	ID         string    `json:"id"`
+	QuoteID    string    `json:"quote_id"`
+	WalletID   string    `json:"wallet_id"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	FromAmount int64     `json:"from_amount"`
+	ToAmount   int64     `json:"to_amount"`
+	Fee        int64     `json:"fee"`
+	Rate       string    `json:"rate"`
+	Created_at time.Time `json:"created_at"`
+}
+
+// ValidCurrency reports whether code looks like an ISO 4217 code. Currency
+// codes are used as field names of the wallet balances, so nothing else may
+// reach the database.
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
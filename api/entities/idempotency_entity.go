@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// Idempotency record statuses.
+const (
+	IdempotencyPending   = "pending"
+	IdempotencyCompleted = "completed"
+)
+
+// IdempotencyRecord remembers the request a client sent with an
+// Idempotency-Key and the response it produced, so retries can be replayed.
+// ReviewID is set instead of Response when the request was held for review.
+type IdempotencyRecord struct {
+	ID          string    `json:"id,omitempty" bson:"_id,omitempty"`
+	Scope       string    `json:"scope" bson:"scope"`
+	Key         string    `json:"key" bson:"key"`
+	Fingerprint string    `json:"fingerprint" bson:"fingerprint"`
+	Status      string    `json:"status" bson:"status"`
+	Response    []byte    `json:"response,omitempty" bson:"response,omitempty"`
+	ReviewID    string    `json:"review_id,omitempty" bson:"review_id,omitempty"`
+	Created_at  time.Time `json:"created_at" bson:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" bson:"expires_at"`
+}
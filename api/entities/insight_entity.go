@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+// Insight periods, the window of history an insight covers up to now.
+const (
+	InsightWeek    = "week"
+	InsightMonth   = "month"
+	InsightQuarter = "quarter"
+	InsightYear    = "year"
+)
+
+// Insight granularities, the size of the buckets of the series.
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// IncomeTypes and ExpenseTypes are the transactions that count as income and
+// expense in insights.
+var (
+	IncomeTypes  = []string{TransactionDeposit, TransactionTransferIn, TransactionPaymentIn, TransactionInterest}
+	ExpenseTypes = []string{TransactionTransferOut, TransactionWithdrawal, TransactionFee, TransactionPaymentOut, TransactionCardPayment}
+)
+
+// InsightRow is the income and expense of a wallet in a bucket for a pair of
+// category and counterparty, the unit insights are built from.
+type InsightRow struct {
+	Bucket             time.Time `bson:"bucket"`
+	Category           string    `bson:"category"`
+	CounterpartyUserID string    `bson:"counterparty_user_id"`
+	Income             int64     `bson:"income"`
+	Expense            int64     `bson:"expense"`
+	Count              int64     `bson:"count"`
+}
+
+// InsightTotal is the income and expense of a category or a counterparty.
+type InsightTotal struct {
+	Key     string `json:"key"`
+	Name    string `json:"name,omitempty"`
+	Income  int64  `json:"income"`
+	Expense int64  `json:"expense"`
+	Count   int64  `json:"count"`
+}
+
+// InsightBucket is a point of the income and expense series.
+type InsightBucket struct {
+	Start   time.Time `json:"start"`
+	Income  int64     `json:"income"`
+	Expense int64     `json:"expense"`
+	Count   int64     `json:"count"`
+}
+
+// Insights summarize where the money of a wallet came from and went to.
+// Moves to pockets and currency conversions are neither income nor expense.
+type Insights struct {
+	WalletID       string          `json:"wallet_id"`
+	Currency       string          `json:"currency"`
+	Period         string          `json:"period"`
+	Granularity    string          `json:"granularity"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	Income         int64           `json:"income"`
+	Expense        int64           `json:"expense"`
+	Net            int64           `json:"net"`
+	ByCategory     []InsightTotal  `json:"by_category"`
+	ByCounterparty []InsightTotal  `json:"by_counterparty"`
+	Series         []InsightBucket `json:"series"`
+	TopMerchants   []InsightTotal  `json:"top_merchants"`
+}
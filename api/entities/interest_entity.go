@@ -0,0 +1,107 @@
+package entities
+
+import "time"
+
+// Accounts that earn interest.
+const (
+	InterestAccountWallet = "wallet"
+	InterestAccountPocket = "pocket"
+)
+
+// Interest payout statuses. A failed payout gave its accruals back, so they
+// are paid with the next one.
+const (
+	InterestPayoutPending = "pending"
+	InterestPayoutPaid    = "paid"
+	InterestPayoutFailed  = "failed"
+)
+
+// InterestRate is the annual rate, in basis points, an account earns and the
+// day count convention it accrues with: "ACT/365" or "30/360".
+type InterestRate struct {
+	AnnualRateBps int64  `json:"annual_rate_bps" bson:"annual_rate_bps"`
+	DayCount      string `json:"day_count" bson:"day_count"`
+}
+
+// AccountBalance is the balance of a wallet, in one currency, or of a pocket
+// at a point in time. Only one of WalletID and PocketID is set.
+type AccountBalance struct {
+	WalletID string `bson:"wallet_id,omitempty"`
+	PocketID string `bson:"pocket_id,omitempty"`
+	Currency string `bson:"currency"`
+	Balance  int64  `bson:"balance"`
+}
+
+// InterestAccrual is the interest an account earned on the balance it closed
+// Day with. There is at most one per account, currency and day, so accruing
+// a day again accrues nothing. Amount is in millionths of the minor unit of
+// the currency; accruals are paid, and get a PayoutID, once a month.
+type InterestAccrual struct {
+	ID          string       `json:"id,omitempty" bson:"_id,omitempty"`
+	AccountKind string       `json:"account_kind" bson:"account_kind"`
+	AccountID   string       `json:"account_id" bson:"account_id"`
+	Currency    string       `json:"currency" bson:"currency"`
+	Day         time.Time    `json:"day" bson:"day"`
+	Balance     int64        `json:"balance" bson:"balance"`
+	Rate        InterestRate `json:"rate" bson:"rate"`
+	Amount      int64        `json:"amount" bson:"amount"`
+	PayoutID    string       `json:"payout_id,omitempty" bson:"payout_id,omitempty"`
+	Created_at  time.Time    `json:"created_at" bson:"created_at"`
+}
+
+// InterestBalance is the interest an account accrued and was not paid yet,
+// in millionths of the minor unit.
+type InterestBalance struct {
+	AccountKind string `json:"account_kind" bson:"account_kind"`
+	AccountID   string `json:"account_id" bson:"account_id"`
+	Currency    string `json:"currency" bson:"currency"`
+	Accrued     int64  `json:"accrued" bson:"accrued"`
+}
+
+// InterestPayout pays an account the interest accrued until the start of
+// Month, plus the fraction of a cent the previous payout carried. Amount is
+// the whole minor units paid and Remainder, in millionths, is carried to the
+// next payout. Payouts of a pocket that was closed are paid to its wallet.
+type InterestPayout struct {
+	ID          string    `json:"id,omitempty" bson:"_id,omitempty"`
+	AccountKind string    `json:"account_kind" bson:"account_kind"`
+	AccountID   string    `json:"account_id" bson:"account_id"`
+	WalletID    string    `json:"wallet_id" bson:"wallet_id"`
+	Currency    string    `json:"currency" bson:"currency"`
+	Month       time.Time `json:"month" bson:"month"`
+	Accrued     int64     `json:"accrued" bson:"accrued"`
+	Amount      int64     `json:"amount" bson:"amount"`
+	Remainder   int64     `json:"remainder" bson:"remainder"`
+	EntryID     string    `json:"entry_id,omitempty" bson:"entry_id,omitempty"`
+	Status      string    `json:"status" bson:"status"`
+	Created_at  time.Time `json:"created_at" bson:"created_at"`
+}
+
+// InterestAccount is an account of a wallet with its rate, if it earns any,
+// and the interest it accrued and was not paid yet. Accrued is in millionths
+// of the minor unit and AccruedAmount in whole minor units.
+type InterestAccount struct {
+	AccountKind   string        `json:"account_kind"`
+	AccountID     string        `json:"account_id"`
+	Name          string        `json:"name,omitempty"`
+	Currency      string        `json:"currency"`
+	Rate          *InterestRate `json:"rate,omitempty"`
+	Accrued       int64         `json:"accrued"`
+	AccruedAmount int64         `json:"accrued_amount"`
+}
+
+// InterestSummary is the interest of a wallet and its pockets and its latest
+// payouts.
+type InterestSummary struct {
+	WalletID string            `json:"wallet_id"`
+	Accounts []InterestAccount `json:"accounts"`
+	Payouts  []InterestPayout  `json:"payouts"`
+}
+
+// InterestRun tells what accruing a day did: how many accounts accrued and
+// how many had been accrued before.
+type InterestRun struct {
+	Day     time.Time `json:"day"`
+	Accrued int       `json:"accrued"`
+	Skipped int       `json:"skipped"`
+}
@@ -0,0 +1,116 @@
+package entities
+
+import "time"
+
+// KYC application statuses. A draft collects documents until it is submitted;
+// a submitted application waits for the provider or for support staff, who
+// approve or reject it.
+const (
+	KYCDraft     = "draft"
+	KYCSubmitted = "submitted"
+	KYCApproved  = "approved"
+	KYCRejected  = "rejected"
+)
+
+// KYC document types.
+const (
+	DocumentIDFront        = "id_front"
+	DocumentIDBack         = "id_back"
+	DocumentSelfie         = "selfie"
+	DocumentProofOfAddress = "proof_of_address"
+)
+
+// KYCRequiredDocuments are the documents an application must have to be
+// submitted for each level.
+var KYCRequiredDocuments = map[string][]string{
+	KYCBasic: {DocumentIDFront, DocumentIDBack},
+	KYCFull:  {DocumentIDFront, DocumentIDBack, DocumentSelfie, DocumentProofOfAddress},
+}
+
+// Features gated by KYC level.
+const (
+	FeatureFXConversion      = "fx_conversion"
+	FeatureScheduledTransfer = "scheduled_transfer"
+	FeatureMerchantAccount   = "merchant_account"
+	FeatureVirtualCard       = "virtual_card"
+)
+
+// KYCFeatureLevels is the level a user needs to use each gated feature.
+var KYCFeatureLevels = map[string]string{
+	FeatureFXConversion:      KYCBasic,
+	FeatureScheduledTransfer: KYCBasic,
+	FeatureMerchantAccount:   KYCFull,
+	FeatureVirtualCard:       KYCBasic,
+}
+
+// KYC decisions of the verification provider.
+const (
+	KYCDecisionApprove = "approve"
+	KYCDecisionReject  = "reject"
+	KYCDecisionReview  = "review"
+)
+
+// KYC audit actions.
+const (
+	KYCActionCreated   = "application.created"
+	KYCActionUploaded  = "document.uploaded"
+	KYCActionSubmitted = "application.submitted"
+	KYCActionApproved  = "application.approved"
+	KYCActionRejected  = "application.rejected"
+)
+
+// KYCActorProvider is the actor of the audit events of the verification
+// provider.
+const KYCActorProvider = "provider"
+
+// KYCApplication is a request of a user to be verified at a level.
+type KYCApplication struct {
+	ID          string                 `json:"id" bson:"_id,omitempty"`
+	UserID      string                 `json:"user_id" bson:"user_id"`
+	Level       string                 `json:"level" bson:"level"`
+	Status      string                 `json:"status" bson:"status"`
+	Documents   map[string]KYCDocument `json:"documents" bson:"documents"`
+	ReviewedBy  string                 `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	Reason      string                 `json:"reason,omitempty" bson:"reason,omitempty"`
+	Open        bool                   `json:"-" bson:"open,omitempty"`
+	Created_at  time.Time              `json:"created_at" bson:"created_at"`
+	Update_at   time.Time              `json:"updated_at" bson:"updated_at"`
+	Reviewed_at *time.Time             `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+}
+
+// KYCDocument is a document uploaded to an application. Its content is kept
+// in the blob store under BlobKey.
+type KYCDocument struct {
+	ID          string    `json:"id" bson:"id"`
+	Type        string    `json:"type" bson:"type"`
+	BlobKey     string    `json:"-" bson:"blob_key"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	Size        int64     `json:"size" bson:"size"`
+	Uploaded_at time.Time `json:"uploaded_at" bson:"uploaded_at"`
+}
+
+// KYCDecision is what the verification provider concluded on an application.
+type KYCDecision struct {
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// KYCEvent is an entry of the KYC audit trail of a user.
+type KYCEvent struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	UserID        string    `json:"user_id" bson:"user_id"`
+	ApplicationID string    `json:"application_id" bson:"application_id"`
+	Action        string    `json:"action" bson:"action"`
+	Actor         string    `json:"actor" bson:"actor"`
+	FromLevel     string    `json:"from_level,omitempty" bson:"from_level,omitempty"`
+	ToLevel       string    `json:"to_level,omitempty" bson:"to_level,omitempty"`
+	Detail        string    `json:"detail,omitempty" bson:"detail,omitempty"`
+	Created_at    time.Time `json:"created_at" bson:"created_at"`
+}
+
+// KYCStatus is the KYC state of a user.
+type KYCStatus struct {
+	Level       string          `json:"level"`
+	Application *KYCApplication `json:"application,omitempty"`
+	Events      []KYCEvent      `json:"events"`
+}
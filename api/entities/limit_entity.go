@@ -0,0 +1,64 @@
+package entities
+
+import "time"
+
+// Limit kinds.
+const (
+	LimitSingle  = "single"
+	LimitDaily   = "daily"
+	LimitMonthly = "monthly"
+	LimitHourly  = "hourly"
+)
+
+// Limits are the outgoing limits of a KYC level in a currency. Amounts are in
+// cents and PerHour counts transactions; zero means no limit.
+type Limits struct {
+	MaxSingle int64 `json:"max_single"`
+	Daily     int64 `json:"daily"`
+	Monthly   int64 `json:"monthly"`
+	PerHour   int64 `json:"per_hour"`
+}
+
+// LimitUsage is how much of a limit is used in its current window.
+type LimitUsage struct {
+	Kind     string    `json:"kind"`
+	Currency string    `json:"currency"`
+	Limit    int64     `json:"limit"`
+	Used     int64     `json:"used"`
+	Reset_at time.Time `json:"reset_at"`
+}
+
+// LimitReservation is what a debit took from the usage windows of its user,
+// to be given back if the debit does not happen.
+type LimitReservation struct {
+	Parts []LimitReservationPart `json:"parts,omitempty" bson:"parts,omitempty"`
+}
+
+// LimitReservationPart is what a debit took from one usage window.
+type LimitReservationPart struct {
+	Key    string `json:"key" bson:"key"`
+	Kind   string `json:"kind,omitempty" bson:"kind,omitempty"`
+	Amount int64  `json:"amount" bson:"amount"`
+}
+
+// Unused returns what is left of the reservation when only amount of the
+// debit it was taken for happened. The debit still counts as a transaction,
+// so hourly parts are kept whole.
+func (r LimitReservation) Unused(amount int64) LimitReservation {
+	var unused LimitReservation
+	for _, part := range r.Parts {
+		if part.Kind == LimitHourly || part.Amount <= amount {
+			continue
+		}
+		unused.Parts = append(unused.Parts, LimitReservationPart{Key: part.Key, Kind: part.Kind, Amount: part.Amount - amount})
+	}
+	return unused
+}
+
+// LimitStatus is what a user may still move out in a currency.
+type LimitStatus struct {
+	KYCLevel string       `json:"kyc_level"`
+	Currency string       `json:"currency"`
+	Limits   Limits       `json:"limits"`
+	Usage    []LimitUsage `json:"usage"`
+}
@@ -0,0 +1,81 @@
+package entities
+
+import "time"
+
+// Wallet member roles. The user a wallet belongs to is always its owner,
+// without a membership; other users can be invited as owners too.
+const (
+	MemberOwner   = "owner"
+	MemberSpender = "spender"
+	MemberViewer  = "viewer"
+)
+
+// MemberRoles are the roles a user can be invited to a wallet with.
+var MemberRoles = map[string]bool{
+	MemberOwner:   true,
+	MemberSpender: true,
+	MemberViewer:  true,
+}
+
+// Membership statuses.
+const (
+	MemberInvited  = "invited"
+	MemberActive   = "active"
+	MemberDeclined = "declined"
+	MemberRemoved  = "removed"
+)
+
+// Permissions on a wallet. Viewing lets a member read the balances, history,
+// statements and insights of the wallet; spending also lets them send money
+// out of it; managing also lets them move money between its currencies and
+// pockets, and invite and remove members.
+const (
+	PermissionView   = "view"
+	PermissionSpend  = "spend"
+	PermissionManage = "manage"
+)
+
+var rolePermissions = map[string]map[string]bool{
+	MemberOwner:   {PermissionView: true, PermissionSpend: true, PermissionManage: true},
+	MemberSpender: {PermissionView: true, PermissionSpend: true},
+	MemberViewer:  {PermissionView: true},
+}
+
+// Notification types of shared wallets.
+const (
+	NotificationWalletInvitation = "wallet.invitation"
+	NotificationMemberJoined     = "wallet.member_joined"
+	NotificationMemberRemoved    = "wallet.member_removed"
+)
+
+// WalletMember is a user invited to use a wallet that belongs to someone
+// else. Spenders can only spend, in each currency, up to its monthly
+// spending limit: a currency without a limit cannot be spent.
+type WalletMember struct {
+	ID             string           `json:"id" bson:"_id,omitempty"`
+	WalletID       string           `json:"wallet_id" bson:"wallet_id"`
+	UserID         string           `json:"user_id" bson:"user_id"`
+	Email          string           `json:"email" bson:"email"`
+	Role           string           `json:"role" bson:"role"`
+	SpendingLimits map[string]int64 `json:"spending_limits,omitempty" bson:"spending_limits,omitempty"`
+	Status         string           `json:"status" bson:"status"`
+	InvitedBy      string           `json:"invited_by,omitempty" bson:"invited_by,omitempty"`
+	Spending       []MemberSpending `json:"spending,omitempty" bson:"-"`
+	Joined_at      *time.Time       `json:"joined_at,omitempty" bson:"joined_at,omitempty"`
+	Created_at     time.Time        `json:"created_at" bson:"created_at"`
+	Update_at      time.Time        `json:"updated_at" bson:"updated_at"`
+}
+
+// Allows tells whether the role of the member grants permission.
+func (m WalletMember) Allows(permission string) bool {
+	return m.Status == MemberActive && rolePermissions[m.Role][permission]
+}
+
+// MemberSpending is what a spender spent of their monthly limit in a
+// currency.
+type MemberSpending struct {
+	Currency string    `json:"currency"`
+	Limit    int64     `json:"limit"`
+	Spent    int64     `json:"spent"`
+	Reset_at time.Time `json:"reset_at"`
+}
@@ -0,0 +1,87 @@
+package entities
+
+import "time"
+
+// Kinds of payment QR code.
+const (
+	QRStatic  = "static"
+	QRDynamic = "dynamic"
+)
+
+// Dynamic QR code statuses. Static codes are not stored.
+const (
+	QRPending = "pending"
+	QRPaid    = "paid"
+)
+
+// MerchantCountry is the country code merchants are registered in.
+const MerchantCountry = "CO"
+
+// Merchant is a shop that accepts payments by QR code into the wallet of the
+// user who registered it. CategoryCode is the ISO 18245 merchant category
+// code.
+type Merchant struct {
+	ID           string    `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID       string    `json:"user_id" bson:"user_id"`
+	Name         string    `json:"name" bson:"name"`
+	City         string    `json:"city" bson:"city"`
+	CategoryCode string    `json:"category_code" bson:"category_code"`
+	Created_at   time.Time `json:"created_at" bson:"created_at"`
+	Update_at    time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// MerchantQR is a payment QR code of a merchant. Static codes can be paid
+// any number of times with the amount the payer enters; dynamic codes are
+// stored, carry the amount and are paid once before ExpiresAt. Payload is
+// the text to render as the QR code.
+type MerchantQR struct {
+	ID         string    `json:"id,omitempty" bson:"_id,omitempty"`
+	MerchantID string    `json:"merchant_id" bson:"merchant_id"`
+	Kind       string    `json:"kind" bson:"kind"`
+	Amount     int64     `json:"amount,omitempty" bson:"amount,omitempty"`
+	Currency   string    `json:"currency" bson:"currency"`
+	Reference  string    `json:"reference,omitempty" bson:"reference,omitempty"`
+	Status     string    `json:"status,omitempty" bson:"status,omitempty"`
+	PaymentID  string    `json:"payment_id,omitempty" bson:"payment_id,omitempty"`
+	Payload    string    `json:"payload" bson:"-"`
+	ExpiresAt  time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	Created_at time.Time `json:"created_at" bson:"created_at"`
+}
+
+// MerchantPayment is a QR code paid by a wallet user. Its ID is the one of
+// the ledger entry that moved the money.
+type MerchantPayment struct {
+	ID           string    `json:"id"`
+	MerchantID   string    `json:"merchant_id"`
+	MerchantName string    `json:"merchant_name"`
+	QRID         string    `json:"qr_id,omitempty"`
+	Reference    string    `json:"reference,omitempty"`
+	PayerUserID  string    `json:"payer_user_id"`
+	Amount       int64     `json:"amount"`
+	Currency     string    `json:"currency"`
+	Memo         string    `json:"memo,omitempty"`
+	Status       string    `json:"status"`
+	Created_at   time.Time `json:"created_at"`
+}
+
+// SettlementDay adds up the QR payments a merchant received on a day, UTC,
+// in a currency: Net is what reached the wallet after the merchant fees.
+// Totals leave Day empty.
+type SettlementDay struct {
+	Day      string `json:"day,omitempty" bson:"day"`
+	Currency string `json:"currency" bson:"currency"`
+	Payments int64  `json:"payments" bson:"payments"`
+	Gross    int64  `json:"gross" bson:"gross"`
+	Fees     int64  `json:"fees" bson:"fees"`
+	Net      int64  `json:"net" bson:"net"`
+}
+
+// SettlementReport is the settlement of a merchant in [From, To), by day and
+// in total per currency.
+type SettlementReport struct {
+	MerchantID string          `json:"merchant_id"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Days       []SettlementDay `json:"days"`
+	Totals     []SettlementDay `json:"totals"`
+}
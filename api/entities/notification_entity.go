@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// Notification types.
+const (
+	NotificationBudgetAlert = "budget.alert"
+)
+
+// Notification is a message for a user about something that happened to
+// their money. Data carries the IDs and amounts a client needs to render it.
+type Notification struct {
+	ID         string            `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID     string            `json:"user_id" bson:"user_id"`
+	Type       string            `json:"type" bson:"type"`
+	Title      string            `json:"title" bson:"title"`
+	Message    string            `json:"message" bson:"message"`
+	Data       map[string]string `json:"data,omitempty" bson:"data,omitempty"`
+	Read       bool              `json:"read" bson:"read"`
+	Read_at    *time.Time        `json:"read_at,omitempty" bson:"read_at,omitempty"`
+	Created_at time.Time         `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,47 @@
+package entities
+
+import "time"
+
+// Payment request statuses.
+const (
+	RequestPending   = "pending"
+	RequestAccepted  = "accepted"
+	RequestDeclined  = "declined"
+	RequestCancelled = "cancelled"
+	RequestExpired   = "expired"
+	// RequestHeld is accepted, but its transfer waits for the analysts.
+	RequestHeld = "held"
+)
+
+// Payment request directions, seen from the authenticated user.
+const (
+	DirectionIncoming = "incoming"
+	DirectionOutgoing = "outgoing"
+)
+
+// PaymentRequest asks the payer to transfer Amount to the requester. The
+// payer is named by exactly one of email, phone or DNI and resolved to
+// PayerID when the request is created. Requests made for a bill split carry
+// the split's ID.
+type PaymentRequest struct {
+	ID          string    `json:"id,omitempty" bson:"_id,omitempty"`
+	RequesterID string    `json:"requester_id" bson:"requester_id"`
+	PayerID     string    `json:"payer_id" bson:"payer_id"`
+	PayerEmail  string    `json:"payer_email,omitempty" bson:"payer_email,omitempty"`
+	PayerPhone  int       `json:"payer_phone,omitempty" bson:"payer_phone,omitempty"`
+	PayerDNI    int       `json:"payer_dni,omitempty" bson:"payer_dni,omitempty"`
+	Amount      int64     `json:"amount" bson:"amount"`
+	Currency    string    `json:"currency" bson:"currency"`
+	Memo        string    `json:"memo,omitempty" bson:"memo,omitempty"`
+	Status      string    `json:"status" bson:"status"`
+	TransferID  string    `json:"transfer_id,omitempty" bson:"transfer_id,omitempty"`
+	SplitID     string    `json:"split_id,omitempty" bson:"split_id,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at" bson:"expires_at"`
+	Created_at  time.Time `json:"created_at" bson:"created_at"`
+	Update_at   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Expired reports whether a pending request can no longer be answered.
+func (r PaymentRequest) Expired(now time.Time) bool {
+	return r.Status == RequestPending && !now.Before(r.ExpiresAt)
+}
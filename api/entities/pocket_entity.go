@@ -0,0 +1,49 @@
+package entities
+
+import "time"
+
+// Pocket statuses. A closed pocket gave its balance back to the wallet.
+const (
+	PocketActive = "active"
+	PocketClosed = "closed"
+)
+
+// Pocket ring-fences part of a wallet balance for a goal. Its money is moved
+// in and out of the wallet with ledger entries, so the wallet balance only
+// holds what is not saved in a pocket.
+//
+// RoundUpTo, when set, rounds every outgoing transfer of the wallet up to a
+// multiple of it and saves the difference. WeeklyAmount, when set, is saved
+// every week at NextAutoSaveAt.
+type Pocket struct {
+	ID             string          `json:"id,omitempty" bson:"_id,omitempty"`
+	WalletID       string          `json:"wallet_id" bson:"wallet_id"`
+	UserID         string          `json:"user_id" bson:"user_id"`
+	Name           string          `json:"name" bson:"name"`
+	Currency       string          `json:"currency" bson:"currency"`
+	Balance        int64           `json:"balance" bson:"balance"`
+	TargetAmount   int64           `json:"target_amount" bson:"target_amount"`
+	TargetDate     *time.Time      `json:"target_date,omitempty" bson:"target_date,omitempty"`
+	RoundUpTo      int64           `json:"round_up_to,omitempty" bson:"round_up_to,omitempty"`
+	WeeklyAmount   int64           `json:"weekly_amount,omitempty" bson:"weekly_amount,omitempty"`
+	NextAutoSaveAt *time.Time      `json:"next_auto_save_at,omitempty" bson:"next_auto_save_at,omitempty"`
+	Status         string          `json:"status" bson:"status"`
+	Progress       *PocketProgress `json:"progress,omitempty" bson:"-"`
+	Created_at     time.Time       `json:"created_at" bson:"created_at"`
+	Update_at      time.Time       `json:"updated_at" bson:"updated_at"`
+}
+
+// PocketProgress tells how far a pocket is from its target. WeeklyNeeded is
+// what would have to be saved every week to reach the target on its date.
+type PocketProgress struct {
+	Percent      int   `json:"percent"`
+	Remaining    int64 `json:"remaining"`
+	DaysLeft     int   `json:"days_left,omitempty"`
+	WeeklyNeeded int64 `json:"weekly_needed,omitempty"`
+	Reached      bool  `json:"reached"`
+}
+
+// PocketAccount returns the ledger account name of a pocket.
+func PocketAccount(pocketID string) string {
+	return "pocket:" + pocketID
+}
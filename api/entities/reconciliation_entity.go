@@ -0,0 +1,96 @@
+package entities
+
+import "time"
+
+// Results of reconciling a movement of a provider settlement file against
+// the ledger.
+const (
+	// ReconciliationMatched is a settled movement found in the ledger with the
+	// same amount, within the tolerance of the run.
+	ReconciliationMatched = "matched"
+	// ReconciliationAmountMismatch is a settled movement found in the ledger
+	// by its reference with a different amount.
+	ReconciliationAmountMismatch = "amount_mismatch"
+	// ReconciliationUnmatchedInternal is a transaction of the day the
+	// provider did not settle.
+	ReconciliationUnmatchedInternal = "unmatched_internal"
+	// ReconciliationUnmatchedExternal is a settled movement missing from the
+	// ledger.
+	ReconciliationUnmatchedExternal = "unmatched_external"
+)
+
+// ReconciliationResults lists the valid results of a reconciliation item.
+var ReconciliationResults = map[string]bool{
+	ReconciliationMatched:           true,
+	ReconciliationAmountMismatch:    true,
+	ReconciliationUnmatchedInternal: true,
+	ReconciliationUnmatchedExternal: true,
+}
+
+// ReconciledTypes are the transactions that move money in or out through a
+// provider and must show up in its settlement files.
+var ReconciledTypes = []string{TransactionDeposit, TransactionWithdrawal}
+
+// ReconciliationTolerance is how far apart a settled movement and a
+// transaction may be and still match: Amount in minor units and Days in
+// calendar days, since providers settle late.
+type ReconciliationTolerance struct {
+	Amount int64 `json:"amount" bson:"amount"`
+	Days   int   `json:"days" bson:"days"`
+}
+
+// ReconciliationRun is the end-of-day reconciliation of the settlement file
+// of a provider against the ledger for the UTC day Day.
+type ReconciliationRun struct {
+	ID         string                  `json:"id,omitempty" bson:"_id,omitempty"`
+	Provider   string                  `json:"provider" bson:"provider"`
+	FileName   string                  `json:"file_name" bson:"file_name"`
+	Day        time.Time               `json:"day" bson:"day"`
+	Tolerance  ReconciliationTolerance `json:"tolerance" bson:"tolerance"`
+	Summary    ReconciliationSummary   `json:"summary" bson:"summary"`
+	RunBy      string                  `json:"run_by,omitempty" bson:"run_by,omitempty"`
+	Created_at time.Time               `json:"created_at" bson:"created_at"`
+}
+
+// ReconciliationSummary counts the items of a run by result.
+type ReconciliationSummary struct {
+	Records           int `json:"records" bson:"records"`
+	Matched           int `json:"matched" bson:"matched"`
+	AmountMismatch    int `json:"amount_mismatch" bson:"amount_mismatch"`
+	UnmatchedInternal int `json:"unmatched_internal" bson:"unmatched_internal"`
+	UnmatchedExternal int `json:"unmatched_external" bson:"unmatched_external"`
+}
+
+// Discrepancies returns the number of items that need someone to look at
+// them.
+func (s ReconciliationSummary) Discrepancies() int {
+	return s.AmountMismatch + s.UnmatchedInternal + s.UnmatchedExternal
+}
+
+// ReconciliationItem is the result for one settled movement or one
+// transaction. Items of an unmatched transaction have no external side and
+// items of an unmatched movement no internal one. Difference is the
+// external amount minus the internal one. Line is the line of the movement
+// in the settlement file.
+type ReconciliationItem struct {
+	ID             string     `json:"id,omitempty" bson:"_id,omitempty"`
+	RunID          string     `json:"run_id" bson:"run_id"`
+	Result         string     `json:"result" bson:"result"`
+	Reference      string     `json:"reference,omitempty" bson:"reference,omitempty"`
+	TransactionID  string     `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+	WalletID       string     `json:"wallet_id,omitempty" bson:"wallet_id,omitempty"`
+	Type           string     `json:"type" bson:"type"`
+	Currency       string     `json:"currency" bson:"currency"`
+	InternalAmount int64      `json:"internal_amount,omitempty" bson:"internal_amount,omitempty"`
+	ExternalAmount int64      `json:"external_amount,omitempty" bson:"external_amount,omitempty"`
+	Difference     int64      `json:"difference" bson:"difference"`
+	Internal_at    *time.Time `json:"internal_at,omitempty" bson:"internal_at,omitempty"`
+	External_at    *time.Time `json:"external_at,omitempty" bson:"external_at,omitempty"`
+	Line           int        `json:"line,omitempty" bson:"line,omitempty"`
+}
+
+// ReconciliationReport is a run with its items.
+type ReconciliationReport struct {
+	Run   ReconciliationRun    `json:"run"`
+	Items []ReconciliationItem `json:"items"`
+}
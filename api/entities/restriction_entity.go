@@ -0,0 +1,132 @@
+package entities
+
+import "time"
+
+// Wallet restriction kinds. A freeze stops money leaving the wallet, a block
+// also stops money coming in, and a capability restriction only stops the
+// capabilities it lists.
+const (
+	RestrictionFreeze       = "freeze"
+	RestrictionBlock        = "block"
+	RestrictionCapabilities = "capabilities"
+)
+
+// Capabilities of a wallet that move money out of it.
+const (
+	CapabilityTransfer   = "transfers"
+	CapabilityWithdrawal = "withdrawals"
+	CapabilityPayment    = "payments"
+	CapabilityConversion = "conversions"
+	CapabilityPocket     = "pockets"
+	// CapabilityReversal is the compensating debit of a dispute resolved
+	// for the disputer. Only a freeze or a block stops it.
+	CapabilityReversal = "reversals"
+)
+
+// RestrictableCapabilities are the capabilities support staff can restrict
+// one by one.
+var RestrictableCapabilities = map[string]bool{
+	CapabilityTransfer:   true,
+	CapabilityWithdrawal: true,
+	CapabilityPayment:    true,
+	CapabilityConversion: true,
+	CapabilityPocket:     true,
+}
+
+// Reason codes of wallet restrictions.
+var RestrictionReasons = map[string]bool{
+	"suspected_fraud":   true,
+	"aml_investigation": true,
+	"chargeback":        true,
+	"legal_order":       true,
+	"kyc_review":        true,
+	"customer_request":  true,
+}
+
+// Wallet restriction statuses. They are derived from the expiry and the lift
+// of the restriction, not stored.
+const (
+	RestrictionActive  = "active"
+	RestrictionExpired = "expired"
+	RestrictionLifted  = "lifted"
+)
+
+// Wallet restriction audit actions.
+const (
+	RestrictionActionPlaced = "restriction.placed"
+	RestrictionActionLifted = "restriction.lifted"
+)
+
+// WalletRestriction is a restriction support staff put on a wallet until it
+// expires or is lifted.
+type WalletRestriction struct {
+	ID           string     `json:"id" bson:"_id,omitempty"`
+	WalletID     string     `json:"wallet_id" bson:"wallet_id"`
+	UserID       string     `json:"user_id" bson:"user_id"`
+	Kind         string     `json:"kind" bson:"kind"`
+	Capabilities []string   `json:"capabilities,omitempty" bson:"capabilities,omitempty"`
+	Reason       string     `json:"reason" bson:"reason"`
+	Note         string     `json:"note,omitempty" bson:"note,omitempty"`
+	Status       string     `json:"status" bson:"-"`
+	CreatedBy    string     `json:"created_by" bson:"created_by"`
+	LiftedBy     string     `json:"lifted_by,omitempty" bson:"lifted_by,omitempty"`
+	LiftNote     string     `json:"lift_note,omitempty" bson:"lift_note,omitempty"`
+	Expires_at   time.Time  `json:"expires_at" bson:"expires_at"`
+	Lifted_at    *time.Time `json:"lifted_at,omitempty" bson:"lifted_at,omitempty"`
+	Created_at   time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// StatusAt returns the status of the restriction at now.
+func (r WalletRestriction) StatusAt(now time.Time) string {
+	switch {
+	case r.Lifted_at != nil:
+		return RestrictionLifted
+	case !now.Before(r.Expires_at):
+		return RestrictionExpired
+	}
+	return RestrictionActive
+}
+
+// Restricts tells whether the restriction stops the capability. Credits
+// are checked with an empty capability.
+func (r WalletRestriction) Restricts(capability string) bool {
+	switch r.Kind {
+	case RestrictionBlock:
+		return true
+	case RestrictionFreeze:
+		return capability != ""
+	}
+	for _, c := range r.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictionEvent is an entry of the restriction audit trail of a wallet.
+type RestrictionEvent struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	WalletID      string    `json:"wallet_id" bson:"wallet_id"`
+	RestrictionID string    `json:"restriction_id" bson:"restriction_id"`
+	Action        string    `json:"action" bson:"action"`
+	Actor         string    `json:"actor" bson:"actor"`
+	Kind          string    `json:"kind" bson:"kind"`
+	Reason        string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	Detail        string    `json:"detail,omitempty" bson:"detail,omitempty"`
+	Created_at    time.Time `json:"created_at" bson:"created_at"`
+}
+
+// WalletRestrictions are the restrictions of a wallet, newest first, and the
+// latest entries of its audit trail.
+type WalletRestrictions struct {
+	WalletID     string              `json:"wallet_id"`
+	Restrictions []WalletRestriction `json:"restrictions"`
+	Events       []RestrictionEvent  `json:"events"`
+}
+
+// Notification types of wallet restrictions.
+const (
+	NotificationWalletRestricted  = "wallet.restricted"
+	NotificationRestrictionLifted = "wallet.restriction_lifted"
+)
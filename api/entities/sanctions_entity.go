@@ -0,0 +1,59 @@
+package entities
+
+import "time"
+
+// Types of the parties of a sanctions list.
+const (
+	SanctionsIndividual = "individual"
+	SanctionsEntity     = "entity"
+	SanctionsVessel     = "vessel"
+	SanctionsAircraft   = "aircraft"
+)
+
+// SanctionsEntry is a party of a sanctions list, with the other names it is
+// known by.
+type SanctionsEntry struct {
+	UID     string   `json:"uid"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Program string   `json:"program,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// SanctionsMatch is a listed name close enough to the name of a user.
+type SanctionsMatch struct {
+	EntryUID    string  `json:"entry_uid" bson:"entry_uid"`
+	ListedName  string  `json:"listed_name" bson:"listed_name"`
+	MatchedName string  `json:"matched_name" bson:"matched_name"`
+	Program     string  `json:"program,omitempty" bson:"program,omitempty"`
+	Score       float64 `json:"score" bson:"score"`
+}
+
+// Statuses of a sanctions screening.
+const (
+	SanctionsPending   = "pending"
+	SanctionsCleared   = "cleared"
+	SanctionsConfirmed = "confirmed"
+)
+
+// Reasons a user is screened.
+const (
+	SanctionsAtRegistration = "registration"
+	SanctionsRescreen       = "rescreen"
+)
+
+// SanctionsScreening is a user whose name matched the sanctions list,
+// waiting for or decided by a compliance analyst.
+type SanctionsScreening struct {
+	ID          string           `json:"id" bson:"_id,omitempty"`
+	UserID      string           `json:"user_id" bson:"user_id"`
+	UserName    string           `json:"user_name" bson:"user_name"`
+	Reason      string           `json:"reason" bson:"reason"`
+	ListVersion string           `json:"list_version" bson:"list_version"`
+	Matches     []SanctionsMatch `json:"matches" bson:"matches"`
+	Status      string           `json:"status" bson:"status"`
+	Reviewer    string           `json:"reviewer,omitempty" bson:"reviewer,omitempty"`
+	Note        string           `json:"note,omitempty" bson:"note,omitempty"`
+	Created_at  time.Time        `json:"created_at" bson:"created_at"`
+	Reviewed_at *time.Time       `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,82 @@
+package entities
+
+import "time"
+
+// Schedule frequencies.
+const (
+	FrequencyOnce    = "once"
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+)
+
+// LastDayOfMonth as DayOfMonth runs a monthly schedule at the end of every
+// month.
+const LastDayOfMonth = -1
+
+// Schedule statuses.
+const (
+	ScheduleActive    = "active"
+	ScheduleCompleted = "completed"
+	ScheduleCancelled = "cancelled"
+	ScheduleFailed    = "failed"
+)
+
+// Execution statuses.
+const (
+	ExecutionSucceeded = "succeeded"
+	ExecutionRetrying  = "retrying"
+	ExecutionFailed    = "failed"
+	// ExecutionHeld waits for the analysts to decide on a transfer the
+	// screening rules held.
+	ExecutionHeld = "held"
+)
+
+// ScheduledPayment is a transfer made by the scheduler, once at StartAt or
+// repeatedly every Interval days, weeks or months. Monthly schedules run on
+// DayOfMonth, or on the last day of shorter months, and keep the time of day
+// of StartAt. A schedule ends after EndAt or after MaxRuns runs, when set.
+type ScheduledPayment struct {
+	ID             string     `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID         string     `json:"user_id" bson:"user_id"`
+	RecipientEmail string     `json:"recipient_email,omitempty" bson:"recipient_email,omitempty"`
+	RecipientPhone int        `json:"recipient_phone,omitempty" bson:"recipient_phone,omitempty"`
+	RecipientDNI   int        `json:"recipient_dni,omitempty" bson:"recipient_dni,omitempty"`
+	Amount         int64      `json:"amount" bson:"amount"`
+	Currency       string     `json:"currency,omitempty" bson:"currency,omitempty"`
+	Memo           string     `json:"memo,omitempty" bson:"memo,omitempty"`
+	Frequency      string     `json:"frequency" bson:"frequency"`
+	Interval       int        `json:"interval,omitempty" bson:"interval,omitempty"`
+	DayOfMonth     int        `json:"day_of_month,omitempty" bson:"day_of_month,omitempty"`
+	StartAt        time.Time  `json:"start_at" bson:"start_at"`
+	EndAt          *time.Time `json:"end_at,omitempty" bson:"end_at,omitempty"`
+	MaxRuns        int        `json:"max_runs,omitempty" bson:"max_runs,omitempty"`
+	Status         string     `json:"status" bson:"status"`
+	NextRunAt      time.Time  `json:"next_run_at" bson:"next_run_at"`
+	DueAt          time.Time  `json:"due_at" bson:"due_at"`
+	Attempts       int        `json:"attempts" bson:"attempts"`
+	Runs           int        `json:"runs" bson:"runs"`
+	LockedUntil    time.Time  `json:"-" bson:"locked_until"`
+	Created_at     time.Time  `json:"created_at" bson:"created_at"`
+	Update_at      time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// ScheduleExecution records one attempt to run a schedule.
+type ScheduleExecution struct {
+	ID         string    `json:"id,omitempty" bson:"_id,omitempty"`
+	ScheduleID string    `json:"schedule_id" bson:"schedule_id"`
+	DueAt      time.Time `json:"due_at" bson:"due_at"`
+	Attempt    int       `json:"attempt" bson:"attempt"`
+	Status     string    `json:"status" bson:"status"`
+	TransferID string    `json:"transfer_id,omitempty" bson:"transfer_id,omitempty"`
+	ReviewID   string    `json:"review_id,omitempty" bson:"review_id,omitempty"`
+	Error      string    `json:"error,omitempty" bson:"error,omitempty"`
+	Created_at time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Lease is held by the replica that runs the background jobs named by ID.
+type Lease struct {
+	ID        string    `bson:"_id"`
+	Holder    string    `bson:"holder"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
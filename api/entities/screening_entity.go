@@ -0,0 +1,94 @@
+package entities
+
+import "time"
+
+// Screening rule kinds.
+const (
+	// RuleAmountAbove matches transactions of more than Threshold.
+	RuleAmountAbove = "amount_above"
+	// RuleNewDevice matches transactions of more than Threshold from a
+	// device the user has not completed a transaction from before.
+	// Transactions that do not name their device are not matched.
+	RuleNewDevice = "new_device"
+	// RuleRapidSuccession matches the Count-th transaction of the user within
+	// WindowMinutes.
+	RuleRapidSuccession = "rapid_succession"
+	// RuleNewCounterparty matches the first transfer of the user to a
+	// recipient of more than Threshold.
+	RuleNewCounterparty = "new_counterparty"
+	// RuleStructuring matches transactions that, each under Threshold, add
+	// up to Threshold or more within WindowMinutes over at least Count of
+	// them.
+	RuleStructuring = "structuring"
+)
+
+// Screening actions, from the least to the most severe.
+const (
+	ScreeningAllow = "allow"
+	ScreeningHold  = "hold"
+	ScreeningBlock = "block"
+)
+
+var screeningSeverity = map[string]int{ScreeningAllow: 0, ScreeningHold: 1, ScreeningBlock: 2}
+
+// MoreSevere reports whether action a is more severe than action b.
+func MoreSevere(a string, b string) bool {
+	return screeningSeverity[a] > screeningSeverity[b]
+}
+
+// ScreeningRule is a declarative fraud or AML rule. Zero fields do not
+// narrow the rule: a rule without Currency applies to every currency.
+type ScreeningRule struct {
+	ID            string `json:"id" bson:"_id"`
+	Name          string `json:"name" bson:"name"`
+	Kind          string `json:"kind" bson:"kind"`
+	Action        string `json:"action" bson:"action"`
+	Enabled       bool   `json:"enabled" bson:"enabled"`
+	Currency      string `json:"currency,omitempty" bson:"currency,omitempty"`
+	Threshold     int64  `json:"threshold,omitempty" bson:"threshold,omitempty"`
+	Count         int    `json:"count,omitempty" bson:"count,omitempty"`
+	WindowMinutes int    `json:"window_minutes,omitempty" bson:"window_minutes,omitempty"`
+}
+
+// ScreeningInput is what a transaction is screened on.
+type ScreeningInput struct {
+	Operation          string
+	UserID             string
+	WalletID           string
+	CounterpartyUserID string
+	DeviceID           string
+	Currency           string
+	Amount             int64
+}
+
+// ScreeningResult is the action the rules decided on and the rules that
+// matched.
+type ScreeningResult struct {
+	Action string   `json:"action" bson:"action"`
+	Rules  []string `json:"rules" bson:"rules"`
+}
+
+// Screening review statuses.
+const (
+	ReviewPending  = "pending"
+	ReviewReleased = "released"
+	ReviewRejected = "rejected"
+	ReviewFailed   = "failed"
+)
+
+// ScreeningReview is a transaction held by the rules until an analyst
+// releases or rejects it. Released transactions that can no longer be
+// executed, for instance for lack of funds, end up failed.
+type ScreeningReview struct {
+	ID          string          `json:"id" bson:"_id,omitempty"`
+	UserID      string          `json:"user_id" bson:"user_id"`
+	Operation   string          `json:"operation" bson:"operation"`
+	Transfer    Transfer        `json:"transfer" bson:"transfer"`
+	Withdrawal  *Withdrawal     `json:"withdrawal,omitempty" bson:"withdrawal,omitempty"`
+	Result      ScreeningResult `json:"result" bson:"result"`
+	Status      string          `json:"status" bson:"status"`
+	Analyst     string          `json:"analyst,omitempty" bson:"analyst,omitempty"`
+	Note        string          `json:"note,omitempty" bson:"note,omitempty"`
+	Created_at  time.Time       `json:"created_at" bson:"created_at"`
+	Reviewed_at *time.Time      `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,49 @@
+package entities
+
+import "time"
+
+// Ways a split divides its amount.
+const (
+	SplitEven   = "even"
+	SplitShares = "shares"
+	SplitExact  = "exact"
+)
+
+// Split statuses. A split is settled once every participant has paid, and
+// cancelled when its payment requests could not all be sent.
+const (
+	SplitOpen      = "open"
+	SplitSettled   = "settled"
+	SplitCancelled = "cancelled"
+)
+
+// Split divides a bill paid by the owner among participants. Each participant
+// other than the owner is sent a payment request for their part; the owner's
+// own part, if they are a participant, counts as paid.
+type Split struct {
+	ID           string             `json:"id,omitempty" bson:"_id,omitempty"`
+	OwnerID      string             `json:"owner_id" bson:"owner_id"`
+	Description  string             `json:"description" bson:"description"`
+	Amount       int64              `json:"amount" bson:"amount"`
+	Currency     string             `json:"currency" bson:"currency"`
+	Method       string             `json:"method" bson:"method"`
+	Participants []SplitParticipant `json:"participants" bson:"participants"`
+	PaidAmount   int64              `json:"paid_amount" bson:"paid_amount"`
+	Status       string             `json:"status" bson:"status"`
+	Created_at   time.Time          `json:"created_at" bson:"created_at"`
+	Update_at    time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// SplitParticipant is one person's part of a split. Shares is only used by
+// the shares method and Amount is given up front only by the exact method.
+// Status follows the participant's payment request.
+type SplitParticipant struct {
+	UserID           string `json:"user_id" bson:"user_id"`
+	Email            string `json:"email,omitempty" bson:"email,omitempty"`
+	Phone            int    `json:"phone,omitempty" bson:"phone,omitempty"`
+	DNI              int    `json:"dni,omitempty" bson:"dni,omitempty"`
+	Shares           int64  `json:"shares,omitempty" bson:"shares,omitempty"`
+	Amount           int64  `json:"amount" bson:"amount"`
+	PaymentRequestID string `json:"payment_request_id,omitempty" bson:"payment_request_id,omitempty"`
+	Status           string `json:"status" bson:"status"`
+}
@@ -2,8 +2,17 @@ package entities
 
 import "time"
 
+// RoleAdmin is given to users allowed to use the back office endpoints.
+const RoleAdmin = "admin"
+
+// RoleSupport is given to support staff, who review KYC applications.
+const RoleSupport = "support"
+
+// RoleAnalyst is given to fraud analysts, who review held transactions.
+const RoleAnalyst = "analyst"
+
 type User struct {
-	ID           string    `json:"id,omitempty" bson:"id,omitempty"`
+	ID           string    `json:"id,omitempty" bson:"_id,omitempty"`
 	TypeDNI      string    `validate:"required"`
 	DNI          int       `validate:"required"`
 	Name         string    `validate:"required"`
@@ -12,8 +21,56 @@ type User struct {
 	Address      string    `validate:"required"`
 	Phone        int       `validate:"required"`
 	Enabled      bool      `validate:"required"`
+	Role         string    `json:"role,omitempty" bson:"role,omitempty"`
+	Segment      string    `json:"segment,omitempty" bson:"segment,omitempty"`
+	KYCLevel     string    `json:"kyc_level,omitempty" bson:"kyc_level,omitempty"`
+	Status       string    `json:"status,omitempty" bson:"status,omitempty"`
 	Token        string    `json:"token"`
 	Created_at   time.Time `json:"created_at"`
 	RefreshToken string    `json:"refresh_token"`
 	Update_at    time.Time `json:"updated_at"`
 }
+
+// User statuses. Users without a status are active.
+const (
+	UserActive        = "active"
+	UserPendingReview = "pending_review"
+	UserBlocked       = "blocked"
+)
+
+// Active reports whether the user may move money; users held for a
+// compliance review may not.
+func (u User) Active() bool {
+	return u.Status == "" || u.Status == UserActive
+}
+
+// KYC levels, from the least to the most verified.
+const (
+	KYCNone  = "none"
+	KYCBasic = "basic"
+	KYCFull  = "full"
+)
+
+var kycRanks = map[string]int{KYCNone: 0, KYCBasic: 1, KYCFull: 2}
+
+// ValidKYCLevel reports whether level is one of the KYC levels.
+func ValidKYCLevel(level string) bool {
+	_, ok := kycRanks[level]
+	return ok
+}
+
+// KYCAtLeast reports whether level is min or a more verified one.
+func KYCAtLeast(level string, min string) bool {
+	if level == "" {
+		level = KYCNone
+	}
+	return kycRanks[level] >= kycRanks[min]
+}
+
+// KYC returns the KYC level of the user; users never verified are at none.
+func (u User) KYC() string {
+	if u.KYCLevel == "" {
+		return KYCNone
+	}
+	return u.KYCLevel
+}
@@ -0,0 +1,235 @@
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultCurrency is the currency used when a request does not name one.
+const DefaultCurrency = "COP"
+
+// Transaction types as seen from the wallet that owns the transaction.
+const (
+	TransactionDeposit       = "deposit"
+	TransactionWithdrawal    = "withdrawal"
+	TransactionTransferIn    = "transfer-in"
+	TransactionTransferOut   = "transfer-out"
+	TransactionFee           = "fee"
+	TransactionConversionOut = "conversion-out"
+	TransactionConversionIn  = "conversion-in"
+	TransactionToPocket      = "to-pocket"
+	TransactionFromPocket    = "from-pocket"
+	TransactionReversalIn    = "reversal-in"
+	TransactionReversalOut   = "reversal-out"
+	TransactionPaymentOut    = "payment-out"
+	TransactionPaymentIn     = "payment-in"
+	TransactionInterest      = "interest"
+	TransactionCardPayment   = "card-payment"
+)
+
+// Transaction statuses.
+const (
+	StatusCompleted = "completed"
+	StatusPending   = "pending"
+	StatusFailed    = "failed"
+)
+
+// Ledger entry types.
+const (
+	EntryTransfer   = "transfer"
+	EntryConversion = "conversion"
+	EntryPocket     = "pocket"
+	EntryReversal   = "reversal"
+	EntryWithdrawal = "withdrawal"
+	// EntryMerchantPayment pays a merchant by QR code.
+	EntryMerchantPayment = "merchant-payment"
+	// EntryInterest pays the interest a wallet or pocket accrued.
+	EntryInterest = "interest"
+	// EntryCardHold holds the amount of an approved card authorization.
+	EntryCardHold = "card-hold"
+	// EntryCardClearing settles a card authorization with the network.
+	EntryCardClearing = "card-clearing"
+)
+
+// Ledger accounts that do not belong to a wallet.
+const (
+	// AccountFXPosition holds the currencies bought and sold by the house at
+	// the mid rate of a conversion.
+	AccountFXPosition = "fx:position"
+	// AccountFXGainLoss receives the difference between the mid rate and the
+	// rate given to the customer, including rounding.
+	AccountFXGainLoss = "fx:gain-loss"
+	// AccountFeeRevenue receives the fees charged on money movements.
+	AccountFeeRevenue = "revenue:fees"
+	// AccountPayouts holds the money withdrawn to external bank accounts
+	// until the bank settles the payout.
+	AccountPayouts = "clearing:payouts"
+	// AccountInterestExpense pays the interest earned on balances.
+	AccountInterestExpense = "expense:interest"
+	// AccountCardHolds holds the amounts of approved card authorizations
+	// until the network clears or reverses them.
+	AccountCardHolds = "clearing:card-holds"
+	// AccountCardNetwork holds what the cleared card purchases owe the card
+	// network until it is settled.
+	AccountCardNetwork = "clearing:card-network"
+)
+
+// Wallet holds the balances of a user, one per currency. Currency is the
+// default currency of the wallet. Amounts are always stored in the minor unit
+// of the currency (cents).
+type Wallet struct {
+	ID         string           `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID     string           `json:"user_id" bson:"user_id"`
+	Currency   string           `json:"currency" bson:"currency"`
+	Balances   map[string]int64 `json:"balances" bson:"balances"`
+	Created_at time.Time        `json:"created_at" bson:"created_at"`
+	Update_at  time.Time        `json:"updated_at" bson:"updated_at"`
+}
+
+// LedgerLine moves Amount in or out of a single account. A positive amount
+// credits the account and a negative amount debits it. Lines that belong to
+// a wallet carry its WalletID, and lines that belong to a pocket its
+// PocketID, so the balance can be updated.
+type LedgerLine struct {
+	Account  string `json:"account" bson:"account"`
+	WalletID string `json:"wallet_id,omitempty" bson:"wallet_id,omitempty"`
+	PocketID string `json:"pocket_id,omitempty" bson:"pocket_id,omitempty"`
+	Currency string `json:"currency" bson:"currency"`
+	Amount   int64  `json:"amount" bson:"amount"`
+}
+
+// LedgerEntry is an atomic and balanced group of ledger lines: for every
+// currency the amounts of its lines must add up to zero. Entries are never
+// changed once posted; a reversal is a new entry whose ReversalOf is the ID
+// of the entry it compensates. Reference, when set, is unique: posting the
+// same reference twice fails instead of moving the money again.
+type LedgerEntry struct {
+	ID         string       `json:"id,omitempty" bson:"_id,omitempty"`
+	Type       string       `json:"type" bson:"type"`
+	Memo       string       `json:"memo,omitempty" bson:"memo,omitempty"`
+	ReversalOf string       `json:"reversal_of,omitempty" bson:"reversal_of,omitempty"`
+	Reference  string       `json:"reference,omitempty" bson:"reference,omitempty"`
+	Lines      []LedgerLine `json:"lines" bson:"lines"`
+	Created_at time.Time    `json:"created_at" bson:"created_at"`
+}
+
+// Transaction is the movement of a ledger entry from the point of view of
+// one wallet. Amount is always positive, Type tells the direction.
+// InitiatedBy is the user who made the movement, which on a shared wallet
+// can be a member other than its owner.
+type Transaction struct {
+	ID                   string    `json:"id,omitempty" bson:"_id,omitempty"`
+	EntryID              string    `json:"entry_id" bson:"entry_id"`
+	WalletID             string    `json:"wallet_id" bson:"wallet_id"`
+	UserID               string    `json:"user_id" bson:"user_id"`
+	Type                 string    `json:"type" bson:"type"`
+	Status               string    `json:"status" bson:"status"`
+	Amount               int64     `json:"amount" bson:"amount"`
+	Currency             string    `json:"currency" bson:"currency"`
+	CounterpartyWalletID string    `json:"counterparty_wallet_id,omitempty" bson:"counterparty_wallet_id,omitempty"`
+	CounterpartyUserID   string    `json:"counterparty_user_id,omitempty" bson:"counterparty_user_id,omitempty"`
+	Memo                 string    `json:"memo,omitempty" bson:"memo,omitempty"`
+	Category             string    `json:"category,omitempty" bson:"category,omitempty"`
+	ReversalOf           string    `json:"reversal_of,omitempty" bson:"reversal_of,omitempty"`
+	InitiatedBy          string    `json:"initiated_by,omitempty" bson:"initiated_by,omitempty"`
+	Created_at           time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Transfer sources, for transfers made on behalf of something else.
+const (
+	TransferSourceSchedule       = "schedule"
+	TransferSourcePaymentRequest = "payment_request"
+)
+
+// Transfer is a peer to peer payment between two wallet users. Transfers
+// made by a schedule or to pay a payment request name it in Source and
+// SourceID, so a held one can complete it once released.
+type Transfer struct {
+	ID                string    `json:"id"`
+	SenderUserID      string    `json:"sender_user_id"`
+	SenderWalletID    string    `json:"sender_wallet_id"`
+	RecipientUserID   string    `json:"recipient_user_id"`
+	RecipientWalletID string    `json:"recipient_wallet_id"`
+	RecipientEmail    string    `json:"recipient_email,omitempty"`
+	RecipientPhone    int       `json:"recipient_phone,omitempty"`
+	RecipientDNI      int       `json:"recipient_dni,omitempty"`
+	BeneficiaryID     string    `json:"beneficiary_id,omitempty"`
+	Amount            int64     `json:"amount"`
+	Fee               int64     `json:"fee"`
+	Currency          string    `json:"currency"`
+	Memo              string    `json:"memo,omitempty"`
+	Status            string    `json:"status"`
+	DeviceID          string    `json:"-"`
+	Reference         string    `json:"-"`
+	Source            string    `json:"source,omitempty"`
+	SourceID          string    `json:"source_id,omitempty"`
+	Created_at        time.Time `json:"created_at"`
+}
+
+// WalletAccount returns the ledger account name of a wallet.
+func WalletAccount(walletID string) string {
+	return "wallet:" + walletID
+}
+
+// TransactionFilter narrows the transaction history of a wallet. Zero values
+// mean "no filter". Results are ordered from newest to oldest and paginated
+// with a cursor over (created_at, id).
+type TransactionFilter struct {
+	WalletID     string
+	Currency     string
+	Types        []string
+	Status       string
+	MinAmount    int64
+	MaxAmount    int64
+	From         time.Time
+	To           time.Time
+	Counterparty string
+	Category     string
+	Member       string
+	Search       string
+	After        *TransactionCursor
+	Limit        int
+}
+
+// TransactionCursor points at the last transaction of a page.
+type TransactionCursor struct {
+	Created_at time.Time
+	ID         string
+}
+
+// TransactionPage is one page of the transaction history.
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+}
+
+// SignedAmount returns the amount of the transaction as it moved the wallet
+// balance: positive for money coming in, negative for money going out.
+func (t Transaction) SignedAmount() int64 {
+	switch t.Type {
+	case TransactionDeposit, TransactionTransferIn, TransactionConversionIn, TransactionFromPocket, TransactionReversalIn, TransactionPaymentIn, TransactionInterest:
+		return t.Amount
+	default:
+		return -t.Amount
+	}
+}
+
+// Statement formats.
+const (
+	StatementCSV   = "csv"
+	StatementJSONL = "jsonl"
+	StatementPDF   = "pdf"
+)
+
+// Statement describes the movements of a wallet in [From, To). Transactions
+// are not loaded in memory: Stream walks them oldest first.
+type Statement struct {
+	WalletID       string                                                      `json:"wallet_id"`
+	UserName       string                                                      `json:"user_name"`
+	Currency       string                                                      `json:"currency"`
+	From           time.Time                                                   `json:"from"`
+	To             time.Time                                                   `json:"to"`
+	OpeningBalance int64                                                       `json:"opening_balance"`
+	ClosingBalance int64                                                       `json:"closing_balance"`
+	Stream         func(ctx context.Context, fn func(Transaction) error) error `json:"-"`
+}
@@ -0,0 +1,153 @@
+package repository_beneficiary
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BeneficiaryRepository interface {
+	CreateBeneficiary(beneficiary entities.Beneficiary, ctx context.Context) (entities.Beneficiary, error)
+	GetBeneficiary(id string, ctx context.Context) (entities.Beneficiary, error)
+	ListBeneficiaries(userID string, ctx context.Context) ([]entities.Beneficiary, error)
+	UpdateBeneficiary(id string, userID string, nickname string, favorite bool, ctx context.Context) (entities.Beneficiary, error)
+	DeleteBeneficiary(id string, userID string, ctx context.Context) error
+	TouchBeneficiary(id string, at time.Time, ctx context.Context) error
+}
+
+type MongoBeneficiaryRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoBeneficiaryRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoBeneficiaryRepository {
+	return &MongoBeneficiaryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes saves each target once per user.
+func (repo *MongoBeneficiaryRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("beneficiaries").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "target", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:beneficiary_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// CreateBeneficiary stores a new beneficiary, unless the user already saved
+// its target.
+func (repo *MongoBeneficiaryRepository) CreateBeneficiary(beneficiary entities.Beneficiary, ctx context.Context) (entities.Beneficiary, error) {
+	coll := repo.db.Database("mywallet").Collection("beneficiaries")
+	result, err := coll.InsertOne(ctx, beneficiary)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.Beneficiary{}, ErrBeneficiaryExists
+		}
+		repo.logger.Errorln("Layer:beneficiary_repository ", "Method:CreateBeneficiary ", "Error:", err)
+		return entities.Beneficiary{}, err
+	}
+	beneficiary.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return beneficiary, nil
+}
+
+func (repo *MongoBeneficiaryRepository) GetBeneficiary(id string, ctx context.Context) (entities.Beneficiary, error) {
+	var beneficiary entities.Beneficiary
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return beneficiary, ErrBeneficiaryNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("beneficiaries")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&beneficiary)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return beneficiary, ErrBeneficiaryNotFound
+		}
+		repo.logger.Errorln("Layer:beneficiary_repository ", "Method:GetBeneficiary ", "Error:", err)
+		return beneficiary, err
+	}
+	return beneficiary, nil
+}
+
+// ListBeneficiaries returns the beneficiaries of the user: favorites first,
+// then the most recently used.
+func (repo *MongoBeneficiaryRepository) ListBeneficiaries(userID string, ctx context.Context) ([]entities.Beneficiary, error) {
+	beneficiaries := []entities.Beneficiary{}
+	coll := repo.db.Database("mywallet").Collection("beneficiaries")
+	opts := options.Find().SetSort(bson.D{{Key: "favorite", Value: -1}, {Key: "last_used_at", Value: -1}, {Key: "nickname", Value: 1}})
+	cursor, err := coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:beneficiary_repository ", "Method:ListBeneficiaries ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &beneficiaries); err != nil {
+		repo.logger.Errorln("Layer:beneficiary_repository ", "Method:ListBeneficiaries ", "Error:", err)
+		return nil, err
+	}
+	return beneficiaries, nil
+}
+
+// UpdateBeneficiary changes the nickname and favorite flag of a beneficiary
+// of the user. The target cannot change: a new one is a new beneficiary,
+// with its own cooling-off period.
+func (repo *MongoBeneficiaryRepository) UpdateBeneficiary(id string, userID string, nickname string, favorite bool, ctx context.Context) (entities.Beneficiary, error) {
+	var beneficiary entities.Beneficiary
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return beneficiary, ErrBeneficiaryNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("beneficiaries")
+	update := bson.M{"$set": bson.M{"nickname": nickname, "favorite": favorite, "updated_at": time.Now().UTC()}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = coll.FindOneAndUpdate(ctx, bson.M{"_id": idd, "user_id": userID}, update, opts).Decode(&beneficiary)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return beneficiary, ErrBeneficiaryNotFound
+		}
+		repo.logger.Errorln("Layer:beneficiary_repository ", "Method:UpdateBeneficiary ", "Error:", err)
+		return beneficiary, err
+	}
+	return beneficiary, nil
+}
+
+func (repo *MongoBeneficiaryRepository) DeleteBeneficiary(id string, userID string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrBeneficiaryNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("beneficiaries")
+	res, err := coll.DeleteOne(ctx, bson.M{"_id": idd, "user_id": userID})
+	if err != nil {
+		repo.logger.Errorln("Layer:beneficiary_repository ", "Method:DeleteBeneficiary ", "Error:", err)
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrBeneficiaryNotFound
+	}
+	return nil
+}
+
+// TouchBeneficiary records that money was sent to a beneficiary at the
+// given time.
+func (repo *MongoBeneficiaryRepository) TouchBeneficiary(id string, at time.Time, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrBeneficiaryNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("beneficiaries")
+	_, err = coll.UpdateOne(ctx, bson.M{"_id": idd}, bson.M{"$max": bson.M{"last_used_at": at}})
+	if err != nil {
+		repo.logger.Errorln("Layer:beneficiary_repository ", "Method:TouchBeneficiary ", "Error:", err)
+	}
+	return err
+}
@@ -0,0 +1,6 @@
+package repository_beneficiary
+
+import "errors"
+
+var ErrBeneficiaryNotFound = errors.New("Error not found beneficiary")
+var ErrBeneficiaryExists = errors.New("Beneficiary already saved")
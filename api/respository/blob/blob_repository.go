@@ -0,0 +1,94 @@
+package repository_blob
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BlobStore keeps opaque contents, such as uploaded documents, by key. Keys
+// are slash separated paths.
+type BlobStore interface {
+	PutBlob(key string, data []byte, ctx context.Context) error
+	GetBlob(key string, ctx context.Context) ([]byte, error)
+}
+
+// FileBlobStore keeps blobs as files under a root directory.
+type FileBlobStore struct {
+	root   string
+	logger logrus.FieldLogger
+}
+
+func NewFileBlobStore(root string, logger logrus.FieldLogger) *FileBlobStore {
+	return &FileBlobStore{
+		root:   root,
+		logger: logger,
+	}
+}
+
+// path maps a key to its file, refusing keys that would leave the root.
+func (store *FileBlobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return "", ErrInvalidBlobKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", ErrInvalidBlobKey
+		}
+	}
+	return filepath.Join(store.root, filepath.FromSlash(key)), nil
+}
+
+// PutBlob stores data under key, replacing what was there. The file is
+// written aside and renamed, so readers never see part of it.
+func (store *FileBlobStore) PutBlob(key string, data []byte, ctx context.Context) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		store.logger.Errorln("Layer:blob_repository ", "Method:PutBlob ", "Error:", err)
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		store.logger.Errorln("Layer:blob_repository ", "Method:PutBlob ", "Error:", err)
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		store.logger.Errorln("Layer:blob_repository ", "Method:PutBlob ", "Error:", err)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		store.logger.Errorln("Layer:blob_repository ", "Method:PutBlob ", "Error:", err)
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		store.logger.Errorln("Layer:blob_repository ", "Method:PutBlob ", "Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (store *FileBlobStore) GetBlob(key string, ctx context.Context) ([]byte, error) {
+	path, err := store.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrBlobNotFound
+		}
+		store.logger.Errorln("Layer:blob_repository ", "Method:GetBlob ", "Error:", err)
+		return nil, err
+	}
+	return data, nil
+}
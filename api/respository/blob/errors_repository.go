@@ -0,0 +1,6 @@
+package repository_blob
+
+import "errors"
+
+var ErrBlobNotFound = errors.New("Error not found blob")
+var ErrInvalidBlobKey = errors.New("Invalid blob key")
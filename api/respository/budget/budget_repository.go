@@ -0,0 +1,201 @@
+package repository_budget
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BudgetRepository interface {
+	CreateBudget(budget entities.Budget, ctx context.Context) (entities.Budget, error)
+	GetBudget(id string, ctx context.Context) (entities.Budget, error)
+	ListBudgets(userID string, ctx context.Context) ([]entities.Budget, error)
+	FindBudget(walletID string, category string, ctx context.Context) (entities.Budget, error)
+	UpdateLimit(id string, limit int64, ctx context.Context) (entities.Budget, error)
+	DeleteBudget(id string, ctx context.Context) error
+	AddSpend(id string, period string, amount int64, ctx context.Context) (entities.Budget, error)
+	ClaimAlert(id string, period string, threshold int, ctx context.Context) (bool, error)
+}
+
+type MongoBudgetRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoBudgetRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoBudgetRepository {
+	return &MongoBudgetRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes allows a single budget per category of a wallet.
+func (repo *MongoBudgetRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("budgets").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "category", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:budget_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoBudgetRepository) CreateBudget(budget entities.Budget, ctx context.Context) (entities.Budget, error) {
+	coll := repo.db.Database("mywallet").Collection("budgets")
+	result, err := coll.InsertOne(ctx, budget)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.Budget{}, ErrBudgetExists
+		}
+		repo.logger.Errorln("Layer:budget_repository ", "Method:CreateBudget ", "Error:", err)
+		return entities.Budget{}, err
+	}
+	budget.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return budget, nil
+}
+
+func (repo *MongoBudgetRepository) GetBudget(id string, ctx context.Context) (entities.Budget, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Budget{}, ErrBudgetNotFound
+	}
+	return repo.findOne(bson.M{"_id": idd}, "GetBudget", ctx)
+}
+
+// FindBudget returns the budget of a category of the wallet.
+func (repo *MongoBudgetRepository) FindBudget(walletID string, category string, ctx context.Context) (entities.Budget, error) {
+	return repo.findOne(bson.M{"wallet_id": walletID, "category": category}, "FindBudget", ctx)
+}
+
+// ListBudgets returns the budgets of the user, oldest first.
+func (repo *MongoBudgetRepository) ListBudgets(userID string, ctx context.Context) ([]entities.Budget, error) {
+	budgets := []entities.Budget{}
+	coll := repo.db.Database("mywallet").Collection("budgets")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:budget_repository ", "Method:ListBudgets ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &budgets); err != nil {
+		repo.logger.Errorln("Layer:budget_repository ", "Method:ListBudgets ", "Error:", err)
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// UpdateLimit changes the monthly limit of a budget. Alerts already sent this
+// month are not sent again.
+func (repo *MongoBudgetRepository) UpdateLimit(id string, limit int64, ctx context.Context) (entities.Budget, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Budget{}, ErrBudgetNotFound
+	}
+	return repo.findOneAndUpdate(bson.M{"_id": idd},
+		bson.M{"$set": bson.M{"limit": limit, "update_at": time.Now().UTC()}},
+		"UpdateLimit", ctx)
+}
+
+func (repo *MongoBudgetRepository) DeleteBudget(id string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrBudgetNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("budgets")
+	result, err := coll.DeleteOne(ctx, bson.M{"_id": idd})
+	if err != nil {
+		repo.logger.Errorln("Layer:budget_repository ", "Method:DeleteBudget ", "Error:", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+// AddSpend adds amount to what was spent in period and returns the budget as
+// it is afterwards. The first spend of a new period starts the count and the
+// alerts over. Both cases are single updates, so concurrent postings from
+// several replicas are all counted. Spend of a month that is already closed
+// is not counted.
+func (repo *MongoBudgetRepository) AddSpend(id string, period string, amount int64, ctx context.Context) (entities.Budget, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Budget{}, ErrBudgetNotFound
+	}
+	for {
+		budget, err := repo.findOneAndUpdate(bson.M{"_id": idd, "period": period},
+			bson.M{"$inc": bson.M{"spent": amount}},
+			"AddSpend", ctx)
+		if err != ErrBudgetNotFound {
+			return budget, err
+		}
+		budget, err = repo.findOneAndUpdate(bson.M{"_id": idd, "period": bson.M{"$lt": period}},
+			bson.M{"$set": bson.M{"period": period, "spent": amount, "alerted": 0}},
+			"AddSpend", ctx)
+		if err != ErrBudgetNotFound {
+			return budget, err
+		}
+		// The budget is gone, tracks a later month or another posting
+		// started the period first; only in the last case to try again.
+		budget, err = repo.GetBudget(id, ctx)
+		if err != nil || budget.Period > period {
+			return budget, err
+		}
+	}
+}
+
+// ClaimAlert records that the alert of threshold was sent for period. Only
+// one caller can claim it, so every alert is sent at most once a month.
+func (repo *MongoBudgetRepository) ClaimAlert(id string, period string, threshold int, ctx context.Context) (bool, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, ErrBudgetNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("budgets")
+	result, err := coll.UpdateOne(ctx,
+		bson.M{"_id": idd, "period": period, "alerted": bson.M{"$lt": threshold}},
+		bson.M{"$set": bson.M{"alerted": threshold}},
+	)
+	if err != nil {
+		repo.logger.Errorln("Layer:budget_repository ", "Method:ClaimAlert ", "Error:", err)
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (repo *MongoBudgetRepository) findOne(query bson.M, method string, ctx context.Context) (entities.Budget, error) {
+	var budget entities.Budget
+	coll := repo.db.Database("mywallet").Collection("budgets")
+	err := coll.FindOne(ctx, query).Decode(&budget)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return budget, ErrBudgetNotFound
+		}
+		repo.logger.Errorln("Layer:budget_repository ", "Method:"+method+" ", "Error:", err)
+		return budget, err
+	}
+	return budget, nil
+}
+
+func (repo *MongoBudgetRepository) findOneAndUpdate(query bson.M, update bson.M, method string, ctx context.Context) (entities.Budget, error) {
+	var budget entities.Budget
+	coll := repo.db.Database("mywallet").Collection("budgets")
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := coll.FindOneAndUpdate(ctx, query, update, opts).Decode(&budget)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return budget, ErrBudgetNotFound
+		}
+		repo.logger.Errorln("Layer:budget_repository ", "Method:"+method+" ", "Error:", err)
+		return budget, err
+	}
+	return budget, nil
+}
@@ -0,0 +1,6 @@
+package repository_budget
+
+import "errors"
+
+var ErrBudgetNotFound = errors.New("Error not found budget")
+var ErrBudgetExists = errors.New("Budget already exists")
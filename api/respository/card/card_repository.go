@@ -0,0 +1,254 @@
+package repository_card
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CardRepository interface {
+	CreateCard(card entities.Card, ctx context.Context) (entities.Card, error)
+	GetCard(id string, ctx context.Context) (entities.Card, error)
+	GetCardByToken(token string, ctx context.Context) (entities.Card, error)
+	ListCards(userID string, ctx context.Context) ([]entities.Card, error)
+	UpdateCard(card entities.Card, ctx context.Context) (entities.Card, error)
+	CreateAuthorization(authorization entities.CardAuthorization, ctx context.Context) (entities.CardAuthorization, error)
+	GetAuthorizationByMessage(messageID string, ctx context.Context) (entities.CardAuthorization, error)
+	ListAuthorizations(cardID string, limit int64, ctx context.Context) ([]entities.CardAuthorization, error)
+	UpdateAuthorization(authorization entities.CardAuthorization, from string, ctx context.Context) error
+	ReserveSpending(key string, amount int64, max int64, expiresAt time.Time, ctx context.Context) (int64, error)
+	ReleaseSpending(key string, amount int64, ctx context.Context) error
+}
+
+type MongoCardRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoCardRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoCardRepository {
+	return &MongoCardRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes finds cards by token and user, makes every message of the
+// network create a single authorization, and lets Mongo remove the spending
+// of windows that are over.
+func (repo *MongoCardRepository) CreateIndexes(ctx context.Context) error {
+	database := repo.db.Database("mywallet")
+	_, err := database.Collection("cards").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:CreateIndexes ", "Error:", err)
+		return err
+	}
+	_, err = database.Collection("card_authorizations").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "message_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "card_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:CreateIndexes ", "Error:", err)
+		return err
+	}
+	_, err = database.Collection("card_spending").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoCardRepository) CreateCard(card entities.Card, ctx context.Context) (entities.Card, error) {
+	coll := repo.db.Database("mywallet").Collection("cards")
+	result, err := coll.InsertOne(ctx, card)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.Card{}, ErrCardExists
+		}
+		repo.logger.Errorln("Layer:card_repository ", "Method:CreateCard ", "Error:", err)
+		return entities.Card{}, err
+	}
+	card.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return card, nil
+}
+
+func (repo *MongoCardRepository) GetCard(id string, ctx context.Context) (entities.Card, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Card{}, ErrCardNotFound
+	}
+	return repo.findCard(bson.M{"_id": idd}, ctx)
+}
+
+func (repo *MongoCardRepository) GetCardByToken(token string, ctx context.Context) (entities.Card, error) {
+	return repo.findCard(bson.M{"token": token}, ctx)
+}
+
+func (repo *MongoCardRepository) findCard(filter bson.M, ctx context.Context) (entities.Card, error) {
+	var card entities.Card
+	coll := repo.db.Database("mywallet").Collection("cards")
+	err := coll.FindOne(ctx, filter).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return card, ErrCardNotFound
+		}
+		repo.logger.Errorln("Layer:card_repository ", "Method:findCard ", "Error:", err)
+		return card, err
+	}
+	return card, nil
+}
+
+func (repo *MongoCardRepository) ListCards(userID string, ctx context.Context) ([]entities.Card, error) {
+	coll := repo.db.Database("mywallet").Collection("cards")
+	cursor, err := coll.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:ListCards ", "Error:", err)
+		return nil, err
+	}
+	cards := []entities.Card{}
+	if err := cursor.All(ctx, &cards); err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:ListCards ", "Error:", err)
+		return nil, err
+	}
+	return cards, nil
+}
+
+// UpdateCard saves the name, status and limits of a card, the only fields
+// that change once it is issued.
+func (repo *MongoCardRepository) UpdateCard(card entities.Card, ctx context.Context) (entities.Card, error) {
+	idd, err := primitive.ObjectIDFromHex(card.ID)
+	if err != nil {
+		return entities.Card{}, ErrCardNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("cards")
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": idd}, bson.M{"$set": bson.M{
+		"name":       card.Name,
+		"status":     card.Status,
+		"limits":     card.Limits,
+		"updated_at": card.Update_at,
+	}})
+	if err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:UpdateCard ", "Error:", err)
+		return entities.Card{}, err
+	}
+	if result.MatchedCount == 0 {
+		return entities.Card{}, ErrCardNotFound
+	}
+	return card, nil
+}
+
+// CreateAuthorization stores an authorization, failing with
+// ErrAuthorizationExists when its message was already received.
+func (repo *MongoCardRepository) CreateAuthorization(authorization entities.CardAuthorization, ctx context.Context) (entities.CardAuthorization, error) {
+	coll := repo.db.Database("mywallet").Collection("card_authorizations")
+	result, err := coll.InsertOne(ctx, authorization)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.CardAuthorization{}, ErrAuthorizationExists
+		}
+		repo.logger.Errorln("Layer:card_repository ", "Method:CreateAuthorization ", "Error:", err)
+		return entities.CardAuthorization{}, err
+	}
+	authorization.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return authorization, nil
+}
+
+func (repo *MongoCardRepository) GetAuthorizationByMessage(messageID string, ctx context.Context) (entities.CardAuthorization, error) {
+	var authorization entities.CardAuthorization
+	coll := repo.db.Database("mywallet").Collection("card_authorizations")
+	err := coll.FindOne(ctx, bson.M{"message_id": messageID}).Decode(&authorization)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return authorization, ErrAuthorizationNotFound
+		}
+		repo.logger.Errorln("Layer:card_repository ", "Method:GetAuthorizationByMessage ", "Error:", err)
+		return authorization, err
+	}
+	return authorization, nil
+}
+
+// ListAuthorizations returns the last limit authorizations of a card, newest
+// first.
+func (repo *MongoCardRepository) ListAuthorizations(cardID string, limit int64, ctx context.Context) ([]entities.CardAuthorization, error) {
+	coll := repo.db.Database("mywallet").Collection("card_authorizations")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := coll.Find(ctx, bson.M{"card_id": cardID}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:ListAuthorizations ", "Error:", err)
+		return nil, err
+	}
+	authorizations := []entities.CardAuthorization{}
+	if err := cursor.All(ctx, &authorizations); err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:ListAuthorizations ", "Error:", err)
+		return nil, err
+	}
+	return authorizations, nil
+}
+
+// UpdateAuthorization saves an authorization that is still in the from
+// status. It fails with ErrAuthorizationStatusChanged otherwise, so two
+// messages cannot both clear or reverse it.
+func (repo *MongoCardRepository) UpdateAuthorization(authorization entities.CardAuthorization, from string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(authorization.ID)
+	if err != nil {
+		return ErrAuthorizationNotFound
+	}
+	authorization.ID = ""
+	coll := repo.db.Database("mywallet").Collection("card_authorizations")
+	result, err := coll.ReplaceOne(ctx, bson.M{"_id": idd, "status": from}, authorization)
+	if err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:UpdateAuthorization ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrAuthorizationStatusChanged
+	}
+	return nil
+}
+
+// ReserveSpending adds amount to the spending of the card window named key,
+// provided it stays within max, and returns the spending afterwards.
+// Otherwise it returns ErrCardLimitReached. The check and the increment are
+// a single update, so authorizations decided at the same time cannot go over
+// the limit together.
+func (repo *MongoCardRepository) ReserveSpending(key string, amount int64, max int64, expiresAt time.Time, ctx context.Context) (int64, error) {
+	coll := repo.db.Database("mywallet").Collection("card_spending")
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var spending struct {
+		Spent int64 `bson:"spent"`
+	}
+	err := coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": key, "spent": bson.M{"$lte": max - amount}},
+		bson.M{"$inc": bson.M{"spent": amount}, "$setOnInsert": bson.M{"expires_at": expiresAt}},
+		opts,
+	).Decode(&spending)
+	if mongo.IsDuplicateKeyError(err) {
+		return 0, ErrCardLimitReached
+	}
+	if err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:ReserveSpending ", "Error:", err)
+		return 0, err
+	}
+	return spending.Spent, nil
+}
+
+// ReleaseSpending gives back amount reserved in the card window named key.
+func (repo *MongoCardRepository) ReleaseSpending(key string, amount int64, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("card_spending")
+	_, err := coll.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$inc": bson.M{"spent": -amount}})
+	if err != nil {
+		repo.logger.Errorln("Layer:card_repository ", "Method:ReleaseSpending ", "Error:", err)
+	}
+	return err
+}
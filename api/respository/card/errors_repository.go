@@ -0,0 +1,10 @@
+package repository_card
+
+import "errors"
+
+var ErrCardNotFound = errors.New("Error not found card")
+var ErrCardExists = errors.New("Card number already issued")
+var ErrAuthorizationNotFound = errors.New("Error not found card authorization")
+var ErrAuthorizationExists = errors.New("Card authorization already received")
+var ErrAuthorizationStatusChanged = errors.New("Card authorization status changed")
+var ErrCardLimitReached = errors.New("Card spending limit reached")
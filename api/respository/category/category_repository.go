@@ -0,0 +1,130 @@
+package repository_category
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryRepository interface {
+	ListCategories(userID string, ctx context.Context) ([]entities.Category, error)
+	CreateCategory(category entities.Category, ctx context.Context) (entities.Category, error)
+	ListRules(userID string, ctx context.Context) ([]entities.CategoryRule, error)
+	CreateRule(rule entities.CategoryRule, ctx context.Context) (entities.CategoryRule, error)
+	DeleteRule(id string, userID string, ctx context.Context) error
+}
+
+type MongoCategoryRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoCategoryRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoCategoryRepository {
+	return &MongoCategoryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes makes category slugs unique per user and orders the rules of
+// a user the way they are evaluated.
+func (repo *MongoCategoryRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("categories").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:category_repository ", "Method:CreateIndexes ", "Error:", err)
+		return err
+	}
+	_, err = repo.db.Database("mywallet").Collection("category_rules").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "priority", Value: -1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:category_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// ListCategories returns the custom categories of the user sorted by name.
+func (repo *MongoCategoryRepository) ListCategories(userID string, ctx context.Context) ([]entities.Category, error) {
+	categories := []entities.Category{}
+	coll := repo.db.Database("mywallet").Collection("categories")
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:category_repository ", "Method:ListCategories ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &categories); err != nil {
+		repo.logger.Errorln("Layer:category_repository ", "Method:ListCategories ", "Error:", err)
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (repo *MongoCategoryRepository) CreateCategory(category entities.Category, ctx context.Context) (entities.Category, error) {
+	coll := repo.db.Database("mywallet").Collection("categories")
+	result, err := coll.InsertOne(ctx, category)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.Category{}, ErrCategoryExists
+		}
+		repo.logger.Errorln("Layer:category_repository ", "Method:CreateCategory ", "Error:", err)
+		return entities.Category{}, err
+	}
+	category.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return category, nil
+}
+
+// ListRules returns the rules of the user in evaluation order: highest
+// priority first and, among equals, the newest first.
+func (repo *MongoCategoryRepository) ListRules(userID string, ctx context.Context) ([]entities.CategoryRule, error) {
+	rules := []entities.CategoryRule{}
+	coll := repo.db.Database("mywallet").Collection("category_rules")
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: -1}, {Key: "created_at", Value: -1}})
+	cursor, err := coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:category_repository ", "Method:ListRules ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &rules); err != nil {
+		repo.logger.Errorln("Layer:category_repository ", "Method:ListRules ", "Error:", err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (repo *MongoCategoryRepository) CreateRule(rule entities.CategoryRule, ctx context.Context) (entities.CategoryRule, error) {
+	coll := repo.db.Database("mywallet").Collection("category_rules")
+	result, err := coll.InsertOne(ctx, rule)
+	if err != nil {
+		repo.logger.Errorln("Layer:category_repository ", "Method:CreateRule ", "Error:", err)
+		return entities.CategoryRule{}, err
+	}
+	rule.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return rule, nil
+}
+
+// DeleteRule deletes a rule of the user.
+func (repo *MongoCategoryRepository) DeleteRule(id string, userID string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrRuleNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("category_rules")
+	result, err := coll.DeleteOne(ctx, bson.M{"_id": idd, "user_id": userID})
+	if err != nil {
+		repo.logger.Errorln("Layer:category_repository ", "Method:DeleteRule ", "Error:", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
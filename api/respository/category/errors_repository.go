@@ -0,0 +1,6 @@
+package repository_category
+
+import "errors"
+
+var ErrCategoryExists = errors.New("Category already exists")
+var ErrRuleNotFound = errors.New("Error not found category rule")
@@ -0,0 +1,190 @@
+package repository_dispute
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DisputeRepository interface {
+	CreateDispute(dispute entities.Dispute, ctx context.Context) (entities.Dispute, error)
+	GetDispute(id string, ctx context.Context) (entities.Dispute, error)
+	ListDisputes(userID string, limit int64, ctx context.Context) ([]entities.Dispute, error)
+	ListDisputesByStatus(status string, limit int64, ctx context.Context) ([]entities.Dispute, error)
+	AddEvidence(id string, evidence entities.DisputeEvidence, ctx context.Context) (entities.Dispute, error)
+	UpdateStatus(id string, from string, to string, reviewer string, resolution string, ctx context.Context) (entities.Dispute, error)
+	SetReversal(id string, entryID string, amount int64, ctx context.Context) (entities.Dispute, error)
+}
+
+type MongoDisputeRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoDisputeRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoDisputeRepository {
+	return &MongoDisputeRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes allows a single dispute per transaction and keeps the
+// disputes of a user and the review queue in order.
+func (repo *MongoDisputeRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("disputes").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "transaction_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:dispute_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// CreateDispute stores a new dispute, unless the transaction already has
+// one.
+func (repo *MongoDisputeRepository) CreateDispute(dispute entities.Dispute, ctx context.Context) (entities.Dispute, error) {
+	coll := repo.db.Database("mywallet").Collection("disputes")
+	result, err := coll.InsertOne(ctx, dispute)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.Dispute{}, ErrDisputeExists
+		}
+		repo.logger.Errorln("Layer:dispute_repository ", "Method:CreateDispute ", "Error:", err)
+		return entities.Dispute{}, err
+	}
+	dispute.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return dispute, nil
+}
+
+func (repo *MongoDisputeRepository) GetDispute(id string, ctx context.Context) (entities.Dispute, error) {
+	var dispute entities.Dispute
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return dispute, ErrDisputeNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("disputes")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&dispute)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return dispute, ErrDisputeNotFound
+		}
+		repo.logger.Errorln("Layer:dispute_repository ", "Method:GetDispute ", "Error:", err)
+		return dispute, err
+	}
+	return dispute, nil
+}
+
+// ListDisputes returns the latest limit disputes of the user, newest first.
+func (repo *MongoDisputeRepository) ListDisputes(userID string, limit int64, ctx context.Context) ([]entities.Dispute, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	return repo.find(bson.M{"user_id": userID}, opts, "ListDisputes", ctx)
+}
+
+// ListDisputesByStatus returns up to limit disputes in a status, the ones
+// waiting the longest first.
+func (repo *MongoDisputeRepository) ListDisputesByStatus(status string, limit int64, ctx context.Context) ([]entities.Dispute, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(limit)
+	return repo.find(bson.M{"status": status}, opts, "ListDisputesByStatus", ctx)
+}
+
+// AddEvidence attaches a file to a dispute still open or under review.
+// Decided disputes are not changed and ErrDisputeStatusChanged is returned.
+func (repo *MongoDisputeRepository) AddEvidence(id string, evidence entities.DisputeEvidence, ctx context.Context) (entities.Dispute, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Dispute{}, ErrDisputeNotFound
+	}
+	query := bson.M{"_id": idd, "status": bson.M{"$in": bson.A{entities.DisputeOpened, entities.DisputeUnderReview}}}
+	update := bson.M{
+		"$push": bson.M{"evidence": evidence},
+		"$set":  bson.M{"updated_at": time.Now().UTC()},
+	}
+	dispute, err := repo.findOneAndUpdate(query, update, "AddEvidence", ctx)
+	if err == ErrDisputeNotFound {
+		if _, err := repo.GetDispute(id, ctx); err != nil {
+			return entities.Dispute{}, err
+		}
+		return entities.Dispute{}, ErrDisputeStatusChanged
+	}
+	return dispute, err
+}
+
+// UpdateStatus moves a dispute from one status to another. When two callers
+// race, only the first one moves it; the other gets ErrDisputeStatusChanged.
+// Resolved and rejected disputes record the resolution and when it was made.
+func (repo *MongoDisputeRepository) UpdateStatus(id string, from string, to string, reviewer string, resolution string, ctx context.Context) (entities.Dispute, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Dispute{}, ErrDisputeNotFound
+	}
+	now := time.Now().UTC()
+	set := bson.M{"status": to, "reviewer": reviewer, "updated_at": now}
+	update := bson.M{"$set": set}
+	switch to {
+	case entities.DisputeResolvedInFavor, entities.DisputeRejected:
+		set["resolution"] = resolution
+		set["resolved_at"] = now
+	default:
+		update["$unset"] = bson.M{"resolution": "", "resolved_at": ""}
+	}
+	dispute, err := repo.findOneAndUpdate(bson.M{"_id": idd, "status": from}, update, "UpdateStatus", ctx)
+	if err == ErrDisputeNotFound {
+		if _, err := repo.GetDispute(id, ctx); err != nil {
+			return entities.Dispute{}, err
+		}
+		return entities.Dispute{}, ErrDisputeStatusChanged
+	}
+	return dispute, err
+}
+
+// SetReversal links a dispute resolved in favor of the user to the ledger
+// entry reversing its transaction.
+func (repo *MongoDisputeRepository) SetReversal(id string, entryID string, amount int64, ctx context.Context) (entities.Dispute, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Dispute{}, ErrDisputeNotFound
+	}
+	update := bson.M{"$set": bson.M{"reversal_entry_id": entryID, "reversed_amount": amount, "updated_at": time.Now().UTC()}}
+	return repo.findOneAndUpdate(bson.M{"_id": idd}, update, "SetReversal", ctx)
+}
+
+func (repo *MongoDisputeRepository) find(query bson.M, opts *options.FindOptions, method string, ctx context.Context) ([]entities.Dispute, error) {
+	disputes := []entities.Dispute{}
+	coll := repo.db.Database("mywallet").Collection("disputes")
+	cursor, err := coll.Find(ctx, query, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:dispute_repository ", "Method:"+method+" ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &disputes); err != nil {
+		repo.logger.Errorln("Layer:dispute_repository ", "Method:"+method+" ", "Error:", err)
+		return nil, err
+	}
+	return disputes, nil
+}
+
+func (repo *MongoDisputeRepository) findOneAndUpdate(query bson.M, update bson.M, method string, ctx context.Context) (entities.Dispute, error) {
+	var dispute entities.Dispute
+	coll := repo.db.Database("mywallet").Collection("disputes")
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := coll.FindOneAndUpdate(ctx, query, update, opts).Decode(&dispute)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return dispute, ErrDisputeNotFound
+		}
+		repo.logger.Errorln("Layer:dispute_repository ", "Method:"+method+" ", "Error:", err)
+		return dispute, err
+	}
+	return dispute, nil
+}
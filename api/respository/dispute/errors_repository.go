@@ -0,0 +1,7 @@
+package repository_dispute
+
+import "errors"
+
+var ErrDisputeNotFound = errors.New("Error not found dispute")
+var ErrDisputeExists = errors.New("Transaction already has a dispute")
+var ErrDisputeStatusChanged = errors.New("Dispute status changed")
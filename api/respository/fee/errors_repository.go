@@ -0,0 +1,5 @@
+package repository_fee
+
+import "errors"
+
+var ErrFeeRuleNotFound = errors.New("Error not found fee rule")
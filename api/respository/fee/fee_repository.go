@@ -0,0 +1,131 @@
+package repository_fee
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FeeRuleRepository interface {
+	ListFeeRules(operation string, ctx context.Context) ([]entities.FeeRule, error)
+	GetFeeRule(id string, ctx context.Context) (entities.FeeRule, error)
+	CreateFeeRule(rule entities.FeeRule, ctx context.Context) (entities.FeeRule, error)
+	UpdateFeeRule(rule entities.FeeRule, ctx context.Context) (entities.FeeRule, error)
+	DeleteFeeRule(id string, ctx context.Context) error
+}
+
+type MongoFeeRuleRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoFeeRuleRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoFeeRuleRepository {
+	return &MongoFeeRuleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (repo *MongoFeeRuleRepository) CreateIndexes(ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("fee_rules")
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "operation", Value: 1}, {Key: "enabled", Value: 1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:fee_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// ListFeeRules returns the rules of an operation, or every rule when
+// operation is empty, highest priority first.
+func (repo *MongoFeeRuleRepository) ListFeeRules(operation string, ctx context.Context) ([]entities.FeeRule, error) {
+	rules := []entities.FeeRule{}
+	filter := bson.M{}
+	if operation != "" {
+		filter["operation"] = operation
+	}
+	coll := repo.db.Database("mywallet").Collection("fee_rules")
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: -1}, {Key: "created_at", Value: -1}})
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:fee_repository ", "Method:ListFeeRules ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &rules); err != nil {
+		repo.logger.Errorln("Layer:fee_repository ", "Method:ListFeeRules ", "Error:", err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (repo *MongoFeeRuleRepository) GetFeeRule(id string, ctx context.Context) (entities.FeeRule, error) {
+	var rule entities.FeeRule
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return rule, ErrFeeRuleNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("fee_rules")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&rule)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return rule, ErrFeeRuleNotFound
+		}
+		repo.logger.Errorln("Layer:fee_repository ", "Method:GetFeeRule ", "Error:", err)
+		return rule, err
+	}
+	return rule, nil
+}
+
+func (repo *MongoFeeRuleRepository) CreateFeeRule(rule entities.FeeRule, ctx context.Context) (entities.FeeRule, error) {
+	coll := repo.db.Database("mywallet").Collection("fee_rules")
+	result, err := coll.InsertOne(ctx, rule)
+	if err != nil {
+		repo.logger.Errorln("Layer:fee_repository ", "Method:CreateFeeRule ", "Error:", err)
+		return entities.FeeRule{}, err
+	}
+	rule.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return rule, nil
+}
+
+func (repo *MongoFeeRuleRepository) UpdateFeeRule(rule entities.FeeRule, ctx context.Context) (entities.FeeRule, error) {
+	idd, err := primitive.ObjectIDFromHex(rule.ID)
+	if err != nil {
+		return entities.FeeRule{}, ErrFeeRuleNotFound
+	}
+	id := rule.ID
+	rule.ID = ""
+	coll := repo.db.Database("mywallet").Collection("fee_rules")
+	result, err := coll.ReplaceOne(ctx, bson.M{"_id": idd}, rule)
+	if err != nil {
+		repo.logger.Errorln("Layer:fee_repository ", "Method:UpdateFeeRule ", "Error:", err)
+		return entities.FeeRule{}, err
+	}
+	if result.MatchedCount == 0 {
+		return entities.FeeRule{}, ErrFeeRuleNotFound
+	}
+	rule.ID = id
+	return rule, nil
+}
+
+func (repo *MongoFeeRuleRepository) DeleteFeeRule(id string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrFeeRuleNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("fee_rules")
+	result, err := coll.DeleteOne(ctx, bson.M{"_id": idd})
+	if err != nil {
+		repo.logger.Errorln("Layer:fee_repository ", "Method:DeleteFeeRule ", "Error:", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrFeeRuleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+package repository_fx
+
+import "errors"
+
+var ErrRateNotFound = errors.New("No exchange rate for the currency pair")
+var ErrQuoteNotFound = errors.New("Error not found quote")
+var ErrQuoteUnavailable = errors.New("Quote expired or already used")
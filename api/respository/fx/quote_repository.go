@@ -0,0 +1,110 @@
+package repository_fx
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Expired quotes are kept for a day before Mongo removes them.
+const quoteRetentionSeconds = 24 * 60 * 60
+
+type QuoteRepository interface {
+	CreateQuote(quote entities.FXQuote, ctx context.Context) (entities.FXQuote, error)
+	GetQuote(id string, ctx context.Context) (entities.FXQuote, error)
+	UseQuote(id string, now time.Time, ctx context.Context) error
+	ReopenQuote(id string, ctx context.Context) error
+}
+
+type MongoQuoteRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoQuoteRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoQuoteRepository {
+	return &MongoQuoteRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (repo *MongoQuoteRepository) CreateIndexes(ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("fx_quotes")
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(quoteRetentionSeconds),
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:quote_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoQuoteRepository) CreateQuote(quote entities.FXQuote, ctx context.Context) (entities.FXQuote, error) {
+	coll := repo.db.Database("mywallet").Collection("fx_quotes")
+	result, err := coll.InsertOne(ctx, quote)
+	if err != nil {
+		repo.logger.Errorln("Layer:quote_repository ", "Method:CreateQuote ", "Error:", err)
+		return entities.FXQuote{}, err
+	}
+	quote.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return quote, nil
+}
+
+func (repo *MongoQuoteRepository) GetQuote(id string, ctx context.Context) (entities.FXQuote, error) {
+	var quote entities.FXQuote
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return quote, ErrQuoteNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("fx_quotes")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&quote)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return quote, ErrQuoteNotFound
+		}
+		repo.logger.Errorln("Layer:quote_repository ", "Method:GetQuote ", "Error:", err)
+		return quote, err
+	}
+	return quote, nil
+}
+
+// UseQuote marks an open quote as used. Only one caller can win, and only
+// while the quote has not expired.
+func (repo *MongoQuoteRepository) UseQuote(id string, now time.Time, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrQuoteNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("fx_quotes")
+	filter := bson.M{"_id": idd, "status": entities.QuoteOpen, "expires_at": bson.M{"$gt": now}}
+	result, err := coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"status": entities.QuoteUsed}})
+	if err != nil {
+		repo.logger.Errorln("Layer:quote_repository ", "Method:UseQuote ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrQuoteUnavailable
+	}
+	return nil
+}
+
+// ReopenQuote gives a quote back when the conversion could not be posted.
+func (repo *MongoQuoteRepository) ReopenQuote(id string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrQuoteNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("fx_quotes")
+	_, err = coll.UpdateOne(ctx, bson.M{"_id": idd}, bson.M{"$set": bson.M{"status": entities.QuoteOpen}})
+	if err != nil {
+		repo.logger.Errorln("Layer:quote_repository ", "Method:ReopenQuote ", "Error:", err)
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package repository_fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// RateProvider quotes how many units of the to currency one unit of the from
+// currency buys.
+type RateProvider interface {
+	Rate(from string, to string, ctx context.Context) (*big.Rat, error)
+}
+
+// StaticRateProvider serves a fixed table of rates, for offline use and
+// tests. Missing inverse pairs are derived from the ones that are present.
+type StaticRateProvider struct {
+	rates map[string]*big.Rat
+}
+
+// NewStaticRateProvider builds a provider from rates keyed by "FROM/TO" with
+// decimal string values, for example {"USD/COP": "4150.25"}.
+func NewStaticRateProvider(rates map[string]string) (*StaticRateProvider, error) {
+	provider := &StaticRateProvider{rates: map[string]*big.Rat{}}
+	for pair, value := range rates {
+		currencies := strings.Split(pair, "/")
+		if len(currencies) != 2 {
+			return nil, fmt.Errorf("invalid currency pair %q", pair)
+		}
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid rate %q for %s", value, pair)
+		}
+		provider.rates[pair] = rate
+	}
+	for pair, rate := range provider.rates {
+		currencies := strings.Split(pair, "/")
+		inverse := currencies[1] + "/" + currencies[0]
+		if _, ok := provider.rates[inverse]; !ok {
+			provider.rates[inverse] = new(big.Rat).Inv(rate)
+		}
+	}
+	return provider, nil
+}
+
+// NewFileRateProvider loads a static provider from a JSON file with the same
+// shape accepted by NewStaticRateProvider.
+func NewFileRateProvider(path string) (*StaticRateProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rates map[string]string
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, err
+	}
+	return NewStaticRateProvider(rates)
+}
+
+func (p *StaticRateProvider) Rate(from string, to string, ctx context.Context) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	rate, ok := p.rates[from+"/"+to]
+	if !ok {
+		return nil, ErrRateNotFound
+	}
+	return new(big.Rat).Set(rate), nil
+}
@@ -0,0 +1,6 @@
+package repository_idempotency
+
+import "errors"
+
+var ErrRecordNotFound = errors.New("Error not found idempotency record")
+var ErrRecordExists = errors.New("Idempotency record already exists")
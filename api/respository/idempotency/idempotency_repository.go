@@ -0,0 +1,126 @@
+package repository_idempotency
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IdempotencyRepository interface {
+	CreateRecord(record entities.IdempotencyRecord, ctx context.Context) error
+	GetRecord(scope string, key string, ctx context.Context) (entities.IdempotencyRecord, error)
+	CompleteRecord(scope string, key string, response []byte, ctx context.Context) error
+	HoldRecord(scope string, key string, reviewID string, ctx context.Context) error
+	DeleteRecord(scope string, key string, ctx context.Context) error
+}
+
+type MongoIdempotencyRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoIdempotencyRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoIdempotencyRepository {
+	return &MongoIdempotencyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes makes (scope, key) unique, which is what serializes two
+// requests racing with the same key, and expires records after ExpiresAt.
+func (repo *MongoIdempotencyRepository) CreateIndexes(ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("idempotency_keys")
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "scope", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:idempotency_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoIdempotencyRepository) CreateRecord(record entities.IdempotencyRecord, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("idempotency_keys")
+	_, err := coll.InsertOne(ctx, record)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrRecordExists
+		}
+		repo.logger.Errorln("Layer:idempotency_repository ", "Method:CreateRecord ", "Error:", err)
+		return err
+	}
+	return nil
+}
+
+func (repo *MongoIdempotencyRepository) GetRecord(scope string, key string, ctx context.Context) (entities.IdempotencyRecord, error) {
+	var record entities.IdempotencyRecord
+	coll := repo.db.Database("mywallet").Collection("idempotency_keys")
+	err := coll.FindOne(ctx, bson.M{"scope": scope, "key": key}).Decode(&record)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return record, ErrRecordNotFound
+		}
+		repo.logger.Errorln("Layer:idempotency_repository ", "Method:GetRecord ", "Error:", err)
+		return record, err
+	}
+	return record, nil
+}
+
+func (repo *MongoIdempotencyRepository) CompleteRecord(scope string, key string, response []byte, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("idempotency_keys")
+	update := bson.M{
+		"$set": bson.M{
+			"status":   entities.IdempotencyCompleted,
+			"response": response,
+		},
+	}
+	result, err := coll.UpdateOne(ctx, bson.M{"scope": scope, "key": key}, update)
+	if err != nil {
+		repo.logger.Errorln("Layer:idempotency_repository ", "Method:CompleteRecord ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (repo *MongoIdempotencyRepository) HoldRecord(scope string, key string, reviewID string, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("idempotency_keys")
+	update := bson.M{
+		"$set": bson.M{
+			"status":    entities.IdempotencyCompleted,
+			"review_id": reviewID,
+		},
+	}
+	result, err := coll.UpdateOne(ctx, bson.M{"scope": scope, "key": key}, update)
+	if err != nil {
+		repo.logger.Errorln("Layer:idempotency_repository ", "Method:HoldRecord ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (repo *MongoIdempotencyRepository) DeleteRecord(scope string, key string, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("idempotency_keys")
+	_, err := coll.DeleteOne(ctx, bson.M{"scope": scope, "key": key})
+	if err != nil {
+		repo.logger.Errorln("Layer:idempotency_repository ", "Method:DeleteRecord ", "Error:", err)
+		return err
+	}
+	return nil
+}
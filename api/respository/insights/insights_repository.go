@@ -0,0 +1,259 @@
+package repository_insights
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// rollupState names the document that records how far the daily rollups go.
+const rollupState = "daily"
+
+// RollupState is the progress of the daily rollups: every day before Through
+// is rolled up, and recategorizations before CheckedAt are accounted for.
+type RollupState struct {
+	Through   time.Time `bson:"through"`
+	CheckedAt time.Time `bson:"checked_at"`
+}
+
+// WalletDay is a day of the history of a wallet.
+type WalletDay struct {
+	WalletID string    `bson:"wallet_id"`
+	Day      time.Time `bson:"day"`
+}
+
+type InsightsRepository interface {
+	AggregateTransactions(walletID string, currency string, from time.Time, to time.Time, unit string, ctx context.Context) ([]entities.InsightRow, error)
+	AggregateRollups(walletID string, currency string, from time.Time, to time.Time, unit string, ctx context.Context) ([]entities.InsightRow, error)
+	GetRollupState(ctx context.Context) (RollupState, error)
+	SaveRollupState(state RollupState, ctx context.Context) error
+	FirstTransactionDay(ctx context.Context) (time.Time, error)
+	RollupDays(walletID string, from time.Time, to time.Time, ctx context.Context) error
+	RecategorizedDays(since time.Time, before time.Time, ctx context.Context) ([]WalletDay, error)
+}
+
+type MongoInsightsRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoInsightsRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoInsightsRepository {
+	return &MongoInsightsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (repo *MongoInsightsRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("transaction_rollups").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "currency", Value: 1}, {Key: "day", Value: 1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:insights_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// AggregateTransactions groups the income and expense of the wallet in
+// [from, to) by bucket of unit, category and counterparty, reading the
+// transactions themselves.
+func (repo *MongoInsightsRepository) AggregateTransactions(walletID string, currency string, from time.Time, to time.Time, unit string, ctx context.Context) ([]entities.InsightRow, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"wallet_id":  walletID,
+			"currency":   currency,
+			"status":     entities.StatusCompleted,
+			"type":       bson.M{"$in": append(append([]string{}, entities.IncomeTypes...), entities.ExpenseTypes...)},
+			"created_at": bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"bucket":       truncate("$created_at", unit),
+				"category":     bson.M{"$ifNull": bson.A{"$category", entities.CategoryOther}},
+				"counterparty": bson.M{"$ifNull": bson.A{"$counterparty_user_id", ""}},
+			},
+			"income":  bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$type", entities.IncomeTypes}}, "$amount", 0}}},
+			"expense": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$type", entities.ExpenseTypes}}, "$amount", 0}}},
+			"count":   bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: rowProjection}},
+	}
+	return repo.aggregate("transactions", pipeline, "AggregateTransactions", ctx)
+}
+
+// AggregateRollups does what AggregateTransactions does from the daily
+// rollups, which must cover [from, to).
+func (repo *MongoInsightsRepository) AggregateRollups(walletID string, currency string, from time.Time, to time.Time, unit string, ctx context.Context) ([]entities.InsightRow, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"wallet_id": walletID,
+			"currency":  currency,
+			"day":       bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"bucket":       truncate("$day", unit),
+				"category":     "$category",
+				"counterparty": "$counterparty_user_id",
+			},
+			"income":  bson.M{"$sum": "$income"},
+			"expense": bson.M{"$sum": "$expense"},
+			"count":   bson.M{"$sum": "$count"},
+		}}},
+		{{Key: "$project", Value: rowProjection}},
+	}
+	return repo.aggregate("transaction_rollups", pipeline, "AggregateRollups", ctx)
+}
+
+func (repo *MongoInsightsRepository) GetRollupState(ctx context.Context) (RollupState, error) {
+	var state RollupState
+	coll := repo.db.Database("mywallet").Collection("transaction_rollups_state")
+	err := coll.FindOne(ctx, bson.M{"_id": rollupState}).Decode(&state)
+	if err != nil && err != mongo.ErrNoDocuments {
+		repo.logger.Errorln("Layer:insights_repository ", "Method:GetRollupState ", "Error:", err)
+		return state, err
+	}
+	return state, nil
+}
+
+func (repo *MongoInsightsRepository) SaveRollupState(state RollupState, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("transaction_rollups_state")
+	_, err := coll.UpdateOne(ctx, bson.M{"_id": rollupState},
+		bson.M{"$set": bson.M{"through": state.Through, "checked_at": state.CheckedAt}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		repo.logger.Errorln("Layer:insights_repository ", "Method:SaveRollupState ", "Error:", err)
+	}
+	return err
+}
+
+// FirstTransactionDay returns the day of the oldest transaction, or the zero
+// time when there are none.
+func (repo *MongoInsightsRepository) FirstTransactionDay(ctx context.Context) (time.Time, error) {
+	var transaction entities.Transaction
+	coll := repo.db.Database("mywallet").Collection("transactions")
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	err := coll.FindOne(ctx, bson.M{}, opts).Decode(&transaction)
+	if err == mongo.ErrNoDocuments {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		repo.logger.Errorln("Layer:insights_repository ", "Method:FirstTransactionDay ", "Error:", err)
+		return time.Time{}, err
+	}
+	created := transaction.Created_at.UTC()
+	return time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// RollupDays computes again the rollups of the days in [from, to), of every
+// wallet or, when walletID is not empty, of that wallet. The old rollups of
+// those days are removed first, so groups that no longer exist go away.
+func (repo *MongoInsightsRepository) RollupDays(walletID string, from time.Time, to time.Time, ctx context.Context) error {
+	rollups := repo.db.Database("mywallet").Collection("transaction_rollups")
+	scope := bson.M{"day": bson.M{"$gte": from, "$lt": to}}
+	match := bson.M{
+		"status":     entities.StatusCompleted,
+		"type":       bson.M{"$in": append(append([]string{}, entities.IncomeTypes...), entities.ExpenseTypes...)},
+		"created_at": bson.M{"$gte": from, "$lt": to},
+	}
+	if walletID != "" {
+		scope["wallet_id"] = walletID
+		match["wallet_id"] = walletID
+	}
+	if _, err := rollups.DeleteMany(ctx, scope); err != nil {
+		repo.logger.Errorln("Layer:insights_repository ", "Method:RollupDays ", "Error:", err)
+		return err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"wallet_id":            "$wallet_id",
+				"currency":             "$currency",
+				"day":                  truncate("$created_at", entities.GranularityDay),
+				"category":             bson.M{"$ifNull": bson.A{"$category", entities.CategoryOther}},
+				"counterparty_user_id": bson.M{"$ifNull": bson.A{"$counterparty_user_id", ""}},
+			},
+			"income":  bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$type", entities.IncomeTypes}}, "$amount", 0}}},
+			"expense": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$type", entities.ExpenseTypes}}, "$amount", 0}}},
+			"count":   bson.M{"$sum": 1},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"wallet_id":            "$_id.wallet_id",
+			"currency":             "$_id.currency",
+			"day":                  "$_id.day",
+			"category":             "$_id.category",
+			"counterparty_user_id": "$_id.counterparty_user_id",
+		}}},
+		{{Key: "$merge", Value: bson.M{"into": "transaction_rollups", "on": "_id", "whenMatched": "replace", "whenNotMatched": "insert"}}},
+	}
+	cursor, err := repo.db.Database("mywallet").Collection("transactions").Aggregate(ctx, pipeline)
+	if err != nil {
+		repo.logger.Errorln("Layer:insights_repository ", "Method:RollupDays ", "Error:", err)
+		return err
+	}
+	return cursor.Close(ctx)
+}
+
+// RecategorizedDays returns the days before before with transactions whose
+// category changed since since.
+func (repo *MongoInsightsRepository) RecategorizedDays(since time.Time, before time.Time, ctx context.Context) ([]WalletDay, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"category_updated_at": bson.M{"$gte": since},
+			"created_at":          bson.M{"$lt": before},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{
+			"wallet_id": "$wallet_id",
+			"day":       truncate("$created_at", entities.GranularityDay),
+		}}}},
+		{{Key: "$replaceWith", Value: "$_id"}},
+	}
+	cursor, err := repo.db.Database("mywallet").Collection("transactions").Aggregate(ctx, pipeline)
+	if err != nil {
+		repo.logger.Errorln("Layer:insights_repository ", "Method:RecategorizedDays ", "Error:", err)
+		return nil, err
+	}
+	days := []WalletDay{}
+	if err := cursor.All(ctx, &days); err != nil {
+		repo.logger.Errorln("Layer:insights_repository ", "Method:RecategorizedDays ", "Error:", err)
+		return nil, err
+	}
+	return days, nil
+}
+
+func (repo *MongoInsightsRepository) aggregate(collection string, pipeline mongo.Pipeline, method string, ctx context.Context) ([]entities.InsightRow, error) {
+	cursor, err := repo.db.Database("mywallet").Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		repo.logger.Errorln("Layer:insights_repository ", "Method:"+method+" ", "Error:", err)
+		return nil, err
+	}
+	rows := []entities.InsightRow{}
+	if err := cursor.All(ctx, &rows); err != nil {
+		repo.logger.Errorln("Layer:insights_repository ", "Method:"+method+" ", "Error:", err)
+		return nil, err
+	}
+	return rows, nil
+}
+
+var rowProjection = bson.M{
+	"_id":                  0,
+	"bucket":               "$_id.bucket",
+	"category":             "$_id.category",
+	"counterparty_user_id": "$_id.counterparty",
+	"income":               1,
+	"expense":              1,
+	"count":                1,
+}
+
+// truncate rounds a date down to the start of its day, ISO week or month in
+// UTC.
+func truncate(field string, unit string) bson.M {
+	return bson.M{"$dateTrunc": bson.M{"date": field, "unit": unit, "startOfWeek": "monday", "timezone": "UTC"}}
+}
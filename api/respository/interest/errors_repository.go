@@ -0,0 +1,8 @@
+package repository_interest
+
+import "errors"
+
+var ErrAccrualExists = errors.New("Interest already accrued for the day")
+var ErrPayoutExists = errors.New("Interest already paid for the month")
+var ErrPayoutNotFound = errors.New("Error not found interest payout")
+var ErrInvalidRate = errors.New("Interest rates need a positive annual rate and a known day count convention")
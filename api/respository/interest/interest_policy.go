@@ -0,0 +1,52 @@
+package repository_interest
+
+import (
+	"encoding/json"
+	"my_wallet/api/entities"
+	"my_wallet/api/utils/daycount"
+	"os"
+)
+
+// RatePolicy gives the interest rate of a kind of account in a currency.
+type RatePolicy interface {
+	Rate(accountKind string, currency string) (entities.InterestRate, bool)
+}
+
+// StaticRatePolicy is a rate policy fixed when the service starts, keyed by
+// account kind and then currency. Accounts without a rate earn nothing.
+type StaticRatePolicy struct {
+	rates map[string]map[string]entities.InterestRate
+}
+
+// NewStaticRatePolicy checks every rate: positive, of at most 100%, and with
+// a known day count convention.
+func NewStaticRatePolicy(rates map[string]map[string]entities.InterestRate) (*StaticRatePolicy, error) {
+	for _, currencies := range rates {
+		for _, rate := range currencies {
+			if rate.AnnualRateBps <= 0 || rate.AnnualRateBps > 10_000 || !daycount.Valid(rate.DayCount) {
+				return nil, ErrInvalidRate
+			}
+		}
+	}
+	return &StaticRatePolicy{rates: rates}, nil
+}
+
+// NewFileRatePolicy loads a static policy from a JSON file with the same
+// shape accepted by NewStaticRatePolicy.
+func NewFileRatePolicy(path string) (*StaticRatePolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rates map[string]map[string]entities.InterestRate
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, err
+	}
+	return NewStaticRatePolicy(rates)
+}
+
+// Rate returns the rate of the account kind in the currency, if there is one.
+func (p *StaticRatePolicy) Rate(accountKind string, currency string) (entities.InterestRate, bool) {
+	rate, ok := p.rates[accountKind][currency]
+	return rate, ok
+}
@@ -0,0 +1,311 @@
+package repository_interest
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// interestState is the ID of the single document of interest_state.
+const interestState = "interest"
+
+// InterestState is how far the interest job got: the last day accrued and
+// the last month paid.
+type InterestState struct {
+	LastAccruedDay time.Time `bson:"last_accrued_day"`
+	LastPaidMonth  time.Time `bson:"last_paid_month"`
+}
+
+type InterestRepository interface {
+	EndOfDayBalances(at time.Time, ctx context.Context) ([]entities.AccountBalance, error)
+	CreateAccrual(accrual entities.InterestAccrual, ctx context.Context) (entities.InterestAccrual, error)
+	UnpaidBalances(accountIDs []string, before time.Time, ctx context.Context) ([]entities.InterestBalance, error)
+	CreatePayout(payout entities.InterestPayout, ctx context.Context) (entities.InterestPayout, error)
+	UpdatePayout(payout entities.InterestPayout, ctx context.Context) error
+	LastPaidPayout(accountID string, currency string, ctx context.Context) (entities.InterestPayout, error)
+	ListPayouts(accountIDs []string, limit int64, ctx context.Context) ([]entities.InterestPayout, error)
+	ClaimAccruals(accountID string, currency string, before time.Time, payoutID string, ctx context.Context) (int64, error)
+	ReleaseAccruals(payoutID string, ctx context.Context) error
+	GetState(ctx context.Context) (InterestState, error)
+	SaveState(state InterestState, ctx context.Context) error
+}
+
+type MongoInterestRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoInterestRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoInterestRepository {
+	return &MongoInterestRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes makes accruals unique per account, currency and day and
+// payouts unique per account, currency and month, which is what makes
+// running a day or a month again harmless.
+func (repo *MongoInterestRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("interest_accruals").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "account_id", Value: 1}, {Key: "currency", Value: 1}, {Key: "day", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "payout_id", Value: 1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:CreateIndexes ", "Error:", err)
+		return err
+	}
+	_, err = repo.db.Database("mywallet").Collection("interest_payouts").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "account_id", Value: 1}, {Key: "currency", Value: 1}, {Key: "month", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// EndOfDayBalances returns the balance of every wallet, per currency, and of
+// every pocket from the ledger entries posted before at. Accounts that never
+// moved money are left out.
+func (repo *MongoInterestRepository) EndOfDayBalances(at time.Time, ctx context.Context) ([]entities.AccountBalance, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$lt": at}}}},
+		{{Key: "$unwind", Value: "$lines"}},
+		{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"lines.wallet_id": bson.M{"$exists": true}},
+			bson.M{"lines.pocket_id": bson.M{"$exists": true}},
+		}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     bson.M{"wallet_id": "$lines.wallet_id", "pocket_id": "$lines.pocket_id", "currency": "$lines.currency"},
+			"balance": bson.M{"$sum": "$lines.amount"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":       0,
+			"wallet_id": "$_id.wallet_id",
+			"pocket_id": "$_id.pocket_id",
+			"currency":  "$_id.currency",
+			"balance":   1,
+		}}},
+	}
+	coll := repo.db.Database("mywallet").Collection("ledger_entries")
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:EndOfDayBalances ", "Error:", err)
+		return nil, err
+	}
+	balances := []entities.AccountBalance{}
+	if err := cursor.All(ctx, &balances); err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:EndOfDayBalances ", "Error:", err)
+		return nil, err
+	}
+	return balances, nil
+}
+
+// CreateAccrual stores the accrual of a day. It fails with ErrAccrualExists
+// when the account already accrued the day.
+func (repo *MongoInterestRepository) CreateAccrual(accrual entities.InterestAccrual, ctx context.Context) (entities.InterestAccrual, error) {
+	coll := repo.db.Database("mywallet").Collection("interest_accruals")
+	result, err := coll.InsertOne(ctx, accrual)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.InterestAccrual{}, ErrAccrualExists
+		}
+		repo.logger.Errorln("Layer:interest_repository ", "Method:CreateAccrual ", "Error:", err)
+		return entities.InterestAccrual{}, err
+	}
+	accrual.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return accrual, nil
+}
+
+// UnpaidBalances adds up the accruals not paid yet of days before before, by
+// account and currency; of the given accounts, or of every account when
+// accountIDs is nil.
+func (repo *MongoInterestRepository) UnpaidBalances(accountIDs []string, before time.Time, ctx context.Context) ([]entities.InterestBalance, error) {
+	match := bson.M{"payout_id": bson.M{"$exists": false}, "day": bson.M{"$lt": before}}
+	if accountIDs != nil {
+		match["account_id"] = bson.M{"$in": accountIDs}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     bson.M{"account_kind": "$account_kind", "account_id": "$account_id", "currency": "$currency"},
+			"accrued": bson.M{"$sum": "$amount"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":          0,
+			"account_kind": "$_id.account_kind",
+			"account_id":   "$_id.account_id",
+			"currency":     "$_id.currency",
+			"accrued":      1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "account_id", Value: 1}, {Key: "currency", Value: 1}}}},
+	}
+	coll := repo.db.Database("mywallet").Collection("interest_accruals")
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:UnpaidBalances ", "Error:", err)
+		return nil, err
+	}
+	balances := []entities.InterestBalance{}
+	if err := cursor.All(ctx, &balances); err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:UnpaidBalances ", "Error:", err)
+		return nil, err
+	}
+	return balances, nil
+}
+
+// CreatePayout claims the payout of a month for an account. It fails with
+// ErrPayoutExists when the month was already claimed.
+func (repo *MongoInterestRepository) CreatePayout(payout entities.InterestPayout, ctx context.Context) (entities.InterestPayout, error) {
+	coll := repo.db.Database("mywallet").Collection("interest_payouts")
+	result, err := coll.InsertOne(ctx, payout)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.InterestPayout{}, ErrPayoutExists
+		}
+		repo.logger.Errorln("Layer:interest_repository ", "Method:CreatePayout ", "Error:", err)
+		return entities.InterestPayout{}, err
+	}
+	payout.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return payout, nil
+}
+
+// UpdatePayout records the outcome of a payout: its amounts, the entry that
+// paid it and its status.
+func (repo *MongoInterestRepository) UpdatePayout(payout entities.InterestPayout, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(payout.ID)
+	if err != nil {
+		return ErrPayoutNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("interest_payouts")
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": idd}, bson.M{"$set": bson.M{
+		"wallet_id": payout.WalletID,
+		"accrued":   payout.Accrued,
+		"amount":    payout.Amount,
+		"remainder": payout.Remainder,
+		"entry_id":  payout.EntryID,
+		"status":    payout.Status,
+	}})
+	if err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:UpdatePayout ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrPayoutNotFound
+	}
+	return nil
+}
+
+// LastPaidPayout returns the latest payout of the account in the currency
+// that was paid, or ErrPayoutNotFound.
+func (repo *MongoInterestRepository) LastPaidPayout(accountID string, currency string, ctx context.Context) (entities.InterestPayout, error) {
+	var payout entities.InterestPayout
+	coll := repo.db.Database("mywallet").Collection("interest_payouts")
+	opts := options.FindOne().SetSort(bson.D{{Key: "month", Value: -1}})
+	err := coll.FindOne(ctx, bson.M{"account_id": accountID, "currency": currency, "status": entities.InterestPayoutPaid}, opts).Decode(&payout)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return payout, ErrPayoutNotFound
+		}
+		repo.logger.Errorln("Layer:interest_repository ", "Method:LastPaidPayout ", "Error:", err)
+		return payout, err
+	}
+	return payout, nil
+}
+
+// ListPayouts returns the latest payouts of the accounts, newest first.
+func (repo *MongoInterestRepository) ListPayouts(accountIDs []string, limit int64, ctx context.Context) ([]entities.InterestPayout, error) {
+	coll := repo.db.Database("mywallet").Collection("interest_payouts")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := coll.Find(ctx, bson.M{"account_id": bson.M{"$in": accountIDs}}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:ListPayouts ", "Error:", err)
+		return nil, err
+	}
+	payouts := []entities.InterestPayout{}
+	if err := cursor.All(ctx, &payouts); err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:ListPayouts ", "Error:", err)
+		return nil, err
+	}
+	return payouts, nil
+}
+
+// ClaimAccruals marks the unpaid accruals of the account in the currency of
+// days before before as paid by the payout and returns their total.
+func (repo *MongoInterestRepository) ClaimAccruals(accountID string, currency string, before time.Time, payoutID string, ctx context.Context) (int64, error) {
+	coll := repo.db.Database("mywallet").Collection("interest_accruals")
+	_, err := coll.UpdateMany(ctx, bson.M{
+		"account_id": accountID,
+		"currency":   currency,
+		"day":        bson.M{"$lt": before},
+		"payout_id":  bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{"payout_id": payoutID}})
+	if err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:ClaimAccruals ", "Error:", err)
+		return 0, err
+	}
+	cursor, err := coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"payout_id": payoutID}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$amount"}}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:ClaimAccruals ", "Error:", err)
+		return 0, err
+	}
+	var result []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:ClaimAccruals ", "Error:", err)
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Total, nil
+}
+
+// ReleaseAccruals gives the accruals of a payout that failed back, so that
+// the next payout pays them.
+func (repo *MongoInterestRepository) ReleaseAccruals(payoutID string, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("interest_accruals")
+	_, err := coll.UpdateMany(ctx, bson.M{"payout_id": payoutID}, bson.M{"$unset": bson.M{"payout_id": ""}})
+	if err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:ReleaseAccruals ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoInterestRepository) GetState(ctx context.Context) (InterestState, error) {
+	var state InterestState
+	coll := repo.db.Database("mywallet").Collection("interest_state")
+	err := coll.FindOne(ctx, bson.M{"_id": interestState}).Decode(&state)
+	if err != nil && err != mongo.ErrNoDocuments {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:GetState ", "Error:", err)
+		return state, err
+	}
+	return state, nil
+}
+
+func (repo *MongoInterestRepository) SaveState(state InterestState, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("interest_state")
+	_, err := coll.UpdateOne(ctx, bson.M{"_id": interestState},
+		bson.M{"$set": bson.M{"last_accrued_day": state.LastAccruedDay, "last_paid_month": state.LastPaidMonth}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		repo.logger.Errorln("Layer:interest_repository ", "Method:SaveState ", "Error:", err)
+	}
+	return err
+}
@@ -0,0 +1,7 @@
+package repository_kyc
+
+import "errors"
+
+var ErrKYCApplicationNotFound = errors.New("Error not found KYC application")
+var ErrKYCApplicationOpen = errors.New("User already has an open KYC application")
+var ErrKYCStatusChanged = errors.New("KYC application status changed")
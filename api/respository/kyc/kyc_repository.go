@@ -0,0 +1,213 @@
+package repository_kyc
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type KYCRepository interface {
+	CreateApplication(application entities.KYCApplication, ctx context.Context) (entities.KYCApplication, error)
+	GetApplication(id string, ctx context.Context) (entities.KYCApplication, error)
+	GetOpenApplication(userID string, ctx context.Context) (entities.KYCApplication, error)
+	ListApplications(status string, limit int64, ctx context.Context) ([]entities.KYCApplication, error)
+	PutDocument(id string, document entities.KYCDocument, ctx context.Context) (entities.KYCApplication, error)
+	UpdateStatus(id string, from string, to string, reviewer string, reason string, ctx context.Context) (entities.KYCApplication, error)
+	CreateEvent(event entities.KYCEvent, ctx context.Context) (entities.KYCEvent, error)
+	ListEvents(userID string, limit int64, ctx context.Context) ([]entities.KYCEvent, error)
+}
+
+type MongoKYCRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoKYCRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoKYCRepository {
+	return &MongoKYCRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes allows a single open application per user and keeps the
+// review queue and the audit trail in order.
+func (repo *MongoKYCRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("kyc_applications").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"open": true}),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: 1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:kyc_repository ", "Method:CreateIndexes ", "Error:", err)
+		return err
+	}
+	_, err = repo.db.Database("mywallet").Collection("kyc_events").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:kyc_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// CreateApplication stores a new open application, unless the user already
+// has one.
+func (repo *MongoKYCRepository) CreateApplication(application entities.KYCApplication, ctx context.Context) (entities.KYCApplication, error) {
+	application.Open = true
+	coll := repo.db.Database("mywallet").Collection("kyc_applications")
+	result, err := coll.InsertOne(ctx, application)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.KYCApplication{}, ErrKYCApplicationOpen
+		}
+		repo.logger.Errorln("Layer:kyc_repository ", "Method:CreateApplication ", "Error:", err)
+		return entities.KYCApplication{}, err
+	}
+	application.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return application, nil
+}
+
+func (repo *MongoKYCRepository) GetApplication(id string, ctx context.Context) (entities.KYCApplication, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.KYCApplication{}, ErrKYCApplicationNotFound
+	}
+	return repo.findOne(bson.M{"_id": idd}, "GetApplication", ctx)
+}
+
+// GetOpenApplication returns the draft or submitted application of the user.
+func (repo *MongoKYCRepository) GetOpenApplication(userID string, ctx context.Context) (entities.KYCApplication, error) {
+	return repo.findOne(bson.M{"user_id": userID, "open": true}, "GetOpenApplication", ctx)
+}
+
+// ListApplications returns up to limit applications in a status, the ones
+// waiting the longest first.
+func (repo *MongoKYCRepository) ListApplications(status string, limit int64, ctx context.Context) ([]entities.KYCApplication, error) {
+	applications := []entities.KYCApplication{}
+	coll := repo.db.Database("mywallet").Collection("kyc_applications")
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}}).SetLimit(limit)
+	cursor, err := coll.Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:kyc_repository ", "Method:ListApplications ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &applications); err != nil {
+		repo.logger.Errorln("Layer:kyc_repository ", "Method:ListApplications ", "Error:", err)
+		return nil, err
+	}
+	return applications, nil
+}
+
+// PutDocument adds a document to a draft application, replacing the one of
+// the same type. Applications no longer in draft are not changed and
+// ErrKYCStatusChanged is returned.
+func (repo *MongoKYCRepository) PutDocument(id string, document entities.KYCDocument, ctx context.Context) (entities.KYCApplication, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.KYCApplication{}, ErrKYCApplicationNotFound
+	}
+	application, err := repo.findOneAndUpdate(bson.M{"_id": idd, "status": entities.KYCDraft},
+		bson.M{"$set": bson.M{"documents." + document.Type: document, "updated_at": time.Now().UTC()}},
+		"PutDocument", ctx)
+	if err == ErrKYCApplicationNotFound {
+		if _, err := repo.GetApplication(id, ctx); err != nil {
+			return entities.KYCApplication{}, err
+		}
+		return entities.KYCApplication{}, ErrKYCStatusChanged
+	}
+	return application, err
+}
+
+// UpdateStatus moves an application from one status to another. When two
+// callers race, only the first one moves it; the other gets
+// ErrKYCStatusChanged. Approved and rejected applications are closed, so the
+// user can apply again.
+func (repo *MongoKYCRepository) UpdateStatus(id string, from string, to string, reviewer string, reason string, ctx context.Context) (entities.KYCApplication, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.KYCApplication{}, ErrKYCApplicationNotFound
+	}
+	now := time.Now().UTC()
+	set := bson.M{"status": to, "updated_at": now}
+	update := bson.M{"$set": set}
+	if to == entities.KYCApproved || to == entities.KYCRejected {
+		set["reviewed_by"] = reviewer
+		set["reviewed_at"] = now
+		set["reason"] = reason
+		update["$unset"] = bson.M{"open": ""}
+	}
+	application, err := repo.findOneAndUpdate(bson.M{"_id": idd, "status": from}, update, "UpdateStatus", ctx)
+	if err == ErrKYCApplicationNotFound {
+		if _, err := repo.GetApplication(id, ctx); err != nil {
+			return entities.KYCApplication{}, err
+		}
+		return entities.KYCApplication{}, ErrKYCStatusChanged
+	}
+	return application, err
+}
+
+func (repo *MongoKYCRepository) CreateEvent(event entities.KYCEvent, ctx context.Context) (entities.KYCEvent, error) {
+	coll := repo.db.Database("mywallet").Collection("kyc_events")
+	result, err := coll.InsertOne(ctx, event)
+	if err != nil {
+		repo.logger.Errorln("Layer:kyc_repository ", "Method:CreateEvent ", "Error:", err)
+		return entities.KYCEvent{}, err
+	}
+	event.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return event, nil
+}
+
+// ListEvents returns the latest limit entries of the audit trail of the user,
+// newest first.
+func (repo *MongoKYCRepository) ListEvents(userID string, limit int64, ctx context.Context) ([]entities.KYCEvent, error) {
+	events := []entities.KYCEvent{}
+	coll := repo.db.Database("mywallet").Collection("kyc_events")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:kyc_repository ", "Method:ListEvents ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &events); err != nil {
+		repo.logger.Errorln("Layer:kyc_repository ", "Method:ListEvents ", "Error:", err)
+		return nil, err
+	}
+	return events, nil
+}
+
+func (repo *MongoKYCRepository) findOne(query bson.M, method string, ctx context.Context) (entities.KYCApplication, error) {
+	var application entities.KYCApplication
+	coll := repo.db.Database("mywallet").Collection("kyc_applications")
+	err := coll.FindOne(ctx, query).Decode(&application)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return application, ErrKYCApplicationNotFound
+		}
+		repo.logger.Errorln("Layer:kyc_repository ", "Method:"+method+" ", "Error:", err)
+		return application, err
+	}
+	return application, nil
+}
+
+func (repo *MongoKYCRepository) findOneAndUpdate(query bson.M, update bson.M, method string, ctx context.Context) (entities.KYCApplication, error) {
+	var application entities.KYCApplication
+	coll := repo.db.Database("mywallet").Collection("kyc_applications")
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := coll.FindOneAndUpdate(ctx, query, update, opts).Decode(&application)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return application, ErrKYCApplicationNotFound
+		}
+		repo.logger.Errorln("Layer:kyc_repository ", "Method:"+method+" ", "Error:", err)
+		return application, err
+	}
+	return application, nil
+}
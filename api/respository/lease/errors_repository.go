@@ -0,0 +1,5 @@
+package repository_lease
+
+import "errors"
+
+var ErrLeaseHeld = errors.New("Lease is held by another replica")
@@ -0,0 +1,66 @@
+package repository_lease
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LeaseRepository interface {
+	AcquireLease(name string, holder string, now time.Time, ttl time.Duration, ctx context.Context) error
+	ReleaseLease(name string, holder string, ctx context.Context) error
+}
+
+type MongoLeaseRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoLeaseRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoLeaseRepository {
+	return &MongoLeaseRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// AcquireLease takes or renews the lease called name for holder. The lease
+// document is upserted only when it is free, expired or already ours; when
+// another replica holds it the upsert collides with its _id and
+// ErrLeaseHeld is returned.
+func (repo *MongoLeaseRepository) AcquireLease(name string, holder string, now time.Time, ttl time.Duration, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("leases")
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"holder": holder},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"holder": holder, "expires_at": now.Add(ttl)}}
+	var lease entities.Lease
+	err := coll.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetUpsert(true)).Decode(&lease)
+	if err != nil && err != mongo.ErrNoDocuments {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrLeaseHeld
+		}
+		repo.logger.Errorln("Layer:lease_repository ", "Method:AcquireLease ", "Error:", err)
+		return err
+	}
+	return nil
+}
+
+// ReleaseLease gives the lease up so another replica can take it without
+// waiting for it to expire.
+func (repo *MongoLeaseRepository) ReleaseLease(name string, holder string, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("leases")
+	_, err := coll.UpdateOne(ctx, bson.M{"_id": name, "holder": holder}, bson.M{"$set": bson.M{"expires_at": time.Time{}}})
+	if err != nil {
+		repo.logger.Errorln("Layer:lease_repository ", "Method:ReleaseLease ", "Error:", err)
+	}
+	return err
+}
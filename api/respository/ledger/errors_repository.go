@@ -0,0 +1,12 @@
+package repository_ledger
+
+import "errors"
+
+var ErrInsufficientFunds = errors.New("Insufficient funds")
+var ErrUnbalancedEntry = errors.New("Ledger entry is not balanced")
+var ErrEntryExists = errors.New("Ledger entry with this reference was already posted")
+var ErrWalletNotFound = errors.New("Error not found wallet")
+var ErrInvalidCurrency = errors.New("Invalid currency")
+var ErrInvalidCursor = errors.New("Invalid pagination cursor")
+var ErrPocketNotFound = errors.New("Error not found pocket")
+var ErrTransactionNotFound = errors.New("Error not found transaction")
@@ -0,0 +1,234 @@
+package repository_ledger
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LedgerRepository interface {
+	PostEntry(entry entities.LedgerEntry, transactions []entities.Transaction, ctx context.Context) (entities.LedgerEntry, []entities.Transaction, error)
+	WalletBalanceAt(walletID string, currency string, at time.Time, ctx context.Context) (int64, error)
+}
+
+type MongoLedgerRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoLedgerRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoLedgerRepository {
+	return &MongoLedgerRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes indexes ledger lines by wallet and date so balances at a
+// point in time can be computed from the ledger, and makes references unique.
+func (repo *MongoLedgerRepository) CreateIndexes(ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("ledger_entries")
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "lines.wallet_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "reference", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:ledger_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// PostEntry applies every wallet and pocket line of the entry to their
+// balances, stores the entry and the per wallet transactions. Everything runs inside a
+// single Mongo transaction, so the database must be a replica set. An entry
+// whose reference was already posted fails with ErrEntryExists and changes
+// nothing.
+func (repo *MongoLedgerRepository) PostEntry(entry entities.LedgerEntry, transactions []entities.Transaction, ctx context.Context) (entities.LedgerEntry, []entities.Transaction, error) {
+	if !IsBalanced(entry) {
+		repo.logger.Errorln("Layer:ledger_repository ", "Method:PostEntry ", "Error:", ErrUnbalancedEntry)
+		return entities.LedgerEntry{}, nil, ErrUnbalancedEntry
+	}
+	if entry.Created_at.IsZero() {
+		entry.Created_at = time.Now().UTC()
+	}
+
+	session, err := repo.db.StartSession()
+	if err != nil {
+		repo.logger.Errorln("Layer:ledger_repository ", "Method:PostEntry ", "Error:", err)
+		return entities.LedgerEntry{}, nil, err
+	}
+	defer session.EndSession(ctx)
+
+	database := repo.db.Database("mywallet")
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		wallets := database.Collection("wallets")
+		pockets := database.Collection("pockets")
+		for _, line := range entry.Lines {
+			switch {
+			case line.WalletID != "":
+				if err := applyWalletLine(sc, wallets, line, entry.Created_at); err != nil {
+					return nil, err
+				}
+			case line.PocketID != "":
+				if err := applyPocketLine(sc, pockets, line, entry.Created_at); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		entry.ID = ""
+		result, err := database.Collection("ledger_entries").InsertOne(sc, entry)
+		if err != nil {
+			return nil, err
+		}
+		entry.ID = result.InsertedID.(primitive.ObjectID).Hex()
+
+		if len(transactions) == 0 {
+			return nil, nil
+		}
+		docs := make([]interface{}, len(transactions))
+		for i := range transactions {
+			transactions[i].ID = ""
+			transactions[i].EntryID = entry.ID
+			transactions[i].Created_at = entry.Created_at
+			docs[i] = transactions[i]
+		}
+		inserted, err := database.Collection("transactions").InsertMany(sc, docs)
+		if err != nil {
+			return nil, err
+		}
+		for i, id := range inserted.InsertedIDs {
+			transactions[i].ID = id.(primitive.ObjectID).Hex()
+		}
+		return nil, nil
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return entities.LedgerEntry{}, nil, ErrEntryExists
+	}
+	if err != nil {
+		repo.logger.Errorln("Layer:ledger_repository ", "Method:PostEntry ", "Error:", err)
+		return entities.LedgerEntry{}, nil, err
+	}
+	repo.logger.Infoln("Layer:ledger_repository ", "Method:PostEntry ", "Entry:", entry.ID)
+	return entry, transactions, nil
+}
+
+// WalletBalanceAt adds up the wallet lines in currency of every entry posted
+// before at.
+func (repo *MongoLedgerRepository) WalletBalanceAt(walletID string, currency string, at time.Time, ctx context.Context) (int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"lines.wallet_id": walletID, "created_at": bson.M{"$lt": at}}}},
+		{{Key: "$unwind", Value: "$lines"}},
+		{{Key: "$match", Value: bson.M{"lines.wallet_id": walletID, "lines.currency": currency}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$lines.amount"}}}},
+	}
+	coll := repo.db.Database("mywallet").Collection("ledger_entries")
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		repo.logger.Errorln("Layer:ledger_repository ", "Method:WalletBalanceAt ", "Error:", err)
+		return 0, err
+	}
+	var result []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		repo.logger.Errorln("Layer:ledger_repository ", "Method:WalletBalanceAt ", "Error:", err)
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Total, nil
+}
+
+// applyWalletLine moves the line amount in or out of the wallet balance of
+// the line currency. A debit only matches the wallet while that balance can
+// cover it.
+func applyWalletLine(ctx context.Context, wallets *mongo.Collection, line entities.LedgerLine, now time.Time) error {
+	idd, err := primitive.ObjectIDFromHex(line.WalletID)
+	if err != nil {
+		return ErrWalletNotFound
+	}
+	if !entities.ValidCurrency(line.Currency) {
+		return ErrInvalidCurrency
+	}
+	balance := "balances." + line.Currency
+	filter := bson.M{"_id": idd}
+	if line.Amount < 0 {
+		filter[balance] = bson.M{"$gte": -line.Amount}
+	}
+	update := bson.M{
+		"$inc": bson.M{balance: line.Amount},
+		"$set": bson.M{"updated_at": now},
+	}
+	result, err := wallets.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		count, err := wallets.CountDocuments(ctx, bson.M{"_id": idd})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrWalletNotFound
+		}
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+// applyPocketLine moves the line amount in or out of an active pocket. Like
+// a wallet, a pocket cannot be debited below zero.
+func applyPocketLine(ctx context.Context, pockets *mongo.Collection, line entities.LedgerLine, now time.Time) error {
+	idd, err := primitive.ObjectIDFromHex(line.PocketID)
+	if err != nil {
+		return ErrPocketNotFound
+	}
+	filter := bson.M{"_id": idd, "currency": line.Currency, "status": entities.PocketActive}
+	if line.Amount < 0 {
+		filter["balance"] = bson.M{"$gte": -line.Amount}
+	}
+	update := bson.M{
+		"$inc": bson.M{"balance": line.Amount},
+		"$set": bson.M{"updated_at": now},
+	}
+	result, err := pockets.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		count, err := pockets.CountDocuments(ctx, bson.M{"_id": idd, "currency": line.Currency, "status": entities.PocketActive})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrPocketNotFound
+		}
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+// IsBalanced reports whether the lines of the entry add up to zero in every
+// currency.
+func IsBalanced(entry entities.LedgerEntry) bool {
+	if len(entry.Lines) == 0 {
+		return false
+	}
+	totals := map[string]int64{}
+	for _, line := range entry.Lines {
+		totals[line.Currency] += line.Amount
+	}
+	for _, total := range totals {
+		if total != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,240 @@
+package repository_ledger
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TransactionRepository interface {
+	ListTransactions(filter entities.TransactionFilter, ctx context.Context) ([]entities.Transaction, error)
+	SumTransactions(walletID string, currency string, from time.Time, to time.Time, ctx context.Context) (map[string]int64, error)
+	StreamTransactions(walletID string, currency string, from time.Time, to time.Time, ctx context.Context, fn func(entities.Transaction) error) error
+	GetTransaction(id string, ctx context.Context) (entities.Transaction, error)
+	SetCategory(id string, category string, ctx context.Context) error
+}
+
+type MongoTransactionRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoTransactionRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoTransactionRepository {
+	return &MongoTransactionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes creates the indexes used by the history: the cursor order per
+// wallet, the most common filters on top of it and the text index of memos.
+func (repo *MongoTransactionRepository) CreateIndexes(ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("transactions")
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "type", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "counterparty_user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "category", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "entry_id", Value: 1}}},
+		{Keys: bson.D{{Key: "category_updated_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "memo", Value: "text"}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:transaction_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// ListTransactions returns up to filter.Limit transactions of the wallet that
+// come after filter.After, newest first.
+func (repo *MongoTransactionRepository) ListTransactions(filter entities.TransactionFilter, ctx context.Context) ([]entities.Transaction, error) {
+	query, err := transactionQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(filter.Limit))
+
+	coll := repo.db.Database("mywallet").Collection("transactions")
+	cursor, err := coll.Find(ctx, query, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:transaction_repository ", "Method:ListTransactions ", "Error:", err)
+		return nil, err
+	}
+	transactions := []entities.Transaction{}
+	if err := cursor.All(ctx, &transactions); err != nil {
+		repo.logger.Errorln("Layer:transaction_repository ", "Method:ListTransactions ", "Error:", err)
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// SumTransactions returns the total amount of the completed transactions of
+// the wallet in currency in [from, to), grouped by transaction type.
+func (repo *MongoTransactionRepository) SumTransactions(walletID string, currency string, from time.Time, to time.Time, ctx context.Context) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: periodQuery(walletID, currency, from, to)}},
+		{{Key: "$group", Value: bson.M{"_id": "$type", "total": bson.M{"$sum": "$amount"}}}},
+	}
+	coll := repo.db.Database("mywallet").Collection("transactions")
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		repo.logger.Errorln("Layer:transaction_repository ", "Method:SumTransactions ", "Error:", err)
+		return nil, err
+	}
+	var result []struct {
+		Type  string `bson:"_id"`
+		Total int64  `bson:"total"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		repo.logger.Errorln("Layer:transaction_repository ", "Method:SumTransactions ", "Error:", err)
+		return nil, err
+	}
+	totals := map[string]int64{}
+	for _, row := range result {
+		totals[row.Type] = row.Total
+	}
+	return totals, nil
+}
+
+// StreamTransactions calls fn for every completed transaction of the wallet in
+// currency in [from, to), oldest first, decoding one document at a time.
+func (repo *MongoTransactionRepository) StreamTransactions(walletID string, currency string, from time.Time, to time.Time, ctx context.Context, fn func(entities.Transaction) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	coll := repo.db.Database("mywallet").Collection("transactions")
+	cursor, err := coll.Find(ctx, periodQuery(walletID, currency, from, to), opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:transaction_repository ", "Method:StreamTransactions ", "Error:", err)
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var transaction entities.Transaction
+		if err := cursor.Decode(&transaction); err != nil {
+			return err
+		}
+		if err := fn(transaction); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (repo *MongoTransactionRepository) GetTransaction(id string, ctx context.Context) (entities.Transaction, error) {
+	var transaction entities.Transaction
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return transaction, ErrTransactionNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("transactions")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&transaction)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return transaction, ErrTransactionNotFound
+		}
+		repo.logger.Errorln("Layer:transaction_repository ", "Method:GetTransaction ", "Error:", err)
+		return transaction, err
+	}
+	return transaction, nil
+}
+
+// SetCategory changes the category of a transaction. It is the only field of
+// a posted transaction that can change; the time of the change tells the
+// insights rollups which days to compute again.
+func (repo *MongoTransactionRepository) SetCategory(id string, category string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrTransactionNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("transactions")
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": idd}, bson.M{"$set": bson.M{"category": category, "category_updated_at": time.Now().UTC()}})
+	if err != nil {
+		repo.logger.Errorln("Layer:transaction_repository ", "Method:SetCategory ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrTransactionNotFound
+	}
+	return nil
+}
+
+func periodQuery(walletID string, currency string, from time.Time, to time.Time) bson.M {
+	return bson.M{
+		"wallet_id":  walletID,
+		"currency":   currency,
+		"status":     entities.StatusCompleted,
+		"created_at": bson.M{"$gte": from, "$lt": to},
+	}
+}
+
+func transactionQuery(filter entities.TransactionFilter) (bson.M, error) {
+	query := bson.M{"wallet_id": filter.WalletID}
+	if len(filter.Types) > 0 {
+		query["type"] = bson.M{"$in": filter.Types}
+	}
+	if filter.Currency != "" {
+		query["currency"] = filter.Currency
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Category != "" {
+		query["category"] = filter.Category
+	}
+	if filter.Member != "" {
+		query["initiated_by"] = filter.Member
+	}
+	amount := bson.M{}
+	if filter.MinAmount > 0 {
+		amount["$gte"] = filter.MinAmount
+	}
+	if filter.MaxAmount > 0 {
+		amount["$lte"] = filter.MaxAmount
+	}
+	if len(amount) > 0 {
+		query["amount"] = amount
+	}
+	createdAt := bson.M{}
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		createdAt["$lt"] = filter.To
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+	if filter.Search != "" {
+		query["$text"] = bson.M{"$search": filter.Search}
+	}
+
+	and := bson.A{}
+	if filter.Counterparty != "" {
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"counterparty_user_id": filter.Counterparty},
+			bson.M{"counterparty_wallet_id": filter.Counterparty},
+		}})
+	}
+	if filter.After != nil {
+		idd, err := primitive.ObjectIDFromHex(filter.After.ID)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{"$lt": filter.After.Created_at}},
+			bson.M{"created_at": filter.After.Created_at, "_id": bson.M{"$lt": idd}},
+		}})
+	}
+	if len(and) > 0 {
+		query["$and"] = and
+	}
+	return query, nil
+}
@@ -0,0 +1,5 @@
+package repository_limit
+
+import "errors"
+
+var ErrLimitReached = errors.New("Limit reached")
@@ -0,0 +1,42 @@
+package repository_limit
+
+import (
+	"encoding/json"
+	"my_wallet/api/entities"
+	"os"
+)
+
+// LimitPolicy gives the limits of a KYC level in a currency.
+type LimitPolicy interface {
+	Limits(level string, currency string) (entities.Limits, bool)
+}
+
+// StaticLimitPolicy is a limit policy fixed when the service starts, keyed by
+// KYC level and then currency.
+type StaticLimitPolicy struct {
+	limits map[string]map[string]entities.Limits
+}
+
+func NewStaticLimitPolicy(limits map[string]map[string]entities.Limits) *StaticLimitPolicy {
+	return &StaticLimitPolicy{limits: limits}
+}
+
+// NewFileLimitPolicy loads a static policy from a JSON file with the same
+// shape accepted by NewStaticLimitPolicy.
+func NewFileLimitPolicy(path string) (*StaticLimitPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var limits map[string]map[string]entities.Limits
+	if err := json.Unmarshal(data, &limits); err != nil {
+		return nil, err
+	}
+	return NewStaticLimitPolicy(limits), nil
+}
+
+// Limits returns the limits of the level in the currency, if there are any.
+func (p *StaticLimitPolicy) Limits(level string, currency string) (entities.Limits, bool) {
+	limits, ok := p.limits[level][currency]
+	return limits, ok
+}
@@ -0,0 +1,107 @@
+package repository_limit
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LimitRepository interface {
+	Reserve(key string, amount int64, max int64, expiresAt time.Time, ctx context.Context) (int64, error)
+	Release(key string, amount int64, ctx context.Context) error
+	Usage(keys []string, ctx context.Context) (map[string]int64, error)
+}
+
+type MongoLimitRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoLimitRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoLimitRepository {
+	return &MongoLimitRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes lets Mongo remove the usage of windows that are over.
+func (repo *MongoLimitRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("limit_usage").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:limit_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// Reserve adds amount to the usage of the window named key, provided it stays
+// within max, and returns the usage afterwards. Otherwise it returns
+// ErrLimitReached with the usage as it is. The check and the increment are a
+// single update, so concurrent debits from several replicas cannot go over
+// the limit together. amount must not be over max on its own.
+func (repo *MongoLimitRepository) Reserve(key string, amount int64, max int64, expiresAt time.Time, ctx context.Context) (int64, error) {
+	coll := repo.db.Database("mywallet").Collection("limit_usage")
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var usage struct {
+		Used int64 `bson:"used"`
+	}
+	// When the window exists but has no room left, the filter does not match
+	// and the upsert fails on the _id of the existing window.
+	err := coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": key, "used": bson.M{"$lte": max - amount}},
+		bson.M{"$inc": bson.M{"used": amount}, "$setOnInsert": bson.M{"expires_at": expiresAt}},
+		opts,
+	).Decode(&usage)
+	if mongo.IsDuplicateKeyError(err) {
+		used, err := repo.Usage([]string{key}, ctx)
+		if err != nil {
+			return 0, err
+		}
+		return used[key], ErrLimitReached
+	}
+	if err != nil {
+		repo.logger.Errorln("Layer:limit_repository ", "Method:Reserve ", "Error:", err)
+		return 0, err
+	}
+	return usage.Used, nil
+}
+
+// Release gives back amount reserved in the window named key.
+func (repo *MongoLimitRepository) Release(key string, amount int64, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("limit_usage")
+	_, err := coll.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$inc": bson.M{"used": -amount}})
+	if err != nil {
+		repo.logger.Errorln("Layer:limit_repository ", "Method:Release ", "Error:", err)
+	}
+	return err
+}
+
+// Usage returns the usage of the windows named by keys; windows never used
+// are left out.
+func (repo *MongoLimitRepository) Usage(keys []string, ctx context.Context) (map[string]int64, error) {
+	coll := repo.db.Database("mywallet").Collection("limit_usage")
+	cursor, err := coll.Find(ctx, bson.M{"_id": bson.M{"$in": keys}})
+	if err != nil {
+		repo.logger.Errorln("Layer:limit_repository ", "Method:Usage ", "Error:", err)
+		return nil, err
+	}
+	var windows []struct {
+		Key  string `bson:"_id"`
+		Used int64  `bson:"used"`
+	}
+	if err := cursor.All(ctx, &windows); err != nil {
+		repo.logger.Errorln("Layer:limit_repository ", "Method:Usage ", "Error:", err)
+		return nil, err
+	}
+	usage := map[string]int64{}
+	for _, window := range windows {
+		usage[window.Key] = window.Used
+	}
+	return usage, nil
+}
@@ -0,0 +1,8 @@
+package repository_member
+
+import "errors"
+
+var ErrMemberNotFound = errors.New("Error not found wallet member")
+var ErrMemberExists = errors.New("User already invited to the wallet")
+var ErrMemberStatusChanged = errors.New("Wallet member status changed")
+var ErrSpendingLimitReached = errors.New("Member spending limit reached")
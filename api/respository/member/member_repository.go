@@ -0,0 +1,240 @@
+package repository_member
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MemberRepository interface {
+	InviteMember(member entities.WalletMember, ctx context.Context) (entities.WalletMember, error)
+	GetMember(id string, ctx context.Context) (entities.WalletMember, error)
+	GetMembership(walletID string, userID string, ctx context.Context) (entities.WalletMember, error)
+	ListMembers(walletID string, ctx context.Context) ([]entities.WalletMember, error)
+	ListMemberships(userID string, ctx context.Context) ([]entities.WalletMember, error)
+	UpdateMember(member entities.WalletMember, from string, ctx context.Context) error
+	ReserveSpending(key string, amount int64, max int64, expiresAt time.Time, ctx context.Context) (int64, error)
+	ReleaseSpending(key string, amount int64, ctx context.Context) error
+	Spending(keys []string, ctx context.Context) (map[string]int64, error)
+}
+
+type MongoMemberRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoMemberRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoMemberRepository {
+	return &MongoMemberRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes keeps a single membership per user and wallet, finds the
+// memberships of a user, and lets Mongo remove the spending of months that
+// are over.
+func (repo *MongoMemberRepository) CreateIndexes(ctx context.Context) error {
+	database := repo.db.Database("mywallet")
+	_, err := database.Collection("wallet_members").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:member_repository ", "Method:CreateIndexes ", "Error:", err)
+		return err
+	}
+	_, err = database.Collection("wallet_member_spending").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:member_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// InviteMember saves the invitation of a user to a wallet. A user who
+// declined an invitation or was removed can be invited again, which replaces
+// their old membership; a user still invited or active fails with
+// ErrMemberExists.
+func (repo *MongoMemberRepository) InviteMember(member entities.WalletMember, ctx context.Context) (entities.WalletMember, error) {
+	coll := repo.db.Database("mywallet").Collection("wallet_members")
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	// When the user is still invited or active the filter does not match and
+	// the upsert fails on the unique index.
+	err := coll.FindOneAndUpdate(ctx,
+		bson.M{"wallet_id": member.WalletID, "user_id": member.UserID, "status": bson.M{"$in": bson.A{entities.MemberDeclined, entities.MemberRemoved}}},
+		bson.M{
+			"$set": bson.M{
+				"email":           member.Email,
+				"role":            member.Role,
+				"spending_limits": member.SpendingLimits,
+				"status":          member.Status,
+				"invited_by":      member.InvitedBy,
+				"created_at":      member.Created_at,
+				"updated_at":      member.Update_at,
+			},
+			"$unset": bson.M{"joined_at": ""},
+		},
+		opts,
+	).Decode(&member)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.WalletMember{}, ErrMemberExists
+		}
+		repo.logger.Errorln("Layer:member_repository ", "Method:InviteMember ", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	return member, nil
+}
+
+func (repo *MongoMemberRepository) GetMember(id string, ctx context.Context) (entities.WalletMember, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.WalletMember{}, ErrMemberNotFound
+	}
+	return repo.findMember(bson.M{"_id": idd}, ctx)
+}
+
+// GetMembership returns the membership of a user in a wallet, whatever its
+// status.
+func (repo *MongoMemberRepository) GetMembership(walletID string, userID string, ctx context.Context) (entities.WalletMember, error) {
+	return repo.findMember(bson.M{"wallet_id": walletID, "user_id": userID}, ctx)
+}
+
+func (repo *MongoMemberRepository) findMember(filter bson.M, ctx context.Context) (entities.WalletMember, error) {
+	var member entities.WalletMember
+	coll := repo.db.Database("mywallet").Collection("wallet_members")
+	err := coll.FindOne(ctx, filter).Decode(&member)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return member, ErrMemberNotFound
+		}
+		repo.logger.Errorln("Layer:member_repository ", "Method:findMember ", "Error:", err)
+		return member, err
+	}
+	return member, nil
+}
+
+// ListMembers returns the members of a wallet that are invited or active.
+func (repo *MongoMemberRepository) ListMembers(walletID string, ctx context.Context) ([]entities.WalletMember, error) {
+	return repo.listMembers(bson.M{"wallet_id": walletID}, "ListMembers", ctx)
+}
+
+// ListMemberships returns the wallets a user is invited to or active in.
+func (repo *MongoMemberRepository) ListMemberships(userID string, ctx context.Context) ([]entities.WalletMember, error) {
+	return repo.listMembers(bson.M{"user_id": userID}, "ListMemberships", ctx)
+}
+
+func (repo *MongoMemberRepository) listMembers(filter bson.M, method string, ctx context.Context) ([]entities.WalletMember, error) {
+	filter["status"] = bson.M{"$in": bson.A{entities.MemberInvited, entities.MemberActive}}
+	coll := repo.db.Database("mywallet").Collection("wallet_members")
+	cursor, err := coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		repo.logger.Errorln("Layer:member_repository ", "Method:"+method+" ", "Error:", err)
+		return nil, err
+	}
+	members := []entities.WalletMember{}
+	if err := cursor.All(ctx, &members); err != nil {
+		repo.logger.Errorln("Layer:member_repository ", "Method:"+method+" ", "Error:", err)
+		return nil, err
+	}
+	return members, nil
+}
+
+// UpdateMember saves the role, spending limits and status of a member,
+// provided its status is still from, so that an invitation cannot be
+// accepted after it was withdrawn. Otherwise it fails with
+// ErrMemberStatusChanged.
+func (repo *MongoMemberRepository) UpdateMember(member entities.WalletMember, from string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(member.ID)
+	if err != nil {
+		return ErrMemberNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("wallet_members")
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": idd, "status": from}, bson.M{"$set": bson.M{
+		"role":            member.Role,
+		"spending_limits": member.SpendingLimits,
+		"status":          member.Status,
+		"joined_at":       member.Joined_at,
+		"updated_at":      member.Update_at,
+	}})
+	if err != nil {
+		repo.logger.Errorln("Layer:member_repository ", "Method:UpdateMember ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrMemberStatusChanged
+	}
+	return nil
+}
+
+// ReserveSpending adds amount to the spending of the month named key,
+// provided it stays within max, and returns the spending afterwards.
+// Otherwise it returns ErrSpendingLimitReached with the spending as it is.
+// The check and the increment are a single update, so members spending at
+// the same time cannot go over the limit together.
+func (repo *MongoMemberRepository) ReserveSpending(key string, amount int64, max int64, expiresAt time.Time, ctx context.Context) (int64, error) {
+	coll := repo.db.Database("mywallet").Collection("wallet_member_spending")
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var spending struct {
+		Spent int64 `bson:"spent"`
+	}
+	err := coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": key, "spent": bson.M{"$lte": max - amount}},
+		bson.M{"$inc": bson.M{"spent": amount}, "$setOnInsert": bson.M{"expires_at": expiresAt}},
+		opts,
+	).Decode(&spending)
+	if mongo.IsDuplicateKeyError(err) {
+		spent, err := repo.Spending([]string{key}, ctx)
+		if err != nil {
+			return 0, err
+		}
+		return spent[key], ErrSpendingLimitReached
+	}
+	if err != nil {
+		repo.logger.Errorln("Layer:member_repository ", "Method:ReserveSpending ", "Error:", err)
+		return 0, err
+	}
+	return spending.Spent, nil
+}
+
+// ReleaseSpending gives back amount reserved in the month named key.
+func (repo *MongoMemberRepository) ReleaseSpending(key string, amount int64, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("wallet_member_spending")
+	_, err := coll.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$inc": bson.M{"spent": -amount}})
+	if err != nil {
+		repo.logger.Errorln("Layer:member_repository ", "Method:ReleaseSpending ", "Error:", err)
+	}
+	return err
+}
+
+// Spending returns the spending of the months named by keys; months without
+// spending are left out.
+func (repo *MongoMemberRepository) Spending(keys []string, ctx context.Context) (map[string]int64, error) {
+	coll := repo.db.Database("mywallet").Collection("wallet_member_spending")
+	cursor, err := coll.Find(ctx, bson.M{"_id": bson.M{"$in": keys}})
+	if err != nil {
+		repo.logger.Errorln("Layer:member_repository ", "Method:Spending ", "Error:", err)
+		return nil, err
+	}
+	var months []struct {
+		Key   string `bson:"_id"`
+		Spent int64  `bson:"spent"`
+	}
+	if err := cursor.All(ctx, &months); err != nil {
+		repo.logger.Errorln("Layer:member_repository ", "Method:Spending ", "Error:", err)
+		return nil, err
+	}
+	spending := map[string]int64{}
+	for _, month := range months {
+		spending[month.Key] = month.Spent
+	}
+	return spending, nil
+}
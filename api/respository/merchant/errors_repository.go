@@ -0,0 +1,8 @@
+package repository_merchant
+
+import "errors"
+
+var ErrMerchantNotFound = errors.New("Error not found merchant")
+var ErrMerchantExists = errors.New("User already has a merchant account")
+var ErrQRCodeNotFound = errors.New("Error not found QR code")
+var ErrQRStatusChanged = errors.New("QR code status changed")
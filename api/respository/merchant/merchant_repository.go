@@ -0,0 +1,190 @@
+package repository_merchant
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MerchantRepository interface {
+	CreateMerchant(merchant entities.Merchant, ctx context.Context) (entities.Merchant, error)
+	GetMerchant(id string, ctx context.Context) (entities.Merchant, error)
+	GetMerchantByUser(userID string, ctx context.Context) (entities.Merchant, error)
+	CreateQRCode(qr entities.MerchantQR, ctx context.Context) (entities.MerchantQR, error)
+	GetQRCode(id string, ctx context.Context) (entities.MerchantQR, error)
+	UpdateQRStatus(id string, from string, to string, paymentID string, ctx context.Context) error
+	SettlementDays(walletID string, from time.Time, to time.Time, ctx context.Context) ([]entities.SettlementDay, error)
+}
+
+type MongoMerchantRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoMerchantRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoMerchantRepository {
+	return &MongoMerchantRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes gives each user a single merchant account.
+func (repo *MongoMerchantRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("merchants").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:merchant_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoMerchantRepository) CreateMerchant(merchant entities.Merchant, ctx context.Context) (entities.Merchant, error) {
+	coll := repo.db.Database("mywallet").Collection("merchants")
+	result, err := coll.InsertOne(ctx, merchant)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.Merchant{}, ErrMerchantExists
+		}
+		repo.logger.Errorln("Layer:merchant_repository ", "Method:CreateMerchant ", "Error:", err)
+		return entities.Merchant{}, err
+	}
+	merchant.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return merchant, nil
+}
+
+func (repo *MongoMerchantRepository) GetMerchant(id string, ctx context.Context) (entities.Merchant, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Merchant{}, ErrMerchantNotFound
+	}
+	return repo.findMerchant(bson.M{"_id": idd}, ctx)
+}
+
+func (repo *MongoMerchantRepository) GetMerchantByUser(userID string, ctx context.Context) (entities.Merchant, error) {
+	return repo.findMerchant(bson.M{"user_id": userID}, ctx)
+}
+
+func (repo *MongoMerchantRepository) findMerchant(filter bson.M, ctx context.Context) (entities.Merchant, error) {
+	var merchant entities.Merchant
+	coll := repo.db.Database("mywallet").Collection("merchants")
+	err := coll.FindOne(ctx, filter).Decode(&merchant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return merchant, ErrMerchantNotFound
+		}
+		repo.logger.Errorln("Layer:merchant_repository ", "Method:findMerchant ", "Error:", err)
+		return merchant, err
+	}
+	return merchant, nil
+}
+
+func (repo *MongoMerchantRepository) CreateQRCode(qr entities.MerchantQR, ctx context.Context) (entities.MerchantQR, error) {
+	coll := repo.db.Database("mywallet").Collection("merchant_qr_codes")
+	result, err := coll.InsertOne(ctx, qr)
+	if err != nil {
+		repo.logger.Errorln("Layer:merchant_repository ", "Method:CreateQRCode ", "Error:", err)
+		return entities.MerchantQR{}, err
+	}
+	qr.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return qr, nil
+}
+
+func (repo *MongoMerchantRepository) GetQRCode(id string, ctx context.Context) (entities.MerchantQR, error) {
+	var qr entities.MerchantQR
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return qr, ErrQRCodeNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("merchant_qr_codes")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&qr)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return qr, ErrQRCodeNotFound
+		}
+		repo.logger.Errorln("Layer:merchant_repository ", "Method:GetQRCode ", "Error:", err)
+		return qr, err
+	}
+	return qr, nil
+}
+
+// UpdateQRStatus moves a dynamic QR code from one status to another,
+// recording the payment that paid it, if any. It fails with
+// ErrQRStatusChanged when the code is no longer in the from status, so a
+// code cannot be paid twice.
+func (repo *MongoMerchantRepository) UpdateQRStatus(id string, from string, to string, paymentID string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrQRCodeNotFound
+	}
+	set := bson.M{"status": to}
+	if paymentID != "" {
+		set["payment_id"] = paymentID
+	}
+	coll := repo.db.Database("mywallet").Collection("merchant_qr_codes")
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": idd, "status": from}, bson.M{"$set": set})
+	if err != nil {
+		repo.logger.Errorln("Layer:merchant_repository ", "Method:UpdateQRStatus ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrQRStatusChanged
+	}
+	return nil
+}
+
+// SettlementDays adds up, by UTC day and currency, the merchant payments
+// the wallet received in [from, to), reading the ledger entries themselves:
+// the wallet's credits are the gross and the revenue lines the fees.
+func (repo *MongoMerchantRepository) SettlementDays(walletID string, from time.Time, to time.Time, ctx context.Context) ([]entities.SettlementDay, error) {
+	credit := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$lines.wallet_id", walletID}},
+		bson.M{"$gt": bson.A{"$lines.amount", 0}},
+	}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"type":            entities.EntryMerchantPayment,
+			"lines.wallet_id": walletID,
+			"created_at":      bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$unwind", Value: "$lines"}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"day":      bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$created_at", "timezone": "UTC"}},
+				"currency": "$lines.currency",
+			},
+			"payments": bson.M{"$sum": bson.M{"$cond": bson.A{credit, 1, 0}}},
+			"gross":    bson.M{"$sum": bson.M{"$cond": bson.A{credit, "$lines.amount", 0}}},
+			"fees":     bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$lines.account", entities.AccountFeeRevenue}}, "$lines.amount", 0}}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":      0,
+			"day":      "$_id.day",
+			"currency": "$_id.currency",
+			"payments": 1,
+			"gross":    1,
+			"fees":     1,
+			"net":      bson.M{"$subtract": bson.A{"$gross", "$fees"}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "day", Value: 1}, {Key: "currency", Value: 1}}}},
+	}
+	coll := repo.db.Database("mywallet").Collection("ledger_entries")
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		repo.logger.Errorln("Layer:merchant_repository ", "Method:SettlementDays ", "Error:", err)
+		return nil, err
+	}
+	days := []entities.SettlementDay{}
+	if err := cursor.All(ctx, &days); err != nil {
+		repo.logger.Errorln("Layer:merchant_repository ", "Method:SettlementDays ", "Error:", err)
+		return nil, err
+	}
+	return days, nil
+}
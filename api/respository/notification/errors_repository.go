@@ -0,0 +1,5 @@
+package repository_notification
+
+import "errors"
+
+var ErrNotificationNotFound = errors.New("Error not found notification")
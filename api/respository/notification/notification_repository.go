@@ -0,0 +1,102 @@
+package repository_notification
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepository interface {
+	CreateNotification(notification entities.Notification, ctx context.Context) (entities.Notification, error)
+	ListNotifications(userID string, unreadOnly bool, limit int64, ctx context.Context) ([]entities.Notification, error)
+	MarkRead(id string, userID string, at time.Time, ctx context.Context) (entities.Notification, error)
+}
+
+type MongoNotificationRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoNotificationRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoNotificationRepository {
+	return &MongoNotificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (repo *MongoNotificationRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("notifications").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "read", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:notification_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoNotificationRepository) CreateNotification(notification entities.Notification, ctx context.Context) (entities.Notification, error) {
+	coll := repo.db.Database("mywallet").Collection("notifications")
+	result, err := coll.InsertOne(ctx, notification)
+	if err != nil {
+		repo.logger.Errorln("Layer:notification_repository ", "Method:CreateNotification ", "Error:", err)
+		return entities.Notification{}, err
+	}
+	notification.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return notification, nil
+}
+
+// ListNotifications returns the latest notifications of the user, newest
+// first.
+func (repo *MongoNotificationRepository) ListNotifications(userID string, unreadOnly bool, limit int64, ctx context.Context) ([]entities.Notification, error) {
+	notifications := []entities.Notification{}
+	query := bson.M{"user_id": userID}
+	if unreadOnly {
+		query["read"] = false
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	coll := repo.db.Database("mywallet").Collection("notifications")
+	cursor, err := coll.Find(ctx, query, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:notification_repository ", "Method:ListNotifications ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		repo.logger.Errorln("Layer:notification_repository ", "Method:ListNotifications ", "Error:", err)
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkRead marks a notification of the user as read. A notification that was
+// already read keeps its first read time.
+func (repo *MongoNotificationRepository) MarkRead(id string, userID string, at time.Time, ctx context.Context) (entities.Notification, error) {
+	var notification entities.Notification
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return notification, ErrNotificationNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("notifications")
+	_, err = coll.UpdateOne(ctx,
+		bson.M{"_id": idd, "user_id": userID, "read": false},
+		bson.M{"$set": bson.M{"read": true, "read_at": at}},
+	)
+	if err != nil {
+		repo.logger.Errorln("Layer:notification_repository ", "Method:MarkRead ", "Error:", err)
+		return notification, err
+	}
+	err = coll.FindOne(ctx, bson.M{"_id": idd, "user_id": userID}).Decode(&notification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return notification, ErrNotificationNotFound
+		}
+		repo.logger.Errorln("Layer:notification_repository ", "Method:MarkRead ", "Error:", err)
+		return notification, err
+	}
+	return notification, nil
+}
@@ -0,0 +1,6 @@
+package repository_payment_request
+
+import "errors"
+
+var ErrPaymentRequestNotFound = errors.New("Error not found payment request")
+var ErrStatusChanged = errors.New("Payment request status changed")
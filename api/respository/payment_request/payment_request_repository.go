@@ -0,0 +1,142 @@
+package repository_payment_request
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxListedRequests = 100
+
+type PaymentRequestRepository interface {
+	CreatePaymentRequest(request entities.PaymentRequest, ctx context.Context) (entities.PaymentRequest, error)
+	GetPaymentRequest(id string, ctx context.Context) (entities.PaymentRequest, error)
+	ListPaymentRequests(userID string, direction string, status string, ctx context.Context) ([]entities.PaymentRequest, error)
+	UpdateStatus(id string, from string, to string, transferID string, ctx context.Context) error
+	ListSplitRequests(splitID string, ctx context.Context) ([]entities.PaymentRequest, error)
+}
+
+type MongoPaymentRequestRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoPaymentRequestRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoPaymentRequestRepository {
+	return &MongoPaymentRequestRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (repo *MongoPaymentRequestRepository) CreateIndexes(ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("payment_requests")
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "payer_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "requester_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "split_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:payment_request_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoPaymentRequestRepository) CreatePaymentRequest(request entities.PaymentRequest, ctx context.Context) (entities.PaymentRequest, error) {
+	coll := repo.db.Database("mywallet").Collection("payment_requests")
+	result, err := coll.InsertOne(ctx, request)
+	if err != nil {
+		repo.logger.Errorln("Layer:payment_request_repository ", "Method:CreatePaymentRequest ", "Error:", err)
+		return entities.PaymentRequest{}, err
+	}
+	request.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return request, nil
+}
+
+func (repo *MongoPaymentRequestRepository) GetPaymentRequest(id string, ctx context.Context) (entities.PaymentRequest, error) {
+	var request entities.PaymentRequest
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return request, ErrPaymentRequestNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("payment_requests")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&request)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return request, ErrPaymentRequestNotFound
+		}
+		repo.logger.Errorln("Layer:payment_request_repository ", "Method:GetPaymentRequest ", "Error:", err)
+		return request, err
+	}
+	return request, nil
+}
+
+// ListPaymentRequests returns the newest requests the user received
+// (incoming) or sent (outgoing), optionally only those with status.
+func (repo *MongoPaymentRequestRepository) ListPaymentRequests(userID string, direction string, status string, ctx context.Context) ([]entities.PaymentRequest, error) {
+	requests := []entities.PaymentRequest{}
+	filter := bson.M{"requester_id": userID}
+	if direction == entities.DirectionIncoming {
+		filter = bson.M{"payer_id": userID}
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+	coll := repo.db.Database("mywallet").Collection("payment_requests")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(maxListedRequests)
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:payment_request_repository ", "Method:ListPaymentRequests ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &requests); err != nil {
+		repo.logger.Errorln("Layer:payment_request_repository ", "Method:ListPaymentRequests ", "Error:", err)
+		return nil, err
+	}
+	return requests, nil
+}
+
+// UpdateStatus moves a request from one status to another, recording the
+// transfer that paid it, if any. It fails with ErrStatusChanged when the request is no longer in the
+// from status, so concurrent answers cannot both win.
+func (repo *MongoPaymentRequestRepository) UpdateStatus(id string, from string, to string, transferID string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrPaymentRequestNotFound
+	}
+	set := bson.M{"status": to, "updated_at": time.Now().UTC()}
+	if transferID != "" {
+		set["transfer_id"] = transferID
+	}
+	coll := repo.db.Database("mywallet").Collection("payment_requests")
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": idd, "status": from}, bson.M{"$set": set})
+	if err != nil {
+		repo.logger.Errorln("Layer:payment_request_repository ", "Method:UpdateStatus ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrStatusChanged
+	}
+	return nil
+}
+
+// ListSplitRequests returns every request made for a bill split.
+func (repo *MongoPaymentRequestRepository) ListSplitRequests(splitID string, ctx context.Context) ([]entities.PaymentRequest, error) {
+	requests := []entities.PaymentRequest{}
+	coll := repo.db.Database("mywallet").Collection("payment_requests")
+	cursor, err := coll.Find(ctx, bson.M{"split_id": splitID})
+	if err != nil {
+		repo.logger.Errorln("Layer:payment_request_repository ", "Method:ListSplitRequests ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &requests); err != nil {
+		repo.logger.Errorln("Layer:payment_request_repository ", "Method:ListSplitRequests ", "Error:", err)
+		return nil, err
+	}
+	return requests, nil
+}
@@ -0,0 +1,6 @@
+package repository_pocket
+
+import "errors"
+
+var ErrPocketNotFound = errors.New("Error not found pocket")
+var ErrPocketChanged = errors.New("Pocket changed since it was read")
@@ -0,0 +1,197 @@
+package repository_pocket
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PocketRepository interface {
+	CreatePocket(pocket entities.Pocket, ctx context.Context) (entities.Pocket, error)
+	GetPocket(id string, ctx context.Context) (entities.Pocket, error)
+	ListPockets(walletID string, ctx context.Context) ([]entities.Pocket, error)
+	UpdatePocket(pocket entities.Pocket, ctx context.Context) error
+	ClosePocket(id string, ctx context.Context) error
+	DueAutoSaves(now time.Time, limit int64, ctx context.Context) ([]entities.Pocket, error)
+	ClaimAutoSave(id string, dueAt time.Time, next time.Time, ctx context.Context) error
+}
+
+type MongoPocketRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoPocketRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoPocketRepository {
+	return &MongoPocketRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (repo *MongoPocketRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("pockets").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_auto_save_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:pocket_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoPocketRepository) CreatePocket(pocket entities.Pocket, ctx context.Context) (entities.Pocket, error) {
+	coll := repo.db.Database("mywallet").Collection("pockets")
+	result, err := coll.InsertOne(ctx, pocket)
+	if err != nil {
+		repo.logger.Errorln("Layer:pocket_repository ", "Method:CreatePocket ", "Error:", err)
+		return entities.Pocket{}, err
+	}
+	pocket.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return pocket, nil
+}
+
+func (repo *MongoPocketRepository) GetPocket(id string, ctx context.Context) (entities.Pocket, error) {
+	var pocket entities.Pocket
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return pocket, ErrPocketNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("pockets")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&pocket)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return pocket, ErrPocketNotFound
+		}
+		repo.logger.Errorln("Layer:pocket_repository ", "Method:GetPocket ", "Error:", err)
+		return pocket, err
+	}
+	return pocket, nil
+}
+
+// ListPockets returns the active pockets of a wallet, oldest first.
+func (repo *MongoPocketRepository) ListPockets(walletID string, ctx context.Context) ([]entities.Pocket, error) {
+	pockets := []entities.Pocket{}
+	coll := repo.db.Database("mywallet").Collection("pockets")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := coll.Find(ctx, bson.M{"wallet_id": walletID, "status": entities.PocketActive}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:pocket_repository ", "Method:ListPockets ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &pockets); err != nil {
+		repo.logger.Errorln("Layer:pocket_repository ", "Method:ListPockets ", "Error:", err)
+		return nil, err
+	}
+	return pockets, nil
+}
+
+// UpdatePocket stores the goal and auto-save settings of an active pocket.
+// The balance is only ever changed by ledger entries and is left alone.
+func (repo *MongoPocketRepository) UpdatePocket(pocket entities.Pocket, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(pocket.ID)
+	if err != nil {
+		return ErrPocketNotFound
+	}
+	set := bson.M{
+		"name":          pocket.Name,
+		"target_amount": pocket.TargetAmount,
+		"round_up_to":   pocket.RoundUpTo,
+		"weekly_amount": pocket.WeeklyAmount,
+		"updated_at":    pocket.Update_at,
+	}
+	unset := bson.M{}
+	if pocket.TargetDate != nil {
+		set["target_date"] = pocket.TargetDate
+	} else {
+		unset["target_date"] = ""
+	}
+	if pocket.NextAutoSaveAt != nil {
+		set["next_auto_save_at"] = pocket.NextAutoSaveAt
+	} else {
+		unset["next_auto_save_at"] = ""
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	coll := repo.db.Database("mywallet").Collection("pockets")
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": idd, "status": entities.PocketActive}, update)
+	if err != nil {
+		repo.logger.Errorln("Layer:pocket_repository ", "Method:UpdatePocket ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrPocketNotFound
+	}
+	return nil
+}
+
+// ClosePocket closes an active pocket whose balance is zero. It fails with
+// ErrPocketChanged when money came in after the balance was given back.
+func (repo *MongoPocketRepository) ClosePocket(id string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrPocketNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("pockets")
+	update := bson.M{
+		"$set":   bson.M{"status": entities.PocketClosed, "updated_at": time.Now().UTC()},
+		"$unset": bson.M{"next_auto_save_at": ""},
+	}
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": idd, "status": entities.PocketActive, "balance": 0}, update)
+	if err != nil {
+		repo.logger.Errorln("Layer:pocket_repository ", "Method:ClosePocket ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrPocketChanged
+	}
+	return nil
+}
+
+// DueAutoSaves returns active pockets whose weekly save is due.
+func (repo *MongoPocketRepository) DueAutoSaves(now time.Time, limit int64, ctx context.Context) ([]entities.Pocket, error) {
+	pockets := []entities.Pocket{}
+	filter := bson.M{"status": entities.PocketActive, "next_auto_save_at": bson.M{"$lte": now}}
+	opts := options.Find().SetSort(bson.D{{Key: "next_auto_save_at", Value: 1}}).SetLimit(limit)
+	coll := repo.db.Database("mywallet").Collection("pockets")
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:pocket_repository ", "Method:DueAutoSaves ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &pockets); err != nil {
+		repo.logger.Errorln("Layer:pocket_repository ", "Method:DueAutoSaves ", "Error:", err)
+		return nil, err
+	}
+	return pockets, nil
+}
+
+// ClaimAutoSave moves the next weekly save of a pocket from dueAt to next.
+// Only one caller can move it, so a due save is made at most once; the others
+// get ErrPocketChanged.
+func (repo *MongoPocketRepository) ClaimAutoSave(id string, dueAt time.Time, next time.Time, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrPocketNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("pockets")
+	result, err := coll.UpdateOne(ctx,
+		bson.M{"_id": idd, "status": entities.PocketActive, "next_auto_save_at": dueAt},
+		bson.M{"$set": bson.M{"next_auto_save_at": next}},
+	)
+	if err != nil {
+		repo.logger.Errorln("Layer:pocket_repository ", "Method:ClaimAutoSave ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrPocketChanged
+	}
+	return nil
+}
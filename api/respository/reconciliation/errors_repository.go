@@ -0,0 +1,5 @@
+package repository_reconciliation
+
+import "errors"
+
+var ErrReconciliationNotFound = errors.New("Error not found reconciliation")
@@ -0,0 +1,167 @@
+package repository_reconciliation
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReconciliationRepository interface {
+	CreateRun(run entities.ReconciliationRun, ctx context.Context) (entities.ReconciliationRun, error)
+	CreateItems(items []entities.ReconciliationItem, ctx context.Context) error
+	GetRun(id string, ctx context.Context) (entities.ReconciliationRun, error)
+	ListRuns(limit int64, ctx context.Context) ([]entities.ReconciliationRun, error)
+	ListItems(runID string, result string, ctx context.Context) ([]entities.ReconciliationItem, error)
+	ProviderTransactions(types []string, from time.Time, to time.Time, ctx context.Context) ([]entities.Transaction, error)
+}
+
+type MongoReconciliationRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoReconciliationRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoReconciliationRepository {
+	return &MongoReconciliationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes keeps the runs in order, the items of a run together and
+// lets the transactions of all wallets be read by type and day.
+func (repo *MongoReconciliationRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("reconciliation_runs").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:CreateIndexes ", "Error:", err)
+		return err
+	}
+	_, err = repo.db.Database("mywallet").Collection("reconciliation_items").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "run_id", Value: 1}, {Key: "result", Value: 1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:CreateIndexes ", "Error:", err)
+		return err
+	}
+	_, err = repo.db.Database("mywallet").Collection("transactions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "type", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoReconciliationRepository) CreateRun(run entities.ReconciliationRun, ctx context.Context) (entities.ReconciliationRun, error) {
+	coll := repo.db.Database("mywallet").Collection("reconciliation_runs")
+	result, err := coll.InsertOne(ctx, run)
+	if err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:CreateRun ", "Error:", err)
+		return entities.ReconciliationRun{}, err
+	}
+	run.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return run, nil
+}
+
+func (repo *MongoReconciliationRepository) CreateItems(items []entities.ReconciliationItem, ctx context.Context) error {
+	if len(items) == 0 {
+		return nil
+	}
+	documents := make([]interface{}, len(items))
+	for i, item := range items {
+		documents[i] = item
+	}
+	coll := repo.db.Database("mywallet").Collection("reconciliation_items")
+	_, err := coll.InsertMany(ctx, documents)
+	if err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:CreateItems ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoReconciliationRepository) GetRun(id string, ctx context.Context) (entities.ReconciliationRun, error) {
+	var run entities.ReconciliationRun
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return run, ErrReconciliationNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("reconciliation_runs")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&run)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return run, ErrReconciliationNotFound
+		}
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:GetRun ", "Error:", err)
+		return run, err
+	}
+	return run, nil
+}
+
+// ListRuns returns the latest runs, newest first.
+func (repo *MongoReconciliationRepository) ListRuns(limit int64, ctx context.Context) ([]entities.ReconciliationRun, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	coll := repo.db.Database("mywallet").Collection("reconciliation_runs")
+	cursor, err := coll.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:ListRuns ", "Error:", err)
+		return nil, err
+	}
+	runs := []entities.ReconciliationRun{}
+	if err := cursor.All(ctx, &runs); err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:ListRuns ", "Error:", err)
+		return nil, err
+	}
+	return runs, nil
+}
+
+// ListItems returns the items of a run in the order they were reconciled,
+// only those with the given result when it is not empty.
+func (repo *MongoReconciliationRepository) ListItems(runID string, result string, ctx context.Context) ([]entities.ReconciliationItem, error) {
+	query := bson.M{"run_id": runID}
+	if result != "" {
+		query["result"] = result
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	coll := repo.db.Database("mywallet").Collection("reconciliation_items")
+	cursor, err := coll.Find(ctx, query, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:ListItems ", "Error:", err)
+		return nil, err
+	}
+	items := []entities.ReconciliationItem{}
+	if err := cursor.All(ctx, &items); err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:ListItems ", "Error:", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// ProviderTransactions returns the completed transactions of the given types
+// of every wallet created in [from, to), oldest first.
+func (repo *MongoReconciliationRepository) ProviderTransactions(types []string, from time.Time, to time.Time, ctx context.Context) ([]entities.Transaction, error) {
+	query := bson.M{
+		"type":       bson.M{"$in": types},
+		"status":     entities.StatusCompleted,
+		"created_at": bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	coll := repo.db.Database("mywallet").Collection("transactions")
+	cursor, err := coll.Find(ctx, query, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:ProviderTransactions ", "Error:", err)
+		return nil, err
+	}
+	transactions := []entities.Transaction{}
+	if err := cursor.All(ctx, &transactions); err != nil {
+		repo.logger.Errorln("Layer:reconciliation_repository ", "Method:ProviderTransactions ", "Error:", err)
+		return nil, err
+	}
+	return transactions, nil
+}
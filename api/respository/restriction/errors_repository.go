@@ -0,0 +1,6 @@
+package repository_restriction
+
+import "errors"
+
+var ErrRestrictionNotFound = errors.New("Error not found restriction")
+var ErrRestrictionLifted = errors.New("Restriction was already lifted")
@@ -0,0 +1,169 @@
+package repository_restriction
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RestrictionRepository interface {
+	CreateRestriction(restriction entities.WalletRestriction, ctx context.Context) (entities.WalletRestriction, error)
+	GetRestriction(id string, ctx context.Context) (entities.WalletRestriction, error)
+	ListRestrictions(walletID string, ctx context.Context) ([]entities.WalletRestriction, error)
+	ActiveRestrictions(walletID string, now time.Time, ctx context.Context) ([]entities.WalletRestriction, error)
+	LiftRestriction(id string, liftedBy string, note string, at time.Time, ctx context.Context) (entities.WalletRestriction, error)
+	CreateEvent(event entities.RestrictionEvent, ctx context.Context) (entities.RestrictionEvent, error)
+	ListEvents(walletID string, limit int64, ctx context.Context) ([]entities.RestrictionEvent, error)
+}
+
+type MongoRestrictionRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoRestrictionRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoRestrictionRepository {
+	return &MongoRestrictionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes keeps the restrictions that are checked on every movement
+// and the audit trail of a wallet in order.
+func (repo *MongoRestrictionRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("wallet_restrictions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "expires_at", Value: -1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:restriction_repository ", "Method:CreateIndexes ", "Error:", err)
+		return err
+	}
+	_, err = repo.db.Database("mywallet").Collection("wallet_restriction_events").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "wallet_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:restriction_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoRestrictionRepository) CreateRestriction(restriction entities.WalletRestriction, ctx context.Context) (entities.WalletRestriction, error) {
+	coll := repo.db.Database("mywallet").Collection("wallet_restrictions")
+	result, err := coll.InsertOne(ctx, restriction)
+	if err != nil {
+		repo.logger.Errorln("Layer:restriction_repository ", "Method:CreateRestriction ", "Error:", err)
+		return entities.WalletRestriction{}, err
+	}
+	restriction.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return restriction, nil
+}
+
+func (repo *MongoRestrictionRepository) GetRestriction(id string, ctx context.Context) (entities.WalletRestriction, error) {
+	var restriction entities.WalletRestriction
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return restriction, ErrRestrictionNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("wallet_restrictions")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&restriction)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return restriction, ErrRestrictionNotFound
+		}
+		repo.logger.Errorln("Layer:restriction_repository ", "Method:GetRestriction ", "Error:", err)
+		return restriction, err
+	}
+	return restriction, nil
+}
+
+// ListRestrictions returns every restriction the wallet ever had, newest
+// first.
+func (repo *MongoRestrictionRepository) ListRestrictions(walletID string, ctx context.Context) ([]entities.WalletRestriction, error) {
+	return repo.find(bson.M{"wallet_id": walletID}, "ListRestrictions", ctx)
+}
+
+// ActiveRestrictions returns the restrictions of the wallet that are neither
+// lifted nor expired at now.
+func (repo *MongoRestrictionRepository) ActiveRestrictions(walletID string, now time.Time, ctx context.Context) ([]entities.WalletRestriction, error) {
+	return repo.find(bson.M{
+		"wallet_id":  walletID,
+		"expires_at": bson.M{"$gt": now},
+		"lifted_at":  bson.M{"$exists": false},
+	}, "ActiveRestrictions", ctx)
+}
+
+// LiftRestriction records who lifted a restriction and when. A restriction
+// is lifted once; later callers get ErrRestrictionLifted.
+func (repo *MongoRestrictionRepository) LiftRestriction(id string, liftedBy string, note string, at time.Time, ctx context.Context) (entities.WalletRestriction, error) {
+	var restriction entities.WalletRestriction
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return restriction, ErrRestrictionNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("wallet_restrictions")
+	update := bson.M{"$set": bson.M{"lifted_by": liftedBy, "lift_note": note, "lifted_at": at}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = coll.FindOneAndUpdate(ctx, bson.M{"_id": idd, "lifted_at": bson.M{"$exists": false}}, update, opts).Decode(&restriction)
+	if err == mongo.ErrNoDocuments {
+		if _, err := repo.GetRestriction(id, ctx); err != nil {
+			return entities.WalletRestriction{}, err
+		}
+		return entities.WalletRestriction{}, ErrRestrictionLifted
+	}
+	if err != nil {
+		repo.logger.Errorln("Layer:restriction_repository ", "Method:LiftRestriction ", "Error:", err)
+		return entities.WalletRestriction{}, err
+	}
+	return restriction, nil
+}
+
+func (repo *MongoRestrictionRepository) CreateEvent(event entities.RestrictionEvent, ctx context.Context) (entities.RestrictionEvent, error) {
+	coll := repo.db.Database("mywallet").Collection("wallet_restriction_events")
+	result, err := coll.InsertOne(ctx, event)
+	if err != nil {
+		repo.logger.Errorln("Layer:restriction_repository ", "Method:CreateEvent ", "Error:", err)
+		return entities.RestrictionEvent{}, err
+	}
+	event.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return event, nil
+}
+
+// ListEvents returns the latest limit entries of the audit trail of the
+// wallet, newest first.
+func (repo *MongoRestrictionRepository) ListEvents(walletID string, limit int64, ctx context.Context) ([]entities.RestrictionEvent, error) {
+	events := []entities.RestrictionEvent{}
+	coll := repo.db.Database("mywallet").Collection("wallet_restriction_events")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := coll.Find(ctx, bson.M{"wallet_id": walletID}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:restriction_repository ", "Method:ListEvents ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &events); err != nil {
+		repo.logger.Errorln("Layer:restriction_repository ", "Method:ListEvents ", "Error:", err)
+		return nil, err
+	}
+	return events, nil
+}
+
+func (repo *MongoRestrictionRepository) find(query bson.M, method string, ctx context.Context) ([]entities.WalletRestriction, error) {
+	restrictions := []entities.WalletRestriction{}
+	coll := repo.db.Database("mywallet").Collection("wallet_restrictions")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := coll.Find(ctx, query, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:restriction_repository ", "Method:"+method+" ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &restrictions); err != nil {
+		repo.logger.Errorln("Layer:restriction_repository ", "Method:"+method+" ", "Error:", err)
+		return nil, err
+	}
+	return restrictions, nil
+}
@@ -0,0 +1,6 @@
+package repository_sanctions
+
+import "errors"
+
+var ErrScreeningNotFound = errors.New("Error not found sanctions screening")
+var ErrScreeningStatusChanged = errors.New("Sanctions screening status changed")
@@ -0,0 +1,200 @@
+package repository_sanctions
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"my_wallet/api/entities"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sdnNull is how the OFAC files write an empty field.
+const sdnNull = "-0-"
+
+// SanctionsList gives the parties of the sanctions list in force and a
+// version that changes whenever the list does.
+type SanctionsList interface {
+	Entries(ctx context.Context) ([]entities.SanctionsEntry, string, error)
+}
+
+// StaticSanctionsList is a list fixed when the service starts.
+type StaticSanctionsList struct {
+	entries []entities.SanctionsEntry
+	version string
+}
+
+func NewStaticSanctionsList(entries []entities.SanctionsEntry, version string) *StaticSanctionsList {
+	return &StaticSanctionsList{entries: entries, version: version}
+}
+
+func (list *StaticSanctionsList) Entries(ctx context.Context) ([]entities.SanctionsEntry, string, error) {
+	return list.entries, list.version, nil
+}
+
+// FileSanctionsList reads the list from files in the OFAC SDN CSV format:
+// the SDN file with the parties and, optionally, the ALT file with their
+// other names. The files are read again when they change, so a new list is
+// loaded by replacing them.
+type FileSanctionsList struct {
+	sdnPath string
+	altPath string
+	mu      sync.Mutex
+	stamp   string
+	entries []entities.SanctionsEntry
+	version string
+}
+
+// NewFileSanctionsList loads the list from sdnPath and, when altPath is not
+// empty and exists, the aliases from altPath.
+func NewFileSanctionsList(sdnPath string, altPath string) (*FileSanctionsList, error) {
+	list := &FileSanctionsList{sdnPath: sdnPath, altPath: altPath}
+	if _, _, err := list.Entries(context.Background()); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (list *FileSanctionsList) Entries(ctx context.Context) ([]entities.SanctionsEntry, string, error) {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+	stamp, err := list.fileStamp()
+	if err != nil {
+		return nil, "", err
+	}
+	if stamp == list.stamp {
+		return list.entries, list.version, nil
+	}
+	sdn, err := os.ReadFile(list.sdnPath)
+	if err != nil {
+		return nil, "", err
+	}
+	var alt []byte
+	if list.altPath != "" {
+		alt, err = os.ReadFile(list.altPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", err
+		}
+	}
+	entries, err := ParseSDN(bytes.NewReader(sdn))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(alt) > 0 {
+		if err := ParseALT(bytes.NewReader(alt), entries); err != nil {
+			return nil, "", err
+		}
+	}
+	sum := sha256.New()
+	sum.Write(sdn)
+	sum.Write(alt)
+	list.entries = entries
+	list.version = hex.EncodeToString(sum.Sum(nil))[:16]
+	list.stamp = stamp
+	return list.entries, list.version, nil
+}
+
+// fileStamp identifies the current contents of the files by their size and
+// modification time, which is cheaper than hashing them on every call.
+func (list *FileSanctionsList) fileStamp() (string, error) {
+	var stamp strings.Builder
+	for _, path := range []string{list.sdnPath, list.altPath} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) && path == list.altPath {
+			stamp.WriteString("none;")
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&stamp, "%s/%d;", info.ModTime().UTC().Format(time.RFC3339Nano), info.Size())
+	}
+	return stamp.String(), nil
+}
+
+// ParseSDN reads an OFAC SDN file: one party per row, with the entity
+// number, name, type and program as the first four columns.
+func ParseSDN(r io.Reader) ([]entities.SanctionsEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	var entries []entities.SanctionsEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 4 || sdnField(record[0]) == "" || sdnField(record[1]) == "" {
+			continue
+		}
+		entries = append(entries, entities.SanctionsEntry{
+			UID:     sdnField(record[0]),
+			Name:    sdnField(record[1]),
+			Type:    sdnType(sdnField(record[2])),
+			Program: sdnField(record[3]),
+		})
+	}
+}
+
+// ParseALT reads an OFAC ALT file, with the entity number and the other name
+// in the first and fourth columns, and adds the names to the entries.
+func ParseALT(r io.Reader, entries []entities.SanctionsEntry) error {
+	byUID := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		byUID[entry.UID] = i
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) < 4 {
+			continue
+		}
+		i, ok := byUID[sdnField(record[0])]
+		if name := sdnField(record[3]); ok && name != "" {
+			entries[i].Aliases = append(entries[i].Aliases, name)
+		}
+	}
+}
+
+func sdnField(value string) string {
+	value = strings.TrimSpace(value)
+	if value == sdnNull {
+		return ""
+	}
+	return value
+}
+
+// sdnType maps the type column, which is empty for entities.
+func sdnType(value string) string {
+	switch strings.ToLower(value) {
+	case "individual":
+		return entities.SanctionsIndividual
+	case "vessel":
+		return entities.SanctionsVessel
+	case "aircraft":
+		return entities.SanctionsAircraft
+	}
+	return entities.SanctionsEntity
+}
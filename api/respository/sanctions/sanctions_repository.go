@@ -0,0 +1,176 @@
+package repository_sanctions
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// screenedListID names the document recording the list version all users
+// were last screened against.
+const screenedListID = "screened"
+
+type SanctionsRepository interface {
+	CreateScreening(screening entities.SanctionsScreening, ctx context.Context) (entities.SanctionsScreening, error)
+	GetScreening(id string, ctx context.Context) (entities.SanctionsScreening, error)
+	ListScreenings(status string, limit int64, ctx context.Context) ([]entities.SanctionsScreening, error)
+	UpdateScreeningStatus(id string, from string, to string, reviewer string, note string, ctx context.Context) (entities.SanctionsScreening, error)
+	ClearedEntries(userID string, ctx context.Context) ([]string, error)
+	ScreenedVersion(ctx context.Context) (string, error)
+	SetScreenedVersion(version string, ctx context.Context) error
+}
+
+type MongoSanctionsRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoSanctionsRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoSanctionsRepository {
+	return &MongoSanctionsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes keeps the review queue in order and finds the screenings of
+// a user.
+func (repo *MongoSanctionsRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("sanctions_screenings").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:sanctions_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoSanctionsRepository) CreateScreening(screening entities.SanctionsScreening, ctx context.Context) (entities.SanctionsScreening, error) {
+	coll := repo.db.Database("mywallet").Collection("sanctions_screenings")
+	result, err := coll.InsertOne(ctx, screening)
+	if err != nil {
+		repo.logger.Errorln("Layer:sanctions_repository ", "Method:CreateScreening ", "Error:", err)
+		return entities.SanctionsScreening{}, err
+	}
+	screening.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return screening, nil
+}
+
+func (repo *MongoSanctionsRepository) GetScreening(id string, ctx context.Context) (entities.SanctionsScreening, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.SanctionsScreening{}, ErrScreeningNotFound
+	}
+	var screening entities.SanctionsScreening
+	coll := repo.db.Database("mywallet").Collection("sanctions_screenings")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&screening)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return screening, ErrScreeningNotFound
+		}
+		repo.logger.Errorln("Layer:sanctions_repository ", "Method:GetScreening ", "Error:", err)
+		return screening, err
+	}
+	return screening, nil
+}
+
+// ListScreenings returns up to limit screenings in a status, oldest first.
+func (repo *MongoSanctionsRepository) ListScreenings(status string, limit int64, ctx context.Context) ([]entities.SanctionsScreening, error) {
+	screenings := []entities.SanctionsScreening{}
+	coll := repo.db.Database("mywallet").Collection("sanctions_screenings")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(limit)
+	cursor, err := coll.Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:sanctions_repository ", "Method:ListScreenings ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &screenings); err != nil {
+		repo.logger.Errorln("Layer:sanctions_repository ", "Method:ListScreenings ", "Error:", err)
+		return nil, err
+	}
+	return screenings, nil
+}
+
+// UpdateScreeningStatus moves a screening from one status to another. When
+// two analysts race, only the first one moves it; the other gets
+// ErrScreeningStatusChanged.
+func (repo *MongoSanctionsRepository) UpdateScreeningStatus(id string, from string, to string, reviewer string, note string, ctx context.Context) (entities.SanctionsScreening, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.SanctionsScreening{}, ErrScreeningNotFound
+	}
+	var screening entities.SanctionsScreening
+	coll := repo.db.Database("mywallet").Collection("sanctions_screenings")
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": idd, "status": from},
+		bson.M{"$set": bson.M{"status": to, "reviewer": reviewer, "note": note, "reviewed_at": time.Now().UTC()}},
+		opts,
+	).Decode(&screening)
+	if err == mongo.ErrNoDocuments {
+		if _, err := repo.GetScreening(id, ctx); err != nil {
+			return entities.SanctionsScreening{}, err
+		}
+		return entities.SanctionsScreening{}, ErrScreeningStatusChanged
+	}
+	if err != nil {
+		repo.logger.Errorln("Layer:sanctions_repository ", "Method:UpdateScreeningStatus ", "Error:", err)
+		return entities.SanctionsScreening{}, err
+	}
+	return screening, nil
+}
+
+// ClearedEntries returns the listed parties analysts already cleared the user
+// of, so re-screening does not hold the user for them again.
+func (repo *MongoSanctionsRepository) ClearedEntries(userID string, ctx context.Context) ([]string, error) {
+	coll := repo.db.Database("mywallet").Collection("sanctions_screenings")
+	uids, err := coll.Distinct(ctx, "matches.entry_uid", bson.M{"user_id": userID, "status": entities.SanctionsCleared})
+	if err != nil {
+		repo.logger.Errorln("Layer:sanctions_repository ", "Method:ClearedEntries ", "Error:", err)
+		return nil, err
+	}
+	cleared := make([]string, 0, len(uids))
+	for _, uid := range uids {
+		if s, ok := uid.(string); ok {
+			cleared = append(cleared, s)
+		}
+	}
+	return cleared, nil
+}
+
+// ScreenedVersion returns the list version all users were last screened
+// against, empty when they never were.
+func (repo *MongoSanctionsRepository) ScreenedVersion(ctx context.Context) (string, error) {
+	var doc struct {
+		Version string `bson:"version"`
+	}
+	coll := repo.db.Database("mywallet").Collection("sanctions_lists")
+	err := coll.FindOne(ctx, bson.M{"_id": screenedListID}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", nil
+		}
+		repo.logger.Errorln("Layer:sanctions_repository ", "Method:ScreenedVersion ", "Error:", err)
+		return "", err
+	}
+	return doc.Version, nil
+}
+
+func (repo *MongoSanctionsRepository) SetScreenedVersion(version string, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("sanctions_lists")
+	_, err := coll.UpdateOne(ctx,
+		bson.M{"_id": screenedListID},
+		bson.M{"$set": bson.M{"version": version, "screened_at": time.Now().UTC()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		repo.logger.Errorln("Layer:sanctions_repository ", "Method:SetScreenedVersion ", "Error:", err)
+	}
+	return err
+}
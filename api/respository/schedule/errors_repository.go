@@ -0,0 +1,8 @@
+package repository_schedule
+
+import "errors"
+
+var ErrScheduleNotFound = errors.New("Error not found scheduled payment")
+var ErrScheduleLocked = errors.New("Scheduled payment is being run")
+var ErrScheduleNotActive = errors.New("Scheduled payment is not active")
+var ErrExecutionNotFound = errors.New("Error not found held execution")
@@ -0,0 +1,247 @@
+package repository_schedule
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ScheduleRepository interface {
+	CreateSchedule(schedule entities.ScheduledPayment, ctx context.Context) (entities.ScheduledPayment, error)
+	GetSchedule(id string, ctx context.Context) (entities.ScheduledPayment, error)
+	ListSchedules(userID string, ctx context.Context) ([]entities.ScheduledPayment, error)
+	DueSchedules(now time.Time, limit int64, ctx context.Context) ([]entities.ScheduledPayment, error)
+	LockSchedule(id string, now time.Time, until time.Time, ctx context.Context) error
+	CancelSchedule(id string, now time.Time, ctx context.Context) error
+	FinishRun(schedule entities.ScheduledPayment, lockedUntil time.Time, ctx context.Context) error
+	CreateExecution(execution entities.ScheduleExecution, ctx context.Context) (entities.ScheduleExecution, error)
+	ListExecutions(scheduleID string, ctx context.Context) ([]entities.ScheduleExecution, error)
+	ResolveExecution(reviewID string, status string, transferID string, reason string, ctx context.Context) error
+}
+
+type MongoScheduleRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoScheduleRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoScheduleRepository {
+	return &MongoScheduleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (repo *MongoScheduleRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("scheduled_payments").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_run_at", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:CreateIndexes ", "Error:", err)
+		return err
+	}
+	_, err = repo.db.Database("mywallet").Collection("schedule_executions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "schedule_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "review_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoScheduleRepository) CreateSchedule(schedule entities.ScheduledPayment, ctx context.Context) (entities.ScheduledPayment, error) {
+	coll := repo.db.Database("mywallet").Collection("scheduled_payments")
+	result, err := coll.InsertOne(ctx, schedule)
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:CreateSchedule ", "Error:", err)
+		return entities.ScheduledPayment{}, err
+	}
+	schedule.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return schedule, nil
+}
+
+func (repo *MongoScheduleRepository) GetSchedule(id string, ctx context.Context) (entities.ScheduledPayment, error) {
+	var schedule entities.ScheduledPayment
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return schedule, ErrScheduleNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("scheduled_payments")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&schedule)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return schedule, ErrScheduleNotFound
+		}
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:GetSchedule ", "Error:", err)
+		return schedule, err
+	}
+	return schedule, nil
+}
+
+func (repo *MongoScheduleRepository) ListSchedules(userID string, ctx context.Context) ([]entities.ScheduledPayment, error) {
+	schedules := []entities.ScheduledPayment{}
+	coll := repo.db.Database("mywallet").Collection("scheduled_payments")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:ListSchedules ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &schedules); err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:ListSchedules ", "Error:", err)
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// DueSchedules returns the active schedules whose next run is not in the
+// future and that no runner holds, oldest first.
+func (repo *MongoScheduleRepository) DueSchedules(now time.Time, limit int64, ctx context.Context) ([]entities.ScheduledPayment, error) {
+	schedules := []entities.ScheduledPayment{}
+	coll := repo.db.Database("mywallet").Collection("scheduled_payments")
+	filter := bson.M{
+		"status":       entities.ScheduleActive,
+		"next_run_at":  bson.M{"$lte": now},
+		"locked_until": bson.M{"$lte": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "next_run_at", Value: 1}}).SetLimit(limit)
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:DueSchedules ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &schedules); err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:DueSchedules ", "Error:", err)
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// LockSchedule takes a schedule for a run until the given time. Even if two
+// replicas believed they were the leader, only one of them gets the lock.
+func (repo *MongoScheduleRepository) LockSchedule(id string, now time.Time, until time.Time, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrScheduleNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("scheduled_payments")
+	filter := bson.M{"_id": idd, "status": entities.ScheduleActive, "locked_until": bson.M{"$lte": now}}
+	result, err := coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"locked_until": until}})
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:LockSchedule ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrScheduleLocked
+	}
+	return nil
+}
+
+// CancelSchedule cancels a schedule that is still active. It fails with
+// ErrScheduleNotActive when the schedule already ended.
+func (repo *MongoScheduleRepository) CancelSchedule(id string, now time.Time, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrScheduleNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("scheduled_payments")
+	filter := bson.M{"_id": idd, "status": entities.ScheduleActive}
+	update := bson.M{"$set": bson.M{"status": entities.ScheduleCancelled, "updated_at": now}}
+	result, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:CancelSchedule ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrScheduleNotActive
+	}
+	return nil
+}
+
+// FinishRun stores where a run left the schedule and frees its lock. It only
+// matches while the schedule is active and still locked until lockedUntil, so
+// a schedule cancelled during the run stays cancelled; ErrScheduleLocked
+// tells the run lost the schedule.
+func (repo *MongoScheduleRepository) FinishRun(schedule entities.ScheduledPayment, lockedUntil time.Time, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(schedule.ID)
+	if err != nil {
+		return ErrScheduleNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("scheduled_payments")
+	filter := bson.M{"_id": idd, "status": entities.ScheduleActive, "locked_until": lockedUntil}
+	update := bson.M{"$set": bson.M{
+		"status":       schedule.Status,
+		"due_at":       schedule.DueAt,
+		"next_run_at":  schedule.NextRunAt,
+		"attempts":     schedule.Attempts,
+		"runs":         schedule.Runs,
+		"locked_until": time.Time{},
+		"updated_at":   schedule.Update_at,
+	}}
+	result, err := coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:FinishRun ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrScheduleLocked
+	}
+	return nil
+}
+
+func (repo *MongoScheduleRepository) CreateExecution(execution entities.ScheduleExecution, ctx context.Context) (entities.ScheduleExecution, error) {
+	coll := repo.db.Database("mywallet").Collection("schedule_executions")
+	result, err := coll.InsertOne(ctx, execution)
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:CreateExecution ", "Error:", err)
+		return entities.ScheduleExecution{}, err
+	}
+	execution.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return execution, nil
+}
+
+func (repo *MongoScheduleRepository) ListExecutions(scheduleID string, ctx context.Context) ([]entities.ScheduleExecution, error) {
+	executions := []entities.ScheduleExecution{}
+	coll := repo.db.Database("mywallet").Collection("schedule_executions")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := coll.Find(ctx, bson.M{"schedule_id": scheduleID}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:ListExecutions ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &executions); err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:ListExecutions ", "Error:", err)
+		return nil, err
+	}
+	return executions, nil
+}
+
+// ResolveExecution records how the review of the transfer of a held
+// execution ended. It fails with ErrExecutionNotFound when no execution
+// waits on the review.
+func (repo *MongoScheduleRepository) ResolveExecution(reviewID string, status string, transferID string, reason string, ctx context.Context) error {
+	set := bson.M{"status": status}
+	if transferID != "" {
+		set["transfer_id"] = transferID
+	}
+	if reason != "" {
+		set["error"] = reason
+	}
+	coll := repo.db.Database("mywallet").Collection("schedule_executions")
+	result, err := coll.UpdateOne(ctx, bson.M{"review_id": reviewID, "status": entities.ExecutionHeld}, bson.M{"$set": set})
+	if err != nil {
+		repo.logger.Errorln("Layer:schedule_repository ", "Method:ResolveExecution ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrExecutionNotFound
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+package repository_screening
+
+import "errors"
+
+var ErrReviewNotFound = errors.New("Error not found review")
+var ErrReviewStatusChanged = errors.New("Review status changed")
+var ErrRulesReadOnly = errors.New("Screening rules are read only")
@@ -0,0 +1,46 @@
+package repository_screening
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/entities"
+	"os"
+)
+
+// RuleSource gives the screening rules in force.
+type RuleSource interface {
+	Rules(ctx context.Context) ([]entities.ScreeningRule, error)
+	SaveRule(rule entities.ScreeningRule, ctx context.Context) (entities.ScreeningRule, error)
+}
+
+// StaticRuleSource is a set of rules fixed when the service starts. Its rules
+// cannot be changed through the API.
+type StaticRuleSource struct {
+	rules []entities.ScreeningRule
+}
+
+func NewStaticRuleSource(rules []entities.ScreeningRule) *StaticRuleSource {
+	return &StaticRuleSource{rules: rules}
+}
+
+// NewFileRuleSource loads a static rule set from a JSON file holding an array
+// of rules.
+func NewFileRuleSource(path string) (*StaticRuleSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []entities.ScreeningRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	return NewStaticRuleSource(rules), nil
+}
+
+func (source *StaticRuleSource) Rules(ctx context.Context) ([]entities.ScreeningRule, error) {
+	return source.rules, nil
+}
+
+func (source *StaticRuleSource) SaveRule(rule entities.ScreeningRule, ctx context.Context) (entities.ScreeningRule, error) {
+	return entities.ScreeningRule{}, ErrRulesReadOnly
+}
@@ -0,0 +1,217 @@
+package repository_screening
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ScreeningRepository interface {
+	KnownDevice(userID string, deviceID string, ctx context.Context) (bool, error)
+	RememberDevice(userID string, deviceID string, ctx context.Context) error
+	RecentOutgoing(walletID string, since time.Time, ctx context.Context) ([]entities.Transaction, error)
+	HasPaid(walletID string, counterpartyUserID string, ctx context.Context) (bool, error)
+	CreateReview(review entities.ScreeningReview, ctx context.Context) (entities.ScreeningReview, error)
+	GetReview(id string, ctx context.Context) (entities.ScreeningReview, error)
+	ListReviews(status string, limit int64, ctx context.Context) ([]entities.ScreeningReview, error)
+	UpdateReviewStatus(id string, from string, to string, analyst string, note string, ctx context.Context) (entities.ScreeningReview, error)
+}
+
+type MongoScreeningRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoScreeningRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoScreeningRepository {
+	return &MongoScreeningRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateIndexes keeps the review queue in order. The lookups of transactions
+// use the indexes of the transaction repository.
+func (repo *MongoScreeningRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("screening_reviews").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+// KnownDevice reports whether the user completed a transaction from the
+// device before.
+func (repo *MongoScreeningRepository) KnownDevice(userID string, deviceID string, ctx context.Context) (bool, error) {
+	coll := repo.db.Database("mywallet").Collection("user_devices")
+	count, err := coll.CountDocuments(ctx, bson.M{"_id": userID + ":" + deviceID}, options.Count().SetLimit(1))
+	if err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:KnownDevice ", "Error:", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RememberDevice records that the user completed a transaction from the
+// device.
+func (repo *MongoScreeningRepository) RememberDevice(userID string, deviceID string, ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("user_devices")
+	now := time.Now().UTC()
+	_, err := coll.UpdateOne(ctx,
+		bson.M{"_id": userID + ":" + deviceID},
+		bson.M{"$set": bson.M{"last_seen_at": now}, "$setOnInsert": bson.M{"user_id": userID, "device_id": deviceID, "created_at": now}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:RememberDevice ", "Error:", err)
+	}
+	return err
+}
+
+// RecentOutgoing returns the transfers sent from the wallet since a time,
+// newest first.
+func (repo *MongoScreeningRepository) RecentOutgoing(walletID string, since time.Time, ctx context.Context) ([]entities.Transaction, error) {
+	transactions := []entities.Transaction{}
+	coll := repo.db.Database("mywallet").Collection("transactions")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := coll.Find(ctx, bson.M{
+		"wallet_id":  walletID,
+		"type":       entities.TransactionTransferOut,
+		"created_at": bson.M{"$gte": since},
+	}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:RecentOutgoing ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &transactions); err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:RecentOutgoing ", "Error:", err)
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// HasPaid reports whether the wallet ever sent a transfer to the
+// counterparty.
+func (repo *MongoScreeningRepository) HasPaid(walletID string, counterpartyUserID string, ctx context.Context) (bool, error) {
+	coll := repo.db.Database("mywallet").Collection("transactions")
+	count, err := coll.CountDocuments(ctx, bson.M{
+		"wallet_id":            walletID,
+		"type":                 entities.TransactionTransferOut,
+		"counterparty_user_id": counterpartyUserID,
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:HasPaid ", "Error:", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Rules returns the rules stored in Mongo, so they can be changed without a
+// deploy.
+func (repo *MongoScreeningRepository) Rules(ctx context.Context) ([]entities.ScreeningRule, error) {
+	rules := []entities.ScreeningRule{}
+	coll := repo.db.Database("mywallet").Collection("screening_rules")
+	cursor, err := coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:Rules ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &rules); err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:Rules ", "Error:", err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SaveRule creates the rule or replaces the one with the same ID.
+func (repo *MongoScreeningRepository) SaveRule(rule entities.ScreeningRule, ctx context.Context) (entities.ScreeningRule, error) {
+	coll := repo.db.Database("mywallet").Collection("screening_rules")
+	_, err := coll.ReplaceOne(ctx, bson.M{"_id": rule.ID}, rule, options.Replace().SetUpsert(true))
+	if err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:SaveRule ", "Error:", err)
+		return entities.ScreeningRule{}, err
+	}
+	return rule, nil
+}
+
+func (repo *MongoScreeningRepository) CreateReview(review entities.ScreeningReview, ctx context.Context) (entities.ScreeningReview, error) {
+	coll := repo.db.Database("mywallet").Collection("screening_reviews")
+	result, err := coll.InsertOne(ctx, review)
+	if err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:CreateReview ", "Error:", err)
+		return entities.ScreeningReview{}, err
+	}
+	review.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return review, nil
+}
+
+func (repo *MongoScreeningRepository) GetReview(id string, ctx context.Context) (entities.ScreeningReview, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.ScreeningReview{}, ErrReviewNotFound
+	}
+	var review entities.ScreeningReview
+	coll := repo.db.Database("mywallet").Collection("screening_reviews")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&review)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return review, ErrReviewNotFound
+		}
+		repo.logger.Errorln("Layer:screening_repository ", "Method:GetReview ", "Error:", err)
+		return review, err
+	}
+	return review, nil
+}
+
+// ListReviews returns up to limit reviews in a status, oldest first.
+func (repo *MongoScreeningRepository) ListReviews(status string, limit int64, ctx context.Context) ([]entities.ScreeningReview, error) {
+	reviews := []entities.ScreeningReview{}
+	coll := repo.db.Database("mywallet").Collection("screening_reviews")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(limit)
+	cursor, err := coll.Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:ListReviews ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:ListReviews ", "Error:", err)
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// UpdateReviewStatus moves a review from one status to another. When two
+// analysts race, only the first one moves it; the other gets
+// ErrReviewStatusChanged.
+func (repo *MongoScreeningRepository) UpdateReviewStatus(id string, from string, to string, analyst string, note string, ctx context.Context) (entities.ScreeningReview, error) {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.ScreeningReview{}, ErrReviewNotFound
+	}
+	var review entities.ScreeningReview
+	coll := repo.db.Database("mywallet").Collection("screening_reviews")
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": idd, "status": from},
+		bson.M{"$set": bson.M{"status": to, "analyst": analyst, "note": note, "reviewed_at": time.Now().UTC()}},
+		opts,
+	).Decode(&review)
+	if err == mongo.ErrNoDocuments {
+		if _, err := repo.GetReview(id, ctx); err != nil {
+			return entities.ScreeningReview{}, err
+		}
+		return entities.ScreeningReview{}, ErrReviewStatusChanged
+	}
+	if err != nil {
+		repo.logger.Errorln("Layer:screening_repository ", "Method:UpdateReviewStatus ", "Error:", err)
+		return entities.ScreeningReview{}, err
+	}
+	return review, nil
+}
@@ -0,0 +1,5 @@
+package repository_split
+
+import "errors"
+
+var ErrSplitNotFound = errors.New("Error not found split")
@@ -0,0 +1,109 @@
+package repository_split
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxListedSplits = 100
+
+type SplitRepository interface {
+	CreateSplit(split entities.Split, ctx context.Context) (entities.Split, error)
+	GetSplit(id string, ctx context.Context) (entities.Split, error)
+	ListSplits(userID string, ctx context.Context) ([]entities.Split, error)
+	SaveSplit(split entities.Split, ctx context.Context) error
+}
+
+type MongoSplitRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoSplitRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoSplitRepository {
+	return &MongoSplitRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (repo *MongoSplitRepository) CreateIndexes(ctx context.Context) error {
+	_, err := repo.db.Database("mywallet").Collection("splits").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "participants.user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		repo.logger.Errorln("Layer:split_repository ", "Method:CreateIndexes ", "Error:", err)
+	}
+	return err
+}
+
+func (repo *MongoSplitRepository) CreateSplit(split entities.Split, ctx context.Context) (entities.Split, error) {
+	coll := repo.db.Database("mywallet").Collection("splits")
+	result, err := coll.InsertOne(ctx, split)
+	if err != nil {
+		repo.logger.Errorln("Layer:split_repository ", "Method:CreateSplit ", "Error:", err)
+		return entities.Split{}, err
+	}
+	split.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return split, nil
+}
+
+func (repo *MongoSplitRepository) GetSplit(id string, ctx context.Context) (entities.Split, error) {
+	var split entities.Split
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return split, ErrSplitNotFound
+	}
+	coll := repo.db.Database("mywallet").Collection("splits")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&split)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return split, ErrSplitNotFound
+		}
+		repo.logger.Errorln("Layer:split_repository ", "Method:GetSplit ", "Error:", err)
+		return split, err
+	}
+	return split, nil
+}
+
+// ListSplits returns the newest splits the user owns or takes part in.
+func (repo *MongoSplitRepository) ListSplits(userID string, ctx context.Context) ([]entities.Split, error) {
+	splits := []entities.Split{}
+	filter := bson.M{"$or": bson.A{bson.M{"owner_id": userID}, bson.M{"participants.user_id": userID}}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(maxListedSplits)
+	coll := repo.db.Database("mywallet").Collection("splits")
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:split_repository ", "Method:ListSplits ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &splits); err != nil {
+		repo.logger.Errorln("Layer:split_repository ", "Method:ListSplits ", "Error:", err)
+		return nil, err
+	}
+	return splits, nil
+}
+
+func (repo *MongoSplitRepository) SaveSplit(split entities.Split, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(split.ID)
+	if err != nil {
+		return ErrSplitNotFound
+	}
+	split.ID = ""
+	coll := repo.db.Database("mywallet").Collection("splits")
+	result, err := coll.ReplaceOne(ctx, bson.M{"_id": idd}, split)
+	if err != nil {
+		repo.logger.Errorln("Layer:split_repository ", "Method:SaveSplit ", "Error:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrSplitNotFound
+	}
+	return nil
+}
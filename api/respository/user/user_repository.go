@@ -3,6 +3,7 @@ package repository_user
 import (
 	"context"
 	"my_wallet/api/entities"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
@@ -15,10 +16,15 @@ type UserRepository interface {
 	CreateUser(user entities.User, ctx context.Context) (entities.User, error)
 	GetUser(id string, ctx context.Context) (entities.User, error)
 	GetUserByEmail(email string, ctx context.Context) (entities.User, error)
+	GetUserByPhone(phone int, ctx context.Context) (entities.User, error)
+	GetUserByDNI(dni int, ctx context.Context) (entities.User, error)
 	DeleteUser(id string, ctx context.Context) error
 	UpdateUser(userUpr entities.User, ctx context.Context) (entities.User, error)
 	SoftDeleteUser(id string, ctx context.Context) error
 	UpdateUserToken(userUpr entities.User, ctx context.Context) (entities.User, error)
+	SetKYCLevel(id string, level string, ctx context.Context) error
+	SetStatus(id string, status string, ctx context.Context) error
+	ListUsers(afterID string, limit int64, ctx context.Context) ([]entities.User, error)
 }
 
 type MongoUserRepositoy struct {
@@ -89,6 +95,42 @@ func (repo *MongoUserRepositoy) GetUserByEmail(email string, ctx context.Context
 	return user, nil
 }
 
+func (repo *MongoUserRepositoy) GetUserByPhone(phone int, ctx context.Context) (entities.User, error) {
+	var user entities.User
+	filter := bson.D{{Key: "phone", Value: phone}}
+	coll := repo.db.Database("mywallet").Collection("users")
+
+	err := coll.FindOne(ctx, filter).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return user, ErrUserNotfound
+		}
+		return user, err
+	}
+	if user.Enabled != true {
+		return entities.User{}, ErrDisbledUser
+	}
+	return user, nil
+}
+
+func (repo *MongoUserRepositoy) GetUserByDNI(dni int, ctx context.Context) (entities.User, error) {
+	var user entities.User
+	filter := bson.D{{Key: "dni", Value: dni}}
+	coll := repo.db.Database("mywallet").Collection("users")
+
+	err := coll.FindOne(ctx, filter).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return user, ErrUserNotfound
+		}
+		return user, err
+	}
+	if user.Enabled != true {
+		return entities.User{}, ErrDisbledUser
+	}
+	return user, nil
+}
+
 func (repo *MongoUserRepositoy) UpdateUser(userUpr entities.User, ctx context.Context) (entities.User, error) {
 	ide := string(userUpr.ID)
 	idd, err := primitive.ObjectIDFromHex(ide)
@@ -136,6 +178,77 @@ func (repo *MongoUserRepositoy) UpdateUserToken(userUpr entities.User, ctx conte
 	return userUpr, nil
 }
 
+// SetKYCLevel records the KYC level the user was verified at.
+func (repo *MongoUserRepositoy) SetKYCLevel(id string, level string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrUserNotfound
+	}
+
+	coll := repo.db.Database("mywallet").Collection("users")
+	res, err := coll.UpdateOne(ctx, bson.M{"_id": idd}, bson.M{"$set": bson.M{"kyc_level": level, "updated_at": time.Now().UTC()}})
+	if err != nil {
+		repo.logger.Errorln("Layer:user_repository ", "Method:SetKYCLevel ", "Error:", err)
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrUserNotfound
+	}
+	repo.logger.Infoln("Layer:user_repository ", "Method:SetKYCLevel ", "User:", id, "Level:", level)
+	return nil
+}
+
+// SetStatus records the compliance status of the user. Blocked users are
+// disabled as well.
+func (repo *MongoUserRepositoy) SetStatus(id string, status string, ctx context.Context) error {
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrUserNotfound
+	}
+
+	set := bson.M{"status": status, "updated_at": time.Now().UTC()}
+	if status == entities.UserBlocked {
+		set["enabled"] = false
+	}
+	coll := repo.db.Database("mywallet").Collection("users")
+	res, err := coll.UpdateOne(ctx, bson.M{"_id": idd}, bson.M{"$set": set})
+	if err != nil {
+		repo.logger.Errorln("Layer:user_repository ", "Method:SetStatus ", "Error:", err)
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrUserNotfound
+	}
+	repo.logger.Infoln("Layer:user_repository ", "Method:SetStatus ", "User:", id, "Status:", status)
+	return nil
+}
+
+// ListUsers returns up to limit enabled users after the one with afterID, in
+// ID order, to walk all of them page by page.
+func (repo *MongoUserRepositoy) ListUsers(afterID string, limit int64, ctx context.Context) ([]entities.User, error) {
+	users := []entities.User{}
+	filter := bson.M{"enabled": true}
+	if afterID != "" {
+		idd, err := primitive.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, ErrUserNotfound
+		}
+		filter["_id"] = bson.M{"$gt": idd}
+	}
+	coll := repo.db.Database("mywallet").Collection("users")
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		repo.logger.Errorln("Layer:user_repository ", "Method:ListUsers ", "Error:", err)
+		return nil, err
+	}
+	if err := cursor.All(ctx, &users); err != nil {
+		repo.logger.Errorln("Layer:user_repository ", "Method:ListUsers ", "Error:", err)
+		return nil, err
+	}
+	return users, nil
+}
+
 func (repo *MongoUserRepositoy) SoftDeleteUser(id string, ctx context.Context) error {
 	idd, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -0,0 +1,5 @@
+package repository_wallet
+
+import "errors"
+
+var ErrWalletNotFound = errors.New("Error not found wallet")
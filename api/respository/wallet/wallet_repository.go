@@ -0,0 +1,94 @@
+package repository_wallet
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WalletRepository interface {
+	GetWallet(id string, ctx context.Context) (entities.Wallet, error)
+	GetOrCreateWallet(userID string, ctx context.Context) (entities.Wallet, error)
+}
+
+type MongoWalletRepository struct {
+	db     *mongo.Client
+	logger logrus.FieldLogger
+}
+
+func NewMongoWalletRepository(db *mongo.Client, logger logrus.FieldLogger) *MongoWalletRepository {
+	return &MongoWalletRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (repo *MongoWalletRepository) GetWallet(id string, ctx context.Context) (entities.Wallet, error) {
+	var wallet entities.Wallet
+	idd, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return wallet, ErrWalletNotFound
+	}
+
+	coll := repo.db.Database("mywallet").Collection("wallets")
+	err = coll.FindOne(ctx, bson.M{"_id": idd}).Decode(&wallet)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return wallet, ErrWalletNotFound
+		}
+		repo.logger.Errorln("Layer:wallet_repository ", "Method:GetWallet ", "Error:", err)
+		return wallet, err
+	}
+	return wallet, nil
+}
+
+// MigrateBalances moves the single balance of wallets created before multi
+// currency support into the balances of their default currency.
+func (repo *MongoWalletRepository) MigrateBalances(ctx context.Context) error {
+	coll := repo.db.Database("mywallet").Collection("wallets")
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"balances": bson.M{"$arrayToObject": bson.A{bson.A{bson.M{"k": "$currency", "v": "$balance"}}}}}}},
+		{{Key: "$unset", Value: "balance"}},
+	}
+	result, err := coll.UpdateMany(ctx, bson.M{"balance": bson.M{"$exists": true}}, pipeline)
+	if err != nil {
+		repo.logger.Errorln("Layer:wallet_repository ", "Method:MigrateBalances ", "Error:", err)
+		return err
+	}
+	if result.ModifiedCount > 0 {
+		repo.logger.Infoln("Layer:wallet_repository ", "Method:MigrateBalances ", "Wallets:", result.ModifiedCount)
+	}
+	return nil
+}
+
+// GetOrCreateWallet returns the wallet of the user, creating an empty one the
+// first time the user needs it. The upsert keeps a single wallet per user even
+// when two requests race to create it.
+func (repo *MongoWalletRepository) GetOrCreateWallet(userID string, ctx context.Context) (entities.Wallet, error) {
+	var wallet entities.Wallet
+	now := time.Now().UTC()
+	coll := repo.db.Database("mywallet").Collection("wallets")
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"user_id":    userID,
+			"currency":   entities.DefaultCurrency,
+			"balances":   bson.M{entities.DefaultCurrency: int64(0)},
+			"created_at": now,
+			"updated_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	err := coll.FindOneAndUpdate(ctx, bson.M{"user_id": userID}, update, opts).Decode(&wallet)
+	if err != nil {
+		repo.logger.Errorln("Layer:wallet_repository ", "Method:GetOrCreateWallet ", "Error:", err)
+		return wallet, err
+	}
+	return wallet, nil
+}
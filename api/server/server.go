@@ -4,26 +4,82 @@ import (
 	"context"
 	_ "my_wallet/api/cmd/docs"
 	"my_wallet/api/endpoints"
+	"my_wallet/api/entities"
 
+	"fmt"
+	repository_beneficiary "my_wallet/api/respository/beneficiary"
+	repository_blob "my_wallet/api/respository/blob"
+	repository_budget "my_wallet/api/respository/budget"
+	repository_card "my_wallet/api/respository/card"
+	repository_category "my_wallet/api/respository/category"
+	repository_dispute "my_wallet/api/respository/dispute"
+	repository_fee "my_wallet/api/respository/fee"
+	repository_fx "my_wallet/api/respository/fx"
 	infraestructure_repository "my_wallet/api/respository/healtcheck"
+	repository_idempotency "my_wallet/api/respository/idempotency"
+	repository_insights "my_wallet/api/respository/insights"
+	repository_interest "my_wallet/api/respository/interest"
+	repository_kyc "my_wallet/api/respository/kyc"
+	repository_lease "my_wallet/api/respository/lease"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_limit "my_wallet/api/respository/limit"
+	repository_member "my_wallet/api/respository/member"
+	repository_merchant "my_wallet/api/respository/merchant"
+	repository_notification "my_wallet/api/respository/notification"
+	repository_payment_request "my_wallet/api/respository/payment_request"
+	repository_pocket "my_wallet/api/respository/pocket"
+	repository_reconciliation "my_wallet/api/respository/reconciliation"
+	repository_restriction "my_wallet/api/respository/restriction"
+	repository_sanctions "my_wallet/api/respository/sanctions"
+	repository_schedule "my_wallet/api/respository/schedule"
+	repository_screening "my_wallet/api/respository/screening"
+	repository_split "my_wallet/api/respository/split"
 	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
 	"my_wallet/api/services"
 	infraestructure_services "my_wallet/api/services/healtcheck"
 	transports "my_wallet/api/transports/http"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	defaultFXRatesFile          = "data/fx/rates.json"
+	defaultInterestRatesFile    = "data/interest/rates.json"
+	defaultLimitsFile           = "data/limits/limits.json"
+	defaultKYCBlobDir           = "data/blobs"
+	defaultScreeningFile        = "data/screening/rules.json"
+	defaultSanctionsSDNFile     = "data/sanctions/sdn.csv"
+	defaultSanctionsALTFile     = "data/sanctions/alt.csv"
+	defaultSanctionsScore       = 0.9
+	defaultFXSpreadBps          = 100
+	defaultFXQuoteTTLSeconds    = 30
+	defaultSchedulerSeconds     = 60
+	defaultRequestTTLHours      = 168
+	defaultCoolingOffHours      = 24
+	defaultCoolingOffLimits     = "COP=20000000,USD=5000,EUR=5000"
+	defaultQRCodeTTLMinutes     = 15
+	defaultReconciliationAmount = 0
+	defaultReconciliationDays   = 1
+	defaultCardBIN              = "489537"
+)
+
 type Server struct {
-	dbMongo  *mongo.Client
-	httpMux  *http.ServeMux
-	httpAddr string
-	logger   logrus.FieldLogger
+	dbMongo   *mongo.Client
+	httpMux   *http.ServeMux
+	httpAddr  string
+	logger    logrus.FieldLogger
+	scheduler *services.Scheduler
+	ctx       context.Context
 }
 
 func New(logger logrus.FieldLogger, httpAddr, dburl string, ctx context.Context) (*Server, error) {
@@ -32,8 +88,189 @@ func New(logger logrus.FieldLogger, httpAddr, dburl string, ctx context.Context)
 	healtCheckRepository := infraestructure_repository.NewMongoUserREpository(db, logger)
 	healtCheckService := infraestructure_services.NewHealtcheckService(ctx, healtCheckRepository, logger)
 	userRepository := repository_user.NewMongoUserREpository(db, logger)
-	userService := services.NewUserService(userRepository, logger, ctx)
-	userEnpoints := endpoints.MakeServerEndpoints(userService, healtCheckService, logger)
+	sanctionsList, err := repository_sanctions.NewFileSanctionsList(configString("SANCTIONS_SDN_FILE", defaultSanctionsSDNFile), configString("SANCTIONS_ALT_FILE", defaultSanctionsALTFile))
+	if err != nil {
+		return nil, err
+	}
+	sanctionsRepository := repository_sanctions.NewMongoSanctionsRepository(db, logger)
+	if err := sanctionsRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	sanctionsService := services.NewSanctionsService(userRepository, sanctionsRepository, sanctionsList, configFloat("SANCTIONS_MATCH_THRESHOLD", defaultSanctionsScore), logger, ctx)
+	userService := services.NewUserService(userRepository, sanctionsService, logger, ctx)
+	walletRepository := repository_wallet.NewMongoWalletRepository(db, logger)
+	if err := walletRepository.MigrateBalances(ctx); err != nil {
+		return nil, err
+	}
+	ledgerRepository := repository_ledger.NewMongoLedgerRepository(db, logger)
+	if err := ledgerRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	transactionRepository := repository_ledger.NewMongoTransactionRepository(db, logger)
+	if err := transactionRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	categoryRepository := repository_category.NewMongoCategoryRepository(db, logger)
+	if err := categoryRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	notificationRepository := repository_notification.NewMongoNotificationRepository(db, logger)
+	if err := notificationRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	notificationService := services.NewNotificationService(userRepository, notificationRepository, logger, ctx)
+	memberRepository := repository_member.NewMongoMemberRepository(db, logger)
+	if err := memberRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	memberService := services.NewMemberService(userRepository, walletRepository, memberRepository, notificationService, logger, ctx)
+	categoryService := services.NewCategoryService(userRepository, walletRepository, transactionRepository, categoryRepository, memberService, logger, ctx)
+	restrictionRepository := repository_restriction.NewMongoRestrictionRepository(db, logger)
+	if err := restrictionRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	restrictionService := services.NewRestrictionService(userRepository, walletRepository, restrictionRepository, notificationService, logger, ctx)
+	budgetRepository := repository_budget.NewMongoBudgetRepository(db, logger)
+	if err := budgetRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	budgetService := services.NewBudgetService(userRepository, walletRepository, transactionRepository, categoryRepository, budgetRepository, notificationService, memberService, logger, ctx)
+	ledger := services.NewObservedLedger(services.NewCategorizedLedger(ledgerRepository, categoryService), budgetService)
+	feeRuleRepository := repository_fee.NewMongoFeeRuleRepository(db, logger)
+	if err := feeRuleRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	feeService := services.NewFeeService(userRepository, feeRuleRepository, logger, ctx)
+	pocketRepository := repository_pocket.NewMongoPocketRepository(db, logger)
+	if err := pocketRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	pocketService := services.NewPocketService(userRepository, walletRepository, ledger, pocketRepository, restrictionService, memberService, logger, ctx)
+	kycRepository := repository_kyc.NewMongoKYCRepository(db, logger)
+	if err := kycRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	var kycProvider services.KYCProvider
+	if configString("KYC_PROVIDER", "manual") == "simulated" {
+		kycProvider = services.NewSimulatedKYCProvider()
+	}
+	kycBlobs := repository_blob.NewFileBlobStore(configString("KYC_BLOB_DIR", defaultKYCBlobDir), logger)
+	kycService := services.NewKYCService(userRepository, kycRepository, kycBlobs, kycProvider, logger, ctx)
+	limitPolicy, err := repository_limit.NewFileLimitPolicy(configString("LIMITS_FILE", defaultLimitsFile))
+	if err != nil {
+		return nil, err
+	}
+	limitRepository := repository_limit.NewMongoLimitRepository(db, logger)
+	if err := limitRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	limitService := services.NewLimitService(userRepository, limitRepository, limitPolicy, logger, ctx)
+	screeningRepository := repository_screening.NewMongoScreeningRepository(db, logger)
+	if err := screeningRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	var screeningRules repository_screening.RuleSource = screeningRepository
+	if configString("SCREENING_RULES_SOURCE", "file") == "file" {
+		screeningRules, err = repository_screening.NewFileRuleSource(configString("SCREENING_RULES_FILE", defaultScreeningFile))
+		if err != nil {
+			return nil, err
+		}
+	}
+	screener := services.NewScreeningEngine(screeningRepository, screeningRules, logger)
+	beneficiaryRepository := repository_beneficiary.NewMongoBeneficiaryRepository(db, logger)
+	if err := beneficiaryRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	coolingOff := time.Duration(configInt("BENEFICIARY_COOLING_OFF_HOURS", defaultCoolingOffHours)) * time.Hour
+	coolingOffLimits, err := configAmounts("BENEFICIARY_COOLING_OFF_LIMITS", defaultCoolingOffLimits)
+	if err != nil {
+		return nil, err
+	}
+	beneficiaryService := services.NewBeneficiaryService(userRepository, beneficiaryRepository, coolingOff, coolingOffLimits, logger, ctx)
+	transferService := services.NewTransferService(userRepository, walletRepository, ledger, feeService, screener, limitService, pocketService, beneficiaryService, restrictionService, memberService, logger, ctx)
+	withdrawalService := services.NewWithdrawalService(userRepository, walletRepository, ledger, feeService, screener, limitService, beneficiaryService, restrictionService, logger, ctx)
+	idempotencyRepository := repository_idempotency.NewMongoIdempotencyRepository(db, logger)
+	if err := idempotencyRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	idempotencyService := services.NewIdempotencyService(idempotencyRepository, logger, ctx)
+	walletService := services.NewWalletService(userRepository, walletRepository, ledger, transactionRepository, memberService, logger, ctx)
+	insightsRepository := repository_insights.NewMongoInsightsRepository(db, logger)
+	if err := insightsRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	insightsService := services.NewInsightsService(userRepository, walletRepository, insightsRepository, configString("INSIGHTS_ROLLUPS", "false") == "true", memberService, logger, ctx)
+	rateProvider, err := repository_fx.NewFileRateProvider(configString("FX_RATES_FILE", defaultFXRatesFile))
+	if err != nil {
+		return nil, err
+	}
+	quoteRepository := repository_fx.NewMongoQuoteRepository(db, logger)
+	if err := quoteRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	spreadBps := int64(configInt("FX_SPREAD_BPS", defaultFXSpreadBps))
+	quoteTTL := time.Duration(configInt("FX_QUOTE_TTL_SECONDS", defaultFXQuoteTTLSeconds)) * time.Second
+	fxService := services.NewFXService(userRepository, walletRepository, ledger, quoteRepository, rateProvider, feeService, spreadBps, quoteTTL, limitService, restrictionService, memberService, logger, ctx)
+	scheduleRepository := repository_schedule.NewMongoScheduleRepository(db, logger)
+	if err := scheduleRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	scheduleService := services.NewScheduleService(userRepository, scheduleRepository, transferService, logger, ctx)
+	interestRepository := repository_interest.NewMongoInterestRepository(db, logger)
+	if err := interestRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	interestRates, err := repository_interest.NewFileRatePolicy(configString("INTEREST_RATES_FILE", defaultInterestRatesFile))
+	if err != nil {
+		return nil, err
+	}
+	interestService := services.NewInterestService(userRepository, walletRepository, pocketRepository, ledger, interestRepository, interestRates, restrictionService, memberService, logger, ctx)
+	leaseRepository := repository_lease.NewMongoLeaseRepository(db, logger)
+	schedulerInterval := time.Duration(configInt("SCHEDULER_INTERVAL_SECONDS", defaultSchedulerSeconds)) * time.Second
+	scheduler := services.NewScheduler(leaseRepository, scheduleService, pocketService, insightsService, sanctionsService, interestService, replicaName(), schedulerInterval, logger)
+	paymentRequestRepository := repository_payment_request.NewMongoPaymentRequestRepository(db, logger)
+	if err := paymentRequestRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	requestTTL := time.Duration(configInt("PAYMENT_REQUEST_TTL_HOURS", defaultRequestTTLHours)) * time.Hour
+	paymentRequestService := services.NewPaymentRequestService(userRepository, paymentRequestRepository, transferService, requestTTL, logger, ctx)
+	screeningService := services.NewScreeningService(userRepository, screeningRepository, screeningRules, transferService, withdrawalService, map[string]services.HeldTransferSource{
+		entities.TransferSourceSchedule:       scheduleService,
+		entities.TransferSourcePaymentRequest: paymentRequestService,
+	}, logger, ctx)
+	splitRepository := repository_split.NewMongoSplitRepository(db, logger)
+	if err := splitRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	splitService := services.NewSplitService(userRepository, splitRepository, paymentRequestRepository, paymentRequestService, logger, ctx)
+	disputeRepository := repository_dispute.NewMongoDisputeRepository(db, logger)
+	if err := disputeRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	// Dispute evidence shares the blob store of the KYC documents, under its
+	// own prefix.
+	disputeService := services.NewDisputeService(userRepository, transactionRepository, ledger, disputeRepository, kycBlobs, notificationService, restrictionService, logger, ctx)
+	merchantRepository := repository_merchant.NewMongoMerchantRepository(db, logger)
+	if err := merchantRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	qrCodeTTL := time.Duration(configInt("MERCHANT_QR_TTL_MINUTES", defaultQRCodeTTLMinutes)) * time.Minute
+	merchantService := services.NewMerchantService(userRepository, walletRepository, ledger, merchantRepository, feeService, limitService, qrCodeTTL, restrictionService, logger, ctx)
+	reconciliationRepository := repository_reconciliation.NewMongoReconciliationRepository(db, logger)
+	if err := reconciliationRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	reconciliationService := services.NewReconciliationService(userRepository, reconciliationRepository, logger, ctx)
+	cardRepository := repository_card.NewMongoCardRepository(db, logger)
+	if err := cardRepository.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+	// The token key and the webhook secret have no default: without a webhook
+	// secret every network message is rejected.
+	cardTokenKey := []byte(configString("CARD_TOKEN_KEY", ""))
+	cardWebhookSecret := []byte(configString("CARD_WEBHOOK_SECRET", ""))
+	cardService := services.NewCardService(userRepository, walletRepository, ledger, cardRepository, limitService, restrictionService, configString("CARD_BIN", defaultCardBIN), cardTokenKey, cardWebhookSecret, logger, ctx)
+	userEnpoints := endpoints.MakeServerEndpoints(userService, healtCheckService, transferService, idempotencyService, walletService, fxService, feeService, scheduleService, paymentRequestService, splitService, pocketService, categoryService, budgetService, notificationService, insightsService, limitService, kycService, screeningService, sanctionsService, disputeService, beneficiaryService, withdrawalService, merchantService, restrictionService, reconciliationService, interestService, cardService, memberService, logger)
 	httpHandler := transports.NewHTTPHandler(userEnpoints, logger)
 
 	httpMux := http.NewServeMux()
@@ -44,14 +281,18 @@ func New(logger logrus.FieldLogger, httpAddr, dburl string, ctx context.Context)
 	})
 
 	return &Server{
-		dbMongo:  db,
-		httpMux:  httpMux,
-		httpAddr: httpAddr,
+		dbMongo:   db,
+		httpMux:   httpMux,
+		httpAddr:  httpAddr,
+		logger:    logger,
+		scheduler: scheduler,
+		ctx:       ctx,
 	}, nil
 }
 
 func (s *Server) Start() error {
 
+	go s.scheduler.Run(s.ctx)
 	logrus.Infoln("Layel:Server ", " Method: Start", "Port:", s.httpAddr)
 	if err := http.ListenAndServe(s.httpAddr, s.httpMux); err != nil {
 		logrus.Fatalf("HTTP server failed: %v", err)
@@ -75,3 +316,67 @@ func GetMongoDB(ctx context.Context, dburl string) *mongo.Client {
 
 	return client
 }
+
+// ReconciliationTolerance returns how far apart settled movements and
+// transactions may be and still match in the end-of-day reconciliation, from
+// the loaded configuration.
+func ReconciliationTolerance() entities.ReconciliationTolerance {
+	return entities.ReconciliationTolerance{
+		Amount: int64(configInt("RECONCILIATION_AMOUNT_TOLERANCE", defaultReconciliationAmount)),
+		Days:   configInt("RECONCILIATION_DATE_TOLERANCE_DAYS", defaultReconciliationDays),
+	}
+}
+
+// configString reads key from the loaded configuration, falling back to def
+// when it is not set.
+func configString(key string, def string) string {
+	if value := viper.GetString(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// configInt reads an integer key from the loaded configuration, falling back
+// to def when it is missing or malformed.
+func configInt(key string, def int) int {
+	value, err := strconv.Atoi(viper.GetString(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// configFloat reads a decimal key from the loaded configuration, falling back
+// to def when it is missing or malformed.
+func configFloat(key string, def float64) float64 {
+	value, err := strconv.ParseFloat(viper.GetString(key), 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// configAmounts reads a list of amounts per currency, such as
+// "COP=20000000,USD=5000", from the loaded configuration, falling back to def
+// when it is missing.
+func configAmounts(key string, def string) (map[string]int64, error) {
+	amounts := map[string]int64{}
+	for _, pair := range strings.Split(configString(key, def), ",") {
+		currency, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		amount, err := strconv.ParseInt(value, 10, 64)
+		if !ok || err != nil || !entities.ValidCurrency(currency) || amount < 0 {
+			return nil, fmt.Errorf("%s: invalid amount %q", key, pair)
+		}
+		amounts[currency] = amount
+	}
+	return amounts, nil
+}
+
+// replicaName identifies this process as a lease holder.
+func replicaName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
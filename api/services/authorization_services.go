@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_user "my_wallet/api/respository/user"
+)
+
+// requireAdmin loads the authenticated user and fails unless it is an
+// administrator.
+func requireAdmin(ctx context.Context, users repository_user.UserRepository, email string) (entities.User, error) {
+	user, err := users.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.User{}, err
+	}
+	if user.Role != entities.RoleAdmin {
+		return entities.User{}, ErrAdminRequired
+	}
+	return user, nil
+}
+
+// requireStaff loads the authenticated user and fails unless it is support
+// staff or an administrator.
+func requireStaff(ctx context.Context, users repository_user.UserRepository, email string) (entities.User, error) {
+	user, err := users.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.User{}, err
+	}
+	if user.Role != entities.RoleSupport && user.Role != entities.RoleAdmin {
+		return entities.User{}, ErrStaffRequired
+	}
+	return user, nil
+}
+
+// requireKYC fails unless the user is verified at the level the feature
+// needs.
+func requireKYC(user entities.User, feature string) error {
+	if level, ok := entities.KYCFeatureLevels[feature]; ok && !entities.KYCAtLeast(user.KYC(), level) {
+		return ErrKYCLevelRequired
+	}
+	return nil
+}
+
+// requireActive fails while the user is held for a compliance review.
+func requireActive(user entities.User) error {
+	if !user.Active() {
+		return ErrUserUnderReview
+	}
+	return nil
+}
+
+// requireAnalyst loads the authenticated user and fails unless it is a fraud
+// or compliance analyst or an administrator.
+func requireAnalyst(ctx context.Context, users repository_user.UserRepository, email string) (entities.User, error) {
+	user, err := users.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.User{}, err
+	}
+	if user.Role != entities.RoleAnalyst && user.Role != entities.RoleAdmin {
+		return entities.User{}, ErrAnalystRequired
+	}
+	return user, nil
+}
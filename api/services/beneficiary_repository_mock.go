@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type beneficiaryRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *beneficiaryRepositoryMock) CreateBeneficiary(beneficiary entities.Beneficiary, ctx context.Context) (entities.Beneficiary, error) {
+	r := m.Called(ctx, beneficiary)
+	if created, ok := r.Get(0).(func(entities.Beneficiary) entities.Beneficiary); ok {
+		return created(beneficiary), r.Error(1)
+	}
+	return r.Get(0).(entities.Beneficiary), r.Error(1)
+}
+
+func (m *beneficiaryRepositoryMock) GetBeneficiary(id string, ctx context.Context) (entities.Beneficiary, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.Beneficiary), r.Error(1)
+}
+
+func (m *beneficiaryRepositoryMock) ListBeneficiaries(userID string, ctx context.Context) ([]entities.Beneficiary, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).([]entities.Beneficiary), r.Error(1)
+}
+
+func (m *beneficiaryRepositoryMock) UpdateBeneficiary(id string, userID string, nickname string, favorite bool, ctx context.Context) (entities.Beneficiary, error) {
+	r := m.Called(ctx, id, userID, nickname, favorite)
+	return r.Get(0).(entities.Beneficiary), r.Error(1)
+}
+
+func (m *beneficiaryRepositoryMock) DeleteBeneficiary(id string, userID string, ctx context.Context) error {
+	r := m.Called(ctx, id, userID)
+	return r.Error(0)
+}
+
+func (m *beneficiaryRepositoryMock) TouchBeneficiary(id string, at time.Time, ctx context.Context) error {
+	r := m.Called(ctx, id, at)
+	return r.Error(0)
+}
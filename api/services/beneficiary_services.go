@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_beneficiary "my_wallet/api/respository/beneficiary"
+	repository_user "my_wallet/api/respository/user"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxNicknameLength   = 60
+	maxHolderNameLength = 100
+)
+
+// Beneficiaries resolves the saved beneficiary money is sent to.
+type Beneficiaries interface {
+	// Beneficiary returns a beneficiary of the user of the given type.
+	Beneficiary(ctx context.Context, user entities.User, id string, beneficiaryType string) (entities.Beneficiary, error)
+	// CheckCoolingOff fails if the beneficiary cannot receive amount in
+	// currency yet.
+	CheckCoolingOff(beneficiary entities.Beneficiary, currency string, amount int64) error
+	// Used records that money was sent to the beneficiary.
+	Used(ctx context.Context, beneficiary entities.Beneficiary)
+}
+
+type BeneficiaryService interface {
+	Beneficiaries
+	CreateBeneficiary(ctx context.Context, email string, beneficiary entities.NewBeneficiary) (entities.Beneficiary, error)
+	ListBeneficiaries(ctx context.Context, email string) ([]entities.Beneficiary, error)
+	UpdateBeneficiary(ctx context.Context, email string, id string, nickname string, favorite bool) (entities.Beneficiary, error)
+	DeleteBeneficiary(ctx context.Context, email string, id string) error
+}
+
+type beneficiaryService struct {
+	ctx                   context.Context
+	userRepository        repository_user.UserRepository
+	beneficiaryRepository repository_beneficiary.BeneficiaryRepository
+	coolingOff            time.Duration
+	coolingOffLimits      map[string]int64
+	logger                logrus.FieldLogger
+}
+
+// NewBeneficiaryService creates the beneficiary service. For coolingOff after
+// a beneficiary is saved, it cannot receive more than the limit of the
+// currency at once; currencies without a limit are not restricted.
+func NewBeneficiaryService(userRepo repository_user.UserRepository, beneficiaryRepo repository_beneficiary.BeneficiaryRepository, coolingOff time.Duration, coolingOffLimits map[string]int64, logger logrus.FieldLogger, ctx context.Context) *beneficiaryService {
+	return &beneficiaryService{
+		ctx:                   ctx,
+		userRepository:        userRepo,
+		beneficiaryRepository: beneficiaryRepo,
+		coolingOff:            coolingOff,
+		coolingOffLimits:      coolingOffLimits,
+		logger:                logger,
+	}
+}
+
+// CreateBeneficiary saves a wallet user or a bank account as a beneficiary
+// of the authenticated user. Wallet users must exist and be enabled. The
+// nickname defaults to the name of the recipient or of the account holder.
+func (s *beneficiaryService) CreateBeneficiary(ctx context.Context, email string, input entities.NewBeneficiary) (entities.Beneficiary, error) {
+	if utf8.RuneCountInString(input.Nickname) > maxNicknameLength {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: CreateBeneficiary", "Error:", ErrInvalidBeneficiary)
+		return entities.Beneficiary{}, ErrInvalidBeneficiary
+	}
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: CreateBeneficiary", "Error:", err)
+		return entities.Beneficiary{}, err
+	}
+
+	now := time.Now().UTC()
+	beneficiary := entities.Beneficiary{
+		UserID:           user.ID,
+		Type:             input.Type,
+		Nickname:         strings.TrimSpace(input.Nickname),
+		Favorite:         input.Favorite,
+		CoolingOff_until: now.Add(s.coolingOff),
+		Created_at:       now,
+		Update_at:        now,
+	}
+	switch input.Type {
+	case entities.BeneficiaryWallet:
+		if input.Bank != nil {
+			s.logger.Errorln("Layer: beneficiary_services", "Method: CreateBeneficiary", "Error:", ErrInvalidBeneficiary)
+			return entities.Beneficiary{}, ErrInvalidBeneficiary
+		}
+		recipient, err := findRecipient(ctx, s.userRepository, entities.Transfer{
+			RecipientEmail: input.RecipientEmail,
+			RecipientPhone: input.RecipientPhone,
+			RecipientDNI:   input.RecipientDNI,
+		})
+		if err != nil {
+			s.logger.Errorln("Layer: beneficiary_services", "Method: CreateBeneficiary", "Error:", err)
+			return entities.Beneficiary{}, err
+		}
+		if recipient.ID == user.ID {
+			s.logger.Errorln("Layer: beneficiary_services", "Method: CreateBeneficiary", "Error:", ErrSelfTransfer)
+			return entities.Beneficiary{}, ErrSelfTransfer
+		}
+		beneficiary.RecipientUserID = recipient.ID
+		beneficiary.RecipientName = recipient.Name
+		beneficiary.Target = "wallet:" + recipient.ID
+	case entities.BeneficiaryBank:
+		if input.Bank == nil || input.RecipientEmail != "" || input.RecipientPhone != 0 || input.RecipientDNI != 0 || !validBankAccount(*input.Bank) {
+			s.logger.Errorln("Layer: beneficiary_services", "Method: CreateBeneficiary", "Error:", ErrInvalidBeneficiary)
+			return entities.Beneficiary{}, ErrInvalidBeneficiary
+		}
+		bank := *input.Bank
+		bank.BankName = entities.Banks[bank.BankCode]
+		bank.HolderName = strings.TrimSpace(bank.HolderName)
+		beneficiary.Bank = &bank
+		beneficiary.RecipientName = bank.HolderName
+		beneficiary.Target = "bank:" + bank.BankCode + ":" + bank.AccountNumber
+	default:
+		s.logger.Errorln("Layer: beneficiary_services", "Method: CreateBeneficiary", "Error:", ErrInvalidBeneficiary)
+		return entities.Beneficiary{}, ErrInvalidBeneficiary
+	}
+	if beneficiary.Nickname == "" {
+		beneficiary.Nickname = beneficiary.RecipientName
+	}
+
+	beneficiary, err = s.beneficiaryRepository.CreateBeneficiary(beneficiary, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: CreateBeneficiary", "Error:", err)
+		return entities.Beneficiary{}, beneficiaryError(err)
+	}
+	s.logger.Infoln("Layer: beneficiary_services", "Method: CreateBeneficiary", "Beneficiary:", beneficiary.ID)
+	return beneficiary, nil
+}
+
+// ListBeneficiaries returns the beneficiaries of the authenticated user,
+// favorites first and then the most recently used.
+func (s *beneficiaryService) ListBeneficiaries(ctx context.Context, email string) ([]entities.Beneficiary, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: ListBeneficiaries", "Error:", err)
+		return nil, err
+	}
+	beneficiaries, err := s.beneficiaryRepository.ListBeneficiaries(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: ListBeneficiaries", "Error:", err)
+		return nil, err
+	}
+	return beneficiaries, nil
+}
+
+// UpdateBeneficiary renames a beneficiary of the authenticated user or marks
+// it as a favorite.
+func (s *beneficiaryService) UpdateBeneficiary(ctx context.Context, email string, id string, nickname string, favorite bool) (entities.Beneficiary, error) {
+	nickname = strings.TrimSpace(nickname)
+	if nickname == "" || utf8.RuneCountInString(nickname) > maxNicknameLength {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: UpdateBeneficiary", "Error:", ErrInvalidBeneficiary)
+		return entities.Beneficiary{}, ErrInvalidBeneficiary
+	}
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: UpdateBeneficiary", "Error:", err)
+		return entities.Beneficiary{}, err
+	}
+	beneficiary, err := s.beneficiaryRepository.UpdateBeneficiary(id, user.ID, nickname, favorite, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: UpdateBeneficiary", "Error:", err)
+		return entities.Beneficiary{}, beneficiaryError(err)
+	}
+	return beneficiary, nil
+}
+
+func (s *beneficiaryService) DeleteBeneficiary(ctx context.Context, email string, id string) error {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: DeleteBeneficiary", "Error:", err)
+		return err
+	}
+	if err := s.beneficiaryRepository.DeleteBeneficiary(id, user.ID, ctx); err != nil {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: DeleteBeneficiary", "Error:", err)
+		return beneficiaryError(err)
+	}
+	return nil
+}
+
+// Beneficiary returns a beneficiary of the user. Beneficiaries of other users
+// are reported as not found.
+func (s *beneficiaryService) Beneficiary(ctx context.Context, user entities.User, id string, beneficiaryType string) (entities.Beneficiary, error) {
+	beneficiary, err := s.beneficiaryRepository.GetBeneficiary(id, ctx)
+	if err == nil && beneficiary.UserID != user.ID {
+		err = ErrBeneficiaryNotFound
+	}
+	if err != nil {
+		return entities.Beneficiary{}, beneficiaryError(err)
+	}
+	if beneficiary.Type != beneficiaryType {
+		return entities.Beneficiary{}, ErrBeneficiaryType
+	}
+	return beneficiary, nil
+}
+
+// CheckCoolingOff fails with ErrBeneficiaryCoolingOff while the beneficiary
+// cools off and amount is above the limit of the currency.
+func (s *beneficiaryService) CheckCoolingOff(beneficiary entities.Beneficiary, currency string, amount int64) error {
+	if limit, ok := s.coolingOffLimits[currency]; ok && amount > limit && time.Now().Before(beneficiary.CoolingOff_until) {
+		return ErrBeneficiaryCoolingOff
+	}
+	return nil
+}
+
+// Used records the time money was last sent to the beneficiary. Failures are
+// logged; the money has already moved.
+func (s *beneficiaryService) Used(ctx context.Context, beneficiary entities.Beneficiary) {
+	if err := s.beneficiaryRepository.TouchBeneficiary(beneficiary.ID, time.Now().UTC(), ctx); err != nil {
+		s.logger.Errorln("Layer: beneficiary_services", "Method: Used", "Error:", err)
+	}
+}
+
+// beneficiaryError maps the errors of the beneficiary repository to the ones
+// of the service.
+func beneficiaryError(err error) error {
+	switch {
+	case errors.Is(err, repository_beneficiary.ErrBeneficiaryNotFound):
+		return ErrBeneficiaryNotFound
+	case errors.Is(err, repository_beneficiary.ErrBeneficiaryExists):
+		return ErrBeneficiaryExists
+	}
+	return err
+}
+
+// validBankAccount checks the account is at a known bank and looks like an
+// account number: 6 to 20 digits.
+func validBankAccount(bank entities.BankAccount) bool {
+	if _, ok := entities.Banks[bank.BankCode]; !ok {
+		return false
+	}
+	if bank.AccountType != entities.BankSavings && bank.AccountType != entities.BankChecking {
+		return false
+	}
+	if len(bank.AccountNumber) < 6 || len(bank.AccountNumber) > 20 {
+		return false
+	}
+	for _, c := range bank.AccountNumber {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	holder := strings.TrimSpace(bank.HolderName)
+	return holder != "" && utf8.RuneCountInString(holder) <= maxHolderNameLength
+}
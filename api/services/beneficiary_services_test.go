@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_beneficiary "my_wallet/api/respository/beneficiary"
+	repository_user "my_wallet/api/respository/user"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateBeneficiaryService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com", Name: "Alexer", Enabled: true}
+	jane := entities.User{ID: "u2", Email: "jane@gmail.com", Name: "Jane", Enabled: true}
+	account := entities.BankAccount{BankCode: "1007", AccountType: entities.BankSavings, AccountNumber: "12345678901", HolderName: "Jane Doe", HolderDNI: 1020304050}
+
+	testScenarios := []struct {
+		testName       string
+		input          entities.NewBeneficiary
+		createError    error
+		expectedTarget string
+		expectedName   string
+		expectedError  error
+	}{
+		{
+			testName:       "TestCreateWalletBeneficiary",
+			input:          entities.NewBeneficiary{Type: entities.BeneficiaryWallet, RecipientEmail: "jane@gmail.com"},
+			expectedTarget: "wallet:u2",
+			expectedName:   "Jane",
+		},
+		{
+			testName:       "TestCreateBankBeneficiary",
+			input:          entities.NewBeneficiary{Type: entities.BeneficiaryBank, Nickname: "Jane's savings", Bank: &account},
+			expectedTarget: "bank:1007:12345678901",
+			expectedName:   "Jane's savings",
+		},
+		{
+			testName:      "TestCreateBeneficiaryUnknownBank",
+			input:         entities.NewBeneficiary{Type: entities.BeneficiaryBank, Bank: &entities.BankAccount{BankCode: "9999", AccountType: entities.BankSavings, AccountNumber: "12345678901", HolderName: "Jane Doe"}},
+			expectedError: ErrInvalidBeneficiary,
+		},
+		{
+			testName:      "TestCreateBeneficiaryBadAccountNumber",
+			input:         entities.NewBeneficiary{Type: entities.BeneficiaryBank, Bank: &entities.BankAccount{BankCode: "1007", AccountType: entities.BankChecking, AccountNumber: "12-34", HolderName: "Jane Doe"}},
+			expectedError: ErrInvalidBeneficiary,
+		},
+		{
+			testName:      "TestCreateBeneficiaryUnknownType",
+			input:         entities.NewBeneficiary{Type: "card", RecipientEmail: "jane@gmail.com"},
+			expectedError: ErrInvalidBeneficiary,
+		},
+		{
+			testName:      "TestCreateBeneficiaryOfSelf",
+			input:         entities.NewBeneficiary{Type: entities.BeneficiaryWallet, RecipientEmail: "alexer@gmail.com"},
+			expectedError: ErrSelfTransfer,
+		},
+		{
+			testName:      "TestCreateBeneficiaryUnknownRecipient",
+			input:         entities.NewBeneficiary{Type: entities.BeneficiaryWallet, RecipientEmail: "nobody@gmail.com"},
+			expectedError: ErrRecipientNotFound,
+		},
+		{
+			testName:      "TestCreateBeneficiaryTwice",
+			input:         entities.NewBeneficiary{Type: entities.BeneficiaryWallet, RecipientEmail: "jane@gmail.com"},
+			createError:   repository_beneficiary.ErrBeneficiaryExists,
+			expectedError: ErrBeneficiaryExists,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			users.On("GetUserByEmail", mock.Anything, "jane@gmail.com").Return(jane, nil)
+			users.On("GetUserByEmail", mock.Anything, "nobody@gmail.com").Return(entities.User{}, repository_user.ErrUserNotfound)
+			beneficiaries := &beneficiaryRepositoryMock{}
+			beneficiaries.On("CreateBeneficiary", mock.Anything, mock.Anything).Return(func(b entities.Beneficiary) entities.Beneficiary {
+				b.ID = "b1"
+				return b
+			}, tt.createError)
+			service := NewBeneficiaryService(users, beneficiaries, 24*time.Hour, map[string]int64{"COP": 20000000}, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateBeneficiary(context.Background(), "alexer@gmail.com", tt.input)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, "u1", result.UserID)
+				assert.Equal(t, tt.expectedTarget, result.Target)
+				assert.Equal(t, tt.expectedName, result.Nickname)
+				assert.True(t, result.CoolingOff_until.After(time.Now().Add(23*time.Hour)))
+			}
+		})
+	}
+}
+
+func TestCheckCoolingOffService(t *testing.T) {
+	service := NewBeneficiaryService(&userServiceMock{}, &beneficiaryRepositoryMock{}, 24*time.Hour, map[string]int64{"COP": 20000000}, logrus.StandardLogger(), context.Background())
+	cooling := entities.Beneficiary{ID: "b1", CoolingOff_until: time.Now().Add(time.Hour)}
+	cooled := entities.Beneficiary{ID: "b1", CoolingOff_until: time.Now().Add(-time.Hour)}
+
+	testScenarios := []struct {
+		testName      string
+		beneficiary   entities.Beneficiary
+		currency      string
+		amount        int64
+		expectedError error
+	}{
+		{testName: "TestCoolingOffBelowLimit", beneficiary: cooling, currency: "COP", amount: 20000000},
+		{testName: "TestCoolingOffAboveLimit", beneficiary: cooling, currency: "COP", amount: 20000001, expectedError: ErrBeneficiaryCoolingOff},
+		{testName: "TestCoolingOffCurrencyWithoutLimit", beneficiary: cooling, currency: "USD", amount: 1000000},
+		{testName: "TestCoolingOffOver", beneficiary: cooled, currency: "COP", amount: 90000000},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			err := service.CheckCoolingOff(tt.beneficiary, tt.currency, tt.amount)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type budgetRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *budgetRepositoryMock) CreateBudget(budget entities.Budget, ctx context.Context) (entities.Budget, error) {
+	r := m.Called(ctx, budget)
+	return r.Get(0).(entities.Budget), r.Error(1)
+}
+
+func (m *budgetRepositoryMock) GetBudget(id string, ctx context.Context) (entities.Budget, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.Budget), r.Error(1)
+}
+
+func (m *budgetRepositoryMock) ListBudgets(userID string, ctx context.Context) ([]entities.Budget, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).([]entities.Budget), r.Error(1)
+}
+
+func (m *budgetRepositoryMock) FindBudget(walletID string, category string, ctx context.Context) (entities.Budget, error) {
+	r := m.Called(ctx, walletID, category)
+	return r.Get(0).(entities.Budget), r.Error(1)
+}
+
+func (m *budgetRepositoryMock) UpdateLimit(id string, limit int64, ctx context.Context) (entities.Budget, error) {
+	r := m.Called(ctx, id, limit)
+	return r.Get(0).(entities.Budget), r.Error(1)
+}
+
+func (m *budgetRepositoryMock) DeleteBudget(id string, ctx context.Context) error {
+	r := m.Called(ctx, id)
+	return r.Error(0)
+}
+
+func (m *budgetRepositoryMock) AddSpend(id string, period string, amount int64, ctx context.Context) (entities.Budget, error) {
+	r := m.Called(ctx, id, period, amount)
+	return r.Get(0).(entities.Budget), r.Error(1)
+}
+
+func (m *budgetRepositoryMock) ClaimAlert(id string, period string, threshold int, ctx context.Context) (bool, error) {
+	r := m.Called(ctx, id, period, threshold)
+	return r.Bool(0), r.Error(1)
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my_wallet/api/entities"
+	repository_budget "my_wallet/api/respository/budget"
+	repository_category "my_wallet/api/respository/category"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// spendingTypes are the transactions that count against a budget. Moves to
+// pockets and conversions keep the money in the user's hands.
+var spendingTypes = map[string]bool{
+	entities.TransactionTransferOut: true,
+	entities.TransactionWithdrawal:  true,
+	entities.TransactionFee:         true,
+	entities.TransactionPaymentOut:  true,
+	entities.TransactionCardPayment: true,
+}
+
+// PostingObserver is told about the transactions of every entry once it is
+// posted.
+type PostingObserver interface {
+	Posted(ctx context.Context, transactions []entities.Transaction)
+}
+
+type BudgetService interface {
+	PostingObserver
+	CreateBudget(ctx context.Context, email string, budget entities.Budget) (entities.Budget, error)
+	ListBudgets(ctx context.Context, email string) ([]entities.Budget, error)
+	UpdateBudget(ctx context.Context, email string, id string, limit int64) (entities.Budget, error)
+	DeleteBudget(ctx context.Context, email string, id string) error
+	GetBudgetStatus(ctx context.Context, email string, id string) (entities.BudgetStatus, error)
+}
+
+type budgetService struct {
+	ctx                   context.Context
+	userRepository        repository_user.UserRepository
+	walletRepository      repository_wallet.WalletRepository
+	transactionRepository repository_ledger.TransactionRepository
+	categoryRepository    repository_category.CategoryRepository
+	budgetRepository      repository_budget.BudgetRepository
+	notifier              Notifier
+	members               Memberships
+	logger                logrus.FieldLogger
+}
+
+func NewBudgetService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, transactionRepo repository_ledger.TransactionRepository, categoryRepo repository_category.CategoryRepository, budgetRepo repository_budget.BudgetRepository, notifier Notifier, members Memberships, logger logrus.FieldLogger, ctx context.Context) *budgetService {
+	return &budgetService{
+		ctx:                   ctx,
+		userRepository:        userRepo,
+		walletRepository:      walletRepo,
+		transactionRepository: transactionRepo,
+		categoryRepository:    categoryRepo,
+		budgetRepository:      budgetRepo,
+		notifier:              notifier,
+		members:               members,
+		logger:                logger,
+	}
+}
+
+// CreateBudget sets a monthly limit for a category of the user's wallet. What
+// was already spent this month counts, but no alert is sent for it.
+func (s *budgetService) CreateBudget(ctx context.Context, email string, budget entities.Budget) (entities.Budget, error) {
+	if budget.Limit <= 0 {
+		s.logger.Errorln("Layer: budget_services", "Method: CreateBudget", "Error:", ErrInvalidBudget)
+		return entities.Budget{}, ErrInvalidBudget
+	}
+	user, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, budget.WalletID, entities.PermissionView)
+	if err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: CreateBudget", "Error:", err)
+		return entities.Budget{}, err
+	}
+	if err := knownCategory(ctx, s.categoryRepository, user.ID, budget.Category); err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: CreateBudget", "Error:", err)
+		return entities.Budget{}, err
+	}
+
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	var spent int64
+	err = s.transactionRepository.StreamTransactions(wallet.ID, wallet.Currency, monthStart, monthStart.AddDate(0, 1, 0), ctx, func(transaction entities.Transaction) error {
+		if spendingTypes[transaction.Type] && transaction.Category == budget.Category {
+			spent += transaction.Amount
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: CreateBudget", "Error:", err)
+		return entities.Budget{}, err
+	}
+
+	budget = entities.Budget{
+		UserID:     user.ID,
+		WalletID:   wallet.ID,
+		Category:   budget.Category,
+		Currency:   wallet.Currency,
+		Limit:      budget.Limit,
+		Period:     entities.BudgetPeriod(now),
+		Spent:      spent,
+		Alerted:    crossedThreshold(spent, budget.Limit),
+		Created_at: now,
+		Update_at:  now,
+	}
+	budget, err = s.budgetRepository.CreateBudget(budget, ctx)
+	if errors.Is(err, repository_budget.ErrBudgetExists) {
+		s.logger.Errorln("Layer: budget_services", "Method: CreateBudget", "Error:", err)
+		return entities.Budget{}, ErrBudgetExists
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: CreateBudget", "Error:", err)
+		return entities.Budget{}, err
+	}
+	return budget, nil
+}
+
+// ListBudgets returns the budgets of the authenticated user.
+func (s *budgetService) ListBudgets(ctx context.Context, email string) ([]entities.Budget, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: ListBudgets", "Error:", err)
+		return nil, err
+	}
+	budgets, err := s.budgetRepository.ListBudgets(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: ListBudgets", "Error:", err)
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// UpdateBudget changes the monthly limit of a budget of the user.
+func (s *budgetService) UpdateBudget(ctx context.Context, email string, id string, limit int64) (entities.Budget, error) {
+	if limit <= 0 {
+		s.logger.Errorln("Layer: budget_services", "Method: UpdateBudget", "Error:", ErrInvalidBudget)
+		return entities.Budget{}, ErrInvalidBudget
+	}
+	if _, err := s.ownedBudget(ctx, email, id); err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: UpdateBudget", "Error:", err)
+		return entities.Budget{}, err
+	}
+	budget, err := s.budgetRepository.UpdateLimit(id, limit, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: UpdateBudget", "Error:", err)
+		return entities.Budget{}, mapBudgetError(err)
+	}
+	return budget, nil
+}
+
+// DeleteBudget deletes a budget of the user.
+func (s *budgetService) DeleteBudget(ctx context.Context, email string, id string) error {
+	if _, err := s.ownedBudget(ctx, email, id); err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: DeleteBudget", "Error:", err)
+		return err
+	}
+	if err := s.budgetRepository.DeleteBudget(id, ctx); err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: DeleteBudget", "Error:", err)
+		return mapBudgetError(err)
+	}
+	return nil
+}
+
+// GetBudgetStatus returns what is left of a budget this month and where the
+// spend is heading by the end of it.
+func (s *budgetService) GetBudgetStatus(ctx context.Context, email string, id string) (entities.BudgetStatus, error) {
+	budget, err := s.ownedBudget(ctx, email, id)
+	if err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: GetBudgetStatus", "Error:", err)
+		return entities.BudgetStatus{}, err
+	}
+	return budgetStatus(budget, time.Now().UTC()), nil
+}
+
+// Posted adds the spending transactions of a posted entry to the budgets of
+// their category and sends the alerts of the thresholds they cross. Posting
+// has already happened, so failures are logged and not returned.
+func (s *budgetService) Posted(ctx context.Context, transactions []entities.Transaction) {
+	for _, transaction := range transactions {
+		if !spendingTypes[transaction.Type] || transaction.Category == "" || transaction.Status != entities.StatusCompleted {
+			continue
+		}
+		budget, err := s.budgetRepository.FindBudget(transaction.WalletID, transaction.Category, ctx)
+		if errors.Is(err, repository_budget.ErrBudgetNotFound) || (err == nil && budget.Currency != transaction.Currency) {
+			continue
+		}
+		if err != nil {
+			s.logger.Errorln("Layer: budget_services", "Method: Posted", "Error:", err)
+			continue
+		}
+		period := entities.BudgetPeriod(transaction.Created_at)
+		budget, err = s.budgetRepository.AddSpend(budget.ID, period, transaction.Amount, ctx)
+		if err != nil {
+			s.logger.Errorln("Layer: budget_services", "Method: Posted", "Error:", err)
+			continue
+		}
+		if budget.Period == period {
+			s.alert(ctx, budget)
+		}
+	}
+}
+
+// alert sends the alert of the highest threshold the budget has crossed,
+// unless it was already sent this month.
+func (s *budgetService) alert(ctx context.Context, budget entities.Budget) {
+	threshold := crossedThreshold(budget.Spent, budget.Limit)
+	if threshold <= budget.Alerted {
+		return
+	}
+	claimed, err := s.budgetRepository.ClaimAlert(budget.ID, budget.Period, threshold, ctx)
+	if err != nil || !claimed {
+		if err != nil {
+			s.logger.Errorln("Layer: budget_services", "Method: alert", "Error:", err)
+		}
+		return
+	}
+	message := fmt.Sprintf("You have spent %d%% of your %s budget for %s", threshold, budget.Category, budget.Period)
+	if threshold >= 100 {
+		message = fmt.Sprintf("You have reached your %s budget for %s", budget.Category, budget.Period)
+	}
+	err = s.notifier.Notify(ctx, entities.Notification{
+		UserID:  budget.UserID,
+		Type:    entities.NotificationBudgetAlert,
+		Title:   "Budget alert",
+		Message: message,
+		Data: map[string]string{
+			"budget_id": budget.ID,
+			"category":  budget.Category,
+			"period":    budget.Period,
+			"threshold": strconv.Itoa(threshold),
+			"spent":     strconv.FormatInt(budget.Spent, 10),
+			"limit":     strconv.FormatInt(budget.Limit, 10),
+			"currency":  budget.Currency,
+		},
+	})
+	if err != nil {
+		s.logger.Errorln("Layer: budget_services", "Method: alert", "Error:", err)
+	}
+}
+
+func (s *budgetService) ownedBudget(ctx context.Context, email string, id string) (entities.Budget, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.Budget{}, err
+	}
+	budget, err := s.budgetRepository.GetBudget(id, ctx)
+	if err != nil {
+		return entities.Budget{}, mapBudgetError(err)
+	}
+	if budget.UserID != user.ID {
+		return entities.Budget{}, ErrBudgetNotFound
+	}
+	return budget, nil
+}
+
+func mapBudgetError(err error) error {
+	if errors.Is(err, repository_budget.ErrBudgetNotFound) {
+		return ErrBudgetNotFound
+	}
+	return err
+}
+
+// crossedThreshold returns the highest alert threshold spent has reached, or
+// zero.
+func crossedThreshold(spent int64, limit int64) int {
+	crossed := 0
+	for _, threshold := range entities.BudgetThresholds {
+		if spent*100 >= limit*int64(threshold) {
+			crossed = threshold
+		}
+	}
+	return crossed
+}
+
+// budgetStatus computes the status of the budget at now. The projection
+// assumes the rest of the month is spent at the daily average so far.
+func budgetStatus(budget entities.Budget, now time.Time) entities.BudgetStatus {
+	period := entities.BudgetPeriod(now)
+	var spent int64
+	if budget.Period == period {
+		spent = budget.Spent
+	}
+	daysInMonth := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	projected := spent * int64(daysInMonth) / int64(now.Day())
+	return entities.BudgetStatus{
+		BudgetID:      budget.ID,
+		Category:      budget.Category,
+		Currency:      budget.Currency,
+		Period:        period,
+		Limit:         budget.Limit,
+		Spent:         spent,
+		Remaining:     max(budget.Limit-spent, 0),
+		Percent:       spent * 100 / budget.Limit,
+		Projected:     projected,
+		DaysLeft:      daysInMonth - now.Day(),
+		OverBudget:    spent > budget.Limit,
+		ProjectedOver: projected > budget.Limit,
+	}
+}
+
+// observedLedger tells the observers about every entry it posts.
+type observedLedger struct {
+	repository_ledger.LedgerRepository
+	observers []PostingObserver
+}
+
+// NewObservedLedger wraps a ledger repository so that the observers learn
+// about every posted transaction.
+func NewObservedLedger(ledger repository_ledger.LedgerRepository, observers ...PostingObserver) repository_ledger.LedgerRepository {
+	return &observedLedger{LedgerRepository: ledger, observers: observers}
+}
+
+func (l *observedLedger) PostEntry(entry entities.LedgerEntry, transactions []entities.Transaction, ctx context.Context) (entities.LedgerEntry, []entities.Transaction, error) {
+	entry, transactions, err := l.LedgerRepository.PostEntry(entry, transactions, ctx)
+	if err != nil {
+		return entry, transactions, err
+	}
+	for _, observer := range l.observers {
+		observer.Posted(ctx, transactions)
+	}
+	return entry, transactions, nil
+}
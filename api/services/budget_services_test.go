@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_budget "my_wallet/api/respository/budget"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBudgetStatus(t *testing.T) {
+	now := time.Date(2024, 4, 10, 15, 0, 0, 0, time.UTC)
+
+	testScenarios := []struct {
+		testName string
+		budget   entities.Budget
+		expected entities.BudgetStatus
+	}{
+		{
+			testName: "on track",
+			budget:   entities.Budget{ID: "b1", Category: "groceries", Currency: "COP", Limit: 300000, Period: "2024-04", Spent: 60000},
+			expected: entities.BudgetStatus{BudgetID: "b1", Category: "groceries", Currency: "COP", Period: "2024-04", Limit: 300000, Spent: 60000, Remaining: 240000, Percent: 20, Projected: 180000, DaysLeft: 20},
+		},
+		{
+			testName: "heading over the limit",
+			budget:   entities.Budget{ID: "b1", Category: "groceries", Currency: "COP", Limit: 300000, Period: "2024-04", Spent: 200000},
+			expected: entities.BudgetStatus{BudgetID: "b1", Category: "groceries", Currency: "COP", Period: "2024-04", Limit: 300000, Spent: 200000, Remaining: 100000, Percent: 66, Projected: 600000, DaysLeft: 20, ProjectedOver: true},
+		},
+		{
+			testName: "over the limit",
+			budget:   entities.Budget{ID: "b1", Category: "groceries", Currency: "COP", Limit: 300000, Period: "2024-04", Spent: 330000},
+			expected: entities.BudgetStatus{BudgetID: "b1", Category: "groceries", Currency: "COP", Period: "2024-04", Limit: 300000, Spent: 330000, Percent: 110, Projected: 990000, DaysLeft: 20, OverBudget: true, ProjectedOver: true},
+		},
+		{
+			testName: "nothing spent yet this month",
+			budget:   entities.Budget{ID: "b1", Category: "groceries", Currency: "COP", Limit: 300000, Period: "2024-03", Spent: 290000, Alerted: 80},
+			expected: entities.BudgetStatus{BudgetID: "b1", Category: "groceries", Currency: "COP", Period: "2024-04", Limit: 300000, Remaining: 300000, DaysLeft: 20},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			result := budgetStatus(tt.budget, now)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestBudgetPosted(t *testing.T) {
+	postedAt := time.Date(2024, 4, 10, 15, 0, 0, 0, time.UTC)
+	budget := entities.Budget{ID: "b1", UserID: "u1", WalletID: "w1", Category: "groceries", Currency: "COP", Limit: 100000, Period: "2024-04"}
+
+	testScenarios := []struct {
+		testName          string
+		transaction       entities.Transaction
+		spentBefore       int64
+		alertedBefore     int
+		findError         error
+		expectedThreshold int
+	}{
+		{
+			testName:          "TestCrossFirstThreshold",
+			transaction:       entities.Transaction{WalletID: "w1", Type: entities.TransactionTransferOut, Status: entities.StatusCompleted, Amount: 55000, Currency: "COP", Category: "groceries", Created_at: postedAt},
+			expectedThreshold: 50,
+		},
+		{
+			testName:          "TestJumpToTheLimit",
+			transaction:       entities.Transaction{WalletID: "w1", Type: entities.TransactionWithdrawal, Status: entities.StatusCompleted, Amount: 70000, Currency: "COP", Category: "groceries", Created_at: postedAt},
+			spentBefore:       40000,
+			expectedThreshold: 100,
+		},
+		{
+			testName:      "TestThresholdAlreadyAlerted",
+			transaction:   entities.Transaction{WalletID: "w1", Type: entities.TransactionTransferOut, Status: entities.StatusCompleted, Amount: 5000, Currency: "COP", Category: "groceries", Created_at: postedAt},
+			spentBefore:   80000,
+			alertedBefore: 80,
+		},
+		{
+			testName:    "TestIncomeIsNotSpend",
+			transaction: entities.Transaction{WalletID: "w1", Type: entities.TransactionTransferIn, Status: entities.StatusCompleted, Amount: 90000, Currency: "COP", Category: "groceries", Created_at: postedAt},
+		},
+		{
+			testName:    "TestCategoryWithoutBudget",
+			transaction: entities.Transaction{WalletID: "w1", Type: entities.TransactionTransferOut, Status: entities.StatusCompleted, Amount: 90000, Currency: "COP", Category: "travel", Created_at: postedAt},
+			findError:   repository_budget.ErrBudgetNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			budgets := &budgetRepositoryMock{}
+			budgets.On("FindBudget", mock.Anything, "w1", tt.transaction.Category).Return(budget, tt.findError)
+			after := budget
+			after.Spent = tt.spentBefore + tt.transaction.Amount
+			after.Alerted = tt.alertedBefore
+			budgets.On("AddSpend", mock.Anything, "b1", "2024-04", tt.transaction.Amount).Return(after, nil)
+			budgets.On("ClaimAlert", mock.Anything, "b1", "2024-04", mock.Anything).Return(true, nil)
+			notifier := &notifierMock{}
+			notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+			service := NewBudgetService(&userServiceMock{}, &walletRepositoryMock{}, &transactionRepositoryMock{}, &categoryRepositoryMock{}, budgets, notifier, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			service.Posted(context.Background(), []entities.Transaction{tt.transaction})
+
+			// Assert
+			if tt.expectedThreshold == 0 {
+				notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+				return
+			}
+			budgets.AssertCalled(t, "ClaimAlert", mock.Anything, "b1", "2024-04", tt.expectedThreshold)
+			notification := notifier.Calls[0].Arguments.Get(1).(entities.Notification)
+			assert.Equal(t, "u1", notification.UserID)
+			assert.Equal(t, entities.NotificationBudgetAlert, notification.Type)
+			assert.Equal(t, "b1", notification.Data["budget_id"])
+		})
+	}
+}
+
+func TestCrossedThreshold(t *testing.T) {
+	assert.Equal(t, 0, crossedThreshold(49999, 100000))
+	assert.Equal(t, 50, crossedThreshold(50000, 100000))
+	assert.Equal(t, 80, crossedThreshold(99999, 100000))
+	assert.Equal(t, 100, crossedThreshold(250000, 100000))
+}
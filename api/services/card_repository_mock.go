@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type cardRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *cardRepositoryMock) CreateCard(card entities.Card, ctx context.Context) (entities.Card, error) {
+	r := m.Called(ctx, card)
+	if created, ok := r.Get(0).(func(entities.Card) entities.Card); ok {
+		return created(card), r.Error(1)
+	}
+	return r.Get(0).(entities.Card), r.Error(1)
+}
+
+func (m *cardRepositoryMock) GetCard(id string, ctx context.Context) (entities.Card, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.Card), r.Error(1)
+}
+
+func (m *cardRepositoryMock) GetCardByToken(token string, ctx context.Context) (entities.Card, error) {
+	r := m.Called(ctx, token)
+	return r.Get(0).(entities.Card), r.Error(1)
+}
+
+func (m *cardRepositoryMock) ListCards(userID string, ctx context.Context) ([]entities.Card, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).([]entities.Card), r.Error(1)
+}
+
+func (m *cardRepositoryMock) UpdateCard(card entities.Card, ctx context.Context) (entities.Card, error) {
+	r := m.Called(ctx, card)
+	if updated, ok := r.Get(0).(func(entities.Card) entities.Card); ok {
+		return updated(card), r.Error(1)
+	}
+	return r.Get(0).(entities.Card), r.Error(1)
+}
+
+func (m *cardRepositoryMock) CreateAuthorization(authorization entities.CardAuthorization, ctx context.Context) (entities.CardAuthorization, error) {
+	r := m.Called(ctx, authorization)
+	if created, ok := r.Get(0).(func(entities.CardAuthorization) entities.CardAuthorization); ok {
+		return created(authorization), r.Error(1)
+	}
+	return r.Get(0).(entities.CardAuthorization), r.Error(1)
+}
+
+func (m *cardRepositoryMock) GetAuthorizationByMessage(messageID string, ctx context.Context) (entities.CardAuthorization, error) {
+	r := m.Called(ctx, messageID)
+	return r.Get(0).(entities.CardAuthorization), r.Error(1)
+}
+
+func (m *cardRepositoryMock) ListAuthorizations(cardID string, limit int64, ctx context.Context) ([]entities.CardAuthorization, error) {
+	r := m.Called(ctx, cardID, limit)
+	return r.Get(0).([]entities.CardAuthorization), r.Error(1)
+}
+
+func (m *cardRepositoryMock) UpdateAuthorization(authorization entities.CardAuthorization, from string, ctx context.Context) error {
+	r := m.Called(ctx, authorization, from)
+	return r.Error(0)
+}
+
+func (m *cardRepositoryMock) ReserveSpending(key string, amount int64, max int64, expiresAt time.Time, ctx context.Context) (int64, error) {
+	r := m.Called(ctx, key, amount, max)
+	return r.Get(0).(int64), r.Error(1)
+}
+
+func (m *cardRepositoryMock) ReleaseSpending(key string, amount int64, ctx context.Context) error {
+	r := m.Called(ctx, key, amount)
+	return r.Error(0)
+}
@@ -0,0 +1,649 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"my_wallet/api/entities"
+	repository_card "my_wallet/api/respository/card"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"my_wallet/api/utils/cardnet"
+	"my_wallet/api/utils/pan"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxCardNameLength     = 40
+	cardValidityYears     = 3
+	maxCardAuthorizations = 50
+	// cardIssueAttempts is how many card numbers are drawn before giving up
+	// when they are already issued.
+	cardIssueAttempts = 3
+)
+
+type CardService interface {
+	IssueCard(ctx context.Context, email string, card entities.Card) (entities.IssuedCard, error)
+	ListCards(ctx context.Context, email string) ([]entities.Card, error)
+	UpdateCardLimits(ctx context.Context, email string, cardID string, limits entities.CardLimits) (entities.Card, error)
+	FreezeCard(ctx context.Context, email string, cardID string, frozen bool) (entities.Card, error)
+	ListCardAuthorizations(ctx context.Context, email string, cardID string) ([]entities.CardAuthorization, error)
+	HandleNetworkMessage(ctx context.Context, body []byte, signature string) (cardnet.Response, error)
+}
+
+type cardService struct {
+	ctx              context.Context
+	userRepository   repository_user.UserRepository
+	walletRepository repository_wallet.WalletRepository
+	ledgerRepository repository_ledger.LedgerRepository
+	cardRepository   repository_card.CardRepository
+	limiter          Limiter
+	restrictions     Restrictions
+	bin              string
+	tokenKey         []byte
+	networkSecret    []byte
+	logger           logrus.FieldLogger
+}
+
+// NewCardService creates the card service. Card numbers start with bin and
+// are stored as tokens under tokenKey; the messages of the card network are
+// signed with networkSecret.
+func NewCardService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, ledgerRepo repository_ledger.LedgerRepository, cardRepo repository_card.CardRepository, limiter Limiter, restrictions Restrictions, bin string, tokenKey []byte, networkSecret []byte, logger logrus.FieldLogger, ctx context.Context) *cardService {
+	return &cardService{
+		ctx:              ctx,
+		userRepository:   userRepo,
+		walletRepository: walletRepo,
+		ledgerRepository: ledgerRepo,
+		cardRepository:   cardRepo,
+		limiter:          limiter,
+		restrictions:     restrictions,
+		bin:              bin,
+		tokenKey:         tokenKey,
+		networkSecret:    networkSecret,
+		logger:           logger,
+	}
+}
+
+// IssueCard issues a virtual card on the wallet of the authenticated user,
+// in the default currency of the wallet unless it names one. The card number
+// is only returned here: it is stored as a token and its last four digits.
+func (s *cardService) IssueCard(ctx context.Context, email string, card entities.Card) (entities.IssuedCard, error) {
+	card.Name = strings.TrimSpace(card.Name)
+	if utf8.RuneCountInString(card.Name) > maxCardNameLength || !validCardLimits(card.Limits) {
+		s.logger.Errorln("Layer: card_services", "Method: IssueCard", "Error:", ErrInvalidCard)
+		return entities.IssuedCard{}, ErrInvalidCard
+	}
+	if card.Currency != "" && !entities.ValidCurrency(card.Currency) {
+		s.logger.Errorln("Layer: card_services", "Method: IssueCard", "Error:", ErrInvalidCurrency)
+		return entities.IssuedCard{}, ErrInvalidCurrency
+	}
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: IssueCard", "Error:", err)
+		return entities.IssuedCard{}, err
+	}
+	if err := requireActive(user); err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: IssueCard", "Error:", err)
+		return entities.IssuedCard{}, err
+	}
+	if err := requireKYC(user, entities.FeatureVirtualCard); err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: IssueCard", "Error:", err)
+		return entities.IssuedCard{}, err
+	}
+	wallet, err := s.walletRepository.GetOrCreateWallet(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: IssueCard", "Error:", err)
+		return entities.IssuedCard{}, err
+	}
+	if card.Currency == "" {
+		card.Currency = wallet.Currency
+	}
+
+	now := time.Now().UTC()
+	expiry := now.AddDate(cardValidityYears, 0, 0)
+	card.ID = ""
+	card.UserID = user.ID
+	card.WalletID = wallet.ID
+	card.ExpiryMonth = int(expiry.Month())
+	card.ExpiryYear = expiry.Year()
+	card.Status = entities.CardActive
+	card.Created_at = now
+	card.Update_at = now
+	for attempt := 0; ; attempt++ {
+		number, err := pan.Generate(s.bin, rand.Reader)
+		if err != nil {
+			s.logger.Errorln("Layer: card_services", "Method: IssueCard", "Error:", err)
+			return entities.IssuedCard{}, err
+		}
+		card.Token = pan.Token(s.tokenKey, number)
+		card.Last4 = pan.Last4(number)
+		issued, err := s.cardRepository.CreateCard(card, ctx)
+		if errors.Is(err, repository_card.ErrCardExists) && attempt+1 < cardIssueAttempts {
+			continue
+		}
+		if err != nil {
+			s.logger.Errorln("Layer: card_services", "Method: IssueCard", "Error:", err)
+			return entities.IssuedCard{}, err
+		}
+		s.logger.Infoln("Layer: card_services", "Method: IssueCard", "Card:", issued.ID)
+		return entities.IssuedCard{Card: issued, PAN: number}, nil
+	}
+}
+
+// ListCards returns the cards of the authenticated user.
+func (s *cardService) ListCards(ctx context.Context, email string) ([]entities.Card, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: ListCards", "Error:", err)
+		return nil, err
+	}
+	cards, err := s.cardRepository.ListCards(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: ListCards", "Error:", err)
+		return nil, err
+	}
+	return cards, nil
+}
+
+// UpdateCardLimits replaces the spending limits of a card of the
+// authenticated user. They apply to the authorizations that come next.
+func (s *cardService) UpdateCardLimits(ctx context.Context, email string, cardID string, limits entities.CardLimits) (entities.Card, error) {
+	if !validCardLimits(limits) {
+		s.logger.Errorln("Layer: card_services", "Method: UpdateCardLimits", "Error:", ErrInvalidCard)
+		return entities.Card{}, ErrInvalidCard
+	}
+	card, err := s.ownCard(ctx, email, cardID)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: UpdateCardLimits", "Error:", err)
+		return entities.Card{}, err
+	}
+	card.Limits = limits
+	card.Update_at = time.Now().UTC()
+	card, err = s.cardRepository.UpdateCard(card, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: UpdateCardLimits", "Error:", err)
+		return entities.Card{}, cardError(err)
+	}
+	return card, nil
+}
+
+// FreezeCard freezes or unfreezes a card of the authenticated user. Frozen
+// cards decline new authorizations; the ones already approved are still
+// cleared or reversed.
+func (s *cardService) FreezeCard(ctx context.Context, email string, cardID string, frozen bool) (entities.Card, error) {
+	card, err := s.ownCard(ctx, email, cardID)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: FreezeCard", "Error:", err)
+		return entities.Card{}, err
+	}
+	card.Status = entities.CardActive
+	if frozen {
+		card.Status = entities.CardFrozen
+	}
+	card.Update_at = time.Now().UTC()
+	card, err = s.cardRepository.UpdateCard(card, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: FreezeCard", "Error:", err)
+		return entities.Card{}, cardError(err)
+	}
+	s.logger.Infoln("Layer: card_services", "Method: FreezeCard", "Card:", card.ID, "Status:", card.Status)
+	return card, nil
+}
+
+// ListCardAuthorizations returns the last authorizations of a card of the
+// authenticated user, declined ones included, newest first.
+func (s *cardService) ListCardAuthorizations(ctx context.Context, email string, cardID string) ([]entities.CardAuthorization, error) {
+	card, err := s.ownCard(ctx, email, cardID)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: ListCardAuthorizations", "Error:", err)
+		return nil, err
+	}
+	authorizations, err := s.cardRepository.ListAuthorizations(card.ID, maxCardAuthorizations, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: ListCardAuthorizations", "Error:", err)
+		return nil, err
+	}
+	return authorizations, nil
+}
+
+// HandleNetworkMessage answers a message of the card network once its
+// signature is checked. Authorizations are always answered with a response
+// code, declines included; clearings and reversals of authorizations that
+// cannot take them fail. Messages the network sends again get the answer
+// they got the first time.
+func (s *cardService) HandleNetworkMessage(ctx context.Context, body []byte, signature string) (cardnet.Response, error) {
+	if !cardnet.Verify(s.networkSecret, body, signature) {
+		s.logger.Errorln("Layer: card_services", "Method: HandleNetworkMessage", "Error:", ErrInvalidCardSignature)
+		return cardnet.Response{}, ErrInvalidCardSignature
+	}
+	message, err := cardnet.Decode(body)
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: HandleNetworkMessage", "Error:", err)
+		return cardnet.Response{}, ErrInvalidCardMessage
+	}
+	var response cardnet.Response
+	switch message.Type {
+	case cardnet.TypeAuthorization:
+		response, err = s.authorize(ctx, message)
+	case cardnet.TypeClearing:
+		response, err = s.clear(ctx, message)
+	case cardnet.TypeReversal:
+		response, err = s.reverse(ctx, message)
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: HandleNetworkMessage", "Message:", message.ID, "Error:", err)
+		return cardnet.Response{}, err
+	}
+	s.logger.Infoln("Layer: card_services", "Method: HandleNetworkMessage", "Message:", message.ID, "Type:", message.Type, "Code:", response.Code)
+	return response, nil
+}
+
+// authorize decides on an authorization and, when it is approved, holds its
+// amount in the wallet of the card. The authorization is stored as pending
+// before anything else, so its message is only decided once.
+func (s *cardService) authorize(ctx context.Context, message cardnet.Message) (cardnet.Response, error) {
+	if !pan.Valid(message.PAN) {
+		return cardnet.Response{MessageID: message.ID, Code: cardnet.CodeInvalidCard}, nil
+	}
+	card, err := s.cardRepository.GetCardByToken(pan.Token(s.tokenKey, message.PAN), ctx)
+	if errors.Is(err, repository_card.ErrCardNotFound) {
+		return cardnet.Response{MessageID: message.ID, Code: cardnet.CodeInvalidCard}, nil
+	}
+	if err != nil {
+		return cardnet.Response{}, err
+	}
+
+	now := time.Now().UTC()
+	authorization := entities.CardAuthorization{
+		CardID:               card.ID,
+		WalletID:             card.WalletID,
+		UserID:               card.UserID,
+		MessageID:            message.ID,
+		Amount:               message.Amount,
+		Currency:             message.Currency,
+		MerchantName:         message.Merchant.Name,
+		MerchantCategoryCode: message.Merchant.CategoryCode,
+		MerchantCountry:      message.Merchant.Country,
+		Status:               entities.CardAuthorizationPending,
+		Created_at:           now,
+		Update_at:            now,
+	}
+	authorization, err = s.cardRepository.CreateAuthorization(authorization, ctx)
+	if errors.Is(err, repository_card.ErrAuthorizationExists) {
+		return s.answered(ctx, message)
+	}
+	if err != nil {
+		return cardnet.Response{}, err
+	}
+
+	code, err := s.decide(ctx, card, &authorization, message, now)
+	if err != nil {
+		// When in doubt the issuer declines; the purchase can be tried again.
+		s.logger.Errorln("Layer: card_services", "Method: authorize", "Error:", err)
+		code = cardnet.CodeDoNotHonor
+	}
+	authorization.Code = code
+	authorization.Status = entities.CardAuthorizationDeclined
+	if code == cardnet.CodeApproved {
+		authorization.Status = entities.CardAuthorizationApproved
+	}
+	authorization.Update_at = time.Now().UTC()
+	if err := s.cardRepository.UpdateAuthorization(authorization, entities.CardAuthorizationPending, ctx); err != nil {
+		// The decision stands; the network is answered anyway.
+		s.logger.Errorln("Layer: card_services", "Method: authorize", "Error:", err)
+	}
+	return cardnet.Response{MessageID: message.ID, Code: code}, nil
+}
+
+// answered returns the answer given to an authorization message already
+// received.
+func (s *cardService) answered(ctx context.Context, message cardnet.Message) (cardnet.Response, error) {
+	existing, err := s.cardRepository.GetAuthorizationByMessage(message.ID, ctx)
+	if err != nil {
+		return cardnet.Response{}, cardError(err)
+	}
+	if existing.Status == entities.CardAuthorizationPending {
+		return cardnet.Response{}, ErrCardAuthorizationPending
+	}
+	return cardnet.Response{MessageID: message.ID, Code: existing.Code}, nil
+}
+
+// decide checks the card, its limits, the user and the wallet, and holds the
+// amount when everything allows it. It returns the response code, or an
+// error when something could not be checked.
+func (s *cardService) decide(ctx context.Context, card entities.Card, authorization *entities.CardAuthorization, message cardnet.Message, now time.Time) (string, error) {
+	switch {
+	case card.Expired(now):
+		return cardnet.CodeExpiredCard, nil
+	case message.Expiry != cardnet.Expiry(card.ExpiryMonth, card.ExpiryYear):
+		return cardnet.CodeInvalidCard, nil
+	case card.Status != entities.CardActive:
+		return cardnet.CodeRestrictedCard, nil
+	case message.Currency != card.Currency:
+		return cardnet.CodeNotPermitted, nil
+	case card.Limits.PerTransaction > 0 && message.Amount > card.Limits.PerTransaction:
+		return cardnet.CodeLimitExceeded, nil
+	}
+
+	user, err := s.userRepository.GetUser(card.UserID, ctx)
+	if errors.Is(err, repository_user.ErrDisbledUser) || errors.Is(err, repository_user.ErrUserNotfound) {
+		return cardnet.CodeRestrictedCard, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !user.Active() {
+		return cardnet.CodeRestrictedCard, nil
+	}
+	if s.restrictions != nil {
+		err := s.restrictions.CheckDebit(ctx, card.WalletID, entities.CapabilityPayment)
+		if errors.Is(err, ErrWalletFrozen) || errors.Is(err, ErrWalletBlocked) || errors.Is(err, ErrCapabilityRestricted) {
+			return cardnet.CodeRestrictedCard, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	wallet, err := s.walletRepository.GetWallet(card.WalletID, ctx)
+	if err != nil {
+		return "", err
+	}
+	if wallet.Balances[message.Currency] < message.Amount {
+		return cardnet.CodeInsufficientFunds, nil
+	}
+
+	spending, err := s.reserveCardSpending(ctx, card, message.Amount, now)
+	if errors.Is(err, repository_card.ErrCardLimitReached) {
+		return cardnet.CodeLimitExceeded, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var reservation entities.LimitReservation
+	if s.limiter != nil {
+		reservation, err = s.limiter.Reserve(ctx, user, message.Currency, message.Amount)
+		if err != nil {
+			s.releaseCardSpending(ctx, spending)
+			if errors.Is(err, ErrLimitExceeded) {
+				return cardnet.CodeLimitExceeded, nil
+			}
+			return "", err
+		}
+	}
+	memo := "Card payment at " + message.Merchant.Name
+	entry := entities.LedgerEntry{
+		Type: entities.EntryCardHold,
+		Memo: memo,
+		Lines: []entities.LedgerLine{
+			{Account: entities.WalletAccount(wallet.ID), WalletID: wallet.ID, Currency: message.Currency, Amount: -message.Amount},
+			{Account: entities.AccountCardHolds, Currency: message.Currency, Amount: message.Amount},
+		},
+		Created_at: now,
+	}
+	transactions := []entities.Transaction{
+		{
+			WalletID: wallet.ID,
+			UserID:   user.ID,
+			Type:     entities.TransactionCardPayment,
+			Status:   entities.StatusCompleted,
+			Amount:   message.Amount,
+			Currency: message.Currency,
+			Memo:     memo,
+		},
+	}
+	entry, _, err = s.ledgerRepository.PostEntry(entry, transactions, ctx)
+	if err != nil {
+		s.releaseCardSpending(ctx, spending)
+		if s.limiter != nil {
+			s.limiter.Release(ctx, reservation)
+		}
+		if errors.Is(err, repository_ledger.ErrInsufficientFunds) {
+			return cardnet.CodeInsufficientFunds, nil
+		}
+		return "", err
+	}
+	authorization.HoldEntryID = entry.ID
+	authorization.LimitReservation = reservation
+	authorization.CardReservation = spending
+	return cardnet.CodeApproved, nil
+}
+
+// reserveCardSpending takes amount out of the daily and monthly limits of the
+// card, or out of none of them. Like the limits of the user, each window is
+// checked and taken in a single update, so authorizations decided at the same
+// time cannot go over a limit of the card together.
+func (s *cardService) reserveCardSpending(ctx context.Context, card entities.Card, amount int64, now time.Time) (entities.LimitReservation, error) {
+	day := now.Truncate(24 * time.Hour)
+	month := day.AddDate(0, 0, 1-day.Day())
+	windows := []struct {
+		kind    string
+		key     string
+		limit   int64
+		resetAt time.Time
+	}{
+		{entities.LimitDaily, card.ID + ":daily:" + day.Format("2006-01-02"), card.Limits.Daily, day.AddDate(0, 0, 1)},
+		{entities.LimitMonthly, card.ID + ":monthly:" + month.Format("2006-01"), card.Limits.Monthly, month.AddDate(0, 1, 0)},
+	}
+	var reservation entities.LimitReservation
+	for _, window := range windows {
+		if window.limit == 0 {
+			continue
+		}
+		// Spending is kept a while after the window is over so a late
+		// release does not bring it back from nothing.
+		if _, err := s.cardRepository.ReserveSpending(window.key, amount, window.limit, window.resetAt.Add(time.Hour), ctx); err != nil {
+			s.releaseCardSpending(ctx, reservation)
+			return entities.LimitReservation{}, err
+		}
+		reservation.Parts = append(reservation.Parts, entities.LimitReservationPart{Key: window.key, Kind: window.kind, Amount: amount})
+	}
+	return reservation, nil
+}
+
+// releaseCardSpending gives back what an authorization took from the limits
+// of its card. Failures are only logged: they leave the card with less room
+// until the window is over, never with more.
+func (s *cardService) releaseCardSpending(ctx context.Context, reservation entities.LimitReservation) {
+	for _, part := range reservation.Parts {
+		if err := s.cardRepository.ReleaseSpending(part.Key, part.Amount, ctx); err != nil {
+			s.logger.Errorln("Layer: card_services", "Method: releaseCardSpending", "Error:", err)
+		}
+	}
+}
+
+// clear settles an approved authorization: the amount cleared goes to the
+// card network and what was held over it goes back to the wallet and to the
+// limits of the card and its user.
+func (s *cardService) clear(ctx context.Context, message cardnet.Message) (cardnet.Response, error) {
+	authorization, err := s.cardRepository.GetAuthorizationByMessage(message.AuthorizationID, ctx)
+	if err != nil {
+		return cardnet.Response{}, cardError(err)
+	}
+	if authorization.Status == entities.CardAuthorizationCleared && authorization.ClearingMessageID == message.ID {
+		return cardnet.Response{MessageID: message.ID, Code: cardnet.CodeApproved}, nil
+	}
+	if err := openAuthorization(authorization); err != nil {
+		return cardnet.Response{}, err
+	}
+	if message.Amount > authorization.Amount || (message.Currency != "" && message.Currency != authorization.Currency) {
+		return cardnet.Response{}, ErrInvalidCardMessage
+	}
+
+	cleared := authorization
+	cleared.Status = entities.CardAuthorizationCleared
+	cleared.ClearedAmount = message.Amount
+	cleared.ClearingMessageID = message.ID
+	cleared.Update_at = time.Now().UTC()
+	if err := s.claimAuthorization(ctx, cleared, entities.CardAuthorizationApproved); err != nil {
+		return cardnet.Response{}, err
+	}
+	entry := entities.LedgerEntry{
+		Type: entities.EntryCardClearing,
+		Memo: "Card payment at " + authorization.MerchantName,
+		Lines: []entities.LedgerLine{
+			{Account: entities.AccountCardHolds, Currency: authorization.Currency, Amount: -authorization.Amount},
+			{Account: entities.AccountCardNetwork, Currency: authorization.Currency, Amount: cleared.ClearedAmount},
+		},
+		Created_at: cleared.Update_at,
+	}
+	var transactions []entities.Transaction
+	if released := authorization.Amount - cleared.ClearedAmount; released > 0 {
+		memo := "Card payment at " + authorization.MerchantName + " released"
+		entry.Lines = append(entry.Lines, entities.LedgerLine{Account: entities.WalletAccount(authorization.WalletID), WalletID: authorization.WalletID, Currency: authorization.Currency, Amount: released})
+		transactions = append(transactions, entities.Transaction{
+			WalletID:   authorization.WalletID,
+			UserID:     authorization.UserID,
+			Type:       entities.TransactionReversalIn,
+			Status:     entities.StatusCompleted,
+			Amount:     released,
+			Currency:   authorization.Currency,
+			Memo:       memo,
+			ReversalOf: authorization.HoldEntryID,
+		})
+	}
+	entry, _, err = s.ledgerRepository.PostEntry(entry, transactions, ctx)
+	if err != nil {
+		s.reopen(ctx, authorization, entities.CardAuthorizationCleared)
+		return cardnet.Response{}, err
+	}
+	s.releaseCardSpending(ctx, authorization.CardReservation.Unused(cleared.ClearedAmount))
+	if s.limiter != nil {
+		s.limiter.Release(ctx, authorization.LimitReservation.Unused(cleared.ClearedAmount))
+	}
+	cleared.ClearingEntryID = entry.ID
+	if err := s.cardRepository.UpdateAuthorization(cleared, entities.CardAuthorizationCleared, ctx); err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: clear", "Error:", err)
+	}
+	return cardnet.Response{MessageID: message.ID, Code: cardnet.CodeApproved}, nil
+}
+
+// reverse cancels an approved authorization and gives the amount held back
+// to the wallet and to the limits of the card and its user. Declined authorizations held nothing, so their reversal is
+// acknowledged without moving money.
+func (s *cardService) reverse(ctx context.Context, message cardnet.Message) (cardnet.Response, error) {
+	authorization, err := s.cardRepository.GetAuthorizationByMessage(message.AuthorizationID, ctx)
+	if err != nil {
+		return cardnet.Response{}, cardError(err)
+	}
+	switch {
+	case authorization.Status == entities.CardAuthorizationDeclined,
+		authorization.Status == entities.CardAuthorizationReversed && authorization.ReversalMessageID == message.ID:
+		return cardnet.Response{MessageID: message.ID, Code: cardnet.CodeApproved}, nil
+	}
+	if err := openAuthorization(authorization); err != nil {
+		return cardnet.Response{}, err
+	}
+
+	reversed := authorization
+	reversed.Status = entities.CardAuthorizationReversed
+	reversed.ReversalMessageID = message.ID
+	reversed.Update_at = time.Now().UTC()
+	if err := s.claimAuthorization(ctx, reversed, entities.CardAuthorizationApproved); err != nil {
+		return cardnet.Response{}, err
+	}
+	memo := "Card payment at " + authorization.MerchantName + " reversed"
+	entry := entities.LedgerEntry{
+		Type:       entities.EntryReversal,
+		Memo:       memo,
+		ReversalOf: authorization.HoldEntryID,
+		Lines: []entities.LedgerLine{
+			{Account: entities.AccountCardHolds, Currency: authorization.Currency, Amount: -authorization.Amount},
+			{Account: entities.WalletAccount(authorization.WalletID), WalletID: authorization.WalletID, Currency: authorization.Currency, Amount: authorization.Amount},
+		},
+		Created_at: reversed.Update_at,
+	}
+	transactions := []entities.Transaction{
+		{
+			WalletID:   authorization.WalletID,
+			UserID:     authorization.UserID,
+			Type:       entities.TransactionReversalIn,
+			Status:     entities.StatusCompleted,
+			Amount:     authorization.Amount,
+			Currency:   authorization.Currency,
+			Memo:       memo,
+			ReversalOf: authorization.HoldEntryID,
+		},
+	}
+	entry, _, err = s.ledgerRepository.PostEntry(entry, transactions, ctx)
+	if err != nil {
+		s.reopen(ctx, authorization, entities.CardAuthorizationReversed)
+		return cardnet.Response{}, err
+	}
+	s.releaseCardSpending(ctx, authorization.CardReservation)
+	if s.limiter != nil {
+		s.limiter.Release(ctx, authorization.LimitReservation)
+	}
+	reversed.ReversalEntryID = entry.ID
+	if err := s.cardRepository.UpdateAuthorization(reversed, entities.CardAuthorizationReversed, ctx); err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: reverse", "Error:", err)
+	}
+	return cardnet.Response{MessageID: message.ID, Code: cardnet.CodeApproved}, nil
+}
+
+// claimAuthorization moves an authorization out of the from status before
+// its money moves, so a clearing and a reversal cannot both take it.
+func (s *cardService) claimAuthorization(ctx context.Context, authorization entities.CardAuthorization, from string) error {
+	err := s.cardRepository.UpdateAuthorization(authorization, from, ctx)
+	if errors.Is(err, repository_card.ErrAuthorizationStatusChanged) {
+		return ErrCardAuthorizationClosed
+	}
+	return err
+}
+
+// reopen puts back an authorization claimed by a message whose entry could
+// not be posted, so the network can send it again.
+func (s *cardService) reopen(ctx context.Context, authorization entities.CardAuthorization, from string) {
+	if err := s.cardRepository.UpdateAuthorization(authorization, from, ctx); err != nil {
+		s.logger.Errorln("Layer: card_services", "Method: reopen", "Error:", err)
+	}
+}
+
+// ownCard loads a card of the authenticated user. The cards of others are
+// not found.
+func (s *cardService) ownCard(ctx context.Context, email string, cardID string) (entities.Card, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.Card{}, err
+	}
+	card, err := s.cardRepository.GetCard(cardID, ctx)
+	if err != nil {
+		return entities.Card{}, cardError(err)
+	}
+	if card.UserID != user.ID {
+		return entities.Card{}, ErrCardNotFound
+	}
+	return card, nil
+}
+
+// openAuthorization checks an authorization can still be cleared or
+// reversed.
+func openAuthorization(authorization entities.CardAuthorization) error {
+	switch authorization.Status {
+	case entities.CardAuthorizationApproved:
+		return nil
+	case entities.CardAuthorizationPending:
+		return ErrCardAuthorizationPending
+	}
+	return ErrCardAuthorizationClosed
+}
+
+func validCardLimits(limits entities.CardLimits) bool {
+	return limits.PerTransaction >= 0 && limits.Daily >= 0 && limits.Monthly >= 0
+}
+
+// cardError maps the errors of the card repository to the ones of the
+// service.
+func cardError(err error) error {
+	switch {
+	case errors.Is(err, repository_card.ErrCardNotFound):
+		return ErrCardNotFound
+	case errors.Is(err, repository_card.ErrAuthorizationNotFound):
+		return ErrCardAuthorizationNotFound
+	}
+	return err
+}
@@ -0,0 +1,560 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/entities"
+	repository_card "my_wallet/api/respository/card"
+	"my_wallet/api/utils/cardnet"
+	"my_wallet/api/utils/pan"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const cardID = "65f1c0a2b3d4e5f6a7b8c9e0"
+const cardPAN = "4895370000000015"
+
+var cardTokenKey = []byte("token-key")
+var cardNetworkSecret = []byte("network-secret")
+
+// signedCardMessage returns a network message as the network sends it.
+func signedCardMessage(t *testing.T, message cardnet.Message) ([]byte, string) {
+	body, err := json.Marshal(message)
+	assert.NoError(t, err)
+	return body, cardnet.Sign(cardNetworkSecret, body)
+}
+
+func testCard() entities.Card {
+	return entities.Card{
+		ID:          cardID,
+		UserID:      "u1",
+		WalletID:    "w1",
+		Token:       pan.Token(cardTokenKey, cardPAN),
+		Last4:       "0015",
+		ExpiryMonth: 12,
+		ExpiryYear:  time.Now().Year() + 2,
+		Currency:    "COP",
+		Status:      entities.CardActive,
+		Limits:      entities.CardLimits{PerTransaction: 80000, Daily: 100000},
+	}
+}
+
+// testCardSpending is what authorizing 50000 COP took from the limits of the
+// card.
+func testCardSpending() entities.LimitReservation {
+	return entities.LimitReservation{Parts: []entities.LimitReservationPart{
+		{Key: cardID + ":daily:2026-10-19", Kind: entities.LimitDaily, Amount: 50000},
+	}}
+}
+
+// testCardReservation is what authorizing 50000 COP took from the limits of
+// the user.
+func testCardReservation() entities.LimitReservation {
+	return entities.LimitReservation{Parts: []entities.LimitReservationPart{
+		{Key: "u1:COP:hourly:2026-10-19T12", Kind: entities.LimitHourly, Amount: 1},
+		{Key: "u1:COP:daily:2026-10-19", Kind: entities.LimitDaily, Amount: 50000},
+		{Key: "u1:COP:monthly:2026-10", Kind: entities.LimitMonthly, Amount: 50000},
+	}}
+}
+
+func TestIssueCardService(t *testing.T) {
+	testScenarios := []struct {
+		testName      string
+		user          entities.User
+		card          entities.Card
+		createErrors  []error
+		expectedError error
+	}{
+		{
+			testName: "TestIssueCard",
+			user:     entities.User{ID: "u1", KYCLevel: entities.KYCBasic},
+			card:     entities.Card{Name: " Subscriptions ", Limits: entities.CardLimits{Monthly: 200000}},
+		},
+		{
+			testName:     "TestIssueCardNumberTaken",
+			user:         entities.User{ID: "u1", KYCLevel: entities.KYCBasic},
+			card:         entities.Card{Name: "Subscriptions"},
+			createErrors: []error{repository_card.ErrCardExists},
+		},
+		{
+			testName:      "TestIssueCardWithoutKYC",
+			user:          entities.User{ID: "u1"},
+			card:          entities.Card{Name: "Subscriptions"},
+			expectedError: ErrKYCLevelRequired,
+		},
+		{
+			testName:      "TestIssueCardNegativeLimit",
+			user:          entities.User{ID: "u1", KYCLevel: entities.KYCBasic},
+			card:          entities.Card{Limits: entities.CardLimits{Daily: -1}},
+			expectedError: ErrInvalidCard,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "buyer@gmail.com").Return(tt.user, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetOrCreateWallet", mock.Anything, "u1").Return(entities.Wallet{ID: "w1", UserID: "u1", Currency: "COP"}, nil)
+			cards := &cardRepositoryMock{}
+			for _, err := range tt.createErrors {
+				cards.On("CreateCard", mock.Anything, mock.Anything).Return(entities.Card{}, err).Once()
+			}
+			cards.On("CreateCard", mock.Anything, mock.Anything).Return(func(c entities.Card) entities.Card {
+				c.ID = cardID
+				return c
+			}, nil)
+			service := NewCardService(users, wallets, &ledgerRepositoryMock{}, cards, nil, nil, "489537", cardTokenKey, cardNetworkSecret, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.IssueCard(context.Background(), "buyer@gmail.com", tt.card)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, cardID, result.ID)
+				assert.True(t, pan.Valid(result.PAN))
+				assert.Equal(t, pan.Token(cardTokenKey, result.PAN), result.Token)
+				assert.Equal(t, result.PAN[12:], result.Last4)
+				assert.Equal(t, "w1", result.WalletID)
+				assert.Equal(t, "COP", result.Currency)
+				assert.Equal(t, entities.CardActive, result.Status)
+				assert.False(t, result.Expired(time.Now().AddDate(2, 11, 0)))
+				assert.True(t, result.Expired(time.Now().AddDate(3, 1, 0)))
+				cards.AssertNumberOfCalls(t, "CreateCard", len(tt.createErrors)+1)
+				data, _ := json.Marshal(result.Card)
+				assert.NotContains(t, string(data), result.PAN)
+				assert.NotContains(t, string(data), result.Token)
+			} else {
+				cards.AssertNotCalled(t, "CreateCard", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthorizeCardService(t *testing.T) {
+	card := testCard()
+	authorization := cardnet.Message{
+		ID:       "auth-1",
+		Type:     cardnet.TypeAuthorization,
+		PAN:      cardPAN,
+		Expiry:   cardnet.Expiry(card.ExpiryMonth, card.ExpiryYear),
+		Amount:   50000,
+		Currency: "COP",
+		Merchant: &cardnet.Merchant{Name: "Cine Colombia", CategoryCode: "7832", Country: "CO"},
+	}
+
+	testScenarios := []struct {
+		testName       string
+		card           func(c entities.Card) entities.Card
+		message        func(m cardnet.Message) cardnet.Message
+		balance        int64
+		reserveError   error
+		restrictions   []entities.WalletRestriction
+		createError    error
+		signature      string
+		expectedCode   string
+		expectedError  error
+		expectedStatus string
+	}{
+		{
+			testName:       "TestAuthorizeApproved",
+			balance:        500000,
+			expectedCode:   cardnet.CodeApproved,
+			expectedStatus: entities.CardAuthorizationApproved,
+		},
+		{
+			testName:       "TestAuthorizeFrozenCard",
+			card:           func(c entities.Card) entities.Card { c.Status = entities.CardFrozen; return c },
+			balance:        500000,
+			expectedCode:   cardnet.CodeRestrictedCard,
+			expectedStatus: entities.CardAuthorizationDeclined,
+		},
+		{
+			testName:       "TestAuthorizeOverTransactionLimit",
+			message:        func(m cardnet.Message) cardnet.Message { m.Amount = 90000; return m },
+			balance:        500000,
+			expectedCode:   cardnet.CodeLimitExceeded,
+			expectedStatus: entities.CardAuthorizationDeclined,
+		},
+		{
+			testName:       "TestAuthorizeOverDailyLimit",
+			balance:        500000,
+			reserveError:   repository_card.ErrCardLimitReached,
+			expectedCode:   cardnet.CodeLimitExceeded,
+			expectedStatus: entities.CardAuthorizationDeclined,
+		},
+		{
+			testName:       "TestAuthorizeInsufficientFunds",
+			balance:        1000,
+			expectedCode:   cardnet.CodeInsufficientFunds,
+			expectedStatus: entities.CardAuthorizationDeclined,
+		},
+		{
+			testName:       "TestAuthorizeWrongExpiry",
+			message:        func(m cardnet.Message) cardnet.Message { m.Expiry = "0130"; return m },
+			balance:        500000,
+			expectedCode:   cardnet.CodeInvalidCard,
+			expectedStatus: entities.CardAuthorizationDeclined,
+		},
+		{
+			testName:       "TestAuthorizeExpiredCard",
+			card:           func(c entities.Card) entities.Card { c.ExpiryYear = 2020; return c },
+			message:        func(m cardnet.Message) cardnet.Message { m.Expiry = "1220"; return m },
+			balance:        500000,
+			expectedCode:   cardnet.CodeExpiredCard,
+			expectedStatus: entities.CardAuthorizationDeclined,
+		},
+		{
+			testName:       "TestAuthorizeOtherCurrency",
+			message:        func(m cardnet.Message) cardnet.Message { m.Currency = "USD"; return m },
+			balance:        500000,
+			expectedCode:   cardnet.CodeNotPermitted,
+			expectedStatus: entities.CardAuthorizationDeclined,
+		},
+		{
+			testName:       "TestAuthorizeFrozenWallet",
+			balance:        500000,
+			restrictions:   []entities.WalletRestriction{{Kind: entities.RestrictionFreeze}},
+			expectedCode:   cardnet.CodeRestrictedCard,
+			expectedStatus: entities.CardAuthorizationDeclined,
+		},
+		{
+			testName:     "TestAuthorizeUnknownCard",
+			message:      func(m cardnet.Message) cardnet.Message { m.PAN = "4111111111111111"; return m },
+			expectedCode: cardnet.CodeInvalidCard,
+		},
+		{
+			testName:     "TestAuthorizeSentAgain",
+			createError:  repository_card.ErrAuthorizationExists,
+			expectedCode: cardnet.CodeApproved,
+		},
+		{
+			testName:      "TestAuthorizeBadSignature",
+			signature:     cardnet.Sign([]byte("other-secret"), []byte("{}")),
+			expectedError: ErrInvalidCardSignature,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			card := testCard()
+			if tt.card != nil {
+				card = tt.card(card)
+			}
+			message := authorization
+			if tt.message != nil {
+				message = tt.message(message)
+			}
+			body, signature := signedCardMessage(t, message)
+			if tt.signature != "" {
+				signature = tt.signature
+			}
+			users := &userServiceMock{}
+			users.On("GetUser", mock.Anything, "u1").Return(entities.User{ID: "u1", KYCLevel: entities.KYCBasic}, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetWallet", mock.Anything, "w1").Return(entities.Wallet{ID: "w1", UserID: "u1", Balances: map[string]int64{"COP": tt.balance}}, nil)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{ID: "e1"}, nil)
+			cards := &cardRepositoryMock{}
+			cards.On("GetCardByToken", mock.Anything, card.Token).Return(card, nil)
+			cards.On("GetCardByToken", mock.Anything, mock.Anything).Return(entities.Card{}, repository_card.ErrCardNotFound)
+			cards.On("CreateAuthorization", mock.Anything, mock.Anything).Return(func(a entities.CardAuthorization) entities.CardAuthorization {
+				a.ID = "a1"
+				return a
+			}, tt.createError)
+			cards.On("GetAuthorizationByMessage", mock.Anything, "auth-1").Return(entities.CardAuthorization{ID: "a1", Status: entities.CardAuthorizationApproved, Code: cardnet.CodeApproved}, nil)
+			cards.On("ReserveSpending", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, cardID+":daily:") }), int64(50000), int64(100000)).Return(int64(50000), tt.reserveError)
+			cards.On("ReleaseSpending", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			cards.On("UpdateAuthorization", mock.Anything, mock.Anything, entities.CardAuthorizationPending).Return(nil)
+			restrictions := &restrictionRepositoryMock{}
+			restrictions.On("ActiveRestrictions", mock.Anything, "w1").Return(tt.restrictions, nil)
+			restrictionService := NewRestrictionService(users, wallets, restrictions, nil, logrus.StandardLogger(), context.Background())
+			service := NewCardService(users, wallets, ledger, cards, nil, restrictionService, "489537", cardTokenKey, cardNetworkSecret, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.HandleNetworkMessage(context.Background(), body, signature)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedCode, result.Code)
+			if tt.expectedStatus != "" {
+				cards.AssertCalled(t, "UpdateAuthorization", mock.Anything, mock.MatchedBy(func(a entities.CardAuthorization) bool {
+					return a.ID == "a1" && a.Status == tt.expectedStatus && a.Code == tt.expectedCode && a.CardID == cardID && a.MerchantName == "Cine Colombia"
+				}), entities.CardAuthorizationPending)
+			} else {
+				cards.AssertNotCalled(t, "UpdateAuthorization", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.expectedStatus == entities.CardAuthorizationApproved {
+				ledger.AssertCalled(t, "PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+					return e.Type == entities.EntryCardHold && e.Lines[0].WalletID == "w1" && e.Lines[0].Amount == -50000 && e.Lines[1].Account == entities.AccountCardHolds
+				}), mock.MatchedBy(func(transactions []entities.Transaction) bool {
+					return len(transactions) == 1 && transactions[0].Type == entities.TransactionCardPayment && transactions[0].Memo == "Card payment at Cine Colombia"
+				}))
+				cards.AssertCalled(t, "UpdateAuthorization", mock.Anything, mock.MatchedBy(func(a entities.CardAuthorization) bool {
+					return a.HoldEntryID == "e1" && len(a.CardReservation.Parts) == 1 && a.CardReservation.Parts[0].Amount == 50000
+				}), entities.CardAuthorizationPending)
+			} else {
+				ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestClearCardService(t *testing.T) {
+	approved := entities.CardAuthorization{ID: "a1", CardID: cardID, WalletID: "w1", UserID: "u1", MessageID: "auth-1", Amount: 50000, Currency: "COP", MerchantName: "Restaurante", Status: entities.CardAuthorizationApproved, Code: cardnet.CodeApproved, HoldEntryID: "e1", CardReservation: testCardSpending(), LimitReservation: testCardReservation()}
+
+	testScenarios := []struct {
+		testName         string
+		authorization    func(a entities.CardAuthorization) entities.CardAuthorization
+		amount           int64
+		expectedError    error
+		expectedReleased int64
+	}{
+		{
+			testName: "TestClearAuthorizedAmount",
+			amount:   50000,
+		},
+		{
+			testName:         "TestClearLessThanAuthorized",
+			amount:           42000,
+			expectedReleased: 8000,
+		},
+		{
+			testName: "TestClearSentAgain",
+			authorization: func(a entities.CardAuthorization) entities.CardAuthorization {
+				a.Status = entities.CardAuthorizationCleared
+				a.ClearingMessageID = "clear-1"
+				return a
+			},
+			amount: 50000,
+		},
+		{
+			testName:      "TestClearMoreThanAuthorized",
+			amount:        60000,
+			expectedError: ErrInvalidCardMessage,
+		},
+		{
+			testName: "TestClearReversedAuthorization",
+			authorization: func(a entities.CardAuthorization) entities.CardAuthorization {
+				a.Status = entities.CardAuthorizationReversed
+				return a
+			},
+			amount:        50000,
+			expectedError: ErrCardAuthorizationClosed,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			authorization := approved
+			if tt.authorization != nil {
+				authorization = tt.authorization(authorization)
+			}
+			body, signature := signedCardMessage(t, cardnet.Message{ID: "clear-1", Type: cardnet.TypeClearing, AuthorizationID: "auth-1", Amount: tt.amount, Currency: "COP"})
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{ID: "e2"}, nil)
+			cards := &cardRepositoryMock{}
+			cards.On("GetAuthorizationByMessage", mock.Anything, "auth-1").Return(authorization, nil)
+			cards.On("UpdateAuthorization", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			cards.On("ReleaseSpending", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			limits := &limitRepositoryMock{}
+			limits.On("Release", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			limiter := NewLimitService(&userServiceMock{}, limits, nil, logrus.StandardLogger(), context.Background())
+			service := NewCardService(&userServiceMock{}, &walletRepositoryMock{}, ledger, cards, limiter, nil, "489537", cardTokenKey, cardNetworkSecret, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.HandleNetworkMessage(context.Background(), body, signature)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError != nil {
+				ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, cardnet.CodeApproved, result.Code)
+			if tt.authorization != nil {
+				ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			cards.AssertCalled(t, "UpdateAuthorization", mock.Anything, mock.MatchedBy(func(a entities.CardAuthorization) bool {
+				return a.Status == entities.CardAuthorizationCleared && a.ClearedAmount == tt.amount && a.ClearingMessageID == "clear-1"
+			}), entities.CardAuthorizationApproved)
+			ledger.AssertCalled(t, "PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+				var holds, network, wallet int64
+				for _, line := range e.Lines {
+					switch line.Account {
+					case entities.AccountCardHolds:
+						holds += line.Amount
+					case entities.AccountCardNetwork:
+						network += line.Amount
+					case entities.WalletAccount("w1"):
+						wallet += line.Amount
+					}
+				}
+				return e.Type == entities.EntryCardClearing && holds == -50000 && network == tt.amount && wallet == tt.expectedReleased
+			}), mock.MatchedBy(func(transactions []entities.Transaction) bool {
+				if tt.expectedReleased == 0 {
+					return len(transactions) == 0
+				}
+				return len(transactions) == 1 && transactions[0].Type == entities.TransactionReversalIn && transactions[0].Amount == tt.expectedReleased && transactions[0].ReversalOf == "e1"
+			}))
+			cards.AssertCalled(t, "UpdateAuthorization", mock.Anything, mock.MatchedBy(func(a entities.CardAuthorization) bool {
+				return a.ClearingEntryID == "e2"
+			}), entities.CardAuthorizationCleared)
+			// Only what was held over the amount cleared goes back; the
+			// purchase still counts as a transaction.
+			if tt.expectedReleased == 0 {
+				cards.AssertNotCalled(t, "ReleaseSpending", mock.Anything, mock.Anything, mock.Anything)
+				limits.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				cards.AssertCalled(t, "ReleaseSpending", mock.Anything, cardID+":daily:2026-10-19", tt.expectedReleased)
+				limits.AssertNumberOfCalls(t, "Release", 2)
+				limits.AssertCalled(t, "Release", mock.Anything, "u1:COP:daily:2026-10-19", tt.expectedReleased)
+				limits.AssertCalled(t, "Release", mock.Anything, "u1:COP:monthly:2026-10", tt.expectedReleased)
+			}
+		})
+	}
+}
+
+func TestReverseCardService(t *testing.T) {
+	approved := entities.CardAuthorization{ID: "a1", CardID: cardID, WalletID: "w1", UserID: "u1", MessageID: "auth-1", Amount: 50000, Currency: "COP", MerchantName: "Restaurante", Status: entities.CardAuthorizationApproved, Code: cardnet.CodeApproved, HoldEntryID: "e1", CardReservation: testCardSpending(), LimitReservation: testCardReservation()}
+
+	testScenarios := []struct {
+		testName      string
+		status        string
+		updateError   error
+		expectedError error
+		expectedPost  bool
+	}{
+		{
+			testName:     "TestReverseApproved",
+			status:       entities.CardAuthorizationApproved,
+			expectedPost: true,
+		},
+		{
+			testName: "TestReverseDeclined",
+			status:   entities.CardAuthorizationDeclined,
+		},
+		{
+			testName:      "TestReverseCleared",
+			status:        entities.CardAuthorizationCleared,
+			expectedError: ErrCardAuthorizationClosed,
+		},
+		{
+			testName:      "TestReversePending",
+			status:        entities.CardAuthorizationPending,
+			expectedError: ErrCardAuthorizationPending,
+		},
+		{
+			testName:      "TestReverseClearedMeanwhile",
+			status:        entities.CardAuthorizationApproved,
+			updateError:   repository_card.ErrAuthorizationStatusChanged,
+			expectedError: ErrCardAuthorizationClosed,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			authorization := approved
+			authorization.Status = tt.status
+			body, signature := signedCardMessage(t, cardnet.Message{ID: "reverse-1", Type: cardnet.TypeReversal, AuthorizationID: "auth-1"})
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{ID: "e3"}, nil)
+			cards := &cardRepositoryMock{}
+			cards.On("GetAuthorizationByMessage", mock.Anything, "auth-1").Return(authorization, nil)
+			cards.On("UpdateAuthorization", mock.Anything, mock.Anything, entities.CardAuthorizationApproved).Return(tt.updateError)
+			cards.On("UpdateAuthorization", mock.Anything, mock.Anything, entities.CardAuthorizationReversed).Return(nil)
+			cards.On("ReleaseSpending", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			limits := &limitRepositoryMock{}
+			limits.On("Release", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			limiter := NewLimitService(&userServiceMock{}, limits, nil, logrus.StandardLogger(), context.Background())
+			service := NewCardService(&userServiceMock{}, &walletRepositoryMock{}, ledger, cards, limiter, nil, "489537", cardTokenKey, cardNetworkSecret, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.HandleNetworkMessage(context.Background(), body, signature)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, cardnet.CodeApproved, result.Code)
+			}
+			if tt.expectedPost {
+				ledger.AssertCalled(t, "PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+					return e.Type == entities.EntryReversal && e.ReversalOf == "e1" && e.Lines[1].WalletID == "w1" && e.Lines[1].Amount == 50000
+				}), mock.Anything)
+				cards.AssertCalled(t, "UpdateAuthorization", mock.Anything, mock.MatchedBy(func(a entities.CardAuthorization) bool {
+					return a.Status == entities.CardAuthorizationReversed && a.ReversalEntryID == "e3"
+				}), entities.CardAuthorizationReversed)
+				cards.AssertCalled(t, "ReleaseSpending", mock.Anything, cardID+":daily:2026-10-19", int64(50000))
+				limits.AssertCalled(t, "Release", mock.Anything, "u1:COP:hourly:2026-10-19T12", int64(1))
+				limits.AssertCalled(t, "Release", mock.Anything, "u1:COP:daily:2026-10-19", int64(50000))
+				limits.AssertCalled(t, "Release", mock.Anything, "u1:COP:monthly:2026-10", int64(50000))
+			} else {
+				ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+				cards.AssertNotCalled(t, "ReleaseSpending", mock.Anything, mock.Anything, mock.Anything)
+				limits.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestFreezeCardService(t *testing.T) {
+	testScenarios := []struct {
+		testName       string
+		email          string
+		frozen         bool
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			testName:       "TestFreezeCard",
+			email:          "buyer@gmail.com",
+			frozen:         true,
+			expectedStatus: entities.CardFrozen,
+		},
+		{
+			testName:       "TestUnfreezeCard",
+			email:          "buyer@gmail.com",
+			expectedStatus: entities.CardActive,
+		},
+		{
+			testName:      "TestFreezeCardOfAnotherUser",
+			email:         "other@gmail.com",
+			frozen:        true,
+			expectedError: ErrCardNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "buyer@gmail.com").Return(entities.User{ID: "u1"}, nil)
+			users.On("GetUserByEmail", mock.Anything, "other@gmail.com").Return(entities.User{ID: "u2"}, nil)
+			cards := &cardRepositoryMock{}
+			cards.On("GetCard", mock.Anything, cardID).Return(testCard(), nil)
+			cards.On("UpdateCard", mock.Anything, mock.Anything).Return(func(c entities.Card) entities.Card { return c }, nil)
+			service := NewCardService(users, &walletRepositoryMock{}, &ledgerRepositoryMock{}, cards, nil, nil, "489537", cardTokenKey, cardNetworkSecret, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.FreezeCard(context.Background(), tt.email, cardID, tt.frozen)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			if tt.expectedError != nil {
+				cards.AssertNotCalled(t, "UpdateCard", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type categoryRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *categoryRepositoryMock) ListCategories(userID string, ctx context.Context) ([]entities.Category, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).([]entities.Category), r.Error(1)
+}
+
+func (m *categoryRepositoryMock) CreateCategory(category entities.Category, ctx context.Context) (entities.Category, error) {
+	r := m.Called(ctx, category)
+	return r.Get(0).(entities.Category), r.Error(1)
+}
+
+func (m *categoryRepositoryMock) ListRules(userID string, ctx context.Context) ([]entities.CategoryRule, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).([]entities.CategoryRule), r.Error(1)
+}
+
+func (m *categoryRepositoryMock) CreateRule(rule entities.CategoryRule, ctx context.Context) (entities.CategoryRule, error) {
+	r := m.Called(ctx, rule)
+	return r.Get(0).(entities.CategoryRule), r.Error(1)
+}
+
+func (m *categoryRepositoryMock) DeleteRule(id string, userID string, ctx context.Context) error {
+	r := m.Called(ctx, id, userID)
+	return r.Error(0)
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_category "my_wallet/api/respository/category"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxCategoryNameLength = 40
+	maxRuleKeywords       = 10
+)
+
+// ruleTransactionTypes are the transactions users can write rules for. The
+// others are categorized by type.
+var ruleTransactionTypes = map[string]bool{
+	entities.TransactionDeposit:     true,
+	entities.TransactionWithdrawal:  true,
+	entities.TransactionTransferIn:  true,
+	entities.TransactionTransferOut: true,
+	entities.TransactionPaymentOut:  true,
+	entities.TransactionPaymentIn:   true,
+	entities.TransactionCardPayment: true,
+}
+
+// Categorizer assigns a category to transactions before they are posted.
+type Categorizer interface {
+	Categorize(ctx context.Context, transactions []entities.Transaction)
+}
+
+type CategoryService interface {
+	Categorizer
+	ListCategories(ctx context.Context, email string) ([]entities.Category, error)
+	CreateCategory(ctx context.Context, email string, name string) (entities.Category, error)
+	ListRules(ctx context.Context, email string) ([]entities.CategoryRule, error)
+	CreateRule(ctx context.Context, email string, rule entities.CategoryRule) (entities.CategoryRule, error)
+	DeleteRule(ctx context.Context, email string, id string) error
+	Recategorize(ctx context.Context, email string, walletID string, transactionID string, category string, learn bool) (entities.Transaction, error)
+}
+
+type categoryService struct {
+	ctx                   context.Context
+	userRepository        repository_user.UserRepository
+	walletRepository      repository_wallet.WalletRepository
+	transactionRepository repository_ledger.TransactionRepository
+	categoryRepository    repository_category.CategoryRepository
+	members               Memberships
+	logger                logrus.FieldLogger
+}
+
+func NewCategoryService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, transactionRepo repository_ledger.TransactionRepository, categoryRepo repository_category.CategoryRepository, members Memberships, logger logrus.FieldLogger, ctx context.Context) *categoryService {
+	return &categoryService{
+		ctx:                   ctx,
+		userRepository:        userRepo,
+		walletRepository:      walletRepo,
+		transactionRepository: transactionRepo,
+		categoryRepository:    categoryRepo,
+		members:               members,
+		logger:                logger,
+	}
+}
+
+// ListCategories returns the built-in categories followed by the custom
+// categories of the authenticated user.
+func (s *categoryService) ListCategories(ctx context.Context, email string) ([]entities.Category, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: ListCategories", "Error:", err)
+		return nil, err
+	}
+	custom, err := s.categoryRepository.ListCategories(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: ListCategories", "Error:", err)
+		return nil, err
+	}
+	categories := append([]entities.Category{}, entities.DefaultCategories...)
+	return append(categories, custom...), nil
+}
+
+// CreateCategory adds a custom category whose slug is made from its name.
+func (s *categoryService) CreateCategory(ctx context.Context, email string, name string) (entities.Category, error) {
+	name = strings.TrimSpace(name)
+	slug := categorySlug(name)
+	if utf8.RuneCountInString(name) > maxCategoryNameLength || slug == "" {
+		s.logger.Errorln("Layer: category_services", "Method: CreateCategory", "Error:", ErrInvalidCategory)
+		return entities.Category{}, ErrInvalidCategory
+	}
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: CreateCategory", "Error:", err)
+		return entities.Category{}, err
+	}
+	if isDefaultCategory(slug) {
+		s.logger.Errorln("Layer: category_services", "Method: CreateCategory", "Error:", ErrCategoryExists)
+		return entities.Category{}, ErrCategoryExists
+	}
+	category, err := s.categoryRepository.CreateCategory(entities.Category{
+		UserID:     user.ID,
+		Slug:       slug,
+		Name:       name,
+		Created_at: time.Now().UTC(),
+	}, ctx)
+	if errors.Is(err, repository_category.ErrCategoryExists) {
+		s.logger.Errorln("Layer: category_services", "Method: CreateCategory", "Error:", err)
+		return entities.Category{}, ErrCategoryExists
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: CreateCategory", "Error:", err)
+		return entities.Category{}, err
+	}
+	return category, nil
+}
+
+// ListRules returns the rules of the authenticated user in evaluation order.
+func (s *categoryService) ListRules(ctx context.Context, email string) ([]entities.CategoryRule, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: ListRules", "Error:", err)
+		return nil, err
+	}
+	rules, err := s.categoryRepository.ListRules(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: ListRules", "Error:", err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateRule adds a categorization rule applied to transactions posted from
+// now on.
+func (s *categoryService) CreateRule(ctx context.Context, email string, rule entities.CategoryRule) (entities.CategoryRule, error) {
+	if err := validateCategoryRule(rule); err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: CreateRule", "Error:", err)
+		return entities.CategoryRule{}, err
+	}
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: CreateRule", "Error:", err)
+		return entities.CategoryRule{}, err
+	}
+	if err := knownCategory(ctx, s.categoryRepository, user.ID, rule.Category); err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: CreateRule", "Error:", err)
+		return entities.CategoryRule{}, err
+	}
+	rule.ID = ""
+	rule.UserID = user.ID
+	rule.Learned = false
+	rule.Created_at = time.Now().UTC()
+	rule, err = s.categoryRepository.CreateRule(rule, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: CreateRule", "Error:", err)
+		return entities.CategoryRule{}, err
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes a rule of the authenticated user.
+func (s *categoryService) DeleteRule(ctx context.Context, email string, id string) error {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: DeleteRule", "Error:", err)
+		return err
+	}
+	err = s.categoryRepository.DeleteRule(id, user.ID, ctx)
+	if errors.Is(err, repository_category.ErrRuleNotFound) {
+		s.logger.Errorln("Layer: category_services", "Method: DeleteRule", "Error:", err)
+		return ErrCategoryRuleNotFound
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: DeleteRule", "Error:", err)
+		return err
+	}
+	return nil
+}
+
+// Recategorize changes the category of a transaction of the user's wallet.
+// With learn, a rule is also created so that future transactions with the
+// same counterparty, or failing that the same memo, get the same category.
+func (s *categoryService) Recategorize(ctx context.Context, email string, walletID string, transactionID string, category string, learn bool) (entities.Transaction, error) {
+	user, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, walletID, entities.PermissionSpend)
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: Recategorize", "Error:", err)
+		return entities.Transaction{}, err
+	}
+	transaction, err := s.transactionRepository.GetTransaction(transactionID, ctx)
+	if errors.Is(err, repository_ledger.ErrTransactionNotFound) || (err == nil && transaction.WalletID != wallet.ID) {
+		s.logger.Errorln("Layer: category_services", "Method: Recategorize", "Error:", ErrTransactionNotFound)
+		return entities.Transaction{}, ErrTransactionNotFound
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: Recategorize", "Error:", err)
+		return entities.Transaction{}, err
+	}
+	if err := knownCategory(ctx, s.categoryRepository, user.ID, category); err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: Recategorize", "Error:", err)
+		return entities.Transaction{}, err
+	}
+	var rule entities.CategoryRule
+	if learn {
+		if rule, err = learnRule(transaction, category); err != nil {
+			s.logger.Errorln("Layer: category_services", "Method: Recategorize", "Error:", err)
+			return entities.Transaction{}, err
+		}
+	}
+
+	if err := s.transactionRepository.SetCategory(transaction.ID, category, ctx); err != nil {
+		s.logger.Errorln("Layer: category_services", "Method: Recategorize", "Error:", err)
+		return entities.Transaction{}, err
+	}
+	transaction.Category = category
+	if learn {
+		rule.UserID = user.ID
+		rule.Created_at = time.Now().UTC()
+		if _, err := s.categoryRepository.CreateRule(rule, ctx); err != nil {
+			s.logger.Errorln("Layer: category_services", "Method: Recategorize", "Error:", err)
+			return entities.Transaction{}, err
+		}
+	}
+	return transaction, nil
+}
+
+// Categorize fills in the category of the transactions that do not have one,
+// using the rules of their user and then the type of the transaction. Rules
+// that cannot be loaded are logged and the type is used instead, so posting
+// never fails because of categorization.
+func (s *categoryService) Categorize(ctx context.Context, transactions []entities.Transaction) {
+	rules := map[string][]entities.CategoryRule{}
+	for i, transaction := range transactions {
+		if transaction.Category != "" {
+			continue
+		}
+		if _, ok := rules[transaction.UserID]; !ok && ruleTransactionTypes[transaction.Type] {
+			userRules, err := s.categoryRepository.ListRules(transaction.UserID, ctx)
+			if err != nil {
+				s.logger.Errorln("Layer: category_services", "Method: Categorize", "Error:", err)
+			}
+			rules[transaction.UserID] = userRules
+		}
+		transactions[i].Category = categoryFor(transaction, rules[transaction.UserID])
+	}
+}
+
+// knownCategory checks that slug is a built-in category or a custom category
+// of the user.
+func knownCategory(ctx context.Context, categories repository_category.CategoryRepository, userID string, slug string) error {
+	if isDefaultCategory(slug) {
+		return nil
+	}
+	custom, err := categories.ListCategories(userID, ctx)
+	if err != nil {
+		return err
+	}
+	for _, category := range custom {
+		if category.Slug == slug {
+			return nil
+		}
+	}
+	return ErrUnknownCategory
+}
+
+// categoryFor returns the category of the first rule, in evaluation order,
+// that matches the transaction, or the category of its type.
+func categoryFor(transaction entities.Transaction, rules []entities.CategoryRule) string {
+	if ruleTransactionTypes[transaction.Type] {
+		for _, rule := range rules {
+			if rule.Matches(transaction) {
+				return rule.Category
+			}
+		}
+	}
+	switch transaction.Type {
+	case entities.TransactionFee:
+		return entities.CategoryFees
+	case entities.TransactionToPocket, entities.TransactionFromPocket:
+		return entities.CategorySavings
+	case entities.TransactionConversionIn, entities.TransactionConversionOut, entities.TransactionReversalIn, entities.TransactionReversalOut:
+		return entities.CategoryTransfers
+	case entities.TransactionDeposit, entities.TransactionTransferIn, entities.TransactionPaymentIn, entities.TransactionInterest:
+		return entities.CategoryIncome
+	default:
+		return entities.CategoryOther
+	}
+}
+
+// learnRule builds the rule that would have given the transaction the
+// category: by counterparty when it has one, otherwise by its memo.
+func learnRule(transaction entities.Transaction, category string) (entities.CategoryRule, error) {
+	rule := entities.CategoryRule{Category: category, Learned: true}
+	memo := strings.ToLower(strings.TrimSpace(transaction.Memo))
+	switch {
+	case transaction.CounterpartyUserID != "":
+		rule.CounterpartyUserID = transaction.CounterpartyUserID
+	case memo != "":
+		rule.Keywords = []string{memo}
+	default:
+		return entities.CategoryRule{}, ErrCannotLearnRule
+	}
+	return rule, nil
+}
+
+func validateCategoryRule(rule entities.CategoryRule) error {
+	if rule.Category == "" {
+		return ErrUnknownCategory
+	}
+	if rule.CounterpartyUserID == "" && len(rule.Keywords) == 0 && rule.MinAmount == 0 && rule.MaxAmount == 0 {
+		return ErrInvalidCategoryRule
+	}
+	if len(rule.Keywords) > maxRuleKeywords || rule.MinAmount < 0 || rule.MaxAmount < 0 {
+		return ErrInvalidCategoryRule
+	}
+	if rule.MaxAmount > 0 && rule.MinAmount > rule.MaxAmount {
+		return ErrInvalidCategoryRule
+	}
+	for _, keyword := range rule.Keywords {
+		if strings.TrimSpace(keyword) == "" {
+			return ErrInvalidCategoryRule
+		}
+	}
+	return nil
+}
+
+func isDefaultCategory(slug string) bool {
+	for _, category := range entities.DefaultCategories {
+		if category.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// categorySlug lowercases the name and joins its words with dashes, keeping
+// only letters and digits.
+func categorySlug(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
+
+// categorizedLedger categorizes the transactions of every entry before it is
+// posted.
+type categorizedLedger struct {
+	repository_ledger.LedgerRepository
+	categorizer Categorizer
+}
+
+// NewCategorizedLedger wraps a ledger repository so that every posted
+// transaction carries a category.
+func NewCategorizedLedger(ledger repository_ledger.LedgerRepository, categorizer Categorizer) repository_ledger.LedgerRepository {
+	return &categorizedLedger{LedgerRepository: ledger, categorizer: categorizer}
+}
+
+func (l *categorizedLedger) PostEntry(entry entities.LedgerEntry, transactions []entities.Transaction, ctx context.Context) (entities.LedgerEntry, []entities.Transaction, error) {
+	l.categorizer.Categorize(ctx, transactions)
+	return l.LedgerRepository.PostEntry(entry, transactions, ctx)
+}
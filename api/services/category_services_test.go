@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_ledger "my_wallet/api/respository/ledger"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategorize(t *testing.T) {
+	rules := []entities.CategoryRule{
+		{Category: entities.CategoryRent, CounterpartyUserID: "landlord", MinAmount: 100000},
+		{Category: entities.CategoryGroceries, Keywords: []string{"market", "super"}},
+	}
+
+	testScenarios := []struct {
+		testName    string
+		transaction entities.Transaction
+		expected    string
+	}{
+		{
+			testName:    "counterparty and amount rule",
+			transaction: entities.Transaction{UserID: "u1", Type: entities.TransactionTransferOut, Amount: 150000, CounterpartyUserID: "landlord"},
+			expected:    entities.CategoryRent,
+		},
+		{
+			testName:    "amount below the rule minimum",
+			transaction: entities.Transaction{UserID: "u1", Type: entities.TransactionTransferOut, Amount: 5000, CounterpartyUserID: "landlord"},
+			expected:    entities.CategoryOther,
+		},
+		{
+			testName:    "keyword in the memo",
+			transaction: entities.Transaction{UserID: "u1", Type: entities.TransactionTransferOut, Amount: 5000, Memo: "Weekly SUPER shopping"},
+			expected:    entities.CategoryGroceries,
+		},
+		{
+			testName:    "incoming transfer without rule",
+			transaction: entities.Transaction{UserID: "u1", Type: entities.TransactionTransferIn, Amount: 5000},
+			expected:    entities.CategoryIncome,
+		},
+		{
+			testName:    "fees ignore rules",
+			transaction: entities.Transaction{UserID: "u1", Type: entities.TransactionFee, Amount: 100, Memo: "market"},
+			expected:    entities.CategoryFees,
+		},
+		{
+			testName:    "pocket moves are savings",
+			transaction: entities.Transaction{UserID: "u1", Type: entities.TransactionToPocket, Amount: 100},
+			expected:    entities.CategorySavings,
+		},
+		{
+			testName:    "category already set",
+			transaction: entities.Transaction{UserID: "u1", Type: entities.TransactionTransferOut, Amount: 150000, CounterpartyUserID: "landlord", Category: entities.CategoryTravel},
+			expected:    entities.CategoryTravel,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			categories := &categoryRepositoryMock{}
+			categories.On("ListRules", mock.Anything, "u1").Return(rules, nil)
+			service := NewCategoryService(&userServiceMock{}, &walletRepositoryMock{}, &transactionRepositoryMock{}, categories, nil, logrus.StandardLogger(), context.Background())
+			transactions := []entities.Transaction{tt.transaction}
+
+			// Act
+			service.Categorize(context.Background(), transactions)
+
+			// Assert
+			assert.Equal(t, tt.expected, transactions[0].Category)
+		})
+	}
+}
+
+func TestRecategorizeService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	wallet := entities.Wallet{ID: "w1", UserID: "u1", Currency: "COP"}
+	custom := []entities.Category{{ID: "c1", UserID: "u1", Slug: "pets", Name: "Pets"}}
+
+	testScenarios := []struct {
+		testName      string
+		transaction   entities.Transaction
+		category      string
+		learn         bool
+		expectedRule  *entities.CategoryRule
+		expectedError error
+	}{
+		{
+			testName:    "TestRecategorize",
+			transaction: entities.Transaction{ID: "t1", WalletID: "w1", Type: entities.TransactionTransferOut, Memo: "Vet"},
+			category:    "pets",
+		},
+		{
+			testName:     "TestLearnFromCounterparty",
+			transaction:  entities.Transaction{ID: "t1", WalletID: "w1", Type: entities.TransactionTransferOut, CounterpartyUserID: "u2", Memo: "Vet"},
+			category:     "pets",
+			learn:        true,
+			expectedRule: &entities.CategoryRule{UserID: "u1", Category: "pets", CounterpartyUserID: "u2", Learned: true},
+		},
+		{
+			testName:     "TestLearnFromMemo",
+			transaction:  entities.Transaction{ID: "t1", WalletID: "w1", Type: entities.TransactionWithdrawal, Memo: " Vet Clinic "},
+			category:     entities.CategoryHealth,
+			learn:        true,
+			expectedRule: &entities.CategoryRule{UserID: "u1", Category: entities.CategoryHealth, Keywords: []string{"vet clinic"}, Learned: true},
+		},
+		{
+			testName:      "TestNothingToLearn",
+			transaction:   entities.Transaction{ID: "t1", WalletID: "w1", Type: entities.TransactionWithdrawal},
+			category:      entities.CategoryHealth,
+			learn:         true,
+			expectedError: ErrCannotLearnRule,
+		},
+		{
+			testName:      "TestUnknownCategory",
+			transaction:   entities.Transaction{ID: "t1", WalletID: "w1", Type: entities.TransactionTransferOut},
+			category:      "gadgets",
+			expectedError: ErrUnknownCategory,
+		},
+		{
+			testName:      "TestTransactionOfAnotherWallet",
+			transaction:   entities.Transaction{ID: "t1", WalletID: "w2", Type: entities.TransactionTransferOut},
+			category:      "pets",
+			expectedError: ErrTransactionNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+			transactions := &transactionRepositoryMock{}
+			transactions.On("GetTransaction", mock.Anything, "t1").Return(tt.transaction, nil)
+			transactions.On("SetCategory", mock.Anything, "t1", tt.category).Return(nil)
+			categories := &categoryRepositoryMock{}
+			categories.On("ListCategories", mock.Anything, "u1").Return(custom, nil)
+			var learned entities.CategoryRule
+			categories.On("CreateRule", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				learned = args.Get(1).(entities.CategoryRule)
+			}).Return(entities.CategoryRule{}, nil)
+			service := NewCategoryService(users, wallets, transactions, categories, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.Recategorize(context.Background(), "alexer@gmail.com", "w1", "t1", tt.category, tt.learn)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError != nil {
+				transactions.AssertNotCalled(t, "SetCategory", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, tt.category, result.Category)
+			if tt.expectedRule == nil {
+				categories.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+				return
+			}
+			learned.Created_at = tt.expectedRule.Created_at
+			assert.Equal(t, *tt.expectedRule, learned)
+		})
+	}
+}
+
+func TestCategorizedLedger(t *testing.T) {
+	// Prepare
+	categories := &categoryRepositoryMock{}
+	categories.On("ListRules", mock.Anything, "u1").Return([]entities.CategoryRule{}, nil)
+	ledger := &ledgerRepositoryMock{}
+	ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{}, nil)
+	categorizer := NewCategoryService(&userServiceMock{}, &walletRepositoryMock{}, &transactionRepositoryMock{}, categories, nil, logrus.StandardLogger(), context.Background())
+	var postingLedger repository_ledger.LedgerRepository = NewCategorizedLedger(ledger, categorizer)
+
+	// Act
+	_, _, err := postingLedger.PostEntry(entities.LedgerEntry{}, []entities.Transaction{{UserID: "u1", Type: entities.TransactionDeposit}}, context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	posted := ledger.Calls[0].Arguments.Get(2).([]entities.Transaction)
+	assert.Equal(t, entities.CategoryIncome, posted[0].Category)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type disputeRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *disputeRepositoryMock) CreateDispute(dispute entities.Dispute, ctx context.Context) (entities.Dispute, error) {
+	r := m.Called(ctx, dispute)
+	return r.Get(0).(entities.Dispute), r.Error(1)
+}
+
+func (m *disputeRepositoryMock) GetDispute(id string, ctx context.Context) (entities.Dispute, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.Dispute), r.Error(1)
+}
+
+func (m *disputeRepositoryMock) ListDisputes(userID string, limit int64, ctx context.Context) ([]entities.Dispute, error) {
+	r := m.Called(ctx, userID, limit)
+	return r.Get(0).([]entities.Dispute), r.Error(1)
+}
+
+func (m *disputeRepositoryMock) ListDisputesByStatus(status string, limit int64, ctx context.Context) ([]entities.Dispute, error) {
+	r := m.Called(ctx, status, limit)
+	return r.Get(0).([]entities.Dispute), r.Error(1)
+}
+
+func (m *disputeRepositoryMock) AddEvidence(id string, evidence entities.DisputeEvidence, ctx context.Context) (entities.Dispute, error) {
+	r := m.Called(ctx, id, evidence)
+	return r.Get(0).(entities.Dispute), r.Error(1)
+}
+
+func (m *disputeRepositoryMock) UpdateStatus(id string, from string, to string, reviewer string, resolution string, ctx context.Context) (entities.Dispute, error) {
+	r := m.Called(ctx, id, from, to, reviewer, resolution)
+	return r.Get(0).(entities.Dispute), r.Error(1)
+}
+
+func (m *disputeRepositoryMock) SetReversal(id string, entryID string, amount int64, ctx context.Context) (entities.Dispute, error) {
+	r := m.Called(ctx, id, entryID, amount)
+	return r.Get(0).(entities.Dispute), r.Error(1)
+}
@@ -0,0 +1,468 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my_wallet/api/entities"
+	repository_blob "my_wallet/api/respository/blob"
+	repository_dispute "my_wallet/api/respository/dispute"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_user "my_wallet/api/respository/user"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxDisputes                 = 100
+	maxDisputeDescriptionLength = 1000
+	maxDisputeEvidence          = 10
+)
+
+var disputeReasons = map[string]bool{
+	entities.DisputeWrongRecipient: true,
+	entities.DisputeWrongAmount:    true,
+	entities.DisputeUnauthorized:   true,
+	entities.DisputeNotReceived:    true,
+	entities.DisputeOther:          true,
+}
+
+type DisputeService interface {
+	OpenDispute(ctx context.Context, email string, transactionID string, reason string, description string) (entities.Dispute, error)
+	ListDisputes(ctx context.Context, email string) ([]entities.Dispute, error)
+	GetDispute(ctx context.Context, email string, disputeID string) (entities.Dispute, error)
+	AddEvidence(ctx context.Context, email string, disputeID string, name string, contentType string, data []byte) (entities.Dispute, error)
+	GetEvidence(ctx context.Context, email string, disputeID string, evidenceID string) (entities.DisputeEvidence, []byte, error)
+	ListDisputeQueue(ctx context.Context, email string, status string) ([]entities.Dispute, error)
+	ReviewDispute(ctx context.Context, email string, disputeID string) (entities.Dispute, error)
+	ResolveDispute(ctx context.Context, email string, disputeID string, inFavor bool, amount int64, note string) (entities.Dispute, error)
+}
+
+type disputeService struct {
+	ctx                   context.Context
+	userRepository        repository_user.UserRepository
+	transactionRepository repository_ledger.TransactionRepository
+	ledgerRepository      repository_ledger.LedgerRepository
+	disputeRepository     repository_dispute.DisputeRepository
+	blobs                 repository_blob.BlobStore
+	notifier              Notifier
+	restrictions          Restrictions
+	logger                logrus.FieldLogger
+}
+
+func NewDisputeService(userRepo repository_user.UserRepository, transactionRepo repository_ledger.TransactionRepository, ledgerRepo repository_ledger.LedgerRepository, disputeRepo repository_dispute.DisputeRepository, blobs repository_blob.BlobStore, notifier Notifier, restrictions Restrictions, logger logrus.FieldLogger, ctx context.Context) *disputeService {
+	return &disputeService{
+		ctx:                   ctx,
+		userRepository:        userRepo,
+		transactionRepository: transactionRepo,
+		ledgerRepository:      ledgerRepo,
+		disputeRepository:     disputeRepo,
+		blobs:                 blobs,
+		notifier:              notifier,
+		restrictions:          restrictions,
+		logger:                logger,
+	}
+}
+
+// OpenDispute lets the authenticated user dispute a completed transfer they
+// sent or merchant payment they made. A transaction is disputed once at most.
+func (s *disputeService) OpenDispute(ctx context.Context, email string, transactionID string, reason string, description string) (entities.Dispute, error) {
+	if !disputeReasons[reason] {
+		s.logger.Errorln("Layer: dispute_services", "Method: OpenDispute", "Error:", ErrInvalidDisputeReason)
+		return entities.Dispute{}, ErrInvalidDisputeReason
+	}
+	if utf8.RuneCountInString(description) > maxDisputeDescriptionLength {
+		s.logger.Errorln("Layer: dispute_services", "Method: OpenDispute", "Error:", ErrMemoTooLong)
+		return entities.Dispute{}, ErrMemoTooLong
+	}
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: OpenDispute", "Error:", err)
+		return entities.Dispute{}, err
+	}
+	transaction, err := s.transactionRepository.GetTransaction(transactionID, ctx)
+	if errors.Is(err, repository_ledger.ErrTransactionNotFound) || (err == nil && transaction.UserID != user.ID) {
+		s.logger.Errorln("Layer: dispute_services", "Method: OpenDispute", "Error:", ErrTransactionNotFound)
+		return entities.Dispute{}, ErrTransactionNotFound
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: OpenDispute", "Error:", err)
+		return entities.Dispute{}, err
+	}
+	if (transaction.Type != entities.TransactionTransferOut && transaction.Type != entities.TransactionPaymentOut) || transaction.Status != entities.StatusCompleted {
+		s.logger.Errorln("Layer: dispute_services", "Method: OpenDispute", "Error:", ErrTransactionNotDisputable)
+		return entities.Dispute{}, ErrTransactionNotDisputable
+	}
+
+	now := time.Now().UTC()
+	dispute, err := s.disputeRepository.CreateDispute(entities.Dispute{
+		UserID:               user.ID,
+		WalletID:             transaction.WalletID,
+		TransactionID:        transaction.ID,
+		EntryID:              transaction.EntryID,
+		CounterpartyWalletID: transaction.CounterpartyWalletID,
+		CounterpartyUserID:   transaction.CounterpartyUserID,
+		Amount:               transaction.Amount,
+		Currency:             transaction.Currency,
+		Reason:               reason,
+		Description:          description,
+		Status:               entities.DisputeOpened,
+		Evidence:             []entities.DisputeEvidence{},
+		Created_at:           now,
+		Update_at:            now,
+	}, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: OpenDispute", "Error:", err)
+		return entities.Dispute{}, disputeError(err)
+	}
+	s.logger.Infoln("Layer: dispute_services", "Method: OpenDispute", "Dispute:", dispute.ID)
+	return dispute, nil
+}
+
+// ListDisputes returns the latest disputes of the authenticated user.
+func (s *disputeService) ListDisputes(ctx context.Context, email string) ([]entities.Dispute, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: ListDisputes", "Error:", err)
+		return nil, err
+	}
+	disputes, err := s.disputeRepository.ListDisputes(user.ID, maxDisputes, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: ListDisputes", "Error:", err)
+		return nil, err
+	}
+	return disputes, nil
+}
+
+// GetDispute returns a dispute of the authenticated user or, for support
+// staff, any dispute.
+func (s *disputeService) GetDispute(ctx context.Context, email string, disputeID string) (entities.Dispute, error) {
+	_, dispute, err := s.visibleDispute(ctx, email, disputeID)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: GetDispute", "Error:", err)
+		return entities.Dispute{}, err
+	}
+	return dispute, nil
+}
+
+// AddEvidence attaches a file to a dispute not yet decided. Both the user and
+// support staff can add evidence.
+func (s *disputeService) AddEvidence(ctx context.Context, email string, disputeID string, name string, contentType string, data []byte) (entities.Dispute, error) {
+	if !kycContentTypes[contentType] || len(data) == 0 || len(data) > maxKYCDocumentSize || utf8.RuneCountInString(name) > maxMemoLength {
+		s.logger.Errorln("Layer: dispute_services", "Method: AddEvidence", "Error:", ErrInvalidEvidence)
+		return entities.Dispute{}, ErrInvalidEvidence
+	}
+	user, dispute, err := s.visibleDispute(ctx, email, disputeID)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: AddEvidence", "Error:", err)
+		return entities.Dispute{}, err
+	}
+	if dispute.Status != entities.DisputeOpened && dispute.Status != entities.DisputeUnderReview {
+		s.logger.Errorln("Layer: dispute_services", "Method: AddEvidence", "Error:", ErrDisputeState)
+		return entities.Dispute{}, ErrDisputeState
+	}
+	if len(dispute.Evidence) >= maxDisputeEvidence {
+		s.logger.Errorln("Layer: dispute_services", "Method: AddEvidence", "Error:", ErrInvalidEvidence)
+		return entities.Dispute{}, ErrInvalidEvidence
+	}
+
+	id, err := newDocumentID()
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: AddEvidence", "Error:", err)
+		return entities.Dispute{}, err
+	}
+	evidence := entities.DisputeEvidence{
+		ID:          id,
+		Name:        name,
+		BlobKey:     "disputes/" + dispute.ID + "/" + id,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		UploadedBy:  user.Email,
+		Uploaded_at: time.Now().UTC(),
+	}
+	if err := s.blobs.PutBlob(evidence.BlobKey, data, ctx); err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: AddEvidence", "Error:", err)
+		return entities.Dispute{}, err
+	}
+	dispute, err = s.disputeRepository.AddEvidence(dispute.ID, evidence, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: AddEvidence", "Error:", err)
+		return entities.Dispute{}, disputeError(err)
+	}
+	return dispute, nil
+}
+
+// GetEvidence returns a file attached to a dispute and its content.
+func (s *disputeService) GetEvidence(ctx context.Context, email string, disputeID string, evidenceID string) (entities.DisputeEvidence, []byte, error) {
+	_, dispute, err := s.visibleDispute(ctx, email, disputeID)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: GetEvidence", "Error:", err)
+		return entities.DisputeEvidence{}, nil, err
+	}
+	for _, evidence := range dispute.Evidence {
+		if evidence.ID != evidenceID {
+			continue
+		}
+		data, err := s.blobs.GetBlob(evidence.BlobKey, ctx)
+		if err != nil {
+			s.logger.Errorln("Layer: dispute_services", "Method: GetEvidence", "Error:", err)
+			if errors.Is(err, repository_blob.ErrBlobNotFound) {
+				return entities.DisputeEvidence{}, nil, ErrEvidenceNotFound
+			}
+			return entities.DisputeEvidence{}, nil, err
+		}
+		return evidence, data, nil
+	}
+	s.logger.Errorln("Layer: dispute_services", "Method: GetEvidence", "Error:", ErrEvidenceNotFound)
+	return entities.DisputeEvidence{}, nil, ErrEvidenceNotFound
+}
+
+// ListDisputeQueue returns the disputes in a status, opened ones by default,
+// for support staff to work on.
+func (s *disputeService) ListDisputeQueue(ctx context.Context, email string, status string) ([]entities.Dispute, error) {
+	if _, err := requireStaff(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: ListDisputeQueue", "Error:", err)
+		return nil, err
+	}
+	if status == "" {
+		status = entities.DisputeOpened
+	}
+	disputes, err := s.disputeRepository.ListDisputesByStatus(status, maxDisputes, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: ListDisputeQueue", "Error:", err)
+		return nil, err
+	}
+	return disputes, nil
+}
+
+// ReviewDispute lets support staff take an opened dispute under review.
+func (s *disputeService) ReviewDispute(ctx context.Context, email string, disputeID string) (entities.Dispute, error) {
+	staff, dispute, err := s.staffDispute(ctx, email, disputeID)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: ReviewDispute", "Error:", err)
+		return entities.Dispute{}, err
+	}
+	dispute, err = s.disputeRepository.UpdateStatus(dispute.ID, entities.DisputeOpened, entities.DisputeUnderReview, staff.Email, "", ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: ReviewDispute", "Error:", err)
+		return entities.Dispute{}, disputeError(err)
+	}
+	return dispute, nil
+}
+
+// ResolveDispute lets support staff decide a dispute under review. Disputes
+// resolved in favor of the user reverse amount, or the whole transfer when it
+// is zero, with a new ledger entry moving the money back from the
+// counterparty; the original entry is never changed. A frozen or blocked
+// counterparty, or a blocked user, stops the reversal until the restriction
+// is lifted. Rejections must give a note, which the user sees.
+func (s *disputeService) ResolveDispute(ctx context.Context, email string, disputeID string, inFavor bool, amount int64, note string) (entities.Dispute, error) {
+	if !inFavor && note == "" {
+		s.logger.Errorln("Layer: dispute_services", "Method: ResolveDispute", "Error:", ErrDisputeNoteRequired)
+		return entities.Dispute{}, ErrDisputeNoteRequired
+	}
+	staff, dispute, err := s.staffDispute(ctx, email, disputeID)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: ResolveDispute", "Error:", err)
+		return entities.Dispute{}, err
+	}
+	if amount == 0 {
+		amount = dispute.Amount
+	}
+	if inFavor && (amount < 0 || amount > dispute.Amount) {
+		s.logger.Errorln("Layer: dispute_services", "Method: ResolveDispute", "Error:", ErrInvalidReversalAmount)
+		return entities.Dispute{}, ErrInvalidReversalAmount
+	}
+
+	status := entities.DisputeRejected
+	if inFavor {
+		status = entities.DisputeResolvedInFavor
+	}
+	// The status is claimed before the money moves, so two resolutions racing
+	// cannot reverse the transfer twice.
+	dispute, err = s.disputeRepository.UpdateStatus(dispute.ID, entities.DisputeUnderReview, status, staff.Email, note, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: ResolveDispute", "Error:", err)
+		return entities.Dispute{}, disputeError(err)
+	}
+	if inFavor {
+		dispute, err = s.reverse(ctx, dispute, amount)
+		if err != nil {
+			s.logger.Errorln("Layer: dispute_services", "Method: ResolveDispute", "Error:", err)
+			if _, err := s.disputeRepository.UpdateStatus(dispute.ID, status, entities.DisputeUnderReview, staff.Email, "", ctx); err != nil {
+				s.logger.Errorln("Layer: dispute_services", "Method: ResolveDispute", "Error:", err)
+			}
+			if errors.Is(err, repository_ledger.ErrInsufficientFunds) {
+				return entities.Dispute{}, ErrReversalFunds
+			}
+			return entities.Dispute{}, err
+		}
+	}
+	s.logger.Infoln("Layer: dispute_services", "Method: ResolveDispute", "Dispute:", dispute.ID, "Status:", dispute.Status)
+	s.notify(ctx, dispute)
+	return dispute, nil
+}
+
+// reverse posts the reversal of amount of the disputed transfer and links it
+// to the dispute.
+func (s *disputeService) reverse(ctx context.Context, dispute entities.Dispute, amount int64) (entities.Dispute, error) {
+	if s.restrictions != nil {
+		if err := s.restrictions.CheckDebit(ctx, dispute.CounterpartyWalletID, entities.CapabilityReversal); err != nil {
+			return dispute, err
+		}
+		if err := s.restrictions.CheckCredit(ctx, dispute.WalletID); err != nil {
+			return dispute, err
+		}
+	}
+	memo := "Reversal of disputed transfer"
+	entry := entities.LedgerEntry{
+		Type:       entities.EntryReversal,
+		Memo:       memo,
+		ReversalOf: dispute.EntryID,
+		Lines: []entities.LedgerLine{
+			{Account: entities.WalletAccount(dispute.CounterpartyWalletID), WalletID: dispute.CounterpartyWalletID, Currency: dispute.Currency, Amount: -amount},
+			{Account: entities.WalletAccount(dispute.WalletID), WalletID: dispute.WalletID, Currency: dispute.Currency, Amount: amount},
+		},
+		Created_at: time.Now().UTC(),
+	}
+	transactions := []entities.Transaction{
+		{
+			WalletID:             dispute.CounterpartyWalletID,
+			UserID:               dispute.CounterpartyUserID,
+			Type:                 entities.TransactionReversalOut,
+			Status:               entities.StatusCompleted,
+			Amount:               amount,
+			Currency:             dispute.Currency,
+			CounterpartyWalletID: dispute.WalletID,
+			CounterpartyUserID:   dispute.UserID,
+			Memo:                 memo,
+			ReversalOf:           dispute.EntryID,
+		},
+		{
+			WalletID:             dispute.WalletID,
+			UserID:               dispute.UserID,
+			Type:                 entities.TransactionReversalIn,
+			Status:               entities.StatusCompleted,
+			Amount:               amount,
+			Currency:             dispute.Currency,
+			CounterpartyWalletID: dispute.CounterpartyWalletID,
+			CounterpartyUserID:   dispute.CounterpartyUserID,
+			Memo:                 memo,
+			ReversalOf:           dispute.EntryID,
+		},
+	}
+	entry, _, err := s.ledgerRepository.PostEntry(entry, transactions, ctx)
+	if err != nil {
+		return dispute, err
+	}
+	linked, err := s.disputeRepository.SetReversal(dispute.ID, entry.ID, amount, ctx)
+	if err != nil {
+		// The money has moved; the dispute is returned linked anyway so the
+		// caller sees the reversal.
+		s.logger.Errorln("Layer: dispute_services", "Method: reverse", "Error:", err)
+		dispute.ReversalEntryID = entry.ID
+		dispute.ReversedAmount = amount
+		return dispute, nil
+	}
+	return linked, nil
+}
+
+// notify tells the user the outcome of their dispute and, when money was
+// reversed, the counterparty as well. Failures are logged; the dispute is
+// already decided.
+func (s *disputeService) notify(ctx context.Context, dispute entities.Dispute) {
+	if s.notifier == nil {
+		return
+	}
+	data := map[string]string{
+		"dispute_id":     dispute.ID,
+		"transaction_id": dispute.TransactionID,
+		"status":         dispute.Status,
+		"currency":       dispute.Currency,
+	}
+	message := "Your dispute was rejected: " + dispute.Resolution
+	if dispute.Status == entities.DisputeResolvedInFavor {
+		data["reversed_amount"] = strconv.FormatInt(dispute.ReversedAmount, 10)
+		message = fmt.Sprintf("Your dispute was resolved in your favor and %d %s were returned to your wallet", dispute.ReversedAmount, dispute.Currency)
+	}
+	err := s.notifier.Notify(ctx, entities.Notification{
+		UserID:  dispute.UserID,
+		Type:    entities.NotificationDisputeResolved,
+		Title:   "Dispute resolved",
+		Message: message,
+		Data:    data,
+	})
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: notify", "Error:", err)
+	}
+	if dispute.Status != entities.DisputeResolvedInFavor {
+		return
+	}
+	err = s.notifier.Notify(ctx, entities.Notification{
+		UserID:  dispute.CounterpartyUserID,
+		Type:    entities.NotificationDisputeReversal,
+		Title:   "Transfer reversed",
+		Message: fmt.Sprintf("%d %s of a transfer you received were reversed after a dispute", dispute.ReversedAmount, dispute.Currency),
+		Data: map[string]string{
+			"reversal_entry_id": dispute.ReversalEntryID,
+			"reversed_amount":   strconv.FormatInt(dispute.ReversedAmount, 10),
+			"currency":          dispute.Currency,
+		},
+	})
+	if err != nil {
+		s.logger.Errorln("Layer: dispute_services", "Method: notify", "Error:", err)
+	}
+}
+
+// visibleDispute loads the authenticated user and a dispute they can see:
+// their own or, for support staff, any. Other disputes are reported as not
+// found.
+func (s *disputeService) visibleDispute(ctx context.Context, email string, disputeID string) (entities.User, entities.Dispute, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.User{}, entities.Dispute{}, err
+	}
+	dispute, err := s.disputeRepository.GetDispute(disputeID, ctx)
+	if err != nil {
+		return entities.User{}, entities.Dispute{}, disputeError(err)
+	}
+	staff := user.Role == entities.RoleSupport || user.Role == entities.RoleAdmin
+	if dispute.UserID != user.ID && !staff {
+		return entities.User{}, entities.Dispute{}, ErrDisputeNotFound
+	}
+	return user, dispute, nil
+}
+
+// staffDispute loads the authenticated support staff and a dispute they can
+// decide: staff do not decide disputes of their own or against them.
+func (s *disputeService) staffDispute(ctx context.Context, email string, disputeID string) (entities.User, entities.Dispute, error) {
+	staff, err := requireStaff(ctx, s.userRepository, email)
+	if err != nil {
+		return entities.User{}, entities.Dispute{}, err
+	}
+	dispute, err := s.disputeRepository.GetDispute(disputeID, ctx)
+	if err != nil {
+		return entities.User{}, entities.Dispute{}, disputeError(err)
+	}
+	if dispute.UserID == staff.ID || dispute.CounterpartyUserID == staff.ID {
+		return entities.User{}, entities.Dispute{}, ErrDisputeSelfReview
+	}
+	return staff, dispute, nil
+}
+
+// disputeError maps the errors of the dispute repository to the ones of the
+// service.
+func disputeError(err error) error {
+	switch {
+	case errors.Is(err, repository_dispute.ErrDisputeNotFound):
+		return ErrDisputeNotFound
+	case errors.Is(err, repository_dispute.ErrDisputeExists):
+		return ErrDisputeExists
+	case errors.Is(err, repository_dispute.ErrDisputeStatusChanged):
+		return ErrDisputeState
+	}
+	return err
+}
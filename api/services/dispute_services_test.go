@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_dispute "my_wallet/api/respository/dispute"
+	repository_ledger "my_wallet/api/respository/ledger"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOpenDisputeService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	transfer := entities.Transaction{ID: "t1", EntryID: "e1", WalletID: "w1", UserID: "u1", Type: entities.TransactionTransferOut, Status: entities.StatusCompleted, Amount: 5000, Currency: "COP", CounterpartyWalletID: "w2", CounterpartyUserID: "u2"}
+
+	testScenarios := []struct {
+		testName      string
+		reason        string
+		transaction   entities.Transaction
+		getError      error
+		createError   error
+		expectedError error
+	}{
+		{
+			testName:    "TestOpenDisputeService",
+			reason:      entities.DisputeWrongRecipient,
+			transaction: transfer,
+		},
+		{
+			testName:      "TestOpenDisputeUnknownReason",
+			reason:        "changed_my_mind",
+			transaction:   transfer,
+			expectedError: ErrInvalidDisputeReason,
+		},
+		{
+			testName:      "TestOpenDisputeTransactionNotFound",
+			reason:        entities.DisputeWrongRecipient,
+			getError:      repository_ledger.ErrTransactionNotFound,
+			expectedError: ErrTransactionNotFound,
+		},
+		{
+			testName:      "TestOpenDisputeTransactionOfOtherUser",
+			reason:        entities.DisputeWrongRecipient,
+			transaction:   entities.Transaction{ID: "t1", UserID: "u9", Type: entities.TransactionTransferOut, Status: entities.StatusCompleted},
+			expectedError: ErrTransactionNotFound,
+		},
+		{
+			testName:      "TestOpenDisputeIncomingTransfer",
+			reason:        entities.DisputeWrongAmount,
+			transaction:   entities.Transaction{ID: "t1", UserID: "u1", Type: entities.TransactionTransferIn, Status: entities.StatusCompleted},
+			expectedError: ErrTransactionNotDisputable,
+		},
+		{
+			testName:      "TestOpenDisputeTwice",
+			reason:        entities.DisputeWrongRecipient,
+			transaction:   transfer,
+			createError:   repository_dispute.ErrDisputeExists,
+			expectedError: ErrDisputeExists,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			transactions := &transactionRepositoryMock{}
+			transactions.On("GetTransaction", mock.Anything, "t1").Return(tt.transaction, tt.getError)
+			disputes := &disputeRepositoryMock{}
+			disputes.On("CreateDispute", mock.Anything, mock.MatchedBy(func(d entities.Dispute) bool {
+				return d.UserID == "u1" && d.EntryID == "e1" && d.CounterpartyWalletID == "w2" && d.Amount == 5000 && d.Status == entities.DisputeOpened
+			})).Return(entities.Dispute{ID: "d1", Status: entities.DisputeOpened}, tt.createError)
+			service := NewDisputeService(users, transactions, &ledgerRepositoryMock{}, disputes, &blobStoreMock{}, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.OpenDispute(context.Background(), "alexer@gmail.com", "t1", tt.reason, "")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, "d1", result.ID)
+			}
+		})
+	}
+}
+
+func TestAddDisputeEvidenceService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+
+	testScenarios := []struct {
+		testName      string
+		dispute       entities.Dispute
+		contentType   string
+		expectedError error
+	}{
+		{
+			testName:    "TestAddDisputeEvidenceService",
+			dispute:     entities.Dispute{ID: "d1", UserID: "u1", Status: entities.DisputeUnderReview},
+			contentType: "application/pdf",
+		},
+		{
+			testName:      "TestAddEvidenceUnsupportedContentType",
+			dispute:       entities.Dispute{ID: "d1", UserID: "u1", Status: entities.DisputeOpened},
+			contentType:   "text/html",
+			expectedError: ErrInvalidEvidence,
+		},
+		{
+			testName:      "TestAddEvidenceToDisputeOfOtherUser",
+			dispute:       entities.Dispute{ID: "d1", UserID: "u9", Status: entities.DisputeOpened},
+			contentType:   "application/pdf",
+			expectedError: ErrDisputeNotFound,
+		},
+		{
+			testName:      "TestAddEvidenceToDecidedDispute",
+			dispute:       entities.Dispute{ID: "d1", UserID: "u1", Status: entities.DisputeRejected},
+			contentType:   "application/pdf",
+			expectedError: ErrDisputeState,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			disputes := &disputeRepositoryMock{}
+			disputes.On("GetDispute", mock.Anything, "d1").Return(tt.dispute, nil)
+			disputes.On("AddEvidence", mock.Anything, "d1", mock.MatchedBy(func(e entities.DisputeEvidence) bool {
+				return e.UploadedBy == "alexer@gmail.com" && e.Size == 3
+			})).Return(entities.Dispute{ID: "d1", Evidence: []entities.DisputeEvidence{{ID: "x"}}}, nil)
+			blobs := &blobStoreMock{}
+			blobs.On("PutBlob", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			service := NewDisputeService(users, &transactionRepositoryMock{}, &ledgerRepositoryMock{}, disputes, blobs, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.AddEvidence(context.Background(), "alexer@gmail.com", "d1", "receipt.pdf", tt.contentType, []byte("pdf"))
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Len(t, result.Evidence, 1)
+				blobs.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestResolveDisputeService(t *testing.T) {
+	staff := entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport}
+	underReview := entities.Dispute{ID: "d1", UserID: "u1", WalletID: "w1", EntryID: "e1", CounterpartyWalletID: "w2", CounterpartyUserID: "u2", Amount: 5000, Currency: "COP", Status: entities.DisputeUnderReview}
+	resolved := underReview
+	resolved.Status = entities.DisputeResolvedInFavor
+
+	testScenarios := []struct {
+		testName       string
+		inFavor        bool
+		amount         int64
+		note           string
+		configureMock  func(*disputeRepositoryMock, *ledgerRepositoryMock)
+		expectedAmount int64
+		expectedError  error
+	}{
+		{
+			testName: "TestResolveDisputeFullReversal",
+			inFavor:  true,
+			configureMock: func(d *disputeRepositoryMock, l *ledgerRepositoryMock) {
+				d.On("UpdateStatus", mock.Anything, "d1", entities.DisputeUnderReview, entities.DisputeResolvedInFavor, "support@gmail.com", "").Return(resolved, nil)
+				l.On("PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+					return repository_ledger.IsBalanced(e) && e.ReversalOf == "e1" && e.Lines[0].WalletID == "w2" && e.Lines[0].Amount == -5000
+				}), mock.Anything).Return(entities.LedgerEntry{ID: "r1"}, nil)
+				d.On("SetReversal", mock.Anything, "d1", "r1", int64(5000)).Return(entities.Dispute{ID: "d1", UserID: "u1", CounterpartyUserID: "u2", Status: entities.DisputeResolvedInFavor, ReversalEntryID: "r1", ReversedAmount: 5000}, nil)
+			},
+			expectedAmount: 5000,
+		},
+		{
+			testName: "TestResolveDisputePartialReversal",
+			inFavor:  true,
+			amount:   2000,
+			configureMock: func(d *disputeRepositoryMock, l *ledgerRepositoryMock) {
+				d.On("UpdateStatus", mock.Anything, "d1", entities.DisputeUnderReview, entities.DisputeResolvedInFavor, "support@gmail.com", "").Return(resolved, nil)
+				l.On("PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+					return e.Lines[1].WalletID == "w1" && e.Lines[1].Amount == 2000
+				}), mock.Anything).Return(entities.LedgerEntry{ID: "r1"}, nil)
+				d.On("SetReversal", mock.Anything, "d1", "r1", int64(2000)).Return(entities.Dispute{ID: "d1", UserID: "u1", CounterpartyUserID: "u2", Status: entities.DisputeResolvedInFavor, ReversalEntryID: "r1", ReversedAmount: 2000}, nil)
+			},
+			expectedAmount: 2000,
+		},
+		{
+			testName:      "TestResolveDisputeAboveDisputedAmount",
+			inFavor:       true,
+			amount:        6000,
+			expectedError: ErrInvalidReversalAmount,
+		},
+		{
+			testName: "TestRejectDispute",
+			note:     "The recipient confirmed the goods were delivered",
+			configureMock: func(d *disputeRepositoryMock, l *ledgerRepositoryMock) {
+				d.On("UpdateStatus", mock.Anything, "d1", entities.DisputeUnderReview, entities.DisputeRejected, "support@gmail.com", "The recipient confirmed the goods were delivered").Return(entities.Dispute{ID: "d1", UserID: "u1", Status: entities.DisputeRejected}, nil)
+			},
+		},
+		{
+			testName:      "TestRejectDisputeWithoutNote",
+			expectedError: ErrDisputeNoteRequired,
+		},
+		{
+			testName: "TestResolveDisputeCounterpartyWithoutFunds",
+			inFavor:  true,
+			configureMock: func(d *disputeRepositoryMock, l *ledgerRepositoryMock) {
+				d.On("UpdateStatus", mock.Anything, "d1", entities.DisputeUnderReview, entities.DisputeResolvedInFavor, "support@gmail.com", "").Return(resolved, nil)
+				l.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{}, repository_ledger.ErrInsufficientFunds)
+				d.On("UpdateStatus", mock.Anything, "d1", entities.DisputeResolvedInFavor, entities.DisputeUnderReview, "support@gmail.com", "").Return(underReview, nil)
+			},
+			expectedError: ErrReversalFunds,
+		},
+		{
+			testName: "TestResolveDisputeAlreadyDecided",
+			inFavor:  true,
+			configureMock: func(d *disputeRepositoryMock, l *ledgerRepositoryMock) {
+				d.On("UpdateStatus", mock.Anything, "d1", entities.DisputeUnderReview, entities.DisputeResolvedInFavor, "support@gmail.com", "").Return(entities.Dispute{}, repository_dispute.ErrDisputeStatusChanged)
+			},
+			expectedError: ErrDisputeState,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "support@gmail.com").Return(staff, nil)
+			disputes := &disputeRepositoryMock{}
+			disputes.On("GetDispute", mock.Anything, "d1").Return(underReview, nil)
+			ledger := &ledgerRepositoryMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(disputes, ledger)
+			}
+			notifier := &notifierMock{}
+			notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+			service := NewDisputeService(users, &transactionRepositoryMock{}, ledger, disputes, &blobStoreMock{}, notifier, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.ResolveDispute(context.Background(), "support@gmail.com", "d1", tt.inFavor, tt.amount, tt.note)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedAmount, result.ReversedAmount)
+			if tt.configureMock != nil {
+				disputes.AssertExpectations(t)
+			}
+			ledger.AssertExpectations(t)
+			if tt.expectedAmount > 0 {
+				notifier.AssertCalled(t, "Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+					return n.UserID == "u2" && n.Type == entities.NotificationDisputeReversal
+				}))
+			}
+		})
+	}
+}
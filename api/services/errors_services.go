@@ -10,3 +10,131 @@ var ErrHashingPassword = errors.New("Error hashing the password")
 var ErrUserNotfound = errors.New("Error not found user")
 var ErrInvalidCredentials = errors.New("Invalid email or password")
 var ErrValidation = errors.New("Error in the structure of the request or in the structure of the email")
+var ErrInvalidAmount = errors.New("Amount must be greater than zero")
+var ErrRecipientRequired = errors.New("Exactly one of recipient email, phone or DNI is required")
+var ErrRecipientNotFound = errors.New("Error not found recipient")
+var ErrRecipientDisabled = errors.New("Recipient user is disabled")
+var ErrSelfTransfer = errors.New("Cannot transfer to yourself")
+var ErrMemoTooLong = errors.New("Memo must be at most 140 characters")
+var ErrInsufficientFunds = errors.New("Insufficient funds")
+var ErrInvalidIdempotencyKey = errors.New("Idempotency-Key must be between 1 and 255 characters")
+var ErrIdempotencyKeyReused = errors.New("Idempotency-Key was already used with a different request")
+var ErrIdempotencyInProgress = errors.New("A request with this Idempotency-Key is still in progress")
+var ErrWalletForbidden = errors.New("Wallet does not belong to the user")
+var ErrInvalidCursor = errors.New("Invalid pagination cursor")
+var ErrInvalidTransactionFilter = errors.New("Invalid transaction filter")
+var ErrInvalidStatementPeriod = errors.New("Statement period requires from before to")
+var ErrInvalidStatementFormat = errors.New("Statement format must be csv, jsonl or pdf")
+var ErrStatementNotReconciled = errors.New("Statement does not reconcile with the ledger")
+var ErrInvalidCurrency = errors.New("Currency must be a three letter ISO 4217 code")
+var ErrSameCurrency = errors.New("Conversion requires two different currencies")
+var ErrRateNotFound = errors.New("No exchange rate for the currency pair")
+var ErrQuoteNotFound = errors.New("Error not found quote")
+var ErrQuoteExpired = errors.New("Quote expired or already used")
+var ErrAdminRequired = errors.New("Only administrators can use this endpoint")
+var ErrInvalidFeeRule = errors.New("Invalid fee rule")
+var ErrFeeRuleNotFound = errors.New("Error not found fee rule")
+var ErrInvalidOperation = errors.New("Invalid operation")
+var ErrInvalidSchedule = errors.New("Invalid scheduled payment")
+var ErrScheduleNotFound = errors.New("Error not found scheduled payment")
+var ErrScheduleNotActive = errors.New("Scheduled payment is not active")
+var ErrPayerRequired = errors.New("Exactly one of payer email, phone or DNI is required")
+var ErrPayerNotFound = errors.New("Error not found payer")
+var ErrPayerDisabled = errors.New("Payer user is disabled")
+var ErrSelfRequest = errors.New("Cannot request money from yourself")
+var ErrPaymentRequestNotFound = errors.New("Error not found payment request")
+var ErrPaymentRequestNotPending = errors.New("Payment request was already answered")
+var ErrPaymentRequestExpired = errors.New("Payment request expired")
+var ErrInvalidPaymentRequestFilter = errors.New("Direction must be incoming or outgoing")
+var ErrInvalidSplit = errors.New("Invalid split")
+var ErrSplitAmountMismatch = errors.New("Split amounts do not add up to the total")
+var ErrSplitNotFound = errors.New("Error not found split")
+var ErrInvalidPocket = errors.New("Invalid pocket")
+var ErrPocketNotFound = errors.New("Error not found pocket")
+var ErrTooManyPockets = errors.New("Wallet already has the maximum number of pockets")
+var ErrPocketNotEmpty = errors.New("Pocket received money while it was being closed")
+var ErrInvalidCategory = errors.New("Category name must be between 1 and 40 characters")
+var ErrUnknownCategory = errors.New("Unknown category")
+var ErrCategoryExists = errors.New("Category already exists")
+var ErrInvalidCategoryRule = errors.New("Category rule needs a counterparty, keywords or an amount range")
+var ErrCategoryRuleNotFound = errors.New("Error not found category rule")
+var ErrTransactionNotFound = errors.New("Error not found transaction")
+var ErrCannotLearnRule = errors.New("Transaction has no counterparty or memo to learn a rule from")
+var ErrNotificationNotFound = errors.New("Error not found notification")
+var ErrInvalidBudget = errors.New("Budget limit must be greater than zero")
+var ErrBudgetExists = errors.New("Wallet already has a budget for this category")
+var ErrBudgetNotFound = errors.New("Error not found budget")
+var ErrInvalidInsightPeriod = errors.New("Insight period must be week, month, quarter or year and granularity day, week or month")
+var ErrLimitExceeded = errors.New("Transaction limit exceeded")
+var ErrStaffRequired = errors.New("Only support staff can use this endpoint")
+var ErrKYCLevelRequired = errors.New("Your KYC level does not allow this operation")
+var ErrInvalidKYCLevel = errors.New("KYC level must be basic or full and above the current one")
+var ErrInvalidKYCDocument = errors.New("KYC document type is not valid or the document is empty or too large")
+var ErrKYCApplicationNotFound = errors.New("Error not found KYC application")
+var ErrKYCApplicationOpen = errors.New("User already has an open KYC application")
+var ErrKYCApplicationState = errors.New("KYC application is not in a status that allows this operation")
+var ErrKYCDocumentsMissing = errors.New("KYC application is missing required documents")
+var ErrKYCDocumentNotFound = errors.New("Error not found KYC document")
+var ErrKYCReasonRequired = errors.New("A reason is required to reject a KYC application")
+var ErrKYCSelfReview = errors.New("Staff cannot review their own KYC application")
+var ErrAnalystRequired = errors.New("Only fraud analysts can use this endpoint")
+var ErrTransferBlocked = errors.New("Transfer was blocked by our risk controls")
+var ErrTransferHeld = errors.New("Transfer is held for review")
+var ErrTransferAlreadyMade = errors.New("Transfer was already made")
+var ErrInvalidScreeningRule = errors.New("Screening rule must have an id, a known kind and action and the fields its kind needs")
+var ErrScreeningRulesReadOnly = errors.New("Screening rules are loaded from a file and cannot be changed")
+var ErrReviewNotFound = errors.New("Error not found review")
+var ErrReviewState = errors.New("Review is no longer pending")
+var ErrTransferRejected = errors.New("Transfer was rejected on review")
+var ErrUserUnderReview = errors.New("User is pending compliance review")
+var ErrSanctionsScreeningNotFound = errors.New("Sanctions screening not found")
+var ErrSanctionsScreeningState = errors.New("Sanctions screening was already decided")
+var ErrDisputeNotFound = errors.New("Error not found dispute")
+var ErrDisputeExists = errors.New("Transaction already has a dispute")
+var ErrTransactionNotDisputable = errors.New("Only completed outgoing transfers and payments can be disputed")
+var ErrInvalidDisputeReason = errors.New("Dispute reason is not valid")
+var ErrDisputeState = errors.New("Dispute is not in a status that allows this operation")
+var ErrInvalidEvidence = errors.New("Evidence must be a JPEG, PNG or PDF file of up to 5 MB")
+var ErrEvidenceNotFound = errors.New("Error not found dispute evidence")
+var ErrInvalidReversalAmount = errors.New("Reversal amount must be positive and no more than the disputed amount")
+var ErrDisputeNoteRequired = errors.New("A note is required to reject a dispute")
+var ErrDisputeSelfReview = errors.New("Staff cannot review their own dispute")
+var ErrReversalFunds = errors.New("Counterparty wallet does not have the funds to reverse the transaction")
+var ErrBeneficiaryNotFound = errors.New("Error not found beneficiary")
+var ErrBeneficiaryExists = errors.New("Beneficiary already saved")
+var ErrInvalidBeneficiary = errors.New("Beneficiary must be a wallet user or a bank account with a known bank, account type, number and holder")
+var ErrBeneficiaryType = errors.New("Beneficiary cannot receive this kind of payment")
+var ErrBeneficiaryCoolingOff = errors.New("Beneficiary was added recently and cannot receive large amounts yet")
+var ErrWithdrawalBeneficiaryRequired = errors.New("Withdrawals are paid out to a saved bank beneficiary")
+var ErrMerchantNotFound = errors.New("Error not found merchant")
+var ErrMerchantExists = errors.New("User already has a merchant account")
+var ErrInvalidMerchant = errors.New("Merchant requires a name of up to 25 characters, a city of up to 15 and a 4 digit category code")
+var ErrInvalidQRCode = errors.New("QR code is not a valid My Wallet payment code")
+var ErrQRCodeNotFound = errors.New("Error not found QR code")
+var ErrQRCodePaid = errors.New("QR code was already paid")
+var ErrQRCodeExpired = errors.New("QR code has expired")
+var ErrQRCodeAmount = errors.New("Amount does not match the QR code")
+var ErrInvalidSettlementPeriod = errors.New("Settlement period requires from before to, at most a year apart")
+var ErrWalletFrozen = errors.New("Wallet is frozen and cannot send money")
+var ErrWalletBlocked = errors.New("Wallet is blocked")
+var ErrCapabilityRestricted = errors.New("Wallet is restricted from this operation")
+var ErrInvalidRestriction = errors.New("Restriction requires a kind, a known reason code, an expiry within a year and, for capability restrictions, the capabilities")
+var ErrRestrictionNotFound = errors.New("Error not found restriction")
+var ErrRestrictionLifted = errors.New("Restriction was already lifted")
+var ErrInvalidReconciliation = errors.New("Reconciliation requires a provider, a day and non-negative tolerances of at most 7 days")
+var ErrInvalidReconciliationResult = errors.New("Result must be matched, amount_mismatch, unmatched_internal or unmatched_external")
+var ErrReconciliationNotFound = errors.New("Error not found reconciliation")
+var ErrInvalidInterestDay = errors.New("Interest can only be accrued again for a day that has ended")
+var ErrInvalidCard = errors.New("Card requires a name of up to 40 characters and limits that are not negative")
+var ErrCardNotFound = errors.New("Error not found card")
+var ErrInvalidCardSignature = errors.New("Invalid card network signature")
+var ErrInvalidCardMessage = errors.New("Invalid card network message")
+var ErrCardAuthorizationNotFound = errors.New("Error not found card authorization")
+var ErrCardAuthorizationPending = errors.New("Card authorization is still being decided")
+var ErrCardAuthorizationClosed = errors.New("Card authorization was already cleared or reversed")
+var ErrWalletPermission = errors.New("Your role in the wallet does not allow it")
+var ErrInvalidMember = errors.New("Invalid wallet member")
+var ErrMemberNotFound = errors.New("Error not found wallet member")
+var ErrMemberExists = errors.New("User already invited to the wallet")
+var ErrInvitationClosed = errors.New("Invitation no longer open")
+var ErrSpendingLimitExceeded = errors.New("Member spending limit exceeded")
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type feeRuleRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *feeRuleRepositoryMock) ListFeeRules(operation string, ctx context.Context) ([]entities.FeeRule, error) {
+	r := m.Called(ctx, operation)
+	return r.Get(0).([]entities.FeeRule), r.Error(1)
+}
+
+func (m *feeRuleRepositoryMock) GetFeeRule(id string, ctx context.Context) (entities.FeeRule, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.FeeRule), r.Error(1)
+}
+
+func (m *feeRuleRepositoryMock) CreateFeeRule(rule entities.FeeRule, ctx context.Context) (entities.FeeRule, error) {
+	r := m.Called(ctx, rule)
+	return r.Get(0).(entities.FeeRule), r.Error(1)
+}
+
+func (m *feeRuleRepositoryMock) UpdateFeeRule(rule entities.FeeRule, ctx context.Context) (entities.FeeRule, error) {
+	r := m.Called(ctx, rule)
+	return r.Get(0).(entities.FeeRule), r.Error(1)
+}
+
+func (m *feeRuleRepositoryMock) DeleteFeeRule(id string, ctx context.Context) error {
+	r := m.Called(ctx, id)
+	return r.Error(0)
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_fee "my_wallet/api/respository/fee"
+	repository_user "my_wallet/api/respository/user"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var feeOperations = map[string]bool{
+	entities.OperationTransfer:        true,
+	entities.OperationWithdrawal:      true,
+	entities.OperationConversion:      true,
+	entities.OperationMerchantPayment: true,
+}
+
+type FeeService interface {
+	QuoteFee(ctx context.Context, email string, operation string, currency string, amount int64) (entities.FeeQuote, error)
+	ComputeFee(ctx context.Context, user entities.User, operation string, currency string, amount int64) (entities.FeeQuote, error)
+	ListFeeRules(ctx context.Context, email string) ([]entities.FeeRule, error)
+	CreateFeeRule(ctx context.Context, email string, rule entities.FeeRule) (entities.FeeRule, error)
+	UpdateFeeRule(ctx context.Context, email string, rule entities.FeeRule) (entities.FeeRule, error)
+	DeleteFeeRule(ctx context.Context, email string, id string) error
+}
+
+type feeService struct {
+	ctx               context.Context
+	userRepository    repository_user.UserRepository
+	feeRuleRepository repository_fee.FeeRuleRepository
+	logger            logrus.FieldLogger
+}
+
+func NewFeeService(userRepo repository_user.UserRepository, feeRuleRepo repository_fee.FeeRuleRepository, logger logrus.FieldLogger, ctx context.Context) *feeService {
+	return &feeService{
+		ctx:               ctx,
+		userRepository:    userRepo,
+		feeRuleRepository: feeRuleRepo,
+		logger:            logger,
+	}
+}
+
+// QuoteFee tells the authenticated user what an operation would cost before
+// it is confirmed.
+func (s *feeService) QuoteFee(ctx context.Context, email string, operation string, currency string, amount int64) (entities.FeeQuote, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: QuoteFee", "Error:", err)
+		return entities.FeeQuote{}, err
+	}
+	if currency == "" {
+		currency = entities.DefaultCurrency
+	}
+	return s.ComputeFee(ctx, user, operation, currency, amount)
+}
+
+// ComputeFee prices an operation with the rule that best matches the
+// currency and the segment of the user. Operations without a matching rule
+// are free.
+func (s *feeService) ComputeFee(ctx context.Context, user entities.User, operation string, currency string, amount int64) (entities.FeeQuote, error) {
+	if !feeOperations[operation] {
+		s.logger.Errorln("Layer: fee_services", "Method: ComputeFee", "Error:", ErrInvalidOperation)
+		return entities.FeeQuote{}, ErrInvalidOperation
+	}
+	if amount <= 0 {
+		s.logger.Errorln("Layer: fee_services", "Method: ComputeFee", "Error:", ErrInvalidAmount)
+		return entities.FeeQuote{}, ErrInvalidAmount
+	}
+	if !entities.ValidCurrency(currency) {
+		s.logger.Errorln("Layer: fee_services", "Method: ComputeFee", "Error:", ErrInvalidCurrency)
+		return entities.FeeQuote{}, ErrInvalidCurrency
+	}
+	rules, err := s.feeRuleRepository.ListFeeRules(operation, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: ComputeFee", "Error:", err)
+		return entities.FeeQuote{}, err
+	}
+
+	quote := entities.FeeQuote{Operation: operation, Currency: currency, Amount: amount}
+	if rule, ok := matchFeeRule(rules, currency, user.Segment); ok {
+		quote.Fee = applyFeeRule(rule, amount)
+		quote.RuleID = rule.ID
+	}
+	quote.Total = amount + quote.Fee
+	return quote, nil
+}
+
+func (s *feeService) ListFeeRules(ctx context.Context, email string) ([]entities.FeeRule, error) {
+	if _, err := requireAdmin(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: ListFeeRules", "Error:", err)
+		return nil, err
+	}
+	rules, err := s.feeRuleRepository.ListFeeRules("", ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: ListFeeRules", "Error:", err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (s *feeService) CreateFeeRule(ctx context.Context, email string, rule entities.FeeRule) (entities.FeeRule, error) {
+	if _, err := requireAdmin(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: CreateFeeRule", "Error:", err)
+		return entities.FeeRule{}, err
+	}
+	if err := validateFeeRule(rule); err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: CreateFeeRule", "Error:", err)
+		return entities.FeeRule{}, err
+	}
+	rule.ID = ""
+	rule.Created_at = time.Now().UTC()
+	rule.Update_at = rule.Created_at
+	return s.feeRuleRepository.CreateFeeRule(rule, ctx)
+}
+
+func (s *feeService) UpdateFeeRule(ctx context.Context, email string, rule entities.FeeRule) (entities.FeeRule, error) {
+	if _, err := requireAdmin(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: UpdateFeeRule", "Error:", err)
+		return entities.FeeRule{}, err
+	}
+	if err := validateFeeRule(rule); err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: UpdateFeeRule", "Error:", err)
+		return entities.FeeRule{}, err
+	}
+	current, err := s.feeRuleRepository.GetFeeRule(rule.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: UpdateFeeRule", "Error:", err)
+		return entities.FeeRule{}, feeRuleError(err)
+	}
+	rule.Created_at = current.Created_at
+	rule.Update_at = time.Now().UTC()
+	rule, err = s.feeRuleRepository.UpdateFeeRule(rule, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: UpdateFeeRule", "Error:", err)
+		return entities.FeeRule{}, feeRuleError(err)
+	}
+	return rule, nil
+}
+
+func (s *feeService) DeleteFeeRule(ctx context.Context, email string, id string) error {
+	if _, err := requireAdmin(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: DeleteFeeRule", "Error:", err)
+		return err
+	}
+	if err := s.feeRuleRepository.DeleteFeeRule(id, ctx); err != nil {
+		s.logger.Errorln("Layer: fee_services", "Method: DeleteFeeRule", "Error:", err)
+		return feeRuleError(err)
+	}
+	return nil
+}
+
+func feeRuleError(err error) error {
+	if errors.Is(err, repository_fee.ErrFeeRuleNotFound) {
+		return ErrFeeRuleNotFound
+	}
+	return err
+}
+
+// matchFeeRule picks the enabled rule for currency and segment. A rule naming
+// the currency or the segment beats a catch-all one; ties go to the highest
+// priority, and rules arrive from the repository in that order.
+func matchFeeRule(rules []entities.FeeRule, currency string, segment string) (entities.FeeRule, bool) {
+	var best entities.FeeRule
+	bestScore := -1
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		if rule.Currency != "" && rule.Currency != currency {
+			continue
+		}
+		if rule.Segment != "" && rule.Segment != segment {
+			continue
+		}
+		score := 0
+		if rule.Segment != "" {
+			score += 2
+		}
+		if rule.Currency != "" {
+			score++
+		}
+		if score > bestScore || (score == bestScore && rule.Priority > best.Priority) {
+			best = rule
+			bestScore = score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// applyFeeRule computes the fee of amount, rounding percentages half up to
+// the cent before applying the caps. Amounts above every tier are priced by
+// the last one.
+func applyFeeRule(rule entities.FeeRule, amount int64) int64 {
+	var fee int64
+	switch rule.Kind {
+	case entities.FeeFlat:
+		fee = rule.FlatAmount
+	case entities.FeePercentage:
+		fee = rule.FlatAmount + percentOf(amount, rule.Bps)
+	case entities.FeeTiered:
+		for i, tier := range rule.Tiers {
+			if tier.UpTo == 0 || amount <= tier.UpTo || i == len(rule.Tiers)-1 {
+				fee = tier.FlatAmount + percentOf(amount, tier.Bps)
+				break
+			}
+		}
+	}
+	if rule.MinFee > 0 && fee < rule.MinFee {
+		fee = rule.MinFee
+	}
+	if rule.MaxFee > 0 && fee > rule.MaxFee {
+		fee = rule.MaxFee
+	}
+	return fee
+}
+
+func percentOf(amount int64, bps int64) int64 {
+	return (amount*bps + basisPoints/2) / basisPoints
+}
+
+func validateFeeRule(rule entities.FeeRule) error {
+	if !feeOperations[rule.Operation] {
+		return ErrInvalidFeeRule
+	}
+	if rule.Currency != "" && !entities.ValidCurrency(rule.Currency) {
+		return ErrInvalidFeeRule
+	}
+	if rule.FlatAmount < 0 || rule.Bps < 0 || rule.Bps > basisPoints || rule.MinFee < 0 || rule.MaxFee < 0 {
+		return ErrInvalidFeeRule
+	}
+	if rule.MaxFee > 0 && rule.MaxFee < rule.MinFee {
+		return ErrInvalidFeeRule
+	}
+	switch rule.Kind {
+	case entities.FeeFlat, entities.FeePercentage:
+		if len(rule.Tiers) > 0 {
+			return ErrInvalidFeeRule
+		}
+	case entities.FeeTiered:
+		if len(rule.Tiers) == 0 {
+			return ErrInvalidFeeRule
+		}
+		var previous int64
+		for i, tier := range rule.Tiers {
+			last := i == len(rule.Tiers)-1
+			if tier.FlatAmount < 0 || tier.Bps < 0 || tier.Bps > basisPoints {
+				return ErrInvalidFeeRule
+			}
+			if tier.UpTo == 0 && !last {
+				return ErrInvalidFeeRule
+			}
+			if tier.UpTo != 0 && tier.UpTo <= previous {
+				return ErrInvalidFeeRule
+			}
+			previous = tier.UpTo
+		}
+	default:
+		return ErrInvalidFeeRule
+	}
+	return nil
+}
+
+// feeLines charges fee to a wallet and credits the fee revenue account. They
+// are posted in the same entry as the operation they are charged for.
+func feeLines(walletID string, currency string, fee int64) []entities.LedgerLine {
+	return []entities.LedgerLine{
+		{Account: entities.WalletAccount(walletID), WalletID: walletID, Currency: currency, Amount: -fee},
+		{Account: entities.AccountFeeRevenue, Currency: currency, Amount: fee},
+	}
+}
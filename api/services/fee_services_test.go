@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_fee "my_wallet/api/respository/fee"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestComputeFeeService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com", Segment: "premium"}
+
+	testScenarios := []struct {
+		testName       string
+		currency       string
+		amount         int64
+		rules          []entities.FeeRule
+		expectedOutput entities.FeeQuote
+		expectedError  error
+	}{
+		{
+			testName:       "TestNoRule",
+			currency:       "COP",
+			amount:         1000,
+			rules:          []entities.FeeRule{},
+			expectedOutput: entities.FeeQuote{Operation: "transfer", Currency: "COP", Amount: 1000, Total: 1000},
+		},
+		{
+			testName:       "TestFlat",
+			currency:       "COP",
+			amount:         1000,
+			rules:          []entities.FeeRule{{ID: "r1", Kind: entities.FeeFlat, FlatAmount: 300, Enabled: true}},
+			expectedOutput: entities.FeeQuote{Operation: "transfer", Currency: "COP", Amount: 1000, Fee: 300, Total: 1300, RuleID: "r1"},
+		},
+		{
+			testName:       "TestPercentageWithMaxFee",
+			currency:       "COP",
+			amount:         1000000,
+			rules:          []entities.FeeRule{{ID: "r1", Kind: entities.FeePercentage, Bps: 150, MaxFee: 10000, Enabled: true}},
+			expectedOutput: entities.FeeQuote{Operation: "transfer", Currency: "COP", Amount: 1000000, Fee: 10000, Total: 1010000, RuleID: "r1"},
+		},
+		{
+			testName:       "TestPercentageRoundsHalfUp",
+			currency:       "COP",
+			amount:         150,
+			rules:          []entities.FeeRule{{ID: "r1", Kind: entities.FeePercentage, Bps: 100, Enabled: true}},
+			expectedOutput: entities.FeeQuote{Operation: "transfer", Currency: "COP", Amount: 150, Fee: 2, Total: 152, RuleID: "r1"},
+		},
+		{
+			testName: "TestTiered",
+			currency: "COP",
+			amount:   50000,
+			rules: []entities.FeeRule{{ID: "r1", Kind: entities.FeeTiered, Enabled: true, Tiers: []entities.FeeTier{
+				{UpTo: 10000, FlatAmount: 100},
+				{UpTo: 100000, FlatAmount: 200, Bps: 10},
+				{Bps: 5},
+			}}},
+			expectedOutput: entities.FeeQuote{Operation: "transfer", Currency: "COP", Amount: 50000, Fee: 250, Total: 50250, RuleID: "r1"},
+		},
+		{
+			testName: "TestTieredAboveLastTier",
+			currency: "COP",
+			amount:   200000,
+			rules: []entities.FeeRule{{ID: "r1", Kind: entities.FeeTiered, Enabled: true, Tiers: []entities.FeeTier{
+				{UpTo: 10000, FlatAmount: 100},
+				{UpTo: 100000, FlatAmount: 200, Bps: 10},
+			}}},
+			expectedOutput: entities.FeeQuote{Operation: "transfer", Currency: "COP", Amount: 200000, Fee: 400, Total: 200400, RuleID: "r1"},
+		},
+		{
+			testName: "TestSegmentBeatsCurrencyAndPriority",
+			currency: "USD",
+			amount:   1000,
+			rules: []entities.FeeRule{
+				{ID: "usd", Currency: "USD", Kind: entities.FeeFlat, FlatAmount: 30, Priority: 10, Enabled: true},
+				{ID: "premium", Segment: "premium", Kind: entities.FeeFlat, FlatAmount: 0, Enabled: true},
+				{ID: "eur", Currency: "EUR", Segment: "premium", Kind: entities.FeeFlat, FlatAmount: 5, Enabled: true},
+			},
+			expectedOutput: entities.FeeQuote{Operation: "transfer", Currency: "USD", Amount: 1000, Fee: 0, Total: 1000, RuleID: "premium"},
+		},
+		{
+			testName:       "TestDisabledRule",
+			currency:       "COP",
+			amount:         1000,
+			rules:          []entities.FeeRule{{ID: "r1", Kind: entities.FeeFlat, FlatAmount: 300}},
+			expectedOutput: entities.FeeQuote{Operation: "transfer", Currency: "COP", Amount: 1000, Total: 1000},
+		},
+		{
+			testName:       "TestInvalidCurrency",
+			currency:       "cop",
+			amount:         1000,
+			expectedOutput: entities.FeeQuote{},
+			expectedError:  ErrInvalidCurrency,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			feeRules := &feeRuleRepositoryMock{}
+			feeRules.On("ListFeeRules", mock.Anything, entities.OperationTransfer).Return(tt.rules, nil)
+			service := NewFeeService(&userServiceMock{}, feeRules, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.ComputeFee(context.Background(), user, entities.OperationTransfer, tt.currency, tt.amount)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestCreateFeeRuleService(t *testing.T) {
+	admin := entities.User{ID: "a1", Email: "admin@gmail.com", Role: entities.RoleAdmin}
+	customer := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	valid := entities.FeeRule{Name: "Transfers", Operation: entities.OperationTransfer, Kind: entities.FeePercentage, Bps: 50, Enabled: true}
+
+	testScenarios := []struct {
+		testName       string
+		user           entities.User
+		rule           entities.FeeRule
+		configureMock  func(*feeRuleRepositoryMock)
+		expectedOutput entities.FeeRule
+		expectedError  error
+	}{
+		{
+			testName: "TestCreateFeeRuleService",
+			user:     admin,
+			rule:     valid,
+			configureMock: func(m *feeRuleRepositoryMock) {
+				m.On("CreateFeeRule", mock.Anything, mock.Anything).Return(entities.FeeRule{ID: "r1"}, nil)
+			},
+			expectedOutput: entities.FeeRule{ID: "r1"},
+		},
+		{
+			testName:       "TestAdminRequired",
+			user:           customer,
+			rule:           valid,
+			expectedOutput: entities.FeeRule{},
+			expectedError:  ErrAdminRequired,
+		},
+		{
+			testName:       "TestInvalidOperation",
+			user:           admin,
+			rule:           entities.FeeRule{Operation: "deposit", Kind: entities.FeeFlat},
+			expectedOutput: entities.FeeRule{},
+			expectedError:  ErrInvalidFeeRule,
+		},
+		{
+			testName:       "TestMaxBelowMin",
+			user:           admin,
+			rule:           entities.FeeRule{Operation: entities.OperationWithdrawal, Kind: entities.FeeFlat, MinFee: 100, MaxFee: 50},
+			expectedOutput: entities.FeeRule{},
+			expectedError:  ErrInvalidFeeRule,
+		},
+		{
+			testName: "TestUnboundedTierNotLast",
+			user:     admin,
+			rule: entities.FeeRule{Operation: entities.OperationTransfer, Kind: entities.FeeTiered, Tiers: []entities.FeeTier{
+				{FlatAmount: 100},
+				{UpTo: 1000, FlatAmount: 50},
+			}},
+			expectedOutput: entities.FeeRule{},
+			expectedError:  ErrInvalidFeeRule,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, tt.user.Email).Return(tt.user, nil)
+			feeRules := &feeRuleRepositoryMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(feeRules)
+			}
+			service := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateFeeRule(context.Background(), tt.user.Email, tt.rule)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestDeleteFeeRuleService(t *testing.T) {
+	admin := entities.User{ID: "a1", Email: "admin@gmail.com", Role: entities.RoleAdmin}
+
+	// Prepare
+	users := &userServiceMock{}
+	users.On("GetUserByEmail", mock.Anything, admin.Email).Return(admin, nil)
+	feeRules := &feeRuleRepositoryMock{}
+	feeRules.On("DeleteFeeRule", mock.Anything, "missing").Return(repository_fee.ErrFeeRuleNotFound)
+	service := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+
+	// Act
+	err := service.DeleteFeeRule(context.Background(), admin.Email, "missing")
+
+	// Assert
+	assert.Equal(t, ErrFeeRuleNotFound, err)
+}
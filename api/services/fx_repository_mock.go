@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type quoteRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *quoteRepositoryMock) CreateQuote(quote entities.FXQuote, ctx context.Context) (entities.FXQuote, error) {
+	r := m.Called(ctx, quote)
+	return r.Get(0).(entities.FXQuote), r.Error(1)
+}
+
+func (m *quoteRepositoryMock) GetQuote(id string, ctx context.Context) (entities.FXQuote, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.FXQuote), r.Error(1)
+}
+
+func (m *quoteRepositoryMock) UseQuote(id string, now time.Time, ctx context.Context) error {
+	r := m.Called(ctx, id, now)
+	return r.Error(0)
+}
+
+func (m *quoteRepositoryMock) ReopenQuote(id string, ctx context.Context) error {
+	r := m.Called(ctx, id)
+	return r.Error(0)
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"my_wallet/api/entities"
+	repository_fx "my_wallet/api/respository/fx"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const basisPoints = 10000
+
+type FXService interface {
+	CreateQuote(ctx context.Context, email string, walletID string, from string, to string, amount int64) (entities.FXQuote, error)
+	CreateConversion(ctx context.Context, email string, walletID string, quoteID string) (entities.Conversion, error)
+}
+
+type fxService struct {
+	ctx              context.Context
+	userRepository   repository_user.UserRepository
+	walletRepository repository_wallet.WalletRepository
+	ledgerRepository repository_ledger.LedgerRepository
+	quoteRepository  repository_fx.QuoteRepository
+	rates            repository_fx.RateProvider
+	feeService       FeeService
+	spreadBps        int64
+	quoteTTL         time.Duration
+	limiter          Limiter
+	restrictions     Restrictions
+	members          Memberships
+	logger           logrus.FieldLogger
+}
+
+func NewFXService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, ledgerRepo repository_ledger.LedgerRepository, quoteRepo repository_fx.QuoteRepository, rates repository_fx.RateProvider, feeService FeeService, spreadBps int64, quoteTTL time.Duration, limiter Limiter, restrictions Restrictions, members Memberships, logger logrus.FieldLogger, ctx context.Context) *fxService {
+	return &fxService{
+		ctx:              ctx,
+		userRepository:   userRepo,
+		walletRepository: walletRepo,
+		ledgerRepository: ledgerRepo,
+		quoteRepository:  quoteRepo,
+		rates:            rates,
+		feeService:       feeService,
+		spreadBps:        spreadBps,
+		quoteTTL:         quoteTTL,
+		limiter:          limiter,
+		restrictions:     restrictions,
+		members:          members,
+		logger:           logger,
+	}
+}
+
+// CreateQuote prices the conversion of amount from one currency of the wallet
+// to another and locks the rate for the quote TTL. The customer rate is the
+// provider rate minus the spread; amounts are rounded down. The conversion fee
+// is quoted too.
+func (s *fxService) CreateQuote(ctx context.Context, email string, walletID string, from string, to string, amount int64) (entities.FXQuote, error) {
+	if amount <= 0 {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateQuote", "Error:", ErrInvalidAmount)
+		return entities.FXQuote{}, ErrInvalidAmount
+	}
+	if !entities.ValidCurrency(from) || !entities.ValidCurrency(to) {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateQuote", "Error:", ErrInvalidCurrency)
+		return entities.FXQuote{}, ErrInvalidCurrency
+	}
+	if from == to {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateQuote", "Error:", ErrSameCurrency)
+		return entities.FXQuote{}, ErrSameCurrency
+	}
+	user, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, walletID, entities.PermissionManage)
+	if err != nil {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateQuote", "Error:", err)
+		return entities.FXQuote{}, err
+	}
+	if err := requireActive(user); err != nil {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateQuote", "Error:", err)
+		return entities.FXQuote{}, err
+	}
+	if err := requireKYC(user, entities.FeatureFXConversion); err != nil {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateQuote", "Error:", err)
+		return entities.FXQuote{}, err
+	}
+
+	midRate, err := s.rates.Rate(from, to, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateQuote", "Error:", err)
+		if errors.Is(err, repository_fx.ErrRateNotFound) {
+			return entities.FXQuote{}, ErrRateNotFound
+		}
+		return entities.FXQuote{}, err
+	}
+	rate := new(big.Rat).Mul(midRate, big.NewRat(basisPoints-s.spreadBps, basisPoints))
+	midAmount := convertAmount(amount, midRate)
+	toAmount := convertAmount(amount, rate)
+	if toAmount <= 0 {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateQuote", "Error:", ErrInvalidAmount)
+		return entities.FXQuote{}, ErrInvalidAmount
+	}
+
+	fee, err := s.feeService.ComputeFee(ctx, user, entities.OperationConversion, from, amount)
+	if err != nil {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateQuote", "Error:", err)
+		return entities.FXQuote{}, err
+	}
+
+	now := time.Now().UTC()
+	quote := entities.FXQuote{
+		WalletID:   wallet.ID,
+		UserID:     user.ID,
+		From:       from,
+		To:         to,
+		FromAmount: amount,
+		ToAmount:   toAmount,
+		Fee:        fee.Fee,
+		MidAmount:  midAmount,
+		MidRate:    midRate.FloatString(8),
+		Rate:       rate.FloatString(8),
+		SpreadBps:  s.spreadBps,
+		Status:     entities.QuoteOpen,
+		ExpiresAt:  now.Add(s.quoteTTL),
+		Created_at: now,
+	}
+	return s.quoteRepository.CreateQuote(quote, ctx)
+}
+
+// CreateConversion executes a quote that is still open, unless the wallet is
+// restricted from conversions. Like transfers, what it debits, fee included,
+// is taken out of the limits of the user in the from currency. The entry debits the wallet in the from
+// currency and credits it in the to currency; the FX position account takes
+// the other side at the mid rate and the spread goes to the FX gain/loss
+// account, so each currency balances on its own.
+func (s *fxService) CreateConversion(ctx context.Context, email string, walletID string, quoteID string) (entities.Conversion, error) {
+	user, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, walletID, entities.PermissionManage)
+	if err != nil {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateConversion", "Error:", err)
+		return entities.Conversion{}, err
+	}
+	if err := requireActive(user); err != nil {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateConversion", "Error:", err)
+		return entities.Conversion{}, err
+	}
+	quote, err := s.quoteRepository.GetQuote(quoteID, ctx)
+	if err != nil || quote.WalletID != wallet.ID || quote.UserID != user.ID {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateConversion", "Error:", err)
+		return entities.Conversion{}, ErrQuoteNotFound
+	}
+	if wallet.Balances[quote.From] < quote.FromAmount+quote.Fee {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateConversion", "Error:", ErrInsufficientFunds)
+		return entities.Conversion{}, ErrInsufficientFunds
+	}
+	if s.restrictions != nil {
+		if err := s.restrictions.CheckDebit(ctx, wallet.ID, entities.CapabilityConversion); err != nil {
+			s.logger.Errorln("Layer: fx_services", "Method: CreateConversion", "Error:", err)
+			return entities.Conversion{}, err
+		}
+	}
+	var reservation entities.LimitReservation
+	if s.limiter != nil {
+		reservation, err = s.limiter.Reserve(ctx, user, quote.From, quote.FromAmount)
+		if err != nil {
+			s.logger.Errorln("Layer: fx_services", "Method: CreateConversion", "Error:", err)
+			return entities.Conversion{}, err
+		}
+	}
+	if err := s.quoteRepository.UseQuote(quote.ID, time.Now().UTC(), ctx); err != nil {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateConversion", "Error:", err)
+		if s.limiter != nil {
+			s.limiter.Release(ctx, reservation)
+		}
+		if errors.Is(err, repository_fx.ErrQuoteUnavailable) {
+			return entities.Conversion{}, ErrQuoteExpired
+		}
+		return entities.Conversion{}, err
+	}
+
+	memo := fmt.Sprintf("%s to %s at %s", quote.From, quote.To, quote.Rate)
+	account := entities.WalletAccount(wallet.ID)
+	entry := entities.LedgerEntry{
+		Type: entities.EntryConversion,
+		Memo: memo,
+		Lines: []entities.LedgerLine{
+			{Account: account, WalletID: wallet.ID, Currency: quote.From, Amount: -quote.FromAmount},
+			{Account: entities.AccountFXPosition, Currency: quote.From, Amount: quote.FromAmount},
+			{Account: entities.AccountFXPosition, Currency: quote.To, Amount: -quote.MidAmount},
+			{Account: account, WalletID: wallet.ID, Currency: quote.To, Amount: quote.ToAmount},
+			{Account: entities.AccountFXGainLoss, Currency: quote.To, Amount: quote.MidAmount - quote.ToAmount},
+		},
+		Created_at: time.Now().UTC(),
+	}
+	transactions := []entities.Transaction{
+		{WalletID: wallet.ID, UserID: wallet.UserID, Type: entities.TransactionConversionOut, Status: entities.StatusCompleted, Amount: quote.FromAmount, Currency: quote.From, Memo: memo, InitiatedBy: user.ID},
+		{WalletID: wallet.ID, UserID: wallet.UserID, Type: entities.TransactionConversionIn, Status: entities.StatusCompleted, Amount: quote.ToAmount, Currency: quote.To, Memo: memo, InitiatedBy: user.ID},
+	}
+	if quote.Fee > 0 {
+		entry.Lines = append(entry.Lines, feeLines(wallet.ID, quote.From, quote.Fee)...)
+		transactions = append(transactions, entities.Transaction{WalletID: wallet.ID, UserID: wallet.UserID, Type: entities.TransactionFee, Status: entities.StatusCompleted, Amount: quote.Fee, Currency: quote.From, Memo: "Conversion fee", InitiatedBy: user.ID})
+	}
+	entry, _, err = s.ledgerRepository.PostEntry(entry, transactions, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: fx_services", "Method: CreateConversion", "Error:", err)
+		if reopenErr := s.quoteRepository.ReopenQuote(quote.ID, ctx); reopenErr != nil {
+			s.logger.Errorln("Layer: fx_services", "Method: CreateConversion", "Error:", reopenErr)
+		}
+		if s.limiter != nil {
+			s.limiter.Release(ctx, reservation)
+		}
+		if errors.Is(err, repository_ledger.ErrInsufficientFunds) {
+			return entities.Conversion{}, ErrInsufficientFunds
+		}
+		return entities.Conversion{}, err
+	}
+
+	s.logger.Infoln("Layer: fx_services", "Method: CreateConversion", "Conversion:", entry.ID)
+	return entities.Conversion{
+		ID:         entry.ID,
+		QuoteID:    quote.ID,
+		WalletID:   wallet.ID,
+		From:       quote.From,
+		To:         quote.To,
+		FromAmount: quote.FromAmount,
+		ToAmount:   quote.ToAmount,
+		Fee:        quote.Fee,
+		Rate:       quote.Rate,
+		Created_at: entry.Created_at,
+	}, nil
+}
+
+// convertAmount multiplies amount by rate and rounds down to the cent.
+func convertAmount(amount int64, rate *big.Rat) int64 {
+	converted := new(big.Rat).Mul(big.NewRat(amount, 1), rate)
+	return new(big.Int).Quo(converted.Num(), converted.Denom()).Int64()
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_fx "my_wallet/api/respository/fx"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_limit "my_wallet/api/respository/limit"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateQuoteService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com", Enabled: true, KYCLevel: entities.KYCBasic}
+	wallet := entities.Wallet{ID: "w1", UserID: "u1", Currency: "COP", Balances: map[string]int64{"COP": 1000000}}
+	rates, _ := repository_fx.NewStaticRateProvider(map[string]string{"USD/COP": "4000"})
+
+	testScenarios := []struct {
+		testName       string
+		from           string
+		to             string
+		amount         int64
+		configureMock  func(*userServiceMock, *walletRepositoryMock, *quoteRepositoryMock)
+		expectedOutput entities.FXQuote
+		expectedError  error
+	}{
+		{
+			testName: "TestCreateQuoteService",
+			from:     "COP",
+			to:       "USD",
+			amount:   400000,
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, q *quoteRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+				q.On("CreateQuote", mock.Anything, mock.Anything).Return(entities.FXQuote{ID: "q1", FromAmount: 400000, ToAmount: 99, MidAmount: 100}, nil)
+			},
+			expectedOutput: entities.FXQuote{ID: "q1", FromAmount: 400000, ToAmount: 99, MidAmount: 100},
+			expectedError:  nil,
+		},
+		{
+			testName: "TestCreateQuoteWithoutKYC",
+			from:     "COP",
+			to:       "USD",
+			amount:   400000,
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, q *quoteRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(entities.User{ID: "u1", Email: "alexer@gmail.com", Enabled: true}, nil)
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+			},
+			expectedOutput: entities.FXQuote{},
+			expectedError:  ErrKYCLevelRequired,
+		},
+		{
+			testName:       "TestSameCurrency",
+			from:           "COP",
+			to:             "COP",
+			amount:         100,
+			expectedOutput: entities.FXQuote{},
+			expectedError:  ErrSameCurrency,
+		},
+		{
+			testName:       "TestInvalidCurrency",
+			from:           "cop",
+			to:             "USD",
+			amount:         100,
+			expectedOutput: entities.FXQuote{},
+			expectedError:  ErrInvalidCurrency,
+		},
+		{
+			testName:       "TestInvalidAmount",
+			from:           "COP",
+			to:             "USD",
+			amount:         0,
+			expectedOutput: entities.FXQuote{},
+			expectedError:  ErrInvalidAmount,
+		},
+		{
+			testName: "TestRateNotFound",
+			from:     "COP",
+			to:       "EUR",
+			amount:   100,
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, q *quoteRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+			},
+			expectedOutput: entities.FXQuote{},
+			expectedError:  ErrRateNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			wallets := &walletRepositoryMock{}
+			quotes := &quoteRepositoryMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(users, wallets, quotes)
+			}
+			feeRules := &feeRuleRepositoryMock{}
+			feeRules.On("ListFeeRules", mock.Anything, entities.OperationConversion).Return([]entities.FeeRule{}, nil)
+			fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+			service := NewFXService(users, wallets, &ledgerRepositoryMock{}, quotes, rates, fees, 100, 30*time.Second, nil, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateQuote(context.Background(), "alexer@gmail.com", "w1", tt.from, tt.to, tt.amount)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+			if tt.testName == "TestCreateQuoteService" {
+				quote := quotes.Calls[0].Arguments.Get(1).(entities.FXQuote)
+				assert.Equal(t, int64(100), quote.MidAmount)
+				assert.Equal(t, int64(99), quote.ToAmount)
+				assert.Equal(t, "0.00024750", quote.Rate)
+			}
+		})
+	}
+}
+
+func TestCreateConversionService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com", Enabled: true}
+	wallet := entities.Wallet{ID: "w1", UserID: "u1", Currency: "COP", Balances: map[string]int64{"COP": 1000000}}
+	quote := entities.FXQuote{ID: "q1", WalletID: "w1", UserID: "u1", From: "COP", To: "USD", FromAmount: 400000, ToAmount: 99, MidAmount: 100, Rate: "0.00024750", Status: entities.QuoteOpen}
+
+	testScenarios := []struct {
+		testName       string
+		configureMock  func(*walletRepositoryMock, *ledgerRepositoryMock, *quoteRepositoryMock)
+		expectedOutput entities.Conversion
+		expectedError  error
+	}{
+		{
+			testName: "TestCreateConversionService",
+			configureMock: func(w *walletRepositoryMock, l *ledgerRepositoryMock, q *quoteRepositoryMock) {
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+				q.On("GetQuote", mock.Anything, "q1").Return(quote, nil)
+				q.On("UseQuote", mock.Anything, "q1", mock.Anything).Return(nil)
+				l.On("PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+					return repository_ledger.IsBalanced(e) && e.Lines[4].Account == entities.AccountFXGainLoss && e.Lines[4].Amount == 1
+				}), mock.Anything).Return(entities.LedgerEntry{ID: "entry"}, nil)
+			},
+			expectedOutput: entities.Conversion{ID: "entry", QuoteID: "q1", WalletID: "w1", From: "COP", To: "USD", FromAmount: 400000, ToAmount: 99, Rate: "0.00024750"},
+			expectedError:  nil,
+		},
+		{
+			testName: "TestCreateConversionWithFee",
+			configureMock: func(w *walletRepositoryMock, l *ledgerRepositoryMock, q *quoteRepositoryMock) {
+				withFee := quote
+				withFee.Fee = 4000
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+				q.On("GetQuote", mock.Anything, "q1").Return(withFee, nil)
+				q.On("UseQuote", mock.Anything, "q1", mock.Anything).Return(nil)
+				l.On("PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+					return repository_ledger.IsBalanced(e) && len(e.Lines) == 7 && e.Lines[5].Amount == -4000 && e.Lines[6].Account == entities.AccountFeeRevenue
+				}), mock.Anything).Return(entities.LedgerEntry{ID: "entry"}, nil)
+			},
+			expectedOutput: entities.Conversion{ID: "entry", QuoteID: "q1", WalletID: "w1", From: "COP", To: "USD", FromAmount: 400000, ToAmount: 99, Fee: 4000, Rate: "0.00024750"},
+			expectedError:  nil,
+		},
+		{
+			testName: "TestQuoteFromOtherWallet",
+			configureMock: func(w *walletRepositoryMock, l *ledgerRepositoryMock, q *quoteRepositoryMock) {
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+				q.On("GetQuote", mock.Anything, "q1").Return(entities.FXQuote{ID: "q1", WalletID: "w9"}, nil)
+			},
+			expectedOutput: entities.Conversion{},
+			expectedError:  ErrQuoteNotFound,
+		},
+		{
+			testName: "TestQuoteExpired",
+			configureMock: func(w *walletRepositoryMock, l *ledgerRepositoryMock, q *quoteRepositoryMock) {
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+				q.On("GetQuote", mock.Anything, "q1").Return(quote, nil)
+				q.On("UseQuote", mock.Anything, "q1", mock.Anything).Return(repository_fx.ErrQuoteUnavailable)
+			},
+			expectedOutput: entities.Conversion{},
+			expectedError:  ErrQuoteExpired,
+		},
+		{
+			testName: "TestInsufficientFundsWhilePosting",
+			configureMock: func(w *walletRepositoryMock, l *ledgerRepositoryMock, q *quoteRepositoryMock) {
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+				q.On("GetQuote", mock.Anything, "q1").Return(quote, nil)
+				q.On("UseQuote", mock.Anything, "q1", mock.Anything).Return(nil)
+				q.On("ReopenQuote", mock.Anything, "q1").Return(nil)
+				l.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{}, repository_ledger.ErrInsufficientFunds)
+			},
+			expectedOutput: entities.Conversion{},
+			expectedError:  ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			wallets := &walletRepositoryMock{}
+			ledger := &ledgerRepositoryMock{}
+			quotes := &quoteRepositoryMock{}
+			tt.configureMock(wallets, ledger, quotes)
+			service := NewFXService(users, wallets, ledger, quotes, nil, nil, 100, 30*time.Second, nil, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateConversion(context.Background(), "alexer@gmail.com", "w1", "q1")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			result.Created_at = tt.expectedOutput.Created_at
+			assert.Equal(t, tt.expectedOutput, result)
+			quotes.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateConversionReservesLimits(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com", Enabled: true}
+	wallet := entities.Wallet{ID: "w1", UserID: "u1", Currency: "COP", Balances: map[string]int64{"COP": 1000000}}
+	quote := entities.FXQuote{ID: "q1", WalletID: "w1", UserID: "u1", From: "COP", To: "USD", FromAmount: 400000, ToAmount: 99, MidAmount: 100, Fee: 4000, Rate: "0.00024750", Status: entities.QuoteOpen}
+	policy := repository_limit.NewStaticLimitPolicy(map[string]map[string]entities.Limits{
+		entities.KYCNone: {"COP": {Daily: 500000}},
+	})
+
+	testScenarios := []struct {
+		testName        string
+		reserveError    error
+		postError       error
+		expectedError   error
+		expectedRelease bool
+	}{
+		{
+			testName:     "TestConversionOverDailyLimit",
+			reserveError: repository_limit.ErrLimitReached,
+			// Reserve wraps the repository error in a LimitExceededError.
+			expectedError: ErrLimitExceeded,
+		},
+		{
+			testName:        "TestConversionNotPostedReleasesLimits",
+			postError:       repository_ledger.ErrInsufficientFunds,
+			expectedError:   ErrInsufficientFunds,
+			expectedRelease: true,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+			quotes := &quoteRepositoryMock{}
+			quotes.On("GetQuote", mock.Anything, "q1").Return(quote, nil)
+			quotes.On("UseQuote", mock.Anything, "q1", mock.Anything).Return(nil)
+			quotes.On("ReopenQuote", mock.Anything, "q1").Return(nil)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{}, tt.postError)
+			limits := &limitRepositoryMock{}
+			limits.On("Reserve", mock.Anything, mock.Anything, int64(400000), int64(500000), mock.Anything).Return(int64(400000), tt.reserveError)
+			limits.On("Release", mock.Anything, mock.Anything, int64(400000)).Return(nil)
+			limiter := NewLimitService(users, limits, policy, logrus.StandardLogger(), context.Background())
+			service := NewFXService(users, wallets, ledger, quotes, nil, nil, 100, 30*time.Second, limiter, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			_, err := service.CreateConversion(context.Background(), "alexer@gmail.com", "w1", "q1")
+
+			// Assert
+			assert.True(t, errors.Is(err, tt.expectedError))
+			limits.AssertCalled(t, "Reserve", mock.Anything, mock.Anything, int64(400000), int64(500000), mock.Anything)
+			if tt.expectedRelease {
+				limits.AssertCalled(t, "Release", mock.Anything, mock.Anything, int64(400000))
+			} else {
+				limits.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, mock.Anything)
+				quotes.AssertNotCalled(t, "UseQuote", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type idempotencyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *idempotencyRepositoryMock) CreateRecord(record entities.IdempotencyRecord, ctx context.Context) error {
+	r := m.Called(ctx, record)
+	return r.Error(0)
+}
+
+func (m *idempotencyRepositoryMock) GetRecord(scope string, key string, ctx context.Context) (entities.IdempotencyRecord, error) {
+	r := m.Called(ctx, scope, key)
+	return r.Get(0).(entities.IdempotencyRecord), r.Error(1)
+}
+
+func (m *idempotencyRepositoryMock) CompleteRecord(scope string, key string, response []byte, ctx context.Context) error {
+	r := m.Called(ctx, scope, key, response)
+	return r.Error(0)
+}
+
+func (m *idempotencyRepositoryMock) HoldRecord(scope string, key string, reviewID string, ctx context.Context) error {
+	r := m.Called(ctx, scope, key, reviewID)
+	return r.Error(0)
+}
+
+func (m *idempotencyRepositoryMock) DeleteRecord(scope string, key string, ctx context.Context) error {
+	r := m.Called(ctx, scope, key)
+	return r.Error(0)
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_idempotency "my_wallet/api/respository/idempotency"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	idempotencyKeyTTL       = 24 * time.Hour
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, scope string, key string, fingerprint string) (entities.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, scope string, key string, response []byte) error
+	Hold(ctx context.Context, scope string, key string, reviewID string) error
+	Release(ctx context.Context, scope string, key string) error
+}
+
+type idempotencyService struct {
+	ctx        context.Context
+	repository repository_idempotency.IdempotencyRepository
+	logger     logrus.FieldLogger
+}
+
+func NewIdempotencyService(repo repository_idempotency.IdempotencyRepository, logger logrus.FieldLogger, ctx context.Context) *idempotencyService {
+	return &idempotencyService{
+		ctx:        ctx,
+		repository: repo,
+		logger:     logger,
+	}
+}
+
+// Begin reserves the key for a request. It returns replay true with the stored
+// record when the same request already completed, and an error when the key
+// belongs to a different request or to one that is still running.
+func (s *idempotencyService) Begin(ctx context.Context, scope string, key string, fingerprint string) (entities.IdempotencyRecord, bool, error) {
+	if len(key) == 0 || len(key) > maxIdempotencyKeyLength {
+		s.logger.Errorln("Layer: idempotency_services", "Method: Begin", "Error:", ErrInvalidIdempotencyKey)
+		return entities.IdempotencyRecord{}, false, ErrInvalidIdempotencyKey
+	}
+	now := time.Now().UTC()
+	record := entities.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      entities.IdempotencyPending,
+		Created_at:  now,
+		ExpiresAt:   now.Add(idempotencyKeyTTL),
+	}
+	err := s.repository.CreateRecord(record, ctx)
+	if err == nil {
+		return record, false, nil
+	}
+	if !errors.Is(err, repository_idempotency.ErrRecordExists) {
+		s.logger.Errorln("Layer: idempotency_services", "Method: Begin", "Error:", err)
+		return entities.IdempotencyRecord{}, false, err
+	}
+
+	stored, err := s.repository.GetRecord(scope, key, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: idempotency_services", "Method: Begin", "Error:", err)
+		return entities.IdempotencyRecord{}, false, err
+	}
+	if stored.Fingerprint != fingerprint {
+		s.logger.Errorln("Layer: idempotency_services", "Method: Begin", "Error:", ErrIdempotencyKeyReused)
+		return entities.IdempotencyRecord{}, false, ErrIdempotencyKeyReused
+	}
+	if stored.Status != entities.IdempotencyCompleted {
+		s.logger.Errorln("Layer: idempotency_services", "Method: Begin", "Error:", ErrIdempotencyInProgress)
+		return entities.IdempotencyRecord{}, false, ErrIdempotencyInProgress
+	}
+	s.logger.Infoln("Layer: idempotency_services", "Method: Begin", "Replaying key:", key)
+	return stored, true, nil
+}
+
+// Complete stores the response produced for the key.
+func (s *idempotencyService) Complete(ctx context.Context, scope string, key string, response []byte) error {
+	return s.repository.CompleteRecord(scope, key, response, ctx)
+}
+
+// Hold finishes the key of a request that was held for review, so a retry
+// gets the same review back instead of queuing another one.
+func (s *idempotencyService) Hold(ctx context.Context, scope string, key string, reviewID string) error {
+	return s.repository.HoldRecord(scope, key, reviewID, ctx)
+}
+
+// Release frees the key of a request that failed, so the client can retry it.
+func (s *idempotencyService) Release(ctx context.Context, scope string, key string) error {
+	return s.repository.DeleteRecord(scope, key, ctx)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_idempotency "my_wallet/api/respository/idempotency"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBeginIdempotencyService(t *testing.T) {
+	testScenarios := []struct {
+		testName       string
+		key            string
+		configureMock  func(*idempotencyRepositoryMock)
+		expectedReplay bool
+		expectedOutput entities.IdempotencyRecord
+		expectedError  error
+	}{
+		{
+			testName: "TestNewKey",
+			key:      "abc",
+			configureMock: func(m *idempotencyRepositoryMock) {
+				m.On("CreateRecord", mock.Anything, mock.AnythingOfType("entities.IdempotencyRecord")).Return(nil)
+			},
+			expectedReplay: false,
+			expectedOutput: entities.IdempotencyRecord{Scope: "scope", Key: "abc", Fingerprint: "f1", Status: entities.IdempotencyPending},
+		},
+		{
+			testName: "TestReplayCompletedKey",
+			key:      "abc",
+			configureMock: func(m *idempotencyRepositoryMock) {
+				m.On("CreateRecord", mock.Anything, mock.Anything).Return(repository_idempotency.ErrRecordExists)
+				m.On("GetRecord", mock.Anything, "scope", "abc").Return(entities.IdempotencyRecord{Fingerprint: "f1", Status: entities.IdempotencyCompleted, Response: []byte("{}")}, nil)
+			},
+			expectedReplay: true,
+			expectedOutput: entities.IdempotencyRecord{Fingerprint: "f1", Status: entities.IdempotencyCompleted, Response: []byte("{}")},
+		},
+		{
+			testName: "TestKeyReusedWithDifferentBody",
+			key:      "abc",
+			configureMock: func(m *idempotencyRepositoryMock) {
+				m.On("CreateRecord", mock.Anything, mock.Anything).Return(repository_idempotency.ErrRecordExists)
+				m.On("GetRecord", mock.Anything, "scope", "abc").Return(entities.IdempotencyRecord{Fingerprint: "f2", Status: entities.IdempotencyCompleted}, nil)
+			},
+			expectedOutput: entities.IdempotencyRecord{},
+			expectedError:  ErrIdempotencyKeyReused,
+		},
+		{
+			testName: "TestKeyInProgress",
+			key:      "abc",
+			configureMock: func(m *idempotencyRepositoryMock) {
+				m.On("CreateRecord", mock.Anything, mock.Anything).Return(repository_idempotency.ErrRecordExists)
+				m.On("GetRecord", mock.Anything, "scope", "abc").Return(entities.IdempotencyRecord{Fingerprint: "f1", Status: entities.IdempotencyPending}, nil)
+			},
+			expectedOutput: entities.IdempotencyRecord{},
+			expectedError:  ErrIdempotencyInProgress,
+		},
+		{
+			testName:       "TestKeyTooLong",
+			key:            strings.Repeat("k", 256),
+			expectedOutput: entities.IdempotencyRecord{},
+			expectedError:  ErrInvalidIdempotencyKey,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			m := &idempotencyRepositoryMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(m)
+			}
+			service := NewIdempotencyService(m, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, replay, err := service.Begin(context.Background(), "scope", tt.key, "f1")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedReplay, replay)
+			result.Created_at = tt.expectedOutput.Created_at
+			result.ExpiresAt = tt.expectedOutput.ExpiresAt
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
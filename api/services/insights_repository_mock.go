@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_insights "my_wallet/api/respository/insights"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type insightsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *insightsRepositoryMock) AggregateTransactions(walletID string, currency string, from time.Time, to time.Time, unit string, ctx context.Context) ([]entities.InsightRow, error) {
+	r := m.Called(ctx, walletID, currency, from, to, unit)
+	return r.Get(0).([]entities.InsightRow), r.Error(1)
+}
+
+func (m *insightsRepositoryMock) AggregateRollups(walletID string, currency string, from time.Time, to time.Time, unit string, ctx context.Context) ([]entities.InsightRow, error) {
+	r := m.Called(ctx, walletID, currency, from, to, unit)
+	return r.Get(0).([]entities.InsightRow), r.Error(1)
+}
+
+func (m *insightsRepositoryMock) GetRollupState(ctx context.Context) (repository_insights.RollupState, error) {
+	r := m.Called(ctx)
+	return r.Get(0).(repository_insights.RollupState), r.Error(1)
+}
+
+func (m *insightsRepositoryMock) SaveRollupState(state repository_insights.RollupState, ctx context.Context) error {
+	r := m.Called(ctx, state)
+	return r.Error(0)
+}
+
+func (m *insightsRepositoryMock) FirstTransactionDay(ctx context.Context) (time.Time, error) {
+	r := m.Called(ctx)
+	return r.Get(0).(time.Time), r.Error(1)
+}
+
+func (m *insightsRepositoryMock) RollupDays(walletID string, from time.Time, to time.Time, ctx context.Context) error {
+	r := m.Called(ctx, walletID, from, to)
+	return r.Error(0)
+}
+
+func (m *insightsRepositoryMock) RecategorizedDays(since time.Time, before time.Time, ctx context.Context) ([]repository_insights.WalletDay, error) {
+	r := m.Called(ctx, since, before)
+	return r.Get(0).([]repository_insights.WalletDay), r.Error(1)
+}
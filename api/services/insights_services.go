@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_insights "my_wallet/api/respository/insights"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxRollupDays bounds the days rolled up in a run, so that the first
+	// runs over a long history are spread over several ticks.
+	maxRollupDays   = 31
+	maxTopMerchants = 5
+)
+
+// insightPeriods are the days of history each period covers, including today,
+// and the granularity of its series when none is asked for.
+var insightPeriods = map[string]struct {
+	days        int
+	granularity string
+}{
+	entities.InsightWeek:    {7, entities.GranularityDay},
+	entities.InsightMonth:   {30, entities.GranularityDay},
+	entities.InsightQuarter: {91, entities.GranularityWeek},
+	entities.InsightYear:    {365, entities.GranularityMonth},
+}
+
+type InsightsService interface {
+	GetInsights(ctx context.Context, email string, walletID string, period string, granularity string) (entities.Insights, error)
+	RollupDays(ctx context.Context, now time.Time) (int, error)
+}
+
+type insightsService struct {
+	ctx                context.Context
+	userRepository     repository_user.UserRepository
+	walletRepository   repository_wallet.WalletRepository
+	insightsRepository repository_insights.InsightsRepository
+	rollups            bool
+	members            Memberships
+	logger             logrus.FieldLogger
+}
+
+// NewInsightsService builds the insights service. With rollups, the days
+// already rolled up are read from the daily rollups instead of the
+// transactions.
+func NewInsightsService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, insightsRepo repository_insights.InsightsRepository, rollups bool, members Memberships, logger logrus.FieldLogger, ctx context.Context) *insightsService {
+	return &insightsService{
+		ctx:                ctx,
+		userRepository:     userRepo,
+		walletRepository:   walletRepo,
+		insightsRepository: insightsRepo,
+		rollups:            rollups,
+		members:            members,
+		logger:             logger,
+	}
+}
+
+// GetInsights summarizes the income and expense of the user's wallet over the
+// period ending now.
+func (s *insightsService) GetInsights(ctx context.Context, email string, walletID string, period string, granularity string) (entities.Insights, error) {
+	now := time.Now().UTC()
+	if period == "" {
+		period = entities.InsightMonth
+	}
+	from, granularity, err := insightWindow(period, granularity, now)
+	if err != nil {
+		s.logger.Errorln("Layer: insights_services", "Method: GetInsights", "Error:", err)
+		return entities.Insights{}, err
+	}
+	_, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, walletID, entities.PermissionView)
+	if err != nil {
+		s.logger.Errorln("Layer: insights_services", "Method: GetInsights", "Error:", err)
+		return entities.Insights{}, err
+	}
+
+	split := from
+	if s.rollups {
+		state, err := s.insightsRepository.GetRollupState(ctx)
+		if err != nil {
+			s.logger.Errorln("Layer: insights_services", "Method: GetInsights", "Error:", err)
+			return entities.Insights{}, err
+		}
+		if state.Through.After(split) {
+			split = state.Through
+		}
+	}
+	rows := []entities.InsightRow{}
+	if split.After(from) {
+		rolled, err := s.insightsRepository.AggregateRollups(wallet.ID, wallet.Currency, from, split, granularity, ctx)
+		if err != nil {
+			s.logger.Errorln("Layer: insights_services", "Method: GetInsights", "Error:", err)
+			return entities.Insights{}, err
+		}
+		rows = append(rows, rolled...)
+	}
+	if split.Before(now) {
+		live, err := s.insightsRepository.AggregateTransactions(wallet.ID, wallet.Currency, split, now, granularity, ctx)
+		if err != nil {
+			s.logger.Errorln("Layer: insights_services", "Method: GetInsights", "Error:", err)
+			return entities.Insights{}, err
+		}
+		rows = append(rows, live...)
+	}
+
+	insights := buildInsights(rows, from, now, granularity)
+	insights.WalletID = wallet.ID
+	insights.Currency = wallet.Currency
+	insights.Period = period
+	for i, merchant := range insights.TopMerchants {
+		if user, err := s.userRepository.GetUser(merchant.Key, ctx); err == nil {
+			insights.TopMerchants[i].Name = user.Name
+		}
+	}
+	return insights, nil
+}
+
+// RollupDays rolls up the days that ended since the last run, at most
+// maxRollupDays of them, and rolls up again the days with transactions that
+// were recategorized since. It returns how many days it rolled up.
+func (s *insightsService) RollupDays(ctx context.Context, now time.Time) (int, error) {
+	if !s.rollups {
+		return 0, nil
+	}
+	state, err := s.insightsRepository.GetRollupState(ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: insights_services", "Method: RollupDays", "Error:", err)
+		return 0, err
+	}
+	today := truncateTime(now, entities.GranularityDay)
+	checked := now
+	if state.Through.IsZero() {
+		if state.Through, err = s.insightsRepository.FirstTransactionDay(ctx); err != nil {
+			s.logger.Errorln("Layer: insights_services", "Method: RollupDays", "Error:", err)
+			return 0, err
+		}
+		if state.Through.IsZero() {
+			state.Through = today
+		}
+	}
+
+	days := 0
+	if state.Through.Before(today) {
+		end := state.Through.AddDate(0, 0, maxRollupDays)
+		if end.After(today) {
+			end = today
+		}
+		if err := s.insightsRepository.RollupDays("", state.Through, end, ctx); err != nil {
+			s.logger.Errorln("Layer: insights_services", "Method: RollupDays", "Error:", err)
+			return 0, err
+		}
+		days = int(end.Sub(state.Through).Hours() / 24)
+		state.Through = end
+	}
+	if !state.CheckedAt.IsZero() {
+		stale, err := s.insightsRepository.RecategorizedDays(state.CheckedAt, state.Through, ctx)
+		if err != nil {
+			s.logger.Errorln("Layer: insights_services", "Method: RollupDays", "Error:", err)
+			return days, err
+		}
+		for _, day := range stale {
+			if err := s.insightsRepository.RollupDays(day.WalletID, day.Day, day.Day.AddDate(0, 0, 1), ctx); err != nil {
+				s.logger.Errorln("Layer: insights_services", "Method: RollupDays", "Error:", err)
+				return days, err
+			}
+			days++
+		}
+	}
+	state.CheckedAt = checked
+	if err := s.insightsRepository.SaveRollupState(state, ctx); err != nil {
+		s.logger.Errorln("Layer: insights_services", "Method: RollupDays", "Error:", err)
+		return days, err
+	}
+	return days, nil
+}
+
+// insightWindow returns the start of the period ending at now, aligned to the
+// granularity so that the first bucket is whole, and the granularity to use.
+func insightWindow(period string, granularity string, now time.Time) (time.Time, string, error) {
+	window, ok := insightPeriods[period]
+	if !ok {
+		return time.Time{}, "", ErrInvalidInsightPeriod
+	}
+	if granularity == "" {
+		granularity = window.granularity
+	}
+	if granularity != entities.GranularityDay && granularity != entities.GranularityWeek && granularity != entities.GranularityMonth {
+		return time.Time{}, "", ErrInvalidInsightPeriod
+	}
+	start := truncateTime(now, entities.GranularityDay).AddDate(0, 0, 1-window.days)
+	return truncateTime(start, granularity), granularity, nil
+}
+
+// truncateTime rounds t down to the start of its day, ISO week or month in
+// UTC, as the aggregation pipelines do.
+func truncateTime(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch granularity {
+	case entities.GranularityWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case entities.GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func nextBucket(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case entities.GranularityWeek:
+		return t.AddDate(0, 0, 7)
+	case entities.GranularityMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// buildInsights adds up the rows into totals, a series with a bucket for every
+// unit in [from, to) and the counterparties the wallet spent the most with.
+func buildInsights(rows []entities.InsightRow, from time.Time, to time.Time, granularity string) entities.Insights {
+	insights := entities.Insights{Granularity: granularity, From: from, To: to}
+	categories := map[string]*entities.InsightTotal{}
+	counterparties := map[string]*entities.InsightTotal{}
+	buckets := map[time.Time]*entities.InsightBucket{}
+	for bucket := from; bucket.Before(to); bucket = nextBucket(bucket, granularity) {
+		insights.Series = append(insights.Series, entities.InsightBucket{Start: bucket})
+	}
+	for i := range insights.Series {
+		buckets[insights.Series[i].Start] = &insights.Series[i]
+	}
+
+	for _, row := range rows {
+		insights.Income += row.Income
+		insights.Expense += row.Expense
+		addInsightTotal(categories, row.Category, row)
+		if row.CounterpartyUserID != "" {
+			addInsightTotal(counterparties, row.CounterpartyUserID, row)
+		}
+		if bucket, ok := buckets[row.Bucket.UTC()]; ok {
+			bucket.Income += row.Income
+			bucket.Expense += row.Expense
+			bucket.Count += row.Count
+		}
+	}
+	insights.Net = insights.Income - insights.Expense
+	insights.ByCategory = sortedInsightTotals(categories)
+	insights.ByCounterparty = sortedInsightTotals(counterparties)
+	insights.TopMerchants = []entities.InsightTotal{}
+	for _, total := range insights.ByCounterparty {
+		if total.Expense > 0 && len(insights.TopMerchants) < maxTopMerchants {
+			insights.TopMerchants = append(insights.TopMerchants, total)
+		}
+	}
+	return insights
+}
+
+func addInsightTotal(totals map[string]*entities.InsightTotal, key string, row entities.InsightRow) {
+	total, ok := totals[key]
+	if !ok {
+		total = &entities.InsightTotal{Key: key}
+		totals[key] = total
+	}
+	total.Income += row.Income
+	total.Expense += row.Expense
+	total.Count += row.Count
+}
+
+// sortedInsightTotals orders the totals by expense, then income, largest
+// first.
+func sortedInsightTotals(totals map[string]*entities.InsightTotal) []entities.InsightTotal {
+	sorted := make([]entities.InsightTotal, 0, len(totals))
+	for _, total := range totals {
+		sorted = append(sorted, *total)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Expense != sorted[j].Expense {
+			return sorted[i].Expense > sorted[j].Expense
+		}
+		if sorted[i].Income != sorted[j].Income {
+			return sorted[i].Income > sorted[j].Income
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_insights "my_wallet/api/respository/insights"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsightWindow(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 4, 10, 15, 0, 0, 0, time.UTC)
+
+	testScenarios := []struct {
+		testName            string
+		period              string
+		granularity         string
+		expectedFrom        time.Time
+		expectedGranularity string
+		expectedError       error
+	}{
+		{
+			testName:            "week by day",
+			period:              entities.InsightWeek,
+			expectedFrom:        time.Date(2024, 4, 4, 0, 0, 0, 0, time.UTC),
+			expectedGranularity: entities.GranularityDay,
+		},
+		{
+			testName:            "quarter by week starts on monday",
+			period:              entities.InsightQuarter,
+			expectedFrom:        time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+			expectedGranularity: entities.GranularityWeek,
+		},
+		{
+			testName:            "year by month",
+			period:              entities.InsightYear,
+			expectedFrom:        time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+			expectedGranularity: entities.GranularityMonth,
+		},
+		{
+			testName:            "month by week",
+			period:              entities.InsightMonth,
+			granularity:         entities.GranularityWeek,
+			expectedFrom:        time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+			expectedGranularity: entities.GranularityWeek,
+		},
+		{
+			testName:      "unknown period",
+			period:        "decade",
+			expectedError: ErrInvalidInsightPeriod,
+		},
+		{
+			testName:      "unknown granularity",
+			period:        entities.InsightMonth,
+			granularity:   "hour",
+			expectedError: ErrInvalidInsightPeriod,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			from, granularity, err := insightWindow(tt.period, tt.granularity, now)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedFrom, from)
+			assert.Equal(t, tt.expectedGranularity, granularity)
+		})
+	}
+}
+
+func TestBuildInsights(t *testing.T) {
+	// Prepare
+	from := time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 10, 15, 0, 0, 0, time.UTC)
+	rows := []entities.InsightRow{
+		{Bucket: from, Category: entities.CategoryIncome, CounterpartyUserID: "employer", Income: 500000, Count: 1},
+		{Bucket: from, Category: entities.CategoryGroceries, CounterpartyUserID: "market", Expense: 40000, Count: 2},
+		{Bucket: from.AddDate(0, 0, 2), Category: entities.CategoryGroceries, CounterpartyUserID: "market", Expense: 10000, Count: 1},
+		{Bucket: from.AddDate(0, 0, 2), Category: entities.CategoryRent, CounterpartyUserID: "landlord", Expense: 300000, Count: 1},
+		{Bucket: from.AddDate(0, 0, 2), Category: entities.CategoryFees, Expense: 500, Count: 1},
+	}
+
+	// Act
+	insights := buildInsights(rows, from, to, entities.GranularityDay)
+
+	// Assert
+	assert.Equal(t, int64(500000), insights.Income)
+	assert.Equal(t, int64(350500), insights.Expense)
+	assert.Equal(t, int64(149500), insights.Net)
+	assert.Equal(t, []entities.InsightTotal{
+		{Key: entities.CategoryRent, Expense: 300000, Count: 1},
+		{Key: entities.CategoryGroceries, Expense: 50000, Count: 3},
+		{Key: entities.CategoryFees, Expense: 500, Count: 1},
+		{Key: entities.CategoryIncome, Income: 500000, Count: 1},
+	}, insights.ByCategory)
+	assert.Equal(t, []entities.InsightTotal{
+		{Key: "landlord", Expense: 300000, Count: 1},
+		{Key: "market", Expense: 50000, Count: 3},
+	}, insights.TopMerchants)
+	assert.Len(t, insights.ByCounterparty, 3)
+	assert.Equal(t, []entities.InsightBucket{
+		{Start: from, Income: 500000, Expense: 40000, Count: 3},
+		{Start: from.AddDate(0, 0, 1)},
+		{Start: from.AddDate(0, 0, 2), Expense: 310500, Count: 3},
+	}, insights.Series)
+}
+
+func TestRollupDays(t *testing.T) {
+	now := time.Date(2024, 4, 10, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+	checkedAt := now.Add(-time.Minute)
+
+	testScenarios := []struct {
+		testName      string
+		state         repository_insights.RollupState
+		firstDay      time.Time
+		recategorized []repository_insights.WalletDay
+		expectedFrom  time.Time
+		expectedTo    time.Time
+		expectedState repository_insights.RollupState
+		expectedDays  int
+	}{
+		{
+			testName:      "first run over a long history",
+			firstDay:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedFrom:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:    time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedState: repository_insights.RollupState{Through: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), CheckedAt: now},
+			expectedDays:  maxRollupDays,
+		},
+		{
+			testName:      "yesterday ended",
+			state:         repository_insights.RollupState{Through: today.AddDate(0, 0, -1), CheckedAt: checkedAt},
+			recategorized: []repository_insights.WalletDay{{WalletID: "w1", Day: today.AddDate(0, 0, -5)}},
+			expectedFrom:  today.AddDate(0, 0, -1),
+			expectedTo:    today,
+			expectedState: repository_insights.RollupState{Through: today, CheckedAt: now},
+			expectedDays:  2,
+		},
+		{
+			testName:      "up to date",
+			state:         repository_insights.RollupState{Through: today, CheckedAt: checkedAt},
+			expectedState: repository_insights.RollupState{Through: today, CheckedAt: now},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			repo := &insightsRepositoryMock{}
+			repo.On("GetRollupState", mock.Anything).Return(tt.state, nil)
+			repo.On("FirstTransactionDay", mock.Anything).Return(tt.firstDay, nil)
+			repo.On("RollupDays", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			repo.On("RecategorizedDays", mock.Anything, checkedAt, mock.Anything).Return(tt.recategorized, nil)
+			repo.On("SaveRollupState", mock.Anything, mock.Anything).Return(nil)
+			service := NewInsightsService(&userServiceMock{}, &walletRepositoryMock{}, repo, true, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			days, err := service.RollupDays(context.Background(), now)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedDays, days)
+			if !tt.expectedFrom.IsZero() {
+				repo.AssertCalled(t, "RollupDays", mock.Anything, "", tt.expectedFrom, tt.expectedTo)
+			}
+			for _, day := range tt.recategorized {
+				repo.AssertCalled(t, "RollupDays", mock.Anything, day.WalletID, day.Day, day.Day.AddDate(0, 0, 1))
+			}
+			repo.AssertCalled(t, "SaveRollupState", mock.Anything, tt.expectedState)
+		})
+	}
+}
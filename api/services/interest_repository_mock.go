@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_interest "my_wallet/api/respository/interest"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type interestRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *interestRepositoryMock) EndOfDayBalances(at time.Time, ctx context.Context) ([]entities.AccountBalance, error) {
+	r := m.Called(ctx, at)
+	return r.Get(0).([]entities.AccountBalance), r.Error(1)
+}
+
+func (m *interestRepositoryMock) CreateAccrual(accrual entities.InterestAccrual, ctx context.Context) (entities.InterestAccrual, error) {
+	r := m.Called(ctx, accrual)
+	if created, ok := r.Get(0).(func(entities.InterestAccrual) entities.InterestAccrual); ok {
+		return created(accrual), r.Error(1)
+	}
+	return r.Get(0).(entities.InterestAccrual), r.Error(1)
+}
+
+func (m *interestRepositoryMock) UnpaidBalances(accountIDs []string, before time.Time, ctx context.Context) ([]entities.InterestBalance, error) {
+	r := m.Called(ctx, accountIDs, before)
+	return r.Get(0).([]entities.InterestBalance), r.Error(1)
+}
+
+func (m *interestRepositoryMock) CreatePayout(payout entities.InterestPayout, ctx context.Context) (entities.InterestPayout, error) {
+	r := m.Called(ctx, payout)
+	if created, ok := r.Get(0).(func(entities.InterestPayout) entities.InterestPayout); ok {
+		return created(payout), r.Error(1)
+	}
+	return r.Get(0).(entities.InterestPayout), r.Error(1)
+}
+
+func (m *interestRepositoryMock) UpdatePayout(payout entities.InterestPayout, ctx context.Context) error {
+	r := m.Called(ctx, payout)
+	return r.Error(0)
+}
+
+func (m *interestRepositoryMock) LastPaidPayout(accountID string, currency string, ctx context.Context) (entities.InterestPayout, error) {
+	r := m.Called(ctx, accountID, currency)
+	return r.Get(0).(entities.InterestPayout), r.Error(1)
+}
+
+func (m *interestRepositoryMock) ListPayouts(accountIDs []string, limit int64, ctx context.Context) ([]entities.InterestPayout, error) {
+	r := m.Called(ctx, accountIDs, limit)
+	return r.Get(0).([]entities.InterestPayout), r.Error(1)
+}
+
+func (m *interestRepositoryMock) ClaimAccruals(accountID string, currency string, before time.Time, payoutID string, ctx context.Context) (int64, error) {
+	r := m.Called(ctx, accountID, currency, before, payoutID)
+	return r.Get(0).(int64), r.Error(1)
+}
+
+func (m *interestRepositoryMock) ReleaseAccruals(payoutID string, ctx context.Context) error {
+	r := m.Called(ctx, payoutID)
+	return r.Error(0)
+}
+
+func (m *interestRepositoryMock) GetState(ctx context.Context) (repository_interest.InterestState, error) {
+	r := m.Called(ctx)
+	return r.Get(0).(repository_interest.InterestState), r.Error(1)
+}
+
+func (m *interestRepositoryMock) SaveState(state repository_interest.InterestState, ctx context.Context) error {
+	r := m.Called(ctx, state)
+	return r.Error(0)
+}
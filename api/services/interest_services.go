@@ -0,0 +1,367 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_interest "my_wallet/api/respository/interest"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_pocket "my_wallet/api/respository/pocket"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"my_wallet/api/utils/daycount"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxInterestDays bounds the days accrued in a run, so that catching up
+	// after downtime is spread over several ticks.
+	maxInterestDays    = 7
+	maxInterestPayouts = 24
+)
+
+type InterestService interface {
+	GetInterest(ctx context.Context, email string, walletID string) (entities.InterestSummary, error)
+	AccrueDay(ctx context.Context, email string, day time.Time) (entities.InterestRun, error)
+	RunInterest(ctx context.Context, now time.Time) (int, error)
+}
+
+type interestService struct {
+	ctx                context.Context
+	userRepository     repository_user.UserRepository
+	walletRepository   repository_wallet.WalletRepository
+	pocketRepository   repository_pocket.PocketRepository
+	ledgerRepository   repository_ledger.LedgerRepository
+	interestRepository repository_interest.InterestRepository
+	rates              repository_interest.RatePolicy
+	restrictions       Restrictions
+	members            Memberships
+	logger             logrus.FieldLogger
+}
+
+// NewInterestService builds the interest engine. Wallets and pockets earn the
+// rates of the policy for their kind and currency; restrictions, when set,
+// hold the payouts to wallets that cannot receive money.
+func NewInterestService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, pocketRepo repository_pocket.PocketRepository, ledgerRepo repository_ledger.LedgerRepository, interestRepo repository_interest.InterestRepository, rates repository_interest.RatePolicy, restrictions Restrictions, members Memberships, logger logrus.FieldLogger, ctx context.Context) *interestService {
+	return &interestService{
+		ctx:                ctx,
+		userRepository:     userRepo,
+		walletRepository:   walletRepo,
+		pocketRepository:   pocketRepo,
+		ledgerRepository:   ledgerRepo,
+		interestRepository: interestRepo,
+		rates:              rates,
+		restrictions:       restrictions,
+		members:            members,
+		logger:             logger,
+	}
+}
+
+// GetInterest returns, for the wallet and each of its pockets, the rate they
+// earn and the interest they accrued and were not paid yet, and the latest
+// payouts.
+func (s *interestService) GetInterest(ctx context.Context, email string, walletID string) (entities.InterestSummary, error) {
+	_, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, walletID, entities.PermissionView)
+	if err != nil {
+		s.logger.Errorln("Layer: interest_services", "Method: GetInterest", "Error:", err)
+		return entities.InterestSummary{}, err
+	}
+	pockets, err := s.pocketRepository.ListPockets(wallet.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: interest_services", "Method: GetInterest", "Error:", err)
+		return entities.InterestSummary{}, err
+	}
+
+	accounts := []entities.InterestAccount{{AccountKind: entities.InterestAccountWallet, AccountID: wallet.ID, Currency: wallet.Currency}}
+	for currency := range wallet.Balances {
+		if currency != wallet.Currency {
+			accounts = append(accounts, entities.InterestAccount{AccountKind: entities.InterestAccountWallet, AccountID: wallet.ID, Currency: currency})
+		}
+	}
+	accountIDs := []string{wallet.ID}
+	for _, pocket := range pockets {
+		accounts = append(accounts, entities.InterestAccount{AccountKind: entities.InterestAccountPocket, AccountID: pocket.ID, Name: pocket.Name, Currency: pocket.Currency})
+		accountIDs = append(accountIDs, pocket.ID)
+	}
+
+	unpaid, err := s.interestRepository.UnpaidBalances(accountIDs, time.Now().UTC(), ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: interest_services", "Method: GetInterest", "Error:", err)
+		return entities.InterestSummary{}, err
+	}
+	for i := range accounts {
+		account := &accounts[i]
+		if rate, ok := s.rates.Rate(account.AccountKind, account.Currency); ok {
+			account.Rate = &rate
+		}
+		for _, balance := range unpaid {
+			if balance.AccountID == account.AccountID && balance.Currency == account.Currency {
+				account.Accrued += balance.Accrued
+			}
+		}
+		carry, err := s.carry(ctx, account.AccountID, account.Currency)
+		if err != nil {
+			s.logger.Errorln("Layer: interest_services", "Method: GetInterest", "Error:", err)
+			return entities.InterestSummary{}, err
+		}
+		account.Accrued += carry
+		account.AccruedAmount = account.Accrued / daycount.Scale
+	}
+	payouts, err := s.interestRepository.ListPayouts(accountIDs, maxInterestPayouts, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: interest_services", "Method: GetInterest", "Error:", err)
+		return entities.InterestSummary{}, err
+	}
+	return entities.InterestSummary{WalletID: wallet.ID, Accounts: accounts, Payouts: payouts}, nil
+}
+
+// AccrueDay accrues a day that has ended again, for support staff to fill in
+// a day the job missed. Accounts that already accrued the day are skipped,
+// so a day is never accrued twice.
+func (s *interestService) AccrueDay(ctx context.Context, email string, day time.Time) (entities.InterestRun, error) {
+	if _, err := requireStaff(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: interest_services", "Method: AccrueDay", "Error:", err)
+		return entities.InterestRun{}, err
+	}
+	if day.IsZero() || !utcDay(day).Before(utcDay(time.Now())) {
+		s.logger.Errorln("Layer: interest_services", "Method: AccrueDay", "Error:", ErrInvalidInterestDay)
+		return entities.InterestRun{}, ErrInvalidInterestDay
+	}
+	run, err := s.accrue(ctx, utcDay(day))
+	if err != nil {
+		s.logger.Errorln("Layer: interest_services", "Method: AccrueDay", "Error:", err)
+		return entities.InterestRun{}, err
+	}
+	s.logger.Infoln("Layer: interest_services", "Method: AccrueDay", "Day:", run.Day, "Accrued:", run.Accrued, "Skipped:", run.Skipped)
+	return run, nil
+}
+
+// RunInterest accrues the days that ended since the last run, at most
+// maxInterestDays of them, starting with yesterday the first time. Once the
+// last day of a month is accrued it pays the month. It returns how many days
+// it accrued.
+func (s *interestService) RunInterest(ctx context.Context, now time.Time) (int, error) {
+	state, err := s.interestRepository.GetState(ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: interest_services", "Method: RunInterest", "Error:", err)
+		return 0, err
+	}
+	today := utcDay(now)
+	day := today.AddDate(0, 0, -1)
+	if !state.LastAccruedDay.IsZero() {
+		day = state.LastAccruedDay.AddDate(0, 0, 1)
+	}
+	days := 0
+	for ; day.Before(today) && days < maxInterestDays; day = day.AddDate(0, 0, 1) {
+		if _, err := s.accrue(ctx, day); err != nil {
+			s.logger.Errorln("Layer: interest_services", "Method: RunInterest", "Error:", err)
+			return days, err
+		}
+		state.LastAccruedDay = day
+		if err := s.interestRepository.SaveState(state, ctx); err != nil {
+			s.logger.Errorln("Layer: interest_services", "Method: RunInterest", "Error:", err)
+			return days, err
+		}
+		days++
+	}
+
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if state.LastPaidMonth.Before(month) && !state.LastAccruedDay.Before(month.AddDate(0, 0, -1)) {
+		payouts := s.pay(ctx, month)
+		state.LastPaidMonth = month
+		if err := s.interestRepository.SaveState(state, ctx); err != nil {
+			s.logger.Errorln("Layer: interest_services", "Method: RunInterest", "Error:", err)
+			return days, err
+		}
+		s.logger.Infoln("Layer: interest_services", "Method: RunInterest", "Month:", month.AddDate(0, -1, 0), "Payouts:", payouts)
+	}
+	return days, nil
+}
+
+// accrue stores the interest every wallet and pocket with a rate earned on
+// the balance it closed the day with.
+func (s *interestService) accrue(ctx context.Context, day time.Time) (entities.InterestRun, error) {
+	run := entities.InterestRun{Day: day}
+	next := day.AddDate(0, 0, 1)
+	balances, err := s.interestRepository.EndOfDayBalances(next, ctx)
+	if err != nil {
+		return run, err
+	}
+	now := time.Now().UTC()
+	for _, balance := range balances {
+		kind, accountID := entities.InterestAccountWallet, balance.WalletID
+		if balance.PocketID != "" {
+			kind, accountID = entities.InterestAccountPocket, balance.PocketID
+		}
+		rate, ok := s.rates.Rate(kind, balance.Currency)
+		if !ok {
+			continue
+		}
+		amount, err := daycount.Accrual(rate.DayCount, balance.Balance, rate.AnnualRateBps, day, next)
+		if err != nil {
+			return run, err
+		}
+		if amount == 0 {
+			continue
+		}
+		_, err = s.interestRepository.CreateAccrual(entities.InterestAccrual{
+			AccountKind: kind,
+			AccountID:   accountID,
+			Currency:    balance.Currency,
+			Day:         day,
+			Balance:     balance.Balance,
+			Rate:        rate,
+			Amount:      amount,
+			Created_at:  now,
+		}, ctx)
+		if errors.Is(err, repository_interest.ErrAccrualExists) {
+			run.Skipped++
+			continue
+		}
+		if err != nil {
+			return run, err
+		}
+		run.Accrued++
+	}
+	return run, nil
+}
+
+// pay pays every account the interest it accrued before month, one payout
+// per account and currency, and returns how many it paid. Payouts that fail
+// are logged and paid with the next month.
+func (s *interestService) pay(ctx context.Context, month time.Time) int {
+	balances, err := s.interestRepository.UnpaidBalances(nil, month, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: interest_services", "Method: pay", "Error:", err)
+		return 0
+	}
+	paid := 0
+	for _, balance := range balances {
+		err := s.payout(ctx, balance, month)
+		if errors.Is(err, repository_interest.ErrPayoutExists) {
+			continue
+		}
+		if err != nil {
+			s.logger.Errorln("Layer: interest_services", "Method: pay", "Account:", balance.AccountID, "Error:", err)
+			continue
+		}
+		paid++
+	}
+	return paid
+}
+
+// payout claims the payout of the month before month for the account, then
+// its accruals, and posts the whole minor units they add up to, with the
+// fraction the previous payout carried. A payout that cannot be posted gives
+// its accruals back; one interrupted after its claim stays pending for staff
+// to look at.
+func (s *interestService) payout(ctx context.Context, balance entities.InterestBalance, month time.Time) error {
+	payout, err := s.interestRepository.CreatePayout(entities.InterestPayout{
+		AccountKind: balance.AccountKind,
+		AccountID:   balance.AccountID,
+		Currency:    balance.Currency,
+		Month:       month.AddDate(0, -1, 0),
+		Status:      entities.InterestPayoutPending,
+		Created_at:  time.Now().UTC(),
+	}, ctx)
+	if err != nil {
+		return err
+	}
+	accrued, err := s.interestRepository.ClaimAccruals(balance.AccountID, balance.Currency, month, payout.ID, ctx)
+	if err == nil {
+		var carry int64
+		carry, err = s.carry(ctx, balance.AccountID, balance.Currency)
+		payout.Accrued = accrued + carry
+	}
+	if err == nil {
+		payout.Amount = payout.Accrued / daycount.Scale
+		payout.Remainder = payout.Accrued % daycount.Scale
+		err = s.post(ctx, &payout)
+	}
+	if err != nil {
+		if err := s.interestRepository.ReleaseAccruals(payout.ID, ctx); err != nil {
+			s.logger.Errorln("Layer: interest_services", "Method: payout", "Error:", err)
+		}
+		payout.Status = entities.InterestPayoutFailed
+		if err := s.interestRepository.UpdatePayout(payout, ctx); err != nil {
+			s.logger.Errorln("Layer: interest_services", "Method: payout", "Error:", err)
+		}
+		return err
+	}
+	payout.Status = entities.InterestPayoutPaid
+	return s.interestRepository.UpdatePayout(payout, ctx)
+}
+
+// post credits the payout to its account: to the pocket while it is active,
+// otherwise to the wallet, where it shows as an interest transaction.
+func (s *interestService) post(ctx context.Context, payout *entities.InterestPayout) error {
+	walletID := payout.AccountID
+	var pocket entities.Pocket
+	if payout.AccountKind == entities.InterestAccountPocket {
+		var err error
+		pocket, err = s.pocketRepository.GetPocket(payout.AccountID, ctx)
+		if err != nil {
+			return err
+		}
+		walletID = pocket.WalletID
+	}
+	payout.WalletID = walletID
+	if payout.Amount == 0 {
+		return nil
+	}
+	wallet, err := s.walletRepository.GetWallet(walletID, ctx)
+	if err != nil {
+		return err
+	}
+
+	memo := "Interest " + payout.Month.Format("January 2006")
+	entry := entities.LedgerEntry{
+		Type: entities.EntryInterest,
+		Memo: memo,
+		Lines: []entities.LedgerLine{
+			{Account: entities.AccountInterestExpense, Currency: payout.Currency, Amount: -payout.Amount},
+		},
+		Created_at: time.Now().UTC(),
+	}
+	var transactions []entities.Transaction
+	if pocket.ID != "" && pocket.Status == entities.PocketActive {
+		entry.Lines = append(entry.Lines, entities.LedgerLine{Account: entities.PocketAccount(pocket.ID), PocketID: pocket.ID, Currency: payout.Currency, Amount: payout.Amount})
+	} else {
+		if s.restrictions != nil {
+			if err := s.restrictions.CheckCredit(ctx, wallet.ID); err != nil {
+				return err
+			}
+		}
+		entry.Lines = append(entry.Lines, entities.LedgerLine{Account: entities.WalletAccount(wallet.ID), WalletID: wallet.ID, Currency: payout.Currency, Amount: payout.Amount})
+		transactions = []entities.Transaction{{
+			WalletID: wallet.ID,
+			UserID:   wallet.UserID,
+			Type:     entities.TransactionInterest,
+			Status:   entities.StatusCompleted,
+			Amount:   payout.Amount,
+			Currency: payout.Currency,
+			Memo:     memo,
+		}}
+	}
+	entry, _, err = s.ledgerRepository.PostEntry(entry, transactions, ctx)
+	if err != nil {
+		return err
+	}
+	payout.EntryID = entry.ID
+	return nil
+}
+
+// carry returns the fraction of a minor unit the last payout of the account
+// left to the next one.
+func (s *interestService) carry(ctx context.Context, accountID string, currency string) (int64, error) {
+	last, err := s.interestRepository.LastPaidPayout(accountID, currency, ctx)
+	if errors.Is(err, repository_interest.ErrPayoutNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return last.Remainder, nil
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_interest "my_wallet/api/respository/interest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	interestWalletID = "65f1c0a2b3d4e5f6a7b8c9e0"
+	interestPocketID = "65f1c0a2b3d4e5f6a7b8c9e1"
+)
+
+func interestRates(t *testing.T) repository_interest.RatePolicy {
+	rates, err := repository_interest.NewStaticRatePolicy(map[string]map[string]entities.InterestRate{
+		entities.InterestAccountWallet: {"COP": {AnnualRateBps: 365, DayCount: "ACT/365"}},
+		entities.InterestAccountPocket: {"COP": {AnnualRateBps: 360, DayCount: "30/360"}},
+	})
+	assert.NoError(t, err)
+	return rates
+}
+
+func TestRunInterestAccrualService(t *testing.T) {
+	now := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	yesterday := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	testScenarios := []struct {
+		testName        string
+		state           repository_interest.InterestState
+		accrualError    error
+		expectedDays    int
+		expectedAccrual int
+	}{
+		{
+			testName:        "TestRunInterestFirstRun",
+			expectedDays:    1,
+			expectedAccrual: 2,
+		},
+		{
+			testName:        "TestRunInterestCatchesUp",
+			state:           repository_interest.InterestState{LastAccruedDay: yesterday.AddDate(0, 0, -2), LastPaidMonth: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+			expectedDays:    2,
+			expectedAccrual: 4,
+		},
+		{
+			testName:        "TestRunInterestDayAlreadyAccrued",
+			accrualError:    repository_interest.ErrAccrualExists,
+			expectedDays:    1,
+			expectedAccrual: 2,
+		},
+		{
+			testName:     "TestRunInterestUpToDate",
+			state:        repository_interest.InterestState{LastAccruedDay: yesterday, LastPaidMonth: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+			expectedDays: 0,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			repo := &interestRepositoryMock{}
+			repo.On("GetState", mock.Anything).Return(tt.state, nil)
+			repo.On("SaveState", mock.Anything, mock.Anything).Return(nil)
+			repo.On("EndOfDayBalances", mock.Anything, mock.Anything).Return([]entities.AccountBalance{
+				{WalletID: interestWalletID, Currency: "COP", Balance: 100_000_000},
+				{WalletID: interestWalletID, Currency: "USD", Balance: 50_000},
+				{PocketID: interestPocketID, Currency: "COP", Balance: 1_000_000},
+				{WalletID: "w2", Currency: "COP", Balance: -500},
+			}, nil)
+			repo.On("CreateAccrual", mock.Anything, mock.Anything).Return(entities.InterestAccrual{}, tt.accrualError)
+			repo.On("UnpaidBalances", mock.Anything, []string(nil), mock.Anything).Return([]entities.InterestBalance{}, nil)
+			service := NewInterestService(&userServiceMock{}, &walletRepositoryMock{}, &pocketRepositoryMock{}, &ledgerRepositoryMock{}, repo, interestRates(t), nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			days, err := service.RunInterest(context.Background(), now)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedDays, days)
+			repo.AssertNumberOfCalls(t, "CreateAccrual", tt.expectedAccrual)
+			if tt.expectedDays > 0 {
+				repo.AssertCalled(t, "EndOfDayBalances", mock.Anything, yesterday.AddDate(0, 0, 1))
+				repo.AssertCalled(t, "CreateAccrual", mock.Anything, mock.MatchedBy(func(a entities.InterestAccrual) bool {
+					return a.AccountKind == entities.InterestAccountWallet && a.AccountID == interestWalletID && a.Day.Equal(yesterday) && a.Amount == 10_000_000_000
+				}))
+				repo.AssertCalled(t, "CreateAccrual", mock.Anything, mock.MatchedBy(func(a entities.InterestAccrual) bool {
+					return a.AccountKind == entities.InterestAccountPocket && a.AccountID == interestPocketID && a.Rate.DayCount == "30/360" && a.Amount == 100_000_000
+				}))
+				repo.AssertCalled(t, "SaveState", mock.Anything, mock.MatchedBy(func(s repository_interest.InterestState) bool {
+					return s.LastAccruedDay.Equal(yesterday)
+				}))
+			}
+		})
+	}
+}
+
+func TestRunInterestPayoutService(t *testing.T) {
+	now := time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC)
+	month := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	october := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	testScenarios := []struct {
+		testName          string
+		balance           entities.InterestBalance
+		pocket            entities.Pocket
+		payoutError       error
+		postError         error
+		expectedLine      entities.LedgerLine
+		expectedInterest  bool
+		expectedStatus    string
+		expectedAmount    int64
+		expectedRemainder int64
+	}{
+		{
+			testName:          "TestPayoutToWallet",
+			balance:           entities.InterestBalance{AccountKind: entities.InterestAccountWallet, AccountID: interestWalletID, Currency: "COP", Accrued: 2_500_000},
+			expectedLine:      entities.LedgerLine{Account: entities.WalletAccount(interestWalletID), WalletID: interestWalletID, Currency: "COP", Amount: 3},
+			expectedInterest:  true,
+			expectedStatus:    entities.InterestPayoutPaid,
+			expectedAmount:    3,
+			expectedRemainder: 100_000,
+		},
+		{
+			testName:          "TestPayoutToPocket",
+			balance:           entities.InterestBalance{AccountKind: entities.InterestAccountPocket, AccountID: interestPocketID, Currency: "COP", Accrued: 2_500_000},
+			pocket:            entities.Pocket{ID: interestPocketID, WalletID: interestWalletID, Currency: "COP", Status: entities.PocketActive},
+			expectedLine:      entities.LedgerLine{Account: entities.PocketAccount(interestPocketID), PocketID: interestPocketID, Currency: "COP", Amount: 3},
+			expectedStatus:    entities.InterestPayoutPaid,
+			expectedAmount:    3,
+			expectedRemainder: 100_000,
+		},
+		{
+			testName:          "TestPayoutOfClosedPocketToWallet",
+			balance:           entities.InterestBalance{AccountKind: entities.InterestAccountPocket, AccountID: interestPocketID, Currency: "COP", Accrued: 2_500_000},
+			pocket:            entities.Pocket{ID: interestPocketID, WalletID: interestWalletID, Currency: "COP", Status: entities.PocketClosed},
+			expectedLine:      entities.LedgerLine{Account: entities.WalletAccount(interestWalletID), WalletID: interestWalletID, Currency: "COP", Amount: 3},
+			expectedInterest:  true,
+			expectedStatus:    entities.InterestPayoutPaid,
+			expectedAmount:    3,
+			expectedRemainder: 100_000,
+		},
+		{
+			testName:       "TestPayoutAlreadyPaid",
+			balance:        entities.InterestBalance{AccountKind: entities.InterestAccountWallet, AccountID: interestWalletID, Currency: "COP", Accrued: 2_500_000},
+			payoutError:    repository_interest.ErrPayoutExists,
+			expectedStatus: "",
+		},
+		{
+			testName:       "TestPayoutFailsToPost",
+			balance:        entities.InterestBalance{AccountKind: entities.InterestAccountWallet, AccountID: interestWalletID, Currency: "COP", Accrued: 2_500_000},
+			postError:      errors.New("replica set unavailable"),
+			expectedStatus: entities.InterestPayoutFailed,
+			expectedAmount: 3,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			repo := &interestRepositoryMock{}
+			repo.On("GetState", mock.Anything).Return(repository_interest.InterestState{LastAccruedDay: month.AddDate(0, 0, -1), LastPaidMonth: october}, nil)
+			repo.On("SaveState", mock.Anything, mock.Anything).Return(nil)
+			repo.On("UnpaidBalances", mock.Anything, []string(nil), month).Return([]entities.InterestBalance{tt.balance}, nil)
+			repo.On("CreatePayout", mock.Anything, mock.Anything).Return(func(p entities.InterestPayout) entities.InterestPayout {
+				p.ID = "p1"
+				return p
+			}, tt.payoutError)
+			repo.On("ClaimAccruals", mock.Anything, tt.balance.AccountID, "COP", month, "p1").Return(int64(2_500_000), nil)
+			repo.On("LastPaidPayout", mock.Anything, tt.balance.AccountID, "COP").Return(entities.InterestPayout{Remainder: 600_000}, nil)
+			repo.On("ReleaseAccruals", mock.Anything, "p1").Return(nil)
+			repo.On("UpdatePayout", mock.Anything, mock.Anything).Return(nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetWallet", mock.Anything, interestWalletID).Return(entities.Wallet{ID: interestWalletID, UserID: "u1", Currency: "COP"}, nil)
+			pockets := &pocketRepositoryMock{}
+			pockets.On("GetPocket", mock.Anything, interestPocketID).Return(tt.pocket, nil)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{ID: "e1"}, tt.postError)
+			service := NewInterestService(&userServiceMock{}, wallets, pockets, ledger, repo, interestRates(t), nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			days, err := service.RunInterest(context.Background(), now)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 0, days)
+			repo.AssertCalled(t, "SaveState", mock.Anything, mock.MatchedBy(func(s repository_interest.InterestState) bool {
+				return s.LastPaidMonth.Equal(month)
+			}))
+			repo.AssertCalled(t, "CreatePayout", mock.Anything, mock.MatchedBy(func(p entities.InterestPayout) bool {
+				return p.Month.Equal(october) && p.Status == entities.InterestPayoutPending
+			}))
+			if tt.expectedStatus == "" {
+				repo.AssertNotCalled(t, "ClaimAccruals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			repo.AssertCalled(t, "UpdatePayout", mock.Anything, mock.MatchedBy(func(p entities.InterestPayout) bool {
+				return p.Status == tt.expectedStatus && p.Amount == tt.expectedAmount && p.Accrued == 3_100_000
+			}))
+			if tt.expectedStatus == entities.InterestPayoutFailed {
+				repo.AssertCalled(t, "ReleaseAccruals", mock.Anything, "p1")
+				return
+			}
+			repo.AssertNotCalled(t, "ReleaseAccruals", mock.Anything, mock.Anything)
+			repo.AssertCalled(t, "UpdatePayout", mock.Anything, mock.MatchedBy(func(p entities.InterestPayout) bool {
+				return p.Remainder == tt.expectedRemainder && p.EntryID == "e1" && p.WalletID == interestWalletID
+			}))
+			ledger.AssertCalled(t, "PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+				return e.Type == entities.EntryInterest && len(e.Lines) == 2 &&
+					e.Lines[0] == entities.LedgerLine{Account: entities.AccountInterestExpense, Currency: "COP", Amount: -3} &&
+					e.Lines[1] == tt.expectedLine
+			}), mock.MatchedBy(func(transactions []entities.Transaction) bool {
+				if !tt.expectedInterest {
+					return len(transactions) == 0
+				}
+				return len(transactions) == 1 && transactions[0].Type == entities.TransactionInterest && transactions[0].UserID == "u1" && transactions[0].Amount == 3
+			}))
+		})
+	}
+}
+
+func TestAccrueDayService(t *testing.T) {
+	testScenarios := []struct {
+		testName      string
+		user          entities.User
+		day           time.Time
+		expectedError error
+	}{
+		{
+			testName: "TestAccrueDay",
+			user:     entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			day:      time.Now().AddDate(0, 0, -3),
+		},
+		{
+			testName:      "TestAccrueDayWithoutStaff",
+			user:          entities.User{ID: "u1", Email: "support@gmail.com"},
+			day:           time.Now().AddDate(0, 0, -3),
+			expectedError: ErrStaffRequired,
+		},
+		{
+			testName:      "TestAccrueToday",
+			user:          entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			day:           time.Now(),
+			expectedError: ErrInvalidInterestDay,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "support@gmail.com").Return(tt.user, nil)
+			repo := &interestRepositoryMock{}
+			repo.On("EndOfDayBalances", mock.Anything, mock.Anything).Return([]entities.AccountBalance{
+				{WalletID: interestWalletID, Currency: "COP", Balance: 100_000_000},
+				{PocketID: interestPocketID, Currency: "COP", Balance: 1_000_000},
+			}, nil)
+			repo.On("CreateAccrual", mock.Anything, mock.MatchedBy(func(a entities.InterestAccrual) bool {
+				return a.AccountKind == entities.InterestAccountWallet
+			})).Return(entities.InterestAccrual{}, nil)
+			repo.On("CreateAccrual", mock.Anything, mock.Anything).Return(entities.InterestAccrual{}, repository_interest.ErrAccrualExists)
+			service := NewInterestService(users, &walletRepositoryMock{}, &pocketRepositoryMock{}, &ledgerRepositoryMock{}, repo, interestRates(t), nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			run, err := service.AccrueDay(context.Background(), "support@gmail.com", tt.day)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, utcDay(tt.day), run.Day)
+				assert.Equal(t, 1, run.Accrued)
+				assert.Equal(t, 1, run.Skipped)
+			} else {
+				repo.AssertNotCalled(t, "EndOfDayBalances", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
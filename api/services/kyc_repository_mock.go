@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type kycRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *kycRepositoryMock) CreateApplication(application entities.KYCApplication, ctx context.Context) (entities.KYCApplication, error) {
+	r := m.Called(ctx, application)
+	return r.Get(0).(entities.KYCApplication), r.Error(1)
+}
+
+func (m *kycRepositoryMock) GetApplication(id string, ctx context.Context) (entities.KYCApplication, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.KYCApplication), r.Error(1)
+}
+
+func (m *kycRepositoryMock) GetOpenApplication(userID string, ctx context.Context) (entities.KYCApplication, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).(entities.KYCApplication), r.Error(1)
+}
+
+func (m *kycRepositoryMock) ListApplications(status string, limit int64, ctx context.Context) ([]entities.KYCApplication, error) {
+	r := m.Called(ctx, status, limit)
+	return r.Get(0).([]entities.KYCApplication), r.Error(1)
+}
+
+func (m *kycRepositoryMock) PutDocument(id string, document entities.KYCDocument, ctx context.Context) (entities.KYCApplication, error) {
+	r := m.Called(ctx, id, document)
+	return r.Get(0).(entities.KYCApplication), r.Error(1)
+}
+
+func (m *kycRepositoryMock) UpdateStatus(id string, from string, to string, reviewer string, reason string, ctx context.Context) (entities.KYCApplication, error) {
+	r := m.Called(ctx, id, from, to, reviewer, reason)
+	return r.Get(0).(entities.KYCApplication), r.Error(1)
+}
+
+func (m *kycRepositoryMock) CreateEvent(event entities.KYCEvent, ctx context.Context) (entities.KYCEvent, error) {
+	r := m.Called(ctx, event)
+	return r.Get(0).(entities.KYCEvent), r.Error(1)
+}
+
+func (m *kycRepositoryMock) ListEvents(userID string, limit int64, ctx context.Context) ([]entities.KYCEvent, error) {
+	r := m.Called(ctx, userID, limit)
+	return r.Get(0).([]entities.KYCEvent), r.Error(1)
+}
+
+type blobStoreMock struct {
+	mock.Mock
+}
+
+func (m *blobStoreMock) PutBlob(key string, data []byte, ctx context.Context) error {
+	r := m.Called(ctx, key, data)
+	return r.Error(0)
+}
+
+func (m *blobStoreMock) GetBlob(key string, ctx context.Context) ([]byte, error) {
+	r := m.Called(ctx, key)
+	return r.Get(0).([]byte), r.Error(1)
+}
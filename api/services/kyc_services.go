@@ -0,0 +1,418 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"my_wallet/api/entities"
+	repository_blob "my_wallet/api/respository/blob"
+	repository_kyc "my_wallet/api/respository/kyc"
+	repository_user "my_wallet/api/respository/user"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxKYCDocumentSize = 5 << 20
+	maxKYCEvents       = 50
+	maxKYCApplications = 100
+)
+
+var kycContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"application/pdf": true,
+}
+
+// KYCProvider verifies the documents of a submitted application. It can
+// approve or reject the application, or leave it to support staff.
+type KYCProvider interface {
+	Verify(ctx context.Context, user entities.User, application entities.KYCApplication) (entities.KYCDecision, error)
+}
+
+type KYCService interface {
+	GetKYC(ctx context.Context, email string) (entities.KYCStatus, error)
+	CreateApplication(ctx context.Context, email string, level string) (entities.KYCApplication, error)
+	UploadDocument(ctx context.Context, email string, applicationID string, documentType string, contentType string, data []byte) (entities.KYCApplication, error)
+	SubmitApplication(ctx context.Context, email string, applicationID string) (entities.KYCApplication, error)
+	ListApplications(ctx context.Context, email string, status string) ([]entities.KYCApplication, error)
+	ReviewApplication(ctx context.Context, email string, applicationID string, approve bool, reason string) (entities.KYCApplication, error)
+	GetDocument(ctx context.Context, email string, applicationID string, documentType string) (entities.KYCDocument, []byte, error)
+	ListEvents(ctx context.Context, email string, userID string) ([]entities.KYCEvent, error)
+}
+
+type kycService struct {
+	ctx            context.Context
+	userRepository repository_user.UserRepository
+	kycRepository  repository_kyc.KYCRepository
+	blobs          repository_blob.BlobStore
+	provider       KYCProvider
+	logger         logrus.FieldLogger
+}
+
+// NewKYCService creates the KYC service. Without a provider every submitted
+// application waits for support staff.
+func NewKYCService(userRepo repository_user.UserRepository, kycRepo repository_kyc.KYCRepository, blobs repository_blob.BlobStore, provider KYCProvider, logger logrus.FieldLogger, ctx context.Context) *kycService {
+	return &kycService{
+		ctx:            ctx,
+		userRepository: userRepo,
+		kycRepository:  kycRepo,
+		blobs:          blobs,
+		provider:       provider,
+		logger:         logger,
+	}
+}
+
+// GetKYC returns the level of the authenticated user, the application in
+// progress, if any, and the latest entries of their audit trail.
+func (s *kycService) GetKYC(ctx context.Context, email string) (entities.KYCStatus, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: GetKYC", "Error:", err)
+		return entities.KYCStatus{}, err
+	}
+	status := entities.KYCStatus{Level: user.KYC()}
+	application, err := s.kycRepository.GetOpenApplication(user.ID, ctx)
+	switch {
+	case err == nil:
+		status.Application = &application
+	case !errors.Is(err, repository_kyc.ErrKYCApplicationNotFound):
+		s.logger.Errorln("Layer: kyc_services", "Method: GetKYC", "Error:", err)
+		return entities.KYCStatus{}, err
+	}
+	status.Events, err = s.kycRepository.ListEvents(user.ID, maxKYCEvents, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: GetKYC", "Error:", err)
+		return entities.KYCStatus{}, err
+	}
+	return status, nil
+}
+
+// CreateApplication starts a draft application of the authenticated user for
+// a level above the one they have. Users have one open application at most.
+func (s *kycService) CreateApplication(ctx context.Context, email string, level string) (entities.KYCApplication, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: CreateApplication", "Error:", err)
+		return entities.KYCApplication{}, err
+	}
+	if _, ok := entities.KYCRequiredDocuments[level]; !ok || entities.KYCAtLeast(user.KYC(), level) {
+		s.logger.Errorln("Layer: kyc_services", "Method: CreateApplication", "Error:", ErrInvalidKYCLevel)
+		return entities.KYCApplication{}, ErrInvalidKYCLevel
+	}
+
+	now := time.Now().UTC()
+	application, err := s.kycRepository.CreateApplication(entities.KYCApplication{
+		UserID:     user.ID,
+		Level:      level,
+		Status:     entities.KYCDraft,
+		Documents:  map[string]entities.KYCDocument{},
+		Created_at: now,
+		Update_at:  now,
+	}, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: CreateApplication", "Error:", err)
+		if errors.Is(err, repository_kyc.ErrKYCApplicationOpen) {
+			return entities.KYCApplication{}, ErrKYCApplicationOpen
+		}
+		return entities.KYCApplication{}, err
+	}
+	s.audit(ctx, application, entities.KYCActionCreated, user.Email, "", "", level)
+	return application, nil
+}
+
+// UploadDocument stores a document of a draft application of the
+// authenticated user, replacing the previous one of the same type.
+func (s *kycService) UploadDocument(ctx context.Context, email string, applicationID string, documentType string, contentType string, data []byte) (entities.KYCApplication, error) {
+	if !validKYCDocumentType(documentType) || !kycContentTypes[contentType] || len(data) == 0 || len(data) > maxKYCDocumentSize {
+		s.logger.Errorln("Layer: kyc_services", "Method: UploadDocument", "Error:", ErrInvalidKYCDocument)
+		return entities.KYCApplication{}, ErrInvalidKYCDocument
+	}
+	user, application, err := s.ownedApplication(ctx, email, applicationID)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: UploadDocument", "Error:", err)
+		return entities.KYCApplication{}, err
+	}
+	if application.Status != entities.KYCDraft {
+		s.logger.Errorln("Layer: kyc_services", "Method: UploadDocument", "Error:", ErrKYCApplicationState)
+		return entities.KYCApplication{}, ErrKYCApplicationState
+	}
+
+	id, err := newDocumentID()
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: UploadDocument", "Error:", err)
+		return entities.KYCApplication{}, err
+	}
+	// Every upload has its own blob, so a replaced document is never
+	// overwritten under a reader.
+	document := entities.KYCDocument{
+		ID:          id,
+		Type:        documentType,
+		BlobKey:     "kyc/" + user.ID + "/" + application.ID + "/" + documentType + "-" + id,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Uploaded_at: time.Now().UTC(),
+	}
+	if err := s.blobs.PutBlob(document.BlobKey, data, ctx); err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: UploadDocument", "Error:", err)
+		return entities.KYCApplication{}, err
+	}
+	application, err = s.kycRepository.PutDocument(application.ID, document, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: UploadDocument", "Error:", err)
+		return entities.KYCApplication{}, kycError(err)
+	}
+	s.audit(ctx, application, entities.KYCActionUploaded, user.Email, documentType, "", "")
+	return application, nil
+}
+
+// SubmitApplication sends a draft application with every required document
+// to review. The provider, when there is one, decides on it right away;
+// otherwise, or when the provider is unsure or fails, it waits for support
+// staff.
+func (s *kycService) SubmitApplication(ctx context.Context, email string, applicationID string) (entities.KYCApplication, error) {
+	user, application, err := s.ownedApplication(ctx, email, applicationID)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: SubmitApplication", "Error:", err)
+		return entities.KYCApplication{}, err
+	}
+	if application.Status != entities.KYCDraft {
+		s.logger.Errorln("Layer: kyc_services", "Method: SubmitApplication", "Error:", ErrKYCApplicationState)
+		return entities.KYCApplication{}, ErrKYCApplicationState
+	}
+	for _, documentType := range entities.KYCRequiredDocuments[application.Level] {
+		if _, ok := application.Documents[documentType]; !ok {
+			s.logger.Errorln("Layer: kyc_services", "Method: SubmitApplication", "Error:", ErrKYCDocumentsMissing)
+			return entities.KYCApplication{}, ErrKYCDocumentsMissing
+		}
+	}
+
+	application, err = s.kycRepository.UpdateStatus(application.ID, entities.KYCDraft, entities.KYCSubmitted, "", "", ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: SubmitApplication", "Error:", err)
+		return entities.KYCApplication{}, kycError(err)
+	}
+	s.audit(ctx, application, entities.KYCActionSubmitted, user.Email, "", "", "")
+	if s.provider == nil {
+		return application, nil
+	}
+
+	decision, err := s.provider.Verify(ctx, user, application)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: SubmitApplication", "Error:", err)
+		return application, nil
+	}
+	switch decision.Outcome {
+	case entities.KYCDecisionApprove:
+		return s.decide(ctx, user, application, true, entities.KYCActorProvider, decision.Reason)
+	case entities.KYCDecisionReject:
+		return s.decide(ctx, user, application, false, entities.KYCActorProvider, decision.Reason)
+	}
+	return application, nil
+}
+
+// ListApplications returns the applications in a status, submitted ones by
+// default, for support staff to review.
+func (s *kycService) ListApplications(ctx context.Context, email string, status string) ([]entities.KYCApplication, error) {
+	if _, err := requireStaff(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: ListApplications", "Error:", err)
+		return nil, err
+	}
+	if status == "" {
+		status = entities.KYCSubmitted
+	}
+	applications, err := s.kycRepository.ListApplications(status, maxKYCApplications, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: ListApplications", "Error:", err)
+		return nil, err
+	}
+	return applications, nil
+}
+
+// ReviewApplication lets support staff approve or reject a submitted
+// application. Rejections must give a reason, which the user sees.
+func (s *kycService) ReviewApplication(ctx context.Context, email string, applicationID string, approve bool, reason string) (entities.KYCApplication, error) {
+	staff, err := requireStaff(ctx, s.userRepository, email)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: ReviewApplication", "Error:", err)
+		return entities.KYCApplication{}, err
+	}
+	if !approve && reason == "" {
+		s.logger.Errorln("Layer: kyc_services", "Method: ReviewApplication", "Error:", ErrKYCReasonRequired)
+		return entities.KYCApplication{}, ErrKYCReasonRequired
+	}
+	application, err := s.kycRepository.GetApplication(applicationID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: ReviewApplication", "Error:", err)
+		return entities.KYCApplication{}, kycError(err)
+	}
+	if application.UserID == staff.ID {
+		s.logger.Errorln("Layer: kyc_services", "Method: ReviewApplication", "Error:", ErrKYCSelfReview)
+		return entities.KYCApplication{}, ErrKYCSelfReview
+	}
+	if application.Status != entities.KYCSubmitted {
+		s.logger.Errorln("Layer: kyc_services", "Method: ReviewApplication", "Error:", ErrKYCApplicationState)
+		return entities.KYCApplication{}, ErrKYCApplicationState
+	}
+	user, err := s.userRepository.GetUser(application.UserID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: ReviewApplication", "Error:", err)
+		return entities.KYCApplication{}, err
+	}
+	return s.decide(ctx, user, application, approve, staff.Email, reason)
+}
+
+// GetDocument returns a document of an application and its content, for
+// support staff to review.
+func (s *kycService) GetDocument(ctx context.Context, email string, applicationID string, documentType string) (entities.KYCDocument, []byte, error) {
+	if _, err := requireStaff(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: GetDocument", "Error:", err)
+		return entities.KYCDocument{}, nil, err
+	}
+	application, err := s.kycRepository.GetApplication(applicationID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: GetDocument", "Error:", err)
+		return entities.KYCDocument{}, nil, kycError(err)
+	}
+	document, ok := application.Documents[documentType]
+	if !ok {
+		s.logger.Errorln("Layer: kyc_services", "Method: GetDocument", "Error:", ErrKYCDocumentNotFound)
+		return entities.KYCDocument{}, nil, ErrKYCDocumentNotFound
+	}
+	data, err := s.blobs.GetBlob(document.BlobKey, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: GetDocument", "Error:", err)
+		if errors.Is(err, repository_blob.ErrBlobNotFound) {
+			return entities.KYCDocument{}, nil, ErrKYCDocumentNotFound
+		}
+		return entities.KYCDocument{}, nil, err
+	}
+	return document, data, nil
+}
+
+// ListEvents returns the latest entries of the KYC audit trail of a user, for
+// support staff.
+func (s *kycService) ListEvents(ctx context.Context, email string, userID string) ([]entities.KYCEvent, error) {
+	if _, err := requireStaff(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: ListEvents", "Error:", err)
+		return nil, err
+	}
+	events, err := s.kycRepository.ListEvents(userID, maxKYCEvents, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: ListEvents", "Error:", err)
+		return nil, err
+	}
+	return events, nil
+}
+
+// decide approves or rejects a submitted application. Approving raises the
+// user to the level of the application; users already verified at that level
+// or above keep theirs.
+func (s *kycService) decide(ctx context.Context, user entities.User, application entities.KYCApplication, approve bool, actor string, reason string) (entities.KYCApplication, error) {
+	status, action := entities.KYCRejected, entities.KYCActionRejected
+	if approve {
+		status, action = entities.KYCApproved, entities.KYCActionApproved
+	}
+	application, err := s.kycRepository.UpdateStatus(application.ID, entities.KYCSubmitted, status, actor, reason, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: decide", "Error:", err)
+		return entities.KYCApplication{}, kycError(err)
+	}
+	if !approve || entities.KYCAtLeast(user.KYC(), application.Level) {
+		s.audit(ctx, application, action, actor, reason, "", "")
+		return application, nil
+	}
+	if err := s.userRepository.SetKYCLevel(user.ID, application.Level, ctx); err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: decide", "Error:", err)
+		return entities.KYCApplication{}, err
+	}
+	s.audit(ctx, application, action, actor, reason, user.KYC(), application.Level)
+	s.logger.Infoln("Layer: kyc_services", "Method: decide", "User:", user.ID, "Level:", application.Level)
+	return application, nil
+}
+
+// audit appends an entry to the audit trail of the user of the application.
+// Failures are logged; the change they describe has already happened.
+func (s *kycService) audit(ctx context.Context, application entities.KYCApplication, action string, actor string, detail string, from string, to string) {
+	_, err := s.kycRepository.CreateEvent(entities.KYCEvent{
+		UserID:        application.UserID,
+		ApplicationID: application.ID,
+		Action:        action,
+		Actor:         actor,
+		FromLevel:     from,
+		ToLevel:       to,
+		Detail:        detail,
+		Created_at:    time.Now().UTC(),
+	}, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: kyc_services", "Method: audit", "Error:", err)
+	}
+}
+
+// ownedApplication loads the authenticated user and one of their
+// applications. Applications of other users are reported as not found.
+func (s *kycService) ownedApplication(ctx context.Context, email string, applicationID string) (entities.User, entities.KYCApplication, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.User{}, entities.KYCApplication{}, err
+	}
+	application, err := s.kycRepository.GetApplication(applicationID, ctx)
+	if err != nil {
+		return entities.User{}, entities.KYCApplication{}, kycError(err)
+	}
+	if application.UserID != user.ID {
+		return entities.User{}, entities.KYCApplication{}, ErrKYCApplicationNotFound
+	}
+	return user, application, nil
+}
+
+// kycError maps the errors of the KYC repository to the ones of the service.
+func kycError(err error) error {
+	switch {
+	case errors.Is(err, repository_kyc.ErrKYCApplicationNotFound):
+		return ErrKYCApplicationNotFound
+	case errors.Is(err, repository_kyc.ErrKYCStatusChanged):
+		return ErrKYCApplicationState
+	}
+	return err
+}
+
+func validKYCDocumentType(documentType string) bool {
+	for _, required := range entities.KYCRequiredDocuments[entities.KYCFull] {
+		if documentType == required {
+			return true
+		}
+	}
+	return false
+}
+
+func newDocumentID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// simulatedKYCProvider stands in for a verification provider in development
+// and tests. It decides by the last digit of the DNI of the user: 0 rejects,
+// 9 leaves the application to support staff and anything else approves.
+type simulatedKYCProvider struct{}
+
+func NewSimulatedKYCProvider() *simulatedKYCProvider {
+	return &simulatedKYCProvider{}
+}
+
+func (p *simulatedKYCProvider) Verify(ctx context.Context, user entities.User, application entities.KYCApplication) (entities.KYCDecision, error) {
+	dni := strconv.Itoa(user.DNI)
+	switch dni[len(dni)-1] {
+	case '0':
+		return entities.KYCDecision{Outcome: entities.KYCDecisionReject, Reason: "Document could not be verified"}, nil
+	case '9':
+		return entities.KYCDecision{Outcome: entities.KYCDecisionReview}, nil
+	}
+	return entities.KYCDecision{Outcome: entities.KYCDecisionApprove}, nil
+}
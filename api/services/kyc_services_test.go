@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_kyc "my_wallet/api/respository/kyc"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateKYCApplicationService(t *testing.T) {
+	testScenarios := []struct {
+		testName      string
+		user          entities.User
+		level         string
+		createError   error
+		expectedError error
+	}{
+		{
+			testName: "TestCreateKYCApplicationService",
+			user:     entities.User{ID: "u1", Email: "alexer@gmail.com"},
+			level:    entities.KYCBasic,
+		},
+		{
+			testName:      "TestCreateApplicationForCurrentLevel",
+			user:          entities.User{ID: "u1", Email: "alexer@gmail.com", KYCLevel: entities.KYCBasic},
+			level:         entities.KYCBasic,
+			expectedError: ErrInvalidKYCLevel,
+		},
+		{
+			testName:      "TestCreateApplicationForUnknownLevel",
+			user:          entities.User{ID: "u1", Email: "alexer@gmail.com"},
+			level:         "gold",
+			expectedError: ErrInvalidKYCLevel,
+		},
+		{
+			testName:      "TestCreateApplicationWhenOneIsOpen",
+			user:          entities.User{ID: "u1", Email: "alexer@gmail.com"},
+			level:         entities.KYCFull,
+			createError:   repository_kyc.ErrKYCApplicationOpen,
+			expectedError: ErrKYCApplicationOpen,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(tt.user, nil)
+			kyc := &kycRepositoryMock{}
+			kyc.On("CreateApplication", mock.Anything, mock.MatchedBy(func(a entities.KYCApplication) bool {
+				return a.UserID == "u1" && a.Status == entities.KYCDraft
+			})).Return(entities.KYCApplication{ID: "a1", UserID: "u1", Level: tt.level, Status: entities.KYCDraft}, tt.createError)
+			kyc.On("CreateEvent", mock.Anything, mock.Anything).Return(entities.KYCEvent{}, nil)
+			service := NewKYCService(users, kyc, &blobStoreMock{}, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateApplication(context.Background(), "alexer@gmail.com", tt.level)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, "a1", result.ID)
+				kyc.AssertCalled(t, "CreateEvent", mock.Anything, mock.MatchedBy(func(e entities.KYCEvent) bool {
+					return e.Action == entities.KYCActionCreated && e.ToLevel == tt.level
+				}))
+			}
+		})
+	}
+}
+
+func TestUploadKYCDocumentService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	draft := entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCBasic, Status: entities.KYCDraft}
+
+	testScenarios := []struct {
+		testName      string
+		application   entities.KYCApplication
+		documentType  string
+		contentType   string
+		data          []byte
+		expectedError error
+	}{
+		{
+			testName:     "TestUploadKYCDocumentService",
+			application:  draft,
+			documentType: entities.DocumentIDFront,
+			contentType:  "image/png",
+			data:         []byte("png"),
+		},
+		{
+			testName:      "TestUploadUnknownDocumentType",
+			application:   draft,
+			documentType:  "passport",
+			contentType:   "image/png",
+			data:          []byte("png"),
+			expectedError: ErrInvalidKYCDocument,
+		},
+		{
+			testName:      "TestUploadUnsupportedContentType",
+			application:   draft,
+			documentType:  entities.DocumentIDFront,
+			contentType:   "text/html",
+			data:          []byte("<html>"),
+			expectedError: ErrInvalidKYCDocument,
+		},
+		{
+			testName:      "TestUploadToSubmittedApplication",
+			application:   entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCBasic, Status: entities.KYCSubmitted},
+			documentType:  entities.DocumentIDFront,
+			contentType:   "image/png",
+			data:          []byte("png"),
+			expectedError: ErrKYCApplicationState,
+		},
+		{
+			testName:      "TestUploadToApplicationOfOtherUser",
+			application:   entities.KYCApplication{ID: "a1", UserID: "u2", Level: entities.KYCBasic, Status: entities.KYCDraft},
+			documentType:  entities.DocumentIDFront,
+			contentType:   "image/png",
+			data:          []byte("png"),
+			expectedError: ErrKYCApplicationNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			kyc := &kycRepositoryMock{}
+			kyc.On("GetApplication", mock.Anything, "a1").Return(tt.application, nil)
+			kyc.On("PutDocument", mock.Anything, "a1", mock.Anything).Return(tt.application, nil)
+			kyc.On("CreateEvent", mock.Anything, mock.Anything).Return(entities.KYCEvent{}, nil)
+			blobs := &blobStoreMock{}
+			blobs.On("PutBlob", mock.Anything, mock.MatchedBy(func(key string) bool {
+				return strings.HasPrefix(key, "kyc/u1/a1/"+tt.documentType+"-")
+			}), tt.data).Return(nil)
+			service := NewKYCService(users, kyc, blobs, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			_, err := service.UploadDocument(context.Background(), "alexer@gmail.com", "a1", tt.documentType, tt.contentType, tt.data)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				blobs.AssertExpectations(t)
+				kyc.AssertCalled(t, "PutDocument", mock.Anything, "a1", mock.Anything)
+			} else {
+				blobs.AssertNotCalled(t, "PutBlob", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestSubmitKYCApplicationService(t *testing.T) {
+	documents := map[string]entities.KYCDocument{
+		entities.DocumentIDFront: {Type: entities.DocumentIDFront},
+		entities.DocumentIDBack:  {Type: entities.DocumentIDBack},
+	}
+
+	testScenarios := []struct {
+		testName       string
+		dni            int
+		documents      map[string]entities.KYCDocument
+		configureMock  func(*userServiceMock, *kycRepositoryMock)
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			testName:  "TestSubmitApprovedByProvider",
+			dni:       1234567891,
+			documents: documents,
+			configureMock: func(u *userServiceMock, k *kycRepositoryMock) {
+				k.On("UpdateStatus", mock.Anything, "a1", entities.KYCDraft, entities.KYCSubmitted, "", "").Return(entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCBasic, Status: entities.KYCSubmitted}, nil)
+				k.On("UpdateStatus", mock.Anything, "a1", entities.KYCSubmitted, entities.KYCApproved, entities.KYCActorProvider, "").Return(entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCBasic, Status: entities.KYCApproved}, nil)
+				u.On("SetKYCLevel", mock.Anything, "u1", entities.KYCBasic).Return(nil).Once()
+			},
+			expectedStatus: entities.KYCApproved,
+		},
+		{
+			testName:  "TestSubmitRejectedByProvider",
+			dni:       1234567890,
+			documents: documents,
+			configureMock: func(u *userServiceMock, k *kycRepositoryMock) {
+				k.On("UpdateStatus", mock.Anything, "a1", entities.KYCDraft, entities.KYCSubmitted, "", "").Return(entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCBasic, Status: entities.KYCSubmitted}, nil)
+				k.On("UpdateStatus", mock.Anything, "a1", entities.KYCSubmitted, entities.KYCRejected, entities.KYCActorProvider, "Document could not be verified").Return(entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCBasic, Status: entities.KYCRejected}, nil)
+			},
+			expectedStatus: entities.KYCRejected,
+		},
+		{
+			testName:  "TestSubmitLeftToStaffByProvider",
+			dni:       1234567899,
+			documents: documents,
+			configureMock: func(u *userServiceMock, k *kycRepositoryMock) {
+				k.On("UpdateStatus", mock.Anything, "a1", entities.KYCDraft, entities.KYCSubmitted, "", "").Return(entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCBasic, Status: entities.KYCSubmitted}, nil)
+			},
+			expectedStatus: entities.KYCSubmitted,
+		},
+		{
+			testName:      "TestSubmitWithMissingDocuments",
+			dni:           1234567891,
+			documents:     map[string]entities.KYCDocument{entities.DocumentIDFront: {Type: entities.DocumentIDFront}},
+			expectedError: ErrKYCDocumentsMissing,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(entities.User{ID: "u1", Email: "alexer@gmail.com", DNI: tt.dni}, nil)
+			kyc := &kycRepositoryMock{}
+			kyc.On("GetApplication", mock.Anything, "a1").Return(entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCBasic, Status: entities.KYCDraft, Documents: tt.documents}, nil)
+			kyc.On("CreateEvent", mock.Anything, mock.Anything).Return(entities.KYCEvent{}, nil)
+			if tt.configureMock != nil {
+				tt.configureMock(users, kyc)
+			}
+			service := NewKYCService(users, kyc, &blobStoreMock{}, NewSimulatedKYCProvider(), logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.SubmitApplication(context.Background(), "alexer@gmail.com", "a1")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			users.AssertExpectations(t)
+			if tt.expectedError == nil {
+				kyc.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestReviewKYCApplicationService(t *testing.T) {
+	staff := entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport}
+	submitted := entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCFull, Status: entities.KYCSubmitted}
+
+	testScenarios := []struct {
+		testName       string
+		reviewer       entities.User
+		application    entities.KYCApplication
+		approve        bool
+		reason         string
+		configureMock  func(*userServiceMock, *kycRepositoryMock)
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			testName:    "TestApproveKYCApplication",
+			reviewer:    staff,
+			application: submitted,
+			approve:     true,
+			configureMock: func(u *userServiceMock, k *kycRepositoryMock) {
+				k.On("UpdateStatus", mock.Anything, "a1", entities.KYCSubmitted, entities.KYCApproved, "support@gmail.com", "").Return(entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCFull, Status: entities.KYCApproved}, nil)
+				u.On("SetKYCLevel", mock.Anything, "u1", entities.KYCFull).Return(nil).Once()
+				k.On("CreateEvent", mock.Anything, mock.MatchedBy(func(e entities.KYCEvent) bool {
+					return e.Action == entities.KYCActionApproved && e.FromLevel == entities.KYCBasic && e.ToLevel == entities.KYCFull
+				})).Return(entities.KYCEvent{}, nil).Once()
+			},
+			expectedStatus: entities.KYCApproved,
+		},
+		{
+			testName:      "TestRejectWithoutReason",
+			reviewer:      staff,
+			application:   submitted,
+			expectedError: ErrKYCReasonRequired,
+		},
+		{
+			testName:      "TestReviewByCustomer",
+			reviewer:      entities.User{ID: "s1", Email: "support@gmail.com"},
+			application:   submitted,
+			approve:       true,
+			expectedError: ErrStaffRequired,
+		},
+		{
+			testName:      "TestReviewOwnApplication",
+			reviewer:      staff,
+			application:   entities.KYCApplication{ID: "a1", UserID: "s1", Level: entities.KYCFull, Status: entities.KYCSubmitted},
+			approve:       true,
+			expectedError: ErrKYCSelfReview,
+		},
+		{
+			testName:      "TestReviewDraftApplication",
+			reviewer:      staff,
+			application:   entities.KYCApplication{ID: "a1", UserID: "u1", Level: entities.KYCFull, Status: entities.KYCDraft},
+			reason:        "Blurry document",
+			expectedError: ErrKYCApplicationState,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "support@gmail.com").Return(tt.reviewer, nil)
+			users.On("GetUser", mock.Anything, "u1").Return(entities.User{ID: "u1", KYCLevel: entities.KYCBasic}, nil)
+			kyc := &kycRepositoryMock{}
+			kyc.On("GetApplication", mock.Anything, "a1").Return(tt.application, nil)
+			if tt.configureMock != nil {
+				tt.configureMock(users, kyc)
+			}
+			service := NewKYCService(users, kyc, &blobStoreMock{}, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.ReviewApplication(context.Background(), "support@gmail.com", "a1", tt.approve, tt.reason)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			if tt.expectedError == nil {
+				users.AssertExpectations(t)
+				kyc.AssertExpectations(t)
+			}
+		})
+	}
+}
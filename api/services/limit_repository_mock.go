@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type limitRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *limitRepositoryMock) Reserve(key string, amount int64, max int64, expiresAt time.Time, ctx context.Context) (int64, error) {
+	r := m.Called(ctx, key, amount, max, expiresAt)
+	return r.Get(0).(int64), r.Error(1)
+}
+
+func (m *limitRepositoryMock) Release(key string, amount int64, ctx context.Context) error {
+	r := m.Called(ctx, key, amount)
+	return r.Error(0)
+}
+
+func (m *limitRepositoryMock) Usage(keys []string, ctx context.Context) (map[string]int64, error) {
+	r := m.Called(ctx, keys)
+	return r.Get(0).(map[string]int64), r.Error(1)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my_wallet/api/entities"
+	repository_limit "my_wallet/api/respository/limit"
+	repository_user "my_wallet/api/respository/user"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LimitExceededError tells which limit a debit would go over, how much of it
+// is already used and when its window starts over. The single transfer limit
+// has no window, so it has no reset time.
+type LimitExceededError struct {
+	Kind     string     `json:"kind"`
+	Currency string     `json:"currency"`
+	Limit    int64      `json:"limit"`
+	Used     int64      `json:"used"`
+	Reset_at *time.Time `json:"reset_at,omitempty"`
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s: %s limit of %d %s", ErrLimitExceeded, e.Kind, e.Limit, e.Currency)
+}
+
+// Is lets errors.Is match the details with ErrLimitExceeded.
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// Limiter takes debits out of the limits of a user before they are posted.
+// Every operation reserves the amount it moves; fees never count against the
+// limits.
+type Limiter interface {
+	Reserve(ctx context.Context, user entities.User, currency string, amount int64) (entities.LimitReservation, error)
+	Release(ctx context.Context, reservation entities.LimitReservation)
+}
+
+type LimitService interface {
+	Limiter
+	GetLimits(ctx context.Context, email string, currency string) (entities.LimitStatus, error)
+}
+
+type limitService struct {
+	ctx             context.Context
+	userRepository  repository_user.UserRepository
+	limitRepository repository_limit.LimitRepository
+	policy          repository_limit.LimitPolicy
+	logger          logrus.FieldLogger
+}
+
+func NewLimitService(userRepo repository_user.UserRepository, limitRepo repository_limit.LimitRepository, policy repository_limit.LimitPolicy, logger logrus.FieldLogger, ctx context.Context) *limitService {
+	return &limitService{
+		ctx:             ctx,
+		userRepository:  userRepo,
+		limitRepository: limitRepo,
+		policy:          policy,
+		logger:          logger,
+	}
+}
+
+// limitWindow is the usage window of a limit at a given time.
+type limitWindow struct {
+	kind    string
+	key     string
+	max     int64
+	resetAt time.Time
+}
+
+// limitWindows returns the windows of the limits that apply at now, in the
+// order they are reserved. Limits set to zero have no window.
+func limitWindows(userID string, currency string, limits entities.Limits, now time.Time) []limitWindow {
+	now = now.UTC()
+	hour := now.Truncate(time.Hour)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	prefix := userID + ":" + currency + ":"
+
+	var windows []limitWindow
+	if limits.PerHour > 0 {
+		windows = append(windows, limitWindow{entities.LimitHourly, prefix + "hourly:" + hour.Format("2006-01-02T15"), limits.PerHour, hour.Add(time.Hour)})
+	}
+	if limits.Daily > 0 {
+		windows = append(windows, limitWindow{entities.LimitDaily, prefix + "daily:" + day.Format("2006-01-02"), limits.Daily, day.AddDate(0, 0, 1)})
+	}
+	if limits.Monthly > 0 {
+		windows = append(windows, limitWindow{entities.LimitMonthly, prefix + "monthly:" + month.Format("2006-01"), limits.Monthly, month.AddDate(0, 1, 0)})
+	}
+	return windows
+}
+
+// windowAmount is what a debit of amount takes from the window: the hourly
+// limit counts transactions, the others count money.
+func windowAmount(window limitWindow, amount int64) int64 {
+	if window.kind == entities.LimitHourly {
+		return 1
+	}
+	return amount
+}
+
+// Reserve takes amount out of every limit of the user in the currency, or out
+// of none of them. Each window is checked and taken in a single update, so
+// concurrent debits on any replica cannot go over a limit together; when a
+// window is full, what was taken from the previous ones is given back. The
+// caller releases the reservation if the debit is not posted after all.
+func (s *limitService) Reserve(ctx context.Context, user entities.User, currency string, amount int64) (entities.LimitReservation, error) {
+	limits, ok := s.policy.Limits(user.KYC(), currency)
+	if !ok {
+		return entities.LimitReservation{}, nil
+	}
+	if limits.MaxSingle > 0 && amount > limits.MaxSingle {
+		err := &LimitExceededError{Kind: entities.LimitSingle, Currency: currency, Limit: limits.MaxSingle}
+		s.logger.Errorln("Layer: limit_services", "Method: Reserve", "Error:", err)
+		return entities.LimitReservation{}, err
+	}
+
+	var reservation entities.LimitReservation
+	for _, window := range limitWindows(user.ID, currency, limits, time.Now()) {
+		taken := windowAmount(window, amount)
+		if taken > window.max {
+			s.Release(ctx, reservation)
+			err := &LimitExceededError{Kind: window.kind, Currency: currency, Limit: window.max, Reset_at: &window.resetAt}
+			s.logger.Errorln("Layer: limit_services", "Method: Reserve", "Error:", err)
+			return entities.LimitReservation{}, err
+		}
+		// Usage is kept a while after the window is over so a late release
+		// does not bring it back from nothing.
+		used, err := s.limitRepository.Reserve(window.key, taken, window.max, window.resetAt.Add(time.Hour), ctx)
+		if err != nil {
+			s.Release(ctx, reservation)
+			if errors.Is(err, repository_limit.ErrLimitReached) {
+				err = &LimitExceededError{Kind: window.kind, Currency: currency, Limit: window.max, Used: used, Reset_at: &window.resetAt}
+			}
+			s.logger.Errorln("Layer: limit_services", "Method: Reserve", "Error:", err)
+			return entities.LimitReservation{}, err
+		}
+		reservation.Parts = append(reservation.Parts, entities.LimitReservationPart{Key: window.key, Kind: window.kind, Amount: taken})
+	}
+	return reservation, nil
+}
+
+// Release gives back a reservation whose debit was not posted. Failures are
+// only logged: they leave the user with less room until the window is over,
+// never with more.
+func (s *limitService) Release(ctx context.Context, reservation entities.LimitReservation) {
+	for _, part := range reservation.Parts {
+		if err := s.limitRepository.Release(part.Key, part.Amount, ctx); err != nil {
+			s.logger.Errorln("Layer: limit_services", "Method: Release", "Error:", err)
+		}
+	}
+}
+
+// GetLimits returns the limits of the authenticated user in a currency,
+// according to their KYC level, and how much of them is used.
+func (s *limitService) GetLimits(ctx context.Context, email string, currency string) (entities.LimitStatus, error) {
+	if currency == "" {
+		currency = entities.DefaultCurrency
+	}
+	if !entities.ValidCurrency(currency) {
+		s.logger.Errorln("Layer: limit_services", "Method: GetLimits", "Error:", ErrInvalidCurrency)
+		return entities.LimitStatus{}, ErrInvalidCurrency
+	}
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: limit_services", "Method: GetLimits", "Error:", err)
+		return entities.LimitStatus{}, err
+	}
+
+	status := entities.LimitStatus{KYCLevel: user.KYC(), Currency: currency, Usage: []entities.LimitUsage{}}
+	limits, ok := s.policy.Limits(user.KYC(), currency)
+	if !ok {
+		return status, nil
+	}
+	status.Limits = limits
+	windows := limitWindows(user.ID, currency, limits, time.Now())
+	keys := make([]string, len(windows))
+	for i, window := range windows {
+		keys[i] = window.key
+	}
+	used, err := s.limitRepository.Usage(keys, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: limit_services", "Method: GetLimits", "Error:", err)
+		return entities.LimitStatus{}, err
+	}
+	for _, window := range windows {
+		status.Usage = append(status.Usage, entities.LimitUsage{
+			Kind:     window.kind,
+			Currency: currency,
+			Limit:    window.max,
+			Used:     used[window.key],
+			Reset_at: window.resetAt,
+		})
+	}
+	return status, nil
+}
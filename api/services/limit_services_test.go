@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_limit "my_wallet/api/respository/limit"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReserveLimitService(t *testing.T) {
+	policy := repository_limit.NewStaticLimitPolicy(map[string]map[string]entities.Limits{
+		entities.KYCNone:  {"COP": {MaxSingle: 5000, Daily: 10000, Monthly: 30000, PerHour: 3}},
+		entities.KYCBasic: {"COP": {MaxSingle: 50000}},
+	})
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	key := func(kind string) interface{} {
+		return mock.MatchedBy(func(k string) bool { return strings.HasPrefix(k, "u1:COP:"+kind+":") })
+	}
+
+	testScenarios := []struct {
+		testName       string
+		user           entities.User
+		currency       string
+		amount         int64
+		configureMock  func(*limitRepositoryMock)
+		expectedOutput []int64
+		expectedKind   string
+		expectedUsed   int64
+		expectedError  error
+	}{
+		{
+			testName: "TestReserveEveryWindow",
+			user:     user,
+			currency: "COP",
+			amount:   2500,
+			configureMock: func(m *limitRepositoryMock) {
+				m.On("Reserve", mock.Anything, key("hourly"), int64(1), int64(3), mock.Anything).Return(int64(1), nil)
+				m.On("Reserve", mock.Anything, key("daily"), int64(2500), int64(10000), mock.Anything).Return(int64(2500), nil)
+				m.On("Reserve", mock.Anything, key("monthly"), int64(2500), int64(30000), mock.Anything).Return(int64(2500), nil)
+			},
+			expectedOutput: []int64{1, 2500, 2500},
+		},
+		{
+			testName:       "TestReserveWithoutLimitsInCurrency",
+			user:           user,
+			currency:       "USD",
+			amount:         2500,
+			expectedOutput: nil,
+		},
+		{
+			testName:       "TestReserveUsesKYCLevel",
+			user:           entities.User{ID: "u1", KYCLevel: entities.KYCBasic},
+			currency:       "COP",
+			amount:         20000,
+			expectedOutput: nil,
+		},
+		{
+			testName:      "TestReserveOverSingleLimit",
+			user:          user,
+			currency:      "COP",
+			amount:        6000,
+			expectedKind:  entities.LimitSingle,
+			expectedError: ErrLimitExceeded,
+		},
+		{
+			testName: "TestReserveGivesBackWhenWindowIsFull",
+			user:     user,
+			currency: "COP",
+			amount:   2500,
+			configureMock: func(m *limitRepositoryMock) {
+				m.On("Reserve", mock.Anything, key("hourly"), int64(1), int64(3), mock.Anything).Return(int64(2), nil)
+				m.On("Reserve", mock.Anything, key("daily"), int64(2500), int64(10000), mock.Anything).Return(int64(9000), repository_limit.ErrLimitReached)
+				m.On("Release", mock.Anything, key("hourly"), int64(1)).Return(nil).Once()
+			},
+			expectedKind:  entities.LimitDaily,
+			expectedUsed:  9000,
+			expectedError: ErrLimitExceeded,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			limits := &limitRepositoryMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(limits)
+			}
+			service := NewLimitService(&userServiceMock{}, limits, policy, logrus.StandardLogger(), context.Background())
+
+			// Act
+			reservation, err := service.Reserve(context.Background(), tt.user, tt.currency, tt.amount)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError != nil {
+				var limitErr *LimitExceededError
+				assert.True(t, errors.As(err, &limitErr))
+				assert.Equal(t, tt.expectedKind, limitErr.Kind)
+				assert.Equal(t, tt.expectedUsed, limitErr.Used)
+			}
+			var amounts []int64
+			for _, part := range reservation.Parts {
+				amounts = append(amounts, part.Amount)
+			}
+			assert.Equal(t, tt.expectedOutput, amounts)
+			limits.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLimitWindows(t *testing.T) {
+	now := time.Date(2026, 10, 31, 23, 30, 0, 0, time.UTC)
+	windows := limitWindows("u1", "COP", entities.Limits{Daily: 100, Monthly: 1000, PerHour: 5}, now)
+
+	assert.Equal(t, 3, len(windows))
+	assert.Equal(t, "u1:COP:hourly:2026-10-31T23", windows[0].key)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), windows[0].resetAt)
+	assert.Equal(t, "u1:COP:daily:2026-10-31", windows[1].key)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), windows[1].resetAt)
+	assert.Equal(t, "u1:COP:monthly:2026-10", windows[2].key)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), windows[2].resetAt)
+}
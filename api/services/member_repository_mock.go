@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type memberRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *memberRepositoryMock) InviteMember(member entities.WalletMember, ctx context.Context) (entities.WalletMember, error) {
+	r := m.Called(ctx, member)
+	if invited, ok := r.Get(0).(func(entities.WalletMember) entities.WalletMember); ok {
+		return invited(member), r.Error(1)
+	}
+	return r.Get(0).(entities.WalletMember), r.Error(1)
+}
+
+func (m *memberRepositoryMock) GetMember(id string, ctx context.Context) (entities.WalletMember, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.WalletMember), r.Error(1)
+}
+
+func (m *memberRepositoryMock) GetMembership(walletID string, userID string, ctx context.Context) (entities.WalletMember, error) {
+	r := m.Called(ctx, walletID, userID)
+	return r.Get(0).(entities.WalletMember), r.Error(1)
+}
+
+func (m *memberRepositoryMock) ListMembers(walletID string, ctx context.Context) ([]entities.WalletMember, error) {
+	r := m.Called(ctx, walletID)
+	return r.Get(0).([]entities.WalletMember), r.Error(1)
+}
+
+func (m *memberRepositoryMock) ListMemberships(userID string, ctx context.Context) ([]entities.WalletMember, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).([]entities.WalletMember), r.Error(1)
+}
+
+func (m *memberRepositoryMock) UpdateMember(member entities.WalletMember, from string, ctx context.Context) error {
+	r := m.Called(ctx, member, from)
+	return r.Error(0)
+}
+
+func (m *memberRepositoryMock) ReserveSpending(key string, amount int64, max int64, expiresAt time.Time, ctx context.Context) (int64, error) {
+	r := m.Called(ctx, key, amount, max)
+	return r.Get(0).(int64), r.Error(1)
+}
+
+func (m *memberRepositoryMock) ReleaseSpending(key string, amount int64, ctx context.Context) error {
+	r := m.Called(ctx, key, amount)
+	return r.Error(0)
+}
+
+func (m *memberRepositoryMock) Spending(keys []string, ctx context.Context) (map[string]int64, error) {
+	r := m.Called(ctx, keys)
+	return r.Get(0).(map[string]int64), r.Error(1)
+}
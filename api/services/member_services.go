@@ -0,0 +1,462 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_member "my_wallet/api/respository/member"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Memberships tells what the members of a shared wallet may do. The services
+// that take a wallet ID let its members in according to their role, and the
+// transfers out of a shared wallet take the amount out of the spending limit
+// of the spender who sends them.
+type Memberships interface {
+	// Member returns the active membership of a user in a wallet that
+	// belongs to someone else, failing with ErrWalletForbidden when there is
+	// none.
+	Member(ctx context.Context, walletID string, userID string) (entities.WalletMember, error)
+	// ReserveSpending takes amount out of the monthly spending limit of a
+	// spender in currency. Owners have no spending limit.
+	ReserveSpending(ctx context.Context, member entities.WalletMember, currency string, amount int64) (entities.LimitReservation, error)
+	// ReleaseSpending gives back a reservation whose debit was not posted.
+	ReleaseSpending(ctx context.Context, reservation entities.LimitReservation)
+}
+
+type MemberService interface {
+	Memberships
+	InviteMember(ctx context.Context, email string, walletID string, invitation entities.WalletMember) (entities.WalletMember, error)
+	ListMembers(ctx context.Context, email string, walletID string) ([]entities.WalletMember, error)
+	UpdateMember(ctx context.Context, email string, walletID string, memberID string, update entities.WalletMember) (entities.WalletMember, error)
+	RemoveMember(ctx context.Context, email string, walletID string, memberID string) (entities.WalletMember, error)
+	ListMemberships(ctx context.Context, email string) ([]entities.WalletMember, error)
+	RespondInvitation(ctx context.Context, email string, memberID string, accept bool) (entities.WalletMember, error)
+}
+
+type memberService struct {
+	ctx              context.Context
+	userRepository   repository_user.UserRepository
+	walletRepository repository_wallet.WalletRepository
+	memberRepository repository_member.MemberRepository
+	notifier         Notifier
+	logger           logrus.FieldLogger
+}
+
+func NewMemberService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, memberRepo repository_member.MemberRepository, notifier Notifier, logger logrus.FieldLogger, ctx context.Context) *memberService {
+	return &memberService{
+		ctx:              ctx,
+		userRepository:   userRepo,
+		walletRepository: walletRepo,
+		memberRepository: memberRepo,
+		notifier:         notifier,
+		logger:           logger,
+	}
+}
+
+// InviteMember invites a user, by email, to a wallet the authenticated user
+// manages. The invitation reaches the user as a notification and gives them
+// no access until they accept it. Only spenders have spending limits.
+func (s *memberService) InviteMember(ctx context.Context, email string, walletID string, invitation entities.WalletMember) (entities.WalletMember, error) {
+	if err := validateMember(invitation); err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: InviteMember", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	inviter, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s, email, walletID, entities.PermissionManage)
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: InviteMember", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	if err := requireActive(inviter); err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: InviteMember", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	invitee, err := s.userRepository.GetUserByEmail(strings.TrimSpace(invitation.Email), ctx)
+	switch {
+	case errors.Is(err, repository_user.ErrDisbledUser):
+		err = ErrRecipientDisabled
+	case errors.Is(err, repository_user.ErrUserNotfound):
+		err = ErrRecipientNotFound
+	case err == nil && invitee.ID == wallet.UserID:
+		err = ErrInvalidMember
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: InviteMember", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+
+	now := time.Now().UTC()
+	member, err := s.memberRepository.InviteMember(entities.WalletMember{
+		WalletID:       wallet.ID,
+		UserID:         invitee.ID,
+		Email:          invitee.Email,
+		Role:           invitation.Role,
+		SpendingLimits: spendingLimits(invitation),
+		Status:         entities.MemberInvited,
+		InvitedBy:      inviter.ID,
+		Created_at:     now,
+		Update_at:      now,
+	}, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: InviteMember", "Error:", err)
+		return entities.WalletMember{}, memberError(err)
+	}
+	s.notify(ctx, member.UserID, member, entities.NotificationWalletInvitation, "Wallet invitation",
+		inviter.Name+" invited you to their wallet as "+member.Role)
+	s.logger.Infoln("Layer: member_services", "Method: InviteMember", "Member:", member.ID)
+	return member, nil
+}
+
+// ListMembers returns the members of a wallet, invited ones included, and
+// what the spenders spent this month.
+func (s *memberService) ListMembers(ctx context.Context, email string, walletID string) ([]entities.WalletMember, error) {
+	_, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s, email, walletID, entities.PermissionView)
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: ListMembers", "Error:", err)
+		return nil, err
+	}
+	members, err := s.memberRepository.ListMembers(wallet.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: ListMembers", "Error:", err)
+		return nil, err
+	}
+	now := time.Now()
+	for i := range members {
+		if members[i].Role != entities.MemberSpender || members[i].Status != entities.MemberActive {
+			continue
+		}
+		if members[i].Spending, err = s.spending(ctx, members[i], now); err != nil {
+			s.logger.Errorln("Layer: member_services", "Method: ListMembers", "Error:", err)
+			return nil, err
+		}
+	}
+	return members, nil
+}
+
+// UpdateMember changes the role and spending limits of a member of a wallet
+// the authenticated user manages.
+func (s *memberService) UpdateMember(ctx context.Context, email string, walletID string, memberID string, update entities.WalletMember) (entities.WalletMember, error) {
+	if err := validateMember(update); err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: UpdateMember", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	member, err := s.managedMember(ctx, email, walletID, memberID)
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: UpdateMember", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	member.Role = update.Role
+	member.SpendingLimits = spendingLimits(update)
+	member.Update_at = time.Now().UTC()
+	if err := s.memberRepository.UpdateMember(member, member.Status, ctx); err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: UpdateMember", "Error:", err)
+		return entities.WalletMember{}, memberError(err)
+	}
+	return member, nil
+}
+
+// RemoveMember takes a member out of a wallet, or withdraws their
+// invitation. Members who manage the wallet can remove anyone; any member
+// can leave.
+func (s *memberService) RemoveMember(ctx context.Context, email string, walletID string, memberID string) (entities.WalletMember, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: RemoveMember", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	member, err := s.memberRepository.GetMember(memberID, ctx)
+	if errors.Is(err, repository_member.ErrMemberNotFound) || (err == nil && member.WalletID != walletID) {
+		s.logger.Errorln("Layer: member_services", "Method: RemoveMember", "Error:", ErrMemberNotFound)
+		return entities.WalletMember{}, ErrMemberNotFound
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: RemoveMember", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	if member.UserID != user.ID {
+		if member, err = s.managedMember(ctx, email, walletID, memberID); err != nil {
+			s.logger.Errorln("Layer: member_services", "Method: RemoveMember", "Error:", err)
+			return entities.WalletMember{}, err
+		}
+	}
+	if member.Status != entities.MemberInvited && member.Status != entities.MemberActive {
+		s.logger.Errorln("Layer: member_services", "Method: RemoveMember", "Error:", ErrMemberNotFound)
+		return entities.WalletMember{}, ErrMemberNotFound
+	}
+
+	from := member.Status
+	member.Status = entities.MemberRemoved
+	member.Update_at = time.Now().UTC()
+	if err := s.memberRepository.UpdateMember(member, from, ctx); err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: RemoveMember", "Error:", err)
+		return entities.WalletMember{}, memberError(err)
+	}
+	if member.UserID != user.ID {
+		s.notify(ctx, member.UserID, member, entities.NotificationMemberRemoved, "Removed from wallet",
+			user.Name+" removed you from their wallet")
+	}
+	s.logger.Infoln("Layer: member_services", "Method: RemoveMember", "Member:", member.ID)
+	return member, nil
+}
+
+// ListMemberships returns the wallets the authenticated user is invited to
+// or a member of.
+func (s *memberService) ListMemberships(ctx context.Context, email string) ([]entities.WalletMember, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: ListMemberships", "Error:", err)
+		return nil, err
+	}
+	memberships, err := s.memberRepository.ListMemberships(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: ListMemberships", "Error:", err)
+		return nil, err
+	}
+	return memberships, nil
+}
+
+// RespondInvitation accepts or declines an invitation of the authenticated
+// user. Accepting it tells whoever sent it.
+func (s *memberService) RespondInvitation(ctx context.Context, email string, memberID string, accept bool) (entities.WalletMember, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: RespondInvitation", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	member, err := s.memberRepository.GetMember(memberID, ctx)
+	if errors.Is(err, repository_member.ErrMemberNotFound) || (err == nil && member.UserID != user.ID) {
+		s.logger.Errorln("Layer: member_services", "Method: RespondInvitation", "Error:", ErrMemberNotFound)
+		return entities.WalletMember{}, ErrMemberNotFound
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: RespondInvitation", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	if member.Status != entities.MemberInvited {
+		s.logger.Errorln("Layer: member_services", "Method: RespondInvitation", "Error:", ErrInvitationClosed)
+		return entities.WalletMember{}, ErrInvitationClosed
+	}
+	if accept {
+		if err := requireActive(user); err != nil {
+			s.logger.Errorln("Layer: member_services", "Method: RespondInvitation", "Error:", err)
+			return entities.WalletMember{}, err
+		}
+	}
+
+	now := time.Now().UTC()
+	member.Status = entities.MemberDeclined
+	if accept {
+		member.Status = entities.MemberActive
+		member.Joined_at = &now
+	}
+	member.Update_at = now
+	if err := s.memberRepository.UpdateMember(member, entities.MemberInvited, ctx); err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: RespondInvitation", "Error:", err)
+		return entities.WalletMember{}, memberError(err)
+	}
+	if accept {
+		s.notify(ctx, member.InvitedBy, member, entities.NotificationMemberJoined, "New wallet member",
+			user.Name+" joined your wallet as "+member.Role)
+	}
+	return member, nil
+}
+
+// Member returns the active membership of a user in a wallet that belongs to
+// someone else.
+func (s *memberService) Member(ctx context.Context, walletID string, userID string) (entities.WalletMember, error) {
+	member, err := s.memberRepository.GetMembership(walletID, userID, ctx)
+	if errors.Is(err, repository_member.ErrMemberNotFound) || (err == nil && member.Status != entities.MemberActive) {
+		return entities.WalletMember{}, ErrWalletForbidden
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: Member", "Error:", err)
+		return entities.WalletMember{}, err
+	}
+	return member, nil
+}
+
+// ReserveSpending takes amount out of the spending limit of a spender for
+// the current month. The check and the reservation are a single update, so
+// two spenders, or one on two devices, cannot go over the limit together.
+func (s *memberService) ReserveSpending(ctx context.Context, member entities.WalletMember, currency string, amount int64) (entities.LimitReservation, error) {
+	if member.Role == entities.MemberOwner {
+		return entities.LimitReservation{}, nil
+	}
+	if !member.Allows(entities.PermissionSpend) {
+		s.logger.Errorln("Layer: member_services", "Method: ReserveSpending", "Error:", ErrWalletPermission)
+		return entities.LimitReservation{}, ErrWalletPermission
+	}
+	limit := member.SpendingLimits[currency]
+	if amount > limit {
+		s.logger.Errorln("Layer: member_services", "Method: ReserveSpending", "Error:", ErrSpendingLimitExceeded)
+		return entities.LimitReservation{}, ErrSpendingLimitExceeded
+	}
+	key, resetAt := spendingMonth(member, currency, time.Now())
+	// Spending is kept a while after the month is over so a late release
+	// does not bring it back from nothing.
+	if _, err := s.memberRepository.ReserveSpending(key, amount, limit, resetAt.Add(time.Hour), ctx); err != nil {
+		if errors.Is(err, repository_member.ErrSpendingLimitReached) {
+			err = ErrSpendingLimitExceeded
+		}
+		s.logger.Errorln("Layer: member_services", "Method: ReserveSpending", "Error:", err)
+		return entities.LimitReservation{}, err
+	}
+	return entities.LimitReservation{Parts: []entities.LimitReservationPart{{Key: key, Amount: amount}}}, nil
+}
+
+// ReleaseSpending gives back a reservation whose debit was not posted.
+// Failures are only logged: they leave the spender with less room until the
+// month is over, never with more.
+func (s *memberService) ReleaseSpending(ctx context.Context, reservation entities.LimitReservation) {
+	for _, part := range reservation.Parts {
+		if err := s.memberRepository.ReleaseSpending(part.Key, part.Amount, ctx); err != nil {
+			s.logger.Errorln("Layer: member_services", "Method: ReleaseSpending", "Error:", err)
+		}
+	}
+}
+
+// managedMember loads a member of a wallet the authenticated user manages.
+func (s *memberService) managedMember(ctx context.Context, email string, walletID string, memberID string) (entities.WalletMember, error) {
+	_, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s, email, walletID, entities.PermissionManage)
+	if err != nil {
+		return entities.WalletMember{}, err
+	}
+	member, err := s.memberRepository.GetMember(memberID, ctx)
+	if errors.Is(err, repository_member.ErrMemberNotFound) || (err == nil && member.WalletID != wallet.ID) {
+		return entities.WalletMember{}, ErrMemberNotFound
+	}
+	if err != nil {
+		return entities.WalletMember{}, err
+	}
+	if member.Status != entities.MemberInvited && member.Status != entities.MemberActive {
+		return entities.WalletMember{}, ErrMemberNotFound
+	}
+	return member, nil
+}
+
+// spending returns what a spender spent this month in each currency they
+// have a limit in.
+func (s *memberService) spending(ctx context.Context, member entities.WalletMember, now time.Time) ([]entities.MemberSpending, error) {
+	var keys []string
+	var spending []entities.MemberSpending
+	for currency, limit := range member.SpendingLimits {
+		key, resetAt := spendingMonth(member, currency, now)
+		keys = append(keys, key)
+		spending = append(spending, entities.MemberSpending{Currency: currency, Limit: limit, Reset_at: resetAt})
+	}
+	spent, err := s.memberRepository.Spending(keys, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range spending {
+		spending[i].Spent = spent[keys[i]]
+	}
+	return spending, nil
+}
+
+func (s *memberService) notify(ctx context.Context, userID string, member entities.WalletMember, notificationType string, title string, message string) {
+	if s.notifier == nil || userID == "" {
+		return
+	}
+	err := s.notifier.Notify(ctx, entities.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		Data: map[string]string{
+			"wallet_id": member.WalletID,
+			"member_id": member.ID,
+			"role":      member.Role,
+		},
+	})
+	if err != nil {
+		s.logger.Errorln("Layer: member_services", "Method: notify", "Error:", err)
+	}
+}
+
+// memberWallet loads the authenticated user and a wallet they own or are an
+// active member of, with their membership.
+func memberWallet(ctx context.Context, users repository_user.UserRepository, wallets repository_wallet.WalletRepository, members Memberships, email string, walletID string, permission string) (entities.User, entities.Wallet, entities.WalletMember, error) {
+	user, err := users.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.User{}, entities.Wallet{}, entities.WalletMember{}, err
+	}
+	wallet, member, err := walletMembership(ctx, wallets, members, user, walletID, permission)
+	if err != nil {
+		return entities.User{}, entities.Wallet{}, entities.WalletMember{}, err
+	}
+	return user, wallet, member, nil
+}
+
+// walletMembership loads a wallet and the membership of user in it; the
+// owner of the wallet gets one with the owner role. It fails with
+// ErrWalletForbidden when the user is not a member, and with
+// ErrWalletPermission when their role does not grant permission. Without
+// members, only the owner gets in.
+func walletMembership(ctx context.Context, wallets repository_wallet.WalletRepository, members Memberships, user entities.User, walletID string, permission string) (entities.Wallet, entities.WalletMember, error) {
+	wallet, err := wallets.GetWallet(walletID, ctx)
+	if err != nil {
+		return entities.Wallet{}, entities.WalletMember{}, err
+	}
+	member := entities.WalletMember{WalletID: wallet.ID, UserID: user.ID, Email: user.Email, Role: entities.MemberOwner, Status: entities.MemberActive}
+	if wallet.UserID != user.ID {
+		if members == nil {
+			return entities.Wallet{}, entities.WalletMember{}, ErrWalletForbidden
+		}
+		if member, err = members.Member(ctx, wallet.ID, user.ID); err != nil {
+			return entities.Wallet{}, entities.WalletMember{}, err
+		}
+	}
+	if !member.Allows(permission) {
+		return entities.Wallet{}, entities.WalletMember{}, ErrWalletPermission
+	}
+	return wallet, member, nil
+}
+
+func validateMember(member entities.WalletMember) error {
+	if !entities.MemberRoles[member.Role] {
+		return ErrInvalidMember
+	}
+	for currency, limit := range member.SpendingLimits {
+		if !entities.ValidCurrency(currency) || limit <= 0 {
+			return ErrInvalidMember
+		}
+	}
+	return nil
+}
+
+// spendingLimits returns the spending limits of a member with the role it
+// has: only spenders keep them.
+func spendingLimits(member entities.WalletMember) map[string]int64 {
+	if member.Role != entities.MemberSpender {
+		return nil
+	}
+	return member.SpendingLimits
+}
+
+// spendingMonth returns the key of the spending of a member in currency for
+// the month of now, and when that month is over.
+func spendingMonth(member entities.WalletMember, currency string, now time.Time) (string, time.Time) {
+	now = now.UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return member.ID + ":" + currency + ":" + month.Format("2006-01"), month.AddDate(0, 1, 0)
+}
+
+// memberError maps the errors of the member repository to the ones of the
+// service.
+func memberError(err error) error {
+	switch {
+	case errors.Is(err, repository_member.ErrMemberNotFound):
+		return ErrMemberNotFound
+	case errors.Is(err, repository_member.ErrMemberExists):
+		return ErrMemberExists
+	case errors.Is(err, repository_member.ErrMemberStatusChanged):
+		return ErrInvitationClosed
+	}
+	return err
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_member "my_wallet/api/respository/member"
+	repository_user "my_wallet/api/respository/user"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInviteMemberService(t *testing.T) {
+	testScenarios := []struct {
+		testName      string
+		inviter       entities.User
+		membership    entities.WalletMember
+		invitation    entities.WalletMember
+		invitee       entities.User
+		inviteeError  error
+		inviteError   error
+		expectedError error
+	}{
+		{
+			testName:   "TestInviteSpender",
+			inviter:    entities.User{ID: "u1", Name: "Ana", Email: "owner@gmail.com"},
+			invitation: entities.WalletMember{Email: "kid@gmail.com", Role: entities.MemberSpender, SpendingLimits: map[string]int64{"USD": 5000}},
+			invitee:    entities.User{ID: "u2", Email: "kid@gmail.com"},
+		},
+		{
+			testName:   "TestInviteByManagingMember",
+			inviter:    entities.User{ID: "u3", Name: "Luis", Email: "partner@gmail.com"},
+			membership: entities.WalletMember{ID: "m3", WalletID: "w1", UserID: "u3", Role: entities.MemberOwner, Status: entities.MemberActive},
+			invitation: entities.WalletMember{Email: "kid@gmail.com", Role: entities.MemberViewer},
+			invitee:    entities.User{ID: "u2", Email: "kid@gmail.com"},
+		},
+		{
+			testName:      "TestInviteBySpender",
+			inviter:       entities.User{ID: "u3", Email: "partner@gmail.com"},
+			membership:    entities.WalletMember{ID: "m3", WalletID: "w1", UserID: "u3", Role: entities.MemberSpender, Status: entities.MemberActive},
+			invitation:    entities.WalletMember{Email: "kid@gmail.com", Role: entities.MemberViewer},
+			invitee:       entities.User{ID: "u2", Email: "kid@gmail.com"},
+			expectedError: ErrWalletPermission,
+		},
+		{
+			testName:      "TestInviteByStranger",
+			inviter:       entities.User{ID: "u3", Email: "partner@gmail.com"},
+			invitation:    entities.WalletMember{Email: "kid@gmail.com", Role: entities.MemberViewer},
+			invitee:       entities.User{ID: "u2", Email: "kid@gmail.com"},
+			expectedError: ErrWalletForbidden,
+		},
+		{
+			testName:      "TestInviteUnknownRole",
+			inviter:       entities.User{ID: "u1", Email: "owner@gmail.com"},
+			invitation:    entities.WalletMember{Email: "kid@gmail.com", Role: "admin"},
+			invitee:       entities.User{ID: "u2", Email: "kid@gmail.com"},
+			expectedError: ErrInvalidMember,
+		},
+		{
+			testName:      "TestInviteInvalidLimit",
+			inviter:       entities.User{ID: "u1", Email: "owner@gmail.com"},
+			invitation:    entities.WalletMember{Email: "kid@gmail.com", Role: entities.MemberSpender, SpendingLimits: map[string]int64{"USD": -1}},
+			invitee:       entities.User{ID: "u2", Email: "kid@gmail.com"},
+			expectedError: ErrInvalidMember,
+		},
+		{
+			testName:      "TestInviteWalletOwner",
+			inviter:       entities.User{ID: "u1", Email: "owner@gmail.com"},
+			invitation:    entities.WalletMember{Email: "owner@gmail.com", Role: entities.MemberViewer},
+			invitee:       entities.User{ID: "u1", Email: "owner@gmail.com"},
+			expectedError: ErrInvalidMember,
+		},
+		{
+			testName:      "TestInviteUnknownUser",
+			inviter:       entities.User{ID: "u1", Email: "owner@gmail.com"},
+			invitation:    entities.WalletMember{Email: "kid@gmail.com", Role: entities.MemberViewer},
+			inviteeError:  repository_user.ErrUserNotfound,
+			expectedError: ErrRecipientNotFound,
+		},
+		{
+			testName:      "TestInviteExistingMember",
+			inviter:       entities.User{ID: "u1", Email: "owner@gmail.com"},
+			invitation:    entities.WalletMember{Email: "kid@gmail.com", Role: entities.MemberViewer},
+			invitee:       entities.User{ID: "u2", Email: "kid@gmail.com"},
+			inviteError:   repository_member.ErrMemberExists,
+			expectedError: ErrMemberExists,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, tt.inviter.Email).Return(tt.inviter, nil)
+			users.On("GetUserByEmail", mock.Anything, "kid@gmail.com").Return(tt.invitee, tt.inviteeError)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetWallet", mock.Anything, "w1").Return(entities.Wallet{ID: "w1", UserID: "u1"}, nil)
+			members := &memberRepositoryMock{}
+			if tt.membership.ID != "" {
+				members.On("GetMembership", mock.Anything, "w1", tt.inviter.ID).Return(tt.membership, nil)
+			} else {
+				members.On("GetMembership", mock.Anything, "w1", tt.inviter.ID).Return(entities.WalletMember{}, repository_member.ErrMemberNotFound)
+			}
+			members.On("InviteMember", mock.Anything, mock.Anything).Return(func(m entities.WalletMember) entities.WalletMember {
+				m.ID = "m1"
+				return m
+			}, tt.inviteError)
+			notifier := &notifierMock{}
+			notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+			service := NewMemberService(users, wallets, members, notifier, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.InviteMember(context.Background(), tt.inviter.Email, "w1", tt.invitation)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, "m1", result.ID)
+				assert.Equal(t, "u2", result.UserID)
+				assert.Equal(t, tt.inviter.ID, result.InvitedBy)
+				assert.Equal(t, entities.MemberInvited, result.Status)
+				assert.Equal(t, tt.invitation.SpendingLimits, result.SpendingLimits)
+				notifier.AssertCalled(t, "Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+					return n.UserID == "u2" && n.Type == entities.NotificationWalletInvitation && n.Data["member_id"] == "m1"
+				}))
+			} else {
+				notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRespondInvitationService(t *testing.T) {
+	invited := entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "u2", Role: entities.MemberSpender, Status: entities.MemberInvited, InvitedBy: "u1"}
+
+	testScenarios := []struct {
+		testName       string
+		member         entities.WalletMember
+		accept         bool
+		updateError    error
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			testName:       "TestAcceptInvitation",
+			member:         invited,
+			accept:         true,
+			expectedStatus: entities.MemberActive,
+		},
+		{
+			testName:       "TestDeclineInvitation",
+			member:         invited,
+			expectedStatus: entities.MemberDeclined,
+		},
+		{
+			testName:      "TestAcceptInvitationOfAnotherUser",
+			member:        entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "u3", Status: entities.MemberInvited},
+			accept:        true,
+			expectedError: ErrMemberNotFound,
+		},
+		{
+			testName:      "TestAcceptRemovedInvitation",
+			member:        entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "u2", Status: entities.MemberRemoved},
+			accept:        true,
+			expectedError: ErrInvitationClosed,
+		},
+		{
+			testName:      "TestAcceptInvitationRemovedMeanwhile",
+			member:        invited,
+			accept:        true,
+			updateError:   repository_member.ErrMemberStatusChanged,
+			expectedError: ErrInvitationClosed,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "kid@gmail.com").Return(entities.User{ID: "u2", Name: "Sofi", Email: "kid@gmail.com"}, nil)
+			members := &memberRepositoryMock{}
+			members.On("GetMember", mock.Anything, "m1").Return(tt.member, nil)
+			members.On("UpdateMember", mock.Anything, mock.Anything, entities.MemberInvited).Return(tt.updateError)
+			notifier := &notifierMock{}
+			notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+			service := NewMemberService(users, &walletRepositoryMock{}, members, notifier, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.RespondInvitation(context.Background(), "kid@gmail.com", "m1", tt.accept)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedStatus, result.Status)
+				assert.Equal(t, tt.accept, result.Joined_at != nil)
+			}
+			if tt.expectedError == nil && tt.accept {
+				notifier.AssertCalled(t, "Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+					return n.UserID == "u1" && n.Type == entities.NotificationMemberJoined
+				}))
+			} else {
+				notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestReserveSpendingService(t *testing.T) {
+	spender := entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "u2", Role: entities.MemberSpender, Status: entities.MemberActive, SpendingLimits: map[string]int64{"USD": 5000}}
+
+	testScenarios := []struct {
+		testName      string
+		member        entities.WalletMember
+		currency      string
+		amount        int64
+		reserveError  error
+		expectedParts int
+		expectedError error
+	}{
+		{
+			testName:      "TestReserveWithinLimit",
+			member:        spender,
+			currency:      "USD",
+			amount:        2000,
+			expectedParts: 1,
+		},
+		{
+			testName: "TestReserveByOwner",
+			member:   entities.WalletMember{WalletID: "w1", UserID: "u1", Role: entities.MemberOwner, Status: entities.MemberActive},
+			currency: "USD",
+			amount:   1000000,
+		},
+		{
+			testName:      "TestReserveOverLimit",
+			member:        spender,
+			currency:      "USD",
+			amount:        5001,
+			expectedError: ErrSpendingLimitExceeded,
+		},
+		{
+			testName:      "TestReserveWithoutLimitInCurrency",
+			member:        spender,
+			currency:      "EUR",
+			amount:        100,
+			expectedError: ErrSpendingLimitExceeded,
+		},
+		{
+			testName:      "TestReserveMonthSpent",
+			member:        spender,
+			currency:      "USD",
+			amount:        2000,
+			reserveError:  repository_member.ErrSpendingLimitReached,
+			expectedError: ErrSpendingLimitExceeded,
+		},
+		{
+			testName:      "TestReserveByViewer",
+			member:        entities.WalletMember{ID: "m2", WalletID: "w1", UserID: "u3", Role: entities.MemberViewer, Status: entities.MemberActive},
+			currency:      "USD",
+			amount:        100,
+			expectedError: ErrWalletPermission,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			members := &memberRepositoryMock{}
+			members.On("ReserveSpending", mock.Anything, mock.Anything, tt.amount, mock.Anything).Return(tt.amount, tt.reserveError)
+			service := NewMemberService(&userServiceMock{}, &walletRepositoryMock{}, members, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.ReserveSpending(context.Background(), tt.member, tt.currency, tt.amount)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Len(t, result.Parts, tt.expectedParts)
+			if tt.expectedParts > 0 {
+				members.AssertCalled(t, "ReserveSpending", mock.Anything, mock.MatchedBy(func(key string) bool {
+					return len(key) > len("m1:USD:") && key[:len("m1:USD:")] == "m1:USD:"
+				}), tt.amount, int64(5000))
+			}
+		})
+	}
+}
+
+func TestListTransactionsByMember(t *testing.T) {
+	testScenarios := []struct {
+		testName      string
+		membership    entities.WalletMember
+		membershipErr error
+		expectedError error
+	}{
+		{
+			testName:   "TestListTransactionsByViewer",
+			membership: entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "u2", Role: entities.MemberViewer, Status: entities.MemberActive},
+		},
+		{
+			testName:      "TestListTransactionsByInvitedMember",
+			membership:    entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "u2", Role: entities.MemberViewer, Status: entities.MemberInvited},
+			expectedError: ErrWalletForbidden,
+		},
+		{
+			testName:      "TestListTransactionsByStranger",
+			membershipErr: repository_member.ErrMemberNotFound,
+			expectedError: ErrWalletForbidden,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "kid@gmail.com").Return(entities.User{ID: "u2", Email: "kid@gmail.com"}, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetWallet", mock.Anything, "w1").Return(entities.Wallet{ID: "w1", UserID: "u1"}, nil)
+			members := &memberRepositoryMock{}
+			members.On("GetMembership", mock.Anything, "w1", "u2").Return(tt.membership, tt.membershipErr)
+			transactions := &transactionRepositoryMock{}
+			transactions.On("ListTransactions", mock.Anything, mock.Anything).Return([]entities.Transaction{{ID: "t1", WalletID: "w1"}}, nil)
+			memberService := NewMemberService(users, wallets, members, nil, logrus.StandardLogger(), context.Background())
+			service := NewWalletService(users, wallets, &ledgerRepositoryMock{}, transactions, memberService, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.ListTransactions(context.Background(), "kid@gmail.com", entities.TransactionFilter{WalletID: "w1"}, "")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Len(t, result.Transactions, 1)
+			} else {
+				transactions.AssertNotCalled(t, "ListTransactions", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type merchantRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *merchantRepositoryMock) CreateMerchant(merchant entities.Merchant, ctx context.Context) (entities.Merchant, error) {
+	r := m.Called(ctx, merchant)
+	return r.Get(0).(entities.Merchant), r.Error(1)
+}
+
+func (m *merchantRepositoryMock) GetMerchant(id string, ctx context.Context) (entities.Merchant, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.Merchant), r.Error(1)
+}
+
+func (m *merchantRepositoryMock) GetMerchantByUser(userID string, ctx context.Context) (entities.Merchant, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).(entities.Merchant), r.Error(1)
+}
+
+func (m *merchantRepositoryMock) CreateQRCode(qr entities.MerchantQR, ctx context.Context) (entities.MerchantQR, error) {
+	r := m.Called(ctx, qr)
+	if created, ok := r.Get(0).(func(entities.MerchantQR) entities.MerchantQR); ok {
+		return created(qr), r.Error(1)
+	}
+	return r.Get(0).(entities.MerchantQR), r.Error(1)
+}
+
+func (m *merchantRepositoryMock) GetQRCode(id string, ctx context.Context) (entities.MerchantQR, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.MerchantQR), r.Error(1)
+}
+
+func (m *merchantRepositoryMock) UpdateQRStatus(id string, from string, to string, paymentID string, ctx context.Context) error {
+	r := m.Called(ctx, id, from, to, paymentID)
+	return r.Error(0)
+}
+
+func (m *merchantRepositoryMock) SettlementDays(walletID string, from time.Time, to time.Time, ctx context.Context) ([]entities.SettlementDay, error) {
+	r := m.Called(ctx, walletID, from, to)
+	return r.Get(0).([]entities.SettlementDay), r.Error(1)
+}
@@ -0,0 +1,509 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_merchant "my_wallet/api/respository/merchant"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"my_wallet/api/utils/emvco"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSettlementDays = 30
+	maxSettlementPeriod   = 366 * 24 * time.Hour
+)
+
+type MerchantService interface {
+	CreateMerchant(ctx context.Context, email string, merchant entities.Merchant) (entities.Merchant, error)
+	GetMerchant(ctx context.Context, email string) (entities.Merchant, error)
+	CreateQRCode(ctx context.Context, email string, qr entities.MerchantQR) (entities.MerchantQR, error)
+	PayQRCode(ctx context.Context, email string, payload string, amount int64, memo string) (entities.MerchantPayment, error)
+	GetSettlementReport(ctx context.Context, email string, from time.Time, to time.Time) (entities.SettlementReport, error)
+}
+
+type merchantService struct {
+	ctx                context.Context
+	userRepository     repository_user.UserRepository
+	walletRepository   repository_wallet.WalletRepository
+	ledgerRepository   repository_ledger.LedgerRepository
+	merchantRepository repository_merchant.MerchantRepository
+	feeService         FeeService
+	limiter            Limiter
+	qrTTL              time.Duration
+	restrictions       Restrictions
+	logger             logrus.FieldLogger
+}
+
+// NewMerchantService creates the merchant service. Dynamic QR codes can be
+// paid for qrTTL after they are generated.
+func NewMerchantService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, ledgerRepo repository_ledger.LedgerRepository, merchantRepo repository_merchant.MerchantRepository, feeService FeeService, limiter Limiter, qrTTL time.Duration, restrictions Restrictions, logger logrus.FieldLogger, ctx context.Context) *merchantService {
+	return &merchantService{
+		ctx:                ctx,
+		userRepository:     userRepo,
+		walletRepository:   walletRepo,
+		ledgerRepository:   ledgerRepo,
+		merchantRepository: merchantRepo,
+		feeService:         feeService,
+		limiter:            limiter,
+		qrTTL:              qrTTL,
+		restrictions:       restrictions,
+		logger:             logger,
+	}
+}
+
+// CreateMerchant registers the authenticated user as a merchant. Users need
+// full KYC and get a single merchant account, paid into their wallet.
+func (s *merchantService) CreateMerchant(ctx context.Context, email string, merchant entities.Merchant) (entities.Merchant, error) {
+	merchant.Name = strings.TrimSpace(merchant.Name)
+	merchant.City = strings.TrimSpace(merchant.City)
+	if merchant.Name == "" || utf8.RuneCountInString(merchant.Name) > emvco.MaxMerchantName ||
+		merchant.City == "" || utf8.RuneCountInString(merchant.City) > emvco.MaxMerchantCity ||
+		!emvco.ValidCategoryCode(merchant.CategoryCode) {
+		s.logger.Errorln("Layer: merchant_services", "Method: CreateMerchant", "Error:", ErrInvalidMerchant)
+		return entities.Merchant{}, ErrInvalidMerchant
+	}
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: CreateMerchant", "Error:", err)
+		return entities.Merchant{}, err
+	}
+	if err := requireActive(user); err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: CreateMerchant", "Error:", err)
+		return entities.Merchant{}, err
+	}
+	if err := requireKYC(user, entities.FeatureMerchantAccount); err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: CreateMerchant", "Error:", err)
+		return entities.Merchant{}, err
+	}
+
+	now := time.Now().UTC()
+	merchant.ID = ""
+	merchant.UserID = user.ID
+	merchant.Created_at = now
+	merchant.Update_at = now
+	merchant, err = s.merchantRepository.CreateMerchant(merchant, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: CreateMerchant", "Error:", err)
+		return entities.Merchant{}, merchantError(err)
+	}
+	s.logger.Infoln("Layer: merchant_services", "Method: CreateMerchant", "Merchant:", merchant.ID)
+	return merchant, nil
+}
+
+// GetMerchant returns the merchant account of the authenticated user.
+func (s *merchantService) GetMerchant(ctx context.Context, email string) (entities.Merchant, error) {
+	_, merchant, err := s.ownMerchant(ctx, email)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: GetMerchant", "Error:", err)
+		return entities.Merchant{}, err
+	}
+	return merchant, nil
+}
+
+// CreateQRCode generates a payment QR code of the merchant of the
+// authenticated user, in the default currency of their wallet unless it
+// names one. Codes without an amount are static and printed once; codes
+// with an amount are dynamic, stored, and paid once before they expire.
+func (s *merchantService) CreateQRCode(ctx context.Context, email string, qr entities.MerchantQR) (entities.MerchantQR, error) {
+	if qr.Amount < 0 {
+		s.logger.Errorln("Layer: merchant_services", "Method: CreateQRCode", "Error:", ErrInvalidAmount)
+		return entities.MerchantQR{}, ErrInvalidAmount
+	}
+	if qr.Currency != "" && emvco.Currencies[qr.Currency] == "" {
+		s.logger.Errorln("Layer: merchant_services", "Method: CreateQRCode", "Error:", ErrInvalidCurrency)
+		return entities.MerchantQR{}, ErrInvalidCurrency
+	}
+	if utf8.RuneCountInString(qr.Reference) > emvco.MaxBillNumber {
+		s.logger.Errorln("Layer: merchant_services", "Method: CreateQRCode", "Error:", ErrMemoTooLong)
+		return entities.MerchantQR{}, ErrMemoTooLong
+	}
+	user, merchant, err := s.ownMerchant(ctx, email)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: CreateQRCode", "Error:", err)
+		return entities.MerchantQR{}, err
+	}
+	if qr.Currency == "" {
+		wallet, err := s.walletRepository.GetOrCreateWallet(user.ID, ctx)
+		if err != nil {
+			s.logger.Errorln("Layer: merchant_services", "Method: CreateQRCode", "Error:", err)
+			return entities.MerchantQR{}, err
+		}
+		qr.Currency = wallet.Currency
+		if emvco.Currencies[qr.Currency] == "" {
+			s.logger.Errorln("Layer: merchant_services", "Method: CreateQRCode", "Error:", ErrInvalidCurrency)
+			return entities.MerchantQR{}, ErrInvalidCurrency
+		}
+	}
+
+	now := time.Now().UTC()
+	qr.ID = ""
+	qr.MerchantID = merchant.ID
+	qr.Kind = entities.QRStatic
+	qr.Status = ""
+	qr.PaymentID = ""
+	qr.ExpiresAt = time.Time{}
+	qr.Created_at = now
+	if qr.Amount > 0 {
+		qr.Kind = entities.QRDynamic
+		qr.Status = entities.QRPending
+		qr.ExpiresAt = now.Add(s.qrTTL)
+		qr, err = s.merchantRepository.CreateQRCode(qr, ctx)
+		if err != nil {
+			s.logger.Errorln("Layer: merchant_services", "Method: CreateQRCode", "Error:", err)
+			return entities.MerchantQR{}, err
+		}
+	}
+	qr.Payload, err = emvco.Encode(emvco.Payload{
+		Dynamic:      qr.Kind == entities.QRDynamic,
+		MerchantID:   merchant.ID,
+		CategoryCode: merchant.CategoryCode,
+		Currency:     qr.Currency,
+		Amount:       qr.Amount,
+		CountryCode:  entities.MerchantCountry,
+		MerchantName: merchant.Name,
+		MerchantCity: merchant.City,
+		BillNumber:   qr.Reference,
+		Reference:    qr.ID,
+	})
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: CreateQRCode", "Error:", err)
+		return entities.MerchantQR{}, err
+	}
+	return qr, nil
+}
+
+// PayQRCode pays a merchant QR code from the wallet of the authenticated
+// user. The payload is checked and must name a merchant of My Wallet; the
+// amount is the one of the code, or the one the payer enters for static
+// codes without it. Dynamic codes are taken before the payment is posted and
+// given back if it fails, so they are never paid twice. The merchant fee is
+// charged to the merchant in the same ledger entry, and the payment is taken
+// out of the limits of the payer like a transfer. The payer cannot be
+// restricted from payments, nor the merchant wallet blocked.
+func (s *merchantService) PayQRCode(ctx context.Context, email string, payload string, amount int64, memo string) (entities.MerchantPayment, error) {
+	if amount < 0 {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", ErrInvalidAmount)
+		return entities.MerchantPayment{}, ErrInvalidAmount
+	}
+	if utf8.RuneCountInString(memo) > maxMemoLength {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", ErrMemoTooLong)
+		return entities.MerchantPayment{}, ErrMemoTooLong
+	}
+	code, err := emvco.Decode(strings.TrimSpace(payload))
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+		return entities.MerchantPayment{}, ErrInvalidQRCode
+	}
+
+	payer, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+		return entities.MerchantPayment{}, err
+	}
+	if err := requireActive(payer); err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+		return entities.MerchantPayment{}, err
+	}
+	merchant, err := s.merchantRepository.GetMerchant(code.MerchantID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+		return entities.MerchantPayment{}, merchantError(err)
+	}
+	var qr entities.MerchantQR
+	if code.Dynamic {
+		qr, err = s.dynamicQRCode(ctx, merchant, code)
+		if err != nil {
+			s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+			return entities.MerchantPayment{}, err
+		}
+	}
+	switch {
+	case code.Amount > 0 && amount != 0 && amount != code.Amount:
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", ErrQRCodeAmount)
+		return entities.MerchantPayment{}, ErrQRCodeAmount
+	case code.Amount > 0:
+		amount = code.Amount
+	case amount == 0:
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", ErrInvalidAmount)
+		return entities.MerchantPayment{}, ErrInvalidAmount
+	}
+
+	owner, err := s.userRepository.GetUser(merchant.UserID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+		if errors.Is(err, repository_user.ErrDisbledUser) || errors.Is(err, repository_user.ErrUserNotfound) {
+			return entities.MerchantPayment{}, ErrRecipientDisabled
+		}
+		return entities.MerchantPayment{}, err
+	}
+	if owner.ID == payer.ID {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", ErrSelfTransfer)
+		return entities.MerchantPayment{}, ErrSelfTransfer
+	}
+	if !owner.Active() {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", ErrUserUnderReview)
+		return entities.MerchantPayment{}, ErrRecipientDisabled
+	}
+	payerWallet, err := s.walletRepository.GetOrCreateWallet(payer.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+		return entities.MerchantPayment{}, err
+	}
+	if payerWallet.Balances[code.Currency] < amount {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", ErrInsufficientFunds)
+		return entities.MerchantPayment{}, ErrInsufficientFunds
+	}
+	merchantWallet, err := s.walletRepository.GetOrCreateWallet(owner.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+		return entities.MerchantPayment{}, err
+	}
+	if s.restrictions != nil {
+		if err := s.restrictions.CheckDebit(ctx, payerWallet.ID, entities.CapabilityPayment); err != nil {
+			s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+			return entities.MerchantPayment{}, err
+		}
+		if err := s.restrictions.CheckCredit(ctx, merchantWallet.ID); err != nil {
+			s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+			return entities.MerchantPayment{}, recipientError(err)
+		}
+	}
+	fee, err := s.feeService.ComputeFee(ctx, owner, entities.OperationMerchantPayment, code.Currency, amount)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+		return entities.MerchantPayment{}, err
+	}
+	if fee.Fee >= amount {
+		// The merchant would receive nothing.
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", ErrInvalidAmount)
+		return entities.MerchantPayment{}, ErrInvalidAmount
+	}
+
+	if memo == "" {
+		memo = "Payment to " + merchant.Name
+	}
+	entry := entities.LedgerEntry{
+		Type: entities.EntryMerchantPayment,
+		Memo: memo,
+		Lines: []entities.LedgerLine{
+			{Account: entities.WalletAccount(payerWallet.ID), WalletID: payerWallet.ID, Currency: code.Currency, Amount: -amount},
+			{Account: entities.WalletAccount(merchantWallet.ID), WalletID: merchantWallet.ID, Currency: code.Currency, Amount: amount},
+		},
+		Created_at: time.Now().UTC(),
+	}
+	transactions := []entities.Transaction{
+		{
+			WalletID:             payerWallet.ID,
+			UserID:               payer.ID,
+			Type:                 entities.TransactionPaymentOut,
+			Status:               entities.StatusCompleted,
+			Amount:               amount,
+			Currency:             code.Currency,
+			CounterpartyWalletID: merchantWallet.ID,
+			CounterpartyUserID:   owner.ID,
+			Memo:                 memo,
+		},
+		{
+			WalletID:             merchantWallet.ID,
+			UserID:               owner.ID,
+			Type:                 entities.TransactionPaymentIn,
+			Status:               entities.StatusCompleted,
+			Amount:               amount,
+			Currency:             code.Currency,
+			CounterpartyWalletID: payerWallet.ID,
+			CounterpartyUserID:   payer.ID,
+			Memo:                 memo,
+		},
+	}
+	if fee.Fee > 0 {
+		entry.Lines = append(entry.Lines, feeLines(merchantWallet.ID, code.Currency, fee.Fee)...)
+		transactions = append(transactions, entities.Transaction{
+			WalletID: merchantWallet.ID,
+			UserID:   owner.ID,
+			Type:     entities.TransactionFee,
+			Status:   entities.StatusCompleted,
+			Amount:   fee.Fee,
+			Currency: code.Currency,
+			Memo:     "Merchant fee",
+		})
+	}
+
+	if qr.ID != "" {
+		if err := s.updateQRStatus(ctx, qr.ID, entities.QRPending, entities.QRPaid, ""); err != nil {
+			s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+			return entities.MerchantPayment{}, err
+		}
+	}
+	var reservation entities.LimitReservation
+	if s.limiter != nil {
+		reservation, err = s.limiter.Reserve(ctx, payer, code.Currency, amount)
+		if err != nil {
+			s.giveBack(ctx, qr)
+			s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+			return entities.MerchantPayment{}, err
+		}
+	}
+	entry, _, err = s.ledgerRepository.PostEntry(entry, transactions, ctx)
+	if err != nil {
+		if s.limiter != nil {
+			s.limiter.Release(ctx, reservation)
+		}
+		s.giveBack(ctx, qr)
+		s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+		if errors.Is(err, repository_ledger.ErrInsufficientFunds) {
+			return entities.MerchantPayment{}, ErrInsufficientFunds
+		}
+		return entities.MerchantPayment{}, err
+	}
+	if qr.ID != "" {
+		if err := s.updateQRStatus(ctx, qr.ID, entities.QRPaid, entities.QRPaid, entry.ID); err != nil {
+			s.logger.Errorln("Layer: merchant_services", "Method: PayQRCode", "Error:", err)
+		}
+	}
+
+	payment := entities.MerchantPayment{
+		ID:           entry.ID,
+		MerchantID:   merchant.ID,
+		MerchantName: merchant.Name,
+		QRID:         qr.ID,
+		Reference:    code.BillNumber,
+		PayerUserID:  payer.ID,
+		Amount:       amount,
+		Currency:     code.Currency,
+		Memo:         memo,
+		Status:       entities.StatusCompleted,
+		Created_at:   entry.Created_at,
+	}
+	s.logger.Infoln("Layer: merchant_services", "Method: PayQRCode", "Payment:", payment.ID)
+	return payment, nil
+}
+
+// GetSettlementReport adds up by day the QR payments the merchant of the
+// authenticated user received in [from, to), the last 30 days by default,
+// with the fees charged and what was settled into the wallet.
+func (s *merchantService) GetSettlementReport(ctx context.Context, email string, from time.Time, to time.Time) (entities.SettlementReport, error) {
+	if to.IsZero() {
+		to = time.Now().UTC().Truncate(24 * time.Hour).AddDate(0, 0, 1)
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -defaultSettlementDays)
+	}
+	if !from.Before(to) || to.Sub(from) > maxSettlementPeriod {
+		s.logger.Errorln("Layer: merchant_services", "Method: GetSettlementReport", "Error:", ErrInvalidSettlementPeriod)
+		return entities.SettlementReport{}, ErrInvalidSettlementPeriod
+	}
+	user, merchant, err := s.ownMerchant(ctx, email)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: GetSettlementReport", "Error:", err)
+		return entities.SettlementReport{}, err
+	}
+	wallet, err := s.walletRepository.GetOrCreateWallet(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: GetSettlementReport", "Error:", err)
+		return entities.SettlementReport{}, err
+	}
+	days, err := s.merchantRepository.SettlementDays(wallet.ID, from, to, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: GetSettlementReport", "Error:", err)
+		return entities.SettlementReport{}, err
+	}
+
+	totals := map[string]*entities.SettlementDay{}
+	for _, day := range days {
+		total, ok := totals[day.Currency]
+		if !ok {
+			total = &entities.SettlementDay{Currency: day.Currency}
+			totals[day.Currency] = total
+		}
+		total.Payments += day.Payments
+		total.Gross += day.Gross
+		total.Fees += day.Fees
+		total.Net += day.Net
+	}
+	report := entities.SettlementReport{
+		MerchantID: merchant.ID,
+		From:       from,
+		To:         to,
+		Days:       days,
+		Totals:     []entities.SettlementDay{},
+	}
+	for _, total := range totals {
+		report.Totals = append(report.Totals, *total)
+	}
+	sort.Slice(report.Totals, func(i, j int) bool { return report.Totals[i].Currency < report.Totals[j].Currency })
+	return report, nil
+}
+
+// ownMerchant loads the authenticated user and their merchant account.
+func (s *merchantService) ownMerchant(ctx context.Context, email string) (entities.User, entities.Merchant, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.User{}, entities.Merchant{}, err
+	}
+	merchant, err := s.merchantRepository.GetMerchantByUser(user.ID, ctx)
+	if err != nil {
+		return entities.User{}, entities.Merchant{}, merchantError(err)
+	}
+	return user, merchant, nil
+}
+
+// dynamicQRCode loads the stored QR code a dynamic payload was generated as.
+// The payload must agree with it, so a code edited to pay less is refused.
+func (s *merchantService) dynamicQRCode(ctx context.Context, merchant entities.Merchant, code emvco.Payload) (entities.MerchantQR, error) {
+	qr, err := s.merchantRepository.GetQRCode(code.Reference, ctx)
+	if err != nil {
+		return entities.MerchantQR{}, merchantError(err)
+	}
+	if qr.MerchantID != merchant.ID || qr.Amount != code.Amount || qr.Currency != code.Currency {
+		return entities.MerchantQR{}, ErrInvalidQRCode
+	}
+	if qr.Status != entities.QRPending {
+		return entities.MerchantQR{}, ErrQRCodePaid
+	}
+	if !time.Now().Before(qr.ExpiresAt) {
+		return entities.MerchantQR{}, ErrQRCodeExpired
+	}
+	return qr, nil
+}
+
+func (s *merchantService) updateQRStatus(ctx context.Context, id string, from string, to string, paymentID string) error {
+	err := s.merchantRepository.UpdateQRStatus(id, from, to, paymentID, ctx)
+	if errors.Is(err, repository_merchant.ErrQRStatusChanged) {
+		return ErrQRCodePaid
+	}
+	return err
+}
+
+// giveBack makes a dynamic QR code taken for a payment that failed payable
+// again.
+func (s *merchantService) giveBack(ctx context.Context, qr entities.MerchantQR) {
+	if qr.ID == "" {
+		return
+	}
+	if err := s.updateQRStatus(ctx, qr.ID, entities.QRPaid, entities.QRPending, ""); err != nil {
+		s.logger.Errorln("Layer: merchant_services", "Method: giveBack", "Error:", err)
+	}
+}
+
+// merchantError maps the errors of the merchant repository to the ones of
+// the service.
+func merchantError(err error) error {
+	switch {
+	case errors.Is(err, repository_merchant.ErrMerchantNotFound):
+		return ErrMerchantNotFound
+	case errors.Is(err, repository_merchant.ErrMerchantExists):
+		return ErrMerchantExists
+	case errors.Is(err, repository_merchant.ErrQRCodeNotFound):
+		return ErrQRCodeNotFound
+	}
+	return err
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_merchant "my_wallet/api/respository/merchant"
+	"my_wallet/api/utils/emvco"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const merchantID = "65f1c0a2b3d4e5f6a7b8c9d0"
+const qrCodeID = "65f1c0a2b3d4e5f6a7b8c9d1"
+
+func TestCreateMerchantService(t *testing.T) {
+	testScenarios := []struct {
+		testName      string
+		user          entities.User
+		merchant      entities.Merchant
+		createError   error
+		expectedError error
+	}{
+		{
+			testName: "TestCreateMerchantService",
+			user:     entities.User{ID: "u1", KYCLevel: entities.KYCFull},
+			merchant: entities.Merchant{Name: " Panaderia La Esquina ", City: "Bogota", CategoryCode: "5462"},
+		},
+		{
+			testName:      "TestCreateMerchantNameTooLong",
+			user:          entities.User{ID: "u1", KYCLevel: entities.KYCFull},
+			merchant:      entities.Merchant{Name: "Panaderia y Pasteleria La Esquina", City: "Bogota", CategoryCode: "5462"},
+			expectedError: ErrInvalidMerchant,
+		},
+		{
+			testName:      "TestCreateMerchantBadCategoryCode",
+			user:          entities.User{ID: "u1", KYCLevel: entities.KYCFull},
+			merchant:      entities.Merchant{Name: "Panaderia", City: "Bogota", CategoryCode: "54A2"},
+			expectedError: ErrInvalidMerchant,
+		},
+		{
+			testName:      "TestCreateMerchantWithoutFullKYC",
+			user:          entities.User{ID: "u1", KYCLevel: entities.KYCBasic},
+			merchant:      entities.Merchant{Name: "Panaderia", City: "Bogota", CategoryCode: "5462"},
+			expectedError: ErrKYCLevelRequired,
+		},
+		{
+			testName:      "TestCreateMerchantTwice",
+			user:          entities.User{ID: "u1", KYCLevel: entities.KYCFull},
+			merchant:      entities.Merchant{Name: "Panaderia", City: "Bogota", CategoryCode: "5462"},
+			createError:   repository_merchant.ErrMerchantExists,
+			expectedError: ErrMerchantExists,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "shop@gmail.com").Return(tt.user, nil)
+			merchants := &merchantRepositoryMock{}
+			merchants.On("CreateMerchant", mock.Anything, mock.MatchedBy(func(m entities.Merchant) bool {
+				return m.UserID == "u1" && m.Name == "Panaderia La Esquina"
+			})).Return(entities.Merchant{ID: merchantID, UserID: "u1"}, tt.createError)
+			merchants.On("CreateMerchant", mock.Anything, mock.Anything).Return(entities.Merchant{}, tt.createError)
+			service := NewMerchantService(users, &walletRepositoryMock{}, &ledgerRepositoryMock{}, merchants, nil, nil, time.Minute, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateMerchant(context.Background(), "shop@gmail.com", tt.merchant)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, merchantID, result.ID)
+			}
+		})
+	}
+}
+
+func TestCreateQRCodeService(t *testing.T) {
+	owner := entities.User{ID: "u1", Email: "shop@gmail.com"}
+	merchant := entities.Merchant{ID: merchantID, UserID: "u1", Name: "Panaderia", City: "Bogota", CategoryCode: "5462"}
+
+	testScenarios := []struct {
+		testName      string
+		qr            entities.MerchantQR
+		expectedCode  emvco.Payload
+		expectedError error
+	}{
+		{
+			testName:     "TestCreateStaticQRCode",
+			qr:           entities.MerchantQR{},
+			expectedCode: emvco.Payload{MerchantID: merchantID, CategoryCode: "5462", Currency: "COP", CountryCode: "CO", MerchantName: "Panaderia", MerchantCity: "Bogota"},
+		},
+		{
+			testName:     "TestCreateDynamicQRCode",
+			qr:           entities.MerchantQR{Amount: 1550000, Currency: "USD", Reference: "INV-7"},
+			expectedCode: emvco.Payload{Dynamic: true, MerchantID: merchantID, CategoryCode: "5462", Currency: "USD", Amount: 1550000, CountryCode: "CO", MerchantName: "Panaderia", MerchantCity: "Bogota", BillNumber: "INV-7", Reference: qrCodeID},
+		},
+		{
+			testName:      "TestCreateQRCodeUnsupportedCurrency",
+			qr:            entities.MerchantQR{Amount: 100, Currency: "GBP"},
+			expectedError: ErrInvalidCurrency,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "shop@gmail.com").Return(owner, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetOrCreateWallet", mock.Anything, "u1").Return(entities.Wallet{ID: "w1", Currency: "COP"}, nil)
+			merchants := &merchantRepositoryMock{}
+			merchants.On("GetMerchantByUser", mock.Anything, "u1").Return(merchant, nil)
+			merchants.On("CreateQRCode", mock.Anything, mock.Anything).Return(func(qr entities.MerchantQR) entities.MerchantQR {
+				qr.ID = qrCodeID
+				return qr
+			}, nil)
+			service := NewMerchantService(users, wallets, &ledgerRepositoryMock{}, merchants, nil, nil, time.Minute, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateQRCode(context.Background(), "shop@gmail.com", tt.qr)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				code, err := emvco.Decode(result.Payload)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestPayQRCodeService(t *testing.T) {
+	payer := entities.User{ID: "u2", Email: "payer@gmail.com"}
+	owner := entities.User{ID: "u1", Email: "shop@gmail.com"}
+	merchant := entities.Merchant{ID: merchantID, UserID: "u1", Name: "Panaderia", City: "Bogota", CategoryCode: "5462"}
+	static, _ := emvco.Encode(emvco.Payload{MerchantID: merchantID, CategoryCode: "5462", Currency: "COP", CountryCode: "CO", MerchantName: "Panaderia", MerchantCity: "Bogota"})
+	dynamicCode := emvco.Payload{Dynamic: true, MerchantID: merchantID, CategoryCode: "5462", Currency: "COP", Amount: 5000, CountryCode: "CO", MerchantName: "Panaderia", MerchantCity: "Bogota", Reference: qrCodeID}
+	dynamic, _ := emvco.Encode(dynamicCode)
+	dynamicCode.Amount = 50
+	tampered, _ := emvco.Encode(dynamicCode)
+	pending := entities.MerchantQR{ID: qrCodeID, MerchantID: merchantID, Kind: entities.QRDynamic, Amount: 5000, Currency: "COP", Status: entities.QRPending, ExpiresAt: time.Now().Add(time.Minute)}
+	paid := pending
+	paid.Status = entities.QRPaid
+	expired := pending
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	testScenarios := []struct {
+		testName       string
+		payload        string
+		amount         int64
+		qr             entities.MerchantQR
+		postError      error
+		expectedAmount int64
+		expectedError  error
+	}{
+		{
+			testName:       "TestPayStaticQRCode",
+			payload:        static,
+			amount:         2500,
+			expectedAmount: 2500,
+		},
+		{
+			testName:      "TestPayStaticQRCodeWithoutAmount",
+			payload:       static,
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			testName:       "TestPayDynamicQRCode",
+			payload:        dynamic,
+			qr:             pending,
+			expectedAmount: 5000,
+		},
+		{
+			testName:      "TestPayDynamicQRCodeOtherAmount",
+			payload:       dynamic,
+			amount:        4000,
+			qr:            pending,
+			expectedError: ErrQRCodeAmount,
+		},
+		{
+			testName:      "TestPayTamperedQRCode",
+			payload:       tampered,
+			qr:            pending,
+			expectedError: ErrInvalidQRCode,
+		},
+		{
+			testName:      "TestPayQRCodeBadChecksum",
+			payload:       static[:len(static)-4] + "0000",
+			expectedError: ErrInvalidQRCode,
+		},
+		{
+			testName:      "TestPayPaidQRCode",
+			payload:       dynamic,
+			qr:            paid,
+			expectedError: ErrQRCodePaid,
+		},
+		{
+			testName:      "TestPayExpiredQRCode",
+			payload:       dynamic,
+			qr:            expired,
+			expectedError: ErrQRCodeExpired,
+		},
+		{
+			testName:      "TestPayDynamicQRCodeInsufficientFunds",
+			payload:       dynamic,
+			qr:            pending,
+			postError:     repository_ledger.ErrInsufficientFunds,
+			expectedError: ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "payer@gmail.com").Return(payer, nil)
+			users.On("GetUser", mock.Anything, "u1").Return(owner, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetOrCreateWallet", mock.Anything, "u2").Return(entities.Wallet{ID: "w2", Currency: "COP", Balances: map[string]int64{"COP": 10000}}, nil)
+			wallets.On("GetOrCreateWallet", mock.Anything, "u1").Return(entities.Wallet{ID: "w1", Currency: "COP"}, nil)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+				return repository_ledger.IsBalanced(e) && e.Type == entities.EntryMerchantPayment && e.Lines[2].WalletID == "w1" && e.Lines[3].Account == entities.AccountFeeRevenue
+			}), mock.Anything).Return(entities.LedgerEntry{ID: "e1"}, tt.postError)
+			merchants := &merchantRepositoryMock{}
+			merchants.On("GetMerchant", mock.Anything, merchantID).Return(merchant, nil)
+			merchants.On("GetQRCode", mock.Anything, qrCodeID).Return(tt.qr, nil)
+			merchants.On("UpdateQRStatus", mock.Anything, qrCodeID, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			feeRules := &feeRuleRepositoryMock{}
+			feeRules.On("ListFeeRules", mock.Anything, entities.OperationMerchantPayment).Return([]entities.FeeRule{{ID: "r1", Operation: entities.OperationMerchantPayment, Kind: entities.FeePercentage, Bps: 200, Enabled: true}}, nil)
+			fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+			service := NewMerchantService(users, wallets, ledger, merchants, fees, nil, time.Minute, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.PayQRCode(context.Background(), "payer@gmail.com", tt.payload, tt.amount, "")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, "e1", result.ID)
+				assert.Equal(t, tt.expectedAmount, result.Amount)
+				assert.Equal(t, "Payment to Panaderia", result.Memo)
+			}
+			if tt.qr.ID != "" && tt.postError != nil {
+				merchants.AssertCalled(t, "UpdateQRStatus", mock.Anything, qrCodeID, entities.QRPaid, entities.QRPending, "")
+			}
+		})
+	}
+}
+
+func TestGetSettlementReportService(t *testing.T) {
+	// Prepare
+	users := &userServiceMock{}
+	users.On("GetUserByEmail", mock.Anything, "shop@gmail.com").Return(entities.User{ID: "u1"}, nil)
+	wallets := &walletRepositoryMock{}
+	wallets.On("GetOrCreateWallet", mock.Anything, "u1").Return(entities.Wallet{ID: "w1", Currency: "COP"}, nil)
+	merchants := &merchantRepositoryMock{}
+	merchants.On("GetMerchantByUser", mock.Anything, "u1").Return(entities.Merchant{ID: merchantID, UserID: "u1"}, nil)
+	merchants.On("SettlementDays", mock.Anything, "w1", mock.Anything, mock.Anything).Return([]entities.SettlementDay{
+		{Day: "2026-10-01", Currency: "COP", Payments: 2, Gross: 10000, Fees: 200, Net: 9800},
+		{Day: "2026-10-02", Currency: "COP", Payments: 1, Gross: 5000, Fees: 100, Net: 4900},
+		{Day: "2026-10-02", Currency: "USD", Payments: 1, Gross: 1000, Fees: 20, Net: 980},
+	}, nil)
+	service := NewMerchantService(users, wallets, &ledgerRepositoryMock{}, merchants, nil, nil, time.Minute, nil, logrus.StandardLogger(), context.Background())
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	result, err := service.GetSettlementReport(context.Background(), "shop@gmail.com", from, from.AddDate(0, 1, 0))
+	_, periodErr := service.GetSettlementReport(context.Background(), "shop@gmail.com", from, from.AddDate(2, 0, 0))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.SettlementDay{
+		{Currency: "COP", Payments: 3, Gross: 15000, Fees: 300, Net: 14700},
+		{Currency: "USD", Payments: 1, Gross: 1000, Fees: 20, Net: 980},
+	}, result.Totals)
+	assert.Len(t, result.Days, 3)
+	assert.Equal(t, ErrInvalidSettlementPeriod, periodErr)
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type notificationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *notificationRepositoryMock) CreateNotification(notification entities.Notification, ctx context.Context) (entities.Notification, error) {
+	r := m.Called(ctx, notification)
+	return r.Get(0).(entities.Notification), r.Error(1)
+}
+
+func (m *notificationRepositoryMock) ListNotifications(userID string, unreadOnly bool, limit int64, ctx context.Context) ([]entities.Notification, error) {
+	r := m.Called(ctx, userID, unreadOnly, limit)
+	return r.Get(0).([]entities.Notification), r.Error(1)
+}
+
+func (m *notificationRepositoryMock) MarkRead(id string, userID string, at time.Time, ctx context.Context) (entities.Notification, error) {
+	r := m.Called(ctx, id, userID)
+	return r.Get(0).(entities.Notification), r.Error(1)
+}
+
+type notifierMock struct {
+	mock.Mock
+}
+
+func (m *notifierMock) Notify(ctx context.Context, notification entities.Notification) error {
+	r := m.Called(ctx, notification)
+	return r.Error(0)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_notification "my_wallet/api/respository/notification"
+	repository_user "my_wallet/api/respository/user"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const maxNotifications = 100
+
+// Notifier delivers notifications to users.
+type Notifier interface {
+	Notify(ctx context.Context, notification entities.Notification) error
+}
+
+type NotificationService interface {
+	Notifier
+	ListNotifications(ctx context.Context, email string, unreadOnly bool) ([]entities.Notification, error)
+	MarkRead(ctx context.Context, email string, id string) (entities.Notification, error)
+}
+
+type notificationService struct {
+	ctx                    context.Context
+	userRepository         repository_user.UserRepository
+	notificationRepository repository_notification.NotificationRepository
+	logger                 logrus.FieldLogger
+}
+
+func NewNotificationService(userRepo repository_user.UserRepository, notificationRepo repository_notification.NotificationRepository, logger logrus.FieldLogger, ctx context.Context) *notificationService {
+	return &notificationService{
+		ctx:                    ctx,
+		userRepository:         userRepo,
+		notificationRepository: notificationRepo,
+		logger:                 logger,
+	}
+}
+
+// Notify stores a notification in the inbox of its user.
+func (s *notificationService) Notify(ctx context.Context, notification entities.Notification) error {
+	notification.ID = ""
+	notification.Read = false
+	notification.Read_at = nil
+	notification.Created_at = time.Now().UTC()
+	notification, err := s.notificationRepository.CreateNotification(notification, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: notification_services", "Method: Notify", "Error:", err)
+		return err
+	}
+	s.logger.Infoln("Layer: notification_services", "Method: Notify", "Type:", notification.Type, "ID:", notification.ID)
+	return nil
+}
+
+// ListNotifications returns the latest notifications of the authenticated
+// user, newest first.
+func (s *notificationService) ListNotifications(ctx context.Context, email string, unreadOnly bool) ([]entities.Notification, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: notification_services", "Method: ListNotifications", "Error:", err)
+		return nil, err
+	}
+	notifications, err := s.notificationRepository.ListNotifications(user.ID, unreadOnly, maxNotifications, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: notification_services", "Method: ListNotifications", "Error:", err)
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkRead marks a notification of the authenticated user as read.
+func (s *notificationService) MarkRead(ctx context.Context, email string, id string) (entities.Notification, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: notification_services", "Method: MarkRead", "Error:", err)
+		return entities.Notification{}, err
+	}
+	notification, err := s.notificationRepository.MarkRead(id, user.ID, time.Now().UTC(), ctx)
+	if errors.Is(err, repository_notification.ErrNotificationNotFound) {
+		s.logger.Errorln("Layer: notification_services", "Method: MarkRead", "Error:", err)
+		return entities.Notification{}, ErrNotificationNotFound
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: notification_services", "Method: MarkRead", "Error:", err)
+		return entities.Notification{}, err
+	}
+	return notification, nil
+}
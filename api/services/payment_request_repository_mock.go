@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type paymentRequestRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *paymentRequestRepositoryMock) CreatePaymentRequest(request entities.PaymentRequest, ctx context.Context) (entities.PaymentRequest, error) {
+	r := m.Called(ctx, request)
+	return r.Get(0).(entities.PaymentRequest), r.Error(1)
+}
+
+func (m *paymentRequestRepositoryMock) GetPaymentRequest(id string, ctx context.Context) (entities.PaymentRequest, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.PaymentRequest), r.Error(1)
+}
+
+func (m *paymentRequestRepositoryMock) ListPaymentRequests(userID string, direction string, status string, ctx context.Context) ([]entities.PaymentRequest, error) {
+	r := m.Called(ctx, userID, direction, status)
+	return r.Get(0).([]entities.PaymentRequest), r.Error(1)
+}
+
+func (m *paymentRequestRepositoryMock) UpdateStatus(id string, from string, to string, transferID string, ctx context.Context) error {
+	r := m.Called(ctx, id, from, to, transferID)
+	return r.Error(0)
+}
+
+func (m *paymentRequestRepositoryMock) ListSplitRequests(splitID string, ctx context.Context) ([]entities.PaymentRequest, error) {
+	r := m.Called(ctx, splitID)
+	return r.Get(0).([]entities.PaymentRequest), r.Error(1)
+}
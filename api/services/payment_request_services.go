@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_payment_request "my_wallet/api/respository/payment_request"
+	repository_user "my_wallet/api/respository/user"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+var paymentRequestStatuses = map[string]bool{
+	entities.RequestPending:   true,
+	entities.RequestAccepted:  true,
+	entities.RequestDeclined:  true,
+	entities.RequestCancelled: true,
+	entities.RequestExpired:   true,
+	entities.RequestHeld:      true,
+}
+
+type PaymentRequestService interface {
+	CreatePaymentRequest(ctx context.Context, email string, request entities.PaymentRequest) (entities.PaymentRequest, error)
+	ListPaymentRequests(ctx context.Context, email string, direction string, status string) ([]entities.PaymentRequest, error)
+	AcceptPaymentRequest(ctx context.Context, email string, id string) (entities.PaymentRequest, error)
+	DeclinePaymentRequest(ctx context.Context, email string, id string) (entities.PaymentRequest, error)
+	CancelPaymentRequest(ctx context.Context, email string, id string) (entities.PaymentRequest, error)
+}
+
+type paymentRequestService struct {
+	ctx                      context.Context
+	userRepository           repository_user.UserRepository
+	paymentRequestRepository repository_payment_request.PaymentRequestRepository
+	transferService          TransferService
+	ttl                      time.Duration
+	logger                   logrus.FieldLogger
+}
+
+func NewPaymentRequestService(userRepo repository_user.UserRepository, paymentRequestRepo repository_payment_request.PaymentRequestRepository, transferService TransferService, ttl time.Duration, logger logrus.FieldLogger, ctx context.Context) *paymentRequestService {
+	return &paymentRequestService{
+		ctx:                      ctx,
+		userRepository:           userRepo,
+		paymentRequestRepository: paymentRequestRepo,
+		transferService:          transferService,
+		ttl:                      ttl,
+		logger:                   logger,
+	}
+}
+
+// CreatePaymentRequest asks the payer, found by email, phone or DNI, to pay
+// the authenticated user. The request expires after the configured TTL.
+func (s *paymentRequestService) CreatePaymentRequest(ctx context.Context, email string, request entities.PaymentRequest) (entities.PaymentRequest, error) {
+	if request.Amount <= 0 {
+		s.logger.Errorln("Layer: payment_request_services", "Method: CreatePaymentRequest", "Error:", ErrInvalidAmount)
+		return entities.PaymentRequest{}, ErrInvalidAmount
+	}
+	if request.Currency == "" {
+		request.Currency = entities.DefaultCurrency
+	}
+	if !entities.ValidCurrency(request.Currency) {
+		s.logger.Errorln("Layer: payment_request_services", "Method: CreatePaymentRequest", "Error:", ErrInvalidCurrency)
+		return entities.PaymentRequest{}, ErrInvalidCurrency
+	}
+	if utf8.RuneCountInString(request.Memo) > maxMemoLength {
+		s.logger.Errorln("Layer: payment_request_services", "Method: CreatePaymentRequest", "Error:", ErrMemoTooLong)
+		return entities.PaymentRequest{}, ErrMemoTooLong
+	}
+	requester, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: CreatePaymentRequest", "Error:", err)
+		return entities.PaymentRequest{}, err
+	}
+	payer, err := findPayer(ctx, s.userRepository, request)
+	if err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: CreatePaymentRequest", "Error:", err)
+		return entities.PaymentRequest{}, err
+	}
+	if payer.ID == requester.ID {
+		s.logger.Errorln("Layer: payment_request_services", "Method: CreatePaymentRequest", "Error:", ErrSelfRequest)
+		return entities.PaymentRequest{}, ErrSelfRequest
+	}
+
+	now := time.Now().UTC()
+	request.ID = ""
+	request.RequesterID = requester.ID
+	request.PayerID = payer.ID
+	request.Status = entities.RequestPending
+	request.TransferID = ""
+	request.ExpiresAt = now.Add(s.ttl)
+	request.Created_at = now
+	request.Update_at = now
+	return s.paymentRequestRepository.CreatePaymentRequest(request, ctx)
+}
+
+// ListPaymentRequests lists the requests received (incoming) or sent
+// (outgoing) by the authenticated user. Pending requests past their expiry
+// are reported as expired.
+func (s *paymentRequestService) ListPaymentRequests(ctx context.Context, email string, direction string, status string) ([]entities.PaymentRequest, error) {
+	if direction != entities.DirectionIncoming && direction != entities.DirectionOutgoing {
+		s.logger.Errorln("Layer: payment_request_services", "Method: ListPaymentRequests", "Error:", ErrInvalidPaymentRequestFilter)
+		return nil, ErrInvalidPaymentRequestFilter
+	}
+	if status != "" && !paymentRequestStatuses[status] {
+		s.logger.Errorln("Layer: payment_request_services", "Method: ListPaymentRequests", "Error:", ErrInvalidPaymentRequestFilter)
+		return nil, ErrInvalidPaymentRequestFilter
+	}
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: ListPaymentRequests", "Error:", err)
+		return nil, err
+	}
+	requests, err := s.paymentRequestRepository.ListPaymentRequests(user.ID, direction, status, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: ListPaymentRequests", "Error:", err)
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for i := range requests {
+		if requests[i].Expired(now) {
+			requests[i].Status = entities.RequestExpired
+		}
+	}
+	return requests, nil
+}
+
+// AcceptPaymentRequest pays a pending request of which the authenticated user
+// is the payer. The request is taken before the transfer is made and given
+// back if the transfer fails, so it is never paid twice. A transfer held for
+// review leaves the request held until the analysts decide on it.
+func (s *paymentRequestService) AcceptPaymentRequest(ctx context.Context, email string, id string) (entities.PaymentRequest, error) {
+	payer, request, err := s.answerable(ctx, email, id, true)
+	if err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: AcceptPaymentRequest", "Error:", err)
+		return entities.PaymentRequest{}, err
+	}
+	if err := s.updateStatus(ctx, request.ID, entities.RequestPending, entities.RequestAccepted, ""); err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: AcceptPaymentRequest", "Error:", err)
+		return entities.PaymentRequest{}, err
+	}
+
+	transfer, err := s.pay(ctx, payer, request)
+	if errors.Is(err, ErrTransferHeld) {
+		s.logger.Errorln("Layer: payment_request_services", "Method: AcceptPaymentRequest", "Error:", err)
+		if holdErr := s.updateStatus(ctx, request.ID, entities.RequestAccepted, entities.RequestHeld, ""); holdErr != nil {
+			s.logger.Errorln("Layer: payment_request_services", "Method: AcceptPaymentRequest", "Error:", holdErr)
+		}
+		return entities.PaymentRequest{}, err
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: AcceptPaymentRequest", "Error:", err)
+		if undoErr := s.updateStatus(ctx, request.ID, entities.RequestAccepted, entities.RequestPending, ""); undoErr != nil {
+			s.logger.Errorln("Layer: payment_request_services", "Method: AcceptPaymentRequest", "Error:", undoErr)
+		}
+		return entities.PaymentRequest{}, err
+	}
+	if err := s.updateStatus(ctx, request.ID, entities.RequestAccepted, entities.RequestAccepted, transfer.ID); err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: AcceptPaymentRequest", "Error:", err)
+	}
+
+	request.Status = entities.RequestAccepted
+	request.TransferID = transfer.ID
+	request.Update_at = time.Now().UTC()
+	s.logger.Infoln("Layer: payment_request_services", "Method: AcceptPaymentRequest", "PaymentRequest:", request.ID)
+	return request, nil
+}
+
+// TransferReleased marks accepted the request whose transfer an analyst
+// released.
+func (s *paymentRequestService) TransferReleased(ctx context.Context, review entities.ScreeningReview) error {
+	return s.updateStatus(ctx, review.Transfer.SourceID, entities.RequestHeld, entities.RequestAccepted, review.Transfer.ID)
+}
+
+// TransferRejected gives the request back to the payer when its transfer was
+// rejected or could not be made once released.
+func (s *paymentRequestService) TransferRejected(ctx context.Context, review entities.ScreeningReview) error {
+	return s.updateStatus(ctx, review.Transfer.SourceID, entities.RequestHeld, entities.RequestPending, "")
+}
+
+// DeclinePaymentRequest is the payer refusing to pay.
+func (s *paymentRequestService) DeclinePaymentRequest(ctx context.Context, email string, id string) (entities.PaymentRequest, error) {
+	_, request, err := s.answerable(ctx, email, id, true)
+	if err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: DeclinePaymentRequest", "Error:", err)
+		return entities.PaymentRequest{}, err
+	}
+	if err := s.updateStatus(ctx, request.ID, entities.RequestPending, entities.RequestDeclined, ""); err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: DeclinePaymentRequest", "Error:", err)
+		return entities.PaymentRequest{}, err
+	}
+	request.Status = entities.RequestDeclined
+	request.Update_at = time.Now().UTC()
+	return request, nil
+}
+
+// CancelPaymentRequest is the requester withdrawing a pending request.
+func (s *paymentRequestService) CancelPaymentRequest(ctx context.Context, email string, id string) (entities.PaymentRequest, error) {
+	_, request, err := s.answerable(ctx, email, id, false)
+	if err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: CancelPaymentRequest", "Error:", err)
+		return entities.PaymentRequest{}, err
+	}
+	if err := s.updateStatus(ctx, request.ID, entities.RequestPending, entities.RequestCancelled, ""); err != nil {
+		s.logger.Errorln("Layer: payment_request_services", "Method: CancelPaymentRequest", "Error:", err)
+		return entities.PaymentRequest{}, err
+	}
+	request.Status = entities.RequestCancelled
+	request.Update_at = time.Now().UTC()
+	return request, nil
+}
+
+// answerable loads a pending request of the authenticated user, who must be
+// its payer or, when asPayer is false, its requester. A request found past
+// its expiry is marked expired.
+func (s *paymentRequestService) answerable(ctx context.Context, email string, id string, asPayer bool) (entities.User, entities.PaymentRequest, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.User{}, entities.PaymentRequest{}, err
+	}
+	request, err := s.paymentRequestRepository.GetPaymentRequest(id, ctx)
+	if errors.Is(err, repository_payment_request.ErrPaymentRequestNotFound) {
+		return entities.User{}, entities.PaymentRequest{}, ErrPaymentRequestNotFound
+	}
+	if err != nil {
+		return entities.User{}, entities.PaymentRequest{}, err
+	}
+	if (asPayer && request.PayerID != user.ID) || (!asPayer && request.RequesterID != user.ID) {
+		return entities.User{}, entities.PaymentRequest{}, ErrPaymentRequestNotFound
+	}
+	if request.Expired(time.Now().UTC()) {
+		if err := s.updateStatus(ctx, request.ID, entities.RequestPending, entities.RequestExpired, ""); err != nil && !errors.Is(err, ErrPaymentRequestNotPending) {
+			return entities.User{}, entities.PaymentRequest{}, err
+		}
+		return entities.User{}, entities.PaymentRequest{}, ErrPaymentRequestExpired
+	}
+	if request.Status != entities.RequestPending {
+		return entities.User{}, entities.PaymentRequest{}, ErrPaymentRequestNotPending
+	}
+	return user, request, nil
+}
+
+func (s *paymentRequestService) updateStatus(ctx context.Context, id string, from string, to string, transferID string) error {
+	err := s.paymentRequestRepository.UpdateStatus(id, from, to, transferID, ctx)
+	if errors.Is(err, repository_payment_request.ErrStatusChanged) {
+		return ErrPaymentRequestNotPending
+	}
+	return err
+}
+
+func (s *paymentRequestService) pay(ctx context.Context, payer entities.User, request entities.PaymentRequest) (entities.Transfer, error) {
+	requester, err := s.userRepository.GetUser(request.RequesterID, ctx)
+	if err != nil {
+		return entities.Transfer{}, err
+	}
+	return s.transferService.CreateTransfer(ctx, payer.Email, entities.Transfer{
+		RecipientEmail: requester.Email,
+		Amount:         request.Amount,
+		Currency:       request.Currency,
+		Memo:           request.Memo,
+		Source:         entities.TransferSourcePaymentRequest,
+		SourceID:       request.ID,
+	})
+}
+
+// findPayer resolves the payer like the recipient of a transfer.
+func findPayer(ctx context.Context, users repository_user.UserRepository, request entities.PaymentRequest) (entities.User, error) {
+	payer, err := findRecipient(ctx, users, entities.Transfer{
+		RecipientEmail: request.PayerEmail,
+		RecipientPhone: request.PayerPhone,
+		RecipientDNI:   request.PayerDNI,
+	})
+	switch {
+	case errors.Is(err, ErrRecipientRequired):
+		return entities.User{}, ErrPayerRequired
+	case errors.Is(err, ErrRecipientNotFound):
+		return entities.User{}, ErrPayerNotFound
+	case errors.Is(err, ErrRecipientDisabled):
+		return entities.User{}, ErrPayerDisabled
+	}
+	return payer, err
+}
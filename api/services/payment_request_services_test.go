@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_payment_request "my_wallet/api/respository/payment_request"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const paymentRequestTTL = 7 * 24 * time.Hour
+
+func TestCreatePaymentRequestService(t *testing.T) {
+	requester := entities.User{ID: "u1", Email: "alexer@gmail.com", Enabled: true}
+	friend := entities.User{ID: "u2", Email: "friend@gmail.com", Enabled: true}
+
+	testScenarios := []struct {
+		testName       string
+		request        entities.PaymentRequest
+		configureMock  func(*userServiceMock, *paymentRequestRepositoryMock)
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			testName: "TestCreatePaymentRequestService",
+			request:  entities.PaymentRequest{PayerEmail: "friend@gmail.com", Amount: 45000, Memo: "Movie tickets"},
+			configureMock: func(u *userServiceMock, r *paymentRequestRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "friend@gmail.com").Return(friend, nil)
+				r.On("CreatePaymentRequest", mock.Anything, mock.MatchedBy(func(p entities.PaymentRequest) bool {
+					return p.RequesterID == "u1" && p.PayerID == "u2" && p.Currency == entities.DefaultCurrency &&
+						p.ExpiresAt.Sub(p.Created_at) == paymentRequestTTL
+				})).Return(entities.PaymentRequest{ID: "r1", Status: entities.RequestPending}, nil)
+			},
+			expectedStatus: entities.RequestPending,
+		},
+		{
+			testName:      "TestInvalidAmount",
+			request:       entities.PaymentRequest{PayerEmail: "friend@gmail.com"},
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			testName:      "TestPayerRequired",
+			request:       entities.PaymentRequest{Amount: 45000},
+			expectedError: ErrPayerRequired,
+		},
+		{
+			testName:      "TestSelfRequest",
+			request:       entities.PaymentRequest{PayerEmail: "alexer@gmail.com", Amount: 45000},
+			expectedError: ErrSelfRequest,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(requester, nil)
+			requests := &paymentRequestRepositoryMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(users, requests)
+			}
+			service := NewPaymentRequestService(users, requests, &transferServiceMock{}, paymentRequestTTL, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreatePaymentRequest(context.Background(), "alexer@gmail.com", tt.request)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+		})
+	}
+}
+
+func TestAcceptPaymentRequestService(t *testing.T) {
+	requester := entities.User{ID: "u1", Email: "alexer@gmail.com", Enabled: true}
+	payer := entities.User{ID: "u2", Email: "friend@gmail.com", Enabled: true}
+	pending := entities.PaymentRequest{
+		ID: "r1", RequesterID: "u1", PayerID: "u2", Amount: 45000, Currency: "COP",
+		Status: entities.RequestPending, ExpiresAt: time.Now().UTC().Add(time.Hour),
+	}
+	expired := pending
+	expired.ExpiresAt = time.Now().UTC().Add(-time.Hour)
+	accepted := pending
+	accepted.Status = entities.RequestAccepted
+
+	testScenarios := []struct {
+		testName           string
+		request            entities.PaymentRequest
+		configureMock      func(*paymentRequestRepositoryMock, *transferServiceMock)
+		expectedTransferID string
+		expectedError      error
+	}{
+		{
+			testName: "TestAcceptPaymentRequestService",
+			request:  pending,
+			configureMock: func(r *paymentRequestRepositoryMock, tr *transferServiceMock) {
+				r.On("UpdateStatus", mock.Anything, "r1", entities.RequestPending, entities.RequestAccepted, "").Return(nil)
+				tr.On("CreateTransfer", mock.Anything, "friend@gmail.com", entities.Transfer{RecipientEmail: "alexer@gmail.com", Amount: 45000, Currency: "COP", Source: entities.TransferSourcePaymentRequest, SourceID: "r1"}).
+					Return(entities.Transfer{ID: "t1"}, nil)
+				r.On("UpdateStatus", mock.Anything, "r1", entities.RequestAccepted, entities.RequestAccepted, "t1").Return(nil)
+			},
+			expectedTransferID: "t1",
+		},
+		{
+			testName: "TestExpiredRequest",
+			request:  expired,
+			configureMock: func(r *paymentRequestRepositoryMock, tr *transferServiceMock) {
+				r.On("UpdateStatus", mock.Anything, "r1", entities.RequestPending, entities.RequestExpired, "").Return(nil)
+			},
+			expectedError: ErrPaymentRequestExpired,
+		},
+		{
+			testName:      "TestAlreadyAccepted",
+			request:       accepted,
+			expectedError: ErrPaymentRequestNotPending,
+		},
+		{
+			testName: "TestConcurrentAnswer",
+			request:  pending,
+			configureMock: func(r *paymentRequestRepositoryMock, tr *transferServiceMock) {
+				r.On("UpdateStatus", mock.Anything, "r1", entities.RequestPending, entities.RequestAccepted, "").Return(repository_payment_request.ErrStatusChanged)
+			},
+			expectedError: ErrPaymentRequestNotPending,
+		},
+		{
+			testName: "TestTransferFailureReopensRequest",
+			request:  pending,
+			configureMock: func(r *paymentRequestRepositoryMock, tr *transferServiceMock) {
+				r.On("UpdateStatus", mock.Anything, "r1", entities.RequestPending, entities.RequestAccepted, "").Return(nil)
+				tr.On("CreateTransfer", mock.Anything, "friend@gmail.com", mock.Anything).Return(entities.Transfer{}, ErrInsufficientFunds)
+				r.On("UpdateStatus", mock.Anything, "r1", entities.RequestAccepted, entities.RequestPending, "").Return(nil).Once()
+			},
+			expectedError: ErrInsufficientFunds,
+		},
+		{
+			testName: "TestHeldTransferHoldsRequest",
+			request:  pending,
+			configureMock: func(r *paymentRequestRepositoryMock, tr *transferServiceMock) {
+				r.On("UpdateStatus", mock.Anything, "r1", entities.RequestPending, entities.RequestAccepted, "").Return(nil)
+				tr.On("CreateTransfer", mock.Anything, "friend@gmail.com", mock.Anything).Return(entities.Transfer{}, &TransferHeldError{ReviewID: "rv1"})
+				r.On("UpdateStatus", mock.Anything, "r1", entities.RequestAccepted, entities.RequestHeld, "").Return(nil).Once()
+			},
+			expectedError: &TransferHeldError{ReviewID: "rv1"},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "friend@gmail.com").Return(payer, nil)
+			users.On("GetUser", mock.Anything, "u1").Return(requester, nil)
+			requests := &paymentRequestRepositoryMock{}
+			requests.On("GetPaymentRequest", mock.Anything, "r1").Return(tt.request, nil)
+			transfers := &transferServiceMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(requests, transfers)
+			}
+			service := NewPaymentRequestService(users, requests, transfers, paymentRequestTTL, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.AcceptPaymentRequest(context.Background(), "friend@gmail.com", "r1")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedTransferID, result.TransferID)
+			requests.AssertExpectations(t)
+			transfers.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAnswerPaymentRequestService(t *testing.T) {
+	requester := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	payer := entities.User{ID: "u2", Email: "friend@gmail.com"}
+	pending := entities.PaymentRequest{
+		ID: "r1", RequesterID: "u1", PayerID: "u2", Amount: 45000, Currency: "COP",
+		Status: entities.RequestPending, ExpiresAt: time.Now().UTC().Add(time.Hour),
+	}
+
+	testScenarios := []struct {
+		testName       string
+		email          string
+		decline        bool
+		configureMock  func(*paymentRequestRepositoryMock)
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			testName: "TestDeclinePaymentRequestService",
+			email:    "friend@gmail.com",
+			decline:  true,
+			configureMock: func(r *paymentRequestRepositoryMock) {
+				r.On("UpdateStatus", mock.Anything, "r1", entities.RequestPending, entities.RequestDeclined, "").Return(nil)
+			},
+			expectedStatus: entities.RequestDeclined,
+		},
+		{
+			testName:      "TestRequesterCannotDecline",
+			email:         "alexer@gmail.com",
+			decline:       true,
+			expectedError: ErrPaymentRequestNotFound,
+		},
+		{
+			testName: "TestCancelPaymentRequestService",
+			email:    "alexer@gmail.com",
+			configureMock: func(r *paymentRequestRepositoryMock) {
+				r.On("UpdateStatus", mock.Anything, "r1", entities.RequestPending, entities.RequestCancelled, "").Return(nil)
+			},
+			expectedStatus: entities.RequestCancelled,
+		},
+		{
+			testName:      "TestPayerCannotCancel",
+			email:         "friend@gmail.com",
+			expectedError: ErrPaymentRequestNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(requester, nil)
+			users.On("GetUserByEmail", mock.Anything, "friend@gmail.com").Return(payer, nil)
+			requests := &paymentRequestRepositoryMock{}
+			requests.On("GetPaymentRequest", mock.Anything, "r1").Return(pending, nil)
+			if tt.configureMock != nil {
+				tt.configureMock(requests)
+			}
+			service := NewPaymentRequestService(users, requests, &transferServiceMock{}, paymentRequestTTL, logrus.StandardLogger(), context.Background())
+
+			// Act
+			var result entities.PaymentRequest
+			var err error
+			if tt.decline {
+				result, err = service.DeclinePaymentRequest(context.Background(), tt.email, "r1")
+			} else {
+				result, err = service.CancelPaymentRequest(context.Background(), tt.email, "r1")
+			}
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+		})
+	}
+}
+
+func TestListPaymentRequestsService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	stale := entities.PaymentRequest{ID: "r1", Status: entities.RequestPending, ExpiresAt: time.Now().UTC().Add(-time.Hour)}
+
+	testScenarios := []struct {
+		testName         string
+		direction        string
+		status           string
+		expectedStatuses []string
+		expectedError    error
+	}{
+		{
+			testName:         "TestListPaymentRequestsService",
+			direction:        entities.DirectionIncoming,
+			expectedStatuses: []string{entities.RequestExpired},
+		},
+		{
+			testName:      "TestInvalidDirection",
+			direction:     "sideways",
+			expectedError: ErrInvalidPaymentRequestFilter,
+		},
+		{
+			testName:      "TestInvalidStatus",
+			direction:     entities.DirectionOutgoing,
+			status:        "paid",
+			expectedError: ErrInvalidPaymentRequestFilter,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			requests := &paymentRequestRepositoryMock{}
+			requests.On("ListPaymentRequests", mock.Anything, "u1", tt.direction, tt.status).Return([]entities.PaymentRequest{stale}, nil)
+			service := NewPaymentRequestService(users, requests, &transferServiceMock{}, paymentRequestTTL, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.ListPaymentRequests(context.Background(), "alexer@gmail.com", tt.direction, tt.status)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			var statuses []string
+			for _, r := range result {
+				statuses = append(statuses, r.Status)
+			}
+			assert.Equal(t, tt.expectedStatuses, statuses)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type pocketRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *pocketRepositoryMock) CreatePocket(pocket entities.Pocket, ctx context.Context) (entities.Pocket, error) {
+	r := m.Called(ctx, pocket)
+	return r.Get(0).(entities.Pocket), r.Error(1)
+}
+
+func (m *pocketRepositoryMock) GetPocket(id string, ctx context.Context) (entities.Pocket, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.Pocket), r.Error(1)
+}
+
+func (m *pocketRepositoryMock) ListPockets(walletID string, ctx context.Context) ([]entities.Pocket, error) {
+	r := m.Called(ctx, walletID)
+	return r.Get(0).([]entities.Pocket), r.Error(1)
+}
+
+func (m *pocketRepositoryMock) UpdatePocket(pocket entities.Pocket, ctx context.Context) error {
+	r := m.Called(ctx, pocket)
+	return r.Error(0)
+}
+
+func (m *pocketRepositoryMock) ClosePocket(id string, ctx context.Context) error {
+	r := m.Called(ctx, id)
+	return r.Error(0)
+}
+
+func (m *pocketRepositoryMock) DueAutoSaves(now time.Time, limit int64, ctx context.Context) ([]entities.Pocket, error) {
+	r := m.Called(ctx, now, limit)
+	return r.Get(0).([]entities.Pocket), r.Error(1)
+}
+
+func (m *pocketRepositoryMock) ClaimAutoSave(id string, dueAt time.Time, next time.Time, ctx context.Context) error {
+	r := m.Called(ctx, id, dueAt, next)
+	return r.Error(0)
+}
@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_pocket "my_wallet/api/respository/pocket"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxPocketsPerWallet  = 10
+	maxPocketNameLength  = 50
+	autoSaveInterval     = 7 * 24 * time.Hour
+	dueAutoSavesBatch    = 50
+	pocketRoundUpMemo    = "Round-up"
+	pocketWeeklySaveMemo = "Weekly save"
+)
+
+// AutoSaver saves the round-up of an outgoing transfer into a pocket.
+type AutoSaver interface {
+	RoundUp(ctx context.Context, transfer entities.Transfer)
+}
+
+type PocketService interface {
+	AutoSaver
+	CreatePocket(ctx context.Context, email string, walletID string, pocket entities.Pocket) (entities.Pocket, error)
+	ListPockets(ctx context.Context, email string, walletID string) ([]entities.Pocket, error)
+	GetPocket(ctx context.Context, email string, walletID string, pocketID string) (entities.Pocket, error)
+	UpdatePocket(ctx context.Context, email string, walletID string, pocket entities.Pocket) (entities.Pocket, error)
+	MoveToPocket(ctx context.Context, email string, walletID string, pocketID string, amount int64) (entities.Pocket, error)
+	MoveFromPocket(ctx context.Context, email string, walletID string, pocketID string, amount int64) (entities.Pocket, error)
+	ClosePocket(ctx context.Context, email string, walletID string, pocketID string) (entities.Pocket, error)
+	RunAutoSaves(ctx context.Context, now time.Time) (int, error)
+}
+
+type pocketService struct {
+	ctx              context.Context
+	userRepository   repository_user.UserRepository
+	walletRepository repository_wallet.WalletRepository
+	ledgerRepository repository_ledger.LedgerRepository
+	pocketRepository repository_pocket.PocketRepository
+	restrictions     Restrictions
+	members          Memberships
+	logger           logrus.FieldLogger
+}
+
+func NewPocketService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, ledgerRepo repository_ledger.LedgerRepository, pocketRepo repository_pocket.PocketRepository, restrictions Restrictions, members Memberships, logger logrus.FieldLogger, ctx context.Context) *pocketService {
+	return &pocketService{
+		ctx:              ctx,
+		userRepository:   userRepo,
+		walletRepository: walletRepo,
+		ledgerRepository: ledgerRepo,
+		pocketRepository: pocketRepo,
+		restrictions:     restrictions,
+		members:          members,
+		logger:           logger,
+	}
+}
+
+// CreatePocket opens an empty pocket in a wallet of the authenticated user.
+// The pocket holds the wallet's default currency unless it names one.
+func (s *pocketService) CreatePocket(ctx context.Context, email string, walletID string, pocket entities.Pocket) (entities.Pocket, error) {
+	_, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, walletID, entities.PermissionManage)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: CreatePocket", "Error:", err)
+		return entities.Pocket{}, err
+	}
+	if pocket.Currency == "" {
+		pocket.Currency = wallet.Currency
+	}
+	now := time.Now().UTC()
+	if err := validatePocket(pocket, now); err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: CreatePocket", "Error:", err)
+		return entities.Pocket{}, err
+	}
+	pockets, err := s.pocketRepository.ListPockets(wallet.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: CreatePocket", "Error:", err)
+		return entities.Pocket{}, err
+	}
+	if len(pockets) >= maxPocketsPerWallet {
+		s.logger.Errorln("Layer: pocket_services", "Method: CreatePocket", "Error:", ErrTooManyPockets)
+		return entities.Pocket{}, ErrTooManyPockets
+	}
+
+	pocket.ID = ""
+	pocket.WalletID = wallet.ID
+	pocket.UserID = wallet.UserID
+	pocket.Balance = 0
+	pocket.Status = entities.PocketActive
+	pocket.NextAutoSaveAt = nextAutoSave(pocket, nil, now)
+	pocket.Created_at = now
+	pocket.Update_at = now
+	pocket, err = s.pocketRepository.CreatePocket(pocket, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: CreatePocket", "Error:", err)
+		return entities.Pocket{}, err
+	}
+	pocket.Progress = pocketProgress(pocket, now)
+	return pocket, nil
+}
+
+// ListPockets lists the active pockets of the wallet with their progress.
+func (s *pocketService) ListPockets(ctx context.Context, email string, walletID string) ([]entities.Pocket, error) {
+	_, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, walletID, entities.PermissionView)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: ListPockets", "Error:", err)
+		return nil, err
+	}
+	pockets, err := s.pocketRepository.ListPockets(wallet.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: ListPockets", "Error:", err)
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for i := range pockets {
+		pockets[i].Progress = pocketProgress(pockets[i], now)
+	}
+	return pockets, nil
+}
+
+// GetPocket returns a pocket of the wallet with its progress.
+func (s *pocketService) GetPocket(ctx context.Context, email string, walletID string, pocketID string) (entities.Pocket, error) {
+	_, pocket, err := s.memberPocket(ctx, email, walletID, pocketID, entities.PermissionView)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: GetPocket", "Error:", err)
+		return entities.Pocket{}, err
+	}
+	pocket.Progress = pocketProgress(pocket, time.Now().UTC())
+	return pocket, nil
+}
+
+// UpdatePocket changes the name, goal and auto-save rules of a pocket.
+func (s *pocketService) UpdatePocket(ctx context.Context, email string, walletID string, pocket entities.Pocket) (entities.Pocket, error) {
+	_, current, err := s.memberPocket(ctx, email, walletID, pocket.ID, entities.PermissionManage)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: UpdatePocket", "Error:", err)
+		return entities.Pocket{}, err
+	}
+	if current.Status != entities.PocketActive {
+		s.logger.Errorln("Layer: pocket_services", "Method: UpdatePocket", "Error:", ErrPocketNotFound)
+		return entities.Pocket{}, ErrPocketNotFound
+	}
+	now := time.Now().UTC()
+	pocket.Currency = current.Currency
+	if err := validatePocket(pocket, now); err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: UpdatePocket", "Error:", err)
+		return entities.Pocket{}, err
+	}
+
+	current.Name = pocket.Name
+	current.TargetAmount = pocket.TargetAmount
+	current.TargetDate = pocket.TargetDate
+	current.RoundUpTo = pocket.RoundUpTo
+	current.NextAutoSaveAt = nextAutoSave(pocket, current.NextAutoSaveAt, now)
+	current.WeeklyAmount = pocket.WeeklyAmount
+	current.Update_at = now
+	if err := s.pocketRepository.UpdatePocket(current, ctx); err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: UpdatePocket", "Error:", err)
+		if errors.Is(err, repository_pocket.ErrPocketNotFound) {
+			return entities.Pocket{}, ErrPocketNotFound
+		}
+		return entities.Pocket{}, err
+	}
+	current.Progress = pocketProgress(current, now)
+	return current, nil
+}
+
+// MoveToPocket moves money from the wallet balance into the pocket.
+func (s *pocketService) MoveToPocket(ctx context.Context, email string, walletID string, pocketID string, amount int64) (entities.Pocket, error) {
+	if amount <= 0 {
+		s.logger.Errorln("Layer: pocket_services", "Method: MoveToPocket", "Error:", ErrInvalidAmount)
+		return entities.Pocket{}, ErrInvalidAmount
+	}
+	return s.move(ctx, "MoveToPocket", email, walletID, pocketID, amount)
+}
+
+// MoveFromPocket gives money saved in the pocket back to the wallet balance.
+func (s *pocketService) MoveFromPocket(ctx context.Context, email string, walletID string, pocketID string, amount int64) (entities.Pocket, error) {
+	if amount <= 0 {
+		s.logger.Errorln("Layer: pocket_services", "Method: MoveFromPocket", "Error:", ErrInvalidAmount)
+		return entities.Pocket{}, ErrInvalidAmount
+	}
+	return s.move(ctx, "MoveFromPocket", email, walletID, pocketID, -amount)
+}
+
+// ClosePocket gives the whole balance of the pocket back to the wallet and
+// closes it.
+func (s *pocketService) ClosePocket(ctx context.Context, email string, walletID string, pocketID string) (entities.Pocket, error) {
+	user, pocket, err := s.memberPocket(ctx, email, walletID, pocketID, entities.PermissionManage)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: ClosePocket", "Error:", err)
+		return entities.Pocket{}, err
+	}
+	if pocket.Status != entities.PocketActive {
+		s.logger.Errorln("Layer: pocket_services", "Method: ClosePocket", "Error:", ErrPocketNotFound)
+		return entities.Pocket{}, ErrPocketNotFound
+	}
+	if pocket.Balance > 0 {
+		if err := s.post(ctx, pocket, -pocket.Balance, "Pocket closed", user.ID); err != nil {
+			s.logger.Errorln("Layer: pocket_services", "Method: ClosePocket", "Error:", err)
+			return entities.Pocket{}, err
+		}
+	}
+	err = s.pocketRepository.ClosePocket(pocket.ID, ctx)
+	if errors.Is(err, repository_pocket.ErrPocketChanged) {
+		s.logger.Errorln("Layer: pocket_services", "Method: ClosePocket", "Error:", err)
+		return entities.Pocket{}, ErrPocketNotEmpty
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: ClosePocket", "Error:", err)
+		return entities.Pocket{}, err
+	}
+	pocket.Balance = 0
+	pocket.Status = entities.PocketClosed
+	pocket.NextAutoSaveAt = nil
+	pocket.Update_at = time.Now().UTC()
+	return pocket, nil
+}
+
+// RoundUp saves the difference between an outgoing transfer and the next
+// multiple of the round-up unit into the oldest pocket of the sender wallet
+// that rounds up in the transfer currency. It never fails the transfer: a
+// round-up that cannot be saved is only logged.
+func (s *pocketService) RoundUp(ctx context.Context, transfer entities.Transfer) {
+	pockets, err := s.pocketRepository.ListPockets(transfer.SenderWalletID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: RoundUp", "Error:", err)
+		return
+	}
+	for _, pocket := range pockets {
+		if pocket.RoundUpTo <= 0 || pocket.Currency != transfer.Currency {
+			continue
+		}
+		amount := roundUpAmount(transfer.Amount+transfer.Fee, pocket.RoundUpTo)
+		if amount == 0 {
+			return
+		}
+		if err := s.post(ctx, pocket, amount, pocketRoundUpMemo, ""); err != nil {
+			s.logger.Errorln("Layer: pocket_services", "Method: RoundUp", "Error:", err)
+		}
+		return
+	}
+}
+
+// RunAutoSaves makes the weekly saves that are due. Each save is claimed
+// before the money is moved, so replicas racing on the same pocket save it
+// once. A week that cannot be saved, or whose pocket already reached its
+// target, is skipped. It returns how many saves were made.
+func (s *pocketService) RunAutoSaves(ctx context.Context, now time.Time) (int, error) {
+	pockets, err := s.pocketRepository.DueAutoSaves(now, dueAutoSavesBatch, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: RunAutoSaves", "Error:", err)
+		return 0, err
+	}
+	saves := 0
+	for _, pocket := range pockets {
+		if pocket.NextAutoSaveAt == nil {
+			continue
+		}
+		next := *pocket.NextAutoSaveAt
+		for !next.After(now) {
+			next = next.Add(autoSaveInterval)
+		}
+		err := s.pocketRepository.ClaimAutoSave(pocket.ID, *pocket.NextAutoSaveAt, next, ctx)
+		if errors.Is(err, repository_pocket.ErrPocketChanged) {
+			continue
+		}
+		if err != nil {
+			s.logger.Errorln("Layer: pocket_services", "Method: RunAutoSaves", "Error:", err)
+			continue
+		}
+		amount := pocket.WeeklyAmount
+		if remaining := pocket.TargetAmount - pocket.Balance; remaining < amount {
+			amount = remaining
+		}
+		if amount <= 0 {
+			continue
+		}
+		if err := s.post(ctx, pocket, amount, pocketWeeklySaveMemo, ""); err != nil {
+			s.logger.Errorln("Layer: pocket_services", "Method: RunAutoSaves", "Pocket:", pocket.ID, "Error:", err)
+			continue
+		}
+		saves++
+	}
+	return saves, nil
+}
+
+// move posts a signed amount between the wallet and one of its active
+// pockets, positive into the pocket.
+func (s *pocketService) move(ctx context.Context, method string, email string, walletID string, pocketID string, amount int64) (entities.Pocket, error) {
+	user, pocket, err := s.memberPocket(ctx, email, walletID, pocketID, entities.PermissionManage)
+	if err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: "+method, "Error:", err)
+		return entities.Pocket{}, err
+	}
+	if pocket.Status != entities.PocketActive {
+		s.logger.Errorln("Layer: pocket_services", "Method: "+method, "Error:", ErrPocketNotFound)
+		return entities.Pocket{}, ErrPocketNotFound
+	}
+	if err := s.post(ctx, pocket, amount, pocket.Name, user.ID); err != nil {
+		s.logger.Errorln("Layer: pocket_services", "Method: "+method, "Error:", err)
+		return entities.Pocket{}, err
+	}
+	pocket.Balance += amount
+	pocket.Update_at = time.Now().UTC()
+	pocket.Progress = pocketProgress(pocket, pocket.Update_at)
+	return pocket, nil
+}
+
+// post moves amount from the wallet into the pocket, or back to the wallet
+// when amount is negative, as a single ledger entry. Wallets restricted from
+// pockets move nothing in either direction. initiatedBy is the member who
+// moved the money, empty for automatic saves.
+func (s *pocketService) post(ctx context.Context, pocket entities.Pocket, amount int64, memo string, initiatedBy string) error {
+	if s.restrictions != nil {
+		if err := s.restrictions.CheckDebit(ctx, pocket.WalletID, entities.CapabilityPocket); err != nil {
+			return err
+		}
+	}
+	transaction := entities.Transaction{
+		WalletID:    pocket.WalletID,
+		UserID:      pocket.UserID,
+		Type:        entities.TransactionToPocket,
+		Status:      entities.StatusCompleted,
+		Amount:      amount,
+		Currency:    pocket.Currency,
+		Memo:        memo,
+		InitiatedBy: initiatedBy,
+	}
+	if amount < 0 {
+		transaction.Type = entities.TransactionFromPocket
+		transaction.Amount = -amount
+	}
+	entry := entities.LedgerEntry{
+		Type: entities.EntryPocket,
+		Memo: memo,
+		Lines: []entities.LedgerLine{
+			{Account: entities.WalletAccount(pocket.WalletID), WalletID: pocket.WalletID, Currency: pocket.Currency, Amount: -amount},
+			{Account: entities.PocketAccount(pocket.ID), PocketID: pocket.ID, Currency: pocket.Currency, Amount: amount},
+		},
+		Created_at: time.Now().UTC(),
+	}
+	_, _, err := s.ledgerRepository.PostEntry(entry, []entities.Transaction{transaction}, ctx)
+	switch {
+	case errors.Is(err, repository_ledger.ErrInsufficientFunds):
+		return ErrInsufficientFunds
+	case errors.Is(err, repository_ledger.ErrPocketNotFound):
+		return ErrPocketNotFound
+	}
+	return err
+}
+
+// memberPocket loads the authenticated user and a pocket of a wallet whose
+// members they are, provided their role grants permission.
+func (s *pocketService) memberPocket(ctx context.Context, email string, walletID string, pocketID string, permission string) (entities.User, entities.Pocket, error) {
+	user, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, walletID, permission)
+	if err != nil {
+		return entities.User{}, entities.Pocket{}, err
+	}
+	pocket, err := s.pocketRepository.GetPocket(pocketID, ctx)
+	if errors.Is(err, repository_pocket.ErrPocketNotFound) {
+		return entities.User{}, entities.Pocket{}, ErrPocketNotFound
+	}
+	if err != nil {
+		return entities.User{}, entities.Pocket{}, err
+	}
+	if pocket.WalletID != wallet.ID {
+		return entities.User{}, entities.Pocket{}, ErrPocketNotFound
+	}
+	return user, pocket, nil
+}
+
+// nextAutoSave keeps the date of the next weekly save when the pocket already
+// had one and starts a week from now when weekly saving is turned on.
+func nextAutoSave(pocket entities.Pocket, current *time.Time, now time.Time) *time.Time {
+	if pocket.WeeklyAmount <= 0 {
+		return nil
+	}
+	if current != nil {
+		return current
+	}
+	next := now.Add(autoSaveInterval)
+	return &next
+}
+
+// roundUpAmount is what is missing for amount to be a multiple of unit.
+func roundUpAmount(amount int64, unit int64) int64 {
+	return (unit - amount%unit) % unit
+}
+
+func pocketProgress(pocket entities.Pocket, now time.Time) *entities.PocketProgress {
+	progress := &entities.PocketProgress{Remaining: pocket.TargetAmount - pocket.Balance}
+	if progress.Remaining <= 0 {
+		progress.Remaining = 0
+		progress.Percent = 100
+		progress.Reached = true
+	} else {
+		progress.Percent = int(pocket.Balance * 100 / pocket.TargetAmount)
+	}
+	if pocket.TargetDate == nil || !pocket.TargetDate.After(now) {
+		return progress
+	}
+	left := pocket.TargetDate.Sub(now)
+	progress.DaysLeft = int((left + 24*time.Hour - 1) / (24 * time.Hour))
+	weeks := int64((left + autoSaveInterval - 1) / autoSaveInterval)
+	progress.WeeklyNeeded = (progress.Remaining + weeks - 1) / weeks
+	return progress
+}
+
+func validatePocket(pocket entities.Pocket, now time.Time) error {
+	length := utf8.RuneCountInString(pocket.Name)
+	if length == 0 || length > maxPocketNameLength {
+		return ErrInvalidPocket
+	}
+	if !entities.ValidCurrency(pocket.Currency) {
+		return ErrInvalidCurrency
+	}
+	if pocket.TargetAmount <= 0 || pocket.RoundUpTo < 0 || pocket.WeeklyAmount < 0 {
+		return ErrInvalidPocket
+	}
+	if pocket.TargetDate != nil && !pocket.TargetDate.After(now) {
+		return ErrInvalidPocket
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_pocket "my_wallet/api/respository/pocket"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPocketProgress(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	inFourWeeks := now.AddDate(0, 0, 28)
+
+	testScenarios := []struct {
+		testName string
+		pocket   entities.Pocket
+		expected entities.PocketProgress
+	}{
+		{
+			testName: "without target date",
+			pocket:   entities.Pocket{Balance: 250000, TargetAmount: 1000000},
+			expected: entities.PocketProgress{Percent: 25, Remaining: 750000},
+		},
+		{
+			testName: "with target date",
+			pocket:   entities.Pocket{Balance: 250000, TargetAmount: 1000000, TargetDate: &inFourWeeks},
+			expected: entities.PocketProgress{Percent: 25, Remaining: 750000, DaysLeft: 28, WeeklyNeeded: 187500},
+		},
+		{
+			testName: "target reached",
+			pocket:   entities.Pocket{Balance: 1200000, TargetAmount: 1000000, TargetDate: &inFourWeeks},
+			expected: entities.PocketProgress{Percent: 100, DaysLeft: 28, Reached: true},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			result := pocketProgress(tt.pocket, now)
+
+			// Assert
+			assert.Equal(t, tt.expected, *result)
+		})
+	}
+}
+
+func TestMovePocketService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	wallet := entities.Wallet{ID: "w1", UserID: "u1", Currency: "COP"}
+	pocket := entities.Pocket{ID: "p1", WalletID: "w1", UserID: "u1", Name: "Trip", Currency: "COP", Balance: 5000, TargetAmount: 100000, Status: entities.PocketActive}
+
+	testScenarios := []struct {
+		testName        string
+		toPocket        bool
+		amount          int64
+		pocketID        string
+		ledgerError     error
+		expectedLines   []entities.LedgerLine
+		expectedType    string
+		expectedBalance int64
+		expectedError   error
+	}{
+		{
+			testName: "TestMoveToPocket",
+			toPocket: true,
+			amount:   2000,
+			pocketID: "p1",
+			expectedLines: []entities.LedgerLine{
+				{Account: "wallet:w1", WalletID: "w1", Currency: "COP", Amount: -2000},
+				{Account: "pocket:p1", PocketID: "p1", Currency: "COP", Amount: 2000},
+			},
+			expectedType:    entities.TransactionToPocket,
+			expectedBalance: 7000,
+		},
+		{
+			testName: "TestMoveFromPocket",
+			amount:   5000,
+			pocketID: "p1",
+			expectedLines: []entities.LedgerLine{
+				{Account: "wallet:w1", WalletID: "w1", Currency: "COP", Amount: 5000},
+				{Account: "pocket:p1", PocketID: "p1", Currency: "COP", Amount: -5000},
+			},
+			expectedType: entities.TransactionFromPocket,
+		},
+		{
+			testName:      "TestMoreThanThePocketHolds",
+			amount:        9000,
+			pocketID:      "p1",
+			ledgerError:   repository_ledger.ErrInsufficientFunds,
+			expectedError: ErrInsufficientFunds,
+		},
+		{
+			testName:      "TestInvalidAmount",
+			toPocket:      true,
+			amount:        -1,
+			pocketID:      "p1",
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			testName:      "TestPocketOfAnotherWallet",
+			toPocket:      true,
+			amount:        2000,
+			pocketID:      "p2",
+			expectedError: ErrPocketNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+			pockets := &pocketRepositoryMock{}
+			pockets.On("GetPocket", mock.Anything, "p1").Return(pocket, nil)
+			pockets.On("GetPocket", mock.Anything, "p2").Return(entities.Pocket{ID: "p2", WalletID: "w9", Status: entities.PocketActive}, nil)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+				return tt.expectedLines == nil || assert.ObjectsAreEqual(tt.expectedLines, e.Lines)
+			}), mock.MatchedBy(func(txs []entities.Transaction) bool {
+				return tt.expectedType == "" || txs[0].Type == tt.expectedType
+			})).Return(entities.LedgerEntry{}, tt.ledgerError)
+			service := NewPocketService(users, wallets, ledger, pockets, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			var result entities.Pocket
+			var err error
+			if tt.toPocket {
+				result, err = service.MoveToPocket(context.Background(), "alexer@gmail.com", "w1", tt.pocketID, tt.amount)
+			} else {
+				result, err = service.MoveFromPocket(context.Background(), "alexer@gmail.com", "w1", tt.pocketID, tt.amount)
+			}
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedBalance, result.Balance)
+		})
+	}
+}
+
+func TestRoundUpService(t *testing.T) {
+	testScenarios := []struct {
+		testName       string
+		transfer       entities.Transfer
+		pockets        []entities.Pocket
+		expectedAmount int64
+	}{
+		{
+			testName:       "rounds up to the next thousand pesos",
+			transfer:       entities.Transfer{SenderWalletID: "w1", Amount: 1234500, Currency: "COP"},
+			pockets:        []entities.Pocket{{ID: "p1", WalletID: "w1", Currency: "COP", RoundUpTo: 100000}},
+			expectedAmount: 65500,
+		},
+		{
+			testName: "fee is rounded up too",
+			transfer: entities.Transfer{SenderWalletID: "w1", Amount: 1234500, Fee: 65000, Currency: "COP"},
+			pockets: []entities.Pocket{
+				{ID: "p0", WalletID: "w1", Currency: "COP"},
+				{ID: "p1", WalletID: "w1", Currency: "COP", RoundUpTo: 100000},
+			},
+			expectedAmount: 500,
+		},
+		{
+			testName: "exact amount saves nothing",
+			transfer: entities.Transfer{SenderWalletID: "w1", Amount: 1200000, Currency: "COP"},
+			pockets:  []entities.Pocket{{ID: "p1", WalletID: "w1", Currency: "COP", RoundUpTo: 100000}},
+		},
+		{
+			testName: "other currency saves nothing",
+			transfer: entities.Transfer{SenderWalletID: "w1", Amount: 1234, Currency: "USD"},
+			pockets:  []entities.Pocket{{ID: "p1", WalletID: "w1", Currency: "COP", RoundUpTo: 100000}},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			pockets := &pocketRepositoryMock{}
+			pockets.On("ListPockets", mock.Anything, "w1").Return(tt.pockets, nil)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{}, nil)
+			service := NewPocketService(&userServiceMock{}, &walletRepositoryMock{}, ledger, pockets, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			service.RoundUp(context.Background(), tt.transfer)
+
+			// Assert
+			if tt.expectedAmount == 0 {
+				ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			ledger.AssertCalled(t, "PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+				return e.Lines[1].PocketID == "p1" && e.Lines[1].Amount == tt.expectedAmount
+			}), mock.Anything)
+		})
+	}
+}
+
+func TestRunAutoSavesService(t *testing.T) {
+	now := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	due := now.Add(-time.Hour)
+	missed := now.AddDate(0, 0, -15)
+
+	testScenarios := []struct {
+		testName       string
+		pocket         entities.Pocket
+		claimError     error
+		expectedNext   time.Time
+		expectedAmount int64
+		expectedSaves  int
+	}{
+		{
+			testName:       "saves the weekly amount",
+			pocket:         entities.Pocket{ID: "p1", WalletID: "w1", Currency: "COP", WeeklyAmount: 50000, TargetAmount: 1000000, NextAutoSaveAt: &due},
+			expectedNext:   due.Add(autoSaveInterval),
+			expectedAmount: 50000,
+			expectedSaves:  1,
+		},
+		{
+			testName:       "saves only what is left to reach the target",
+			pocket:         entities.Pocket{ID: "p1", WalletID: "w1", Currency: "COP", Balance: 980000, WeeklyAmount: 50000, TargetAmount: 1000000, NextAutoSaveAt: &due},
+			expectedNext:   due.Add(autoSaveInterval),
+			expectedAmount: 20000,
+			expectedSaves:  1,
+		},
+		{
+			testName:       "missed weeks are not saved twice",
+			pocket:         entities.Pocket{ID: "p1", WalletID: "w1", Currency: "COP", WeeklyAmount: 50000, TargetAmount: 1000000, NextAutoSaveAt: &missed},
+			expectedNext:   missed.Add(3 * autoSaveInterval),
+			expectedAmount: 50000,
+			expectedSaves:  1,
+		},
+		{
+			testName:      "claimed by another replica",
+			pocket:        entities.Pocket{ID: "p1", WalletID: "w1", Currency: "COP", WeeklyAmount: 50000, TargetAmount: 1000000, NextAutoSaveAt: &due},
+			claimError:    repository_pocket.ErrPocketChanged,
+			expectedNext:  due.Add(autoSaveInterval),
+			expectedSaves: 0,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			pockets := &pocketRepositoryMock{}
+			pockets.On("DueAutoSaves", mock.Anything, now, int64(dueAutoSavesBatch)).Return([]entities.Pocket{tt.pocket}, nil)
+			pockets.On("ClaimAutoSave", mock.Anything, "p1", *tt.pocket.NextAutoSaveAt, tt.expectedNext).Return(tt.claimError)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+				return e.Lines[1].Amount == tt.expectedAmount
+			}), mock.Anything).Return(entities.LedgerEntry{}, nil)
+			service := NewPocketService(&userServiceMock{}, &walletRepositoryMock{}, ledger, pockets, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			saves, err := service.RunAutoSaves(context.Background(), now)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSaves, saves)
+			pockets.AssertExpectations(t)
+			if tt.expectedSaves == 0 {
+				ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
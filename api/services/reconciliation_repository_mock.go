@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type reconciliationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *reconciliationRepositoryMock) CreateRun(run entities.ReconciliationRun, ctx context.Context) (entities.ReconciliationRun, error) {
+	r := m.Called(ctx, run)
+	if created, ok := r.Get(0).(func(entities.ReconciliationRun) entities.ReconciliationRun); ok {
+		return created(run), r.Error(1)
+	}
+	return r.Get(0).(entities.ReconciliationRun), r.Error(1)
+}
+
+func (m *reconciliationRepositoryMock) CreateItems(items []entities.ReconciliationItem, ctx context.Context) error {
+	r := m.Called(ctx, items)
+	return r.Error(0)
+}
+
+func (m *reconciliationRepositoryMock) GetRun(id string, ctx context.Context) (entities.ReconciliationRun, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.ReconciliationRun), r.Error(1)
+}
+
+func (m *reconciliationRepositoryMock) ListRuns(limit int64, ctx context.Context) ([]entities.ReconciliationRun, error) {
+	r := m.Called(ctx, limit)
+	return r.Get(0).([]entities.ReconciliationRun), r.Error(1)
+}
+
+func (m *reconciliationRepositoryMock) ListItems(runID string, result string, ctx context.Context) ([]entities.ReconciliationItem, error) {
+	r := m.Called(ctx, runID, result)
+	return r.Get(0).([]entities.ReconciliationItem), r.Error(1)
+}
+
+func (m *reconciliationRepositoryMock) ProviderTransactions(types []string, from time.Time, to time.Time, ctx context.Context) ([]entities.Transaction, error) {
+	r := m.Called(ctx, types, from, to)
+	return r.Get(0).([]entities.Transaction), r.Error(1)
+}
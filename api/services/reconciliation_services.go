@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_reconciliation "my_wallet/api/respository/reconciliation"
+	repository_user "my_wallet/api/respository/user"
+	"my_wallet/api/utils/settlement"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxReconciliationDays = 7
+	maxReconciliationRuns = 50
+)
+
+type ReconciliationService interface {
+	Reconcile(ctx context.Context, run entities.ReconciliationRun, records []settlement.Record) (entities.ReconciliationRun, error)
+	ListReconciliations(ctx context.Context, email string) ([]entities.ReconciliationRun, error)
+	GetReconciliation(ctx context.Context, email string, runID string, result string) (entities.ReconciliationReport, error)
+}
+
+type reconciliationService struct {
+	ctx                      context.Context
+	userRepository           repository_user.UserRepository
+	reconciliationRepository repository_reconciliation.ReconciliationRepository
+	logger                   logrus.FieldLogger
+}
+
+func NewReconciliationService(userRepo repository_user.UserRepository, reconciliationRepo repository_reconciliation.ReconciliationRepository, logger logrus.FieldLogger, ctx context.Context) *reconciliationService {
+	return &reconciliationService{
+		ctx:                      ctx,
+		userRepository:           userRepo,
+		reconciliationRepository: reconciliationRepo,
+		logger:                   logger,
+	}
+}
+
+// Reconcile matches the settlement file of a provider for run.Day against
+// the deposits and withdrawals of the ledger and stores the run with an
+// item per movement and per unsettled transaction. It is the end-of-day job
+// and has no user: whoever runs it is recorded in run.RunBy.
+//
+// A movement matches the transaction its reference names, the entry or the
+// transaction ID, when type and currency agree and the dates are within the
+// tolerance; its amount decides between matched and amount_mismatch.
+// Movements without a usable reference match the closest transaction of the
+// same type and currency within both tolerances. Transactions of the day
+// nobody settled are unmatched_internal; those of the days around it are
+// left to the runs of their own day.
+func (s *reconciliationService) Reconcile(ctx context.Context, run entities.ReconciliationRun, records []settlement.Record) (entities.ReconciliationRun, error) {
+	run.Provider = strings.TrimSpace(run.Provider)
+	if run.Provider == "" || run.Day.IsZero() || run.Tolerance.Amount < 0 || run.Tolerance.Days < 0 || run.Tolerance.Days > maxReconciliationDays {
+		s.logger.Errorln("Layer: reconciliation_services", "Method: Reconcile", "Error:", ErrInvalidReconciliation)
+		return entities.ReconciliationRun{}, ErrInvalidReconciliation
+	}
+	run.Day = utcDay(run.Day)
+	from := run.Day.AddDate(0, 0, -run.Tolerance.Days)
+	to := run.Day.AddDate(0, 0, 1+run.Tolerance.Days)
+	transactions, err := s.reconciliationRepository.ProviderTransactions(entities.ReconciledTypes, from, to, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: reconciliation_services", "Method: Reconcile", "Error:", err)
+		return entities.ReconciliationRun{}, err
+	}
+
+	items := reconcile(run, records, transactions)
+	run.Summary = entities.ReconciliationSummary{Records: len(records)}
+	for _, item := range items {
+		switch item.Result {
+		case entities.ReconciliationMatched:
+			run.Summary.Matched++
+		case entities.ReconciliationAmountMismatch:
+			run.Summary.AmountMismatch++
+		case entities.ReconciliationUnmatchedInternal:
+			run.Summary.UnmatchedInternal++
+		case entities.ReconciliationUnmatchedExternal:
+			run.Summary.UnmatchedExternal++
+		}
+	}
+	run.ID = ""
+	run.Created_at = time.Now().UTC()
+	run, err = s.reconciliationRepository.CreateRun(run, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: reconciliation_services", "Method: Reconcile", "Error:", err)
+		return entities.ReconciliationRun{}, err
+	}
+	for i := range items {
+		items[i].RunID = run.ID
+	}
+	if err := s.reconciliationRepository.CreateItems(items, ctx); err != nil {
+		s.logger.Errorln("Layer: reconciliation_services", "Method: Reconcile", "Error:", err)
+		return entities.ReconciliationRun{}, err
+	}
+	s.logger.Infoln("Layer: reconciliation_services", "Method: Reconcile", "Run:", run.ID, "Discrepancies:", run.Summary.Discrepancies())
+	return run, nil
+}
+
+// ListReconciliations returns the latest reconciliation runs with their
+// summaries.
+func (s *reconciliationService) ListReconciliations(ctx context.Context, email string) ([]entities.ReconciliationRun, error) {
+	if _, err := requireStaff(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: reconciliation_services", "Method: ListReconciliations", "Error:", err)
+		return nil, err
+	}
+	runs, err := s.reconciliationRepository.ListRuns(maxReconciliationRuns, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: reconciliation_services", "Method: ListReconciliations", "Error:", err)
+		return nil, err
+	}
+	return runs, nil
+}
+
+// GetReconciliation returns a run with its items, only those with the given
+// result when it is not empty.
+func (s *reconciliationService) GetReconciliation(ctx context.Context, email string, runID string, result string) (entities.ReconciliationReport, error) {
+	if _, err := requireStaff(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: reconciliation_services", "Method: GetReconciliation", "Error:", err)
+		return entities.ReconciliationReport{}, err
+	}
+	if result != "" && !entities.ReconciliationResults[result] {
+		s.logger.Errorln("Layer: reconciliation_services", "Method: GetReconciliation", "Error:", ErrInvalidReconciliationResult)
+		return entities.ReconciliationReport{}, ErrInvalidReconciliationResult
+	}
+	run, err := s.reconciliationRepository.GetRun(runID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: reconciliation_services", "Method: GetReconciliation", "Error:", err)
+		return entities.ReconciliationReport{}, reconciliationError(err)
+	}
+	items, err := s.reconciliationRepository.ListItems(run.ID, result, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: reconciliation_services", "Method: GetReconciliation", "Error:", err)
+		return entities.ReconciliationReport{}, err
+	}
+	return entities.ReconciliationReport{Run: run, Items: items}, nil
+}
+
+func reconciliationError(err error) error {
+	if errors.Is(err, repository_reconciliation.ErrReconciliationNotFound) {
+		return ErrReconciliationNotFound
+	}
+	return err
+}
+
+// reconcile pairs the settled movements with the transactions, first by
+// reference and then by amount and date, and returns an item per movement
+// followed by one per unsettled transaction of the day.
+func reconcile(run entities.ReconciliationRun, records []settlement.Record, transactions []entities.Transaction) []entities.ReconciliationItem {
+	tolerance := run.Tolerance
+	used := make([]bool, len(transactions))
+	items := make([]entities.ReconciliationItem, len(records))
+	matched := make([]bool, len(records))
+	comparable := func(record settlement.Record, tx entities.Transaction) bool {
+		return record.Type == tx.Type && record.Currency == tx.Currency && dayDistance(record.SettledAt, tx.Created_at) <= tolerance.Days
+	}
+
+	for i, record := range records {
+		if record.Reference == "" {
+			continue
+		}
+		for j, tx := range transactions {
+			if used[j] || (record.Reference != tx.EntryID && record.Reference != tx.ID) || !comparable(record, tx) {
+				continue
+			}
+			result := entities.ReconciliationMatched
+			if abs(record.Amount-tx.Amount) > tolerance.Amount {
+				result = entities.ReconciliationAmountMismatch
+			}
+			items[i] = reconciliationItem(result, record, tx)
+			used[j], matched[i] = true, true
+			break
+		}
+	}
+
+	for i, record := range records {
+		if matched[i] {
+			continue
+		}
+		best := -1
+		for j, tx := range transactions {
+			if used[j] || !comparable(record, tx) || abs(record.Amount-tx.Amount) > tolerance.Amount {
+				continue
+			}
+			if best < 0 || closer(record, tx, transactions[best]) {
+				best = j
+			}
+		}
+		if best < 0 {
+			items[i] = entities.ReconciliationItem{
+				Result:         entities.ReconciliationUnmatchedExternal,
+				Reference:      record.Reference,
+				Type:           record.Type,
+				Currency:       record.Currency,
+				ExternalAmount: record.Amount,
+				Difference:     record.Amount,
+				External_at:    &record.SettledAt,
+				Line:           record.Line,
+			}
+			continue
+		}
+		items[i] = reconciliationItem(entities.ReconciliationMatched, record, transactions[best])
+		used[best] = true
+	}
+
+	end := run.Day.AddDate(0, 0, 1)
+	for j, tx := range transactions {
+		if used[j] || tx.Created_at.Before(run.Day) || !tx.Created_at.Before(end) {
+			continue
+		}
+		internalAt := tx.Created_at
+		items = append(items, entities.ReconciliationItem{
+			Result:         entities.ReconciliationUnmatchedInternal,
+			Reference:      tx.EntryID,
+			TransactionID:  tx.ID,
+			WalletID:       tx.WalletID,
+			Type:           tx.Type,
+			Currency:       tx.Currency,
+			InternalAmount: tx.Amount,
+			Difference:     -tx.Amount,
+			Internal_at:    &internalAt,
+		})
+	}
+	return items
+}
+
+func reconciliationItem(result string, record settlement.Record, tx entities.Transaction) entities.ReconciliationItem {
+	internalAt := tx.Created_at
+	return entities.ReconciliationItem{
+		Result:         result,
+		Reference:      record.Reference,
+		TransactionID:  tx.ID,
+		WalletID:       tx.WalletID,
+		Type:           tx.Type,
+		Currency:       tx.Currency,
+		InternalAmount: tx.Amount,
+		ExternalAmount: record.Amount,
+		Difference:     record.Amount - tx.Amount,
+		Internal_at:    &internalAt,
+		External_at:    &record.SettledAt,
+		Line:           record.Line,
+	}
+}
+
+// closer reports whether a is a better match for the movement than b: the
+// nearer amount first and then the nearer date.
+func closer(record settlement.Record, a entities.Transaction, b entities.Transaction) bool {
+	da, db := abs(record.Amount-a.Amount), abs(record.Amount-b.Amount)
+	if da != db {
+		return da < db
+	}
+	return abs(int64(dayDistance(record.SettledAt, a.Created_at))) < abs(int64(dayDistance(record.SettledAt, b.Created_at)))
+}
+
+// dayDistance returns how many UTC calendar days apart two times are.
+func dayDistance(a time.Time, b time.Time) int {
+	days := int(utcDay(a).Sub(utcDay(b)).Hours() / 24)
+	if days < 0 {
+		return -days
+	}
+	return days
+}
+
+// utcDay returns the start of the UTC day of t.
+func utcDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
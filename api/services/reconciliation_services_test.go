@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_reconciliation "my_wallet/api/respository/reconciliation"
+	"my_wallet/api/utils/settlement"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReconcileService(t *testing.T) {
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	at := func(d int, hour int) time.Time {
+		return day.AddDate(0, 0, d).Add(time.Duration(hour) * time.Hour)
+	}
+	transactions := []entities.Transaction{
+		{ID: "t1", EntryID: "e1", WalletID: "w1", Type: entities.TransactionWithdrawal, Amount: 50000, Currency: "COP", Created_at: at(0, 9)},
+		{ID: "t2", EntryID: "e2", WalletID: "w2", Type: entities.TransactionWithdrawal, Amount: 20000, Currency: "COP", Created_at: at(0, 10)},
+		{ID: "t3", EntryID: "e3", WalletID: "w3", Type: entities.TransactionDeposit, Amount: 10000, Currency: "COP", Created_at: at(0, 11)},
+		{ID: "t4", EntryID: "e4", WalletID: "w4", Type: entities.TransactionWithdrawal, Amount: 7000, Currency: "COP", Created_at: at(0, 12)},
+		{ID: "t5", EntryID: "e5", WalletID: "w5", Type: entities.TransactionWithdrawal, Amount: 3000, Currency: "COP", Created_at: at(-1, 23)},
+		{ID: "t6", EntryID: "e6", WalletID: "w6", Type: entities.TransactionWithdrawal, Amount: 9000, Currency: "COP", Created_at: at(1, 8)},
+	}
+
+	testScenarios := []struct {
+		testName        string
+		run             entities.ReconciliationRun
+		records         []settlement.Record
+		expectedSummary entities.ReconciliationSummary
+		expectedResults map[string]string
+		expectedError   error
+	}{
+		{
+			testName: "TestReconcile",
+			run:      entities.ReconciliationRun{Provider: "bank", Day: at(0, 15), Tolerance: entities.ReconciliationTolerance{Amount: 100, Days: 1}},
+			records: []settlement.Record{
+				{Line: 2, Reference: "e1", Type: entities.TransactionWithdrawal, Amount: 50050, Currency: "COP", SettledAt: at(1, 0)},
+				{Line: 3, Reference: "t2", Type: entities.TransactionWithdrawal, Amount: 25000, Currency: "COP", SettledAt: at(0, 0)},
+				{Line: 4, Type: entities.TransactionDeposit, Amount: 10000, Currency: "COP", SettledAt: at(0, 0)},
+				{Line: 5, Reference: "x9", Type: entities.TransactionDeposit, Amount: 4000, Currency: "COP", SettledAt: at(0, 0)},
+				{Line: 6, Reference: "e5", Type: entities.TransactionWithdrawal, Amount: 3000, Currency: "COP", SettledAt: at(0, 0)},
+			},
+			expectedSummary: entities.ReconciliationSummary{Records: 5, Matched: 3, AmountMismatch: 1, UnmatchedInternal: 1, UnmatchedExternal: 1},
+			expectedResults: map[string]string{
+				"t1": entities.ReconciliationMatched,
+				"t2": entities.ReconciliationAmountMismatch,
+				"t3": entities.ReconciliationMatched,
+				"x9": entities.ReconciliationUnmatchedExternal,
+				"t5": entities.ReconciliationMatched,
+				"t4": entities.ReconciliationUnmatchedInternal,
+			},
+		},
+		{
+			testName: "TestReconcileOutsideDateTolerance",
+			run:      entities.ReconciliationRun{Provider: "bank", Day: day},
+			records: []settlement.Record{
+				{Line: 2, Reference: "e1", Type: entities.TransactionWithdrawal, Amount: 50000, Currency: "COP", SettledAt: at(1, 0)},
+			},
+			expectedSummary: entities.ReconciliationSummary{Records: 1, UnmatchedInternal: 4, UnmatchedExternal: 1},
+		},
+		{
+			testName:      "TestReconcileWithoutProvider",
+			run:           entities.ReconciliationRun{Day: day},
+			expectedError: ErrInvalidReconciliation,
+		},
+		{
+			testName:      "TestReconcileDateToleranceTooLarge",
+			run:           entities.ReconciliationRun{Provider: "bank", Day: day, Tolerance: entities.ReconciliationTolerance{Days: 30}},
+			expectedError: ErrInvalidReconciliation,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			from := day.AddDate(0, 0, -tt.run.Tolerance.Days)
+			to := day.AddDate(0, 0, 1+tt.run.Tolerance.Days)
+			var window []entities.Transaction
+			for _, tx := range transactions {
+				if !tx.Created_at.Before(from) && tx.Created_at.Before(to) {
+					window = append(window, tx)
+				}
+			}
+			repo := &reconciliationRepositoryMock{}
+			repo.On("ProviderTransactions", mock.Anything, entities.ReconciledTypes, from, to).Return(window, nil)
+			repo.On("CreateRun", mock.Anything, mock.Anything).Return(func(run entities.ReconciliationRun) entities.ReconciliationRun {
+				run.ID = "run1"
+				return run
+			}, nil)
+			repo.On("CreateItems", mock.Anything, mock.Anything).Return(nil)
+			service := NewReconciliationService(&userServiceMock{}, repo, logrus.StandardLogger(), context.Background())
+
+			// Act
+			run, err := service.Reconcile(context.Background(), tt.run, tt.records)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError != nil {
+				repo.AssertNotCalled(t, "CreateRun", mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, "run1", run.ID)
+			assert.Equal(t, day, run.Day)
+			assert.Equal(t, tt.expectedSummary, run.Summary)
+			repo.AssertCalled(t, "CreateItems", mock.Anything, mock.MatchedBy(func(items []entities.ReconciliationItem) bool {
+				for _, item := range items {
+					key := item.TransactionID
+					if key == "" {
+						key = item.Reference
+					}
+					if item.RunID != "run1" || (tt.expectedResults != nil && tt.expectedResults[key] != item.Result) {
+						return false
+					}
+				}
+				return len(items) == tt.expectedSummary.Matched+tt.expectedSummary.AmountMismatch+tt.expectedSummary.UnmatchedInternal+tt.expectedSummary.UnmatchedExternal
+			}))
+		})
+	}
+}
+
+func TestGetReconciliationService(t *testing.T) {
+	testScenarios := []struct {
+		testName      string
+		user          entities.User
+		runID         string
+		result        string
+		runError      error
+		expectedError error
+	}{
+		{
+			testName: "TestGetReconciliation",
+			user:     entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			runID:    "run1",
+			result:   entities.ReconciliationAmountMismatch,
+		},
+		{
+			testName:      "TestGetReconciliationWithoutStaff",
+			user:          entities.User{ID: "u1", Email: "support@gmail.com"},
+			runID:         "run1",
+			expectedError: ErrStaffRequired,
+		},
+		{
+			testName:      "TestGetReconciliationUnknownResult",
+			user:          entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			runID:         "run1",
+			result:        "lost",
+			expectedError: ErrInvalidReconciliationResult,
+		},
+		{
+			testName:      "TestGetReconciliationNotFound",
+			user:          entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			runID:         "run2",
+			runError:      repository_reconciliation.ErrReconciliationNotFound,
+			expectedError: ErrReconciliationNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "support@gmail.com").Return(tt.user, nil)
+			repo := &reconciliationRepositoryMock{}
+			repo.On("GetRun", mock.Anything, tt.runID).Return(entities.ReconciliationRun{ID: tt.runID}, tt.runError)
+			repo.On("ListItems", mock.Anything, tt.runID, tt.result).Return([]entities.ReconciliationItem{{RunID: tt.runID, Result: entities.ReconciliationAmountMismatch}}, nil)
+			service := NewReconciliationService(users, repo, logrus.StandardLogger(), context.Background())
+
+			// Act
+			report, err := service.GetReconciliation(context.Background(), "support@gmail.com", tt.runID, tt.result)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.runID, report.Run.ID)
+				assert.Len(t, report.Items, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type restrictionRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *restrictionRepositoryMock) CreateRestriction(restriction entities.WalletRestriction, ctx context.Context) (entities.WalletRestriction, error) {
+	r := m.Called(ctx, restriction)
+	if created, ok := r.Get(0).(func(entities.WalletRestriction) entities.WalletRestriction); ok {
+		return created(restriction), r.Error(1)
+	}
+	return r.Get(0).(entities.WalletRestriction), r.Error(1)
+}
+
+func (m *restrictionRepositoryMock) GetRestriction(id string, ctx context.Context) (entities.WalletRestriction, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.WalletRestriction), r.Error(1)
+}
+
+func (m *restrictionRepositoryMock) ListRestrictions(walletID string, ctx context.Context) ([]entities.WalletRestriction, error) {
+	r := m.Called(ctx, walletID)
+	return r.Get(0).([]entities.WalletRestriction), r.Error(1)
+}
+
+func (m *restrictionRepositoryMock) ActiveRestrictions(walletID string, now time.Time, ctx context.Context) ([]entities.WalletRestriction, error) {
+	r := m.Called(ctx, walletID)
+	return r.Get(0).([]entities.WalletRestriction), r.Error(1)
+}
+
+func (m *restrictionRepositoryMock) LiftRestriction(id string, liftedBy string, note string, at time.Time, ctx context.Context) (entities.WalletRestriction, error) {
+	r := m.Called(ctx, id, liftedBy, note)
+	return r.Get(0).(entities.WalletRestriction), r.Error(1)
+}
+
+func (m *restrictionRepositoryMock) CreateEvent(event entities.RestrictionEvent, ctx context.Context) (entities.RestrictionEvent, error) {
+	r := m.Called(ctx, event)
+	return r.Get(0).(entities.RestrictionEvent), r.Error(1)
+}
+
+func (m *restrictionRepositoryMock) ListEvents(walletID string, limit int64, ctx context.Context) ([]entities.RestrictionEvent, error) {
+	r := m.Called(ctx, walletID, limit)
+	return r.Get(0).([]entities.RestrictionEvent), r.Error(1)
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_restriction "my_wallet/api/respository/restriction"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxRestrictionDuration   = 365 * 24 * time.Hour
+	maxRestrictionNoteLength = 500
+	maxRestrictionEvents     = 50
+)
+
+// Restrictions enforces the restrictions support staff put on wallets. The
+// services that move money check the wallets they debit and credit before
+// posting; a restriction that cannot be read fails the movement.
+type Restrictions interface {
+	// CheckDebit fails if money cannot leave the wallet for the capability.
+	CheckDebit(ctx context.Context, walletID string, capability string) error
+	// CheckCredit fails if the wallet cannot receive money.
+	CheckCredit(ctx context.Context, walletID string) error
+}
+
+type RestrictionService interface {
+	Restrictions
+	PlaceRestriction(ctx context.Context, email string, walletID string, restriction entities.WalletRestriction) (entities.WalletRestriction, error)
+	ListRestrictions(ctx context.Context, email string, walletID string) (entities.WalletRestrictions, error)
+	LiftRestriction(ctx context.Context, email string, walletID string, restrictionID string, note string) (entities.WalletRestriction, error)
+}
+
+type restrictionService struct {
+	ctx                   context.Context
+	userRepository        repository_user.UserRepository
+	walletRepository      repository_wallet.WalletRepository
+	restrictionRepository repository_restriction.RestrictionRepository
+	notifier              Notifier
+	logger                logrus.FieldLogger
+}
+
+func NewRestrictionService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, restrictionRepo repository_restriction.RestrictionRepository, notifier Notifier, logger logrus.FieldLogger, ctx context.Context) *restrictionService {
+	return &restrictionService{
+		ctx:                   ctx,
+		userRepository:        userRepo,
+		walletRepository:      walletRepo,
+		restrictionRepository: restrictionRepo,
+		notifier:              notifier,
+		logger:                logger,
+	}
+}
+
+// PlaceRestriction freezes, blocks or restricts capabilities of a wallet
+// until the restriction expires, at most a year later, or support staff lift
+// it. Every restriction carries a reason code, is recorded in the audit
+// trail of the wallet and is notified to its owner.
+func (s *restrictionService) PlaceRestriction(ctx context.Context, email string, walletID string, restriction entities.WalletRestriction) (entities.WalletRestriction, error) {
+	staff, err := requireStaff(ctx, s.userRepository, email)
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: PlaceRestriction", "Error:", err)
+		return entities.WalletRestriction{}, err
+	}
+	now := time.Now().UTC()
+	if err := validateRestriction(restriction, now); err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: PlaceRestriction", "Error:", err)
+		return entities.WalletRestriction{}, err
+	}
+	wallet, err := s.walletRepository.GetWallet(walletID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: PlaceRestriction", "Error:", err)
+		return entities.WalletRestriction{}, err
+	}
+
+	if restriction.Kind != entities.RestrictionCapabilities {
+		restriction.Capabilities = nil
+	}
+	restriction, err = s.restrictionRepository.CreateRestriction(entities.WalletRestriction{
+		WalletID:     wallet.ID,
+		UserID:       wallet.UserID,
+		Kind:         restriction.Kind,
+		Capabilities: restriction.Capabilities,
+		Reason:       restriction.Reason,
+		Note:         strings.TrimSpace(restriction.Note),
+		CreatedBy:    staff.Email,
+		Expires_at:   restriction.Expires_at.UTC(),
+		Created_at:   now,
+	}, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: PlaceRestriction", "Error:", err)
+		return entities.WalletRestriction{}, err
+	}
+	restriction.Status = restriction.StatusAt(now)
+	s.audit(ctx, restriction, entities.RestrictionActionPlaced, staff.Email, restriction.Note)
+	s.notify(ctx, restriction, entities.NotificationWalletRestricted, "Wallet restricted",
+		"Support restricted your wallet until "+restriction.Expires_at.Format(time.RFC3339)+". Contact support for details")
+	s.logger.Infoln("Layer: restriction_services", "Method: PlaceRestriction", "Restriction:", restriction.ID)
+	return restriction, nil
+}
+
+// ListRestrictions returns every restriction of a wallet, with its current
+// status, and the latest entries of its audit trail.
+func (s *restrictionService) ListRestrictions(ctx context.Context, email string, walletID string) (entities.WalletRestrictions, error) {
+	if _, err := requireStaff(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: ListRestrictions", "Error:", err)
+		return entities.WalletRestrictions{}, err
+	}
+	wallet, err := s.walletRepository.GetWallet(walletID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: ListRestrictions", "Error:", err)
+		return entities.WalletRestrictions{}, err
+	}
+	restrictions, err := s.restrictionRepository.ListRestrictions(wallet.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: ListRestrictions", "Error:", err)
+		return entities.WalletRestrictions{}, err
+	}
+	now := time.Now()
+	for i := range restrictions {
+		restrictions[i].Status = restrictions[i].StatusAt(now)
+	}
+	events, err := s.restrictionRepository.ListEvents(wallet.ID, maxRestrictionEvents, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: ListRestrictions", "Error:", err)
+		return entities.WalletRestrictions{}, err
+	}
+	return entities.WalletRestrictions{WalletID: wallet.ID, Restrictions: restrictions, Events: events}, nil
+}
+
+// LiftRestriction ends a restriction of a wallet before it expires. Expired
+// restrictions no longer apply, but can still be lifted for the record.
+func (s *restrictionService) LiftRestriction(ctx context.Context, email string, walletID string, restrictionID string, note string) (entities.WalletRestriction, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxRestrictionNoteLength {
+		s.logger.Errorln("Layer: restriction_services", "Method: LiftRestriction", "Error:", ErrInvalidRestriction)
+		return entities.WalletRestriction{}, ErrInvalidRestriction
+	}
+	staff, err := requireStaff(ctx, s.userRepository, email)
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: LiftRestriction", "Error:", err)
+		return entities.WalletRestriction{}, err
+	}
+	restriction, err := s.restrictionRepository.GetRestriction(restrictionID, ctx)
+	if err == nil && restriction.WalletID != walletID {
+		err = ErrRestrictionNotFound
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: LiftRestriction", "Error:", err)
+		return entities.WalletRestriction{}, restrictionError(err)
+	}
+	restriction, err = s.restrictionRepository.LiftRestriction(restriction.ID, staff.Email, note, time.Now().UTC(), ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: LiftRestriction", "Error:", err)
+		return entities.WalletRestriction{}, restrictionError(err)
+	}
+	restriction.Status = entities.RestrictionLifted
+	s.audit(ctx, restriction, entities.RestrictionActionLifted, staff.Email, note)
+	s.notify(ctx, restriction, entities.NotificationRestrictionLifted, "Wallet restriction lifted",
+		"Support lifted a restriction on your wallet")
+	s.logger.Infoln("Layer: restriction_services", "Method: LiftRestriction", "Restriction:", restriction.ID)
+	return restriction, nil
+}
+
+// CheckDebit fails with ErrWalletBlocked or ErrWalletFrozen while the wallet
+// is blocked or frozen, and with ErrCapabilityRestricted while the
+// capability is restricted.
+func (s *restrictionService) CheckDebit(ctx context.Context, walletID string, capability string) error {
+	return s.check(ctx, walletID, capability)
+}
+
+// CheckCredit fails with ErrWalletBlocked while the wallet is blocked.
+func (s *restrictionService) CheckCredit(ctx context.Context, walletID string) error {
+	return s.check(ctx, walletID, "")
+}
+
+// check returns the error of the most severe active restriction of the
+// wallet that stops the capability: a block, then a freeze, then a
+// capability restriction.
+func (s *restrictionService) check(ctx context.Context, walletID string, capability string) error {
+	restrictions, err := s.restrictionRepository.ActiveRestrictions(walletID, time.Now().UTC(), ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: check", "Error:", err)
+		return err
+	}
+	var result error
+	for _, restriction := range restrictions {
+		if !restriction.Restricts(capability) {
+			continue
+		}
+		switch restriction.Kind {
+		case entities.RestrictionBlock:
+			return ErrWalletBlocked
+		case entities.RestrictionFreeze:
+			result = ErrWalletFrozen
+		default:
+			if result == nil {
+				result = ErrCapabilityRestricted
+			}
+		}
+	}
+	return result
+}
+
+// audit appends an entry to the restriction audit trail of the wallet.
+// Failures are logged; the change they describe has already happened.
+func (s *restrictionService) audit(ctx context.Context, restriction entities.WalletRestriction, action string, actor string, detail string) {
+	_, err := s.restrictionRepository.CreateEvent(entities.RestrictionEvent{
+		WalletID:      restriction.WalletID,
+		RestrictionID: restriction.ID,
+		Action:        action,
+		Actor:         actor,
+		Kind:          restriction.Kind,
+		Reason:        restriction.Reason,
+		Detail:        detail,
+		Created_at:    time.Now().UTC(),
+	}, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: audit", "Error:", err)
+	}
+}
+
+// notify tells the owner of the wallet about a restriction. The reason code
+// and the notes of the staff are kept out of it. Failures are logged.
+func (s *restrictionService) notify(ctx context.Context, restriction entities.WalletRestriction, notificationType string, title string, message string) {
+	if s.notifier == nil {
+		return
+	}
+	err := s.notifier.Notify(ctx, entities.Notification{
+		UserID:  restriction.UserID,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		Data: map[string]string{
+			"wallet_id":      restriction.WalletID,
+			"restriction_id": restriction.ID,
+			"kind":           restriction.Kind,
+		},
+	})
+	if err != nil {
+		s.logger.Errorln("Layer: restriction_services", "Method: notify", "Error:", err)
+	}
+}
+
+// restrictionError maps the errors of the restriction repository to the ones
+// of the service.
+func restrictionError(err error) error {
+	switch {
+	case errors.Is(err, repository_restriction.ErrRestrictionNotFound):
+		return ErrRestrictionNotFound
+	case errors.Is(err, repository_restriction.ErrRestrictionLifted):
+		return ErrRestrictionLifted
+	}
+	return err
+}
+
+// validateRestriction checks the kind, the reason code and the expiry of a
+// new restriction, and that capability restrictions name restrictable
+// capabilities.
+func validateRestriction(restriction entities.WalletRestriction, now time.Time) error {
+	switch restriction.Kind {
+	case entities.RestrictionFreeze, entities.RestrictionBlock:
+	case entities.RestrictionCapabilities:
+		if len(restriction.Capabilities) == 0 {
+			return ErrInvalidRestriction
+		}
+		for _, capability := range restriction.Capabilities {
+			if !entities.RestrictableCapabilities[capability] {
+				return ErrInvalidRestriction
+			}
+		}
+	default:
+		return ErrInvalidRestriction
+	}
+	if !entities.RestrictionReasons[restriction.Reason] {
+		return ErrInvalidRestriction
+	}
+	if !restriction.Expires_at.After(now) || restriction.Expires_at.Sub(now) > maxRestrictionDuration {
+		return ErrInvalidRestriction
+	}
+	if utf8.RuneCountInString(restriction.Note) > maxRestrictionNoteLength {
+		return ErrInvalidRestriction
+	}
+	return nil
+}
+
+// recipientError hides from the sender that the wallet receiving the money
+// is blocked.
+func recipientError(err error) error {
+	if errors.Is(err, ErrWalletBlocked) {
+		return ErrRecipientDisabled
+	}
+	return err
+}
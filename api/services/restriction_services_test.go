@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_restriction "my_wallet/api/respository/restriction"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPlaceRestrictionService(t *testing.T) {
+	nextWeek := time.Now().Add(7 * 24 * time.Hour)
+
+	testScenarios := []struct {
+		testName      string
+		staff         entities.User
+		restriction   entities.WalletRestriction
+		expectedError error
+	}{
+		{
+			testName:    "TestPlaceFreeze",
+			staff:       entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			restriction: entities.WalletRestriction{Kind: entities.RestrictionFreeze, Reason: "suspected_fraud", Expires_at: nextWeek},
+		},
+		{
+			testName:    "TestPlaceCapabilityRestriction",
+			staff:       entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			restriction: entities.WalletRestriction{Kind: entities.RestrictionCapabilities, Capabilities: []string{entities.CapabilityWithdrawal}, Reason: "chargeback", Expires_at: nextWeek},
+		},
+		{
+			testName:      "TestPlaceRestrictionWithoutStaff",
+			staff:         entities.User{ID: "u1", Email: "support@gmail.com"},
+			restriction:   entities.WalletRestriction{Kind: entities.RestrictionFreeze, Reason: "suspected_fraud", Expires_at: nextWeek},
+			expectedError: ErrStaffRequired,
+		},
+		{
+			testName:      "TestPlaceRestrictionUnknownReason",
+			staff:         entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			restriction:   entities.WalletRestriction{Kind: entities.RestrictionBlock, Reason: "bad_vibes", Expires_at: nextWeek},
+			expectedError: ErrInvalidRestriction,
+		},
+		{
+			testName:      "TestPlaceRestrictionWithoutExpiry",
+			staff:         entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			restriction:   entities.WalletRestriction{Kind: entities.RestrictionBlock, Reason: "legal_order"},
+			expectedError: ErrInvalidRestriction,
+		},
+		{
+			testName:      "TestPlaceRestrictionExpiryTooFar",
+			staff:         entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			restriction:   entities.WalletRestriction{Kind: entities.RestrictionBlock, Reason: "legal_order", Expires_at: time.Now().AddDate(2, 0, 0)},
+			expectedError: ErrInvalidRestriction,
+		},
+		{
+			testName:      "TestPlaceCapabilityRestrictionWithoutCapabilities",
+			staff:         entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			restriction:   entities.WalletRestriction{Kind: entities.RestrictionCapabilities, Reason: "chargeback", Expires_at: nextWeek},
+			expectedError: ErrInvalidRestriction,
+		},
+		{
+			testName:      "TestPlaceReversalRestriction",
+			staff:         entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleSupport},
+			restriction:   entities.WalletRestriction{Kind: entities.RestrictionCapabilities, Capabilities: []string{entities.CapabilityReversal}, Reason: "chargeback", Expires_at: nextWeek},
+			expectedError: ErrInvalidRestriction,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "support@gmail.com").Return(tt.staff, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetWallet", mock.Anything, "w1").Return(entities.Wallet{ID: "w1", UserID: "u1"}, nil)
+			restrictions := &restrictionRepositoryMock{}
+			restrictions.On("CreateRestriction", mock.Anything, mock.Anything).Return(func(r entities.WalletRestriction) entities.WalletRestriction {
+				r.ID = "r1"
+				return r
+			}, nil)
+			restrictions.On("CreateEvent", mock.Anything, mock.Anything).Return(entities.RestrictionEvent{}, nil)
+			notifier := &notifierMock{}
+			notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+			service := NewRestrictionService(users, wallets, restrictions, notifier, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.PlaceRestriction(context.Background(), "support@gmail.com", "w1", tt.restriction)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, "r1", result.ID)
+				assert.Equal(t, "u1", result.UserID)
+				assert.Equal(t, "support@gmail.com", result.CreatedBy)
+				assert.Equal(t, entities.RestrictionActive, result.Status)
+				restrictions.AssertCalled(t, "CreateEvent", mock.Anything, mock.MatchedBy(func(e entities.RestrictionEvent) bool {
+					return e.RestrictionID == "r1" && e.Action == entities.RestrictionActionPlaced && e.Actor == "support@gmail.com" && e.Reason == tt.restriction.Reason
+				}))
+				notifier.AssertCalled(t, "Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+					return n.UserID == "u1" && n.Type == entities.NotificationWalletRestricted
+				}))
+			} else {
+				restrictions.AssertNotCalled(t, "CreateRestriction", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestLiftRestrictionService(t *testing.T) {
+	active := entities.WalletRestriction{ID: "r1", WalletID: "w1", UserID: "u1", Kind: entities.RestrictionFreeze, Reason: "suspected_fraud"}
+
+	testScenarios := []struct {
+		testName      string
+		walletID      string
+		liftError     error
+		expectedError error
+	}{
+		{
+			testName: "TestLiftRestriction",
+			walletID: "w1",
+		},
+		{
+			testName:      "TestLiftRestrictionOfAnotherWallet",
+			walletID:      "w2",
+			expectedError: ErrRestrictionNotFound,
+		},
+		{
+			testName:      "TestLiftRestrictionTwice",
+			walletID:      "w1",
+			liftError:     repository_restriction.ErrRestrictionLifted,
+			expectedError: ErrRestrictionLifted,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "support@gmail.com").Return(entities.User{ID: "s1", Email: "support@gmail.com", Role: entities.RoleAdmin}, nil)
+			restrictions := &restrictionRepositoryMock{}
+			restrictions.On("GetRestriction", mock.Anything, "r1").Return(active, nil)
+			restrictions.On("LiftRestriction", mock.Anything, "r1", "support@gmail.com", "Customer confirmed").Return(active, tt.liftError)
+			restrictions.On("CreateEvent", mock.Anything, mock.Anything).Return(entities.RestrictionEvent{}, nil)
+			service := NewRestrictionService(users, &walletRepositoryMock{}, restrictions, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.LiftRestriction(context.Background(), "support@gmail.com", tt.walletID, "r1", " Customer confirmed ")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, entities.RestrictionLifted, result.Status)
+				restrictions.AssertCalled(t, "CreateEvent", mock.Anything, mock.MatchedBy(func(e entities.RestrictionEvent) bool {
+					return e.Action == entities.RestrictionActionLifted && e.Detail == "Customer confirmed"
+				}))
+			} else {
+				restrictions.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCheckRestrictions(t *testing.T) {
+	freeze := entities.WalletRestriction{Kind: entities.RestrictionFreeze}
+	block := entities.WalletRestriction{Kind: entities.RestrictionBlock}
+	withdrawals := entities.WalletRestriction{Kind: entities.RestrictionCapabilities, Capabilities: []string{entities.CapabilityWithdrawal}}
+
+	testScenarios := []struct {
+		testName      string
+		restrictions  []entities.WalletRestriction
+		capability    string
+		expectedError error
+	}{
+		{
+			testName:   "TestCheckDebitWithoutRestrictions",
+			capability: entities.CapabilityTransfer,
+		},
+		{
+			testName:      "TestCheckDebitFrozen",
+			restrictions:  []entities.WalletRestriction{freeze},
+			capability:    entities.CapabilityTransfer,
+			expectedError: ErrWalletFrozen,
+		},
+		{
+			testName:     "TestCheckCreditFrozen",
+			restrictions: []entities.WalletRestriction{freeze},
+		},
+		{
+			testName:      "TestCheckCreditBlocked",
+			restrictions:  []entities.WalletRestriction{block},
+			expectedError: ErrWalletBlocked,
+		},
+		{
+			testName:      "TestCheckDebitRestrictedCapability",
+			restrictions:  []entities.WalletRestriction{withdrawals},
+			capability:    entities.CapabilityWithdrawal,
+			expectedError: ErrCapabilityRestricted,
+		},
+		{
+			testName:     "TestCheckDebitOtherCapability",
+			restrictions: []entities.WalletRestriction{withdrawals},
+			capability:   entities.CapabilityTransfer,
+		},
+		{
+			testName:      "TestCheckDebitMostSevere",
+			restrictions:  []entities.WalletRestriction{withdrawals, freeze, block},
+			capability:    entities.CapabilityWithdrawal,
+			expectedError: ErrWalletBlocked,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			restrictions := &restrictionRepositoryMock{}
+			restrictions.On("ActiveRestrictions", mock.Anything, "w1").Return(tt.restrictions, nil)
+			service := NewRestrictionService(&userServiceMock{}, &walletRepositoryMock{}, restrictions, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			var err error
+			if tt.capability == "" {
+				err = service.CheckCredit(context.Background(), "w1")
+			} else {
+				err = service.CheckDebit(context.Background(), "w1", tt.capability)
+			}
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type sanctionsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *sanctionsRepositoryMock) CreateScreening(screening entities.SanctionsScreening, ctx context.Context) (entities.SanctionsScreening, error) {
+	r := m.Called(ctx, screening)
+	return r.Get(0).(entities.SanctionsScreening), r.Error(1)
+}
+
+func (m *sanctionsRepositoryMock) GetScreening(id string, ctx context.Context) (entities.SanctionsScreening, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.SanctionsScreening), r.Error(1)
+}
+
+func (m *sanctionsRepositoryMock) ListScreenings(status string, limit int64, ctx context.Context) ([]entities.SanctionsScreening, error) {
+	r := m.Called(ctx, status, limit)
+	return r.Get(0).([]entities.SanctionsScreening), r.Error(1)
+}
+
+func (m *sanctionsRepositoryMock) UpdateScreeningStatus(id string, from string, to string, reviewer string, note string, ctx context.Context) (entities.SanctionsScreening, error) {
+	r := m.Called(ctx, id, from, to, reviewer, note)
+	return r.Get(0).(entities.SanctionsScreening), r.Error(1)
+}
+
+func (m *sanctionsRepositoryMock) ClearedEntries(userID string, ctx context.Context) ([]string, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).([]string), r.Error(1)
+}
+
+func (m *sanctionsRepositoryMock) ScreenedVersion(ctx context.Context) (string, error) {
+	r := m.Called(ctx)
+	return r.String(0), r.Error(1)
+}
+
+func (m *sanctionsRepositoryMock) SetScreenedVersion(version string, ctx context.Context) error {
+	r := m.Called(ctx, version)
+	return r.Error(0)
+}
@@ -0,0 +1,445 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_sanctions "my_wallet/api/respository/sanctions"
+	repository_user "my_wallet/api/respository/user"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxSanctionsScreenings = 100
+	rescreenUsersBatch     = 500
+)
+
+// SanctionsScreener checks users against the sanctions list and holds the
+// ones that match for a compliance review.
+type SanctionsScreener interface {
+	CheckUser(ctx context.Context, user entities.User) ([]entities.SanctionsMatch, string, error)
+	HoldUser(ctx context.Context, user entities.User, matches []entities.SanctionsMatch, version string, reason string) (entities.SanctionsScreening, error)
+}
+
+type SanctionsService interface {
+	ListScreenings(ctx context.Context, email string, status string) ([]entities.SanctionsScreening, error)
+	ClearScreening(ctx context.Context, email string, id string, note string) (entities.SanctionsScreening, error)
+	ConfirmScreening(ctx context.Context, email string, id string, note string) (entities.SanctionsScreening, error)
+	RescreenUsers(ctx context.Context) (int, error)
+}
+
+type sanctionsService struct {
+	ctx                 context.Context
+	userRepository      repository_user.UserRepository
+	sanctionsRepository repository_sanctions.SanctionsRepository
+	list                repository_sanctions.SanctionsList
+	threshold           float64
+	logger              logrus.FieldLogger
+	mu                  sync.Mutex
+	index               *sanctionsIndex
+}
+
+// sanctionsIndex holds the names of a version of the list, normalized once.
+type sanctionsIndex struct {
+	version string
+	names   []sanctionsName
+}
+
+type sanctionsName struct {
+	entry *entities.SanctionsEntry
+	name  string
+	words []string
+}
+
+// NewSanctionsService screens users against list; names scoring threshold
+// or more, between 0 and 1, match.
+func NewSanctionsService(userRepo repository_user.UserRepository, sanctionsRepo repository_sanctions.SanctionsRepository, list repository_sanctions.SanctionsList, threshold float64, logger logrus.FieldLogger, ctx context.Context) *sanctionsService {
+	return &sanctionsService{
+		ctx:                 ctx,
+		userRepository:      userRepo,
+		sanctionsRepository: sanctionsRepo,
+		list:                list,
+		threshold:           threshold,
+		logger:              logger,
+	}
+}
+
+// CheckUser returns the listed parties whose name, or one of their other
+// names, matches the name of the user, best match first, and the version of
+// the list checked. People are only checked against listed individuals and
+// companies against listed entities.
+func (s *sanctionsService) CheckUser(ctx context.Context, user entities.User) ([]entities.SanctionsMatch, string, error) {
+	index, err := s.loadIndex(ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: CheckUser", "Error:", err)
+		return nil, "", err
+	}
+	return s.match(index, user), index.version, nil
+}
+
+// HoldUser puts the user in the compliance review queue and keeps it from
+// moving money until an analyst decides.
+func (s *sanctionsService) HoldUser(ctx context.Context, user entities.User, matches []entities.SanctionsMatch, version string, reason string) (entities.SanctionsScreening, error) {
+	if user.Status != entities.UserPendingReview {
+		if err := s.userRepository.SetStatus(user.ID, entities.UserPendingReview, ctx); err != nil {
+			s.logger.Errorln("Layer: sanctions_services", "Method: HoldUser", "Error:", err)
+			return entities.SanctionsScreening{}, err
+		}
+	}
+	screening, err := s.sanctionsRepository.CreateScreening(entities.SanctionsScreening{
+		UserID:      user.ID,
+		UserName:    user.Name,
+		Reason:      reason,
+		ListVersion: version,
+		Matches:     matches,
+		Status:      entities.SanctionsPending,
+		Created_at:  time.Now().UTC(),
+	}, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: HoldUser", "Error:", err)
+		return entities.SanctionsScreening{}, err
+	}
+	s.logger.Infoln("Layer: sanctions_services", "Method: HoldUser", "User:", user.ID, "Reason:", reason, "Matches:", len(matches))
+	return screening, nil
+}
+
+// ListScreenings returns the screenings in a status, pending ones by
+// default, oldest first.
+func (s *sanctionsService) ListScreenings(ctx context.Context, email string, status string) ([]entities.SanctionsScreening, error) {
+	if _, err := requireAnalyst(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: ListScreenings", "Error:", err)
+		return nil, err
+	}
+	if status == "" {
+		status = entities.SanctionsPending
+	}
+	screenings, err := s.sanctionsRepository.ListScreenings(status, maxSanctionsScreenings, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: ListScreenings", "Error:", err)
+		return nil, err
+	}
+	return screenings, nil
+}
+
+// ClearScreening lets an analyst decide the matches were not the user, who
+// can move money again. Re-screening does not hold the user for the same
+// listed parties again.
+func (s *sanctionsService) ClearScreening(ctx context.Context, email string, id string, note string) (entities.SanctionsScreening, error) {
+	return s.decide(ctx, "ClearScreening", email, id, note, entities.SanctionsCleared, entities.UserActive)
+}
+
+// ConfirmScreening lets an analyst confirm the user is a listed party. The
+// user is blocked and disabled.
+func (s *sanctionsService) ConfirmScreening(ctx context.Context, email string, id string, note string) (entities.SanctionsScreening, error) {
+	return s.decide(ctx, "ConfirmScreening", email, id, note, entities.SanctionsConfirmed, entities.UserBlocked)
+}
+
+func (s *sanctionsService) decide(ctx context.Context, method string, email string, id string, note string, status string, userStatus string) (entities.SanctionsScreening, error) {
+	analyst, err := requireAnalyst(ctx, s.userRepository, email)
+	if err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: "+method, "Error:", err)
+		return entities.SanctionsScreening{}, err
+	}
+	screening, err := s.sanctionsRepository.UpdateScreeningStatus(id, entities.SanctionsPending, status, analyst.Email, note, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: "+method, "Error:", err)
+		return entities.SanctionsScreening{}, sanctionsError(err)
+	}
+	if err := s.userRepository.SetStatus(screening.UserID, userStatus, ctx); err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: "+method, "Error:", err)
+		return entities.SanctionsScreening{}, err
+	}
+	s.logger.Infoln("Layer: sanctions_services", "Method: "+method, "Screening:", id, "User:", screening.UserID, "Analyst:", analyst.Email)
+	return screening, nil
+}
+
+// RescreenUsers screens every active user again when the list changed since
+// the last time, and reports how many were held. The list version is only
+// recorded once all users were screened, so an interrupted run starts over.
+func (s *sanctionsService) RescreenUsers(ctx context.Context) (int, error) {
+	index, err := s.loadIndex(ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: RescreenUsers", "Error:", err)
+		return 0, err
+	}
+	screened, err := s.sanctionsRepository.ScreenedVersion(ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: RescreenUsers", "Error:", err)
+		return 0, err
+	}
+	if screened == index.version {
+		return 0, nil
+	}
+	held, after := 0, ""
+	for {
+		users, err := s.userRepository.ListUsers(after, rescreenUsersBatch, ctx)
+		if err != nil {
+			s.logger.Errorln("Layer: sanctions_services", "Method: RescreenUsers", "Error:", err)
+			return held, err
+		}
+		for _, user := range users {
+			after = user.ID
+			if !user.Active() {
+				continue
+			}
+			matches, err := s.uncleared(ctx, user, s.match(index, user))
+			if err != nil {
+				return held, err
+			}
+			if len(matches) == 0 {
+				continue
+			}
+			if _, err := s.HoldUser(ctx, user, matches, index.version, entities.SanctionsRescreen); err != nil {
+				return held, err
+			}
+			held++
+		}
+		if len(users) < rescreenUsersBatch {
+			break
+		}
+	}
+	if err := s.sanctionsRepository.SetScreenedVersion(index.version, ctx); err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: RescreenUsers", "Error:", err)
+		return held, err
+	}
+	s.logger.Infoln("Layer: sanctions_services", "Method: RescreenUsers", "Version:", index.version, "Held:", held)
+	return held, nil
+}
+
+// uncleared drops the matches an analyst already cleared the user of.
+func (s *sanctionsService) uncleared(ctx context.Context, user entities.User, matches []entities.SanctionsMatch) ([]entities.SanctionsMatch, error) {
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	cleared, err := s.sanctionsRepository.ClearedEntries(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: sanctions_services", "Method: RescreenUsers", "Error:", err)
+		return nil, err
+	}
+	clearedUIDs := make(map[string]bool, len(cleared))
+	for _, uid := range cleared {
+		clearedUIDs[uid] = true
+	}
+	var left []entities.SanctionsMatch
+	for _, match := range matches {
+		if !clearedUIDs[match.EntryUID] {
+			left = append(left, match)
+		}
+	}
+	return left, nil
+}
+
+// loadIndex returns the normalized names of the list in force, building them
+// again only when the list changed.
+func (s *sanctionsService) loadIndex(ctx context.Context) (*sanctionsIndex, error) {
+	entries, version, err := s.list.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index != nil && s.index.version == version {
+		return s.index, nil
+	}
+	index := &sanctionsIndex{version: version}
+	for i := range entries {
+		entry := &entries[i]
+		for _, name := range append([]string{entry.Name}, entry.Aliases...) {
+			if words := normalizeName(name); len(words) > 0 {
+				index.names = append(index.names, sanctionsName{entry: entry, name: name, words: words})
+			}
+		}
+	}
+	s.index = index
+	return index, nil
+}
+
+// match returns the best match of every listed party matching the user.
+func (s *sanctionsService) match(index *sanctionsIndex, user entities.User) []entities.SanctionsMatch {
+	words := normalizeName(user.Name)
+	partyType := entities.SanctionsIndividual
+	if user.TypeDNI == "NIT" {
+		partyType = entities.SanctionsEntity
+	}
+	best := map[string]int{}
+	var matches []entities.SanctionsMatch
+	for _, listed := range index.names {
+		if listed.entry.Type != partyType {
+			continue
+		}
+		score := nameScore(words, listed.words)
+		if score < s.threshold {
+			continue
+		}
+		match := entities.SanctionsMatch{
+			EntryUID:    listed.entry.UID,
+			ListedName:  listed.entry.Name,
+			MatchedName: listed.name,
+			Program:     listed.entry.Program,
+			Score:       float64(int(score*1000)) / 1000,
+		}
+		if i, ok := best[match.EntryUID]; ok {
+			if match.Score > matches[i].Score {
+				matches[i] = match
+			}
+			continue
+		}
+		best[match.EntryUID] = len(matches)
+		matches = append(matches, match)
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// sanctionsError maps the errors of the review queue to the ones of the
+// service.
+func sanctionsError(err error) error {
+	switch {
+	case errors.Is(err, repository_sanctions.ErrScreeningNotFound):
+		return ErrSanctionsScreeningNotFound
+	case errors.Is(err, repository_sanctions.ErrScreeningStatusChanged):
+		return ErrSanctionsScreeningState
+	}
+	return err
+}
+
+// transliterations spell the letters of other alphabets, and Latin letters
+// with diacritics, in plain ASCII, the way sanctions lists usually romanize
+// them.
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'ç': "c", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ḍ': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h", 'ḥ': "h", 'ḫ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j", 'ķ': "k", 'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r",
+	'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ṣ': "s", 'ș': "s",
+	'ţ': "t", 'ť': "t", 'ŧ': "t", 'ṭ': "t", 'ț': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ŵ': "w", 'ý': "y", 'ÿ': "y", 'ŷ': "y", 'ź': "z", 'ż': "z", 'ž': "z", 'ẓ': "z",
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'þ': "th", 'ð': "d",
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i",
+	'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s",
+	'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+	'ά': "a", 'έ': "e", 'ή': "i", 'ί': "i", 'ό': "o", 'ύ': "y", 'ώ': "o",
+}
+
+// nameParticles are left out of names, as lists and users write them
+// inconsistently.
+var nameParticles = map[string]bool{
+	"al": true, "el": true, "bin": true, "ibn": true, "de": true, "del": true,
+	"la": true, "los": true, "van": true, "von": true, "der": true,
+}
+
+// normalizeName lower-cases and transliterates a name and splits it into
+// words, dropping punctuation and particles.
+func normalizeName(name string) []string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if spelled, ok := transliterations[r]; ok {
+			b.WriteString(spelled)
+			continue
+		}
+		switch {
+		case r == '\'' || r == '`' || r == '’':
+			// Apostrophes join the parts of a name: O'Brien, Ma'ruf.
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	var words []string
+	for _, word := range strings.Fields(b.String()) {
+		if !nameParticles[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// nameScore compares two normalized names with Jaro-Winkler, both in the
+// order they are written and with the words sorted, as lists write the last
+// name first.
+func nameScore(a []string, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	score := jaroWinkler(strings.Join(a, " "), strings.Join(b, " "))
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	if sorted := jaroWinkler(strings.Join(sortedA, " "), strings.Join(sortedB, " ")); sorted > score {
+		score = sorted
+	}
+	return score
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of two strings, from 0 for
+// nothing in common to 1 for equal strings.
+func jaroWinkler(a string, b string) float64 {
+	s, t := []rune(a), []rune(b)
+	if len(s) == 0 && len(t) == 0 {
+		return 1
+	}
+	if len(s) == 0 || len(t) == 0 {
+		return 0
+	}
+	window := max(len(s), len(t))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	sMatched := make([]bool, len(s))
+	tMatched := make([]bool, len(t))
+	matches := 0
+	for i := range s {
+		for j := max(0, i-window); j < min(len(t), i+window+1); j++ {
+			if !tMatched[j] && s[i] == t[j] {
+				sMatched[i], tMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	transpositions, j := 0, 0
+	for i := range s {
+		if !sMatched[i] {
+			continue
+		}
+		for !tMatched[j] {
+			j++
+		}
+		if s[i] != t[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	jaro := (m/float64(len(s)) + m/float64(len(t)) + (m-float64(transpositions)/2)/m) / 3
+	prefix := 0
+	for prefix < min(4, len(s), len(t)) && s[prefix] == t[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
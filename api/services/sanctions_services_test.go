@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_sanctions "my_wallet/api/respository/sanctions"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testSanctionsEntries = []entities.SanctionsEntry{
+	{UID: "90001", Name: "QUINTERO VALDES, Hernan Dario", Type: entities.SanctionsIndividual, Program: "SDNTK"},
+	{UID: "90003", Name: "INVERSIONES RIO CLARO S.A.S.", Type: entities.SanctionsEntity, Program: "SDNTK"},
+	{UID: "90004", Name: "PETROVSKY, Dmitri Ivanovich", Type: entities.SanctionsIndividual, Program: "RUSSIA-EO14024", Aliases: []string{"ПЕТРОВСКИЙ, Дмитрий Иванович"}},
+	{UID: "90006", Name: "HERNAN DARIO QUINTERO VALDES", Type: entities.SanctionsVessel},
+}
+
+func TestJaroWinkler(t *testing.T) {
+	testScenarios := []struct {
+		a        string
+		b        string
+		expected float64
+	}{
+		{a: "martha", b: "marhta", expected: 0.961},
+		{a: "dwayne", b: "duane", expected: 0.84},
+		{a: "dixon", b: "dicksonx", expected: 0.813},
+		{a: "abc", b: "xyz", expected: 0},
+		{a: "same", b: "same", expected: 1},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			// Act
+			result := jaroWinkler(tt.a, tt.b)
+
+			// Assert
+			assert.InDelta(t, tt.expected, result, 0.001)
+		})
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, []string{"quintero", "valdes", "hernan", "dario"}, normalizeName("QUINTERO VALDÉS, Hernán Darío"))
+	assert.Equal(t, []string{"petrovskiy", "dmitriy", "ivanovich"}, normalizeName("ПЕТРОВСКИЙ, Дмитрий Иванович"))
+	assert.Equal(t, []string{"rashid", "yusuf"}, normalizeName("AL-RASHID, Yusuf"))
+}
+
+func TestCheckUserService(t *testing.T) {
+	testScenarios := []struct {
+		testName string
+		user     entities.User
+		expected []string
+	}{
+		{
+			testName: "TestMatchInOtherOrder",
+			user:     entities.User{Name: "Hernan Dario Quintero Valdes", TypeDNI: "CC"},
+			expected: []string{"90001"},
+		},
+		{
+			testName: "TestMatchWithTypo",
+			user:     entities.User{Name: "Hernan Dario Quintero Valdez", TypeDNI: "CC"},
+			expected: []string{"90001"},
+		},
+		{
+			testName: "TestMatchTransliteratedAlias",
+			user:     entities.User{Name: "Dmitriy Ivanovich Petrovskiy", TypeDNI: "CC"},
+			expected: []string{"90004"},
+		},
+		{
+			testName: "TestCompanyOnlyMatchesEntities",
+			user:     entities.User{Name: "Inversiones Rio Claro SAS", TypeDNI: "NIT"},
+			expected: []string{"90003"},
+		},
+		{
+			testName: "TestPersonDoesNotMatchEntities",
+			user:     entities.User{Name: "Inversiones Rio Claro SAS", TypeDNI: "CC"},
+			expected: nil,
+		},
+		{
+			testName: "TestNoMatch",
+			user:     entities.User{Name: "Alexer Maestre", TypeDNI: "CC"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			list := repository_sanctions.NewStaticSanctionsList(testSanctionsEntries, "v1")
+			service := NewSanctionsService(&userServiceMock{}, &sanctionsRepositoryMock{}, list, 0.9, logrus.StandardLogger(), context.Background())
+
+			// Act
+			matches, version, err := service.CheckUser(context.Background(), tt.user)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, "v1", version)
+			var uids []string
+			for _, match := range matches {
+				uids = append(uids, match.EntryUID)
+			}
+			assert.Equal(t, tt.expected, uids)
+		})
+	}
+}
+
+func TestCreateUserHeldBySanctions(t *testing.T) {
+	// Prepare
+	users := &userServiceMock{}
+	users.On("CreateUser", mock.Anything, mock.MatchedBy(func(u entities.User) bool {
+		return u.Status == entities.UserPendingReview
+	})).Return(entities.User{ID: "u1", Name: "Hernan Dario Quintero Valdes", Status: entities.UserPendingReview}, nil)
+	repo := &sanctionsRepositoryMock{}
+	repo.On("CreateScreening", mock.Anything, mock.MatchedBy(func(s entities.SanctionsScreening) bool {
+		return s.UserID == "u1" && s.Reason == entities.SanctionsAtRegistration && s.Status == entities.SanctionsPending && len(s.Matches) == 1
+	})).Return(entities.SanctionsScreening{ID: "s1"}, nil)
+	list := repository_sanctions.NewStaticSanctionsList(testSanctionsEntries, "v1")
+	sanctions := NewSanctionsService(users, repo, list, 0.9, logrus.StandardLogger(), context.Background())
+	service := NewUserService(users, sanctions, logrus.StandardLogger(), context.Background())
+
+	// Act
+	result, err := service.CreateUser(context.Background(), entities.User{
+		DNI: 34, TypeDNI: "CC", Name: "Hernan Dario Quintero Valdes", Email: "hernan@gmail.com",
+		Password: "12345678", Address: "cra 22a", Phone: 1234567899, Enabled: true,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, entities.UserPendingReview, result.Status)
+	repo.AssertExpectations(t)
+	users.AssertNotCalled(t, "SetStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateUserRolledBackWhenHoldFails(t *testing.T) {
+	// Prepare
+	users := &userServiceMock{}
+	users.On("CreateUser", mock.Anything, mock.Anything).Return(entities.User{ID: "u1", Name: "Hernan Dario Quintero Valdes", Status: entities.UserPendingReview}, nil)
+	users.On("DeleteUser", mock.Anything, "u1").Return(nil)
+	repo := &sanctionsRepositoryMock{}
+	repo.On("CreateScreening", mock.Anything, mock.Anything).Return(entities.SanctionsScreening{}, errors.New("connection reset"))
+	list := repository_sanctions.NewStaticSanctionsList(testSanctionsEntries, "v1")
+	sanctions := NewSanctionsService(users, repo, list, 0.9, logrus.StandardLogger(), context.Background())
+	service := NewUserService(users, sanctions, logrus.StandardLogger(), context.Background())
+
+	// Act
+	result, err := service.CreateUser(context.Background(), entities.User{
+		DNI: 34, TypeDNI: "CC", Name: "Hernan Dario Quintero Valdes", Email: "hernan@gmail.com",
+		Password: "12345678", Address: "cra 22a", Phone: 1234567899, Enabled: true,
+	})
+
+	// Assert
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, entities.User{}, result)
+	users.AssertCalled(t, "DeleteUser", mock.Anything, "u1")
+}
+
+func TestRescreenUsersService(t *testing.T) {
+	matching := entities.User{ID: "u1", Name: "Hernan Dario Quintero Valdes", TypeDNI: "CC", Enabled: true}
+	cleared := entities.User{ID: "u2", Name: "Dmitri Ivanovich Petrovsky", TypeDNI: "CC", Enabled: true}
+	pending := entities.User{ID: "u3", Name: "Hernan Dario Quintero Valdes", TypeDNI: "CC", Enabled: true, Status: entities.UserPendingReview}
+	other := entities.User{ID: "u4", Name: "Alexer Maestre", TypeDNI: "CC", Enabled: true}
+
+	testScenarios := []struct {
+		testName      string
+		configureMock func(*userServiceMock, *sanctionsRepositoryMock)
+		expectedHeld  int
+	}{
+		{
+			testName: "TestRescreenWhenListChanged",
+			configureMock: func(u *userServiceMock, s *sanctionsRepositoryMock) {
+				s.On("ScreenedVersion", mock.Anything).Return("v0", nil)
+				u.On("ListUsers", mock.Anything, "", int64(rescreenUsersBatch)).Return([]entities.User{matching, cleared, pending, other}, nil)
+				s.On("ClearedEntries", mock.Anything, "u1").Return([]string{}, nil)
+				s.On("ClearedEntries", mock.Anything, "u2").Return([]string{"90004"}, nil)
+				u.On("SetStatus", mock.Anything, "u1", entities.UserPendingReview).Return(nil)
+				s.On("CreateScreening", mock.Anything, mock.MatchedBy(func(screening entities.SanctionsScreening) bool {
+					return screening.UserID == "u1" && screening.Reason == entities.SanctionsRescreen && screening.ListVersion == "v1"
+				})).Return(entities.SanctionsScreening{ID: "s1"}, nil)
+				s.On("SetScreenedVersion", mock.Anything, "v1").Return(nil)
+			},
+			expectedHeld: 1,
+		},
+		{
+			testName: "TestRescreenListUnchanged",
+			configureMock: func(u *userServiceMock, s *sanctionsRepositoryMock) {
+				s.On("ScreenedVersion", mock.Anything).Return("v1", nil)
+			},
+			expectedHeld: 0,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			repo := &sanctionsRepositoryMock{}
+			tt.configureMock(users, repo)
+			list := repository_sanctions.NewStaticSanctionsList(testSanctionsEntries, "v1")
+			service := NewSanctionsService(users, repo, list, 0.9, logrus.StandardLogger(), context.Background())
+
+			// Act
+			held, err := service.RescreenUsers(context.Background())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedHeld, held)
+			users.AssertExpectations(t)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDecideSanctionsScreeningService(t *testing.T) {
+	analyst := entities.User{ID: "a1", Email: "analyst@gmail.com", Enabled: true, Role: entities.RoleAnalyst}
+
+	testScenarios := []struct {
+		testName       string
+		confirm        bool
+		configureMock  func(*userServiceMock, *sanctionsRepositoryMock)
+		expectedOutput entities.SanctionsScreening
+		expectedError  error
+	}{
+		{
+			testName: "TestClearScreening",
+			configureMock: func(u *userServiceMock, s *sanctionsRepositoryMock) {
+				s.On("UpdateScreeningStatus", mock.Anything, "s1", entities.SanctionsPending, entities.SanctionsCleared, "analyst@gmail.com", "").Return(entities.SanctionsScreening{ID: "s1", UserID: "u1", Status: entities.SanctionsCleared}, nil)
+				u.On("SetStatus", mock.Anything, "u1", entities.UserActive).Return(nil)
+			},
+			expectedOutput: entities.SanctionsScreening{ID: "s1", UserID: "u1", Status: entities.SanctionsCleared},
+			expectedError:  nil,
+		},
+		{
+			testName: "TestConfirmScreening",
+			confirm:  true,
+			configureMock: func(u *userServiceMock, s *sanctionsRepositoryMock) {
+				s.On("UpdateScreeningStatus", mock.Anything, "s1", entities.SanctionsPending, entities.SanctionsConfirmed, "analyst@gmail.com", "").Return(entities.SanctionsScreening{ID: "s1", UserID: "u1", Status: entities.SanctionsConfirmed}, nil)
+				u.On("SetStatus", mock.Anything, "u1", entities.UserBlocked).Return(nil)
+			},
+			expectedOutput: entities.SanctionsScreening{ID: "s1", UserID: "u1", Status: entities.SanctionsConfirmed},
+			expectedError:  nil,
+		},
+		{
+			testName: "TestScreeningAlreadyDecided",
+			configureMock: func(u *userServiceMock, s *sanctionsRepositoryMock) {
+				s.On("UpdateScreeningStatus", mock.Anything, "s1", entities.SanctionsPending, entities.SanctionsCleared, "analyst@gmail.com", "").Return(entities.SanctionsScreening{}, repository_sanctions.ErrScreeningStatusChanged)
+			},
+			expectedOutput: entities.SanctionsScreening{},
+			expectedError:  ErrSanctionsScreeningState,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "analyst@gmail.com").Return(analyst, nil)
+			repo := &sanctionsRepositoryMock{}
+			tt.configureMock(users, repo)
+			service := NewSanctionsService(users, repo, nil, 0.9, logrus.StandardLogger(), context.Background())
+
+			// Act
+			var result entities.SanctionsScreening
+			var err error
+			if tt.confirm {
+				result, err = service.ConfirmScreening(context.Background(), "analyst@gmail.com", "s1", "")
+			} else {
+				result, err = service.ClearScreening(context.Background(), "analyst@gmail.com", "s1", "")
+			}
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+			users.AssertExpectations(t)
+		})
+	}
+}
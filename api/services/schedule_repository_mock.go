@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type scheduleRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *scheduleRepositoryMock) CreateSchedule(schedule entities.ScheduledPayment, ctx context.Context) (entities.ScheduledPayment, error) {
+	r := m.Called(ctx, schedule)
+	return r.Get(0).(entities.ScheduledPayment), r.Error(1)
+}
+
+func (m *scheduleRepositoryMock) GetSchedule(id string, ctx context.Context) (entities.ScheduledPayment, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.ScheduledPayment), r.Error(1)
+}
+
+func (m *scheduleRepositoryMock) ListSchedules(userID string, ctx context.Context) ([]entities.ScheduledPayment, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).([]entities.ScheduledPayment), r.Error(1)
+}
+
+func (m *scheduleRepositoryMock) DueSchedules(now time.Time, limit int64, ctx context.Context) ([]entities.ScheduledPayment, error) {
+	r := m.Called(ctx, now, limit)
+	return r.Get(0).([]entities.ScheduledPayment), r.Error(1)
+}
+
+func (m *scheduleRepositoryMock) LockSchedule(id string, now time.Time, until time.Time, ctx context.Context) error {
+	r := m.Called(ctx, id, now, until)
+	return r.Error(0)
+}
+
+func (m *scheduleRepositoryMock) CancelSchedule(id string, now time.Time, ctx context.Context) error {
+	r := m.Called(ctx, id)
+	return r.Error(0)
+}
+
+func (m *scheduleRepositoryMock) FinishRun(schedule entities.ScheduledPayment, lockedUntil time.Time, ctx context.Context) error {
+	r := m.Called(ctx, schedule, lockedUntil)
+	return r.Error(0)
+}
+
+func (m *scheduleRepositoryMock) CreateExecution(execution entities.ScheduleExecution, ctx context.Context) (entities.ScheduleExecution, error) {
+	r := m.Called(ctx, execution)
+	return r.Get(0).(entities.ScheduleExecution), r.Error(1)
+}
+
+func (m *scheduleRepositoryMock) ListExecutions(scheduleID string, ctx context.Context) ([]entities.ScheduleExecution, error) {
+	r := m.Called(ctx, scheduleID)
+	return r.Get(0).([]entities.ScheduleExecution), r.Error(1)
+}
+
+func (m *scheduleRepositoryMock) ResolveExecution(reviewID string, status string, transferID string, reason string, ctx context.Context) error {
+	r := m.Called(ctx, reviewID, status, transferID, reason)
+	return r.Error(0)
+}
+
+type leaseRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *leaseRepositoryMock) AcquireLease(name string, holder string, now time.Time, ttl time.Duration, ctx context.Context) error {
+	r := m.Called(ctx, name, holder, now, ttl)
+	return r.Error(0)
+}
+
+func (m *leaseRepositoryMock) ReleaseLease(name string, holder string, ctx context.Context) error {
+	r := m.Called(ctx, name, holder)
+	return r.Error(0)
+}
+
+type transferServiceMock struct {
+	mock.Mock
+}
+
+func (m *transferServiceMock) CreateTransfer(ctx context.Context, senderEmail string, transfer entities.Transfer) (entities.Transfer, error) {
+	r := m.Called(ctx, senderEmail, transfer)
+	return r.Get(0).(entities.Transfer), r.Error(1)
+}
@@ -0,0 +1,396 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_schedule "my_wallet/api/respository/schedule"
+	repository_user "my_wallet/api/respository/user"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxScheduleAttempts is how many times a run is tried before the
+	// occurrence is given up.
+	maxScheduleAttempts = 3
+	// scheduleRetryDelay is multiplied by the attempt number to space retries.
+	scheduleRetryDelay = time.Hour
+	// scheduleLockTTL bounds how long a runner holds a schedule.
+	scheduleLockTTL   = 5 * time.Minute
+	dueSchedulesBatch = 50
+)
+
+type ScheduleService interface {
+	CreateSchedule(ctx context.Context, email string, schedule entities.ScheduledPayment) (entities.ScheduledPayment, error)
+	ListSchedules(ctx context.Context, email string) ([]entities.ScheduledPayment, error)
+	GetSchedule(ctx context.Context, email string, id string) (entities.ScheduledPayment, error)
+	CancelSchedule(ctx context.Context, email string, id string) (entities.ScheduledPayment, error)
+	ListExecutions(ctx context.Context, email string, id string) ([]entities.ScheduleExecution, error)
+	RunDueSchedules(ctx context.Context, now time.Time) (int, error)
+}
+
+type scheduleService struct {
+	ctx                context.Context
+	userRepository     repository_user.UserRepository
+	scheduleRepository repository_schedule.ScheduleRepository
+	transferService    TransferService
+	logger             logrus.FieldLogger
+}
+
+func NewScheduleService(userRepo repository_user.UserRepository, scheduleRepo repository_schedule.ScheduleRepository, transferService TransferService, logger logrus.FieldLogger, ctx context.Context) *scheduleService {
+	return &scheduleService{
+		ctx:                ctx,
+		userRepository:     userRepo,
+		scheduleRepository: scheduleRepo,
+		transferService:    transferService,
+		logger:             logger,
+	}
+}
+
+// CreateSchedule validates and stores a scheduled payment of the
+// authenticated user. A zero StartAt means now.
+func (s *scheduleService) CreateSchedule(ctx context.Context, email string, schedule entities.ScheduledPayment) (entities.ScheduledPayment, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: schedule_services", "Method: CreateSchedule", "Error:", err)
+		return entities.ScheduledPayment{}, err
+	}
+	if err := requireKYC(user, entities.FeatureScheduledTransfer); err != nil {
+		s.logger.Errorln("Layer: schedule_services", "Method: CreateSchedule", "Error:", err)
+		return entities.ScheduledPayment{}, err
+	}
+	now := time.Now().UTC()
+	if schedule.StartAt.IsZero() {
+		schedule.StartAt = now
+	}
+	schedule.StartAt = schedule.StartAt.UTC()
+	if schedule.Interval == 0 {
+		schedule.Interval = 1
+	}
+	if schedule.Frequency == entities.FrequencyMonthly && schedule.DayOfMonth == 0 {
+		schedule.DayOfMonth = schedule.StartAt.Day()
+	}
+	if err := validateSchedule(schedule, now); err != nil {
+		s.logger.Errorln("Layer: schedule_services", "Method: CreateSchedule", "Error:", err)
+		return entities.ScheduledPayment{}, err
+	}
+	recipient, err := findRecipient(ctx, s.userRepository, scheduleTransfer(schedule))
+	if err != nil {
+		s.logger.Errorln("Layer: schedule_services", "Method: CreateSchedule", "Error:", err)
+		return entities.ScheduledPayment{}, err
+	}
+	if recipient.ID == user.ID {
+		s.logger.Errorln("Layer: schedule_services", "Method: CreateSchedule", "Error:", ErrSelfTransfer)
+		return entities.ScheduledPayment{}, ErrSelfTransfer
+	}
+
+	schedule.ID = ""
+	schedule.UserID = user.ID
+	schedule.Status = entities.ScheduleActive
+	schedule.NextRunAt = firstOccurrence(schedule)
+	schedule.DueAt = schedule.NextRunAt
+	schedule.Attempts = 0
+	schedule.Runs = 0
+	schedule.LockedUntil = time.Time{}
+	schedule.Created_at = now
+	schedule.Update_at = now
+	if schedule.EndAt != nil && schedule.NextRunAt.After(*schedule.EndAt) {
+		s.logger.Errorln("Layer: schedule_services", "Method: CreateSchedule", "Error:", ErrInvalidSchedule)
+		return entities.ScheduledPayment{}, ErrInvalidSchedule
+	}
+	return s.scheduleRepository.CreateSchedule(schedule, ctx)
+}
+
+func (s *scheduleService) ListSchedules(ctx context.Context, email string) ([]entities.ScheduledPayment, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: schedule_services", "Method: ListSchedules", "Error:", err)
+		return nil, err
+	}
+	return s.scheduleRepository.ListSchedules(user.ID, ctx)
+}
+
+func (s *scheduleService) GetSchedule(ctx context.Context, email string, id string) (entities.ScheduledPayment, error) {
+	schedule, err := s.ownedSchedule(ctx, email, id)
+	if err != nil {
+		s.logger.Errorln("Layer: schedule_services", "Method: GetSchedule", "Error:", err)
+		return entities.ScheduledPayment{}, err
+	}
+	return schedule, nil
+}
+
+// CancelSchedule stops an active schedule. Runs already made are kept.
+func (s *scheduleService) CancelSchedule(ctx context.Context, email string, id string) (entities.ScheduledPayment, error) {
+	schedule, err := s.ownedSchedule(ctx, email, id)
+	if err != nil {
+		s.logger.Errorln("Layer: schedule_services", "Method: CancelSchedule", "Error:", err)
+		return entities.ScheduledPayment{}, err
+	}
+	if schedule.Status != entities.ScheduleActive {
+		s.logger.Errorln("Layer: schedule_services", "Method: CancelSchedule", "Error:", ErrScheduleNotActive)
+		return entities.ScheduledPayment{}, ErrScheduleNotActive
+	}
+	now := time.Now().UTC()
+	err = s.scheduleRepository.CancelSchedule(schedule.ID, now, ctx)
+	if errors.Is(err, repository_schedule.ErrScheduleNotActive) {
+		s.logger.Errorln("Layer: schedule_services", "Method: CancelSchedule", "Error:", ErrScheduleNotActive)
+		return entities.ScheduledPayment{}, ErrScheduleNotActive
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: schedule_services", "Method: CancelSchedule", "Error:", err)
+		return entities.ScheduledPayment{}, err
+	}
+	schedule.Status = entities.ScheduleCancelled
+	schedule.Update_at = now
+	return schedule, nil
+}
+
+func (s *scheduleService) ListExecutions(ctx context.Context, email string, id string) ([]entities.ScheduleExecution, error) {
+	schedule, err := s.ownedSchedule(ctx, email, id)
+	if err != nil {
+		s.logger.Errorln("Layer: schedule_services", "Method: ListExecutions", "Error:", err)
+		return nil, err
+	}
+	return s.scheduleRepository.ListExecutions(schedule.ID, ctx)
+}
+
+// RunDueSchedules runs every schedule due at now and returns how many were
+// run. Schedules locked by another runner are skipped, and one that fails is
+// logged and left for the next tick without stopping the others.
+func (s *scheduleService) RunDueSchedules(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.scheduleRepository.DueSchedules(now, dueSchedulesBatch, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: schedule_services", "Method: RunDueSchedules", "Error:", err)
+		return 0, err
+	}
+	runs := 0
+	for _, schedule := range schedules {
+		lockedUntil := now.Add(scheduleLockTTL)
+		err := s.scheduleRepository.LockSchedule(schedule.ID, now, lockedUntil, ctx)
+		if errors.Is(err, repository_schedule.ErrScheduleLocked) {
+			continue
+		}
+		if err != nil {
+			s.logger.Errorln("Layer: schedule_services", "Method: RunDueSchedules", "Schedule:", schedule.ID, "Error:", err)
+			continue
+		}
+		if err := s.runSchedule(ctx, schedule, now, lockedUntil); err != nil {
+			s.logger.Errorln("Layer: schedule_services", "Method: RunDueSchedules", "Schedule:", schedule.ID, "Error:", err)
+			continue
+		}
+		runs++
+	}
+	return runs, nil
+}
+
+// runSchedule makes the transfer of one due schedule and records the
+// execution. Insufficient funds and unexpected errors are retried later, up
+// to maxScheduleAttempts; errors that retrying cannot fix fail the schedule.
+// A transfer held for review moves the schedule on: the execution waits for
+// the analysts and is never retried. The transfer is referenced by the
+// occurrence, so running it again after the records below failed to save
+// finds it already made instead of paying twice.
+func (s *scheduleService) runSchedule(ctx context.Context, schedule entities.ScheduledPayment, now time.Time, lockedUntil time.Time) error {
+	attempt := schedule.Attempts + 1
+	execution := entities.ScheduleExecution{
+		ScheduleID: schedule.ID,
+		DueAt:      schedule.DueAt,
+		Attempt:    attempt,
+		Created_at: now,
+	}
+
+	transfer, err := s.transferOf(ctx, schedule)
+	var held *TransferHeldError
+	switch {
+	case err == nil:
+		execution.Status = entities.ExecutionSucceeded
+		execution.TransferID = transfer.ID
+		advanceSchedule(&schedule)
+	case errors.Is(err, ErrTransferAlreadyMade):
+		execution.Status = entities.ExecutionSucceeded
+		advanceSchedule(&schedule)
+	case errors.As(err, &held):
+		execution.Status = entities.ExecutionHeld
+		execution.ReviewID = held.ReviewID
+		advanceSchedule(&schedule)
+	case isPermanentTransferError(err):
+		execution.Status = entities.ExecutionFailed
+		execution.Error = err.Error()
+		schedule.Status = entities.ScheduleFailed
+	case attempt < maxScheduleAttempts:
+		execution.Status = entities.ExecutionRetrying
+		execution.Error = err.Error()
+		schedule.Attempts = attempt
+		schedule.NextRunAt = now.Add(time.Duration(attempt) * scheduleRetryDelay)
+	default:
+		execution.Status = entities.ExecutionFailed
+		execution.Error = err.Error()
+		advanceSchedule(&schedule)
+	}
+	s.logger.Infoln("Layer: schedule_services", "Method: runSchedule", "Schedule:", schedule.ID, "Status:", execution.Status)
+
+	if _, err := s.scheduleRepository.CreateExecution(execution, ctx); err != nil {
+		return err
+	}
+	schedule.Update_at = now
+	return s.scheduleRepository.FinishRun(schedule, lockedUntil, ctx)
+}
+
+// TransferReleased completes the execution whose transfer an analyst
+// released.
+func (s *scheduleService) TransferReleased(ctx context.Context, review entities.ScreeningReview) error {
+	return s.scheduleRepository.ResolveExecution(review.ID, entities.ExecutionSucceeded, review.Transfer.ID, "", ctx)
+}
+
+// TransferRejected fails the execution whose transfer was rejected or could
+// not be made once released.
+func (s *scheduleService) TransferRejected(ctx context.Context, review entities.ScreeningReview) error {
+	return s.scheduleRepository.ResolveExecution(review.ID, entities.ExecutionFailed, "", ErrTransferRejected.Error(), ctx)
+}
+
+func (s *scheduleService) transferOf(ctx context.Context, schedule entities.ScheduledPayment) (entities.Transfer, error) {
+	user, err := s.userRepository.GetUser(schedule.UserID, ctx)
+	if err != nil {
+		return entities.Transfer{}, err
+	}
+	return s.transferService.CreateTransfer(ctx, user.Email, scheduleTransfer(schedule))
+}
+
+func (s *scheduleService) ownedSchedule(ctx context.Context, email string, id string) (entities.ScheduledPayment, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		return entities.ScheduledPayment{}, err
+	}
+	schedule, err := s.scheduleRepository.GetSchedule(id, ctx)
+	if errors.Is(err, repository_schedule.ErrScheduleNotFound) || (err == nil && schedule.UserID != user.ID) {
+		return entities.ScheduledPayment{}, ErrScheduleNotFound
+	}
+	if err != nil {
+		return entities.ScheduledPayment{}, err
+	}
+	return schedule, nil
+}
+
+func scheduleTransfer(schedule entities.ScheduledPayment) entities.Transfer {
+	return entities.Transfer{
+		RecipientEmail: schedule.RecipientEmail,
+		RecipientPhone: schedule.RecipientPhone,
+		RecipientDNI:   schedule.RecipientDNI,
+		Amount:         schedule.Amount,
+		Currency:       schedule.Currency,
+		Memo:           schedule.Memo,
+		Source:         entities.TransferSourceSchedule,
+		SourceID:       schedule.ID,
+		Reference:      scheduleReference(schedule),
+	}
+}
+
+// scheduleReference names the occurrence of a schedule a transfer pays.
+func scheduleReference(schedule entities.ScheduledPayment) string {
+	return "schedule:" + schedule.ID + ":" + schedule.DueAt.UTC().Format(time.RFC3339)
+}
+
+// isPermanentTransferError reports whether a transfer would fail the same
+// way on every retry. Blocks, missing KYC and restrictions only change by
+// someone acting on them, so they fail the schedule too.
+func isPermanentTransferError(err error) bool {
+	for _, permanent := range []error{
+		ErrRecipientNotFound, ErrRecipientDisabled, ErrRecipientRequired, ErrSelfTransfer,
+		ErrInvalidAmount, ErrInvalidCurrency, ErrMemoTooLong,
+		ErrTransferBlocked, ErrKYCLevelRequired, ErrUserUnderReview,
+		ErrWalletFrozen, ErrWalletBlocked, ErrCapabilityRestricted,
+		repository_user.ErrUserNotfound, repository_user.ErrDisbledUser,
+	} {
+		if errors.Is(err, permanent) {
+			return true
+		}
+	}
+	return false
+}
+
+// advanceSchedule moves a schedule past its current occurrence, completing
+// it when there is no other one.
+func advanceSchedule(schedule *entities.ScheduledPayment) {
+	schedule.Runs++
+	schedule.Attempts = 0
+	next, ok := nextOccurrence(*schedule, schedule.DueAt)
+	if !ok || (schedule.EndAt != nil && next.After(*schedule.EndAt)) || (schedule.MaxRuns > 0 && schedule.Runs >= schedule.MaxRuns) {
+		schedule.Status = entities.ScheduleCompleted
+		return
+	}
+	schedule.DueAt = next
+	schedule.NextRunAt = next
+}
+
+// firstOccurrence is StartAt, except for monthly schedules whose day of the
+// month has already passed in the month of StartAt.
+func firstOccurrence(schedule entities.ScheduledPayment) time.Time {
+	start := schedule.StartAt
+	if schedule.Frequency != entities.FrequencyMonthly {
+		return start
+	}
+	first := monthDay(start, 0, schedule.DayOfMonth)
+	if first.Before(start) {
+		first = monthDay(start, 1, schedule.DayOfMonth)
+	}
+	return first
+}
+
+// nextOccurrence returns the occurrence that follows the one due at from.
+func nextOccurrence(schedule entities.ScheduledPayment, from time.Time) (time.Time, bool) {
+	switch schedule.Frequency {
+	case entities.FrequencyDaily:
+		return from.AddDate(0, 0, schedule.Interval), true
+	case entities.FrequencyWeekly:
+		return from.AddDate(0, 0, 7*schedule.Interval), true
+	case entities.FrequencyMonthly:
+		return monthDay(from, schedule.Interval, schedule.DayOfMonth), true
+	}
+	return time.Time{}, false
+}
+
+// monthDay returns day of the month that is months after the month of t, at
+// the time of day of t. Days past the end of the month, and LastDayOfMonth,
+// fall on the last day.
+func monthDay(t time.Time, months int, day int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day == entities.LastDayOfMonth || day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+func validateSchedule(schedule entities.ScheduledPayment, now time.Time) error {
+	if schedule.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if schedule.Currency != "" && !entities.ValidCurrency(schedule.Currency) {
+		return ErrInvalidCurrency
+	}
+	if utf8.RuneCountInString(schedule.Memo) > maxMemoLength {
+		return ErrMemoTooLong
+	}
+	if schedule.StartAt.Before(now.Add(-time.Minute)) || schedule.Interval < 1 || schedule.MaxRuns < 0 {
+		return ErrInvalidSchedule
+	}
+	if schedule.EndAt != nil && schedule.EndAt.Before(schedule.StartAt) {
+		return ErrInvalidSchedule
+	}
+	switch schedule.Frequency {
+	case entities.FrequencyOnce, entities.FrequencyDaily, entities.FrequencyWeekly:
+		if schedule.DayOfMonth != 0 {
+			return ErrInvalidSchedule
+		}
+	case entities.FrequencyMonthly:
+		if schedule.DayOfMonth != entities.LastDayOfMonth && (schedule.DayOfMonth < 1 || schedule.DayOfMonth > 31) {
+			return ErrInvalidSchedule
+		}
+	default:
+		return ErrInvalidSchedule
+	}
+	return nil
+}
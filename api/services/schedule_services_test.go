@@ -0,0 +1,431 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_interest "my_wallet/api/respository/interest"
+	repository_lease "my_wallet/api/respository/lease"
+	repository_sanctions "my_wallet/api/respository/sanctions"
+	repository_schedule "my_wallet/api/respository/schedule"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	testScenarios := []struct {
+		testName string
+		schedule entities.ScheduledPayment
+		from     time.Time
+		expected time.Time
+		ok       bool
+	}{
+		{
+			testName: "daily every two days",
+			schedule: entities.ScheduledPayment{Frequency: entities.FrequencyDaily, Interval: 2},
+			from:     time.Date(2024, 2, 28, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			testName: "weekly",
+			schedule: entities.ScheduledPayment{Frequency: entities.FrequencyWeekly, Interval: 1},
+			from:     time.Date(2024, 12, 30, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			testName: "monthly on the 31st falls on the last day of February",
+			schedule: entities.ScheduledPayment{Frequency: entities.FrequencyMonthly, Interval: 1, DayOfMonth: 31},
+			from:     time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			testName: "monthly on the 31st comes back after a short month",
+			schedule: entities.ScheduledPayment{Frequency: entities.FrequencyMonthly, Interval: 1, DayOfMonth: 31},
+			from:     time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			testName: "end of month every three months",
+			schedule: entities.ScheduledPayment{Frequency: entities.FrequencyMonthly, Interval: 3, DayOfMonth: entities.LastDayOfMonth},
+			from:     time.Date(2024, 11, 30, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			testName: "once",
+			schedule: entities.ScheduledPayment{Frequency: entities.FrequencyOnce, Interval: 1},
+			from:     time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+			ok:       false,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			result, ok := nextOccurrence(tt.schedule, tt.from)
+
+			// Assert
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCreateScheduleService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com", KYCLevel: entities.KYCBasic}
+	landlord := entities.User{ID: "u2", Email: "landlord@gmail.com"}
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+
+	testScenarios := []struct {
+		testName       string
+		schedule       entities.ScheduledPayment
+		configureMock  func(*userServiceMock, *scheduleRepositoryMock)
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			testName: "TestCreateScheduleService",
+			schedule: entities.ScheduledPayment{RecipientEmail: "landlord@gmail.com", Amount: 100000, Frequency: entities.FrequencyMonthly, DayOfMonth: entities.LastDayOfMonth, StartAt: start},
+			configureMock: func(u *userServiceMock, r *scheduleRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "landlord@gmail.com").Return(landlord, nil)
+				r.On("CreateSchedule", mock.Anything, mock.MatchedBy(func(s entities.ScheduledPayment) bool {
+					return s.UserID == "u1" && s.Interval == 1 && !s.NextRunAt.Before(start) && s.NextRunAt.AddDate(0, 0, 1).Day() == 1
+				})).Return(entities.ScheduledPayment{ID: "s1", Status: entities.ScheduleActive}, nil)
+			},
+			expectedStatus: entities.ScheduleActive,
+		},
+		{
+			testName:      "TestInvalidFrequency",
+			schedule:      entities.ScheduledPayment{RecipientEmail: "landlord@gmail.com", Amount: 100000, Frequency: "yearly", StartAt: start},
+			expectedError: ErrInvalidSchedule,
+		},
+		{
+			testName:      "TestStartInThePast",
+			schedule:      entities.ScheduledPayment{RecipientEmail: "landlord@gmail.com", Amount: 100000, Frequency: entities.FrequencyOnce, StartAt: start.AddDate(0, 0, -3)},
+			expectedError: ErrInvalidSchedule,
+		},
+		{
+			testName:      "TestDayOfMonthOnWeekly",
+			schedule:      entities.ScheduledPayment{RecipientEmail: "landlord@gmail.com", Amount: 100000, Frequency: entities.FrequencyWeekly, DayOfMonth: 3, StartAt: start},
+			expectedError: ErrInvalidSchedule,
+		},
+		{
+			testName:      "TestSelfSchedule",
+			schedule:      entities.ScheduledPayment{RecipientEmail: "alexer@gmail.com", Amount: 100000, Frequency: entities.FrequencyOnce, StartAt: start},
+			expectedError: ErrSelfTransfer,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			schedules := &scheduleRepositoryMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(users, schedules)
+			}
+			service := NewScheduleService(users, schedules, &transferServiceMock{}, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateSchedule(context.Background(), "alexer@gmail.com", tt.schedule)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+		})
+	}
+}
+
+func TestCancelScheduleService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	schedule := entities.ScheduledPayment{ID: "s1", UserID: "u1", Status: entities.ScheduleActive}
+
+	testScenarios := []struct {
+		testName       string
+		cancelError    error
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			testName:       "TestCancelActiveSchedule",
+			expectedStatus: entities.ScheduleCancelled,
+		},
+		{
+			testName:      "TestScheduleEndedMeanwhile",
+			cancelError:   repository_schedule.ErrScheduleNotActive,
+			expectedError: ErrScheduleNotActive,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			schedules := &scheduleRepositoryMock{}
+			schedules.On("GetSchedule", mock.Anything, "s1").Return(schedule, nil)
+			schedules.On("CancelSchedule", mock.Anything, "s1").Return(tt.cancelError)
+			service := NewScheduleService(users, schedules, &transferServiceMock{}, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CancelSchedule(context.Background(), "alexer@gmail.com", "s1")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			schedules.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRunDueSchedulesService(t *testing.T) {
+	now := time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC)
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	rent := entities.ScheduledPayment{
+		ID: "s1", UserID: "u1", RecipientEmail: "landlord@gmail.com", Amount: 100000,
+		Frequency: entities.FrequencyMonthly, Interval: 1, DayOfMonth: entities.LastDayOfMonth,
+		Status: entities.ScheduleActive, DueAt: now, NextRunAt: now,
+	}
+	retried := rent
+	retried.Attempts = maxScheduleAttempts - 1
+
+	testScenarios := []struct {
+		testName          string
+		schedule          entities.ScheduledPayment
+		transferError     error
+		expectedExecution string
+		expectedSchedule  func(entities.ScheduledPayment) bool
+	}{
+		{
+			testName:          "TestRunSucceeds",
+			schedule:          rent,
+			expectedExecution: entities.ExecutionSucceeded,
+			expectedSchedule: func(s entities.ScheduledPayment) bool {
+				return s.Runs == 1 && s.NextRunAt.Equal(time.Date(2024, 4, 30, 9, 0, 0, 0, time.UTC))
+			},
+		},
+		{
+			testName:          "TestInsufficientFundsIsRetried",
+			schedule:          rent,
+			transferError:     ErrInsufficientFunds,
+			expectedExecution: entities.ExecutionRetrying,
+			expectedSchedule: func(s entities.ScheduledPayment) bool {
+				return s.Attempts == 1 && s.NextRunAt.Equal(now.Add(scheduleRetryDelay)) && s.DueAt.Equal(now)
+			},
+		},
+		{
+			testName:          "TestLastAttemptSkipsOccurrence",
+			schedule:          retried,
+			transferError:     ErrInsufficientFunds,
+			expectedExecution: entities.ExecutionFailed,
+			expectedSchedule: func(s entities.ScheduledPayment) bool {
+				return s.Attempts == 0 && s.Runs == 1 && s.Status == entities.ScheduleActive && s.DueAt.Month() == time.April
+			},
+		},
+		{
+			testName:          "TestRecipientGoneFailsSchedule",
+			schedule:          rent,
+			transferError:     ErrRecipientDisabled,
+			expectedExecution: entities.ExecutionFailed,
+			expectedSchedule: func(s entities.ScheduledPayment) bool {
+				return s.Status == entities.ScheduleFailed
+			},
+		},
+		{
+			testName:          "TestBlockedTransferFailsSchedule",
+			schedule:          rent,
+			transferError:     ErrTransferBlocked,
+			expectedExecution: entities.ExecutionFailed,
+			expectedSchedule: func(s entities.ScheduledPayment) bool {
+				return s.Status == entities.ScheduleFailed
+			},
+		},
+		{
+			testName:          "TestKYCRequiredFailsSchedule",
+			schedule:          rent,
+			transferError:     ErrKYCLevelRequired,
+			expectedExecution: entities.ExecutionFailed,
+			expectedSchedule: func(s entities.ScheduledPayment) bool {
+				return s.Status == entities.ScheduleFailed
+			},
+		},
+		{
+			testName:          "TestFrozenWalletFailsSchedule",
+			schedule:          rent,
+			transferError:     ErrWalletFrozen,
+			expectedExecution: entities.ExecutionFailed,
+			expectedSchedule: func(s entities.ScheduledPayment) bool {
+				return s.Status == entities.ScheduleFailed
+			},
+		},
+		{
+			testName:          "TestRestrictedWalletFailsSchedule",
+			schedule:          rent,
+			transferError:     ErrCapabilityRestricted,
+			expectedExecution: entities.ExecutionFailed,
+			expectedSchedule: func(s entities.ScheduledPayment) bool {
+				return s.Status == entities.ScheduleFailed
+			},
+		},
+		{
+			testName:          "TestTransferAlreadyMadeAdvancesSchedule",
+			schedule:          rent,
+			transferError:     ErrTransferAlreadyMade,
+			expectedExecution: entities.ExecutionSucceeded,
+			expectedSchedule: func(s entities.ScheduledPayment) bool {
+				return s.Attempts == 0 && s.Runs == 1 && s.Status == entities.ScheduleActive && s.DueAt.Month() == time.April
+			},
+		},
+		{
+			testName:          "TestHeldTransferIsNotRetried",
+			schedule:          rent,
+			transferError:     &TransferHeldError{ReviewID: "r1"},
+			expectedExecution: entities.ExecutionHeld,
+			expectedSchedule: func(s entities.ScheduledPayment) bool {
+				return s.Attempts == 0 && s.Runs == 1 && s.Status == entities.ScheduleActive && s.DueAt.Month() == time.April
+			},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUser", mock.Anything, "u1").Return(user, nil)
+			transfers := &transferServiceMock{}
+			transfers.On("CreateTransfer", mock.Anything, "alexer@gmail.com", mock.MatchedBy(func(tr entities.Transfer) bool {
+				return tr.Reference == "schedule:s1:2024-03-31T09:00:00Z"
+			})).Return(entities.Transfer{ID: "t1"}, tt.transferError)
+			schedules := &scheduleRepositoryMock{}
+			schedules.On("DueSchedules", mock.Anything, now, int64(dueSchedulesBatch)).Return([]entities.ScheduledPayment{tt.schedule}, nil)
+			schedules.On("LockSchedule", mock.Anything, "s1", now, now.Add(scheduleLockTTL)).Return(nil)
+			schedules.On("CreateExecution", mock.Anything, mock.MatchedBy(func(e entities.ScheduleExecution) bool {
+				return e.Status == tt.expectedExecution
+			})).Return(entities.ScheduleExecution{}, nil)
+			schedules.On("FinishRun", mock.Anything, mock.MatchedBy(tt.expectedSchedule), now.Add(scheduleLockTTL)).Return(nil)
+			service := NewScheduleService(users, schedules, transfers, logrus.StandardLogger(), context.Background())
+
+			// Act
+			runs, err := service.RunDueSchedules(context.Background(), now)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 1, runs)
+			schedules.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRunDueSchedulesSkipsLocked(t *testing.T) {
+	now := time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC)
+
+	// Prepare
+	schedules := &scheduleRepositoryMock{}
+	schedules.On("DueSchedules", mock.Anything, now, int64(dueSchedulesBatch)).Return([]entities.ScheduledPayment{{ID: "s1"}}, nil)
+	schedules.On("LockSchedule", mock.Anything, "s1", now, now.Add(scheduleLockTTL)).Return(repository_schedule.ErrScheduleLocked)
+	service := NewScheduleService(&userServiceMock{}, schedules, &transferServiceMock{}, logrus.StandardLogger(), context.Background())
+
+	// Act
+	runs, err := service.RunDueSchedules(context.Background(), now)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 0, runs)
+}
+
+func TestRunDueSchedulesContinuesAfterError(t *testing.T) {
+	now := time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC)
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	first := entities.ScheduledPayment{ID: "s1", UserID: "u1", RecipientEmail: "landlord@gmail.com", Amount: 100, Frequency: entities.FrequencyOnce, Status: entities.ScheduleActive, DueAt: now, NextRunAt: now}
+	second := first
+	second.ID = "s2"
+
+	// Prepare
+	users := &userServiceMock{}
+	users.On("GetUser", mock.Anything, "u1").Return(user, nil)
+	transfers := &transferServiceMock{}
+	transfers.On("CreateTransfer", mock.Anything, "alexer@gmail.com", mock.Anything).Return(entities.Transfer{ID: "t1"}, nil)
+	schedules := &scheduleRepositoryMock{}
+	schedules.On("DueSchedules", mock.Anything, now, int64(dueSchedulesBatch)).Return([]entities.ScheduledPayment{first, second}, nil)
+	schedules.On("LockSchedule", mock.Anything, "s1", now, now.Add(scheduleLockTTL)).Return(errors.New("connection reset"))
+	schedules.On("LockSchedule", mock.Anything, "s2", now, now.Add(scheduleLockTTL)).Return(nil)
+	schedules.On("CreateExecution", mock.Anything, mock.Anything).Return(entities.ScheduleExecution{}, nil)
+	schedules.On("FinishRun", mock.Anything, mock.Anything, now.Add(scheduleLockTTL)).Return(nil)
+	service := NewScheduleService(users, schedules, transfers, logrus.StandardLogger(), context.Background())
+
+	// Act
+	runs, err := service.RunDueSchedules(context.Background(), now)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, runs)
+	schedules.AssertExpectations(t)
+}
+
+func TestSchedulerTick(t *testing.T) {
+	now := time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC)
+
+	testScenarios := []struct {
+		testName     string
+		leaseError   error
+		renewError   error
+		expected     bool
+		expectedJobs bool
+	}{
+		{testName: "leader runs the due schedules", leaseError: nil, expected: true, expectedJobs: true},
+		{testName: "follower does nothing", leaseError: repository_lease.ErrLeaseHeld, expected: false},
+		{testName: "lease error does nothing", leaseError: errors.New("connection reset"), expected: false},
+		{testName: "lease lost during the tick stops it", renewError: repository_lease.ErrLeaseHeld, expected: true},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			leases := &leaseRepositoryMock{}
+			leases.On("AcquireLease", mock.Anything, schedulerLease, "replica-1", now, 3*time.Minute).Return(tt.leaseError).Once()
+			leases.On("AcquireLease", mock.Anything, schedulerLease, "replica-1", mock.AnythingOfType("time.Time"), 3*time.Minute).Return(tt.renewError)
+			schedules := &scheduleRepositoryMock{}
+			schedules.On("DueSchedules", mock.Anything, now, int64(dueSchedulesBatch)).Return([]entities.ScheduledPayment{}, nil)
+			service := NewScheduleService(&userServiceMock{}, schedules, &transferServiceMock{}, logrus.StandardLogger(), context.Background())
+			pockets := &pocketRepositoryMock{}
+			pockets.On("DueAutoSaves", mock.Anything, now, int64(dueAutoSavesBatch)).Return([]entities.Pocket{}, nil)
+			pocketService := NewPocketService(&userServiceMock{}, &walletRepositoryMock{}, &ledgerRepositoryMock{}, pockets, nil, nil, logrus.StandardLogger(), context.Background())
+			insightsService := NewInsightsService(&userServiceMock{}, &walletRepositoryMock{}, &insightsRepositoryMock{}, false, nil, logrus.StandardLogger(), context.Background())
+			sanctions := &sanctionsRepositoryMock{}
+			sanctions.On("ScreenedVersion", mock.Anything).Return("v1", nil)
+			sanctionsService := NewSanctionsService(&userServiceMock{}, sanctions, repository_sanctions.NewStaticSanctionsList(nil, "v1"), 0.9, logrus.StandardLogger(), context.Background())
+			interest := &interestRepositoryMock{}
+			interest.On("GetState", mock.Anything).Return(repository_interest.InterestState{LastAccruedDay: time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC), LastPaidMonth: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}, nil)
+			interestService := NewInterestService(&userServiceMock{}, &walletRepositoryMock{}, pockets, &ledgerRepositoryMock{}, interest, nil, nil, nil, logrus.StandardLogger(), context.Background())
+			scheduler := NewScheduler(leases, service, pocketService, insightsService, sanctionsService, interestService, "replica-1", time.Minute, logrus.StandardLogger())
+
+			// Act
+			result := scheduler.Tick(context.Background(), now)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+			if tt.expected {
+				schedules.AssertCalled(t, "DueSchedules", mock.Anything, now, int64(dueSchedulesBatch))
+			} else {
+				schedules.AssertNotCalled(t, "DueSchedules", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.expectedJobs {
+				leases.AssertNumberOfCalls(t, "AcquireLease", 5)
+				pockets.AssertCalled(t, "DueAutoSaves", mock.Anything, now, int64(dueAutoSavesBatch))
+				interest.AssertCalled(t, "GetState", mock.Anything)
+			} else {
+				pockets.AssertNotCalled(t, "DueAutoSaves", mock.Anything, mock.Anything, mock.Anything)
+				interest.AssertNotCalled(t, "GetState", mock.Anything)
+			}
+		})
+	}
+}
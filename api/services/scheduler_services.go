@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	repository_lease "my_wallet/api/respository/lease"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// schedulerLease names the lease document of the scheduler.
+const schedulerLease = "scheduler"
+
+// Scheduler runs the background jobs on every tick. Every replica runs one,
+// but only the holder of the scheduler lease does any work; another replica
+// takes over when the holder stops renewing it.
+type Scheduler struct {
+	leaseRepository  repository_lease.LeaseRepository
+	scheduleService  ScheduleService
+	pocketService    PocketService
+	insightsService  InsightsService
+	sanctionsService SanctionsService
+	interestService  InterestService
+	holder           string
+	interval         time.Duration
+	logger           logrus.FieldLogger
+}
+
+func NewScheduler(leaseRepo repository_lease.LeaseRepository, scheduleService ScheduleService, pocketService PocketService, insightsService InsightsService, sanctionsService SanctionsService, interestService InterestService, holder string, interval time.Duration, logger logrus.FieldLogger) *Scheduler {
+	return &Scheduler{
+		leaseRepository:  leaseRepo,
+		scheduleService:  scheduleService,
+		pocketService:    pocketService,
+		insightsService:  insightsService,
+		sanctionsService: sanctionsService,
+		interestService:  interestService,
+		holder:           holder,
+		interval:         interval,
+		logger:           logger,
+	}
+}
+
+// Run ticks until ctx is done, then gives the lease up.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.Tick(ctx, time.Now().UTC())
+		select {
+		case <-ctx.Done():
+			if err := s.leaseRepository.ReleaseLease(schedulerLease, s.holder, context.Background()); err != nil {
+				s.logger.Errorln("Layer: scheduler_services", "Method: Run", "Error:", err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick runs the background jobs if this replica holds, or can take, the
+// lease, and reports whether it did. The lease is renewed before every job
+// after the first, and the tick stops as soon as it cannot be, so a slow tick
+// never overlaps with the jobs of a replica that took over.
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) bool {
+	err := s.leaseRepository.AcquireLease(schedulerLease, s.holder, now, 3*s.interval, ctx)
+	if errors.Is(err, repository_lease.ErrLeaseHeld) {
+		return false
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: scheduler_services", "Method: Tick", "Error:", err)
+		return false
+	}
+	jobs := []struct {
+		label string
+		run   func() (int, error)
+	}{
+		{"Runs:", func() (int, error) { return s.scheduleService.RunDueSchedules(ctx, now) }},
+		{"Saves:", func() (int, error) { return s.pocketService.RunAutoSaves(ctx, now) }},
+		{"Rolled up days:", func() (int, error) { return s.insightsService.RollupDays(ctx, now) }},
+		{"Held by sanctions:", func() (int, error) { return s.sanctionsService.RescreenUsers(ctx) }},
+		{"Interest days accrued:", func() (int, error) { return s.interestService.RunInterest(ctx, now) }},
+	}
+	for i, job := range jobs {
+		if i > 0 && !s.renewLease(ctx) {
+			return true
+		}
+		done, err := job.run()
+		if err != nil {
+			s.logger.Errorln("Layer: scheduler_services", "Method: Tick", "Error:", err)
+		}
+		if done > 0 {
+			s.logger.Infoln("Layer: scheduler_services", "Method: Tick", job.label, done)
+		}
+	}
+	return true
+}
+
+// renewLease extends the lease from the current time and reports whether
+// this replica still holds it.
+func (s *Scheduler) renewLease(ctx context.Context) bool {
+	err := s.leaseRepository.AcquireLease(schedulerLease, s.holder, time.Now().UTC(), 3*s.interval, ctx)
+	if errors.Is(err, repository_lease.ErrLeaseHeld) {
+		s.logger.Infoln("Layer: scheduler_services", "Method: Tick", "Lease taken by another replica")
+		return false
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: scheduler_services", "Method: Tick", "Error:", err)
+		return false
+	}
+	return true
+}
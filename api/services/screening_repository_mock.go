@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type screeningRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *screeningRepositoryMock) KnownDevice(userID string, deviceID string, ctx context.Context) (bool, error) {
+	r := m.Called(ctx, userID, deviceID)
+	return r.Bool(0), r.Error(1)
+}
+
+func (m *screeningRepositoryMock) RememberDevice(userID string, deviceID string, ctx context.Context) error {
+	r := m.Called(ctx, userID, deviceID)
+	return r.Error(0)
+}
+
+func (m *screeningRepositoryMock) RecentOutgoing(walletID string, since time.Time, ctx context.Context) ([]entities.Transaction, error) {
+	r := m.Called(ctx, walletID, since)
+	return r.Get(0).([]entities.Transaction), r.Error(1)
+}
+
+func (m *screeningRepositoryMock) HasPaid(walletID string, counterpartyUserID string, ctx context.Context) (bool, error) {
+	r := m.Called(ctx, walletID, counterpartyUserID)
+	return r.Bool(0), r.Error(1)
+}
+
+func (m *screeningRepositoryMock) CreateReview(review entities.ScreeningReview, ctx context.Context) (entities.ScreeningReview, error) {
+	r := m.Called(ctx, review)
+	return r.Get(0).(entities.ScreeningReview), r.Error(1)
+}
+
+func (m *screeningRepositoryMock) GetReview(id string, ctx context.Context) (entities.ScreeningReview, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.ScreeningReview), r.Error(1)
+}
+
+func (m *screeningRepositoryMock) ListReviews(status string, limit int64, ctx context.Context) ([]entities.ScreeningReview, error) {
+	r := m.Called(ctx, status, limit)
+	return r.Get(0).([]entities.ScreeningReview), r.Error(1)
+}
+
+func (m *screeningRepositoryMock) UpdateReviewStatus(id string, from string, to string, analyst string, note string, ctx context.Context) (entities.ScreeningReview, error) {
+	r := m.Called(ctx, id, from, to, analyst, note)
+	return r.Get(0).(entities.ScreeningReview), r.Error(1)
+}
+
+type reviewedTransfererMock struct {
+	mock.Mock
+}
+
+func (m *reviewedTransfererMock) ExecuteReviewedTransfer(ctx context.Context, senderEmail string, transfer entities.Transfer) (entities.Transfer, error) {
+	r := m.Called(ctx, senderEmail, transfer)
+	return r.Get(0).(entities.Transfer), r.Error(1)
+}
+
+type heldTransferSourceMock struct {
+	mock.Mock
+}
+
+func (m *heldTransferSourceMock) TransferReleased(ctx context.Context, review entities.ScreeningReview) error {
+	return m.Called(ctx, review).Error(0)
+}
+
+func (m *heldTransferSourceMock) TransferRejected(ctx context.Context, review entities.ScreeningReview) error {
+	return m.Called(ctx, review).Error(0)
+}
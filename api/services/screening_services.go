@@ -0,0 +1,456 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_screening "my_wallet/api/respository/screening"
+	repository_user "my_wallet/api/respository/user"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const maxScreeningReviews = 100
+
+var screeningActions = map[string]bool{
+	entities.ScreeningAllow: true,
+	entities.ScreeningHold:  true,
+	entities.ScreeningBlock: true,
+}
+
+// TransferHeldError tells that a transfer was held for review and which
+// review holds it.
+type TransferHeldError struct {
+	ReviewID string
+}
+
+func (e *TransferHeldError) Error() string {
+	return ErrTransferHeld.Error()
+}
+
+// Is lets errors.Is match the details with ErrTransferHeld.
+func (e *TransferHeldError) Is(target error) bool {
+	return target == ErrTransferHeld
+}
+
+// Screener runs the fraud and AML rules on a transaction before it executes.
+type Screener interface {
+	Screen(ctx context.Context, input entities.ScreeningInput) (entities.ScreeningResult, error)
+	Hold(ctx context.Context, input entities.ScreeningInput, result entities.ScreeningResult, transfer entities.Transfer) (entities.ScreeningReview, error)
+	HoldWithdrawal(ctx context.Context, input entities.ScreeningInput, result entities.ScreeningResult, withdrawal entities.Withdrawal) (entities.ScreeningReview, error)
+	Completed(ctx context.Context, input entities.ScreeningInput)
+}
+
+// HeldTransferSource completes what a held transfer was made for, a
+// scheduled payment or a payment request, once the analysts decide on it.
+type HeldTransferSource interface {
+	// TransferReleased is told the released transfer was made.
+	TransferReleased(ctx context.Context, review entities.ScreeningReview) error
+	// TransferRejected is told it never will be.
+	TransferRejected(ctx context.Context, review entities.ScreeningReview) error
+}
+
+// ReviewedTransferer executes transfers an analyst released, without
+// screening them again.
+type ReviewedTransferer interface {
+	ExecuteReviewedTransfer(ctx context.Context, senderEmail string, transfer entities.Transfer) (entities.Transfer, error)
+}
+
+// ReviewedWithdrawer executes withdrawals an analyst released, without
+// screening them again.
+type ReviewedWithdrawer interface {
+	ExecuteReviewedWithdrawal(ctx context.Context, email string, withdrawal entities.Withdrawal) (entities.Withdrawal, error)
+}
+
+type ScreeningService interface {
+	ListRules(ctx context.Context, email string) ([]entities.ScreeningRule, error)
+	SaveRule(ctx context.Context, email string, rule entities.ScreeningRule) (entities.ScreeningRule, error)
+	ListReviews(ctx context.Context, email string, status string) ([]entities.ScreeningReview, error)
+	ReleaseReview(ctx context.Context, email string, id string, note string) (entities.ScreeningReview, error)
+	RejectReview(ctx context.Context, email string, id string, note string) (entities.ScreeningReview, error)
+}
+
+type screeningEngine struct {
+	screeningRepository repository_screening.ScreeningRepository
+	rules               repository_screening.RuleSource
+	logger              logrus.FieldLogger
+}
+
+func NewScreeningEngine(screeningRepo repository_screening.ScreeningRepository, rules repository_screening.RuleSource, logger logrus.FieldLogger) *screeningEngine {
+	return &screeningEngine{
+		screeningRepository: screeningRepo,
+		rules:               rules,
+		logger:              logger,
+	}
+}
+
+// Screen runs every enabled rule on the transaction. The result is the most
+// severe action of the rules that matched, allow when none did, and lists
+// all of them for the analysts.
+func (e *screeningEngine) Screen(ctx context.Context, input entities.ScreeningInput) (entities.ScreeningResult, error) {
+	rules, err := e.rules.Rules(ctx)
+	if err != nil {
+		e.logger.Errorln("Layer: screening_services", "Method: Screen", "Error:", err)
+		return entities.ScreeningResult{}, err
+	}
+	facts := &screeningFacts{repo: e.screeningRepository, input: input, ctx: ctx, now: time.Now().UTC()}
+	result := entities.ScreeningResult{Action: entities.ScreeningAllow, Rules: []string{}}
+	for _, rule := range rules {
+		if !rule.Enabled || (rule.Currency != "" && rule.Currency != input.Currency) {
+			continue
+		}
+		matched, err := matchRule(rule, facts)
+		if err != nil {
+			e.logger.Errorln("Layer: screening_services", "Method: Screen", "Error:", err)
+			return entities.ScreeningResult{}, err
+		}
+		if !matched {
+			continue
+		}
+		result.Rules = append(result.Rules, rule.ID)
+		if entities.MoreSevere(rule.Action, result.Action) {
+			result.Action = rule.Action
+		}
+	}
+	if result.Action != entities.ScreeningAllow {
+		e.logger.Infoln("Layer: screening_services", "Method: Screen", "User:", input.UserID, "Action:", result.Action, "Rules:", result.Rules)
+	}
+	return result, nil
+}
+
+// Hold puts the transfer in the review queue.
+func (e *screeningEngine) Hold(ctx context.Context, input entities.ScreeningInput, result entities.ScreeningResult, transfer entities.Transfer) (entities.ScreeningReview, error) {
+	return e.hold(ctx, input, result, entities.ScreeningReview{Transfer: transfer})
+}
+
+// HoldWithdrawal puts the withdrawal in the review queue.
+func (e *screeningEngine) HoldWithdrawal(ctx context.Context, input entities.ScreeningInput, result entities.ScreeningResult, withdrawal entities.Withdrawal) (entities.ScreeningReview, error) {
+	return e.hold(ctx, input, result, entities.ScreeningReview{Withdrawal: &withdrawal})
+}
+
+func (e *screeningEngine) hold(ctx context.Context, input entities.ScreeningInput, result entities.ScreeningResult, review entities.ScreeningReview) (entities.ScreeningReview, error) {
+	review.UserID = input.UserID
+	review.Operation = input.Operation
+	review.Result = result
+	review.Status = entities.ReviewPending
+	review.Created_at = time.Now().UTC()
+	review, err := e.screeningRepository.CreateReview(review, ctx)
+	if err != nil {
+		e.logger.Errorln("Layer: screening_services", "Method: Hold", "Error:", err)
+		return entities.ScreeningReview{}, err
+	}
+	return review, nil
+}
+
+// Completed records what the rules need to know about an executed
+// transaction. Failures are only logged: at worst the next transaction from
+// the device is screened as new.
+func (e *screeningEngine) Completed(ctx context.Context, input entities.ScreeningInput) {
+	if input.DeviceID == "" {
+		return
+	}
+	if err := e.screeningRepository.RememberDevice(input.UserID, input.DeviceID, ctx); err != nil {
+		e.logger.Errorln("Layer: screening_services", "Method: Completed", "Error:", err)
+	}
+}
+
+// screeningFacts loads what the rules look at once, and only when a rule
+// needs it.
+type screeningFacts struct {
+	repo   repository_screening.ScreeningRepository
+	input  entities.ScreeningInput
+	ctx    context.Context
+	now    time.Time
+	known  *bool
+	paid   *bool
+	since  time.Time
+	recent []entities.Transaction
+}
+
+func (f *screeningFacts) knownDevice() (bool, error) {
+	if f.known == nil {
+		known, err := f.repo.KnownDevice(f.input.UserID, f.input.DeviceID, f.ctx)
+		if err != nil {
+			return false, err
+		}
+		f.known = &known
+	}
+	return *f.known, nil
+}
+
+func (f *screeningFacts) hasPaid() (bool, error) {
+	if f.paid == nil {
+		paid, err := f.repo.HasPaid(f.input.WalletID, f.input.CounterpartyUserID, f.ctx)
+		if err != nil {
+			return false, err
+		}
+		f.paid = &paid
+	}
+	return *f.paid, nil
+}
+
+// recentOutgoing returns the transfers sent from the wallet within the last
+// minutes. The longest window asked for so far is loaded and narrowed down.
+func (f *screeningFacts) recentOutgoing(minutes int) ([]entities.Transaction, error) {
+	since := f.now.Add(-time.Duration(minutes) * time.Minute)
+	if f.recent == nil || since.Before(f.since) {
+		recent, err := f.repo.RecentOutgoing(f.input.WalletID, since, f.ctx)
+		if err != nil {
+			return nil, err
+		}
+		f.recent, f.since = recent, since
+	}
+	var transactions []entities.Transaction
+	for _, transaction := range f.recent {
+		if !transaction.Created_at.Before(since) {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions, nil
+}
+
+// matchRule reports whether the rule matches the transaction of the facts.
+func matchRule(rule entities.ScreeningRule, facts *screeningFacts) (bool, error) {
+	input := facts.input
+	switch rule.Kind {
+	case entities.RuleAmountAbove:
+		return input.Amount > rule.Threshold, nil
+	case entities.RuleNewDevice:
+		if input.DeviceID == "" || input.Amount <= rule.Threshold {
+			return false, nil
+		}
+		known, err := facts.knownDevice()
+		return !known, err
+	case entities.RuleNewCounterparty:
+		if input.CounterpartyUserID == "" || input.Amount <= rule.Threshold {
+			return false, nil
+		}
+		paid, err := facts.hasPaid()
+		return !paid, err
+	case entities.RuleRapidSuccession:
+		recent, err := facts.recentOutgoing(rule.WindowMinutes)
+		if err != nil {
+			return false, err
+		}
+		count := 1
+		for _, transaction := range recent {
+			if rule.Currency == "" || transaction.Currency == rule.Currency {
+				count++
+			}
+		}
+		return count >= rule.Count, nil
+	case entities.RuleStructuring:
+		if input.Amount >= rule.Threshold {
+			return false, nil
+		}
+		recent, err := facts.recentOutgoing(rule.WindowMinutes)
+		if err != nil {
+			return false, err
+		}
+		count, total := 1, input.Amount
+		for _, transaction := range recent {
+			if transaction.Currency == input.Currency && transaction.Amount < rule.Threshold {
+				count++
+				total += transaction.Amount
+			}
+		}
+		return count >= rule.Count && total >= rule.Threshold, nil
+	}
+	return false, nil
+}
+
+// validateScreeningRule checks that the rule has what its kind looks at.
+func validateScreeningRule(rule entities.ScreeningRule) error {
+	if rule.ID == "" || rule.Name == "" || !screeningActions[rule.Action] || rule.Threshold < 0 {
+		return ErrInvalidScreeningRule
+	}
+	if rule.Currency != "" && !entities.ValidCurrency(rule.Currency) {
+		return ErrInvalidScreeningRule
+	}
+	switch rule.Kind {
+	case entities.RuleAmountAbove:
+		if rule.Threshold <= 0 {
+			return ErrInvalidScreeningRule
+		}
+	case entities.RuleNewDevice, entities.RuleNewCounterparty:
+	case entities.RuleRapidSuccession:
+		if rule.Count < 2 || rule.WindowMinutes <= 0 {
+			return ErrInvalidScreeningRule
+		}
+	case entities.RuleStructuring:
+		if rule.Threshold <= 0 || rule.Count < 2 || rule.WindowMinutes <= 0 {
+			return ErrInvalidScreeningRule
+		}
+	default:
+		return ErrInvalidScreeningRule
+	}
+	return nil
+}
+
+type screeningService struct {
+	ctx                 context.Context
+	userRepository      repository_user.UserRepository
+	screeningRepository repository_screening.ScreeningRepository
+	rules               repository_screening.RuleSource
+	transferer          ReviewedTransferer
+	withdrawer          ReviewedWithdrawer
+	sources             map[string]HeldTransferSource
+	logger              logrus.FieldLogger
+}
+
+func NewScreeningService(userRepo repository_user.UserRepository, screeningRepo repository_screening.ScreeningRepository, rules repository_screening.RuleSource, transferer ReviewedTransferer, withdrawer ReviewedWithdrawer, sources map[string]HeldTransferSource, logger logrus.FieldLogger, ctx context.Context) *screeningService {
+	return &screeningService{
+		ctx:                 ctx,
+		userRepository:      userRepo,
+		screeningRepository: screeningRepo,
+		rules:               rules,
+		transferer:          transferer,
+		withdrawer:          withdrawer,
+		sources:             sources,
+		logger:              logger,
+	}
+}
+
+// ListRules returns the screening rules in force, for analysts.
+func (s *screeningService) ListRules(ctx context.Context, email string) ([]entities.ScreeningRule, error) {
+	if _, err := requireAnalyst(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: ListRules", "Error:", err)
+		return nil, err
+	}
+	rules, err := s.rules.Rules(ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: ListRules", "Error:", err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SaveRule creates or replaces a screening rule. Only rules kept in Mongo
+// can be changed; rules loaded from a file change with the file.
+func (s *screeningService) SaveRule(ctx context.Context, email string, rule entities.ScreeningRule) (entities.ScreeningRule, error) {
+	if _, err := requireAnalyst(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: SaveRule", "Error:", err)
+		return entities.ScreeningRule{}, err
+	}
+	if err := validateScreeningRule(rule); err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: SaveRule", "Error:", err)
+		return entities.ScreeningRule{}, err
+	}
+	rule, err := s.rules.SaveRule(rule, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: SaveRule", "Error:", err)
+		if errors.Is(err, repository_screening.ErrRulesReadOnly) {
+			return entities.ScreeningRule{}, ErrScreeningRulesReadOnly
+		}
+		return entities.ScreeningRule{}, err
+	}
+	return rule, nil
+}
+
+// ListReviews returns the reviews in a status, pending ones by default,
+// oldest first.
+func (s *screeningService) ListReviews(ctx context.Context, email string, status string) ([]entities.ScreeningReview, error) {
+	if _, err := requireAnalyst(ctx, s.userRepository, email); err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: ListReviews", "Error:", err)
+		return nil, err
+	}
+	if status == "" {
+		status = entities.ReviewPending
+	}
+	reviews, err := s.screeningRepository.ListReviews(status, maxScreeningReviews, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: ListReviews", "Error:", err)
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// ReleaseReview lets an analyst release a held transfer or withdrawal, which
+// executes right away. If it cannot, the review is marked failed with the
+// reason.
+// Either way, the schedule or payment request the transfer was made for is
+// completed.
+func (s *screeningService) ReleaseReview(ctx context.Context, email string, id string, note string) (entities.ScreeningReview, error) {
+	analyst, err := requireAnalyst(ctx, s.userRepository, email)
+	if err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: ReleaseReview", "Error:", err)
+		return entities.ScreeningReview{}, err
+	}
+	review, err := s.screeningRepository.UpdateReviewStatus(id, entities.ReviewPending, entities.ReviewReleased, analyst.Email, note, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: ReleaseReview", "Error:", err)
+		return entities.ScreeningReview{}, reviewError(err)
+	}
+	sender, err := s.userRepository.GetUser(review.UserID, ctx)
+	var transfer entities.Transfer
+	var withdrawal entities.Withdrawal
+	if err == nil && review.Withdrawal != nil {
+		withdrawal, err = s.withdrawer.ExecuteReviewedWithdrawal(ctx, sender.Email, *review.Withdrawal)
+	} else if err == nil {
+		transfer, err = s.transferer.ExecuteReviewedTransfer(ctx, sender.Email, review.Transfer)
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: ReleaseReview", "Error:", err)
+		if _, failErr := s.screeningRepository.UpdateReviewStatus(id, entities.ReviewReleased, entities.ReviewFailed, analyst.Email, err.Error(), ctx); failErr != nil {
+			s.logger.Errorln("Layer: screening_services", "Method: ReleaseReview", "Error:", failErr)
+		}
+		s.settleSource(ctx, review, false)
+		return entities.ScreeningReview{}, err
+	}
+	if review.Withdrawal != nil {
+		review.Withdrawal = &withdrawal
+		s.logger.Infoln("Layer: screening_services", "Method: ReleaseReview", "Review:", id, "Withdrawal:", withdrawal.ID)
+		return review, nil
+	}
+	review.Transfer = transfer
+	s.settleSource(ctx, review, true)
+	s.logger.Infoln("Layer: screening_services", "Method: ReleaseReview", "Review:", id, "Transfer:", review.Transfer.ID)
+	return review, nil
+}
+
+// RejectReview lets an analyst reject a held transfer, which never executes.
+func (s *screeningService) RejectReview(ctx context.Context, email string, id string, note string) (entities.ScreeningReview, error) {
+	analyst, err := requireAnalyst(ctx, s.userRepository, email)
+	if err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: RejectReview", "Error:", err)
+		return entities.ScreeningReview{}, err
+	}
+	review, err := s.screeningRepository.UpdateReviewStatus(id, entities.ReviewPending, entities.ReviewRejected, analyst.Email, note, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: RejectReview", "Error:", err)
+		return entities.ScreeningReview{}, reviewError(err)
+	}
+	s.settleSource(ctx, review, false)
+	return review, nil
+}
+
+// settleSource tells the schedule or payment request a held transfer was
+// made for whether it was made in the end. Failures are only logged: the
+// review is decided either way.
+func (s *screeningService) settleSource(ctx context.Context, review entities.ScreeningReview, released bool) {
+	source, ok := s.sources[review.Transfer.Source]
+	if !ok {
+		return
+	}
+	var err error
+	if released {
+		err = source.TransferReleased(ctx, review)
+	} else {
+		err = source.TransferRejected(ctx, review)
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: screening_services", "Method: settleSource", "Error:", err)
+	}
+}
+
+// reviewError maps the errors of the review queue to the ones of the service.
+func reviewError(err error) error {
+	switch {
+	case errors.Is(err, repository_screening.ErrReviewNotFound):
+		return ErrReviewNotFound
+	case errors.Is(err, repository_screening.ErrReviewStatusChanged):
+		return ErrReviewState
+	}
+	return err
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_screening "my_wallet/api/respository/screening"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScreenService(t *testing.T) {
+	rules := repository_screening.NewStaticRuleSource([]entities.ScreeningRule{
+		{ID: "large", Name: "Large", Kind: entities.RuleAmountAbove, Action: entities.ScreeningHold, Enabled: true, Currency: "COP", Threshold: 1000000},
+		{ID: "huge", Name: "Huge", Kind: entities.RuleAmountAbove, Action: entities.ScreeningBlock, Enabled: true, Currency: "COP", Threshold: 5000000},
+		{ID: "device", Name: "Device", Kind: entities.RuleNewDevice, Action: entities.ScreeningHold, Enabled: true, Threshold: 100000},
+		{ID: "payee", Name: "Payee", Kind: entities.RuleNewCounterparty, Action: entities.ScreeningHold, Enabled: false},
+		{ID: "rapid", Name: "Rapid", Kind: entities.RuleRapidSuccession, Action: entities.ScreeningHold, Enabled: true, Count: 3, WindowMinutes: 10},
+		{ID: "split", Name: "Split", Kind: entities.RuleStructuring, Action: entities.ScreeningHold, Enabled: true, Threshold: 1000000, Count: 3, WindowMinutes: 1440},
+	})
+	now := time.Now().UTC()
+	input := entities.ScreeningInput{Operation: entities.OperationTransfer, UserID: "u1", WalletID: "w1", CounterpartyUserID: "u2", Currency: "COP"}
+
+	testScenarios := []struct {
+		testName       string
+		amount         int64
+		deviceID       string
+		configureMock  func(*screeningRepositoryMock)
+		expectedOutput entities.ScreeningResult
+	}{
+		{
+			testName: "TestScreenAllow",
+			amount:   5000,
+			configureMock: func(s *screeningRepositoryMock) {
+				s.On("RecentOutgoing", mock.Anything, "w1", mock.Anything).Return([]entities.Transaction{}, nil)
+			},
+			expectedOutput: entities.ScreeningResult{Action: entities.ScreeningAllow, Rules: []string{}},
+		},
+		{
+			testName: "TestScreenMostSevere",
+			amount:   6000000,
+			configureMock: func(s *screeningRepositoryMock) {
+				s.On("RecentOutgoing", mock.Anything, "w1", mock.Anything).Return([]entities.Transaction{}, nil)
+			},
+			expectedOutput: entities.ScreeningResult{Action: entities.ScreeningBlock, Rules: []string{"large", "huge"}},
+		},
+		{
+			testName: "TestScreenNewDevice",
+			amount:   200000,
+			deviceID: "d1",
+			configureMock: func(s *screeningRepositoryMock) {
+				s.On("KnownDevice", mock.Anything, "u1", "d1").Return(false, nil)
+				s.On("RecentOutgoing", mock.Anything, "w1", mock.Anything).Return([]entities.Transaction{}, nil)
+			},
+			expectedOutput: entities.ScreeningResult{Action: entities.ScreeningHold, Rules: []string{"device"}},
+		},
+		{
+			testName: "TestScreenRapidSuccession",
+			amount:   5000,
+			configureMock: func(s *screeningRepositoryMock) {
+				s.On("RecentOutgoing", mock.Anything, "w1", mock.Anything).Return([]entities.Transaction{
+					{Amount: 5000, Currency: "COP", Created_at: now.Add(-2 * time.Minute)},
+					{Amount: 5000, Currency: "COP", Created_at: now.Add(-5 * time.Minute)},
+				}, nil)
+			},
+			expectedOutput: entities.ScreeningResult{Action: entities.ScreeningHold, Rules: []string{"rapid"}},
+		},
+		{
+			testName: "TestScreenStructuring",
+			amount:   400000,
+			configureMock: func(s *screeningRepositoryMock) {
+				s.On("RecentOutgoing", mock.Anything, "w1", mock.Anything).Return([]entities.Transaction{
+					{Amount: 400000, Currency: "COP", Created_at: now.Add(-3 * time.Hour)},
+					{Amount: 300000, Currency: "COP", Created_at: now.Add(-6 * time.Hour)},
+				}, nil)
+			},
+			expectedOutput: entities.ScreeningResult{Action: entities.ScreeningHold, Rules: []string{"split"}},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			repo := &screeningRepositoryMock{}
+			tt.configureMock(repo)
+			engine := NewScreeningEngine(repo, rules, logrus.StandardLogger())
+			in := input
+			in.Amount = tt.amount
+			in.DeviceID = tt.deviceID
+
+			// Act
+			result, err := engine.Screen(context.Background(), in)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOutput, result)
+			repo.AssertNotCalled(t, "HasPaid", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSaveScreeningRuleService(t *testing.T) {
+	analyst := entities.User{ID: "a1", Email: "analyst@gmail.com", Enabled: true, Role: entities.RoleAnalyst}
+	rule := entities.ScreeningRule{ID: "large", Name: "Large", Kind: entities.RuleAmountAbove, Action: entities.ScreeningHold, Enabled: true, Threshold: 1000000}
+
+	testScenarios := []struct {
+		testName      string
+		user          entities.User
+		rule          entities.ScreeningRule
+		rules         repository_screening.RuleSource
+		expectedError error
+	}{
+		{
+			testName:      "TestSaveRuleReadOnly",
+			user:          analyst,
+			rule:          rule,
+			rules:         repository_screening.NewStaticRuleSource(nil),
+			expectedError: ErrScreeningRulesReadOnly,
+		},
+		{
+			testName:      "TestSaveInvalidRule",
+			user:          analyst,
+			rule:          entities.ScreeningRule{ID: "rapid", Name: "Rapid", Kind: entities.RuleRapidSuccession, Action: entities.ScreeningHold, Count: 1},
+			rules:         repository_screening.NewStaticRuleSource(nil),
+			expectedError: ErrInvalidScreeningRule,
+		},
+		{
+			testName:      "TestSaveRuleNotAnalyst",
+			user:          entities.User{ID: "u1", Email: "analyst@gmail.com", Enabled: true},
+			rule:          rule,
+			rules:         repository_screening.NewStaticRuleSource(nil),
+			expectedError: ErrAnalystRequired,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "analyst@gmail.com").Return(tt.user, nil)
+			service := NewScreeningService(users, &screeningRepositoryMock{}, tt.rules, nil, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			_, err := service.SaveRule(context.Background(), "analyst@gmail.com", tt.rule)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestReleaseScreeningReviewService(t *testing.T) {
+	analyst := entities.User{ID: "a1", Email: "analyst@gmail.com", Enabled: true, Role: entities.RoleAnalyst}
+	sender := entities.User{ID: "u1", Email: "sender@gmail.com", Enabled: true}
+	transfer := entities.Transfer{SenderUserID: "u1", RecipientEmail: "recipient@gmail.com", Amount: 2000000, Currency: "COP"}
+	review := entities.ScreeningReview{ID: "r1", UserID: "u1", Transfer: transfer, Status: entities.ReviewReleased}
+
+	testScenarios := []struct {
+		testName       string
+		configureMock  func(*userServiceMock, *screeningRepositoryMock, *reviewedTransfererMock)
+		expectedOutput entities.ScreeningReview
+		expectedError  error
+	}{
+		{
+			testName: "TestReleaseReviewService",
+			configureMock: func(u *userServiceMock, s *screeningRepositoryMock, x *reviewedTransfererMock) {
+				u.On("GetUser", mock.Anything, "u1").Return(sender, nil)
+				s.On("UpdateReviewStatus", mock.Anything, "r1", entities.ReviewPending, entities.ReviewReleased, "analyst@gmail.com", "ok").Return(review, nil)
+				x.On("ExecuteReviewedTransfer", mock.Anything, "sender@gmail.com", transfer).Return(entities.Transfer{ID: "t1"}, nil)
+			},
+			expectedOutput: entities.ScreeningReview{ID: "r1", UserID: "u1", Transfer: entities.Transfer{ID: "t1"}, Status: entities.ReviewReleased},
+			expectedError:  nil,
+		},
+		{
+			testName: "TestReleaseReviewFailed",
+			configureMock: func(u *userServiceMock, s *screeningRepositoryMock, x *reviewedTransfererMock) {
+				u.On("GetUser", mock.Anything, "u1").Return(sender, nil)
+				s.On("UpdateReviewStatus", mock.Anything, "r1", entities.ReviewPending, entities.ReviewReleased, "analyst@gmail.com", "ok").Return(review, nil)
+				s.On("UpdateReviewStatus", mock.Anything, "r1", entities.ReviewReleased, entities.ReviewFailed, "analyst@gmail.com", ErrInsufficientFunds.Error()).Return(review, nil)
+				x.On("ExecuteReviewedTransfer", mock.Anything, "sender@gmail.com", transfer).Return(entities.Transfer{}, ErrInsufficientFunds)
+			},
+			expectedOutput: entities.ScreeningReview{},
+			expectedError:  ErrInsufficientFunds,
+		},
+		{
+			testName: "TestReleaseReviewAlreadyDecided",
+			configureMock: func(u *userServiceMock, s *screeningRepositoryMock, x *reviewedTransfererMock) {
+				s.On("UpdateReviewStatus", mock.Anything, "r1", entities.ReviewPending, entities.ReviewReleased, "analyst@gmail.com", "ok").Return(entities.ScreeningReview{}, repository_screening.ErrReviewStatusChanged)
+			},
+			expectedOutput: entities.ScreeningReview{},
+			expectedError:  ErrReviewState,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "analyst@gmail.com").Return(analyst, nil)
+			repo := &screeningRepositoryMock{}
+			transferer := &reviewedTransfererMock{}
+			tt.configureMock(users, repo, transferer)
+			service := NewScreeningService(users, repo, nil, transferer, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.ReleaseReview(context.Background(), "analyst@gmail.com", "r1", "ok")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHeldTransferSourceService(t *testing.T) {
+	analyst := entities.User{ID: "a1", Email: "analyst@gmail.com", Role: entities.RoleAnalyst}
+	sender := entities.User{ID: "u1", Email: "sender@gmail.com"}
+	transfer := entities.Transfer{RecipientEmail: "landlord@gmail.com", Amount: 100000, Source: entities.TransferSourceSchedule, SourceID: "s1"}
+	review := entities.ScreeningReview{ID: "r1", UserID: "u1", Transfer: transfer, Status: entities.ReviewReleased}
+
+	testScenarios := []struct {
+		testName         string
+		reject           bool
+		transferError    error
+		expectedReleased bool
+	}{
+		{
+			testName:         "TestReleasedTransferCompletesSource",
+			expectedReleased: true,
+		},
+		{
+			testName:      "TestFailedReleaseRejectsSource",
+			transferError: ErrInsufficientFunds,
+		},
+		{
+			testName: "TestRejectedTransferRejectsSource",
+			reject:   true,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "analyst@gmail.com").Return(analyst, nil)
+			users.On("GetUser", mock.Anything, "u1").Return(sender, nil)
+			repo := &screeningRepositoryMock{}
+			repo.On("UpdateReviewStatus", mock.Anything, "r1", mock.Anything, mock.Anything, "analyst@gmail.com", mock.Anything).Return(review, nil)
+			released := transfer
+			released.ID = "t1"
+			transferer := &reviewedTransfererMock{}
+			transferer.On("ExecuteReviewedTransfer", mock.Anything, "sender@gmail.com", transfer).Return(released, tt.transferError)
+			source := &heldTransferSourceMock{}
+			source.On("TransferReleased", mock.Anything, mock.Anything).Return(nil)
+			source.On("TransferRejected", mock.Anything, mock.Anything).Return(nil)
+			service := NewScreeningService(users, repo, nil, transferer, nil, map[string]HeldTransferSource{entities.TransferSourceSchedule: source}, logrus.StandardLogger(), context.Background())
+
+			// Act
+			if tt.reject {
+				_, _ = service.RejectReview(context.Background(), "analyst@gmail.com", "r1", "mule account")
+			} else {
+				_, _ = service.ReleaseReview(context.Background(), "analyst@gmail.com", "r1", "ok")
+			}
+
+			// Assert
+			if tt.expectedReleased {
+				source.AssertCalled(t, "TransferReleased", mock.Anything, mock.MatchedBy(func(r entities.ScreeningReview) bool {
+					return r.ID == "r1" && r.Transfer.ID == "t1" && r.Transfer.SourceID == "s1"
+				}))
+				source.AssertNotCalled(t, "TransferRejected", mock.Anything, mock.Anything)
+			} else {
+				source.AssertCalled(t, "TransferRejected", mock.Anything, mock.MatchedBy(func(r entities.ScreeningReview) bool {
+					return r.ID == "r1" && r.Transfer.SourceID == "s1"
+				}))
+				source.AssertNotCalled(t, "TransferReleased", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type splitRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *splitRepositoryMock) CreateSplit(split entities.Split, ctx context.Context) (entities.Split, error) {
+	r := m.Called(ctx, split)
+	if created, ok := r.Get(0).(func(entities.Split) entities.Split); ok {
+		return created(split), r.Error(1)
+	}
+	return r.Get(0).(entities.Split), r.Error(1)
+}
+
+func (m *splitRepositoryMock) GetSplit(id string, ctx context.Context) (entities.Split, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.Split), r.Error(1)
+}
+
+func (m *splitRepositoryMock) ListSplits(userID string, ctx context.Context) ([]entities.Split, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).([]entities.Split), r.Error(1)
+}
+
+func (m *splitRepositoryMock) SaveSplit(split entities.Split, ctx context.Context) error {
+	r := m.Called(ctx, split)
+	return r.Error(0)
+}
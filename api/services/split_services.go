@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"my_wallet/api/entities"
+	repository_payment_request "my_wallet/api/respository/payment_request"
+	repository_split "my_wallet/api/respository/split"
+	repository_user "my_wallet/api/respository/user"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const maxSplitParticipants = 20
+
+type SplitService interface {
+	CreateSplit(ctx context.Context, email string, split entities.Split) (entities.Split, error)
+	ListSplits(ctx context.Context, email string) ([]entities.Split, error)
+	GetSplit(ctx context.Context, email string, id string) (entities.Split, error)
+}
+
+type splitService struct {
+	ctx                      context.Context
+	userRepository           repository_user.UserRepository
+	splitRepository          repository_split.SplitRepository
+	paymentRequestRepository repository_payment_request.PaymentRequestRepository
+	paymentRequestService    PaymentRequestService
+	logger                   logrus.FieldLogger
+}
+
+func NewSplitService(userRepo repository_user.UserRepository, splitRepo repository_split.SplitRepository, paymentRequestRepo repository_payment_request.PaymentRequestRepository, paymentRequestService PaymentRequestService, logger logrus.FieldLogger, ctx context.Context) *splitService {
+	return &splitService{
+		ctx:                      ctx,
+		userRepository:           userRepo,
+		splitRepository:          splitRepo,
+		paymentRequestRepository: paymentRequestRepo,
+		paymentRequestService:    paymentRequestService,
+		logger:                   logger,
+	}
+}
+
+// CreateSplit divides the amount among the participants and sends each of
+// them, except the owner, a payment request for their part. If a request
+// cannot be sent the ones already sent are cancelled along with the split.
+func (s *splitService) CreateSplit(ctx context.Context, email string, split entities.Split) (entities.Split, error) {
+	if split.Currency == "" {
+		split.Currency = entities.DefaultCurrency
+	}
+	if err := validateSplit(split); err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: CreateSplit", "Error:", err)
+		return entities.Split{}, err
+	}
+	owner, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: CreateSplit", "Error:", err)
+		return entities.Split{}, err
+	}
+	if err := s.resolveParticipants(ctx, owner, split.Participants); err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: CreateSplit", "Error:", err)
+		return entities.Split{}, err
+	}
+	amounts, err := allocateSplit(split)
+	if err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: CreateSplit", "Error:", err)
+		return entities.Split{}, err
+	}
+
+	now := time.Now().UTC()
+	split.ID = ""
+	split.OwnerID = owner.ID
+	split.PaidAmount = 0
+	for i := range split.Participants {
+		split.Participants[i].Amount = amounts[i]
+		split.Participants[i].PaymentRequestID = ""
+		split.Participants[i].Status = entities.RequestPending
+		if split.Participants[i].UserID == owner.ID {
+			split.Participants[i].Status = entities.RequestAccepted
+			split.PaidAmount += amounts[i]
+		}
+	}
+	split.Status = entities.SplitOpen
+	split.Created_at = now
+	split.Update_at = now
+	split, err = s.splitRepository.CreateSplit(split, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: CreateSplit", "Error:", err)
+		return entities.Split{}, err
+	}
+
+	for i, participant := range split.Participants {
+		if participant.UserID == owner.ID || participant.Amount == 0 {
+			continue
+		}
+		request, err := s.paymentRequestService.CreatePaymentRequest(ctx, email, entities.PaymentRequest{
+			PayerEmail: participant.Email,
+			PayerPhone: participant.Phone,
+			PayerDNI:   participant.DNI,
+			Amount:     participant.Amount,
+			Currency:   split.Currency,
+			Memo:       split.Description,
+			SplitID:    split.ID,
+		})
+		if err != nil {
+			s.logger.Errorln("Layer: split_services", "Method: CreateSplit", "Error:", err)
+			s.cancelSplit(ctx, split)
+			return entities.Split{}, err
+		}
+		split.Participants[i].PaymentRequestID = request.ID
+	}
+	if split.PaidAmount == split.Amount {
+		split.Status = entities.SplitSettled
+	}
+	if err := s.splitRepository.SaveSplit(split, ctx); err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: CreateSplit", "Error:", err)
+		return entities.Split{}, err
+	}
+	s.logger.Infoln("Layer: split_services", "Method: CreateSplit", "Split:", split.ID)
+	return split, nil
+}
+
+// ListSplits lists the splits the authenticated user owns or takes part in.
+func (s *splitService) ListSplits(ctx context.Context, email string) ([]entities.Split, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: ListSplits", "Error:", err)
+		return nil, err
+	}
+	splits, err := s.splitRepository.ListSplits(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: ListSplits", "Error:", err)
+		return nil, err
+	}
+	for i := range splits {
+		if splits[i], err = s.refreshSplit(ctx, splits[i]); err != nil {
+			s.logger.Errorln("Layer: split_services", "Method: ListSplits", "Error:", err)
+			return nil, err
+		}
+	}
+	return splits, nil
+}
+
+// GetSplit returns a split with the settlement status of every participant.
+func (s *splitService) GetSplit(ctx context.Context, email string, id string) (entities.Split, error) {
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: GetSplit", "Error:", err)
+		return entities.Split{}, err
+	}
+	split, err := s.splitRepository.GetSplit(id, ctx)
+	if errors.Is(err, repository_split.ErrSplitNotFound) {
+		s.logger.Errorln("Layer: split_services", "Method: GetSplit", "Error:", err)
+		return entities.Split{}, ErrSplitNotFound
+	}
+	if err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: GetSplit", "Error:", err)
+		return entities.Split{}, err
+	}
+	if !takesPart(split, user.ID) {
+		s.logger.Errorln("Layer: split_services", "Method: GetSplit", "Error:", ErrSplitNotFound)
+		return entities.Split{}, ErrSplitNotFound
+	}
+	split, err = s.refreshSplit(ctx, split)
+	if err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: GetSplit", "Error:", err)
+		return entities.Split{}, err
+	}
+	return split, nil
+}
+
+// refreshSplit brings an open split up to date with its payment requests and
+// stores it when something changed, settling it once everyone has paid.
+func (s *splitService) refreshSplit(ctx context.Context, split entities.Split) (entities.Split, error) {
+	if split.Status != entities.SplitOpen {
+		return split, nil
+	}
+	requests, err := s.paymentRequestRepository.ListSplitRequests(split.ID, ctx)
+	if err != nil {
+		return entities.Split{}, err
+	}
+	statuses := make(map[string]string, len(requests))
+	now := time.Now().UTC()
+	for _, request := range requests {
+		statuses[request.ID] = request.Status
+		if request.Expired(now) {
+			statuses[request.ID] = entities.RequestExpired
+		}
+	}
+
+	changed := false
+	paid := int64(0)
+	for i, participant := range split.Participants {
+		if status, ok := statuses[participant.PaymentRequestID]; ok && status != participant.Status {
+			split.Participants[i].Status = status
+			changed = true
+		}
+		if split.Participants[i].Status == entities.RequestAccepted || participant.Amount == 0 {
+			paid += participant.Amount
+		}
+	}
+	split.PaidAmount = paid
+	if paid == split.Amount {
+		split.Status = entities.SplitSettled
+		changed = true
+	}
+	if !changed {
+		return split, nil
+	}
+	split.Update_at = now
+	if err := s.splitRepository.SaveSplit(split, ctx); err != nil {
+		return entities.Split{}, err
+	}
+	return split, nil
+}
+
+func (s *splitService) cancelSplit(ctx context.Context, split entities.Split) {
+	for _, participant := range split.Participants {
+		if participant.PaymentRequestID == "" {
+			continue
+		}
+		err := s.paymentRequestRepository.UpdateStatus(participant.PaymentRequestID, entities.RequestPending, entities.RequestCancelled, "", ctx)
+		if err != nil {
+			s.logger.Errorln("Layer: split_services", "Method: cancelSplit", "Error:", err)
+		}
+	}
+	split.Status = entities.SplitCancelled
+	split.Update_at = time.Now().UTC()
+	if err := s.splitRepository.SaveSplit(split, ctx); err != nil {
+		s.logger.Errorln("Layer: split_services", "Method: cancelSplit", "Error:", err)
+	}
+}
+
+// resolveParticipants finds the user behind every participant. Each user may
+// appear once and someone other than the owner must take part.
+func (s *splitService) resolveParticipants(ctx context.Context, owner entities.User, participants []entities.SplitParticipant) error {
+	seen := make(map[string]bool, len(participants))
+	others := 0
+	for i, participant := range participants {
+		user, err := findPayer(ctx, s.userRepository, entities.PaymentRequest{
+			PayerEmail: participant.Email,
+			PayerPhone: participant.Phone,
+			PayerDNI:   participant.DNI,
+		})
+		if err != nil {
+			return err
+		}
+		if seen[user.ID] {
+			return ErrInvalidSplit
+		}
+		seen[user.ID] = true
+		if user.ID != owner.ID {
+			others++
+		}
+		participants[i].UserID = user.ID
+	}
+	if others == 0 {
+		return ErrSelfRequest
+	}
+	return nil
+}
+
+func takesPart(split entities.Split, userID string) bool {
+	if split.OwnerID == userID {
+		return true
+	}
+	for _, participant := range split.Participants {
+		if participant.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func validateSplit(split entities.Split) error {
+	if split.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if !entities.ValidCurrency(split.Currency) {
+		return ErrInvalidCurrency
+	}
+	if utf8.RuneCountInString(split.Description) > maxMemoLength {
+		return ErrMemoTooLong
+	}
+	if len(split.Participants) == 0 || len(split.Participants) > maxSplitParticipants {
+		return ErrInvalidSplit
+	}
+	switch split.Method {
+	case entities.SplitEven:
+	case entities.SplitShares:
+		for _, participant := range split.Participants {
+			if participant.Shares <= 0 {
+				return ErrInvalidSplit
+			}
+		}
+	case entities.SplitExact:
+		for _, participant := range split.Participants {
+			if participant.Amount <= 0 {
+				return ErrInvalidSplit
+			}
+		}
+	default:
+		return ErrInvalidSplit
+	}
+	return nil
+}
+
+// allocateSplit returns each participant's part of the split amount. The
+// parts always add up to the amount.
+func allocateSplit(split entities.Split) ([]int64, error) {
+	weights := make([]int64, len(split.Participants))
+	for i, participant := range split.Participants {
+		switch split.Method {
+		case entities.SplitEven:
+			weights[i] = 1
+		case entities.SplitShares:
+			weights[i] = participant.Shares
+		case entities.SplitExact:
+			weights[i] = participant.Amount
+		}
+	}
+	if split.Method == entities.SplitExact {
+		total := int64(0)
+		for _, amount := range weights {
+			total += amount
+			if total > split.Amount {
+				return nil, ErrSplitAmountMismatch
+			}
+		}
+		if total != split.Amount {
+			return nil, ErrSplitAmountMismatch
+		}
+		return weights, nil
+	}
+	return allocate(split.Amount, weights), nil
+}
+
+// allocate divides amount in proportion to weights using the largest
+// remainder method: every part is first rounded down and the cents left over
+// go one by one to the largest remainders, earlier parts winning ties.
+func allocate(amount int64, weights []int64) []int64 {
+	total := new(big.Int)
+	for _, weight := range weights {
+		total.Add(total, big.NewInt(weight))
+	}
+	parts := make([]int64, len(weights))
+	remainders := make([]*big.Int, len(weights))
+	left := amount
+	for i, weight := range weights {
+		quotient, remainder := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(amount), big.NewInt(weight)), total, new(big.Int))
+		parts[i] = quotient.Int64()
+		remainders[i] = remainder
+		left -= parts[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	for i := int64(0); i < left; i++ {
+		parts[order[i]]++
+	}
+	return parts
+}
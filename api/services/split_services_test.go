@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAllocate(t *testing.T) {
+	testScenarios := []struct {
+		testName string
+		amount   int64
+		weights  []int64
+		expected []int64
+	}{
+		{
+			testName: "even split with cents left over",
+			amount:   10000,
+			weights:  []int64{1, 1, 1},
+			expected: []int64{3334, 3333, 3333},
+		},
+		{
+			testName: "shares",
+			amount:   100,
+			weights:  []int64{1, 2, 3},
+			expected: []int64{17, 33, 50},
+		},
+		{
+			testName: "largest remainder wins the cent",
+			amount:   101,
+			weights:  []int64{3, 7},
+			expected: []int64{30, 71},
+		},
+		{
+			testName: "fewer cents than participants",
+			amount:   2,
+			weights:  []int64{1, 1, 1},
+			expected: []int64{1, 1, 0},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			result := allocate(tt.amount, tt.weights)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+			sum := int64(0)
+			for _, part := range result {
+				sum += part
+			}
+			assert.Equal(t, tt.amount, sum)
+		})
+	}
+}
+
+func TestCreateSplitService(t *testing.T) {
+	owner := entities.User{ID: "u1", Email: "alexer@gmail.com", Enabled: true}
+	ana := entities.User{ID: "u2", Email: "ana@gmail.com", Enabled: true}
+	luis := entities.User{ID: "u3", Email: "luis@gmail.com", Enabled: true}
+
+	testScenarios := []struct {
+		testName        string
+		split           entities.Split
+		expectedAmounts []int64
+		expectedPaid    int64
+		expectedError   error
+	}{
+		{
+			testName: "TestEvenSplitWithOwner",
+			split: entities.Split{Description: "Dinner", Amount: 10000, Method: entities.SplitEven, Participants: []entities.SplitParticipant{
+				{Email: "alexer@gmail.com"}, {Email: "ana@gmail.com"}, {Email: "luis@gmail.com"},
+			}},
+			expectedAmounts: []int64{3334, 3333, 3333},
+			expectedPaid:    3334,
+		},
+		{
+			testName: "TestExactSplit",
+			split: entities.Split{Description: "Dinner", Amount: 10000, Method: entities.SplitExact, Participants: []entities.SplitParticipant{
+				{Email: "ana@gmail.com", Amount: 6000}, {Email: "luis@gmail.com", Amount: 4000},
+			}},
+			expectedAmounts: []int64{6000, 4000},
+		},
+		{
+			testName: "TestExactSplitMismatch",
+			split: entities.Split{Description: "Dinner", Amount: 10000, Method: entities.SplitExact, Participants: []entities.SplitParticipant{
+				{Email: "ana@gmail.com", Amount: 6000}, {Email: "luis@gmail.com", Amount: 3000},
+			}},
+			expectedError: ErrSplitAmountMismatch,
+		},
+		{
+			testName: "TestSharesRequired",
+			split: entities.Split{Description: "Dinner", Amount: 10000, Method: entities.SplitShares, Participants: []entities.SplitParticipant{
+				{Email: "ana@gmail.com", Shares: 2}, {Email: "luis@gmail.com"},
+			}},
+			expectedError: ErrInvalidSplit,
+		},
+		{
+			testName: "TestDuplicateParticipant",
+			split: entities.Split{Description: "Dinner", Amount: 10000, Method: entities.SplitEven, Participants: []entities.SplitParticipant{
+				{Email: "ana@gmail.com"}, {Email: "ana@gmail.com"},
+			}},
+			expectedError: ErrInvalidSplit,
+		},
+		{
+			testName: "TestOnlyOwner",
+			split: entities.Split{Description: "Dinner", Amount: 10000, Method: entities.SplitEven, Participants: []entities.SplitParticipant{
+				{Email: "alexer@gmail.com"},
+			}},
+			expectedError: ErrSelfRequest,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(owner, nil)
+			users.On("GetUserByEmail", mock.Anything, "ana@gmail.com").Return(ana, nil)
+			users.On("GetUserByEmail", mock.Anything, "luis@gmail.com").Return(luis, nil)
+			splits := &splitRepositoryMock{}
+			splits.On("CreateSplit", mock.Anything, mock.Anything).Return(func(split entities.Split) entities.Split {
+				split.ID = "s1"
+				return split
+			}, nil)
+			splits.On("SaveSplit", mock.Anything, mock.Anything).Return(nil)
+			requests := &paymentRequestRepositoryMock{}
+			requests.On("CreatePaymentRequest", mock.Anything, mock.MatchedBy(func(r entities.PaymentRequest) bool {
+				return r.SplitID == "s1" && r.Memo == "Dinner" && r.RequesterID == "u1"
+			})).Return(entities.PaymentRequest{ID: "r1"}, nil)
+			paymentRequests := NewPaymentRequestService(users, requests, &transferServiceMock{}, time.Hour, logrus.StandardLogger(), context.Background())
+			service := NewSplitService(users, splits, requests, paymentRequests, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateSplit(context.Background(), "alexer@gmail.com", tt.split)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			var amounts []int64
+			for _, participant := range result.Participants {
+				amounts = append(amounts, participant.Amount)
+			}
+			assert.Equal(t, tt.expectedAmounts, amounts)
+			assert.Equal(t, tt.expectedPaid, result.PaidAmount)
+		})
+	}
+}
+
+func TestGetSplitService(t *testing.T) {
+	owner := entities.User{ID: "u1", Email: "alexer@gmail.com"}
+	stranger := entities.User{ID: "u9", Email: "stranger@gmail.com"}
+	split := entities.Split{
+		ID: "s1", OwnerID: "u1", Amount: 10000, Status: entities.SplitOpen, PaidAmount: 3334,
+		Participants: []entities.SplitParticipant{
+			{UserID: "u1", Amount: 3334, Status: entities.RequestAccepted},
+			{UserID: "u2", Amount: 3333, PaymentRequestID: "r2", Status: entities.RequestPending},
+			{UserID: "u3", Amount: 3333, PaymentRequestID: "r3", Status: entities.RequestPending},
+		},
+	}
+	later := time.Now().UTC().Add(time.Hour)
+
+	testScenarios := []struct {
+		testName       string
+		email          string
+		requests       []entities.PaymentRequest
+		expectedStatus string
+		expectedPaid   int64
+		expectedError  error
+	}{
+		{
+			testName: "TestPartiallyPaid",
+			email:    "alexer@gmail.com",
+			requests: []entities.PaymentRequest{
+				{ID: "r2", Status: entities.RequestAccepted},
+				{ID: "r3", Status: entities.RequestPending, ExpiresAt: later},
+			},
+			expectedStatus: entities.SplitOpen,
+			expectedPaid:   6667,
+		},
+		{
+			testName: "TestSettled",
+			email:    "alexer@gmail.com",
+			requests: []entities.PaymentRequest{
+				{ID: "r2", Status: entities.RequestAccepted},
+				{ID: "r3", Status: entities.RequestAccepted},
+			},
+			expectedStatus: entities.SplitSettled,
+			expectedPaid:   10000,
+		},
+		{
+			testName:      "TestNotAParticipant",
+			email:         "stranger@gmail.com",
+			expectedError: ErrSplitNotFound,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(owner, nil)
+			users.On("GetUserByEmail", mock.Anything, "stranger@gmail.com").Return(stranger, nil)
+			stored := split
+			stored.Participants = append([]entities.SplitParticipant{}, split.Participants...)
+			splits := &splitRepositoryMock{}
+			splits.On("GetSplit", mock.Anything, "s1").Return(stored, nil)
+			splits.On("SaveSplit", mock.Anything, mock.Anything).Return(nil)
+			requests := &paymentRequestRepositoryMock{}
+			requests.On("ListSplitRequests", mock.Anything, "s1").Return(tt.requests, nil)
+			service := NewSplitService(users, splits, requests, &paymentRequestService{}, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.GetSplit(context.Background(), tt.email, "s1")
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			assert.Equal(t, tt.expectedPaid, result.PaidAmount)
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const maxMemoLength = 140
+
+type TransferService interface {
+	CreateTransfer(ctx context.Context, senderEmail string, transfer entities.Transfer) (entities.Transfer, error)
+}
+
+type transferService struct {
+	ctx              context.Context
+	userRepository   repository_user.UserRepository
+	walletRepository repository_wallet.WalletRepository
+	ledgerRepository repository_ledger.LedgerRepository
+	feeService       FeeService
+	screener         Screener
+	limiter          Limiter
+	autoSaver        AutoSaver
+	beneficiaries    Beneficiaries
+	restrictions     Restrictions
+	members          Memberships
+	logger           logrus.FieldLogger
+}
+
+func NewTransferService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, ledgerRepo repository_ledger.LedgerRepository, feeService FeeService, screener Screener, limiter Limiter, autoSaver AutoSaver, beneficiaries Beneficiaries, restrictions Restrictions, members Memberships, logger logrus.FieldLogger, ctx context.Context) *transferService {
+	return &transferService{
+		ctx:              ctx,
+		userRepository:   userRepo,
+		walletRepository: walletRepo,
+		ledgerRepository: ledgerRepo,
+		feeService:       feeService,
+		screener:         screener,
+		limiter:          limiter,
+		autoSaver:        autoSaver,
+		beneficiaries:    beneficiaries,
+		restrictions:     restrictions,
+		members:          members,
+		logger:           logger,
+	}
+}
+
+// CreateTransfer moves money from a wallet the sender can spend from to the
+// wallet of the recipient, in a single ledger entry with the fee. The
+// transfer is screened first and taken out of the limits of the sender;
+// blocked transfers fail with ErrTransferBlocked and held ones with a
+// TransferHeldError.
+func (s *transferService) CreateTransfer(ctx context.Context, senderEmail string, transfer entities.Transfer) (entities.Transfer, error) {
+	return s.createTransfer(ctx, senderEmail, transfer, true)
+}
+
+// ExecuteReviewedTransfer executes a transfer an analyst released from the
+// review queue, without screening it again.
+func (s *transferService) ExecuteReviewedTransfer(ctx context.Context, senderEmail string, transfer entities.Transfer) (entities.Transfer, error) {
+	return s.createTransfer(ctx, senderEmail, transfer, false)
+}
+
+func (s *transferService) createTransfer(ctx context.Context, senderEmail string, transfer entities.Transfer, screen bool) (entities.Transfer, error) {
+	if transfer.Amount <= 0 {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", ErrInvalidAmount)
+		return entities.Transfer{}, ErrInvalidAmount
+	}
+	if transfer.Currency != "" && !entities.ValidCurrency(transfer.Currency) {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", ErrInvalidCurrency)
+		return entities.Transfer{}, ErrInvalidCurrency
+	}
+	if utf8.RuneCountInString(transfer.Memo) > maxMemoLength {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", ErrMemoTooLong)
+		return entities.Transfer{}, ErrMemoTooLong
+	}
+
+	sender, err := s.userRepository.GetUserByEmail(senderEmail, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+		return entities.Transfer{}, err
+	}
+	if err := requireActive(sender); err != nil {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+		return entities.Transfer{}, err
+	}
+	recipient, beneficiary, err := s.findRecipient(ctx, sender, transfer)
+	if err != nil {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+		return entities.Transfer{}, err
+	}
+	if recipient.ID == sender.ID {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", ErrSelfTransfer)
+		return entities.Transfer{}, ErrSelfTransfer
+	}
+	if !recipient.Active() {
+		// The sender is not told the recipient is under review.
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", ErrUserUnderReview)
+		return entities.Transfer{}, ErrRecipientDisabled
+	}
+
+	senderWallet, member, err := s.senderWallet(ctx, sender, transfer.SenderWalletID)
+	if err != nil {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+		return entities.Transfer{}, err
+	}
+	currency := transfer.Currency
+	if currency == "" {
+		currency = senderWallet.Currency
+	}
+	if beneficiary.ID != "" {
+		if err := s.beneficiaries.CheckCoolingOff(beneficiary, currency, transfer.Amount); err != nil {
+			s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+			return entities.Transfer{}, err
+		}
+	}
+	fee, err := s.feeService.ComputeFee(ctx, sender, entities.OperationTransfer, currency, transfer.Amount)
+	if err != nil {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+		return entities.Transfer{}, err
+	}
+	if senderWallet.Balances[currency] < fee.Total {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", ErrInsufficientFunds)
+		return entities.Transfer{}, ErrInsufficientFunds
+	}
+	recipientWallet, err := s.walletRepository.GetOrCreateWallet(recipient.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+		return entities.Transfer{}, err
+	}
+	if recipientWallet.ID == senderWallet.ID {
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", ErrSelfTransfer)
+		return entities.Transfer{}, ErrSelfTransfer
+	}
+	if s.restrictions != nil {
+		if err := s.restrictions.CheckDebit(ctx, senderWallet.ID, entities.CapabilityTransfer); err != nil {
+			s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+			return entities.Transfer{}, err
+		}
+		if err := s.restrictions.CheckCredit(ctx, recipientWallet.ID); err != nil {
+			s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+			return entities.Transfer{}, recipientError(err)
+		}
+	}
+
+	entry := entities.LedgerEntry{
+		Type:      entities.EntryTransfer,
+		Memo:      transfer.Memo,
+		Reference: transfer.Reference,
+		Lines: []entities.LedgerLine{
+			{Account: entities.WalletAccount(senderWallet.ID), WalletID: senderWallet.ID, Currency: currency, Amount: -transfer.Amount},
+			{Account: entities.WalletAccount(recipientWallet.ID), WalletID: recipientWallet.ID, Currency: currency, Amount: transfer.Amount},
+		},
+		Created_at: time.Now().UTC(),
+	}
+	transactions := []entities.Transaction{
+		{
+			WalletID:             senderWallet.ID,
+			UserID:               senderWallet.UserID,
+			Type:                 entities.TransactionTransferOut,
+			Status:               entities.StatusCompleted,
+			Amount:               transfer.Amount,
+			Currency:             currency,
+			CounterpartyWalletID: recipientWallet.ID,
+			CounterpartyUserID:   recipient.ID,
+			Memo:                 transfer.Memo,
+			InitiatedBy:          sender.ID,
+		},
+		{
+			WalletID:             recipientWallet.ID,
+			UserID:               recipient.ID,
+			Type:                 entities.TransactionTransferIn,
+			Status:               entities.StatusCompleted,
+			Amount:               transfer.Amount,
+			Currency:             currency,
+			CounterpartyWalletID: senderWallet.ID,
+			CounterpartyUserID:   senderWallet.UserID,
+			Memo:                 transfer.Memo,
+		},
+	}
+	if fee.Fee > 0 {
+		entry.Lines = append(entry.Lines, feeLines(senderWallet.ID, currency, fee.Fee)...)
+		transactions = append(transactions, entities.Transaction{
+			WalletID:    senderWallet.ID,
+			UserID:      senderWallet.UserID,
+			Type:        entities.TransactionFee,
+			Status:      entities.StatusCompleted,
+			Amount:      fee.Fee,
+			Currency:    currency,
+			Memo:        "Transfer fee",
+			InitiatedBy: sender.ID,
+		})
+	}
+
+	screening := entities.ScreeningInput{
+		Operation:          entities.OperationTransfer,
+		UserID:             sender.ID,
+		WalletID:           senderWallet.ID,
+		CounterpartyUserID: recipient.ID,
+		DeviceID:           transfer.DeviceID,
+		Currency:           currency,
+		Amount:             transfer.Amount,
+	}
+	if screen && s.screener != nil {
+		if err := s.screen(ctx, screening, transfer); err != nil {
+			s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+			return entities.Transfer{}, err
+		}
+	}
+
+	var reservation entities.LimitReservation
+	if s.limiter != nil {
+		reservation, err = s.limiter.Reserve(ctx, sender, currency, transfer.Amount)
+		if err != nil {
+			s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+			return entities.Transfer{}, err
+		}
+	}
+	var spending entities.LimitReservation
+	if member.Role != entities.MemberOwner {
+		spending, err = s.members.ReserveSpending(ctx, member, currency, fee.Total)
+		if err != nil {
+			if s.limiter != nil {
+				s.limiter.Release(ctx, reservation)
+			}
+			s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+			return entities.Transfer{}, err
+		}
+	}
+	entry, _, err = s.ledgerRepository.PostEntry(entry, transactions, ctx)
+	if err != nil {
+		if s.limiter != nil {
+			s.limiter.Release(ctx, reservation)
+		}
+		if member.Role != entities.MemberOwner {
+			s.members.ReleaseSpending(ctx, spending)
+		}
+		s.logger.Errorln("Layer: transfer_services", "Method: CreateTransfer", "Error:", err)
+		if errors.Is(err, repository_ledger.ErrInsufficientFunds) {
+			return entities.Transfer{}, ErrInsufficientFunds
+		}
+		if errors.Is(err, repository_ledger.ErrEntryExists) {
+			return entities.Transfer{}, ErrTransferAlreadyMade
+		}
+		return entities.Transfer{}, err
+	}
+
+	transfer.ID = entry.ID
+	transfer.SenderUserID = sender.ID
+	transfer.SenderWalletID = senderWallet.ID
+	transfer.RecipientUserID = recipient.ID
+	transfer.RecipientWalletID = recipientWallet.ID
+	transfer.Currency = currency
+	transfer.Fee = fee.Fee
+	transfer.Status = entities.StatusCompleted
+	transfer.Created_at = entry.Created_at
+	s.logger.Infoln("Layer: transfer_services", "Method: CreateTransfer", "Transfer:", transfer.ID)
+	if s.screener != nil {
+		s.screener.Completed(ctx, screening)
+	}
+	if s.autoSaver != nil {
+		s.autoSaver.RoundUp(ctx, transfer)
+	}
+	if beneficiary.ID != "" {
+		s.beneficiaries.Used(ctx, beneficiary)
+	}
+	return transfer, nil
+}
+
+// senderWallet returns the wallet a transfer is sent from: the own wallet of
+// the sender, or the wallet the transfer names, which the sender must be
+// allowed to spend from.
+func (s *transferService) senderWallet(ctx context.Context, sender entities.User, walletID string) (entities.Wallet, entities.WalletMember, error) {
+	if walletID != "" {
+		return walletMembership(ctx, s.walletRepository, s.members, sender, walletID, entities.PermissionSpend)
+	}
+	wallet, err := s.walletRepository.GetOrCreateWallet(sender.ID, ctx)
+	if err != nil {
+		return entities.Wallet{}, entities.WalletMember{}, err
+	}
+	return wallet, entities.WalletMember{WalletID: wallet.ID, UserID: sender.ID, Role: entities.MemberOwner, Status: entities.MemberActive}, nil
+}
+
+// findRecipient resolves the recipient of the transfer: the user saved as
+// the beneficiary it names, which must belong to the sender, or the one its
+// identifiers find.
+func (s *transferService) findRecipient(ctx context.Context, sender entities.User, transfer entities.Transfer) (entities.User, entities.Beneficiary, error) {
+	if transfer.BeneficiaryID == "" {
+		recipient, err := findRecipient(ctx, s.userRepository, transfer)
+		return recipient, entities.Beneficiary{}, err
+	}
+	if s.beneficiaries == nil || transfer.RecipientEmail != "" || transfer.RecipientPhone != 0 || transfer.RecipientDNI != 0 {
+		return entities.User{}, entities.Beneficiary{}, ErrRecipientRequired
+	}
+	beneficiary, err := s.beneficiaries.Beneficiary(ctx, sender, transfer.BeneficiaryID, entities.BeneficiaryWallet)
+	if err != nil {
+		return entities.User{}, entities.Beneficiary{}, err
+	}
+	recipient, err := s.userRepository.GetUser(beneficiary.RecipientUserID, ctx)
+	switch {
+	case errors.Is(err, repository_user.ErrDisbledUser):
+		return entities.User{}, entities.Beneficiary{}, ErrRecipientDisabled
+	case errors.Is(err, repository_user.ErrUserNotfound):
+		return entities.User{}, entities.Beneficiary{}, ErrRecipientNotFound
+	case err != nil:
+		return entities.User{}, entities.Beneficiary{}, err
+	}
+	return recipient, beneficiary, nil
+}
+
+// findRecipient resolves the recipient from the only identifier set in the
+// transfer. Disabled (soft deleted) users cannot receive money.
+func findRecipient(ctx context.Context, users repository_user.UserRepository, transfer entities.Transfer) (entities.User, error) {
+	var user entities.User
+	var err error
+	identifiers := 0
+	if transfer.RecipientEmail != "" {
+		identifiers++
+	}
+	if transfer.RecipientPhone != 0 {
+		identifiers++
+	}
+	if transfer.RecipientDNI != 0 {
+		identifiers++
+	}
+	if identifiers != 1 {
+		return entities.User{}, ErrRecipientRequired
+	}
+
+	switch {
+	case transfer.RecipientEmail != "":
+		user, err = users.GetUserByEmail(transfer.RecipientEmail, ctx)
+	case transfer.RecipientPhone != 0:
+		user, err = users.GetUserByPhone(transfer.RecipientPhone, ctx)
+	default:
+		user, err = users.GetUserByDNI(transfer.RecipientDNI, ctx)
+	}
+	switch {
+	case errors.Is(err, repository_user.ErrDisbledUser):
+		return entities.User{}, ErrRecipientDisabled
+	case errors.Is(err, repository_user.ErrUserNotfound):
+		return entities.User{}, ErrRecipientNotFound
+	case err != nil:
+		return entities.User{}, err
+	}
+	return user, nil
+}
+
+// screen runs the rules on the transfer and holds it for review when they
+// say so. Only allowed transfers get a nil error.
+func (s *transferService) screen(ctx context.Context, input entities.ScreeningInput, transfer entities.Transfer) error {
+	result, err := s.screener.Screen(ctx, input)
+	if err != nil {
+		return err
+	}
+	switch result.Action {
+	case entities.ScreeningBlock:
+		return ErrTransferBlocked
+	case entities.ScreeningHold:
+		review, err := s.screener.Hold(ctx, input, result, transfer)
+		if err != nil {
+			return err
+		}
+		return &TransferHeldError{ReviewID: review.ID}
+	}
+	return nil
+}
@@ -0,0 +1,508 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_limit "my_wallet/api/respository/limit"
+	repository_member "my_wallet/api/respository/member"
+	repository_screening "my_wallet/api/respository/screening"
+	repository_user "my_wallet/api/respository/user"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateTransferService(t *testing.T) {
+	sender := entities.User{ID: "sender", Email: "sender@gmail.com", Enabled: true}
+	recipient := entities.User{ID: "recipient", Email: "recipient@gmail.com", Enabled: true}
+	senderWallet := entities.Wallet{ID: "w1", UserID: "sender", Currency: "COP", Balances: map[string]int64{"COP": 10000, "USD": 50}}
+	recipientWallet := entities.Wallet{ID: "w2", UserID: "recipient", Currency: "COP"}
+
+	testScenarios := []struct {
+		testName       string
+		transfer       entities.Transfer
+		feeRules       []entities.FeeRule
+		configureMock  func(*userServiceMock, *walletRepositoryMock, *ledgerRepositoryMock)
+		expectedOutput entities.Transfer
+		expectedError  error
+	}{
+		{
+			testName: "TestCreateTransferService",
+			transfer: entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: 2500, Memo: "Dinner"},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+				u.On("GetUserByEmail", mock.Anything, "recipient@gmail.com").Return(recipient, nil)
+				w.On("GetOrCreateWallet", mock.Anything, "sender").Return(senderWallet, nil)
+				w.On("GetOrCreateWallet", mock.Anything, "recipient").Return(recipientWallet, nil)
+				l.On("PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+					return repository_ledger.IsBalanced(e) && e.Lines[0].Amount == -2500 && e.Lines[1].WalletID == "w2"
+				}), mock.Anything).Return(entities.LedgerEntry{ID: "entry"}, nil)
+			},
+			expectedOutput: entities.Transfer{
+				ID:                "entry",
+				SenderUserID:      "sender",
+				SenderWalletID:    "w1",
+				RecipientUserID:   "recipient",
+				RecipientWalletID: "w2",
+				RecipientEmail:    "recipient@gmail.com",
+				Amount:            2500,
+				Currency:          "COP",
+				Memo:              "Dinner",
+				Status:            entities.StatusCompleted,
+			},
+			expectedError: nil,
+		},
+		{
+			testName: "TestCreateTransferWithFee",
+			transfer: entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: 2500},
+			feeRules: []entities.FeeRule{{ID: "r1", Operation: entities.OperationTransfer, Kind: entities.FeePercentage, Bps: 100, MinFee: 50, Enabled: true}},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+				u.On("GetUserByEmail", mock.Anything, "recipient@gmail.com").Return(recipient, nil)
+				w.On("GetOrCreateWallet", mock.Anything, "sender").Return(senderWallet, nil)
+				w.On("GetOrCreateWallet", mock.Anything, "recipient").Return(recipientWallet, nil)
+				l.On("PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+					return repository_ledger.IsBalanced(e) && len(e.Lines) == 4 && e.Lines[2].Amount == -50 && e.Lines[3].Account == entities.AccountFeeRevenue
+				}), mock.Anything).Return(entities.LedgerEntry{ID: "entry"}, nil)
+			},
+			expectedOutput: entities.Transfer{
+				ID:                "entry",
+				SenderUserID:      "sender",
+				SenderWalletID:    "w1",
+				RecipientUserID:   "recipient",
+				RecipientWalletID: "w2",
+				RecipientEmail:    "recipient@gmail.com",
+				Amount:            2500,
+				Fee:               50,
+				Currency:          "COP",
+				Status:            entities.StatusCompleted,
+			},
+			expectedError: nil,
+		},
+		{
+			testName: "TestInsufficientFundsForFee",
+			transfer: entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: 10000},
+			feeRules: []entities.FeeRule{{ID: "r1", Operation: entities.OperationTransfer, Kind: entities.FeeFlat, FlatAmount: 1, Enabled: true}},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+				u.On("GetUserByEmail", mock.Anything, "recipient@gmail.com").Return(recipient, nil)
+				w.On("GetOrCreateWallet", mock.Anything, "sender").Return(senderWallet, nil)
+			},
+			expectedOutput: entities.Transfer{},
+			expectedError:  ErrInsufficientFunds,
+		},
+		{
+			testName: "TestInsufficientFundsInCurrency",
+			transfer: entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: 100, Currency: "USD"},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+				u.On("GetUserByEmail", mock.Anything, "recipient@gmail.com").Return(recipient, nil)
+				w.On("GetOrCreateWallet", mock.Anything, "sender").Return(senderWallet, nil)
+			},
+			expectedOutput: entities.Transfer{},
+			expectedError:  ErrInsufficientFunds,
+		},
+		{
+			testName:       "TestInvalidCurrency",
+			transfer:       entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: 100, Currency: "usd"},
+			expectedOutput: entities.Transfer{},
+			expectedError:  ErrInvalidCurrency,
+		},
+		{
+			testName:       "TestInvalidAmount",
+			transfer:       entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: 0},
+			expectedOutput: entities.Transfer{},
+			expectedError:  ErrInvalidAmount,
+		},
+		{
+			testName: "TestRecipientRequired",
+			transfer: entities.Transfer{RecipientEmail: "recipient@gmail.com", RecipientPhone: 3001234567, Amount: 100},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+			},
+			expectedOutput: entities.Transfer{},
+			expectedError:  ErrRecipientRequired,
+		},
+		{
+			testName: "TestRecipientDisabled",
+			transfer: entities.Transfer{RecipientDNI: 1002842747, Amount: 100},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+				u.On("GetUserByDNI", mock.Anything, 1002842747).Return(entities.User{}, repository_user.ErrDisbledUser)
+			},
+			expectedOutput: entities.Transfer{},
+			expectedError:  ErrRecipientDisabled,
+		},
+		{
+			testName: "TestRecipientNotFound",
+			transfer: entities.Transfer{RecipientPhone: 3001234567, Amount: 100},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+				u.On("GetUserByPhone", mock.Anything, 3001234567).Return(entities.User{}, repository_user.ErrUserNotfound)
+			},
+			expectedOutput: entities.Transfer{},
+			expectedError:  ErrRecipientNotFound,
+		},
+		{
+			testName: "TestSelfTransfer",
+			transfer: entities.Transfer{RecipientEmail: "sender@gmail.com", Amount: 100},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+			},
+			expectedOutput: entities.Transfer{},
+			expectedError:  ErrSelfTransfer,
+		},
+		{
+			testName: "TestInsufficientFunds",
+			transfer: entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: 20000},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+				u.On("GetUserByEmail", mock.Anything, "recipient@gmail.com").Return(recipient, nil)
+				w.On("GetOrCreateWallet", mock.Anything, "sender").Return(senderWallet, nil)
+			},
+			expectedOutput: entities.Transfer{},
+			expectedError:  ErrInsufficientFunds,
+		},
+		{
+			testName: "TestInsufficientFundsWhilePosting",
+			transfer: entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: 5000},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+				u.On("GetUserByEmail", mock.Anything, "recipient@gmail.com").Return(recipient, nil)
+				w.On("GetOrCreateWallet", mock.Anything, "sender").Return(senderWallet, nil)
+				w.On("GetOrCreateWallet", mock.Anything, "recipient").Return(recipientWallet, nil)
+				l.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{}, repository_ledger.ErrInsufficientFunds)
+			},
+			expectedOutput: entities.Transfer{},
+			expectedError:  ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			wallets := &walletRepositoryMock{}
+			ledger := &ledgerRepositoryMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(users, wallets, ledger)
+			}
+			feeRules := &feeRuleRepositoryMock{}
+			feeRules.On("ListFeeRules", mock.Anything, entities.OperationTransfer).Return(tt.feeRules, nil)
+			fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+			service := NewTransferService(users, wallets, ledger, fees, nil, nil, nil, nil, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateTransfer(context.Background(), "sender@gmail.com", tt.transfer)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			result.Created_at = tt.expectedOutput.Created_at
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestCreateTransferScreened(t *testing.T) {
+	sender := entities.User{ID: "sender", Email: "sender@gmail.com", Enabled: true}
+	recipient := entities.User{ID: "recipient", Email: "recipient@gmail.com", Enabled: true}
+	senderWallet := entities.Wallet{ID: "w1", UserID: "sender", Currency: "COP", Balances: map[string]int64{"COP": 10000000}}
+	recipientWallet := entities.Wallet{ID: "w2", UserID: "recipient", Currency: "COP"}
+	rules := repository_screening.NewStaticRuleSource([]entities.ScreeningRule{
+		{ID: "large", Name: "Large", Kind: entities.RuleAmountAbove, Action: entities.ScreeningHold, Enabled: true, Threshold: 1000000},
+		{ID: "huge", Name: "Huge", Kind: entities.RuleAmountAbove, Action: entities.ScreeningBlock, Enabled: true, Threshold: 5000000},
+	})
+
+	testScenarios := []struct {
+		testName      string
+		amount        int64
+		configureMock func(*screeningRepositoryMock)
+		expectedError error
+	}{
+		{
+			testName: "TestTransferHeld",
+			amount:   2000000,
+			configureMock: func(s *screeningRepositoryMock) {
+				s.On("CreateReview", mock.Anything, mock.MatchedBy(func(r entities.ScreeningReview) bool {
+					return r.Status == entities.ReviewPending && r.Transfer.Amount == 2000000 && r.Transfer.RecipientEmail == "recipient@gmail.com" && r.UserID == "sender"
+				})).Return(entities.ScreeningReview{ID: "r1"}, nil)
+			},
+			expectedError: &TransferHeldError{ReviewID: "r1"},
+		},
+		{
+			testName:      "TestTransferBlocked",
+			amount:        6000000,
+			configureMock: func(s *screeningRepositoryMock) {},
+			expectedError: ErrTransferBlocked,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+			users.On("GetUserByEmail", mock.Anything, "recipient@gmail.com").Return(recipient, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetOrCreateWallet", mock.Anything, "sender").Return(senderWallet, nil)
+			wallets.On("GetOrCreateWallet", mock.Anything, "recipient").Return(recipientWallet, nil)
+			ledger := &ledgerRepositoryMock{}
+			repo := &screeningRepositoryMock{}
+			tt.configureMock(repo)
+			feeRules := &feeRuleRepositoryMock{}
+			feeRules.On("ListFeeRules", mock.Anything, entities.OperationTransfer).Return([]entities.FeeRule{}, nil)
+			fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+			screener := NewScreeningEngine(repo, rules, logrus.StandardLogger())
+			service := NewTransferService(users, wallets, ledger, fees, screener, nil, nil, nil, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			_, err := service.CreateTransfer(context.Background(), "sender@gmail.com", entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: tt.amount})
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateTransferToBeneficiary(t *testing.T) {
+	sender := entities.User{ID: "sender", Email: "sender@gmail.com", Enabled: true}
+	recipient := entities.User{ID: "recipient", Email: "recipient@gmail.com", Enabled: true}
+	senderWallet := entities.Wallet{ID: "w1", UserID: "sender", Currency: "COP", Balances: map[string]int64{"COP": 50000000}}
+	recipientWallet := entities.Wallet{ID: "w2", UserID: "recipient", Currency: "COP"}
+	beneficiary := entities.Beneficiary{ID: "b1", UserID: "sender", Type: entities.BeneficiaryWallet, RecipientUserID: "recipient", CoolingOff_until: time.Now().Add(time.Hour)}
+
+	testScenarios := []struct {
+		testName      string
+		transfer      entities.Transfer
+		expectedError error
+	}{
+		{
+			testName: "TestCreateTransferToBeneficiary",
+			transfer: entities.Transfer{BeneficiaryID: "b1", Amount: 2500},
+		},
+		{
+			testName:      "TestCreateTransferToBeneficiaryWithEmail",
+			transfer:      entities.Transfer{BeneficiaryID: "b1", RecipientEmail: "recipient@gmail.com", Amount: 2500},
+			expectedError: ErrRecipientRequired,
+		},
+		{
+			testName:      "TestCreateTransferToBeneficiaryCoolingOff",
+			transfer:      entities.Transfer{BeneficiaryID: "b1", Amount: 25000000},
+			expectedError: ErrBeneficiaryCoolingOff,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+			users.On("GetUser", mock.Anything, "recipient").Return(recipient, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetOrCreateWallet", mock.Anything, "sender").Return(senderWallet, nil)
+			wallets.On("GetOrCreateWallet", mock.Anything, "recipient").Return(recipientWallet, nil)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{ID: "entry"}, nil)
+			repo := &beneficiaryRepositoryMock{}
+			repo.On("GetBeneficiary", mock.Anything, "b1").Return(beneficiary, nil)
+			repo.On("TouchBeneficiary", mock.Anything, "b1", mock.Anything).Return(nil)
+			beneficiaries := NewBeneficiaryService(users, repo, 24*time.Hour, map[string]int64{"COP": 20000000}, logrus.StandardLogger(), context.Background())
+			feeRules := &feeRuleRepositoryMock{}
+			feeRules.On("ListFeeRules", mock.Anything, entities.OperationTransfer).Return([]entities.FeeRule{}, nil)
+			fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+			service := NewTransferService(users, wallets, ledger, fees, nil, nil, nil, beneficiaries, nil, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateTransfer(context.Background(), "sender@gmail.com", tt.transfer)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, "recipient", result.RecipientUserID)
+				repo.AssertCalled(t, "TouchBeneficiary", mock.Anything, "b1", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCreateTransferReservesAmount(t *testing.T) {
+	// Prepare
+	sender := entities.User{ID: "sender", Email: "sender@gmail.com", Enabled: true}
+	recipient := entities.User{ID: "recipient", Email: "recipient@gmail.com", Enabled: true}
+	users := &userServiceMock{}
+	users.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+	users.On("GetUserByEmail", mock.Anything, "recipient@gmail.com").Return(recipient, nil)
+	wallets := &walletRepositoryMock{}
+	wallets.On("GetOrCreateWallet", mock.Anything, "sender").Return(entities.Wallet{ID: "w1", UserID: "sender", Currency: "COP", Balances: map[string]int64{"COP": 50000000}}, nil)
+	wallets.On("GetOrCreateWallet", mock.Anything, "recipient").Return(entities.Wallet{ID: "w2", UserID: "recipient", Currency: "COP"}, nil)
+	ledger := &ledgerRepositoryMock{}
+	ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{ID: "entry"}, nil)
+	feeRules := &feeRuleRepositoryMock{}
+	feeRules.On("ListFeeRules", mock.Anything, entities.OperationTransfer).Return([]entities.FeeRule{{ID: "r1", Kind: entities.FeeFlat, FlatAmount: 300, Enabled: true}}, nil)
+	fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+	limits := &limitRepositoryMock{}
+	limits.On("Reserve", mock.Anything, mock.Anything, int64(10000), int64(1000000), mock.Anything).Return(int64(10000), nil)
+	policy := repository_limit.NewStaticLimitPolicy(map[string]map[string]entities.Limits{
+		entities.KYCNone: {"COP": {Daily: 1000000}},
+	})
+	limiter := NewLimitService(users, limits, policy, logrus.StandardLogger(), context.Background())
+	service := NewTransferService(users, wallets, ledger, fees, nil, limiter, nil, nil, nil, nil, logrus.StandardLogger(), context.Background())
+
+	// Act
+	result, err := service.CreateTransfer(context.Background(), "sender@gmail.com", entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: 10000})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(300), result.Fee)
+	limits.AssertExpectations(t)
+}
+
+func TestCreateTransferRestricted(t *testing.T) {
+	sender := entities.User{ID: "sender", Email: "sender@gmail.com", Enabled: true}
+	recipient := entities.User{ID: "recipient", Email: "recipient@gmail.com", Enabled: true}
+	senderWallet := entities.Wallet{ID: "w1", UserID: "sender", Currency: "COP", Balances: map[string]int64{"COP": 10000}}
+	recipientWallet := entities.Wallet{ID: "w2", UserID: "recipient", Currency: "COP"}
+
+	testScenarios := []struct {
+		testName      string
+		sender        []entities.WalletRestriction
+		recipient     []entities.WalletRestriction
+		expectedError error
+	}{
+		{
+			testName: "TestTransferBetweenUnrestrictedWallets",
+		},
+		{
+			testName:      "TestTransferFromFrozenWallet",
+			sender:        []entities.WalletRestriction{{Kind: entities.RestrictionFreeze}},
+			expectedError: ErrWalletFrozen,
+		},
+		{
+			testName:      "TestTransferFromWalletRestrictedFromTransfers",
+			sender:        []entities.WalletRestriction{{Kind: entities.RestrictionCapabilities, Capabilities: []string{entities.CapabilityTransfer}}},
+			expectedError: ErrCapabilityRestricted,
+		},
+		{
+			testName:  "TestTransferToFrozenWallet",
+			recipient: []entities.WalletRestriction{{Kind: entities.RestrictionFreeze}},
+		},
+		{
+			testName:      "TestTransferToBlockedWallet",
+			recipient:     []entities.WalletRestriction{{Kind: entities.RestrictionBlock}},
+			expectedError: ErrRecipientDisabled,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "sender@gmail.com").Return(sender, nil)
+			users.On("GetUserByEmail", mock.Anything, "recipient@gmail.com").Return(recipient, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetOrCreateWallet", mock.Anything, "sender").Return(senderWallet, nil)
+			wallets.On("GetOrCreateWallet", mock.Anything, "recipient").Return(recipientWallet, nil)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{ID: "e1"}, nil)
+			feeRules := &feeRuleRepositoryMock{}
+			feeRules.On("ListFeeRules", mock.Anything, entities.OperationTransfer).Return([]entities.FeeRule{}, nil)
+			fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+			restrictionRepo := &restrictionRepositoryMock{}
+			restrictionRepo.On("ActiveRestrictions", mock.Anything, "w1").Return(tt.sender, nil)
+			restrictionRepo.On("ActiveRestrictions", mock.Anything, "w2").Return(tt.recipient, nil)
+			restrictions := NewRestrictionService(users, wallets, restrictionRepo, nil, logrus.StandardLogger(), context.Background())
+			service := NewTransferService(users, wallets, ledger, fees, nil, nil, nil, nil, restrictions, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			_, err := service.CreateTransfer(context.Background(), "sender@gmail.com", entities.Transfer{RecipientEmail: "recipient@gmail.com", Amount: 5000})
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError != nil {
+				ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCreateTransferFromSharedWallet(t *testing.T) {
+	spender := entities.User{ID: "spender", Email: "spender@gmail.com", Enabled: true}
+	recipient := entities.User{ID: "recipient", Email: "recipient@gmail.com", Enabled: true}
+	sharedWallet := entities.Wallet{ID: "w1", UserID: "owner", Currency: "COP", Balances: map[string]int64{"COP": 100000}}
+	recipientWallet := entities.Wallet{ID: "w2", UserID: "recipient", Currency: "COP"}
+
+	testScenarios := []struct {
+		testName      string
+		membership    entities.WalletMember
+		amount        int64
+		reserveError  error
+		expectedError error
+	}{
+		{
+			testName:   "TestTransferBySpenderWithinLimit",
+			membership: entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "spender", Role: entities.MemberSpender, Status: entities.MemberActive, SpendingLimits: map[string]int64{"COP": 10000}},
+			amount:     5000,
+		},
+		{
+			testName:      "TestTransferBySpenderOverLimit",
+			membership:    entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "spender", Role: entities.MemberSpender, Status: entities.MemberActive, SpendingLimits: map[string]int64{"COP": 10000}},
+			amount:        20000,
+			expectedError: ErrSpendingLimitExceeded,
+		},
+		{
+			testName:      "TestTransferBySpenderMonthSpent",
+			membership:    entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "spender", Role: entities.MemberSpender, Status: entities.MemberActive, SpendingLimits: map[string]int64{"COP": 10000}},
+			amount:        5000,
+			reserveError:  repository_member.ErrSpendingLimitReached,
+			expectedError: ErrSpendingLimitExceeded,
+		},
+		{
+			testName:      "TestTransferByViewer",
+			membership:    entities.WalletMember{ID: "m1", WalletID: "w1", UserID: "spender", Role: entities.MemberViewer, Status: entities.MemberActive},
+			amount:        5000,
+			expectedError: ErrWalletPermission,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "spender@gmail.com").Return(spender, nil)
+			users.On("GetUserByEmail", mock.Anything, "recipient@gmail.com").Return(recipient, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetWallet", mock.Anything, "w1").Return(sharedWallet, nil)
+			wallets.On("GetOrCreateWallet", mock.Anything, "recipient").Return(recipientWallet, nil)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{ID: "e1"}, nil)
+			feeRules := &feeRuleRepositoryMock{}
+			feeRules.On("ListFeeRules", mock.Anything, entities.OperationTransfer).Return([]entities.FeeRule{}, nil)
+			fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+			memberRepo := &memberRepositoryMock{}
+			memberRepo.On("GetMembership", mock.Anything, "w1", "spender").Return(tt.membership, nil)
+			memberRepo.On("ReserveSpending", mock.Anything, mock.Anything, tt.amount, mock.Anything).Return(tt.amount, tt.reserveError)
+			members := NewMemberService(users, wallets, memberRepo, nil, logrus.StandardLogger(), context.Background())
+			service := NewTransferService(users, wallets, ledger, fees, nil, nil, nil, nil, nil, members, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateTransfer(context.Background(), "spender@gmail.com", entities.Transfer{SenderWalletID: "w1", RecipientEmail: "recipient@gmail.com", Amount: tt.amount})
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, "w1", result.SenderWalletID)
+				ledger.AssertCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.MatchedBy(func(transactions []entities.Transaction) bool {
+					return transactions[0].WalletID == "w1" && transactions[0].UserID == "owner" && transactions[0].InitiatedBy == "spender"
+				}))
+			} else {
+				ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -41,7 +41,32 @@ func (m *userServiceMock) GetUserByEmail(email string, ctx context.Context) (ent
 
 }
 
+func (m *userServiceMock) GetUserByPhone(phone int, ctx context.Context) (entities.User, error) {
+	r := m.Called(ctx, phone)
+	return r.Get(0).(entities.User), r.Error(1)
+}
+
+func (m *userServiceMock) GetUserByDNI(dni int, ctx context.Context) (entities.User, error) {
+	r := m.Called(ctx, dni)
+	return r.Get(0).(entities.User), r.Error(1)
+}
+
 func (m *userServiceMock) UpdateUserToken(userUpr entities.User, ctx context.Context) (entities.User, error) {
 	r := m.Called(ctx, userUpr)
 	return r.Get(0).(entities.User), r.Error(1)
 }
+
+func (m *userServiceMock) SetKYCLevel(id string, level string, ctx context.Context) error {
+	r := m.Called(ctx, id, level)
+	return r.Error(0)
+}
+
+func (m *userServiceMock) SetStatus(id string, status string, ctx context.Context) error {
+	r := m.Called(ctx, id, status)
+	return r.Error(0)
+}
+
+func (m *userServiceMock) ListUsers(afterID string, limit int64, ctx context.Context) ([]entities.User, error) {
+	r := m.Called(ctx, afterID, limit)
+	return r.Get(0).([]entities.User), r.Error(1)
+}
@@ -26,14 +26,16 @@ type UserService interface {
 type userService struct {
 	ctx        context.Context
 	repository repository_user.UserRepository
+	sanctions  SanctionsScreener
 	logger     logrus.FieldLogger
 	validate   *validator.Validate
 }
 
-func NewUserService(repo repository_user.UserRepository, logger logrus.FieldLogger, ctx context.Context) *userService {
+func NewUserService(repo repository_user.UserRepository, sanctions SanctionsScreener, logger logrus.FieldLogger, ctx context.Context) *userService {
 	return &userService{
 		ctx:        ctx,
 		repository: repo,
+		sanctions:  sanctions,
 		logger:     logger,
 		validate:   validator.New(),
 	}
@@ -81,7 +83,33 @@ func (s *userService) CreateUser(ctx context.Context, user entities.User) (entit
 	user.Token = token
 	user.RefreshToken = refreshToken
 
-	return s.repository.CreateUser(user, ctx)
+	// Users whose name matches the sanctions list are registered, but held
+	// for a compliance review before they can move money. A user whose review
+	// cannot be stored is not registered at all, so no match goes unreviewed.
+	var matches []entities.SanctionsMatch
+	var listVersion string
+	if s.sanctions != nil {
+		matches, listVersion, err = s.sanctions.CheckUser(ctx, user)
+		if err != nil {
+			s.logger.Errorln("Layer: user_services", "Method: CreateUser", "Error:", err)
+			return entities.User{}, err
+		}
+		if len(matches) > 0 {
+			user.Status = entities.UserPendingReview
+		}
+	}
+	created, err := s.repository.CreateUser(user, ctx)
+	if err != nil || len(matches) == 0 {
+		return created, err
+	}
+	if _, err := s.sanctions.HoldUser(ctx, created, matches, listVersion, entities.SanctionsAtRegistration); err != nil {
+		s.logger.Errorln("Layer: user_services", "Method: CreateUser", "Error:", err)
+		if err := s.repository.DeleteUser(created.ID, ctx); err != nil {
+			s.logger.Errorln("Layer: user_services", "Method: CreateUser", "Error:", err)
+		}
+		return entities.User{}, err
+	}
+	return created, nil
 
 }
 
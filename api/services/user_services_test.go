@@ -531,7 +531,7 @@ func TestNewUserService(t *testing.T) {
 		t.Run(tt.testName, func(t *testing.T) {
 
 			// Act
-			result := NewUserService(tt.mockRepo, tt.mockLogger, tt.mockContext)
+			result := NewUserService(tt.mockRepo, nil, tt.mockLogger, tt.mockContext)
 
 			// Assert
 			assert.NotNil(t, result)
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type walletRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *walletRepositoryMock) GetWallet(id string, ctx context.Context) (entities.Wallet, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.Wallet), r.Error(1)
+}
+
+func (m *walletRepositoryMock) GetOrCreateWallet(userID string, ctx context.Context) (entities.Wallet, error) {
+	r := m.Called(ctx, userID)
+	return r.Get(0).(entities.Wallet), r.Error(1)
+}
+
+type ledgerRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ledgerRepositoryMock) PostEntry(entry entities.LedgerEntry, transactions []entities.Transaction, ctx context.Context) (entities.LedgerEntry, []entities.Transaction, error) {
+	r := m.Called(ctx, entry, transactions)
+	return r.Get(0).(entities.LedgerEntry), transactions, r.Error(1)
+}
+
+type transactionRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *transactionRepositoryMock) GetTransaction(id string, ctx context.Context) (entities.Transaction, error) {
+	r := m.Called(ctx, id)
+	return r.Get(0).(entities.Transaction), r.Error(1)
+}
+
+func (m *transactionRepositoryMock) SetCategory(id string, category string, ctx context.Context) error {
+	r := m.Called(ctx, id, category)
+	return r.Error(0)
+}
+
+func (m *transactionRepositoryMock) ListTransactions(filter entities.TransactionFilter, ctx context.Context) ([]entities.Transaction, error) {
+	r := m.Called(ctx, filter)
+	return r.Get(0).([]entities.Transaction), r.Error(1)
+}
+
+func (m *ledgerRepositoryMock) WalletBalanceAt(walletID string, currency string, at time.Time, ctx context.Context) (int64, error) {
+	r := m.Called(ctx, walletID, currency, at)
+	return r.Get(0).(int64), r.Error(1)
+}
+
+func (m *transactionRepositoryMock) SumTransactions(walletID string, currency string, from time.Time, to time.Time, ctx context.Context) (map[string]int64, error) {
+	r := m.Called(ctx, walletID, currency, from, to)
+	return r.Get(0).(map[string]int64), r.Error(1)
+}
+
+func (m *transactionRepositoryMock) StreamTransactions(walletID string, currency string, from time.Time, to time.Time, ctx context.Context, fn func(entities.Transaction) error) error {
+	r := m.Called(ctx, walletID, currency, from, to)
+	for _, transaction := range r.Get(0).([]entities.Transaction) {
+		if err := fn(transaction); err != nil {
+			return err
+		}
+	}
+	return r.Error(1)
+}
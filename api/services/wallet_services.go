@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"my_wallet/api/entities"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var transactionTypes = map[string]bool{
+	entities.TransactionDeposit:     true,
+	entities.TransactionWithdrawal:  true,
+	entities.TransactionTransferIn:  true,
+	entities.TransactionTransferOut: true,
+	entities.TransactionFee:         true,
+	entities.TransactionToPocket:    true,
+	entities.TransactionFromPocket:  true,
+	entities.TransactionReversalIn:  true,
+	entities.TransactionReversalOut: true,
+	entities.TransactionPaymentOut:  true,
+	entities.TransactionPaymentIn:   true,
+	entities.TransactionInterest:    true,
+	entities.TransactionCardPayment: true,
+}
+
+type WalletService interface {
+	ListTransactions(ctx context.Context, email string, filter entities.TransactionFilter, cursor string) (entities.TransactionPage, error)
+	GetStatement(ctx context.Context, email string, walletID string, currency string, from time.Time, to time.Time) (entities.Statement, error)
+}
+
+type walletService struct {
+	ctx                   context.Context
+	userRepository        repository_user.UserRepository
+	walletRepository      repository_wallet.WalletRepository
+	ledgerRepository      repository_ledger.LedgerRepository
+	transactionRepository repository_ledger.TransactionRepository
+	members               Memberships
+	logger                logrus.FieldLogger
+}
+
+func NewWalletService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, ledgerRepo repository_ledger.LedgerRepository, transactionRepo repository_ledger.TransactionRepository, members Memberships, logger logrus.FieldLogger, ctx context.Context) *walletService {
+	return &walletService{
+		ctx:                   ctx,
+		userRepository:        userRepo,
+		walletRepository:      walletRepo,
+		ledgerRepository:      ledgerRepo,
+		transactionRepository: transactionRepo,
+		members:               members,
+		logger:                logger,
+	}
+}
+
+// ListTransactions returns one page of the history of a wallet the
+// authenticated user owns or is a member of. cursor is the NextCursor of the
+// previous page.
+func (s *walletService) ListTransactions(ctx context.Context, email string, filter entities.TransactionFilter, cursor string) (entities.TransactionPage, error) {
+	if err := validateTransactionFilter(filter); err != nil {
+		s.logger.Errorln("Layer: wallet_services", "Method: ListTransactions", "Error:", err)
+		return entities.TransactionPage{}, err
+	}
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			s.logger.Errorln("Layer: wallet_services", "Method: ListTransactions", "Error:", err)
+			return entities.TransactionPage{}, err
+		}
+		filter.After = &after
+	}
+	if _, _, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, filter.WalletID, entities.PermissionView); err != nil {
+		s.logger.Errorln("Layer: wallet_services", "Method: ListTransactions", "Error:", err)
+		return entities.TransactionPage{}, err
+	}
+
+	pageSize := filter.Limit
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	// One extra row tells whether there is a next page.
+	filter.Limit = pageSize + 1
+	transactions, err := s.transactionRepository.ListTransactions(filter, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: wallet_services", "Method: ListTransactions", "Error:", err)
+		if errors.Is(err, repository_ledger.ErrInvalidCursor) {
+			return entities.TransactionPage{}, ErrInvalidCursor
+		}
+		return entities.TransactionPage{}, err
+	}
+
+	page := entities.TransactionPage{Transactions: transactions}
+	if len(transactions) > pageSize {
+		page.Transactions = transactions[:pageSize]
+		last := page.Transactions[pageSize-1]
+		page.NextCursor = encodeCursor(entities.TransactionCursor{Created_at: last.Created_at, ID: last.ID})
+	}
+	return page, nil
+}
+
+// GetStatement returns the statement of one currency of the wallet for
+// [from, to), the default currency when none is given. Opening and closing
+// balances come from the ledger and must match the transactions of the
+// period, otherwise the statement is refused. The statement is in the name
+// of the owner of the wallet, whichever member asks for it.
+func (s *walletService) GetStatement(ctx context.Context, email string, walletID string, currency string, from time.Time, to time.Time) (entities.Statement, error) {
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		s.logger.Errorln("Layer: wallet_services", "Method: GetStatement", "Error:", ErrInvalidStatementPeriod)
+		return entities.Statement{}, ErrInvalidStatementPeriod
+	}
+	// Entries posted while the statement streams must not land in a period
+	// that was already reconciled.
+	if now := time.Now().UTC(); to.After(now) {
+		to = now
+	}
+	owner, wallet, _, err := memberWallet(ctx, s.userRepository, s.walletRepository, s.members, email, walletID, entities.PermissionView)
+	if err != nil {
+		s.logger.Errorln("Layer: wallet_services", "Method: GetStatement", "Error:", err)
+		return entities.Statement{}, err
+	}
+	if owner.ID != wallet.UserID {
+		if owner, err = s.userRepository.GetUser(wallet.UserID, ctx); err != nil {
+			s.logger.Errorln("Layer: wallet_services", "Method: GetStatement", "Error:", err)
+			return entities.Statement{}, err
+		}
+	}
+	if currency == "" {
+		currency = wallet.Currency
+	}
+	if !entities.ValidCurrency(currency) {
+		s.logger.Errorln("Layer: wallet_services", "Method: GetStatement", "Error:", ErrInvalidCurrency)
+		return entities.Statement{}, ErrInvalidCurrency
+	}
+
+	opening, err := s.ledgerRepository.WalletBalanceAt(wallet.ID, currency, from, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: wallet_services", "Method: GetStatement", "Error:", err)
+		return entities.Statement{}, err
+	}
+	closing, err := s.ledgerRepository.WalletBalanceAt(wallet.ID, currency, to, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: wallet_services", "Method: GetStatement", "Error:", err)
+		return entities.Statement{}, err
+	}
+	totals, err := s.transactionRepository.SumTransactions(wallet.ID, currency, from, to, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: wallet_services", "Method: GetStatement", "Error:", err)
+		return entities.Statement{}, err
+	}
+	movement := int64(0)
+	for transactionType, total := range totals {
+		movement += entities.Transaction{Type: transactionType, Amount: total}.SignedAmount()
+	}
+	if opening+movement != closing {
+		s.logger.Errorln("Layer: wallet_services", "Method: GetStatement", "Error:", ErrStatementNotReconciled, "Opening:", opening, "Movement:", movement, "Closing:", closing)
+		return entities.Statement{}, ErrStatementNotReconciled
+	}
+
+	repo := s.transactionRepository
+	return entities.Statement{
+		WalletID:       wallet.ID,
+		UserName:       owner.Name,
+		Currency:       currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		ClosingBalance: closing,
+		Stream: func(ctx context.Context, fn func(entities.Transaction) error) error {
+			return repo.StreamTransactions(wallet.ID, currency, from, to, ctx, fn)
+		},
+	}, nil
+}
+
+func validateTransactionFilter(filter entities.TransactionFilter) error {
+	for _, transactionType := range filter.Types {
+		if !transactionTypes[transactionType] {
+			return ErrInvalidTransactionFilter
+		}
+	}
+	if filter.Currency != "" && !entities.ValidCurrency(filter.Currency) {
+		return ErrInvalidTransactionFilter
+	}
+	if filter.MinAmount < 0 || filter.MaxAmount < 0 {
+		return ErrInvalidTransactionFilter
+	}
+	if filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount {
+		return ErrInvalidTransactionFilter
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return ErrInvalidTransactionFilter
+	}
+	return nil
+}
+
+func encodeCursor(cursor entities.TransactionCursor) string {
+	raw := fmt.Sprintf("%d:%s", cursor.Created_at.UnixNano(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (entities.TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return entities.TransactionCursor{}, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return entities.TransactionCursor{}, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return entities.TransactionCursor{}, ErrInvalidCursor
+	}
+	return entities.TransactionCursor{Created_at: time.Unix(0, nanos).UTC(), ID: parts[1]}, nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListTransactionsService(t *testing.T) {
+	owner := entities.User{ID: "owner", Email: "owner@gmail.com", Enabled: true}
+	wallet := entities.Wallet{ID: "w1", UserID: "owner"}
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	transactions := []entities.Transaction{
+		{ID: "t3", WalletID: "w1", Created_at: createdAt},
+		{ID: "t2", WalletID: "w1", Created_at: createdAt},
+		{ID: "t1", WalletID: "w1", Created_at: createdAt.Add(-time.Hour)},
+	}
+
+	testScenarios := []struct {
+		testName       string
+		filter         entities.TransactionFilter
+		cursor         string
+		configureMock  func(*userServiceMock, *walletRepositoryMock, *transactionRepositoryMock)
+		expectedOutput entities.TransactionPage
+		expectedError  error
+	}{
+		{
+			testName: "TestListTransactionsWithNextPage",
+			filter:   entities.TransactionFilter{WalletID: "w1", Limit: 2},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, r *transactionRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "owner@gmail.com").Return(owner, nil)
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+				r.On("ListTransactions", mock.Anything, entities.TransactionFilter{WalletID: "w1", Limit: 3}).Return(transactions, nil)
+			},
+			expectedOutput: entities.TransactionPage{
+				Transactions: transactions[:2],
+				NextCursor:   encodeCursor(entities.TransactionCursor{Created_at: createdAt, ID: "t2"}),
+			},
+		},
+		{
+			testName: "TestListTransactionsLastPage",
+			filter:   entities.TransactionFilter{WalletID: "w1", Types: []string{entities.TransactionTransferIn}},
+			cursor:   encodeCursor(entities.TransactionCursor{Created_at: createdAt, ID: "t2"}),
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, r *transactionRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "owner@gmail.com").Return(owner, nil)
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+				r.On("ListTransactions", mock.Anything, mock.MatchedBy(func(f entities.TransactionFilter) bool {
+					return f.After != nil && f.After.ID == "t2" && f.After.Created_at.Equal(createdAt) && f.Limit == defaultPageSize+1
+				})).Return(transactions[2:], nil)
+			},
+			expectedOutput: entities.TransactionPage{Transactions: transactions[2:]},
+		},
+		{
+			testName: "TestListTransactionsForbidden",
+			filter:   entities.TransactionFilter{WalletID: "w2"},
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, r *transactionRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "owner@gmail.com").Return(owner, nil)
+				w.On("GetWallet", mock.Anything, "w2").Return(entities.Wallet{ID: "w2", UserID: "other"}, nil)
+			},
+			expectedOutput: entities.TransactionPage{},
+			expectedError:  ErrWalletForbidden,
+		},
+		{
+			testName:       "TestListTransactionsInvalidType",
+			filter:         entities.TransactionFilter{WalletID: "w1", Types: []string{"refund"}},
+			expectedOutput: entities.TransactionPage{},
+			expectedError:  ErrInvalidTransactionFilter,
+		},
+		{
+			testName:       "TestListTransactionsInvalidAmountRange",
+			filter:         entities.TransactionFilter{WalletID: "w1", MinAmount: 500, MaxAmount: 100},
+			expectedOutput: entities.TransactionPage{},
+			expectedError:  ErrInvalidTransactionFilter,
+		},
+		{
+			testName:       "TestListTransactionsInvalidCursor",
+			filter:         entities.TransactionFilter{WalletID: "w1"},
+			cursor:         "not a cursor",
+			expectedOutput: entities.TransactionPage{},
+			expectedError:  ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			wallets := &walletRepositoryMock{}
+			repo := &transactionRepositoryMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(users, wallets, repo)
+			}
+			service := NewWalletService(users, wallets, &ledgerRepositoryMock{}, repo, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.ListTransactions(context.Background(), "owner@gmail.com", tt.filter, tt.cursor)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
+
+func TestGetStatementService(t *testing.T) {
+	owner := entities.User{ID: "owner", Name: "Owner", Email: "owner@gmail.com", Enabled: true}
+	wallet := entities.Wallet{ID: "w1", UserID: "owner", Currency: "COP"}
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	testScenarios := []struct {
+		testName      string
+		from          time.Time
+		to            time.Time
+		configureMock func(*userServiceMock, *walletRepositoryMock, *ledgerRepositoryMock, *transactionRepositoryMock)
+		expectedOpen  int64
+		expectedClose int64
+		expectedError error
+	}{
+		{
+			testName: "TestGetStatementReconciled",
+			from:     from,
+			to:       to,
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock, r *transactionRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "owner@gmail.com").Return(owner, nil)
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+				l.On("WalletBalanceAt", mock.Anything, "w1", "COP", from).Return(int64(1000), nil)
+				l.On("WalletBalanceAt", mock.Anything, "w1", "COP", to).Return(int64(4750), nil)
+				r.On("SumTransactions", mock.Anything, "w1", "COP", from, to).Return(map[string]int64{
+					entities.TransactionTransferIn:  5000,
+					entities.TransactionTransferOut: 1250,
+				}, nil)
+			},
+			expectedOpen:  1000,
+			expectedClose: 4750,
+		},
+		{
+			testName: "TestGetStatementNotReconciled",
+			from:     from,
+			to:       to,
+			configureMock: func(u *userServiceMock, w *walletRepositoryMock, l *ledgerRepositoryMock, r *transactionRepositoryMock) {
+				u.On("GetUserByEmail", mock.Anything, "owner@gmail.com").Return(owner, nil)
+				w.On("GetWallet", mock.Anything, "w1").Return(wallet, nil)
+				l.On("WalletBalanceAt", mock.Anything, "w1", "COP", from).Return(int64(1000), nil)
+				l.On("WalletBalanceAt", mock.Anything, "w1", "COP", to).Return(int64(5000), nil)
+				r.On("SumTransactions", mock.Anything, "w1", "COP", from, to).Return(map[string]int64{entities.TransactionTransferIn: 5000}, nil)
+			},
+			expectedError: ErrStatementNotReconciled,
+		},
+		{
+			testName:      "TestGetStatementInvalidPeriod",
+			from:          to,
+			to:            from,
+			expectedError: ErrInvalidStatementPeriod,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			wallets := &walletRepositoryMock{}
+			ledger := &ledgerRepositoryMock{}
+			repo := &transactionRepositoryMock{}
+			if tt.configureMock != nil {
+				tt.configureMock(users, wallets, ledger, repo)
+			}
+			service := NewWalletService(users, wallets, ledger, repo, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.GetStatement(context.Background(), "owner@gmail.com", "w1", "", tt.from, tt.to)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOpen, result.OpeningBalance)
+			assert.Equal(t, tt.expectedClose, result.ClosingBalance)
+		})
+	}
+}
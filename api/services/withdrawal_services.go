@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"my_wallet/api/entities"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+type WithdrawalService interface {
+	CreateWithdrawal(ctx context.Context, email string, withdrawal entities.Withdrawal) (entities.Withdrawal, error)
+}
+
+type withdrawalService struct {
+	ctx              context.Context
+	userRepository   repository_user.UserRepository
+	walletRepository repository_wallet.WalletRepository
+	ledgerRepository repository_ledger.LedgerRepository
+	feeService       FeeService
+	screener         Screener
+	limiter          Limiter
+	beneficiaries    Beneficiaries
+	restrictions     Restrictions
+	logger           logrus.FieldLogger
+}
+
+func NewWithdrawalService(userRepo repository_user.UserRepository, walletRepo repository_wallet.WalletRepository, ledgerRepo repository_ledger.LedgerRepository, feeService FeeService, screener Screener, limiter Limiter, beneficiaries Beneficiaries, restrictions Restrictions, logger logrus.FieldLogger, ctx context.Context) *withdrawalService {
+	return &withdrawalService{
+		ctx:              ctx,
+		userRepository:   userRepo,
+		walletRepository: walletRepo,
+		ledgerRepository: ledgerRepo,
+		feeService:       feeService,
+		screener:         screener,
+		limiter:          limiter,
+		beneficiaries:    beneficiaries,
+		restrictions:     restrictions,
+		logger:           logger,
+	}
+}
+
+// CreateWithdrawal pays money out of the wallet of the authenticated user to
+// the bank account of one of their beneficiaries, in the default currency of
+// the wallet unless it names one. The amount moves to the payouts clearing
+// account, where it waits for the bank to settle it, and the withdrawal fee
+// goes to revenue in the same ledger entry. Like transfers, withdrawals are
+// screened by the fraud and AML rules and taken out of the limits of the
+// user, fee included, and beneficiaries still cooling off only receive small
+// amounts. Wallets restricted from withdrawals cannot withdraw.
+func (s *withdrawalService) CreateWithdrawal(ctx context.Context, email string, withdrawal entities.Withdrawal) (entities.Withdrawal, error) {
+	return s.createWithdrawal(ctx, email, withdrawal, true)
+}
+
+// ExecuteReviewedWithdrawal executes a withdrawal an analyst released from
+// the review queue, without screening it again.
+func (s *withdrawalService) ExecuteReviewedWithdrawal(ctx context.Context, email string, withdrawal entities.Withdrawal) (entities.Withdrawal, error) {
+	return s.createWithdrawal(ctx, email, withdrawal, false)
+}
+
+func (s *withdrawalService) createWithdrawal(ctx context.Context, email string, withdrawal entities.Withdrawal, screen bool) (entities.Withdrawal, error) {
+	if withdrawal.Amount <= 0 {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", ErrInvalidAmount)
+		return entities.Withdrawal{}, ErrInvalidAmount
+	}
+	if withdrawal.Currency != "" && !entities.ValidCurrency(withdrawal.Currency) {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", ErrInvalidCurrency)
+		return entities.Withdrawal{}, ErrInvalidCurrency
+	}
+	if utf8.RuneCountInString(withdrawal.Memo) > maxMemoLength {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", ErrMemoTooLong)
+		return entities.Withdrawal{}, ErrMemoTooLong
+	}
+	if withdrawal.BeneficiaryID == "" {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", ErrWithdrawalBeneficiaryRequired)
+		return entities.Withdrawal{}, ErrWithdrawalBeneficiaryRequired
+	}
+
+	user, err := s.userRepository.GetUserByEmail(email, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", err)
+		return entities.Withdrawal{}, err
+	}
+	if err := requireActive(user); err != nil {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", err)
+		return entities.Withdrawal{}, err
+	}
+	beneficiary, err := s.beneficiaries.Beneficiary(ctx, user, withdrawal.BeneficiaryID, entities.BeneficiaryBank)
+	if err != nil {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", err)
+		return entities.Withdrawal{}, err
+	}
+	wallet, err := s.walletRepository.GetOrCreateWallet(user.ID, ctx)
+	if err != nil {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", err)
+		return entities.Withdrawal{}, err
+	}
+	currency := withdrawal.Currency
+	if currency == "" {
+		currency = wallet.Currency
+	}
+	if err := s.beneficiaries.CheckCoolingOff(beneficiary, currency, withdrawal.Amount); err != nil {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", err)
+		return entities.Withdrawal{}, err
+	}
+	fee, err := s.feeService.ComputeFee(ctx, user, entities.OperationWithdrawal, currency, withdrawal.Amount)
+	if err != nil {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", err)
+		return entities.Withdrawal{}, err
+	}
+	if wallet.Balances[currency] < fee.Total {
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", ErrInsufficientFunds)
+		return entities.Withdrawal{}, ErrInsufficientFunds
+	}
+	if s.restrictions != nil {
+		if err := s.restrictions.CheckDebit(ctx, wallet.ID, entities.CapabilityWithdrawal); err != nil {
+			s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", err)
+			return entities.Withdrawal{}, err
+		}
+	}
+
+	memo := withdrawal.Memo
+	if memo == "" {
+		memo = "Withdrawal to " + beneficiary.Nickname
+	}
+	entry := entities.LedgerEntry{
+		Type: entities.EntryWithdrawal,
+		Memo: memo,
+		Lines: []entities.LedgerLine{
+			{Account: entities.WalletAccount(wallet.ID), WalletID: wallet.ID, Currency: currency, Amount: -withdrawal.Amount},
+			{Account: entities.AccountPayouts, Currency: currency, Amount: withdrawal.Amount},
+		},
+		Created_at: time.Now().UTC(),
+	}
+	transactions := []entities.Transaction{
+		{
+			WalletID: wallet.ID,
+			UserID:   user.ID,
+			Type:     entities.TransactionWithdrawal,
+			Status:   entities.StatusCompleted,
+			Amount:   withdrawal.Amount,
+			Currency: currency,
+			Memo:     memo,
+		},
+	}
+	if fee.Fee > 0 {
+		entry.Lines = append(entry.Lines, feeLines(wallet.ID, currency, fee.Fee)...)
+		transactions = append(transactions, entities.Transaction{
+			WalletID: wallet.ID,
+			UserID:   user.ID,
+			Type:     entities.TransactionFee,
+			Status:   entities.StatusCompleted,
+			Amount:   fee.Fee,
+			Currency: currency,
+			Memo:     "Withdrawal fee",
+		})
+	}
+
+	screening := entities.ScreeningInput{
+		Operation: entities.OperationWithdrawal,
+		UserID:    user.ID,
+		WalletID:  wallet.ID,
+		DeviceID:  withdrawal.DeviceID,
+		Currency:  currency,
+		Amount:    withdrawal.Amount,
+	}
+	if screen && s.screener != nil {
+		if err := s.screen(ctx, screening, withdrawal); err != nil {
+			s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", err)
+			return entities.Withdrawal{}, err
+		}
+	}
+
+	var reservation entities.LimitReservation
+	if s.limiter != nil {
+		reservation, err = s.limiter.Reserve(ctx, user, currency, withdrawal.Amount)
+		if err != nil {
+			s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", err)
+			return entities.Withdrawal{}, err
+		}
+	}
+	entry, _, err = s.ledgerRepository.PostEntry(entry, transactions, ctx)
+	if err != nil {
+		if s.limiter != nil {
+			s.limiter.Release(ctx, reservation)
+		}
+		s.logger.Errorln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Error:", err)
+		if errors.Is(err, repository_ledger.ErrInsufficientFunds) {
+			return entities.Withdrawal{}, ErrInsufficientFunds
+		}
+		return entities.Withdrawal{}, err
+	}
+	if s.screener != nil {
+		s.screener.Completed(ctx, screening)
+	}
+	s.beneficiaries.Used(ctx, beneficiary)
+
+	withdrawal.ID = entry.ID
+	withdrawal.UserID = user.ID
+	withdrawal.WalletID = wallet.ID
+	withdrawal.Bank = *beneficiary.Bank
+	withdrawal.Fee = fee.Fee
+	withdrawal.Currency = currency
+	withdrawal.Memo = memo
+	withdrawal.Status = entities.StatusCompleted
+	withdrawal.Created_at = entry.Created_at
+	s.logger.Infoln("Layer: withdrawal_services", "Method: CreateWithdrawal", "Withdrawal:", withdrawal.ID)
+	return withdrawal, nil
+}
+
+// screen runs the rules on the withdrawal and holds it for review when they
+// say so. Only allowed withdrawals get a nil error.
+func (s *withdrawalService) screen(ctx context.Context, input entities.ScreeningInput, withdrawal entities.Withdrawal) error {
+	result, err := s.screener.Screen(ctx, input)
+	if err != nil {
+		return err
+	}
+	switch result.Action {
+	case entities.ScreeningBlock:
+		return ErrTransferBlocked
+	case entities.ScreeningHold:
+		review, err := s.screener.HoldWithdrawal(ctx, input, result, withdrawal)
+		if err != nil {
+			return err
+		}
+		return &TransferHeldError{ReviewID: review.ID}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"my_wallet/api/entities"
+	repository_ledger "my_wallet/api/respository/ledger"
+	repository_limit "my_wallet/api/respository/limit"
+	repository_screening "my_wallet/api/respository/screening"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateWithdrawalService(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com", Enabled: true}
+	wallet := entities.Wallet{ID: "w1", UserID: "u1", Currency: "COP", Balances: map[string]int64{"COP": 50000000}}
+	account := entities.BankAccount{BankCode: "1007", BankName: entities.Banks["1007"], AccountType: entities.BankSavings, AccountNumber: "12345678901", HolderName: "Alexer"}
+	bank := entities.Beneficiary{ID: "b1", UserID: "u1", Type: entities.BeneficiaryBank, Nickname: "Savings", Bank: &account, CoolingOff_until: time.Now().Add(-time.Hour)}
+	coolingBank := bank
+	coolingBank.CoolingOff_until = time.Now().Add(time.Hour)
+
+	testScenarios := []struct {
+		testName      string
+		withdrawal    entities.Withdrawal
+		beneficiary   entities.Beneficiary
+		postError     error
+		expectedError error
+	}{
+		{
+			testName:    "TestCreateWithdrawalService",
+			withdrawal:  entities.Withdrawal{BeneficiaryID: "b1", Amount: 25000000},
+			beneficiary: bank,
+		},
+		{
+			testName:      "TestCreateWithdrawalWithoutBeneficiary",
+			withdrawal:    entities.Withdrawal{Amount: 100},
+			expectedError: ErrWithdrawalBeneficiaryRequired,
+		},
+		{
+			testName:      "TestCreateWithdrawalToWalletBeneficiary",
+			withdrawal:    entities.Withdrawal{BeneficiaryID: "b1", Amount: 100},
+			beneficiary:   entities.Beneficiary{ID: "b1", UserID: "u1", Type: entities.BeneficiaryWallet},
+			expectedError: ErrBeneficiaryType,
+		},
+		{
+			testName:      "TestCreateWithdrawalToBeneficiaryOfOtherUser",
+			withdrawal:    entities.Withdrawal{BeneficiaryID: "b1", Amount: 100},
+			beneficiary:   entities.Beneficiary{ID: "b1", UserID: "u9", Type: entities.BeneficiaryBank, Bank: &account},
+			expectedError: ErrBeneficiaryNotFound,
+		},
+		{
+			testName:      "TestCreateWithdrawalCoolingOff",
+			withdrawal:    entities.Withdrawal{BeneficiaryID: "b1", Amount: 25000000},
+			beneficiary:   coolingBank,
+			expectedError: ErrBeneficiaryCoolingOff,
+		},
+		{
+			testName:      "TestCreateWithdrawalInsufficientFunds",
+			withdrawal:    entities.Withdrawal{BeneficiaryID: "b1", Amount: 100},
+			beneficiary:   bank,
+			postError:     repository_ledger.ErrInsufficientFunds,
+			expectedError: ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetOrCreateWallet", mock.Anything, "u1").Return(wallet, nil)
+			ledger := &ledgerRepositoryMock{}
+			ledger.On("PostEntry", mock.Anything, mock.MatchedBy(func(e entities.LedgerEntry) bool {
+				return repository_ledger.IsBalanced(e) && e.Type == entities.EntryWithdrawal && e.Lines[1].Account == entities.AccountPayouts
+			}), mock.Anything).Return(entities.LedgerEntry{ID: "e1"}, tt.postError)
+			repo := &beneficiaryRepositoryMock{}
+			repo.On("GetBeneficiary", mock.Anything, "b1").Return(tt.beneficiary, nil)
+			repo.On("TouchBeneficiary", mock.Anything, "b1", mock.Anything).Return(nil)
+			beneficiaries := NewBeneficiaryService(users, repo, 24*time.Hour, map[string]int64{"COP": 20000000}, logrus.StandardLogger(), context.Background())
+			feeRules := &feeRuleRepositoryMock{}
+			feeRules.On("ListFeeRules", mock.Anything, entities.OperationWithdrawal).Return([]entities.FeeRule{}, nil)
+			fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+			service := NewWithdrawalService(users, wallets, ledger, fees, nil, nil, beneficiaries, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			result, err := service.CreateWithdrawal(context.Background(), "alexer@gmail.com", tt.withdrawal)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, "e1", result.ID)
+				assert.Equal(t, "COP", result.Currency)
+				assert.Equal(t, "12345678901", result.Bank.AccountNumber)
+				repo.AssertCalled(t, "TouchBeneficiary", mock.Anything, "b1", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCreateWithdrawalScreened(t *testing.T) {
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com", Enabled: true}
+	wallet := entities.Wallet{ID: "w1", UserID: "u1", Currency: "COP", Balances: map[string]int64{"COP": 50000000}}
+	account := entities.BankAccount{BankCode: "1007", BankName: entities.Banks["1007"], AccountType: entities.BankSavings, AccountNumber: "12345678901", HolderName: "Alexer"}
+	bank := entities.Beneficiary{ID: "b1", UserID: "u1", Type: entities.BeneficiaryBank, Nickname: "Savings", Bank: &account, CoolingOff_until: time.Now().Add(-time.Hour)}
+	rules := repository_screening.NewStaticRuleSource([]entities.ScreeningRule{
+		{ID: "large", Name: "Large", Kind: entities.RuleAmountAbove, Action: entities.ScreeningHold, Enabled: true, Threshold: 1000000},
+		{ID: "huge", Name: "Huge", Kind: entities.RuleAmountAbove, Action: entities.ScreeningBlock, Enabled: true, Threshold: 5000000},
+	})
+
+	testScenarios := []struct {
+		testName      string
+		amount        int64
+		configureMock func(*screeningRepositoryMock)
+		expectedError error
+	}{
+		{
+			testName: "TestWithdrawalHeld",
+			amount:   2000000,
+			configureMock: func(s *screeningRepositoryMock) {
+				s.On("CreateReview", mock.Anything, mock.MatchedBy(func(r entities.ScreeningReview) bool {
+					return r.Status == entities.ReviewPending && r.Withdrawal != nil && r.Withdrawal.Amount == 2000000 && r.Withdrawal.BeneficiaryID == "b1" && r.UserID == "u1"
+				})).Return(entities.ScreeningReview{ID: "r1"}, nil)
+			},
+			expectedError: &TransferHeldError{ReviewID: "r1"},
+		},
+		{
+			testName:      "TestWithdrawalBlocked",
+			amount:        6000000,
+			configureMock: func(s *screeningRepositoryMock) {},
+			expectedError: ErrTransferBlocked,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Prepare
+			users := &userServiceMock{}
+			users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+			wallets := &walletRepositoryMock{}
+			wallets.On("GetOrCreateWallet", mock.Anything, "u1").Return(wallet, nil)
+			ledger := &ledgerRepositoryMock{}
+			beneficiaryRepo := &beneficiaryRepositoryMock{}
+			beneficiaryRepo.On("GetBeneficiary", mock.Anything, "b1").Return(bank, nil)
+			beneficiaries := NewBeneficiaryService(users, beneficiaryRepo, 24*time.Hour, map[string]int64{}, logrus.StandardLogger(), context.Background())
+			feeRules := &feeRuleRepositoryMock{}
+			feeRules.On("ListFeeRules", mock.Anything, entities.OperationWithdrawal).Return([]entities.FeeRule{}, nil)
+			fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+			repo := &screeningRepositoryMock{}
+			tt.configureMock(repo)
+			screener := NewScreeningEngine(repo, rules, logrus.StandardLogger())
+			service := NewWithdrawalService(users, wallets, ledger, fees, screener, nil, beneficiaries, nil, logrus.StandardLogger(), context.Background())
+
+			// Act
+			_, err := service.CreateWithdrawal(context.Background(), "alexer@gmail.com", entities.Withdrawal{BeneficiaryID: "b1", Amount: tt.amount})
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			ledger.AssertNotCalled(t, "PostEntry", mock.Anything, mock.Anything, mock.Anything)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateWithdrawalReservesAmount(t *testing.T) {
+	// Prepare
+	user := entities.User{ID: "u1", Email: "alexer@gmail.com", Enabled: true}
+	wallet := entities.Wallet{ID: "w1", UserID: "u1", Currency: "COP", Balances: map[string]int64{"COP": 50000000}}
+	account := entities.BankAccount{BankCode: "1007", BankName: entities.Banks["1007"], AccountType: entities.BankSavings, AccountNumber: "12345678901", HolderName: "Alexer"}
+	bank := entities.Beneficiary{ID: "b1", UserID: "u1", Type: entities.BeneficiaryBank, Nickname: "Savings", Bank: &account, CoolingOff_until: time.Now().Add(-time.Hour)}
+	users := &userServiceMock{}
+	users.On("GetUserByEmail", mock.Anything, "alexer@gmail.com").Return(user, nil)
+	wallets := &walletRepositoryMock{}
+	wallets.On("GetOrCreateWallet", mock.Anything, "u1").Return(wallet, nil)
+	ledger := &ledgerRepositoryMock{}
+	ledger.On("PostEntry", mock.Anything, mock.Anything, mock.Anything).Return(entities.LedgerEntry{ID: "e1"}, nil)
+	beneficiaryRepo := &beneficiaryRepositoryMock{}
+	beneficiaryRepo.On("GetBeneficiary", mock.Anything, "b1").Return(bank, nil)
+	beneficiaryRepo.On("TouchBeneficiary", mock.Anything, "b1", mock.Anything).Return(nil)
+	beneficiaries := NewBeneficiaryService(users, beneficiaryRepo, 24*time.Hour, map[string]int64{}, logrus.StandardLogger(), context.Background())
+	feeRules := &feeRuleRepositoryMock{}
+	feeRules.On("ListFeeRules", mock.Anything, entities.OperationWithdrawal).Return([]entities.FeeRule{{ID: "r1", Kind: entities.FeeFlat, FlatAmount: 300, Enabled: true}}, nil)
+	fees := NewFeeService(users, feeRules, logrus.StandardLogger(), context.Background())
+	limits := &limitRepositoryMock{}
+	limits.On("Reserve", mock.Anything, mock.Anything, int64(10000), int64(1000000), mock.Anything).Return(int64(10000), nil)
+	policy := repository_limit.NewStaticLimitPolicy(map[string]map[string]entities.Limits{
+		entities.KYCNone: {"COP": {Daily: 1000000}},
+	})
+	limiter := NewLimitService(users, limits, policy, logrus.StandardLogger(), context.Background())
+	service := NewWithdrawalService(users, wallets, ledger, fees, nil, limiter, beneficiaries, nil, logrus.StandardLogger(), context.Background())
+
+	// Act
+	result, err := service.CreateWithdrawal(context.Background(), "alexer@gmail.com", entities.Withdrawal{BeneficiaryID: "b1", Amount: 10000})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(300), result.Fee)
+	limits.AssertExpectations(t)
+}
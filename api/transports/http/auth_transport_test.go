@@ -0,0 +1,110 @@
+package transports
+
+import (
+	"context"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/entities"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type beneficiaryServiceMock struct {
+	mock.Mock
+}
+
+func (m *beneficiaryServiceMock) Beneficiary(ctx context.Context, user entities.User, id string, beneficiaryType string) (entities.Beneficiary, error) {
+	r := m.Called(ctx, user, id, beneficiaryType)
+	return r.Get(0).(entities.Beneficiary), r.Error(1)
+}
+
+func (m *beneficiaryServiceMock) CheckCoolingOff(beneficiary entities.Beneficiary, currency string, amount int64) error {
+	return m.Called(beneficiary, currency, amount).Error(0)
+}
+
+func (m *beneficiaryServiceMock) Used(ctx context.Context, beneficiary entities.Beneficiary) {
+	m.Called(ctx, beneficiary)
+}
+
+func (m *beneficiaryServiceMock) CreateBeneficiary(ctx context.Context, email string, beneficiary entities.NewBeneficiary) (entities.Beneficiary, error) {
+	r := m.Called(ctx, email, beneficiary)
+	return r.Get(0).(entities.Beneficiary), r.Error(1)
+}
+
+func (m *beneficiaryServiceMock) ListBeneficiaries(ctx context.Context, email string) ([]entities.Beneficiary, error) {
+	r := m.Called(ctx, email)
+	return r.Get(0).([]entities.Beneficiary), r.Error(1)
+}
+
+func (m *beneficiaryServiceMock) UpdateBeneficiary(ctx context.Context, email string, id string, nickname string, favorite bool) (entities.Beneficiary, error) {
+	r := m.Called(ctx, email, id, nickname, favorite)
+	return r.Get(0).(entities.Beneficiary), r.Error(1)
+}
+
+func (m *beneficiaryServiceMock) DeleteBeneficiary(ctx context.Context, email string, id string) error {
+	return m.Called(ctx, email, id).Error(0)
+}
+
+func TestAuthenticatedRequest(t *testing.T) {
+	viper.Set("SECRET_KEY", "test-secret")
+	defer viper.Set("SECRET_KEY", nil)
+	token, _, err := jwt.GenerateToken("payer@gmail.com", logrus.StandardLogger())
+	assert.NoError(t, err)
+
+	testScenarios := []struct {
+		name          string
+		authorization string
+		expectedCode  int
+		expectedEmail string
+	}{
+		{
+			name:          "valid token",
+			authorization: "Bearer " + token,
+			expectedCode:  http.StatusOK,
+			expectedEmail: "payer@gmail.com",
+		},
+		{
+			name:          "tampered token",
+			authorization: "Bearer " + token + "x",
+			expectedCode:  http.StatusUnauthorized,
+		},
+		{
+			name:         "missing token",
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Prepare
+			service := &beneficiaryServiceMock{}
+			service.On("ListBeneficiaries", mock.Anything, mock.Anything).Return([]entities.Beneficiary{}, nil)
+			handler := NewHTTPHandler(endpoints.Endpoints{
+				ListBeneficiariesEndpoint: endpoints.MakeListBeneficiariesEndpoint(service, logrus.StandardLogger()),
+			}, logrus.StandardLogger())
+			req := httptest.NewRequest(http.MethodGet, "/beneficiaries", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			// Act
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedEmail != "" {
+				service.AssertCalled(t, "ListBeneficiaries", mock.Anything, tt.expectedEmail)
+			} else {
+				service.AssertNotCalled(t, "ListBeneficiaries", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeBeneficiaryResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeCreateBeneficiaryResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeDeleteBeneficiaryResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func decodeCreateBeneficiaryRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreateBeneficiaryRequest
+	err := json.NewDecoder(r.Body).Decode(&req.Beneficiary)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func decodeListBeneficiariesRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListBeneficiariesRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeUpdateBeneficiaryRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.UpdateBeneficiaryRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.ID = r.PathValue("id")
+	return req, nil
+}
+
+func decodeBeneficiaryRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.BeneficiaryRequest{Email: jwt.EmailFromContext(ctx), ID: r.PathValue("id")}, nil
+}
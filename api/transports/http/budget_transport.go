@@ -0,0 +1,45 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeBudgetResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeCreateBudgetResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeDeleteBudgetResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// decodeBudgetRequest reads the budget from the body; the id in the path, if
+// any, is the budget to update.
+func decodeBudgetRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.BudgetRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.ID = r.PathValue("id")
+	return req, nil
+}
+
+func decodeListBudgetsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListBudgetsRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeGetBudgetRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.GetBudgetRequest{Email: jwt.EmailFromContext(ctx), ID: r.PathValue("id")}, nil
+}
@@ -0,0 +1,79 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/cardnet"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+// maxCardMessageBytes is read from a message of the card network at most;
+// its messages are much smaller.
+const maxCardMessageBytes = 64 << 10
+
+func encodeCardResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeIssueCardResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+// encodeCardNetworkMessageResponse writes the answer as the card network
+// reads it, without an envelope.
+func encodeCardNetworkMessageResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response.(endpoints.CardNetworkMessageResponse).Response)
+}
+
+func decodeIssueCardRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.IssueCardRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func decodeListCardsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListCardsRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeUpdateCardLimitsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.UpdateCardLimitsRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.CardID = r.PathValue("id")
+	return req, nil
+}
+
+func decodeFreezeCardRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.FreezeCardRequest{Email: jwt.EmailFromContext(ctx), CardID: r.PathValue("id"), Frozen: true}, nil
+}
+
+func decodeUnfreezeCardRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.FreezeCardRequest{Email: jwt.EmailFromContext(ctx), CardID: r.PathValue("id"), Frozen: false}, nil
+}
+
+func decodeListCardAuthorizationsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListCardAuthorizationsRequest{Email: jwt.EmailFromContext(ctx), CardID: r.PathValue("id")}, nil
+}
+
+// decodeCardNetworkMessageRequest keeps the message as it was sent, since
+// its signature is over the raw body.
+func decodeCardNetworkMessageRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCardMessageBytes))
+	if err != nil {
+		return nil, err
+	}
+	return endpoints.CardNetworkMessageRequest{Body: body, Signature: r.Header.Get(cardnet.SignatureHeader)}, nil
+}
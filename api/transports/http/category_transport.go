@@ -0,0 +1,68 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeCategoryResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeCreateCategoryResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeListCategoriesRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListCategoriesRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeCreateCategoryRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreateCategoryRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func decodeListCategoryRulesRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListCategoryRulesRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeCategoryRuleRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CategoryRuleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func encodeDeleteCategoryRuleResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func decodeDeleteCategoryRuleRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.DeleteCategoryRuleRequest{Email: jwt.EmailFromContext(ctx), ID: r.PathValue("id")}, nil
+}
+
+func decodeRecategorizeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.RecategorizeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	req.TransactionID = r.PathValue("transactionId")
+	return req, nil
+}
@@ -0,0 +1,90 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+	"strconv"
+)
+
+func encodeDisputeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeOpenDisputeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+// encodeDisputeEvidenceResponse writes the evidence as it was uploaded.
+func encodeDisputeEvidenceResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	evidence := response.(endpoints.GetDisputeEvidenceResponse)
+	w.Header().Set("Content-Type", evidence.Evidence.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(evidence.Data)))
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(evidence.Data)
+	return err
+}
+
+func decodeOpenDisputeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.OpenDisputeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func decodeListDisputesRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListDisputesRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeListDisputeQueueRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListDisputeQueueRequest{Email: jwt.EmailFromContext(ctx), Status: r.URL.Query().Get("status")}, nil
+}
+
+func decodeDisputeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.DisputeRequest{Email: jwt.EmailFromContext(ctx), DisputeID: r.PathValue("id")}, nil
+}
+
+// decodeAddDisputeEvidenceRequest takes the file from the raw body, its type
+// from the Content-Type header and its name from the query.
+func decodeAddDisputeEvidenceRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxKYCUploadBytes))
+	if err != nil {
+		return nil, err
+	}
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return endpoints.AddDisputeEvidenceRequest{
+		Email:       jwt.EmailFromContext(ctx),
+		DisputeID:   r.PathValue("id"),
+		Name:        r.URL.Query().Get("name"),
+		ContentType: contentType,
+		Data:        data,
+	}, nil
+}
+
+func decodeGetDisputeEvidenceRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.GetDisputeEvidenceRequest{
+		Email:      jwt.EmailFromContext(ctx),
+		DisputeID:  r.PathValue("id"),
+		EvidenceID: r.PathValue("evidence_id"),
+	}, nil
+}
+
+func decodeResolveDisputeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.ResolveDisputeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.DisputeID = r.PathValue("id")
+	return req, nil
+}
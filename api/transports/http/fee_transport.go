@@ -0,0 +1,65 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeQuoteFeeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeQuoteFeeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.QuoteFeeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func encodeListFeeRulesResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeListFeeRulesRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListFeeRulesRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func encodeCreateFeeRuleResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeUpdateFeeRuleResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+// decodeFeeRuleRequest reads the rule from the body; the id in the path, if
+// any, names the rule being replaced.
+func decodeFeeRuleRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.FeeRuleRequest
+	err := json.NewDecoder(r.Body).Decode(&req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.ID = r.PathValue("id")
+	return req, nil
+}
+
+func encodeDeleteFeeRuleResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func decodeDeleteFeeRuleRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.DeleteFeeRuleRequest{Email: jwt.EmailFromContext(ctx), ID: r.PathValue("id")}, nil
+}
@@ -0,0 +1,41 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeCreateQuoteResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeCreateQuoteRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreateQuoteRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	return req, nil
+}
+
+func encodeCreateConversionResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeCreateConversionRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreateConversionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	return req, nil
+}
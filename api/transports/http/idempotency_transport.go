@@ -0,0 +1,13 @@
+package transports
+
+import (
+	"context"
+	"my_wallet/api/endpoints"
+	"net/http"
+)
+
+// populateIdempotencyKey copies the Idempotency-Key header into the context
+// for endpoints wrapped with endpoints.IdempotencyMiddleware.
+func populateIdempotencyKey(ctx context.Context, r *http.Request) context.Context {
+	return endpoints.ContextWithIdempotencyKey(ctx, r.Header.Get("Idempotency-Key"))
+}
@@ -0,0 +1,24 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeGetInsightsResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetInsightsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+	return endpoints.GetInsightsRequest{
+		Email:       jwt.EmailFromContext(ctx),
+		WalletID:    r.PathValue("id"),
+		Period:      query.Get("period"),
+		Granularity: query.Get("granularity"),
+	}, nil
+}
@@ -0,0 +1,33 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeInterestResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetInterestRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.GetInterestRequest{Email: jwt.EmailFromContext(ctx), WalletID: r.PathValue("id")}, nil
+}
+
+// decodeAccrueInterestRequest reads the day to accrue, YYYY-MM-DD or RFC 3339.
+func decodeAccrueInterestRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		Day string `json:"day"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	day, err := parseTimeQuery(body.Day)
+	if err != nil {
+		return nil, err
+	}
+	return endpoints.AccrueInterestRequest{Email: jwt.EmailFromContext(ctx), Day: day}, nil
+}
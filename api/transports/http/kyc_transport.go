@@ -0,0 +1,99 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+	"strconv"
+)
+
+// maxKYCUploadBytes is read from an upload at most: one byte over the
+// largest document the service accepts, so it can tell the document is too
+// large.
+const maxKYCUploadBytes = 5<<20 + 1
+
+func encodeKYCResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeCreateKYCApplicationResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+// encodeKYCDocumentResponse writes the document as it was uploaded.
+func encodeKYCDocumentResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	document := response.(endpoints.GetKYCDocumentResponse)
+	w.Header().Set("Content-Type", document.Document.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(document.Data)))
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(document.Data)
+	return err
+}
+
+func decodeGetKYCRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.GetKYCRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeCreateKYCApplicationRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreateKYCApplicationRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+// decodeUploadKYCDocumentRequest takes the document from the raw body and its
+// type from the Content-Type header.
+func decodeUploadKYCDocumentRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxKYCUploadBytes))
+	if err != nil {
+		return nil, err
+	}
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return endpoints.UploadKYCDocumentRequest{
+		Email:         jwt.EmailFromContext(ctx),
+		ApplicationID: r.PathValue("id"),
+		DocumentType:  r.PathValue("type"),
+		ContentType:   contentType,
+		Data:          data,
+	}, nil
+}
+
+func decodeKYCApplicationRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.KYCApplicationRequest{Email: jwt.EmailFromContext(ctx), ApplicationID: r.PathValue("id")}, nil
+}
+
+func decodeListKYCApplicationsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListKYCApplicationsRequest{Email: jwt.EmailFromContext(ctx), Status: r.URL.Query().Get("status")}, nil
+}
+
+func decodeReviewKYCApplicationRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.ReviewKYCApplicationRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.ApplicationID = r.PathValue("id")
+	return req, nil
+}
+
+func decodeGetKYCDocumentRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.GetKYCDocumentRequest{
+		Email:         jwt.EmailFromContext(ctx),
+		ApplicationID: r.PathValue("id"),
+		DocumentType:  r.PathValue("type"),
+	}, nil
+}
+
+func decodeListKYCEventsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListKYCEventsRequest{Email: jwt.EmailFromContext(ctx), UserID: r.PathValue("id")}, nil
+}
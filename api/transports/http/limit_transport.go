@@ -0,0 +1,21 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeGetLimitsResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetLimitsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.GetLimitsRequest{
+		Email:    jwt.EmailFromContext(ctx),
+		Currency: r.URL.Query().Get("currency"),
+	}, nil
+}
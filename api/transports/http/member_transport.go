@@ -0,0 +1,62 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeMemberResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeInviteMemberResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeInviteMemberRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.InviteMemberRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	return req, nil
+}
+
+func decodeListMembersRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListMembersRequest{Email: jwt.EmailFromContext(ctx), WalletID: r.PathValue("id")}, nil
+}
+
+func decodeUpdateMemberRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.UpdateMemberRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	req.MemberID = r.PathValue("memberId")
+	return req, nil
+}
+
+func decodeRemoveMemberRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.RemoveMemberRequest{Email: jwt.EmailFromContext(ctx), WalletID: r.PathValue("id"), MemberID: r.PathValue("memberId")}, nil
+}
+
+func decodeListMembershipsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListMembershipsRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeAcceptInvitationRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.RespondInvitationRequest{Email: jwt.EmailFromContext(ctx), MemberID: r.PathValue("id"), Accept: true}, nil
+}
+
+func decodeDeclineInvitationRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.RespondInvitationRequest{Email: jwt.EmailFromContext(ctx), MemberID: r.PathValue("id"), Accept: false}, nil
+}
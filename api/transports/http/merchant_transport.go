@@ -0,0 +1,66 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeMerchantResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeMerchantCreatedResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeCreateMerchantRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreateMerchantRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func decodeMerchantRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.MerchantRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeCreateQRCodeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreateQRCodeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func decodePayQRCodeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.PayQRCodeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func decodeSettlementReportRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var err error
+	req := endpoints.SettlementReportRequest{Email: jwt.EmailFromContext(ctx)}
+	query := r.URL.Query()
+	if req.From, err = parseTimeQuery(query.Get("from")); err != nil {
+		return nil, err
+	}
+	if req.To, err = parseTimeQuery(query.Get("to")); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
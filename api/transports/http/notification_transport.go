@@ -0,0 +1,25 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeNotificationResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeListNotificationsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListNotificationsRequest{
+		Email:      jwt.EmailFromContext(ctx),
+		UnreadOnly: r.URL.Query().Get("unread") == "true",
+	}, nil
+}
+
+func decodeMarkNotificationReadRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.MarkNotificationReadRequest{Email: jwt.EmailFromContext(ctx), ID: r.PathValue("id")}, nil
+}
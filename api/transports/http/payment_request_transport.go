@@ -0,0 +1,42 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeCreatePaymentRequestResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeCreatePaymentRequestRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreatePaymentRequestRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func encodePaymentRequestResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeListPaymentRequestsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+	return endpoints.ListPaymentRequestsRequest{
+		Email:     jwt.EmailFromContext(ctx),
+		Direction: query.Get("direction"),
+		Status:    query.Get("status"),
+	}, nil
+}
+
+func decodeAnswerPaymentRequestRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.AnswerPaymentRequestRequest{Email: jwt.EmailFromContext(ctx), ID: r.PathValue("id")}, nil
+}
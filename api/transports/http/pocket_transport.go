@@ -0,0 +1,55 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeCreatePocketResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodePocketResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodePocketRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.PocketRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	req.PocketID = r.PathValue("pocketId")
+	return req, nil
+}
+
+func decodeListPocketsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListPocketsRequest{Email: jwt.EmailFromContext(ctx), WalletID: r.PathValue("id")}, nil
+}
+
+func decodeGetPocketRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.GetPocketRequest{
+		Email:    jwt.EmailFromContext(ctx),
+		WalletID: r.PathValue("id"),
+		PocketID: r.PathValue("pocketId"),
+	}, nil
+}
+
+func decodeMovePocketRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.MovePocketRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	req.PocketID = r.PathValue("pocketId")
+	return req, nil
+}
@@ -0,0 +1,26 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeReconciliationResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeListReconciliationsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListReconciliationsRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeGetReconciliationRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.GetReconciliationRequest{
+		Email:  jwt.EmailFromContext(ctx),
+		RunID:  r.PathValue("id"),
+		Result: r.URL.Query().Get("result"),
+	}, nil
+}
@@ -0,0 +1,50 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeRestrictionResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodePlaceRestrictionResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodePlaceRestrictionRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.PlaceRestrictionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	return req, nil
+}
+
+func decodeListRestrictionsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListRestrictionsRequest{Email: jwt.EmailFromContext(ctx), WalletID: r.PathValue("id")}, nil
+}
+
+// decodeLiftRestrictionRequest reads the optional note of the lift; an empty
+// body is no note.
+func decodeLiftRestrictionRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.LiftRestrictionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	req.RestrictionID = r.PathValue("restrictionId")
+	return req, nil
+}
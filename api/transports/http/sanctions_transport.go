@@ -0,0 +1,33 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeSanctionsResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeListSanctionsScreeningsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListSanctionsScreeningsRequest{Email: jwt.EmailFromContext(ctx), Status: r.URL.Query().Get("status")}, nil
+}
+
+// decodeSanctionsScreeningRequest reads the optional note of the analyst; an
+// empty body is no note.
+func decodeSanctionsScreeningRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.SanctionsScreeningRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.ID = r.PathValue("id")
+	return req, nil
+}
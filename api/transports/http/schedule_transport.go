@@ -0,0 +1,37 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeCreateScheduleResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeCreateScheduleRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreateScheduleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func encodeScheduleResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeListSchedulesRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListSchedulesRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeScheduleRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ScheduleRequest{Email: jwt.EmailFromContext(ctx), ID: r.PathValue("id")}, nil
+}
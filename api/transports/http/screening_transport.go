@@ -0,0 +1,48 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeScreeningResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeListScreeningRulesRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListScreeningRulesRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeSaveScreeningRuleRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.SaveScreeningRuleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.ID = r.PathValue("id")
+	return req, nil
+}
+
+func decodeListScreeningReviewsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListScreeningReviewsRequest{Email: jwt.EmailFromContext(ctx), Status: r.URL.Query().Get("status")}, nil
+}
+
+// decodeScreeningReviewRequest reads the optional note of the analyst; an
+// empty body is no note.
+func decodeScreeningReviewRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.ScreeningReviewRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	req.ID = r.PathValue("id")
+	return req, nil
+}
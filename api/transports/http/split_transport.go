@@ -0,0 +1,37 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeCreateSplitResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeCreateSplitRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreateSplitRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = jwt.EmailFromContext(ctx)
+	return req, nil
+}
+
+func encodeSplitResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeListSplitsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.ListSplitsRequest{Email: jwt.EmailFromContext(ctx)}, nil
+}
+
+func decodeGetSplitRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return endpoints.GetSplitRequest{Email: jwt.EmailFromContext(ctx), ID: r.PathValue("id")}, nil
+}
@@ -0,0 +1,156 @@
+package transports
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"my_wallet/api/entities"
+	"strings"
+	"time"
+)
+
+const (
+	pdfPageWidth    = 595
+	pdfPageHeight   = 842
+	pdfMargin       = 40
+	pdfFontSize     = 8
+	pdfLeading      = 11
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+)
+
+// Objects 1, 2 and 3 are the catalog, the page tree and the font. The page
+// tree is written last because its kids are only known at the end.
+const (
+	pdfCatalogObject = 1
+	pdfPagesObject   = 2
+	pdfFontObject    = 3
+)
+
+// pdfStatementWriter writes a text only PDF with a fixed width font. Pages are
+// flushed as soon as they are full, so only one page is held in memory.
+type pdfStatementWriter struct {
+	w       io.Writer
+	written int64
+	offsets map[int]int64
+	next    int
+	pages   []int
+	lines   []string
+	err     error
+}
+
+func newPDFStatementWriter(w io.Writer) *pdfStatementWriter {
+	return &pdfStatementWriter{
+		w:       w,
+		offsets: map[int]int64{},
+		next:    pdfFontObject + 1,
+	}
+}
+
+func (p *pdfStatementWriter) Opening(statement entities.Statement) error {
+	p.write("%PDF-1.4\n")
+	p.object(pdfFontObject, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	p.line("My Wallet - Account statement")
+	p.line(fmt.Sprintf("Holder: %s", statement.UserName))
+	p.line(fmt.Sprintf("Wallet: %s  Currency: %s", statement.WalletID, statement.Currency))
+	p.line(fmt.Sprintf("Period: %s to %s", statement.From.Format(time.DateOnly), statement.To.Format(time.DateOnly)))
+	p.line("")
+	p.line(fmt.Sprintf("%-16s %-12s %-24s %14s %14s", "Date", "Type", "Memo", "Amount", "Balance"))
+	p.line(fmt.Sprintf("%-16s %-12s %-24s %14s %14s", statement.From.Format("2006-01-02 15:04"), "opening", "", "", formatAmount(statement.OpeningBalance)))
+	return p.err
+}
+
+func (p *pdfStatementWriter) Transaction(transaction entities.Transaction, balance int64) error {
+	memo := transaction.Memo
+	if len([]rune(memo)) > 24 {
+		memo = string([]rune(memo)[:24])
+	}
+	p.line(fmt.Sprintf("%-16s %-12s %-24s %14s %14s", transaction.Created_at.Format("2006-01-02 15:04"), transaction.Type, memo, formatAmount(transaction.SignedAmount()), formatAmount(balance)))
+	return p.err
+}
+
+func (p *pdfStatementWriter) Closing(statement entities.Statement) error {
+	p.line(fmt.Sprintf("%-16s %-12s %-24s %14s %14s", statement.To.Format("2006-01-02 15:04"), "closing", "", "", formatAmount(statement.ClosingBalance)))
+	p.flushPage()
+
+	kids := make([]string, len(p.pages))
+	for i, page := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	p.object(pdfPagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	p.object(pdfCatalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObject))
+
+	xref := p.written
+	p.write(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", p.next))
+	for id := 1; id < p.next; id++ {
+		p.write(fmt.Sprintf("%010d 00000 n \n", p.offsets[id]))
+	}
+	p.write(fmt.Sprintf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", p.next, pdfCatalogObject, xref))
+	return p.err
+}
+
+func (p *pdfStatementWriter) line(text string) {
+	p.lines = append(p.lines, text)
+	if len(p.lines) == pdfLinesPerPage {
+		p.flushPage()
+	}
+}
+
+func (p *pdfStatementWriter) flushPage() {
+	if len(p.lines) == 0 {
+		return
+	}
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+	for _, text := range p.lines {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", pdfEscape(text))
+	}
+	content.WriteString("ET\n")
+	p.lines = p.lines[:0]
+
+	contentObject := p.allocate()
+	p.object(contentObject, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	pageObject := p.allocate()
+	p.object(pageObject, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObject, pdfPageWidth, pdfPageHeight, pdfFontObject, contentObject))
+	p.pages = append(p.pages, pageObject)
+}
+
+func (p *pdfStatementWriter) allocate() int {
+	id := p.next
+	p.next++
+	return id
+}
+
+func (p *pdfStatementWriter) object(id int, body string) {
+	p.offsets[id] = p.written
+	p.write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", id, body))
+}
+
+func (p *pdfStatementWriter) write(s string) {
+	if p.err != nil {
+		return
+	}
+	n, err := io.WriteString(p.w, s)
+	p.written += int64(n)
+	p.err = err
+}
+
+// pdfEscape escapes a string literal and maps it to single byte WinAnsi
+// characters, replacing anything outside Latin-1 with '?'.
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 256:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
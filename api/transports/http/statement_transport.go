@@ -0,0 +1,174 @@
+package transports
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// statementWriter renders a statement in one output format. Transactions are
+// written one at a time so large statements never sit in memory.
+type statementWriter interface {
+	Opening(statement entities.Statement) error
+	Transaction(transaction entities.Transaction, balance int64) error
+	Closing(statement entities.Statement) error
+}
+
+var statementContentTypes = map[string]string{
+	entities.StatementCSV:   "text/csv; charset=utf-8",
+	entities.StatementJSONL: "application/x-ndjson",
+	entities.StatementPDF:   "application/pdf",
+}
+
+func newStatementWriter(format string, w io.Writer) statementWriter {
+	switch format {
+	case entities.StatementJSONL:
+		return &jsonlStatementWriter{encoder: json.NewEncoder(w)}
+	case entities.StatementPDF:
+		return newPDFStatementWriter(w)
+	default:
+		return &csvStatementWriter{writer: csv.NewWriter(w)}
+	}
+}
+
+// writeStatement streams the statement and checks that the running balance
+// lands on the closing balance.
+func writeStatement(ctx context.Context, statement entities.Statement, writer statementWriter) error {
+	if err := writer.Opening(statement); err != nil {
+		return err
+	}
+	balance := statement.OpeningBalance
+	err := statement.Stream(ctx, func(transaction entities.Transaction) error {
+		balance += transaction.SignedAmount()
+		return writer.Transaction(transaction, balance)
+	})
+	if err != nil {
+		return err
+	}
+	if balance != statement.ClosingBalance {
+		return services.ErrStatementNotReconciled
+	}
+	return writer.Closing(statement)
+}
+
+func encodeGetStatementResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(endpoints.GetStatementResponse)
+	statement := resp.Statement
+	filename := fmt.Sprintf("statement-%s-%s-%s.%s", statement.WalletID, statement.From.Format(time.DateOnly), statement.To.Format(time.DateOnly), resp.Format)
+
+	w.Header().Set("Content-Type", statementContentTypes[resp.Format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.WriteHeader(http.StatusOK)
+	if err := writeStatement(ctx, statement, newStatementWriter(resp.Format, w)); err != nil {
+		// The 200 is already sent, so an error body would only corrupt the
+		// file. Aborting drops the connection and the client sees the download
+		// fail instead.
+		panic(http.ErrAbortHandler)
+	}
+	return nil
+}
+
+func decodeGetStatementRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.GetStatementRequest
+	var err error
+	query := r.URL.Query()
+
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	req.Currency = query.Get("currency")
+	req.Format = query.Get("format")
+	if req.Format == "" {
+		req.Format = entities.StatementCSV
+	}
+	if _, ok := statementContentTypes[req.Format]; !ok {
+		return nil, services.ErrInvalidStatementFormat
+	}
+	if req.From, err = parseTimeQuery(query.Get("from")); err != nil {
+		return nil, services.ErrInvalidStatementPeriod
+	}
+	if req.To, err = parseTimeQuery(query.Get("to")); err != nil {
+		return nil, services.ErrInvalidStatementPeriod
+	}
+	return req, nil
+}
+
+type csvStatementWriter struct {
+	writer *csv.Writer
+}
+
+func (c *csvStatementWriter) Opening(statement entities.Statement) error {
+	if err := c.writer.Write([]string{"date", "transaction_id", "type", "status", "counterparty", "memo", "amount", "balance"}); err != nil {
+		return err
+	}
+	return c.writer.Write([]string{statement.From.Format(time.RFC3339), "", "opening_balance", "", "", "", "", formatAmount(statement.OpeningBalance)})
+}
+
+func (c *csvStatementWriter) Transaction(transaction entities.Transaction, balance int64) error {
+	return c.writer.Write([]string{
+		transaction.Created_at.Format(time.RFC3339),
+		transaction.ID,
+		transaction.Type,
+		transaction.Status,
+		transaction.CounterpartyUserID,
+		transaction.Memo,
+		formatAmount(transaction.SignedAmount()),
+		formatAmount(balance),
+	})
+}
+
+func (c *csvStatementWriter) Closing(statement entities.Statement) error {
+	if err := c.writer.Write([]string{statement.To.Format(time.RFC3339), "", "closing_balance", "", "", "", "", formatAmount(statement.ClosingBalance)}); err != nil {
+		return err
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type jsonlStatementWriter struct {
+	encoder *json.Encoder
+}
+
+type jsonlBalanceRecord struct {
+	Record   string    `json:"record"`
+	WalletID string    `json:"wallet_id"`
+	Currency string    `json:"currency"`
+	Date     time.Time `json:"date"`
+	Balance  int64     `json:"balance"`
+}
+
+type jsonlTransactionRecord struct {
+	Record string `json:"record"`
+	entities.Transaction
+	Balance int64 `json:"balance"`
+}
+
+func (j *jsonlStatementWriter) Opening(statement entities.Statement) error {
+	return j.encoder.Encode(jsonlBalanceRecord{Record: "opening_balance", WalletID: statement.WalletID, Currency: statement.Currency, Date: statement.From, Balance: statement.OpeningBalance})
+}
+
+func (j *jsonlStatementWriter) Transaction(transaction entities.Transaction, balance int64) error {
+	return j.encoder.Encode(jsonlTransactionRecord{Record: "transaction", Transaction: transaction, Balance: balance})
+}
+
+func (j *jsonlStatementWriter) Closing(statement entities.Statement) error {
+	return j.encoder.Encode(jsonlBalanceRecord{Record: "closing_balance", WalletID: statement.WalletID, Currency: statement.Currency, Date: statement.To, Balance: statement.ClosingBalance})
+}
+
+// formatAmount renders an amount in cents with two decimals.
+func formatAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return sign + strconv.FormatInt(amount/100, 10) + "." + fmt.Sprintf("%02d", amount%100)
+}
@@ -0,0 +1,172 @@
+package transports
+
+import (
+	"bytes"
+	"context"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/entities"
+	"my_wallet/api/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStatement(closing int64) entities.Statement {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	transactions := []entities.Transaction{
+		{ID: "t1", Type: entities.TransactionTransferIn, Status: entities.StatusCompleted, Amount: 5000, CounterpartyUserID: "u2", Memo: "Rent, May", Created_at: from.Add(time.Hour)},
+		{ID: "t2", Type: entities.TransactionTransferOut, Status: entities.StatusCompleted, Amount: 1250, CounterpartyUserID: "u3", Created_at: from.Add(2 * time.Hour)},
+	}
+	return entities.Statement{
+		WalletID:       "w1",
+		UserName:       "Alexer",
+		Currency:       "COP",
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: 1000,
+		ClosingBalance: closing,
+		Stream: func(ctx context.Context, fn func(entities.Transaction) error) error {
+			for _, transaction := range transactions {
+				if err := fn(transaction); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestWriteStatement(t *testing.T) {
+	testScenarios := []struct {
+		name           string
+		format         string
+		closing        int64
+		expectedOutput string
+		expectedError  error
+	}{
+		{
+			name:    "csv",
+			format:  entities.StatementCSV,
+			closing: 4750,
+			expectedOutput: "date,transaction_id,type,status,counterparty,memo,amount,balance\n" +
+				"2024-05-01T00:00:00Z,,opening_balance,,,,,10.00\n" +
+				"2024-05-01T01:00:00Z,t1,transfer-in,completed,u2,\"Rent, May\",50.00,60.00\n" +
+				"2024-05-01T02:00:00Z,t2,transfer-out,completed,u3,,-12.50,47.50\n" +
+				"2024-06-01T00:00:00Z,,closing_balance,,,,,47.50\n",
+		},
+		{
+			name:    "jsonl",
+			format:  entities.StatementJSONL,
+			closing: 4750,
+			expectedOutput: `{"record":"opening_balance","wallet_id":"w1","currency":"COP","date":"2024-05-01T00:00:00Z","balance":1000}` + "\n" +
+				`{"record":"transaction","id":"t1","entry_id":"","wallet_id":"","user_id":"","type":"transfer-in","status":"completed","amount":5000,"currency":"","counterparty_user_id":"u2","memo":"Rent, May","created_at":"2024-05-01T01:00:00Z","balance":6000}` + "\n" +
+				`{"record":"transaction","id":"t2","entry_id":"","wallet_id":"","user_id":"","type":"transfer-out","status":"completed","amount":1250,"currency":"","counterparty_user_id":"u3","created_at":"2024-05-01T02:00:00Z","balance":4750}` + "\n" +
+				`{"record":"closing_balance","wallet_id":"w1","currency":"COP","date":"2024-06-01T00:00:00Z","balance":4750}` + "\n",
+		},
+		{
+			name:          "not reconciled",
+			format:        entities.StatementCSV,
+			closing:       9999,
+			expectedError: services.ErrStatementNotReconciled,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Prepare
+			var buf bytes.Buffer
+
+			// Act
+			err := writeStatement(context.Background(), testStatement(tt.closing), newStatementWriter(tt.format, &buf))
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedOutput, buf.String())
+			}
+		})
+	}
+}
+
+func TestEncodeGetStatementResponsePDF(t *testing.T) {
+	// Prepare
+	w := httptest.NewRecorder()
+	response := endpoints.GetStatementResponse{Statement: testStatement(4750), Format: entities.StatementPDF}
+
+	// Act
+	err := encodeGetStatementResponse(context.Background(), w, response)
+
+	// Assert
+	body := w.Body.String()
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="statement-w1-2024-05-01-2024-06-01.pdf"`, w.Header().Get("Content-Disposition"))
+	assert.True(t, strings.HasPrefix(body, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(body, "%%EOF\n"))
+	assert.Contains(t, body, "/Count 1")
+	assert.Contains(t, body, "(2024-05-01 01:00 transfer-in  Rent, May                         50.00          60.00) Tj")
+}
+
+func TestEncodeGetStatementResponseAbortsOnStreamError(t *testing.T) {
+	// Prepare
+	w := httptest.NewRecorder()
+	response := endpoints.GetStatementResponse{Statement: testStatement(9999), Format: entities.StatementCSV}
+
+	// Act
+	encode := func() { _ = encodeGetStatementResponse(context.Background(), w, response) }
+
+	// Assert
+	assert.PanicsWithValue(t, http.ErrAbortHandler, encode)
+}
+
+func TestDecodeGetStatementRequest(t *testing.T) {
+	testScenarios := []struct {
+		name           string
+		url            string
+		expectedOutput interface{}
+		expectedError  error
+	}{
+		{
+			name: "default format",
+			url:  "/wallets/w1/statements?from=2024-05-01&to=2024-06-01",
+			expectedOutput: endpoints.GetStatementRequest{
+				WalletID: "w1",
+				From:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+				To:       time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+				Format:   entities.StatementCSV,
+			},
+		},
+		{
+			name:          "unknown format",
+			url:           "/wallets/w1/statements?from=2024-05-01&to=2024-06-01&format=xlsx",
+			expectedError: services.ErrInvalidStatementFormat,
+		},
+		{
+			name:          "invalid date",
+			url:           "/wallets/w1/statements?from=may&to=2024-06-01",
+			expectedError: services.ErrInvalidStatementPeriod,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Prepare
+			req := httptest.NewRequest("GET", tt.url, nil)
+			req.SetPathValue("id", "w1")
+
+			// Act
+			result, err := decodeGetStatementRequest(context.Background(), req)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
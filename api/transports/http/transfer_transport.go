@@ -0,0 +1,25 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+)
+
+func encodeCreateTransferResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeCreateTransferRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.CreateTransferRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.SenderEmail = jwt.EmailFromContext(ctx)
+	req.DeviceID = r.Header.Get("X-Device-ID")
+	return req, nil
+}
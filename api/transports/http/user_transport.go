@@ -6,7 +6,9 @@ import (
 	"errors"
 	"my_wallet/api/endpoints"
 	infraestructure_repository "my_wallet/api/respository/healtcheck"
+	repository_ledger "my_wallet/api/respository/ledger"
 	repository_user "my_wallet/api/respository/user"
+	repository_wallet "my_wallet/api/respository/wallet"
 	"my_wallet/api/services"
 	"my_wallet/api/utils/jwt"
 	"net/http"
@@ -16,7 +18,10 @@ import (
 )
 
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error string                       `json:"error"`
+	Limit *services.LimitExceededError `json:"limit,omitempty"`
+	// ReviewID is the review holding a transfer, when one is held.
+	ReviewID string `json:"review_id,omitempty"`
 }
 
 func NewHTTPHandler(endpoints endpoints.Endpoints, logger logrus.FieldLogger) http.Handler {
@@ -65,12 +70,625 @@ func NewHTTPHandler(endpoints endpoints.Endpoints, logger logrus.FieldLogger) ht
 		encodeHealtcheckDbResponse,
 		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
 	))
+	m.Handle("POST /transfers", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateTransfer,
+		decodeCreateTransferRequest,
+		encodeCreateTransferResponse,
+		httpTransport.ServerBefore(populateIdempotencyKey),
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /wallets/{id}/transactions", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListTransactions,
+		decodeListTransactionsRequest,
+		encodeListTransactionsResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /wallets/{id}/statements", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetStatement,
+		decodeGetStatementRequest,
+		encodeGetStatementResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /wallets/{id}/conversions/quotes", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateQuote,
+		decodeCreateQuoteRequest,
+		encodeCreateQuoteResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /wallets/{id}/conversions", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateConversion,
+		decodeCreateConversionRequest,
+		encodeCreateConversionResponse,
+		httpTransport.ServerBefore(populateIdempotencyKey),
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /fees/quote", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.QuoteFee,
+		decodeQuoteFeeRequest,
+		encodeQuoteFeeResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /admin/fee-rules", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListFeeRules,
+		decodeListFeeRulesRequest,
+		encodeListFeeRulesResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /admin/fee-rules", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateFeeRule,
+		decodeFeeRuleRequest,
+		encodeCreateFeeRuleResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("PUT /admin/fee-rules/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.UpdateFeeRule,
+		decodeFeeRuleRequest,
+		encodeUpdateFeeRuleResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("DELETE /admin/fee-rules/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.DeleteFeeRule,
+		decodeDeleteFeeRuleRequest,
+		encodeDeleteFeeRuleResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /schedules", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateSchedule,
+		decodeCreateScheduleRequest,
+		encodeCreateScheduleResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /schedules", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListSchedules,
+		decodeListSchedulesRequest,
+		encodeScheduleResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /schedules/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetSchedule,
+		decodeScheduleRequest,
+		encodeScheduleResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("DELETE /schedules/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CancelSchedule,
+		decodeScheduleRequest,
+		encodeScheduleResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /schedules/{id}/executions", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListExecutions,
+		decodeScheduleRequest,
+		encodeScheduleResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /payment-requests", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreatePaymentRequest,
+		decodeCreatePaymentRequestRequest,
+		encodeCreatePaymentRequestResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /payment-requests", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListPaymentRequests,
+		decodeListPaymentRequestsRequest,
+		encodePaymentRequestResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /payment-requests/{id}/accept", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.AcceptPaymentRequest,
+		decodeAnswerPaymentRequestRequest,
+		encodePaymentRequestResponse,
+		httpTransport.ServerBefore(populateIdempotencyKey),
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /payment-requests/{id}/decline", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.DeclinePaymentRequest,
+		decodeAnswerPaymentRequestRequest,
+		encodePaymentRequestResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /payment-requests/{id}/cancel", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CancelPaymentRequest,
+		decodeAnswerPaymentRequestRequest,
+		encodePaymentRequestResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /splits", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateSplit,
+		decodeCreateSplitRequest,
+		encodeCreateSplitResponse,
+		httpTransport.ServerBefore(populateIdempotencyKey),
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /splits", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListSplits,
+		decodeListSplitsRequest,
+		encodeSplitResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /splits/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetSplit,
+		decodeGetSplitRequest,
+		encodeSplitResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /wallets/{id}/pockets", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreatePocket,
+		decodePocketRequest,
+		encodeCreatePocketResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /wallets/{id}/pockets", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListPockets,
+		decodeListPocketsRequest,
+		encodePocketResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /wallets/{id}/pockets/{pocketId}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetPocket,
+		decodeGetPocketRequest,
+		encodePocketResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("PUT /wallets/{id}/pockets/{pocketId}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.UpdatePocket,
+		decodePocketRequest,
+		encodePocketResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("DELETE /wallets/{id}/pockets/{pocketId}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ClosePocket,
+		decodeGetPocketRequest,
+		encodePocketResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /wallets/{id}/pockets/{pocketId}/deposit", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.MoveToPocket,
+		decodeMovePocketRequest,
+		encodePocketResponse,
+		httpTransport.ServerBefore(populateIdempotencyKey),
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /wallets/{id}/pockets/{pocketId}/withdraw", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.MoveFromPocket,
+		decodeMovePocketRequest,
+		encodePocketResponse,
+		httpTransport.ServerBefore(populateIdempotencyKey),
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /categories", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListCategories,
+		decodeListCategoriesRequest,
+		encodeCategoryResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /categories", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateCategory,
+		decodeCreateCategoryRequest,
+		encodeCreateCategoryResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /categories/rules", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListCategoryRules,
+		decodeListCategoryRulesRequest,
+		encodeCategoryResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /categories/rules", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateCategoryRule,
+		decodeCategoryRuleRequest,
+		encodeCreateCategoryResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("DELETE /categories/rules/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.DeleteCategoryRule,
+		decodeDeleteCategoryRuleRequest,
+		encodeDeleteCategoryRuleResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("PUT /wallets/{id}/transactions/{transactionId}/category", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.Recategorize,
+		decodeRecategorizeRequest,
+		encodeCategoryResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /budgets", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateBudget,
+		decodeBudgetRequest,
+		encodeCreateBudgetResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /budgets", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListBudgets,
+		decodeListBudgetsRequest,
+		encodeBudgetResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("PUT /budgets/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.UpdateBudget,
+		decodeBudgetRequest,
+		encodeBudgetResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("DELETE /budgets/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.DeleteBudget,
+		decodeGetBudgetRequest,
+		encodeDeleteBudgetResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /budgets/{id}/status", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetBudgetStatus,
+		decodeGetBudgetRequest,
+		encodeBudgetResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /notifications", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListNotifications,
+		decodeListNotificationsRequest,
+		encodeNotificationResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /notifications/{id}/read", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.MarkNotificationRead,
+		decodeMarkNotificationReadRequest,
+		encodeNotificationResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /wallets/{id}/insights", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetInsights,
+		decodeGetInsightsRequest,
+		encodeGetInsightsResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /limits", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetLimitsEndpoint,
+		decodeGetLimitsRequest,
+		encodeGetLimitsResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /kyc", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetKYCEndpoint,
+		decodeGetKYCRequest,
+		encodeKYCResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /kyc/applications", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateKYCApplicationEndpoint,
+		decodeCreateKYCApplicationRequest,
+		encodeCreateKYCApplicationResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /kyc/applications", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListKYCApplicationsEndpoint,
+		decodeListKYCApplicationsRequest,
+		encodeKYCResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("PUT /kyc/applications/{id}/documents/{type}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.UploadKYCDocumentEndpoint,
+		decodeUploadKYCDocumentRequest,
+		encodeKYCResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /kyc/applications/{id}/documents/{type}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetKYCDocumentEndpoint,
+		decodeGetKYCDocumentRequest,
+		encodeKYCDocumentResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /kyc/applications/{id}/submit", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.SubmitKYCApplicationEndpoint,
+		decodeKYCApplicationRequest,
+		encodeKYCResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /kyc/applications/{id}/review", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ReviewKYCApplicationEndpoint,
+		decodeReviewKYCApplicationRequest,
+		encodeKYCResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /kyc/users/{id}/events", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListKYCEventsEndpoint,
+		decodeListKYCEventsRequest,
+		encodeKYCResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /screening/rules", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListScreeningRulesEndpoint,
+		decodeListScreeningRulesRequest,
+		encodeScreeningResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("PUT /screening/rules/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.SaveScreeningRuleEndpoint,
+		decodeSaveScreeningRuleRequest,
+		encodeScreeningResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /screening/reviews", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListScreeningReviewsEndpoint,
+		decodeListScreeningReviewsRequest,
+		encodeScreeningResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /screening/reviews/{id}/release", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ReleaseScreeningReviewEndpoint,
+		decodeScreeningReviewRequest,
+		encodeScreeningResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /screening/reviews/{id}/reject", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.RejectScreeningReviewEndpoint,
+		decodeScreeningReviewRequest,
+		encodeScreeningResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /sanctions/screenings", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListSanctionsScreeningsEndpoint,
+		decodeListSanctionsScreeningsRequest,
+		encodeSanctionsResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /sanctions/screenings/{id}/clear", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ClearSanctionsScreeningEndpoint,
+		decodeSanctionsScreeningRequest,
+		encodeSanctionsResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /sanctions/screenings/{id}/confirm", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ConfirmSanctionsScreeningEndpoint,
+		decodeSanctionsScreeningRequest,
+		encodeSanctionsResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /disputes", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.OpenDisputeEndpoint,
+		decodeOpenDisputeRequest,
+		encodeOpenDisputeResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /disputes", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListDisputesEndpoint,
+		decodeListDisputesRequest,
+		encodeDisputeResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /disputes/queue", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListDisputeQueueEndpoint,
+		decodeListDisputeQueueRequest,
+		encodeDisputeResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /disputes/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetDisputeEndpoint,
+		decodeDisputeRequest,
+		encodeDisputeResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /disputes/{id}/evidence", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.AddDisputeEvidenceEndpoint,
+		decodeAddDisputeEvidenceRequest,
+		encodeDisputeResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /disputes/{id}/evidence/{evidence_id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetDisputeEvidenceEndpoint,
+		decodeGetDisputeEvidenceRequest,
+		encodeDisputeEvidenceResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /disputes/{id}/review", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ReviewDisputeEndpoint,
+		decodeDisputeRequest,
+		encodeDisputeResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /disputes/{id}/resolve", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ResolveDisputeEndpoint,
+		decodeResolveDisputeRequest,
+		encodeDisputeResponse,
+		httpTransport.ServerBefore(populateIdempotencyKey),
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /beneficiaries", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateBeneficiaryEndpoint,
+		decodeCreateBeneficiaryRequest,
+		encodeCreateBeneficiaryResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /beneficiaries", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListBeneficiariesEndpoint,
+		decodeListBeneficiariesRequest,
+		encodeBeneficiaryResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("PUT /beneficiaries/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.UpdateBeneficiaryEndpoint,
+		decodeUpdateBeneficiaryRequest,
+		encodeBeneficiaryResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("DELETE /beneficiaries/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.DeleteBeneficiaryEndpoint,
+		decodeBeneficiaryRequest,
+		encodeDeleteBeneficiaryResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /withdrawals", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateWithdrawalEndpoint,
+		decodeCreateWithdrawalRequest,
+		encodeCreateWithdrawalResponse,
+		httpTransport.ServerBefore(populateIdempotencyKey),
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /merchants", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateMerchantEndpoint,
+		decodeCreateMerchantRequest,
+		encodeMerchantCreatedResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /merchants/me", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetMerchantEndpoint,
+		decodeMerchantRequest,
+		encodeMerchantResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /merchants/me/qr-codes", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.CreateQRCodeEndpoint,
+		decodeCreateQRCodeRequest,
+		encodeMerchantCreatedResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /merchants/me/settlements", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetSettlementReportEndpoint,
+		decodeSettlementReportRequest,
+		encodeMerchantResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /qr-payments", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.PayQRCodeEndpoint,
+		decodePayQRCodeRequest,
+		encodeMerchantCreatedResponse,
+		httpTransport.ServerBefore(populateIdempotencyKey),
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /admin/wallets/{id}/restrictions", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.PlaceRestrictionEndpoint,
+		decodePlaceRestrictionRequest,
+		encodePlaceRestrictionResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /admin/wallets/{id}/restrictions", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListRestrictionsEndpoint,
+		decodeListRestrictionsRequest,
+		encodeRestrictionResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /admin/wallets/{id}/restrictions/{restrictionId}/lift", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.LiftRestrictionEndpoint,
+		decodeLiftRestrictionRequest,
+		encodeRestrictionResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /admin/reconciliations", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListReconciliationsEndpoint,
+		decodeListReconciliationsRequest,
+		encodeReconciliationResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /admin/reconciliations/{id}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetReconciliationEndpoint,
+		decodeGetReconciliationRequest,
+		encodeReconciliationResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /wallets/{id}/interest", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.GetInterestEndpoint,
+		decodeGetInterestRequest,
+		encodeInterestResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /admin/interest/accruals", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.AccrueInterestEndpoint,
+		decodeAccrueInterestRequest,
+		encodeInterestResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /cards", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.IssueCardEndpoint,
+		decodeIssueCardRequest,
+		encodeIssueCardResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /cards", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListCardsEndpoint,
+		decodeListCardsRequest,
+		encodeCardResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("PUT /cards/{id}/limits", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.UpdateCardLimitsEndpoint,
+		decodeUpdateCardLimitsRequest,
+		encodeCardResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /cards/{id}/freeze", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.FreezeCardEndpoint,
+		decodeFreezeCardRequest,
+		encodeCardResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /cards/{id}/unfreeze", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.FreezeCardEndpoint,
+		decodeUnfreezeCardRequest,
+		encodeCardResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /cards/{id}/authorizations", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListCardAuthorizationsEndpoint,
+		decodeListCardAuthorizationsRequest,
+		encodeCardResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	// The card network authenticates by signing its messages, not with a token.
+	m.Handle("POST /cards/network/messages", httpTransport.NewServer(
+		endpoints.CardNetworkMessageEndpoint,
+		decodeCardNetworkMessageRequest,
+		encodeCardNetworkMessageResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	))
+	m.Handle("POST /wallets/{id}/members", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.InviteMemberEndpoint,
+		decodeInviteMemberRequest,
+		encodeInviteMemberResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /wallets/{id}/members", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListMembersEndpoint,
+		decodeListMembersRequest,
+		encodeMemberResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("PUT /wallets/{id}/members/{memberId}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.UpdateMemberEndpoint,
+		decodeUpdateMemberRequest,
+		encodeMemberResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("DELETE /wallets/{id}/members/{memberId}", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.RemoveMemberEndpoint,
+		decodeRemoveMemberRequest,
+		encodeMemberResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("GET /memberships", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.ListMembershipsEndpoint,
+		decodeListMembershipsRequest,
+		encodeMemberResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /memberships/{id}/accept", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.RespondInvitationEndpoint,
+		decodeAcceptInvitationRequest,
+		encodeMemberResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
+	m.Handle("POST /memberships/{id}/decline", jwt.JWTMiddleware(httpTransport.NewServer(
+		endpoints.RespondInvitationEndpoint,
+		decodeDeclineInvitationRequest,
+		encodeMemberResponse,
+		httpTransport.ServerErrorEncoder(CustomErrorEncoder),
+	)))
 	return m
 }
 
 func CustomErrorEncoder(ctx context.Context, err error, w http.ResponseWriter) {
 	var statusCode int
 	var errorMessage string
+	var limitErr *services.LimitExceededError
+	var heldErr *services.TransferHeldError
+	var reviewID string
 
 	switch {
 	case errors.Is(err, services.ErrLenghtPassword):
@@ -112,6 +730,397 @@ func CustomErrorEncoder(ctx context.Context, err error, w http.ResponseWriter) {
 	case errors.Is(err, endpoints.ErrInterfaceWrong):
 		statusCode = http.StatusBadRequest
 		errorMessage = endpoints.ErrInterfaceWrong.Error()
+	case errors.Is(err, services.ErrInvalidAmount):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidAmount.Error()
+	case errors.Is(err, services.ErrRecipientRequired):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrRecipientRequired.Error()
+	case errors.Is(err, services.ErrMemoTooLong):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrMemoTooLong.Error()
+	case errors.Is(err, services.ErrRecipientNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrRecipientNotFound.Error()
+	case errors.Is(err, services.ErrRecipientDisabled):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrRecipientDisabled.Error()
+	case errors.Is(err, services.ErrSelfTransfer):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrSelfTransfer.Error()
+	case errors.Is(err, services.ErrInsufficientFunds):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrInsufficientFunds.Error()
+	case errors.Is(err, services.ErrInvalidIdempotencyKey):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidIdempotencyKey.Error()
+	case errors.Is(err, services.ErrIdempotencyKeyReused):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrIdempotencyKeyReused.Error()
+	case errors.Is(err, services.ErrIdempotencyInProgress):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrIdempotencyInProgress.Error()
+	case errors.Is(err, services.ErrWalletForbidden):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrWalletForbidden.Error()
+	case errors.Is(err, services.ErrInvalidCursor):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidCursor.Error()
+	case errors.Is(err, services.ErrInvalidTransactionFilter):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidTransactionFilter.Error()
+	case errors.Is(err, services.ErrInvalidStatementPeriod):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidStatementPeriod.Error()
+	case errors.Is(err, services.ErrInvalidStatementFormat):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidStatementFormat.Error()
+	case errors.Is(err, services.ErrStatementNotReconciled):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrStatementNotReconciled.Error()
+	case errors.Is(err, repository_wallet.ErrWalletNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = repository_wallet.ErrWalletNotFound.Error()
+	case errors.Is(err, repository_ledger.ErrWalletNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = repository_ledger.ErrWalletNotFound.Error()
+	case errors.Is(err, services.ErrInvalidCurrency):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidCurrency.Error()
+	case errors.Is(err, services.ErrSameCurrency):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrSameCurrency.Error()
+	case errors.Is(err, services.ErrRateNotFound):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrRateNotFound.Error()
+	case errors.Is(err, services.ErrQuoteNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrQuoteNotFound.Error()
+	case errors.Is(err, services.ErrQuoteExpired):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrQuoteExpired.Error()
+	case errors.Is(err, services.ErrAdminRequired):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrAdminRequired.Error()
+	case errors.Is(err, services.ErrInvalidFeeRule):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidFeeRule.Error()
+	case errors.Is(err, services.ErrInvalidOperation):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidOperation.Error()
+	case errors.Is(err, services.ErrFeeRuleNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrFeeRuleNotFound.Error()
+	case errors.Is(err, services.ErrInvalidSchedule):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidSchedule.Error()
+	case errors.Is(err, services.ErrScheduleNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrScheduleNotFound.Error()
+	case errors.Is(err, services.ErrScheduleNotActive):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrScheduleNotActive.Error()
+	case errors.Is(err, services.ErrPayerRequired):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrPayerRequired.Error()
+	case errors.Is(err, services.ErrPayerNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrPayerNotFound.Error()
+	case errors.Is(err, services.ErrPayerDisabled):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrPayerDisabled.Error()
+	case errors.Is(err, services.ErrSelfRequest):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrSelfRequest.Error()
+	case errors.Is(err, services.ErrPaymentRequestNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrPaymentRequestNotFound.Error()
+	case errors.Is(err, services.ErrPaymentRequestNotPending):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrPaymentRequestNotPending.Error()
+	case errors.Is(err, services.ErrPaymentRequestExpired):
+		statusCode = http.StatusGone
+		errorMessage = services.ErrPaymentRequestExpired.Error()
+	case errors.Is(err, services.ErrInvalidPaymentRequestFilter):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidPaymentRequestFilter.Error()
+	case errors.Is(err, services.ErrInvalidSplit):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidSplit.Error()
+	case errors.Is(err, services.ErrSplitAmountMismatch):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrSplitAmountMismatch.Error()
+	case errors.Is(err, services.ErrSplitNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrSplitNotFound.Error()
+	case errors.Is(err, services.ErrInvalidPocket):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidPocket.Error()
+	case errors.Is(err, services.ErrPocketNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrPocketNotFound.Error()
+	case errors.Is(err, services.ErrTooManyPockets):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrTooManyPockets.Error()
+	case errors.Is(err, services.ErrPocketNotEmpty):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrPocketNotEmpty.Error()
+	case errors.Is(err, services.ErrInvalidCategory):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidCategory.Error()
+	case errors.Is(err, services.ErrUnknownCategory):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrUnknownCategory.Error()
+	case errors.Is(err, services.ErrCategoryExists):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrCategoryExists.Error()
+	case errors.Is(err, services.ErrInvalidCategoryRule):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidCategoryRule.Error()
+	case errors.Is(err, services.ErrCategoryRuleNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrCategoryRuleNotFound.Error()
+	case errors.Is(err, services.ErrTransactionNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrTransactionNotFound.Error()
+	case errors.Is(err, services.ErrCannotLearnRule):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrCannotLearnRule.Error()
+	case errors.Is(err, services.ErrNotificationNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrNotificationNotFound.Error()
+	case errors.Is(err, services.ErrInvalidBudget):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidBudget.Error()
+	case errors.Is(err, services.ErrBudgetExists):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrBudgetExists.Error()
+	case errors.Is(err, services.ErrBudgetNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrBudgetNotFound.Error()
+	case errors.Is(err, services.ErrInvalidInsightPeriod):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidInsightPeriod.Error()
+	case errors.Is(err, services.ErrTransferAlreadyMade):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrTransferAlreadyMade.Error()
+	case errors.As(err, &heldErr):
+		statusCode = http.StatusAccepted
+		errorMessage = services.ErrTransferHeld.Error()
+		reviewID = heldErr.ReviewID
+	case errors.As(err, &limitErr):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrLimitExceeded.Error()
+	case errors.Is(err, services.ErrLimitExceeded):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrLimitExceeded.Error()
+	case errors.Is(err, services.ErrStaffRequired):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrStaffRequired.Error()
+	case errors.Is(err, services.ErrKYCLevelRequired):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrKYCLevelRequired.Error()
+	case errors.Is(err, services.ErrKYCSelfReview):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrKYCSelfReview.Error()
+	case errors.Is(err, services.ErrInvalidKYCLevel):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidKYCLevel.Error()
+	case errors.Is(err, services.ErrInvalidKYCDocument):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidKYCDocument.Error()
+	case errors.Is(err, services.ErrKYCReasonRequired):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrKYCReasonRequired.Error()
+	case errors.Is(err, services.ErrKYCApplicationNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrKYCApplicationNotFound.Error()
+	case errors.Is(err, services.ErrKYCDocumentNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrKYCDocumentNotFound.Error()
+	case errors.Is(err, services.ErrKYCApplicationOpen):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrKYCApplicationOpen.Error()
+	case errors.Is(err, services.ErrKYCApplicationState):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrKYCApplicationState.Error()
+	case errors.Is(err, services.ErrKYCDocumentsMissing):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrKYCDocumentsMissing.Error()
+	case errors.Is(err, services.ErrAnalystRequired):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrAnalystRequired.Error()
+	case errors.Is(err, services.ErrTransferBlocked):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrTransferBlocked.Error()
+	case errors.Is(err, services.ErrInvalidScreeningRule):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidScreeningRule.Error()
+	case errors.Is(err, services.ErrReviewNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrReviewNotFound.Error()
+	case errors.Is(err, services.ErrScreeningRulesReadOnly):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrScreeningRulesReadOnly.Error()
+	case errors.Is(err, services.ErrReviewState):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrReviewState.Error()
+	case errors.Is(err, services.ErrUserUnderReview):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrUserUnderReview.Error()
+	case errors.Is(err, services.ErrSanctionsScreeningNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrSanctionsScreeningNotFound.Error()
+	case errors.Is(err, services.ErrSanctionsScreeningState):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrSanctionsScreeningState.Error()
+	case errors.Is(err, services.ErrDisputeNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrDisputeNotFound.Error()
+	case errors.Is(err, services.ErrEvidenceNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrEvidenceNotFound.Error()
+	case errors.Is(err, services.ErrDisputeExists):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrDisputeExists.Error()
+	case errors.Is(err, services.ErrDisputeState):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrDisputeState.Error()
+	case errors.Is(err, services.ErrReversalFunds):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrReversalFunds.Error()
+	case errors.Is(err, services.ErrTransactionNotDisputable):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrTransactionNotDisputable.Error()
+	case errors.Is(err, services.ErrInvalidDisputeReason):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidDisputeReason.Error()
+	case errors.Is(err, services.ErrInvalidEvidence):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidEvidence.Error()
+	case errors.Is(err, services.ErrInvalidReversalAmount):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidReversalAmount.Error()
+	case errors.Is(err, services.ErrDisputeNoteRequired):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrDisputeNoteRequired.Error()
+	case errors.Is(err, services.ErrDisputeSelfReview):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrDisputeSelfReview.Error()
+	case errors.Is(err, services.ErrBeneficiaryNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrBeneficiaryNotFound.Error()
+	case errors.Is(err, services.ErrBeneficiaryExists):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrBeneficiaryExists.Error()
+	case errors.Is(err, services.ErrInvalidBeneficiary):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidBeneficiary.Error()
+	case errors.Is(err, services.ErrBeneficiaryType):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrBeneficiaryType.Error()
+	case errors.Is(err, services.ErrBeneficiaryCoolingOff):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrBeneficiaryCoolingOff.Error()
+	case errors.Is(err, services.ErrWithdrawalBeneficiaryRequired):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrWithdrawalBeneficiaryRequired.Error()
+	case errors.Is(err, services.ErrMerchantNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrMerchantNotFound.Error()
+	case errors.Is(err, services.ErrQRCodeNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrQRCodeNotFound.Error()
+	case errors.Is(err, services.ErrMerchantExists):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrMerchantExists.Error()
+	case errors.Is(err, services.ErrQRCodePaid):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrQRCodePaid.Error()
+	case errors.Is(err, services.ErrInvalidMerchant):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidMerchant.Error()
+	case errors.Is(err, services.ErrInvalidQRCode):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidQRCode.Error()
+	case errors.Is(err, services.ErrInvalidSettlementPeriod):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidSettlementPeriod.Error()
+	case errors.Is(err, services.ErrQRCodeExpired):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrQRCodeExpired.Error()
+	case errors.Is(err, services.ErrQRCodeAmount):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrQRCodeAmount.Error()
+	case errors.Is(err, services.ErrWalletFrozen):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrWalletFrozen.Error()
+	case errors.Is(err, services.ErrWalletBlocked):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrWalletBlocked.Error()
+	case errors.Is(err, services.ErrCapabilityRestricted):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrCapabilityRestricted.Error()
+	case errors.Is(err, services.ErrInvalidRestriction):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidRestriction.Error()
+	case errors.Is(err, services.ErrRestrictionNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrRestrictionNotFound.Error()
+	case errors.Is(err, services.ErrRestrictionLifted):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrRestrictionLifted.Error()
+	case errors.Is(err, services.ErrInvalidReconciliation):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidReconciliation.Error()
+	case errors.Is(err, services.ErrInvalidReconciliationResult):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidReconciliationResult.Error()
+	case errors.Is(err, services.ErrReconciliationNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrReconciliationNotFound.Error()
+	case errors.Is(err, services.ErrInvalidInterestDay):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidInterestDay.Error()
+	case errors.Is(err, services.ErrInvalidCard):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidCard.Error()
+	case errors.Is(err, services.ErrCardNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrCardNotFound.Error()
+	case errors.Is(err, services.ErrInvalidCardSignature):
+		statusCode = http.StatusUnauthorized
+		errorMessage = services.ErrInvalidCardSignature.Error()
+	case errors.Is(err, services.ErrInvalidCardMessage):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidCardMessage.Error()
+	case errors.Is(err, services.ErrCardAuthorizationNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrCardAuthorizationNotFound.Error()
+	case errors.Is(err, services.ErrCardAuthorizationPending):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrCardAuthorizationPending.Error()
+	case errors.Is(err, services.ErrCardAuthorizationClosed):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrCardAuthorizationClosed.Error()
+	case errors.Is(err, services.ErrWalletPermission):
+		statusCode = http.StatusForbidden
+		errorMessage = services.ErrWalletPermission.Error()
+	case errors.Is(err, services.ErrInvalidMember):
+		statusCode = http.StatusBadRequest
+		errorMessage = services.ErrInvalidMember.Error()
+	case errors.Is(err, services.ErrMemberNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = services.ErrMemberNotFound.Error()
+	case errors.Is(err, services.ErrMemberExists):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrMemberExists.Error()
+	case errors.Is(err, services.ErrInvitationClosed):
+		statusCode = http.StatusConflict
+		errorMessage = services.ErrInvitationClosed.Error()
+	case errors.Is(err, services.ErrSpendingLimitExceeded):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = services.ErrSpendingLimitExceeded.Error()
 	case errors.Is(err, infraestructure_repository.ErrLoadingDatabase):
 		statusCode = http.StatusInternalServerError
 		errorMessage = infraestructure_repository.ErrLoadingDatabase.Error()
@@ -123,7 +1132,7 @@ func CustomErrorEncoder(ctx context.Context, err error, w http.ResponseWriter) {
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(ErrorResponse{Error: errorMessage})
+	json.NewEncoder(w).Encode(ErrorResponse{Error: errorMessage, Limit: limitErr, ReviewID: reviewID})
 }
 
 func encodeLoginUserResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
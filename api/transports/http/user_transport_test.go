@@ -25,6 +25,7 @@ import (
 )
 
 func TestCustomErrorEncoder(t *testing.T) {
+	resetAt := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
 	testScenarios := []struct {
 		name           string
 		err            error
@@ -103,6 +104,270 @@ func TestCustomErrorEncoder(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   `{"error":"Database unavailable"}`,
 		},
+		{
+			name:           "ErrInsufficientFunds",
+			err:            services.ErrInsufficientFunds,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Insufficient funds"}`,
+		},
+		{
+			name:           "ErrRecipientNotFound",
+			err:            services.ErrRecipientNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Error not found recipient"}`,
+		},
+		{
+			name:           "ErrRecipientDisabled",
+			err:            services.ErrRecipientDisabled,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Recipient user is disabled"}`,
+		},
+		{
+			name:           "ErrIdempotencyKeyReused",
+			err:            services.ErrIdempotencyKeyReused,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Idempotency-Key was already used with a different request"}`,
+		},
+		{
+			name:           "ErrIdempotencyInProgress",
+			err:            services.ErrIdempotencyInProgress,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"A request with this Idempotency-Key is still in progress"}`,
+		},
+		{
+			name:           "ErrQuoteExpired",
+			err:            services.ErrQuoteExpired,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Quote expired or already used"}`,
+		},
+		{
+			name:           "ErrRateNotFound",
+			err:            services.ErrRateNotFound,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"No exchange rate for the currency pair"}`,
+		},
+		{
+			name:           "ErrAdminRequired",
+			err:            services.ErrAdminRequired,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"Only administrators can use this endpoint"}`,
+		},
+		{
+			name:           "ErrFeeRuleNotFound",
+			err:            services.ErrFeeRuleNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Error not found fee rule"}`,
+		},
+		{
+			name:           "ErrScheduleNotActive",
+			err:            services.ErrScheduleNotActive,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Scheduled payment is not active"}`,
+		},
+		{
+			name:           "ErrPaymentRequestExpired",
+			err:            services.ErrPaymentRequestExpired,
+			expectedStatus: http.StatusGone,
+			expectedBody:   `{"error":"Payment request expired"}`,
+		},
+		{
+			name:           "ErrPaymentRequestNotPending",
+			err:            services.ErrPaymentRequestNotPending,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Payment request was already answered"}`,
+		},
+		{
+			name:           "ErrSplitAmountMismatch",
+			err:            services.ErrSplitAmountMismatch,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Split amounts do not add up to the total"}`,
+		},
+		{
+			name:           "ErrTooManyPockets",
+			err:            services.ErrTooManyPockets,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Wallet already has the maximum number of pockets"}`,
+		},
+		{
+			name:           "ErrCannotLearnRule",
+			err:            services.ErrCannotLearnRule,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Transaction has no counterparty or memo to learn a rule from"}`,
+		},
+		{
+			name:           "ErrBudgetExists",
+			err:            services.ErrBudgetExists,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Wallet already has a budget for this category"}`,
+		},
+		{
+			name:           "LimitExceededError",
+			err:            &services.LimitExceededError{Kind: "daily", Currency: "COP", Limit: 100000, Used: 90000, Reset_at: &resetAt},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Transaction limit exceeded","limit":{"kind":"daily","currency":"COP","limit":100000,"used":90000,"reset_at":"2026-10-20T00:00:00Z"}}`,
+		},
+		{
+			name:           "ErrLimitExceeded",
+			err:            services.ErrLimitExceeded,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Transaction limit exceeded"}`,
+		},
+		{
+			name:           "ErrKYCLevelRequired",
+			err:            services.ErrKYCLevelRequired,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"Your KYC level does not allow this operation"}`,
+		},
+		{
+			name:           "ErrKYCDocumentsMissing",
+			err:            services.ErrKYCDocumentsMissing,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"KYC application is missing required documents"}`,
+		},
+		{
+			name:           "TransferHeldError",
+			err:            &services.TransferHeldError{ReviewID: "r1"},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"error":"Transfer is held for review","review_id":"r1"}`,
+		},
+		{
+			name:           "ErrTransferBlocked",
+			err:            services.ErrTransferBlocked,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"Transfer was blocked by our risk controls"}`,
+		},
+		{
+			name:           "ErrUserUnderReview",
+			err:            services.ErrUserUnderReview,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"User is pending compliance review"}`,
+		},
+		{
+			name:           "ErrDisputeExists",
+			err:            services.ErrDisputeExists,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Transaction already has a dispute"}`,
+		},
+		{
+			name:           "ErrTransactionNotDisputable",
+			err:            services.ErrTransactionNotDisputable,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Only completed outgoing transfers and payments can be disputed"}`,
+		},
+		{
+			name:           "ErrBeneficiaryNotFound",
+			err:            services.ErrBeneficiaryNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Error not found beneficiary"}`,
+		},
+		{
+			name:           "ErrBeneficiaryCoolingOff",
+			err:            services.ErrBeneficiaryCoolingOff,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Beneficiary was added recently and cannot receive large amounts yet"}`,
+		},
+		{
+			name:           "ErrInvalidQRCode",
+			err:            services.ErrInvalidQRCode,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"QR code is not a valid My Wallet payment code"}`,
+		},
+		{
+			name:           "ErrQRCodePaid",
+			err:            services.ErrQRCodePaid,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"QR code was already paid"}`,
+		},
+		{
+			name:           "ErrWalletFrozen",
+			err:            services.ErrWalletFrozen,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"Wallet is frozen and cannot send money"}`,
+		},
+		{
+			name:           "ErrRestrictionLifted",
+			err:            services.ErrRestrictionLifted,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Restriction was already lifted"}`,
+		},
+		{
+			name:           "ErrInvalidReconciliationResult",
+			err:            services.ErrInvalidReconciliationResult,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Result must be matched, amount_mismatch, unmatched_internal or unmatched_external"}`,
+		},
+		{
+			name:           "ErrReconciliationNotFound",
+			err:            services.ErrReconciliationNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Error not found reconciliation"}`,
+		},
+		{
+			name:           "ErrInvalidInterestDay",
+			err:            services.ErrInvalidInterestDay,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Interest can only be accrued again for a day that has ended"}`,
+		},
+		{
+			name:           "ErrInvalidCard",
+			err:            services.ErrInvalidCard,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Card requires a name of up to 40 characters and limits that are not negative"}`,
+		},
+		{
+			name:           "ErrCardNotFound",
+			err:            services.ErrCardNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Error not found card"}`,
+		},
+		{
+			name:           "ErrInvalidCardSignature",
+			err:            services.ErrInvalidCardSignature,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"Invalid card network signature"}`,
+		},
+		{
+			name:           "ErrInvalidCardMessage",
+			err:            services.ErrInvalidCardMessage,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"Invalid card network message"}`,
+		},
+		{
+			name:           "ErrCardAuthorizationNotFound",
+			err:            services.ErrCardAuthorizationNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Error not found card authorization"}`,
+		},
+		{
+			name:           "ErrCardAuthorizationPending",
+			err:            services.ErrCardAuthorizationPending,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Card authorization is still being decided"}`,
+		},
+		{
+			name:           "ErrCardAuthorizationClosed",
+			err:            services.ErrCardAuthorizationClosed,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Card authorization was already cleared or reversed"}`,
+		},
+		{
+			name:           "ErrWalletPermission",
+			err:            services.ErrWalletPermission,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"Your role in the wallet does not allow it"}`,
+		},
+		{
+			name:           "ErrMemberExists",
+			err:            services.ErrMemberExists,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"User already invited to the wallet"}`,
+		},
+		{
+			name:           "ErrSpendingLimitExceeded",
+			err:            services.ErrSpendingLimitExceeded,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"Member spending limit exceeded"}`,
+		},
 		{
 			name:           "nil error",
 			err:            nil,
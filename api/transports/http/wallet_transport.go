@@ -0,0 +1,77 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/services"
+	"my_wallet/api/utils/jwt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func encodeListTransactionsResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeListTransactionsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req endpoints.ListTransactionsRequest
+	var err error
+	query := r.URL.Query()
+
+	req.Email = jwt.EmailFromContext(ctx)
+	req.WalletID = r.PathValue("id")
+	for _, value := range query["type"] {
+		for _, transactionType := range strings.Split(value, ",") {
+			if transactionType != "" {
+				req.Types = append(req.Types, transactionType)
+			}
+		}
+	}
+	req.Currency = query.Get("currency")
+	req.Status = query.Get("status")
+	req.Counterparty = query.Get("counterparty")
+	req.Category = query.Get("category")
+	req.Member = query.Get("member")
+	req.Search = query.Get("q")
+	req.Cursor = query.Get("cursor")
+	if req.MinAmount, err = parseInt64Query(query.Get("min_amount")); err != nil {
+		return nil, services.ErrInvalidTransactionFilter
+	}
+	if req.MaxAmount, err = parseInt64Query(query.Get("max_amount")); err != nil {
+		return nil, services.ErrInvalidTransactionFilter
+	}
+	if req.From, err = parseTimeQuery(query.Get("from")); err != nil {
+		return nil, services.ErrInvalidTransactionFilter
+	}
+	if req.To, err = parseTimeQuery(query.Get("to")); err != nil {
+		return nil, services.ErrInvalidTransactionFilter
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if req.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, services.ErrInvalidTransactionFilter
+		}
+	}
+	return req, nil
+}
+
+func parseInt64Query(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// parseTimeQuery accepts RFC3339 timestamps and plain dates (2006-01-02).
+func parseTimeQuery(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
@@ -0,0 +1,67 @@
+package transports
+
+import (
+	"context"
+	"my_wallet/api/endpoints"
+	"my_wallet/api/services"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeListTransactionsRequest(t *testing.T) {
+	testScenarios := []struct {
+		name           string
+		url            string
+		expectedOutput interface{}
+		expectedError  error
+	}{
+		{
+			name: "all filters",
+			url:  "/wallets/w1/transactions?type=transfer-in,fee&status=completed&min_amount=100&max_amount=900&from=2024-01-01&to=2024-02-01T00:00:00Z&counterparty=u2&q=rent&cursor=abc&limit=5",
+			expectedOutput: endpoints.ListTransactionsRequest{
+				WalletID:     "w1",
+				Types:        []string{"transfer-in", "fee"},
+				Status:       "completed",
+				MinAmount:    100,
+				MaxAmount:    900,
+				From:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:           time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				Counterparty: "u2",
+				Search:       "rent",
+				Cursor:       "abc",
+				Limit:        5,
+			},
+		},
+		{
+			name:           "invalid amount",
+			url:            "/wallets/w1/transactions?min_amount=ten",
+			expectedOutput: nil,
+			expectedError:  services.ErrInvalidTransactionFilter,
+		},
+		{
+			name:           "invalid date",
+			url:            "/wallets/w1/transactions?from=yesterday",
+			expectedOutput: nil,
+			expectedError:  services.ErrInvalidTransactionFilter,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Prepare
+			req := httptest.NewRequest("GET", tt.url, nil)
+			req.SetPathValue("id", "w1")
+
+			// Act
+			result, err := decodeListTransactionsRequest(context.Background(), req)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, result)
+		})
+	}
+}
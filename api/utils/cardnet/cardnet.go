@@ -0,0 +1,126 @@
+// Package cardnet speaks the protocol of the card network virtual cards are
+// issued on. The network sends the issuer a signed JSON message when a card
+// is used: an authorization asks to approve a purchase, a clearing settles an
+// approved one and a reversal cancels it. The issuer answers every message
+// with an ISO 8583 response code.
+package cardnet
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SignatureHeader carries the signature of the body of a message.
+const SignatureHeader = "X-Card-Signature"
+
+// Message types.
+const (
+	TypeAuthorization = "authorization"
+	TypeClearing      = "clearing"
+	TypeReversal      = "reversal"
+)
+
+// ISO 8583 response codes used by the issuer.
+const (
+	CodeApproved          = "00"
+	CodeDoNotHonor        = "05"
+	CodeInvalidCard       = "14"
+	CodeInsufficientFunds = "51"
+	CodeExpiredCard       = "54"
+	CodeNotPermitted      = "57"
+	CodeLimitExceeded     = "61"
+	CodeRestrictedCard    = "62"
+)
+
+var ErrMalformed = errors.New("cardnet: malformed message")
+
+// Merchant is where a card was used. CategoryCode is the ISO 18245 merchant
+// category code.
+type Merchant struct {
+	Name         string `json:"name"`
+	CategoryCode string `json:"category_code"`
+	Country      string `json:"country"`
+}
+
+// Message is a message of the network. IDs are unique per message, so a
+// message the network sends again can be recognized. Authorizations carry the
+// card number and its expiry as MMYY; clearings and reversals name the
+// authorization they refer to by the ID of its message. Amounts are in the
+// minor unit of Currency.
+type Message struct {
+	ID              string    `json:"id"`
+	Type            string    `json:"type"`
+	AuthorizationID string    `json:"authorization_id,omitempty"`
+	PAN             string    `json:"pan,omitempty"`
+	Expiry          string    `json:"expiry,omitempty"`
+	Amount          int64     `json:"amount,omitempty"`
+	Currency        string    `json:"currency,omitempty"`
+	Merchant        *Merchant `json:"merchant,omitempty"`
+	SentAt          time.Time `json:"sent_at"`
+}
+
+// Response is the answer of the issuer to a message.
+type Response struct {
+	MessageID string `json:"message_id"`
+	Code      string `json:"code"`
+}
+
+// Approved tells whether the issuer approved the message.
+func (r Response) Approved() bool {
+	return r.Code == CodeApproved
+}
+
+// Sign returns the signature of body under secret, as sent in
+// SignatureHeader.
+func Sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify tells whether signature is the one of body under secret.
+func Verify(secret []byte, body []byte, signature string) bool {
+	return len(secret) > 0 && hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Decode parses a message and checks it carries what its type needs.
+func Decode(body []byte) (Message, error) {
+	var m Message
+	if err := json.Unmarshal(body, &m); err != nil {
+		return Message{}, ErrMalformed
+	}
+	if m.ID == "" {
+		return Message{}, ErrMalformed
+	}
+	switch m.Type {
+	case TypeAuthorization:
+		if m.PAN == "" || !validExpiry(m.Expiry) || m.Amount <= 0 || m.Currency == "" || m.Merchant == nil || m.Merchant.Name == "" {
+			return Message{}, ErrMalformed
+		}
+	case TypeClearing:
+		if m.AuthorizationID == "" || m.Amount <= 0 {
+			return Message{}, ErrMalformed
+		}
+	case TypeReversal:
+		if m.AuthorizationID == "" {
+			return Message{}, ErrMalformed
+		}
+	default:
+		return Message{}, ErrMalformed
+	}
+	return m, nil
+}
+
+// Expiry formats the last month a card can be used as MMYY.
+func Expiry(month int, year int) string {
+	return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC).Format("0106")
+}
+
+func validExpiry(expiry string) bool {
+	_, err := time.Parse("0106", expiry)
+	return err == nil
+}
@@ -0,0 +1,115 @@
+package cardnet
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":"m1"}`)
+	signature := Sign([]byte("secret"), body)
+
+	assert.True(t, Verify([]byte("secret"), body, signature))
+	assert.False(t, Verify([]byte("other"), body, signature))
+	assert.False(t, Verify([]byte("secret"), []byte(`{"id":"m2"}`), signature))
+	assert.False(t, Verify(nil, body, Sign(nil, body)))
+}
+
+func TestDecode(t *testing.T) {
+	testScenarios := []struct {
+		testName      string
+		body          string
+		expectedError error
+	}{
+		{
+			testName: "TestDecodeAuthorization",
+			body:     `{"id":"m1","type":"authorization","pan":"4111111111111111","expiry":"1229","amount":5000,"currency":"COP","merchant":{"name":"Cafe","category_code":"5814","country":"CO"}}`,
+		},
+		{
+			testName: "TestDecodeClearing",
+			body:     `{"id":"m2","type":"clearing","authorization_id":"m1","amount":5000}`,
+		},
+		{
+			testName: "TestDecodeReversal",
+			body:     `{"id":"m3","type":"reversal","authorization_id":"m1"}`,
+		},
+		{
+			testName:      "TestDecodeAuthorizationBadExpiry",
+			body:          `{"id":"m1","type":"authorization","pan":"4111111111111111","expiry":"1329","amount":5000,"currency":"COP","merchant":{"name":"Cafe"}}`,
+			expectedError: ErrMalformed,
+		},
+		{
+			testName:      "TestDecodeAuthorizationWithoutMerchant",
+			body:          `{"id":"m1","type":"authorization","pan":"4111111111111111","expiry":"1229","amount":5000,"currency":"COP"}`,
+			expectedError: ErrMalformed,
+		},
+		{
+			testName:      "TestDecodeClearingWithoutAuthorization",
+			body:          `{"id":"m2","type":"clearing","amount":5000}`,
+			expectedError: ErrMalformed,
+		},
+		{
+			testName:      "TestDecodeUnknownType",
+			body:          `{"id":"m4","type":"refund","authorization_id":"m1"}`,
+			expectedError: ErrMalformed,
+		},
+		{
+			testName:      "TestDecodeGarbage",
+			body:          `not json`,
+			expectedError: ErrMalformed,
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			_, err := Decode([]byte(tt.body))
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestSimulator(t *testing.T) {
+	// Prepare
+	var received []Message
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !Verify([]byte("secret"), body, r.Header.Get(SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		m, err := Decode(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, m)
+		json.NewEncoder(w).Encode(Response{MessageID: m.ID, Code: CodeApproved})
+	}))
+	defer issuer.Close()
+	simulator := NewSimulator(issuer.URL, []byte("secret"), nil)
+
+	// Act
+	authorization, response, err := simulator.Authorize(context.Background(), "4111111111111111", Expiry(12, 2029), 5000, "COP", Merchant{Name: "Cafe", CategoryCode: "5814", Country: "CO"})
+	assert.NoError(t, err)
+	assert.True(t, response.Approved())
+	clearing, err := simulator.Clear(context.Background(), authorization, 4500)
+	assert.NoError(t, err)
+	_, err = NewSimulator(issuer.URL, []byte("wrong"), nil).Reverse(context.Background(), authorization)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, clearing.MessageID, received[1].ID)
+	assert.Len(t, received, 2)
+	assert.Equal(t, "1229", received[0].Expiry)
+	assert.Equal(t, authorization.ID, received[1].AuthorizationID)
+	assert.Equal(t, int64(4500), received[1].Amount)
+}
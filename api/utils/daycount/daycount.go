@@ -0,0 +1,65 @@
+// Package daycount computes interest over periods of days with the day count
+// conventions of money markets. Interest is returned in millionths of the
+// unit of the principal, so that accruals too small for a cent add up
+// exactly over a month.
+package daycount
+
+import (
+	"errors"
+	"math/big"
+	"time"
+)
+
+// Day count conventions.
+const (
+	// ACT365 counts the actual days of the period over a year of 365 days.
+	ACT365 = "ACT/365"
+	// Thirty360 counts every month as 30 days over a year of 360 days, the
+	// 30E/360 convention: the 31st is taken as the 30th, so a full month
+	// always counts 30 days.
+	Thirty360 = "30/360"
+)
+
+// Scale is how many units of interest make a unit of the principal.
+const Scale = 1_000_000
+
+var ErrUnknownConvention = errors.New("daycount: unknown convention")
+
+// Valid reports whether convention is a known day count convention.
+func Valid(convention string) bool {
+	return convention == ACT365 || convention == Thirty360
+}
+
+// Days returns the days the period [from, to) counts under the convention
+// and the days of its year. Times are taken as UTC days.
+func Days(convention string, from time.Time, to time.Time) (int64, int64, error) {
+	from, to = from.UTC(), to.UTC()
+	switch convention {
+	case ACT365:
+		start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+		end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+		return int64(end.Sub(start).Hours() / 24), 365, nil
+	case Thirty360:
+		d1, d2 := min(from.Day(), 30), min(to.Day(), 30)
+		days := 360*(to.Year()-from.Year()) + 30*(int(to.Month())-int(from.Month())) + d2 - d1
+		return int64(days), 360, nil
+	}
+	return 0, 0, ErrUnknownConvention
+}
+
+// Accrual returns the simple interest of principal at an annual rate in
+// basis points over [from, to), in millionths of the unit of the principal
+// and rounded down. Principals that are not positive earn nothing.
+func Accrual(convention string, principal int64, rateBps int64, from time.Time, to time.Time) (int64, error) {
+	days, year, err := Days(convention, from, to)
+	if err != nil {
+		return 0, err
+	}
+	if principal <= 0 || rateBps <= 0 || days <= 0 {
+		return 0, nil
+	}
+	interest := new(big.Int).Mul(big.NewInt(principal), big.NewInt(rateBps))
+	interest.Mul(interest, big.NewInt(days*Scale))
+	interest.Quo(interest, big.NewInt(10_000*year))
+	return interest.Int64(), nil
+}
@@ -0,0 +1,76 @@
+package daycount
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDays(t *testing.T) {
+	testScenarios := []struct {
+		testName     string
+		convention   string
+		from         time.Time
+		to           time.Time
+		expectedDays int64
+		expectedYear int64
+		expectedErr  error
+	}{
+		{"TestDaysACT365OneDay", ACT365, date(2026, 1, 31), date(2026, 2, 1), 1, 365, nil},
+		{"TestDaysACT365February", ACT365, date(2024, 2, 1), date(2024, 3, 1), 29, 365, nil},
+		{"TestDays30360The31st", Thirty360, date(2026, 1, 31), date(2026, 2, 1), 1, 360, nil},
+		{"TestDays30360The30th", Thirty360, date(2026, 1, 30), date(2026, 1, 31), 0, 360, nil},
+		{"TestDays30360EndOfFebruary", Thirty360, date(2026, 2, 28), date(2026, 3, 1), 3, 360, nil},
+		{"TestDays30360February", Thirty360, date(2026, 2, 1), date(2026, 3, 1), 30, 360, nil},
+		{"TestDays30360Year", Thirty360, date(2025, 12, 31), date(2026, 12, 31), 360, 360, nil},
+		{"TestDaysUnknownConvention", "ACT/ACT", date(2026, 1, 1), date(2026, 1, 2), 0, 0, ErrUnknownConvention},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			days, year, err := Days(tt.convention, tt.from, tt.to)
+
+			// Assert
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedDays, days)
+			assert.Equal(t, tt.expectedYear, year)
+		})
+	}
+}
+
+func TestAccrual(t *testing.T) {
+	testScenarios := []struct {
+		testName        string
+		convention      string
+		principal       int64
+		rateBps         int64
+		from            time.Time
+		to              time.Time
+		expectedAccrual int64
+	}{
+		// 1,000,000.00 at 3.65% for a day is exactly 100.00.
+		{"TestAccrualACT365", ACT365, 100_000_000, 365, date(2026, 3, 1), date(2026, 3, 2), 10_000 * Scale},
+		// 100.00 at 5% for a day is 0.0136986... cents.
+		{"TestAccrualFraction", ACT365, 10_000, 500, date(2026, 3, 1), date(2026, 3, 2), 1_369_863},
+		// 30/360 pays a month as a twelfth of a year.
+		{"TestAccrual30360Month", Thirty360, 1_200_000, 1_000, date(2026, 2, 1), date(2026, 3, 1), 10_000 * Scale},
+		{"TestAccrualNegativeBalance", ACT365, -10_000, 500, date(2026, 3, 1), date(2026, 3, 2), 0},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			accrual, err := Accrual(tt.convention, tt.principal, tt.rateBps, tt.from, tt.to)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAccrual, accrual)
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// Package emvco encodes and decodes the merchant-presented QR codes of the
+// EMVCo QR Code Specification for Payment Systems. A payload is a list of
+// data objects, each an ID of two digits, a length of two digits and a value,
+// ending with a CRC16 of everything before it.
+package emvco
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// GloballyUniqueID identifies My Wallet in the merchant account information
+// of a payload; payloads of other payment systems are not ours to pay.
+const GloballyUniqueID = "CO.MYWALLET"
+
+// IDs of the data objects used by My Wallet.
+const (
+	idPayloadFormat     = "00"
+	idPointOfInitiation = "01"
+	idMerchantAccount   = "26"
+	idCategoryCode      = "52"
+	idCurrency          = "53"
+	idAmount            = "54"
+	idCountryCode       = "58"
+	idMerchantName      = "59"
+	idMerchantCity      = "60"
+	idAdditionalData    = "62"
+	idCRC               = "63"
+
+	// Inside the merchant account information.
+	idGloballyUniqueID = "00"
+	idMerchantID       = "01"
+
+	// Inside the additional data.
+	idBillNumber     = "01"
+	idReferenceLabel = "05"
+)
+
+const (
+	payloadFormat = "01"
+	staticCode    = "11"
+	dynamicCode   = "12"
+)
+
+// Maximum lengths of the free text data objects.
+const (
+	MaxMerchantName = 25
+	MaxMerchantCity = 15
+	MaxBillNumber   = 25
+)
+
+// Currencies maps the ISO 4217 currencies My Wallet accepts to the numeric
+// codes QR codes carry.
+var Currencies = map[string]string{
+	"COP": "170",
+	"USD": "840",
+	"EUR": "978",
+}
+
+var (
+	ErrMalformed   = errors.New("emvco: malformed payload")
+	ErrChecksum    = errors.New("emvco: checksum mismatch")
+	ErrUnsupported = errors.New("emvco: payload is not a My Wallet QR code")
+)
+
+// Payload is the content of a merchant-presented QR code. Static codes leave
+// the amount to the payer; dynamic codes fix it and name the QR code they
+// were generated as in Reference. Amounts are in the minor unit of Currency.
+type Payload struct {
+	Dynamic      bool
+	MerchantID   string
+	CategoryCode string
+	Currency     string
+	Amount       int64
+	CountryCode  string
+	MerchantName string
+	MerchantCity string
+	BillNumber   string
+	Reference    string
+}
+
+// Encode returns the payload as the text of a QR code, checksum included.
+func Encode(p Payload) (string, error) {
+	currency, ok := Currencies[p.Currency]
+	if !ok || p.Amount < 0 || !ValidCategoryCode(p.CategoryCode) || len(p.CountryCode) != 2 {
+		return "", ErrMalformed
+	}
+	for _, value := range []string{p.MerchantID, p.MerchantName, p.MerchantCity, p.BillNumber, p.Reference} {
+		if utf8.RuneCountInString(value) > 99 {
+			return "", ErrMalformed
+		}
+	}
+	var b strings.Builder
+	writeObject(&b, idPayloadFormat, payloadFormat)
+	if p.Dynamic {
+		writeObject(&b, idPointOfInitiation, dynamicCode)
+	} else {
+		writeObject(&b, idPointOfInitiation, staticCode)
+	}
+	var account strings.Builder
+	writeObject(&account, idGloballyUniqueID, GloballyUniqueID)
+	writeObject(&account, idMerchantID, p.MerchantID)
+	writeObject(&b, idMerchantAccount, account.String())
+	writeObject(&b, idCategoryCode, p.CategoryCode)
+	writeObject(&b, idCurrency, currency)
+	if p.Amount > 0 {
+		writeObject(&b, idAmount, formatAmount(p.Amount))
+	}
+	writeObject(&b, idCountryCode, p.CountryCode)
+	writeObject(&b, idMerchantName, p.MerchantName)
+	writeObject(&b, idMerchantCity, p.MerchantCity)
+	if p.BillNumber != "" || p.Reference != "" {
+		var additional strings.Builder
+		if p.BillNumber != "" {
+			writeObject(&additional, idBillNumber, p.BillNumber)
+		}
+		if p.Reference != "" {
+			writeObject(&additional, idReferenceLabel, p.Reference)
+		}
+		writeObject(&b, idAdditionalData, additional.String())
+	}
+	b.WriteString(idCRC + "04")
+	return b.String() + fmt.Sprintf("%04X", CRC16([]byte(b.String()))), nil
+}
+
+// Decode checks the checksum and the data objects of a QR code and returns
+// its payload. Codes of other payment systems fail with ErrUnsupported.
+func Decode(text string) (Payload, error) {
+	if len(text) < 8 || text[len(text)-8:len(text)-4] != idCRC+"04" {
+		return Payload{}, ErrMalformed
+	}
+	crc, err := strconv.ParseUint(text[len(text)-4:], 16, 16)
+	if err != nil {
+		return Payload{}, ErrMalformed
+	}
+	if uint16(crc) != CRC16([]byte(text[:len(text)-4])) {
+		return Payload{}, ErrChecksum
+	}
+	objects, err := parseObjects(text[:len(text)-8])
+	if err != nil {
+		return Payload{}, err
+	}
+	if objects[idPayloadFormat] != payloadFormat {
+		return Payload{}, ErrMalformed
+	}
+
+	var p Payload
+	switch objects[idPointOfInitiation] {
+	case staticCode:
+	case dynamicCode:
+		p.Dynamic = true
+	default:
+		return Payload{}, ErrMalformed
+	}
+	account, err := parseObjects(objects[idMerchantAccount])
+	if err != nil || account[idGloballyUniqueID] != GloballyUniqueID || account[idMerchantID] == "" {
+		return Payload{}, ErrUnsupported
+	}
+	p.MerchantID = account[idMerchantID]
+	p.CategoryCode = objects[idCategoryCode]
+	for alpha, numeric := range Currencies {
+		if objects[idCurrency] == numeric {
+			p.Currency = alpha
+		}
+	}
+	if !ValidCategoryCode(p.CategoryCode) || p.Currency == "" {
+		return Payload{}, ErrMalformed
+	}
+	if amount, ok := objects[idAmount]; ok {
+		if p.Amount, err = parseAmount(amount); err != nil {
+			return Payload{}, err
+		}
+	}
+	p.CountryCode = objects[idCountryCode]
+	p.MerchantName = objects[idMerchantName]
+	p.MerchantCity = objects[idMerchantCity]
+	if len(p.CountryCode) != 2 || p.MerchantName == "" || p.MerchantCity == "" {
+		return Payload{}, ErrMalformed
+	}
+	if data, ok := objects[idAdditionalData]; ok {
+		additional, err := parseObjects(data)
+		if err != nil {
+			return Payload{}, err
+		}
+		p.BillNumber = additional[idBillNumber]
+		p.Reference = additional[idReferenceLabel]
+	}
+	return p, nil
+}
+
+// CRC16 is the CRC-16/CCITT-FALSE checksum of the specification: polynomial
+// 0x1021 and initial value 0xFFFF.
+func CRC16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// writeObject appends a data object. Lengths count characters.
+func writeObject(b *strings.Builder, id string, value string) {
+	fmt.Fprintf(b, "%s%02d%s", id, utf8.RuneCountInString(value), value)
+}
+
+// parseObjects splits a list of data objects by ID. IDs must not repeat.
+func parseObjects(text string) (map[string]string, error) {
+	objects := map[string]string{}
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if i+4 > len(runes) {
+			return nil, ErrMalformed
+		}
+		id := string(runes[i : i+2])
+		length, err := strconv.Atoi(string(runes[i+2 : i+4]))
+		if err != nil || length < 1 || i+4+length > len(runes) {
+			return nil, ErrMalformed
+		}
+		if _, ok := objects[id]; ok {
+			return nil, ErrMalformed
+		}
+		objects[id] = string(runes[i+4 : i+4+length])
+		i += 4 + length
+	}
+	return objects, nil
+}
+
+// ValidCategoryCode reports whether code looks like a merchant category
+// code: four digits.
+func ValidCategoryCode(code string) bool {
+	if len(code) != 4 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatAmount writes minor units with two decimals, as in "1500.00".
+func formatAmount(amount int64) string {
+	return fmt.Sprintf("%d.%02d", amount/100, amount%100)
+}
+
+// parseAmount reads an amount of up to two decimals into minor units.
+func parseAmount(text string) (int64, error) {
+	whole, fraction, _ := strings.Cut(text, ".")
+	if whole == "" || len(fraction) > 2 || len(text) > 13 {
+		return 0, ErrMalformed
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil || amount <= 0 || strings.ContainsAny(whole+fraction, "+-") {
+		return 0, ErrMalformed
+	}
+	return amount, nil
+}
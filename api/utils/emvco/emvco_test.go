@@ -0,0 +1,71 @@
+package emvco
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, uint16(0x29B1), CRC16([]byte("123456789")))
+}
+
+func TestEncodeDecode(t *testing.T) {
+	testScenarios := []struct {
+		testName string
+		payload  Payload
+	}{
+		{
+			testName: "TestStaticPayload",
+			payload:  Payload{MerchantID: "m1", CategoryCode: "5812", Currency: "COP", CountryCode: "CO", MerchantName: "Panaderia La Esquina", MerchantCity: "Bogota"},
+		},
+		{
+			testName: "TestDynamicPayload",
+			payload:  Payload{Dynamic: true, MerchantID: "m1", CategoryCode: "5812", Currency: "COP", Amount: 1550050, CountryCode: "CO", MerchantName: "Panadería", MerchantCity: "Medellín", BillNumber: "INV-7", Reference: "q1"},
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			text, err := Encode(tt.payload)
+			assert.NoError(t, err)
+			result, err := Decode(text)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.payload, result)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	valid, _ := Encode(Payload{MerchantID: "m1", CategoryCode: "5812", Currency: "USD", CountryCode: "CO", MerchantName: "Cafe", MerchantCity: "Cali"})
+	other := "00020101021126300014BR.GOV.BCB.PIX0108m1234567520400005303986540510.005802BR5904Loja6009Sao Paulo6304"
+
+	testScenarios := []struct {
+		testName      string
+		text          string
+		expectedError error
+	}{
+		{testName: "TestDecodeTampered", text: valid[:len(valid)-10] + "X" + valid[len(valid)-9:], expectedError: ErrChecksum},
+		{testName: "TestDecodeWithoutChecksum", text: valid[:len(valid)-8], expectedError: ErrMalformed},
+		{testName: "TestDecodeOtherScheme", text: withChecksum(other), expectedError: ErrUnsupported},
+		{testName: "TestDecodeGarbage", text: withChecksum("hello6304"), expectedError: ErrMalformed},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			_, err := Decode(tt.text)
+
+			// Assert
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func withChecksum(text string) string {
+	return fmt.Sprintf("%s%04X", text, CRC16([]byte(text)))
+}
@@ -15,6 +15,8 @@ import (
 // Documentar valor por defecto
 const defaultExpirationTimeToken = 30
 
+var ErrInvalidToken = errors.New("Invalid token")
+
 func GenerateToken(email string, logger logrus.FieldLogger) (string, string, error) {
 	dir, err := os.Getwd()
 	//rootDir := filepath.Join(dir, "../..")
@@ -63,22 +65,18 @@ func ValidateToken(tokenStr string) (*jwt.StandardClaims, error) {
 	viper.SetConfigFile(envPath)
 	key := viper.GetString("SECRET_KEY")
 	secretKey := []byte(key)
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+	token, err := jwt.ParseWithClaims(tokenStr, &jwt.StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
 		return secretKey, nil
 	})
-	if err != nil || !token.Valid {
+	if err != nil {
 		return nil, err
 	}
 	claims, ok := token.Claims.(*jwt.StandardClaims)
-	if !ok {
-		return nil, err
-	}
-
-	if claims.ExpiresAt < time.Now().Unix() {
-		return nil, err
+	if !token.Valid || !ok || claims.Subject == "" || claims.ExpiresAt < time.Now().Unix() {
+		return nil, ErrInvalidToken
 	}
 	return claims, nil
 }
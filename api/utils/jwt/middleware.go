@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"strings"
+
+	"github.com/golang-jwt/jwt"
 )
 
 func JWTMiddleware(next http.Handler) http.Handler {
@@ -29,3 +31,13 @@ func JWTMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// EmailFromContext returns the email of the user authenticated by
+// JWTMiddleware, or an empty string when the request was not authenticated.
+func EmailFromContext(ctx context.Context) string {
+	claims, ok := ctx.Value("email").(*jwt.StandardClaims)
+	if !ok || claims == nil {
+		return ""
+	}
+	return claims.Subject
+}
@@ -0,0 +1,98 @@
+// Package pan generates and checks the primary account numbers of payment
+// cards. A PAN is the issuer's BIN, an account number and a Luhn check digit.
+// Card numbers are never stored: they are replaced by a token, an HMAC of the
+// number under a secret key, which the numbers the card network sends can be
+// matched against.
+package pan
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// Length is the length of the PANs My Wallet issues.
+const Length = 16
+
+// Lengths a PAN can have.
+const (
+	minLength = 12
+	maxLength = 19
+)
+
+var ErrInvalidBIN = errors.New("pan: invalid BIN")
+
+// Valid tells whether pan is made of digits, has a valid length and its last
+// digit is its Luhn check digit.
+func Valid(pan string) bool {
+	if len(pan) < minLength || len(pan) > maxLength || !digits(pan) {
+		return false
+	}
+	return CheckDigit(pan[:len(pan)-1]) == pan[len(pan)-1]
+}
+
+// CheckDigit returns the Luhn check digit of a PAN without it. It expects
+// payload to be made of digits.
+func CheckDigit(payload string) byte {
+	sum := 0
+	double := true
+	for i := len(payload) - 1; i >= 0; i-- {
+		d := int(payload[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// Generate returns a random PAN of Length digits starting with bin, reading
+// the account number from r.
+func Generate(bin string, r io.Reader) (string, error) {
+	if len(bin) < 6 || len(bin) >= Length-1 || !digits(bin) {
+		return "", ErrInvalidBIN
+	}
+	payload := []byte(bin)
+	b := make([]byte, 1)
+	for len(payload) < Length-1 {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return "", err
+		}
+		// Bytes of 250 and over are skipped so every digit is as likely.
+		if b[0] < 250 {
+			payload = append(payload, '0'+b[0]%10)
+		}
+	}
+	return string(payload) + string(CheckDigit(string(payload))), nil
+}
+
+// Last4 returns the last four digits of a PAN, shown to tell cards apart.
+func Last4(pan string) string {
+	if len(pan) < 4 {
+		return pan
+	}
+	return pan[len(pan)-4:]
+}
+
+// Token returns the token that replaces pan when it is stored. The same PAN
+// and key always give the same token.
+func Token(key []byte, pan string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(pan))
+	return "tok_" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
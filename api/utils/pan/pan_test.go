@@ -0,0 +1,78 @@
+package pan
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	testScenarios := []struct {
+		testName string
+		pan      string
+		expected bool
+	}{
+		{testName: "TestValidVisa", pan: "4111111111111111", expected: true},
+		{testName: "TestValidMastercard", pan: "5555555555554444", expected: true},
+		{testName: "TestValidAmex", pan: "378282246310005", expected: true},
+		{testName: "TestWrongCheckDigit", pan: "4111111111111112", expected: false},
+		{testName: "TestTooShort", pan: "41111111119", expected: false},
+		{testName: "TestNotDigits", pan: "4111 1111 1111 1111", expected: false},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			result := Valid(tt.pan)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	// Act
+	result, err := Generate("489537", rand.Reader)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result, Length)
+	assert.True(t, strings.HasPrefix(result, "489537"))
+	assert.True(t, Valid(result))
+}
+
+func TestGenerateSkipsBiasedBytes(t *testing.T) {
+	// Prepare
+	random := bytes.NewReader(append([]byte{255, 251}, bytes.Repeat([]byte{13}, 9)...))
+
+	// Act
+	result, err := Generate("489537", random)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "489537333333333", result[:Length-1])
+	assert.True(t, Valid(result))
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate("48A537", rand.Reader)
+	assert.Equal(t, ErrInvalidBIN, err)
+	_, err = Generate("48953", rand.Reader)
+	assert.Equal(t, ErrInvalidBIN, err)
+	_, err = Generate("489537", bytes.NewReader([]byte{1, 2}))
+	assert.Error(t, err)
+}
+
+func TestToken(t *testing.T) {
+	token := Token([]byte("key"), "4111111111111111")
+
+	assert.True(t, strings.HasPrefix(token, "tok_"))
+	assert.NotContains(t, token, "4111111111111111")
+	assert.Equal(t, token, Token([]byte("key"), "4111111111111111"))
+	assert.NotEqual(t, token, Token([]byte("other"), "4111111111111111"))
+	assert.Equal(t, "1111", Last4("4111111111111111"))
+}
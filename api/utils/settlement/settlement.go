@@ -0,0 +1,138 @@
+// Package settlement reads the settlement files payment providers send at the
+// end of the day: a CSV with a header row and one settled movement per row.
+package settlement
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Columns of a settlement file. The header row names them in any order and
+// in any case; other columns are ignored.
+const (
+	ColumnReference = "reference"
+	ColumnType      = "type"
+	ColumnAmount    = "amount"
+	ColumnCurrency  = "currency"
+	ColumnSettledAt = "settled_at"
+)
+
+var required = []string{ColumnReference, ColumnType, ColumnAmount, ColumnCurrency, ColumnSettledAt}
+
+// ErrMalformed is returned for files that cannot be read; the error names the
+// line at fault.
+var ErrMalformed = errors.New("settlement: malformed file")
+
+// Record is a movement the provider settled. Amount is in the minor unit of
+// the currency.
+type Record struct {
+	Line      int
+	Reference string
+	Type      string
+	Amount    int64
+	Currency  string
+	SettledAt time.Time
+}
+
+// Parse reads a settlement file. Amounts are positive decimal numbers in the
+// major unit with up to two decimals, as in "1500.50"; dates are either
+// 2006-01-02, taken as UTC, or RFC 3339. Types and currencies are returned in
+// lower and upper case; the reference may be empty.
+func Parse(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: line 1: missing header", ErrMalformed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: line 1: missing column %s", ErrMalformed, name)
+		}
+	}
+
+	records := []Record{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		line, _ := reader.FieldPos(0)
+		record, err := parseRecord(row, columns)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrMalformed, line, err)
+		}
+		record.Line = line
+		records = append(records, record)
+	}
+}
+
+func parseRecord(row []string, columns map[string]int) (Record, error) {
+	field := func(name string) string {
+		return strings.TrimSpace(row[columns[name]])
+	}
+	record := Record{
+		Reference: field(ColumnReference),
+		Type:      strings.ToLower(field(ColumnType)),
+		Currency:  strings.ToUpper(field(ColumnCurrency)),
+	}
+	if record.Type == "" {
+		return Record{}, errors.New("missing type")
+	}
+	if len(record.Currency) != 3 {
+		return Record{}, errors.New("invalid currency")
+	}
+	amount, err := parseAmount(field(ColumnAmount))
+	if err != nil {
+		return Record{}, err
+	}
+	record.Amount = amount
+	settledAt, err := parseDate(field(ColumnSettledAt))
+	if err != nil {
+		return Record{}, err
+	}
+	record.SettledAt = settledAt
+	return record, nil
+}
+
+// parseAmount reads an amount of up to two decimals into minor units.
+func parseAmount(text string) (int64, error) {
+	whole, fraction, _ := strings.Cut(text, ".")
+	if whole == "" || len(fraction) > 2 || len(text) > 16 || strings.ContainsAny(text, "+-") {
+		return 0, errors.New("invalid amount")
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil || amount <= 0 {
+		return 0, errors.New("invalid amount")
+	}
+	return amount, nil
+}
+
+func parseDate(text string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, text); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return time.Time{}, errors.New("invalid settled_at")
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,72 @@
+package settlement
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	// Prepare
+	file := "\ufeffSettled_At,Reference,Amount,Currency,Type,Fee\n" +
+		"2026-10-18,65f1c0a2b3d4e5f6a7b8c9d0,1500.5,cop,Withdrawal,3.00\n" +
+		"2026-10-18T23:10:00-05:00,,20,USD,deposit,0\n"
+
+	// Act
+	records, err := Parse(strings.NewReader(file))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{
+		{Line: 2, Reference: "65f1c0a2b3d4e5f6a7b8c9d0", Type: "withdrawal", Amount: 150050, Currency: "COP", SettledAt: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{Line: 3, Type: "deposit", Amount: 2000, Currency: "USD", SettledAt: time.Date(2026, 10, 19, 4, 10, 0, 0, time.UTC)},
+	}, records)
+}
+
+func TestParseErrors(t *testing.T) {
+	testScenarios := []struct {
+		testName      string
+		file          string
+		expectedError string
+	}{
+		{
+			testName:      "TestParseEmptyFile",
+			file:          "",
+			expectedError: "settlement: malformed file: line 1: missing header",
+		},
+		{
+			testName:      "TestParseMissingColumn",
+			file:          "reference,type,amount,currency\n",
+			expectedError: "settlement: malformed file: line 1: missing column settled_at",
+		},
+		{
+			testName:      "TestParseNegativeAmount",
+			file:          "reference,type,amount,currency,settled_at\nr1,deposit,-10.00,COP,2026-10-18\n",
+			expectedError: "settlement: malformed file: line 2: invalid amount",
+		},
+		{
+			testName:      "TestParseTooManyDecimals",
+			file:          "reference,type,amount,currency,settled_at\nr1,deposit,10.005,COP,2026-10-18\n",
+			expectedError: "settlement: malformed file: line 2: invalid amount",
+		},
+		{
+			testName:      "TestParseBadDate",
+			file:          "reference,type,amount,currency,settled_at\nr1,deposit,10,COP,18/10/2026\n",
+			expectedError: "settlement: malformed file: line 2: invalid settled_at",
+		},
+	}
+
+	for _, tt := range testScenarios {
+		t.Run(tt.testName, func(t *testing.T) {
+			// Act
+			_, err := Parse(strings.NewReader(tt.file))
+
+			// Assert
+			assert.True(t, errors.Is(err, ErrMalformed))
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}